@@ -69,6 +69,11 @@ func (app Application) ConfigureSMTP(logger lager.Logger) {
 	mailClient := app.mother.MailClient()
 	err := mailClient.Connect(logger)
 	if err != nil {
+		if len(app.env.SMTPFailoverRelays) > 0 {
+			logger.Error("smtp-primary-relay-unavailable", err)
+			return
+		}
+
 		logger.Fatal("smtp-connect-errored", err)
 	}
 
@@ -81,12 +86,15 @@ func (app Application) ConfigureSMTP(logger lager.Logger) {
 
 	mailClient.Quit()
 
-	if !startTLSSupported && app.env.SMTPTLS {
-		logger.Fatal("smtp-config-mismatch", errors.New(`SMTP TLS configuration mismatch: Configured to use TLS over SMTP, but the mail server does not support the "STARTTLS" extension.`))
-	}
-
-	if startTLSSupported && !app.env.SMTPTLS {
-		logger.Fatal("smtp-config-mismatch", errors.New(`SMTP TLS configuration mismatch: Not configured to use TLS over SMTP, but the mail server does support the "STARTTLS" extension.`))
+	switch app.env.SMTPTLSMode {
+	case SMTPTLSModeStartTLS:
+		if !startTLSSupported {
+			logger.Fatal("smtp-config-mismatch", errors.New(`SMTP TLS configuration mismatch: Configured to use STARTTLS over SMTP, but the mail server does not support the "STARTTLS" extension.`))
+		}
+	case SMTPTLSModeNone:
+		if startTLSSupported {
+			logger.Fatal("smtp-config-mismatch", errors.New(`SMTP TLS configuration mismatch: Not configured to use TLS over SMTP, but the mail server does support the "STARTTLS" extension.`))
+		}
 	}
 }
 
@@ -131,6 +139,11 @@ func (app Application) StartWorkers(validator *uaa.TokenValidator) {
 		Domain:               app.env.Domain,
 		QueueWaitMaxDuration: app.env.GobbleWaitMaxDuration,
 		CCHost:               app.env.CCHost,
+		PublicURL:            app.env.PublicURL,
+		TestMode:             app.env.TestMode,
+		SMTPLoggingEnabled:   app.env.SMTPLoggingEnabled,
+		HTMLPolicy:           app.env.HTMLPolicy,
+		HTMLClientPolicies:   app.env.HTMLClientPolicies,
 	})
 }
 
@@ -138,10 +151,11 @@ func (app Application) StartMessageGC() {
 	messageLifetime := 24 * time.Hour
 	db := app.mother.Database()
 	messagesRepo := app.mother.MessagesRepo()
+	attachmentsRepo := app.mother.AttachmentsRepo()
 	pollingInterval := 1 * time.Hour
 
 	logger := log.New(os.Stdout, "", 0)
-	messageGC := postal.NewMessageGC(messageLifetime, db, messagesRepo, pollingInterval, logger)
+	messageGC := postal.NewMessageGC(messageLifetime, db, messagesRepo, attachmentsRepo, pollingInterval, logger)
 	messageGC.Run()
 }
 
@@ -161,6 +175,13 @@ func (app Application) StartServer(logger lager.Logger, validator *uaa.TokenVali
 		UAAClientSecret:   app.env.UAAClientSecret,
 		DefaultUAAScopes:  app.env.DefaultUAAScopes,
 		CCHost:            app.env.CCHost,
+		EncryptionKey:     app.env.EncryptionKey,
+		SenderDomains:     app.env.SenderDomains,
+		Sender:            app.env.Sender,
+		Domain:            app.env.Domain,
+
+		HTMLPolicy:         app.env.HTMLPolicy,
+		HTMLClientPolicies: app.env.HTMLClientPolicies,
 	})
 }
 
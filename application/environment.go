@@ -1,12 +1,14 @@
 package application
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
 	"path"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/sanitize"
 	"github.com/ryanmoran/viron"
 )
 
@@ -14,9 +16,18 @@ const (
 	SMTPAuthNone    = "none"
 	SMTPAuthPlain   = "plain"
 	SMTPAuthCRAMMD5 = "cram-md5"
+	SMTPAuthLogin   = "login"
+	SMTPAuthXOAUTH2 = "xoauth2"
+
+	SMTPTLSModeNone     = "none"
+	SMTPTLSModeStartTLS = "starttls"
+	SMTPTLSModeImplicit = "implicit"
 )
 
-var SMTPAuthMechanisms = []string{SMTPAuthNone, SMTPAuthPlain, SMTPAuthCRAMMD5}
+var (
+	SMTPAuthMechanisms = []string{SMTPAuthNone, SMTPAuthPlain, SMTPAuthCRAMMD5, SMTPAuthLogin, SMTPAuthXOAUTH2}
+	SMTPTLSModes       = []string{SMTPTLSModeNone, SMTPTLSModeStartTLS, SMTPTLSModeImplicit}
+)
 
 type Environment struct {
 	CCHost                string `env:"CC_HOST"                  env-required:"true"`
@@ -28,17 +39,26 @@ type Environment struct {
 	Domain                string `env:"DOMAIN"                   env-required:"true"`
 	EncryptionKey         []byte `env:"ENCRYPTION_KEY"           env-required:"true"`
 	GobbleWaitMaxDuration int    `env:"GOBBLE_WAIT_MAX_DURATION" env-default:"5000"`
+	HTMLClientPoliciesRaw string `env:"HTML_CLIENT_POLICIES"`
+	HTMLPolicyRaw         string `env:"HTML_POLICY"`
 	Port                  int    `env:"PORT"                     env-default:"3000"`
+	PublicURL             string `env:"PUBLIC_URL"`
 	RootPath              string `env:"ROOT_PATH"`
 	SMTPAuthMechanism     string `env:"SMTP_AUTH_MECHANISM"      env-required:"true"`
 	SMTPCRAMMD5Secret     string `env:"SMTP_CRAMMD5_SECRET"`
+	SMTPFailoverCooldown  int    `env:"SMTP_FAILOVER_COOLDOWN"   env-default:"60000"`
+	SMTPFailoverRelaysRaw string `env:"SMTP_FAILOVER_RELAYS"`
 	SMTPHost              string `env:"SMTP_HOST"                env-required:"true"`
 	SMTPLoggingEnabled    bool   `env:"SMTP_LOGGING_ENABLED"     env-default:"false"`
 	SMTPPass              string `env:"SMTP_PASS"`
 	SMTPPort              string `env:"SMTP_PORT"                env-required:"true"`
 	SMTPTLS               bool   `env:"SMTP_TLS"                 env-default:"true"`
+	SMTPTLSMode           string `env:"SMTP_TLS_MODE"`
 	SMTPUser              string `env:"SMTP_USER"`
+	SMTPXOAUTH2Token      string `env:"SMTP_XOAUTH2_TOKEN"`
+	SMTPXOAUTH2TokenFile  string `env:"SMTP_XOAUTH2_TOKEN_FILE"`
 	Sender                string `env:"SENDER"                   env-required:"true"`
+	SenderDomainsList     string `env:"SENDER_DOMAINS"`
 	TestMode              bool   `env:"TEST_MODE"                env-default:"false"`
 	UAAClientID           string `env:"UAA_CLIENT_ID"            env-required:"true"`
 	UAAClientSecret       string `env:"UAA_CLIENT_SECRET"        env-required:"true"`
@@ -53,6 +73,22 @@ type Environment struct {
 	ModelMigrationsPath  string
 	GobbleMigrationsPath string
 	DefaultUAAScopes     []string
+	SMTPFailoverRelays   []SMTPRelay
+	SenderDomains        []string
+	HTMLPolicy           sanitize.Policy
+	HTMLClientPolicies   map[string]sanitize.Policy
+}
+
+type SMTPRelay struct {
+	Host             string `json:"host"`
+	Port             string `json:"port"`
+	User             string `json:"user"`
+	Pass             string `json:"pass"`
+	CRAMMD5Secret    string `json:"crammd5_secret"`
+	XOAUTH2Token     string `json:"xoauth2_token"`
+	XOAUTH2TokenFile string `json:"xoauth2_token_file"`
+	AuthMechanism    string `json:"auth_mechanism"`
+	TLSMode          string `json:"tls_mode"`
 }
 
 type EnvironmentError struct {
@@ -77,13 +113,31 @@ func NewEnvironment() (Environment, error) {
 
 	env.expandRoot()
 
+	env.inferSMTPTLSMode()
+
+	err = env.validateSMTPTLSMode()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
 	err = env.validateSMTPAuthMechanism()
 	if err != nil {
 		return env, EnvironmentError{err}
 	}
 
+	err = env.parseSMTPFailoverRelays()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
+	err = env.parseHTMLPolicies()
+	if err != nil {
+		return env, EnvironmentError{err}
+	}
+
 	env.inferMigrationsDirs()
 	env.parseDefaultUAAScopes()
+	env.parseSenderDomains()
 
 	return env, nil
 }
@@ -92,6 +146,15 @@ func (env *Environment) parseDefaultUAAScopes() {
 	env.DefaultUAAScopes = strings.Split(env.DefaultUAAScopesList, ",")
 }
 
+func (env *Environment) parseSenderDomains() {
+	for _, domain := range strings.Split(env.SenderDomainsList, ",") {
+		domain = strings.TrimSpace(domain)
+		if domain != "" {
+			env.SenderDomains = append(env.SenderDomains, domain)
+		}
+	}
+}
+
 func (env *Environment) expandRoot() {
 	env.RootPath = os.ExpandEnv(env.RootPath)
 }
@@ -118,12 +181,127 @@ func (env *Environment) parseDatabaseURL() error {
 	return nil
 }
 
+func (env *Environment) inferSMTPTLSMode() {
+	if env.SMTPTLSMode != "" {
+		return
+	}
+
+	if env.SMTPTLS {
+		env.SMTPTLSMode = SMTPTLSModeStartTLS
+	} else {
+		env.SMTPTLSMode = SMTPTLSModeNone
+	}
+}
+
+func (env *Environment) validateSMTPTLSMode() error {
+	if !contains(SMTPTLSModes, env.SMTPTLSMode) {
+		return fmt.Errorf("Could not parse SMTP_TLS_MODE %q, it is not one of the allowed values: %+v", env.SMTPTLSMode, SMTPTLSModes)
+	}
+
+	return nil
+}
+
 func (env *Environment) validateSMTPAuthMechanism() error {
-	for _, mechanism := range SMTPAuthMechanisms {
-		if mechanism == env.SMTPAuthMechanism {
-			return nil
+	if !contains(SMTPAuthMechanisms, env.SMTPAuthMechanism) {
+		return fmt.Errorf("Could not parse SMTP_AUTH_MECHANISM %q, it is not one of the allowed values: %+v", env.SMTPAuthMechanism, SMTPAuthMechanisms)
+	}
+
+	switch env.SMTPAuthMechanism {
+	case SMTPAuthLogin, SMTPAuthXOAUTH2:
+		if env.SMTPTLSMode == SMTPTLSModeNone {
+			return fmt.Errorf("SMTP_AUTH_MECHANISM %q requires SMTP_TLS_MODE to be %q or %q", env.SMTPAuthMechanism, SMTPTLSModeStartTLS, SMTPTLSModeImplicit)
+		}
+
+		if env.SMTPUser == "" {
+			return fmt.Errorf("SMTP_AUTH_MECHANISM %q requires SMTP_USER to be set", env.SMTPAuthMechanism)
+		}
+	}
+
+	if env.SMTPAuthMechanism == SMTPAuthXOAUTH2 && env.SMTPXOAUTH2Token == "" && env.SMTPXOAUTH2TokenFile == "" {
+		return fmt.Errorf("SMTP_AUTH_MECHANISM %q requires SMTP_XOAUTH2_TOKEN or SMTP_XOAUTH2_TOKEN_FILE to be set", env.SMTPAuthMechanism)
+	}
+
+	return nil
+}
+
+func (env *Environment) parseSMTPFailoverRelays() error {
+	if env.SMTPFailoverRelaysRaw == "" {
+		return nil
+	}
+
+	err := json.Unmarshal([]byte(env.SMTPFailoverRelaysRaw), &env.SMTPFailoverRelays)
+	if err != nil {
+		return fmt.Errorf("Could not parse SMTP_FAILOVER_RELAYS %q, it is not a JSON array of relays", env.SMTPFailoverRelaysRaw)
+	}
+
+	for i := range env.SMTPFailoverRelays {
+		relay := &env.SMTPFailoverRelays[i]
+
+		if relay.AuthMechanism == "" {
+			relay.AuthMechanism = SMTPAuthNone
+		}
+
+		if relay.TLSMode == "" {
+			relay.TLSMode = SMTPTLSModeStartTLS
+		}
+
+		if relay.Host == "" || relay.Port == "" {
+			return fmt.Errorf("SMTP_FAILOVER_RELAYS[%d] requires a host and port", i)
+		}
+
+		if !contains(SMTPTLSModes, relay.TLSMode) {
+			return fmt.Errorf("Could not parse SMTP_FAILOVER_RELAYS[%d] tls_mode %q, it is not one of the allowed values: %+v", i, relay.TLSMode, SMTPTLSModes)
+		}
+
+		if !contains(SMTPAuthMechanisms, relay.AuthMechanism) {
+			return fmt.Errorf("Could not parse SMTP_FAILOVER_RELAYS[%d] auth_mechanism %q, it is not one of the allowed values: %+v", i, relay.AuthMechanism, SMTPAuthMechanisms)
+		}
+
+		switch relay.AuthMechanism {
+		case SMTPAuthLogin, SMTPAuthXOAUTH2:
+			if relay.TLSMode == SMTPTLSModeNone {
+				return fmt.Errorf("SMTP_FAILOVER_RELAYS[%d] auth_mechanism %q requires tls_mode to be %q or %q", i, relay.AuthMechanism, SMTPTLSModeStartTLS, SMTPTLSModeImplicit)
+			}
+
+			if relay.User == "" {
+				return fmt.Errorf("SMTP_FAILOVER_RELAYS[%d] auth_mechanism %q requires user to be set", i, relay.AuthMechanism)
+			}
+		}
+
+		if relay.AuthMechanism == SMTPAuthXOAUTH2 && relay.XOAUTH2Token == "" && relay.XOAUTH2TokenFile == "" {
+			return fmt.Errorf("SMTP_FAILOVER_RELAYS[%d] auth_mechanism %q requires xoauth2_token or xoauth2_token_file to be set", i, relay.AuthMechanism)
+		}
+	}
+
+	return nil
+}
+
+func (env *Environment) parseHTMLPolicies() error {
+	env.HTMLPolicy = sanitize.StrictPolicy
+	if env.HTMLPolicyRaw != "" {
+		env.HTMLPolicy = sanitize.Policy{}
+		err := json.Unmarshal([]byte(env.HTMLPolicyRaw), &env.HTMLPolicy)
+		if err != nil {
+			return fmt.Errorf("Could not parse HTML_POLICY %q, it is not a JSON object of elements, attributes and url_schemes", env.HTMLPolicyRaw)
+		}
+	}
+
+	if env.HTMLClientPoliciesRaw != "" {
+		err := json.Unmarshal([]byte(env.HTMLClientPoliciesRaw), &env.HTMLClientPolicies)
+		if err != nil {
+			return fmt.Errorf("Could not parse HTML_CLIENT_POLICIES %q, it is not a JSON object of client IDs to policies", env.HTMLClientPoliciesRaw)
+		}
+	}
+
+	return nil
+}
+
+func contains(elements []string, element string) bool {
+	for _, elem := range elements {
+		if elem == element {
+			return true
 		}
 	}
 
-	return fmt.Errorf("Could not parse SMTP_AUTH_MECHANISM %q, it is not one of the allowed values: %+v", env.SMTPAuthMechanism, SMTPAuthMechanisms)
+	return false
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/cloudfoundry-incubator/notifications/application"
+	"github.com/cloudfoundry-incubator/notifications/sanitize"
 	"github.com/ryanmoran/viron"
 
 	. "github.com/onsi/ginkgo"
@@ -23,16 +24,25 @@ var _ = Describe("Environment", func() {
 		"DOMAIN",
 		"ENCRYPTION_KEY",
 		"GOBBLE_WAIT_MAX_DURATION",
+		"HTML_CLIENT_POLICIES",
+		"HTML_POLICY",
 		"PORT",
 		"ROOT_PATH",
 		"SENDER",
+		"SENDER_DOMAINS",
 		"SMTP_AUTH_MECHANISM",
 		"SMTP_CRAMMD5_SECRET",
+		"SMTP_FAILOVER_COOLDOWN",
+		"SMTP_FAILOVER_RELAYS",
 		"SMTP_HOST",
 		"SMTP_LOGGING_ENABLED",
 		"SMTP_PASS",
 		"SMTP_PORT",
+		"SMTP_TLS",
+		"SMTP_TLS_MODE",
 		"SMTP_USER",
+		"SMTP_XOAUTH2_TOKEN",
+		"SMTP_XOAUTH2_TOKEN_FILE",
 		"TEST_MODE",
 		"UAA_CLIENT_ID",
 		"UAA_CLIENT_SECRET",
@@ -220,7 +230,7 @@ var _ = Describe("Environment", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
-		It("it errors if SMTP_AUTH_MECHANISM is not one of the three supported types", func() {
+		It("it errors if SMTP_AUTH_MECHANISM is not one of the supported types", func() {
 			os.Setenv("SMTP_AUTH_MECHANISM", "cram-md5")
 			_, err := application.NewEnvironment()
 			Expect(err).NotTo(HaveOccurred())
@@ -235,7 +245,150 @@ var _ = Describe("Environment", func() {
 
 			os.Setenv("SMTP_AUTH_MECHANISM", "banana")
 			_, err = application.NewEnvironment()
-			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse SMTP_AUTH_MECHANISM \"banana\", it is not one of the allowed values: [none plain cram-md5]")}))
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse SMTP_AUTH_MECHANISM \"banana\", it is not one of the allowed values: [none plain cram-md5 login xoauth2]")}))
+		})
+
+		It("infers SMTP_TLS_MODE from SMTP_TLS when it is not set", func() {
+			os.Setenv("SMTP_TLS_MODE", "")
+			os.Setenv("SMTP_TLS", "true")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPTLSMode).To(Equal("starttls"))
+
+			os.Setenv("SMTP_TLS", "false")
+
+			env, err = application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPTLSMode).To(Equal("none"))
+		})
+
+		It("errors if SMTP_TLS_MODE is not one of the supported modes", func() {
+			os.Setenv("SMTP_TLS_MODE", "implicit")
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPTLSMode).To(Equal("implicit"))
+
+			os.Setenv("SMTP_TLS_MODE", "banana")
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New("Could not parse SMTP_TLS_MODE \"banana\", it is not one of the allowed values: [none starttls implicit]")}))
+		})
+
+		It("validates the configuration required by the login and xoauth2 mechanisms", func() {
+			os.Setenv("SMTP_USER", "my-smtp-user")
+			os.Setenv("SMTP_TLS_MODE", "implicit")
+			os.Setenv("SMTP_AUTH_MECHANISM", "login")
+
+			_, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+
+			os.Setenv("SMTP_TLS_MODE", "none")
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New(`SMTP_AUTH_MECHANISM "login" requires SMTP_TLS_MODE to be "starttls" or "implicit"`)}))
+
+			os.Setenv("SMTP_TLS_MODE", "starttls")
+			os.Setenv("SMTP_USER", "")
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New(`SMTP_AUTH_MECHANISM "login" requires SMTP_USER to be set`)}))
+
+			os.Setenv("SMTP_USER", "my-smtp-user")
+			os.Setenv("SMTP_AUTH_MECHANISM", "xoauth2")
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New(`SMTP_AUTH_MECHANISM "xoauth2" requires SMTP_XOAUTH2_TOKEN or SMTP_XOAUTH2_TOKEN_FILE to be set`)}))
+
+			os.Setenv("SMTP_XOAUTH2_TOKEN", "some-oauth-token")
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPXOAUTH2Token).To(Equal("some-oauth-token"))
+
+			os.Setenv("SMTP_XOAUTH2_TOKEN", "")
+			os.Setenv("SMTP_XOAUTH2_TOKEN_FILE", "/var/vcap/data/notifications/xoauth2-token")
+			env, err = application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPXOAUTH2TokenFile).To(Equal("/var/vcap/data/notifications/xoauth2-token"))
+		})
+
+		It("parses the failover relays", func() {
+			os.Setenv("SMTP_FAILOVER_RELAYS", `[
+				{"host": "backup.example.com", "port": "465", "user": "backup-user", "pass": "backup-pass", "auth_mechanism": "login", "tls_mode": "implicit"},
+				{"host": "last-resort.example.com", "port": "25"}
+			]`)
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SMTPFailoverCooldown).To(Equal(60000))
+			Expect(env.SMTPFailoverRelays).To(Equal([]application.SMTPRelay{
+				{
+					Host:          "backup.example.com",
+					Port:          "465",
+					User:          "backup-user",
+					Pass:          "backup-pass",
+					AuthMechanism: "login",
+					TLSMode:       "implicit",
+				},
+				{
+					Host:          "last-resort.example.com",
+					Port:          "25",
+					AuthMechanism: "none",
+					TLSMode:       "starttls",
+				},
+			}))
+		})
+
+		It("defaults to the strict HTML policy", func() {
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.HTMLPolicy).To(Equal(sanitize.StrictPolicy))
+			Expect(env.HTMLClientPolicies).To(BeEmpty())
+		})
+
+		It("parses the HTML policies", func() {
+			os.Setenv("HTML_POLICY", `{"elements": ["p", "a"], "attributes": ["href"], "url_schemes": ["https"]}`)
+			os.Setenv("HTML_CLIENT_POLICIES", `{"some-client": {"elements": ["p", "form"], "attributes": ["action"], "url_schemes": ["https"]}}`)
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.HTMLPolicy).To(Equal(sanitize.Policy{
+				Elements:   []string{"p", "a"},
+				Attributes: []string{"href"},
+				URLSchemes: []string{"https"},
+			}))
+			Expect(env.HTMLClientPolicies).To(Equal(map[string]sanitize.Policy{
+				"some-client": {
+					Elements:   []string{"p", "form"},
+					Attributes: []string{"action"},
+					URLSchemes: []string{"https"},
+				},
+			}))
+		})
+
+		It("errors when the HTML policies are invalid", func() {
+			os.Setenv("HTML_POLICY", "banana")
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New(`Could not parse HTML_POLICY "banana", it is not a JSON object of elements, attributes and url_schemes`)}))
+
+			os.Setenv("HTML_POLICY", "")
+			os.Setenv("HTML_CLIENT_POLICIES", `["some-client"]`)
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New(`Could not parse HTML_CLIENT_POLICIES "[\"some-client\"]", it is not a JSON object of client IDs to policies`)}))
+		})
+
+		It("errors when the failover relays are invalid", func() {
+			os.Setenv("SMTP_FAILOVER_RELAYS", "banana")
+			_, err := application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New(`Could not parse SMTP_FAILOVER_RELAYS "banana", it is not a JSON array of relays`)}))
+
+			os.Setenv("SMTP_FAILOVER_RELAYS", `[{"port": "25"}]`)
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New(`SMTP_FAILOVER_RELAYS[0] requires a host and port`)}))
+
+			os.Setenv("SMTP_FAILOVER_RELAYS", `[{"host": "backup.example.com", "port": "25", "auth_mechanism": "banana"}]`)
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New(`Could not parse SMTP_FAILOVER_RELAYS[0] auth_mechanism "banana", it is not one of the allowed values: [none plain cram-md5 login xoauth2]`)}))
+
+			os.Setenv("SMTP_FAILOVER_RELAYS", `[{"host": "backup.example.com", "port": "25", "auth_mechanism": "xoauth2", "user": "backup-user"}]`)
+			_, err = application.NewEnvironment()
+			Expect(err).To(MatchError(application.EnvironmentError{errors.New(`SMTP_FAILOVER_RELAYS[0] auth_mechanism "xoauth2" requires xoauth2_token or xoauth2_token_file to be set`)}))
 		})
 
 		It("errors when the values are missing", func() {
@@ -298,6 +451,14 @@ var _ = Describe("Environment", func() {
 			_, err := application.NewEnvironment()
 			Expect(err).To(MatchError(application.EnvironmentError{viron.RequiredFieldError{"SENDER"}}))
 		})
+
+		It("parses the SENDER_DOMAINS environment variable into a list", func() {
+			os.Setenv("SENDER_DOMAINS", "example.com, cf.example,")
+
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(env.SenderDomains).To(Equal([]string{"example.com", "cf.example"}))
+		})
 	})
 
 	Describe("CloudController configuration", func() {
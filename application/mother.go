@@ -12,6 +12,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/metrics"
 	"github.com/cloudfoundry-incubator/notifications/util"
 	v1models "github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/pivotal-golang/lager"
@@ -40,30 +41,73 @@ func (m *Mother) Queue() gobble.QueueInterface {
 }
 
 func (m *Mother) MailClient() *mail.Client {
-	var authMechanism mail.AuthMechanism
-	switch m.env.SMTPAuthMechanism {
-	case SMTPAuthNone:
-		authMechanism = mail.AuthNone
-	case SMTPAuthPlain:
-		authMechanism = mail.AuthPlain
-	case SMTPAuthCRAMMD5:
-		authMechanism = mail.AuthCRAMMD5
-	}
-
 	return mail.NewClient(mail.Config{
 		User:           m.env.SMTPUser,
 		Pass:           m.env.SMTPPass,
 		Host:           m.env.SMTPHost,
 		Port:           m.env.SMTPPort,
 		Secret:         m.env.SMTPCRAMMD5Secret,
+		Token:          m.env.SMTPXOAUTH2Token,
+		TokenFile:      m.env.SMTPXOAUTH2TokenFile,
 		TestMode:       m.env.TestMode,
 		SkipVerifySSL:  !m.env.VerifySSL,
-		DisableTLS:     !m.env.SMTPTLS,
+		TLSMode:        mailTLSMode(m.env.SMTPTLSMode),
 		LoggingEnabled: m.env.SMTPLoggingEnabled,
-		AuthMechanism:  authMechanism,
+		AuthMechanism:  mailAuthMechanism(m.env.SMTPAuthMechanism),
 	})
 }
 
+func (m *Mother) MailRelayPool() *mail.RelayPool {
+	relays := []*mail.Client{m.MailClient()}
+
+	for _, relay := range m.env.SMTPFailoverRelays {
+		relays = append(relays, mail.NewClient(mail.Config{
+			User:           relay.User,
+			Pass:           relay.Pass,
+			Host:           relay.Host,
+			Port:           relay.Port,
+			Secret:         relay.CRAMMD5Secret,
+			Token:          relay.XOAUTH2Token,
+			TokenFile:      relay.XOAUTH2TokenFile,
+			TestMode:       m.env.TestMode,
+			SkipVerifySSL:  !m.env.VerifySSL,
+			TLSMode:        mailTLSMode(relay.TLSMode),
+			LoggingEnabled: m.env.SMTPLoggingEnabled,
+			AuthMechanism:  mailAuthMechanism(relay.AuthMechanism),
+		}))
+	}
+
+	cooldown := time.Duration(m.env.SMTPFailoverCooldown) * time.Millisecond
+
+	return mail.NewRelayPool(relays, cooldown, util.NewClock(), metrics.NewEmitter(metrics.DefaultLogger))
+}
+
+func mailAuthMechanism(mechanism string) mail.AuthMechanism {
+	switch mechanism {
+	case SMTPAuthPlain:
+		return mail.AuthPlain
+	case SMTPAuthCRAMMD5:
+		return mail.AuthCRAMMD5
+	case SMTPAuthLogin:
+		return mail.AuthLogin
+	case SMTPAuthXOAUTH2:
+		return mail.AuthXOAUTH2
+	default:
+		return mail.AuthNone
+	}
+}
+
+func mailTLSMode(mode string) mail.TLSMode {
+	switch mode {
+	case SMTPTLSModeNone:
+		return mail.TLSModeNone
+	case SMTPTLSModeImplicit:
+		return mail.TLSModeImplicit
+	default:
+		return mail.TLSModeStartTLS
+	}
+}
+
 func (m *Mother) Logger() lager.Logger {
 	logger := lager.NewLogger("notifications")
 	logger.RegisterSink(lager.NewWriterSink(os.Stdout, lager.DEBUG))
@@ -109,3 +153,7 @@ func (m *Mother) Database() db.DatabaseInterface {
 func (m *Mother) MessagesRepo() v1models.MessagesRepo {
 	return v1models.NewMessagesRepo(util.NewIDGenerator(rand.Reader).Generate)
 }
+
+func (m *Mother) AttachmentsRepo() v1models.AttachmentsRepo {
+	return v1models.NewAttachmentsRepo(util.NewIDGenerator(rand.Reader).Generate)
+}
@@ -0,0 +1,13 @@
+package bounces_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBouncesSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "bounces")
+}
@@ -0,0 +1,216 @@
+package bounces
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+const (
+	TypeBounce    = "bounce"
+	TypeComplaint = "complaint"
+)
+
+type ParseError struct {
+	Err error
+}
+
+func (e ParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Feedback describes a single recipient reported in a delivery status
+// notification (RFC 3464) or an abuse feedback report (RFC 5965). For
+// complaints, Status holds the reported feedback type.
+type Feedback struct {
+	Type       string
+	Recipient  string
+	Action     string
+	Status     string
+	Diagnostic string
+}
+
+func (f Feedback) Permanent() bool {
+	if f.Type == TypeComplaint {
+		return true
+	}
+
+	return f.Action == "failed" && strings.HasPrefix(f.Status, "5.")
+}
+
+func Parse(r io.Reader) ([]Feedback, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, ParseError{err}
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, ParseError{err}
+	}
+
+	if mediaType != "multipart/report" {
+		return nil, ParseError{fmt.Errorf("Unsupported content type %q, expected multipart/report", mediaType)}
+	}
+
+	reportType := strings.ToLower(params["report-type"])
+	if reportType != "delivery-status" && reportType != "feedback-report" {
+		return nil, ParseError{fmt.Errorf("Unsupported report type %q", params["report-type"])}
+	}
+
+	var feedback []Feedback
+	var originalRecipients []string
+
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ParseError{err}
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch partType {
+		case "message/delivery-status", "message/global-delivery-status":
+			bounces, err := parseDeliveryStatus(part)
+			if err != nil {
+				return nil, ParseError{err}
+			}
+			feedback = append(feedback, bounces...)
+		case "message/feedback-report":
+			complaints, err := parseFeedbackReport(part)
+			if err != nil {
+				return nil, ParseError{err}
+			}
+			feedback = append(feedback, complaints...)
+		case "message/rfc822", "text/rfc822-headers":
+			originalRecipients = parseOriginalRecipients(part)
+		}
+	}
+
+	if len(feedback) == 1 && feedback[0].Type == TypeComplaint && feedback[0].Recipient == "" {
+		complaint := feedback[0]
+		feedback = nil
+		for _, recipient := range originalRecipients {
+			complaint.Recipient = recipient
+			feedback = append(feedback, complaint)
+		}
+	}
+
+	if len(feedback) == 0 {
+		return nil, ParseError{errors.New("The report does not identify any recipients")}
+	}
+
+	return feedback, nil
+}
+
+func parseDeliveryStatus(r io.Reader) ([]Feedback, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+
+	// The first block holds the per-message fields, which are not needed.
+	_, err := reader.ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var feedback []Feedback
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		recipient := trimAddress(fieldValue(fields.Get("Final-Recipient")))
+		if recipient == "" {
+			recipient = trimAddress(fieldValue(fields.Get("Original-Recipient")))
+		}
+
+		if recipient != "" {
+			status := strings.Fields(fields.Get("Status"))
+			if len(status) == 0 {
+				status = []string{""}
+			}
+
+			feedback = append(feedback, Feedback{
+				Type:       TypeBounce,
+				Recipient:  recipient,
+				Action:     strings.ToLower(strings.TrimSpace(fields.Get("Action"))),
+				Status:     status[0],
+				Diagnostic: fieldValue(fields.Get("Diagnostic-Code")),
+			})
+		}
+
+		if err == io.EOF {
+			return feedback, nil
+		}
+	}
+}
+
+func parseFeedbackReport(r io.Reader) ([]Feedback, error) {
+	fields, err := textproto.NewReader(bufio.NewReader(r)).ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	complaint := Feedback{
+		Type:   TypeComplaint,
+		Status: strings.ToLower(strings.TrimSpace(fields.Get("Feedback-Type"))),
+	}
+
+	recipients := fields["Original-Rcpt-To"]
+	if len(recipients) == 0 {
+		return []Feedback{complaint}, nil
+	}
+
+	var feedback []Feedback
+	for _, recipient := range recipients {
+		complaint.Recipient = trimAddress(recipient)
+		feedback = append(feedback, complaint)
+	}
+
+	return feedback, nil
+}
+
+func parseOriginalRecipients(r io.Reader) []string {
+	fields, err := textproto.NewReader(bufio.NewReader(r)).ReadMIMEHeader()
+	if err != nil && err != io.EOF {
+		return nil
+	}
+
+	addresses, err := mail.Header(fields).AddressList("To")
+	if err != nil {
+		return nil
+	}
+
+	var recipients []string
+	for _, address := range addresses {
+		recipients = append(recipients, address.Address)
+	}
+
+	return recipients
+}
+
+// fieldValue strips the type prefix from fields such as
+// "Final-Recipient: rfc822; user@example.com".
+func fieldValue(field string) string {
+	if index := strings.Index(field, ";"); index >= 0 {
+		field = field[index+1:]
+	}
+
+	return strings.TrimSpace(field)
+}
+
+func trimAddress(address string) string {
+	return strings.Trim(strings.TrimSpace(address), "<>")
+}
@@ -0,0 +1,172 @@
+package bounces_test
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/bounces"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const deliveryStatusNotification = `From: MAILER-DAEMON@mx.example.com
+To: no-reply@notifications.example.com
+Subject: Undelivered Mail Returned to Sender
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=delivery-status; boundary="dsn-boundary"
+
+--dsn-boundary
+Content-Type: text/plain
+
+This is the mail system. Your message could not be delivered.
+
+--dsn-boundary
+Content-Type: message/delivery-status
+
+Reporting-MTA: dns; mx.example.com
+Arrival-Date: Tue, 30 Sep 2014 23:19:11 +0000
+
+Final-Recipient: rfc822; missing-user@example.com
+Original-Recipient: rfc822; Missing-User@example.com
+Action: failed
+Status: 5.1.1
+Diagnostic-Code: smtp; 550 5.1.1 <missing-user@example.com>: Recipient address rejected
+
+Final-Recipient: rfc822; <full-mailbox@example.com>
+Action: delayed
+Status: 4.2.2 (mailbox full)
+
+--dsn-boundary
+Content-Type: text/rfc822-headers
+
+From: no-reply@notifications.example.com
+To: missing-user@example.com, full-mailbox@example.com
+Subject: CF Notification: some-subject
+
+--dsn-boundary--
+`
+
+const feedbackReport = `From: feedback@isp.example.com
+To: no-reply@notifications.example.com
+Subject: Abuse report
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=feedback-report; boundary="arf-boundary"
+
+--arf-boundary
+Content-Type: text/plain
+
+This is an email abuse report.
+
+--arf-boundary
+Content-Type: message/feedback-report
+
+Feedback-Type: Abuse
+User-Agent: SomeGenerator/1.0
+Version: 1
+Original-Rcpt-To: <complainer@example.com>
+
+--arf-boundary
+Content-Type: message/rfc822
+
+From: no-reply@notifications.example.com
+To: complainer@example.com
+Subject: CF Notification: some-subject
+
+some message body
+--arf-boundary--
+`
+
+var _ = Describe("Parse", func() {
+	Context("when given a delivery status notification", func() {
+		It("returns a bounce for each reported recipient", func() {
+			feedback, err := bounces.Parse(strings.NewReader(deliveryStatusNotification))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(feedback).To(Equal([]bounces.Feedback{
+				{
+					Type:       bounces.TypeBounce,
+					Recipient:  "missing-user@example.com",
+					Action:     "failed",
+					Status:     "5.1.1",
+					Diagnostic: "550 5.1.1 <missing-user@example.com>: Recipient address rejected",
+				},
+				{
+					Type:      bounces.TypeBounce,
+					Recipient: "full-mailbox@example.com",
+					Action:    "delayed",
+					Status:    "4.2.2",
+				},
+			}))
+		})
+	})
+
+	Context("when given an abuse feedback report", func() {
+		It("returns a complaint for the original recipient", func() {
+			feedback, err := bounces.Parse(strings.NewReader(feedbackReport))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(feedback).To(Equal([]bounces.Feedback{
+				{
+					Type:      bounces.TypeComplaint,
+					Recipient: "complainer@example.com",
+					Status:    "abuse",
+				},
+			}))
+		})
+
+		It("falls back to the recipients of the original message", func() {
+			report := strings.Replace(feedbackReport, "Original-Rcpt-To: <complainer@example.com>\n", "", 1)
+
+			feedback, err := bounces.Parse(strings.NewReader(report))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(feedback).To(Equal([]bounces.Feedback{
+				{
+					Type:      bounces.TypeComplaint,
+					Recipient: "complainer@example.com",
+					Status:    "abuse",
+				},
+			}))
+		})
+	})
+
+	Context("when an error occurs", func() {
+		It("returns a parse error when the message is not a report", func() {
+			_, err := bounces.Parse(strings.NewReader("Content-Type: text/plain\n\nhello\n"))
+			Expect(err).To(MatchError(bounces.ParseError{Err: errors.New("Unsupported content type \"text/plain\", expected multipart/report")}))
+		})
+
+		It("returns a parse error when the report type is not supported", func() {
+			report := strings.Replace(deliveryStatusNotification, "report-type=delivery-status", "report-type=disposition-notification", 1)
+
+			_, err := bounces.Parse(strings.NewReader(report))
+			Expect(err).To(BeAssignableToTypeOf(bounces.ParseError{}))
+		})
+
+		It("returns a parse error when the report does not identify any recipients", func() {
+			report := strings.Replace(feedbackReport, "Original-Rcpt-To: <complainer@example.com>\n", "", 1)
+			report = strings.Replace(report, "To: complainer@example.com\n", "", 1)
+
+			_, err := bounces.Parse(strings.NewReader(report))
+			Expect(err).To(MatchError(bounces.ParseError{Err: errors.New("The report does not identify any recipients")}))
+		})
+	})
+})
+
+var _ = Describe("Feedback", func() {
+	Describe("Permanent", func() {
+		It("is true for failed deliveries with a permanent status", func() {
+			Expect(bounces.Feedback{Type: bounces.TypeBounce, Action: "failed", Status: "5.1.1"}.Permanent()).To(BeTrue())
+		})
+
+		It("is false for delayed or transient failures", func() {
+			Expect(bounces.Feedback{Type: bounces.TypeBounce, Action: "delayed", Status: "4.2.2"}.Permanent()).To(BeFalse())
+			Expect(bounces.Feedback{Type: bounces.TypeBounce, Action: "failed", Status: "4.4.1"}.Permanent()).To(BeFalse())
+		})
+
+		It("is true for complaints", func() {
+			Expect(bounces.Feedback{Type: bounces.TypeComplaint, Status: "abuse"}.Permanent()).To(BeTrue())
+		})
+	})
+})
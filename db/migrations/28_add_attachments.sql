@@ -0,0 +1,16 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `attachments` (
+      `id` varchar(36) NOT NULL,
+      `campaign_id` varchar(36) DEFAULT NULL,
+      `filename` varchar(255) DEFAULT NULL,
+      `content_type` varchar(255) DEFAULT NULL,
+      `content_id` varchar(255) DEFAULT NULL,
+      `content` longblob,
+      `created_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE attachments;
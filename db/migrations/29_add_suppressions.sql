@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `suppressions` (
+      `address` varchar(255) NOT NULL,
+      `reason` varchar(255) DEFAULT NULL,
+      `status` varchar(255) DEFAULT NULL,
+      `diagnostic` text,
+      `created_at` datetime DEFAULT NULL,
+      `updated_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`address`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE suppressions;
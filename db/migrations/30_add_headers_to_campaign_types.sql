@@ -0,0 +1,8 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `campaign_types` ADD `headers` longtext;
+UPDATE `campaign_types` SET `headers` = '{}';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `campaign_types` DROP COLUMN `headers`;
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `senders` ADD `from_address` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `senders` ADD `from_name` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `campaign_types` ADD `from_address` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `campaign_types` ADD `from_name` varchar(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `senders` DROP COLUMN `from_address`;
+ALTER TABLE `senders` DROP COLUMN `from_name`;
+ALTER TABLE `campaign_types` DROP COLUMN `from_address`;
+ALTER TABLE `campaign_types` DROP COLUMN `from_name`;
@@ -0,0 +1,24 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `transports` (
+      `name` varchar(255) NOT NULL,
+      `host` varchar(255) DEFAULT NULL,
+      `port` varchar(255) DEFAULT NULL,
+      `user` varchar(255) DEFAULT NULL,
+      `pass` text,
+      `crammd5_secret` text,
+      `xoauth2_token` text,
+      `auth_mechanism` varchar(255) DEFAULT NULL,
+      `tls_mode` varchar(255) DEFAULT NULL,
+      `created_at` datetime DEFAULT NULL,
+      `updated_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+ALTER TABLE `senders` ADD `transport` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `clients` ADD `transport` varchar(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE transports;
+ALTER TABLE `senders` DROP COLUMN `transport`;
+ALTER TABLE `clients` DROP COLUMN `transport`;
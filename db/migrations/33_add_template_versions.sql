@@ -0,0 +1,43 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `v2_template_versions` (
+      `id` varchar(36) NOT NULL,
+      `template_id` varchar(36) NOT NULL,
+      `version` int(11) NOT NULL,
+      `name` varchar(255) DEFAULT NULL,
+      `html` longtext DEFAULT NULL,
+      `text` longtext DEFAULT NULL,
+      `subject` varchar(255) DEFAULT NULL,
+      `metadata` longtext DEFAULT NULL,
+      `created_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`id`),
+      UNIQUE KEY `template_id_version` (`template_id`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+CREATE TABLE IF NOT EXISTS `template_versions` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `template_id` varchar(255) NOT NULL,
+      `version` int(11) NOT NULL,
+      `name` varchar(255) DEFAULT NULL,
+      `subject` varchar(255) DEFAULT NULL,
+      `text` longtext DEFAULT NULL,
+      `html` longtext DEFAULT NULL,
+      `metadata` longtext DEFAULT NULL,
+      `created_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`primary`),
+      UNIQUE KEY `template_id_version` (`template_id`, `version`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+ALTER TABLE `v2_templates` ADD `version` int(11) NOT NULL DEFAULT 1;
+ALTER TABLE `templates` ADD `version` int(11) NOT NULL DEFAULT 1;
+ALTER TABLE `campaigns` ADD `template_version` int(11) NOT NULL DEFAULT 0;
+INSERT INTO `v2_template_versions` (`id`, `template_id`, `version`, `name`, `html`, `text`, `subject`, `metadata`, `created_at`)
+      SELECT UUID(), `id`, 1, `name`, `html`, `text`, `subject`, `metadata`, UTC_TIMESTAMP() FROM `v2_templates`;
+INSERT INTO `template_versions` (`template_id`, `version`, `name`, `subject`, `text`, `html`, `metadata`, `created_at`)
+      SELECT `id`, 1, `name`, `subject`, `text`, `html`, `metadata`, `updated_at` FROM `templates`;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE v2_template_versions;
+DROP TABLE template_versions;
+ALTER TABLE `v2_templates` DROP COLUMN `version`;
+ALTER TABLE `templates` DROP COLUMN `version`;
+ALTER TABLE `campaigns` DROP COLUMN `template_version`;
@@ -0,0 +1,24 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `v2_templates` ADD `localizations` longtext DEFAULT NULL;
+ALTER TABLE `v2_template_versions` ADD `localizations` longtext DEFAULT NULL;
+ALTER TABLE `templates` ADD `localizations` longtext DEFAULT NULL;
+ALTER TABLE `template_versions` ADD `localizations` longtext DEFAULT NULL;
+UPDATE `v2_templates` SET `localizations` = '{}';
+UPDATE `v2_template_versions` SET `localizations` = '{}';
+UPDATE `templates` SET `localizations` = '{}';
+UPDATE `template_versions` SET `localizations` = '{}';
+CREATE TABLE IF NOT EXISTS `user_locales` (
+      `user_id` varchar(255) NOT NULL,
+      `locale` varchar(255) NOT NULL,
+      `updated_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `v2_templates` DROP COLUMN `localizations`;
+ALTER TABLE `v2_template_versions` DROP COLUMN `localizations`;
+ALTER TABLE `templates` DROP COLUMN `localizations`;
+ALTER TABLE `template_versions` DROP COLUMN `localizations`;
+DROP TABLE user_locales;
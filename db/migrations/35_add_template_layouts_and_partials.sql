@@ -0,0 +1,25 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `v2_templates` ADD `layout_id` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `v2_template_versions` ADD `layout_id` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `templates` ADD `layout_id` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `template_versions` ADD `layout_id` varchar(255) NOT NULL DEFAULT '';
+CREATE TABLE IF NOT EXISTS `v2_template_partials` (
+      `id` varchar(255) NOT NULL,
+      `client_id` varchar(255) NOT NULL,
+      `name` varchar(255) NOT NULL,
+      `text` longtext,
+      `html` longtext,
+      `created_at` datetime DEFAULT NULL,
+      `updated_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`id`),
+      UNIQUE KEY `client_id_name` (`client_id`, `name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `v2_templates` DROP COLUMN `layout_id`;
+ALTER TABLE `v2_template_versions` DROP COLUMN `layout_id`;
+ALTER TABLE `templates` DROP COLUMN `layout_id`;
+ALTER TABLE `template_versions` DROP COLUMN `layout_id`;
+DROP TABLE v2_template_partials;
@@ -0,0 +1,21 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `v2_templates` ADD `disable_auto_text` bool NOT NULL DEFAULT FALSE;
+ALTER TABLE `v2_templates` ADD `disable_css_inlining` bool NOT NULL DEFAULT FALSE;
+ALTER TABLE `v2_template_versions` ADD `disable_auto_text` bool NOT NULL DEFAULT FALSE;
+ALTER TABLE `v2_template_versions` ADD `disable_css_inlining` bool NOT NULL DEFAULT FALSE;
+ALTER TABLE `templates` ADD `disable_auto_text` bool NOT NULL DEFAULT FALSE;
+ALTER TABLE `templates` ADD `disable_css_inlining` bool NOT NULL DEFAULT FALSE;
+ALTER TABLE `template_versions` ADD `disable_auto_text` bool NOT NULL DEFAULT FALSE;
+ALTER TABLE `template_versions` ADD `disable_css_inlining` bool NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `v2_templates` DROP COLUMN `disable_auto_text`;
+ALTER TABLE `v2_templates` DROP COLUMN `disable_css_inlining`;
+ALTER TABLE `v2_template_versions` DROP COLUMN `disable_auto_text`;
+ALTER TABLE `v2_template_versions` DROP COLUMN `disable_css_inlining`;
+ALTER TABLE `templates` DROP COLUMN `disable_auto_text`;
+ALTER TABLE `templates` DROP COLUMN `disable_css_inlining`;
+ALTER TABLE `template_versions` DROP COLUMN `disable_auto_text`;
+ALTER TABLE `template_versions` DROP COLUMN `disable_css_inlining`;
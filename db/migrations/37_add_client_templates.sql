@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `v2_client_templates` (
+      `client_id` varchar(255) NOT NULL,
+      `template_id` varchar(255) NOT NULL DEFAULT '',
+      `created_at` datetime DEFAULT NULL,
+      `updated_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`client_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+ALTER TABLE `campaigns` ADD `template_source` varchar(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE v2_client_templates;
+ALTER TABLE `campaigns` DROP COLUMN `template_source`;
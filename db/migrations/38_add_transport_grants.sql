@@ -0,0 +1,12 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `transport_grants` (
+      `transport_name` varchar(255) NOT NULL,
+      `client_id` varchar(255) NOT NULL,
+      `created_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`transport_name`, `client_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE transport_grants;
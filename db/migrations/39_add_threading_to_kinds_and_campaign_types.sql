@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `kinds` ADD `threading` bool NOT NULL DEFAULT FALSE;
+ALTER TABLE `campaign_types` ADD `threading` bool NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `kinds` DROP COLUMN `threading`;
+ALTER TABLE `campaign_types` DROP COLUMN `threading`;
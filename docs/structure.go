@@ -73,6 +73,64 @@ var Structure = []Resource{
 				Key:         "template-delete",
 				Description: "Delete a template",
 			},
+			{
+				Key:         "template-client-default-get",
+				Description: "Retrieve the default template of the client",
+			},
+			{
+				Key:         "template-client-default-update",
+				Description: "Set the default template of the client",
+			},
+			{
+				Key:         "template-associations",
+				Description: "List the campaign types and campaigns using a template",
+			},
+			{
+				Key:         "template-version-list",
+				Description: "Retrieve the versions of a template",
+			},
+			{
+				Key:         "template-version-get",
+				Description: "Retrieve a version of a template",
+			},
+			{
+				Key:         "template-diff",
+				Description: "Compare two versions of a template",
+			},
+			{
+				Key:         "template-rollback",
+				Description: "Roll a template back to an earlier version",
+			},
+			{
+				Key:         "template-preview",
+				Description: "Preview a template with sample data",
+			},
+			{
+				Key:         "template-preview-inline",
+				Description: "Preview an unsaved template with sample data",
+			},
+		},
+	},
+	{
+		Name:        "Partials",
+		Description: "Partials are named snippets that a client's templates and layouts include with {{template \"name\" .}}.",
+		Endpoints: []Endpoint{
+			{
+				Key:         "partial-set",
+				Description: "Create or update a partial",
+			},
+			{
+				Key:         "partial-list",
+				Description: "Retrieve a list of partials",
+			},
+			{
+				Key:         "partial-get",
+				Description: "Retrieve a partial",
+			},
+			{
+				Key:         "partial-delete",
+				Description: "Delete a partial",
+			},
 		},
 	},
 	{
@@ -103,7 +161,7 @@ var Structure = []Resource{
 	},
 	{
 		Name:        "Campaigns",
-		Description: "Campaigns are an email to a set of users using a template provided directly or via a campaign type or via the default template.",
+		Description: "Campaigns are an email to a set of users using a template provided directly or via a campaign type or via the default template. A campaign records the version of its template when it is created and is sent with that version, but any layouts and partials the template uses are sent as they are when each message is delivered.",
 		Endpoints: []Endpoint{
 			{
 				Key:         "campaign-create",
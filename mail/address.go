@@ -0,0 +1,118 @@
+package mail
+
+import (
+	"fmt"
+	"mime"
+	netmail "net/mail"
+	"strings"
+)
+
+type UndeliverableError struct {
+	Err error
+}
+
+func (e UndeliverableError) Error() string {
+	return e.Err.Error()
+}
+
+// EnvelopeAddress prepares an address for the SMTP envelope. Internationalized
+// domains are converted to their ASCII form. A non-ASCII local part has no
+// ASCII equivalent, so it can only be sent to servers that advertise SMTPUTF8.
+func EnvelopeAddress(address string, smtpUTF8 bool) (string, error) {
+	if parsed, err := netmail.ParseAddress(address); err == nil {
+		address = parsed.Address
+	}
+
+	index := strings.LastIndex(address, "@")
+	if index < 0 {
+		return address, nil
+	}
+
+	local, domain := address[:index], address[index+1:]
+
+	domain, err := ToASCII(domain)
+	if err != nil {
+		return "", UndeliverableError{fmt.Errorf("address %q has an invalid domain: %s", address, err)}
+	}
+
+	if !isASCII(local) && !smtpUTF8 {
+		return "", UndeliverableError{fmt.Errorf("address %q requires SMTPUTF8, which the mail server does not support", address)}
+	}
+
+	return local + "@" + domain, nil
+}
+
+// ValidateFrom checks a configurable From name and address. The address must
+// be a bare address in one of the operator-approved sending domains. Empty
+// values are valid and leave the default sender in place.
+func ValidateFrom(name, address string, allowedDomains []string) error {
+	if strings.ContainsAny(name, "\r\n") {
+		return fmt.Errorf("from_name must not contain line breaks")
+	}
+
+	if address == "" {
+		return nil
+	}
+
+	parsed, err := netmail.ParseAddress(address)
+	if err != nil || parsed.Address != address {
+		return fmt.Errorf("from_address %q is not a valid email address", address)
+	}
+
+	domain, err := ToASCII(address[strings.LastIndex(address, "@")+1:])
+	if err != nil {
+		return fmt.Errorf("from_address %q has an invalid domain: %s", address, err)
+	}
+
+	for _, allowed := range allowedDomains {
+		allowed, err := ToASCII(allowed)
+		if err != nil {
+			continue
+		}
+
+		if strings.EqualFold(domain, allowed) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("from_address %q is not in an allowed sending domain", address)
+}
+
+// FormatFromAddress combines a display name and an address into a From
+// header value, eg. "Billing <billing@example.com>".
+func FormatFromAddress(name, address string) string {
+	if name == "" {
+		return address
+	}
+
+	return (&netmail.Address{Name: name, Address: address}).String()
+}
+
+// encodeAddressHeader renders an address header value with an RFC 2047
+// encoded display name and an ASCII domain. Values that cannot be parsed are
+// returned unchanged.
+func encodeAddressHeader(value string) string {
+	if value == "" {
+		return value
+	}
+
+	address, err := netmail.ParseAddress(value)
+	if err != nil {
+		return value
+	}
+
+	address.Address, err = EnvelopeAddress(address.Address, true)
+	if err != nil {
+		return value
+	}
+
+	if address.Name == "" {
+		return address.Address
+	}
+
+	return address.String()
+}
+
+func encodeHeader(value string) string {
+	return mime.QEncoding.Encode("utf-8", value)
+}
@@ -0,0 +1,56 @@
+package mail_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Address", func() {
+	Describe("ValidateFrom", func() {
+		var allowedDomains []string
+
+		BeforeEach(func() {
+			allowedDomains = []string{"example.com", "bücher.example"}
+		})
+
+		It("accepts addresses in an allowed sending domain", func() {
+			Expect(mail.ValidateFrom("Billing", "billing@example.com", allowedDomains)).To(Succeed())
+			Expect(mail.ValidateFrom("", "billing@EXAMPLE.com", allowedDomains)).To(Succeed())
+			Expect(mail.ValidateFrom("", "billing@xn--bcher-kva.example", allowedDomains)).To(Succeed())
+		})
+
+		It("accepts an empty address", func() {
+			Expect(mail.ValidateFrom("Billing", "", nil)).To(Succeed())
+		})
+
+		It("rejects addresses outside the allowed sending domains", func() {
+			err := mail.ValidateFrom("", "billing@elsewhere.com", allowedDomains)
+			Expect(err).To(MatchError(`from_address "billing@elsewhere.com" is not in an allowed sending domain`))
+
+			err = mail.ValidateFrom("", "billing@example.com", nil)
+			Expect(err).To(MatchError(`from_address "billing@example.com" is not in an allowed sending domain`))
+		})
+
+		It("rejects addresses that include a display name", func() {
+			err := mail.ValidateFrom("", "Billing <billing@example.com>", allowedDomains)
+			Expect(err).To(MatchError(`from_address "Billing <billing@example.com>" is not a valid email address`))
+		})
+
+		It("rejects names that contain line breaks", func() {
+			err := mail.ValidateFrom("Billing\r\nBcc: someone@example.com", "billing@example.com", allowedDomains)
+			Expect(err).To(MatchError("from_name must not contain line breaks"))
+		})
+	})
+
+	Describe("FormatFromAddress", func() {
+		It("combines the name and the address", func() {
+			Expect(mail.FormatFromAddress("Billing", "billing@example.com")).To(Equal(`"Billing" <billing@example.com>`))
+		})
+
+		It("returns the bare address when there is no name", func() {
+			Expect(mail.FormatFromAddress("", "billing@example.com")).To(Equal("billing@example.com"))
+		})
+	})
+})
@@ -0,0 +1,89 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+type loginAuth struct {
+	username string
+	password string
+	host     string
+}
+
+// LoginAuth returns an smtp.Auth that implements the LOGIN authentication
+// mechanism. Like smtp.PlainAuth, it will only send credentials over TLS
+// or to localhost.
+func LoginAuth(username, password, host string) smtp.Auth {
+	return &loginAuth{
+		username: username,
+		password: password,
+		host:     host,
+	}
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge: %q", fromServer)
+	}
+}
+
+type xoauth2Auth struct {
+	username string
+	token    string
+}
+
+// XOAUTH2Auth returns an smtp.Auth that implements the XOAUTH2
+// authentication mechanism using the given OAuth2 bearer token.
+func XOAUTH2Auth(username, token string) smtp.Auth {
+	return &xoauth2Auth{
+		username: username,
+		token:    token,
+	}
+}
+
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+
+	return "XOAUTH2", []byte("user=" + a.username + "\x01auth=Bearer " + a.token + "\x01\x01"), nil
+}
+
+func (a *xoauth2Auth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if more {
+		// The server sends a base64 encoded JSON error as a challenge when
+		// the token is rejected. Responding with an empty message causes it
+		// to finish the exchange with the actual failure reply.
+		return []byte{}, nil
+	}
+
+	return nil, nil
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
@@ -1,10 +1,12 @@
 package mail
 
 import (
+	"bytes"
 	"crypto/tls"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/smtp"
 	"strings"
@@ -13,14 +15,26 @@ import (
 	"github.com/pivotal-golang/lager"
 )
 
+var errServerTimeout = errors.New("server timeout")
+
 const (
 	AuthNone AuthMechanism = iota
 	AuthPlain
 	AuthCRAMMD5
+	AuthLogin
+	AuthXOAUTH2
+)
+
+const (
+	TLSModeStartTLS TLSMode = iota
+	TLSModeNone
+	TLSModeImplicit
 )
 
 type AuthMechanism int
 
+type TLSMode int
+
 type Client struct {
 	config Config
 	client *smtp.Client
@@ -32,10 +46,12 @@ type Config struct {
 	User           string
 	Pass           string
 	Secret         string
+	Token          string
+	TokenFile      string
 	AuthMechanism  AuthMechanism
 	TestMode       bool
 	SkipVerifySSL  bool
-	DisableTLS     bool
+	TLSMode        TLSMode
 	ConnectTimeout time.Duration
 	LoggingEnabled bool
 }
@@ -87,7 +103,7 @@ func (c *Client) Connect(logger lager.Logger) error {
 		c.client = connection.client
 	case <-time.After(c.config.ConnectTimeout):
 		c.PrintLog(logger, "connection-timeout", lager.Data{"timeout-duration": c.config.ConnectTimeout})
-		return errors.New("server timeout")
+		return errServerTimeout
 	}
 
 	return nil
@@ -97,7 +113,24 @@ func (c *Client) connect() chan connection {
 	channel := make(chan connection)
 
 	go func() {
-		client, err := smtp.Dial(net.JoinHostPort(c.config.Host, c.config.Port))
+		address := c.address()
+
+		if c.config.TLSMode != TLSModeImplicit {
+			client, err := smtp.Dial(address)
+			channel <- connection{
+				client: client,
+				err:    err,
+			}
+			return
+		}
+
+		conn, err := tls.Dial("tcp", address, c.tlsConfig())
+		if err != nil {
+			channel <- connection{err: err}
+			return
+		}
+
+		client, err := smtp.NewClient(conn, c.config.Host)
 		channel <- connection{
 			client: client,
 			err:    err,
@@ -107,6 +140,10 @@ func (c *Client) connect() chan connection {
 	return channel
 }
 
+func (c *Client) address() string {
+	return net.JoinHostPort(c.config.Host, c.config.Port)
+}
+
 func (c *Client) Send(msg Message, logger lager.Logger) error {
 	logger = c.createLoggerSession(logger)
 
@@ -127,13 +164,15 @@ func (c *Client) Send(msg Message, logger lager.Logger) error {
 	}
 	c.PrintLog(logger, "hello-complete")
 
-	if !c.config.DisableTLS {
-		c.PrintLog(logger, "tls-starting")
-		err = c.StartTLS()
-		if err != nil {
-			return c.Error(logger, err)
+	if c.config.TLSMode != TLSModeNone {
+		if c.config.TLSMode == TLSModeStartTLS {
+			c.PrintLog(logger, "tls-starting")
+			err = c.StartTLS()
+			if err != nil {
+				return c.Error(logger, err)
+			}
+			c.PrintLog(logger, "tls-connected")
 		}
-		c.PrintLog(logger, "tls-connected")
 
 		c.PrintLog(logger, "authentication-starting")
 		err = c.Auth(logger)
@@ -143,14 +182,31 @@ func (c *Client) Send(msg Message, logger lager.Logger) error {
 		c.PrintLog(logger, "authenticated")
 	}
 
-	c.PrintLog(logger, "setting-msg-from", lager.Data{"from": msg.From})
-	err = c.client.Mail(msg.From)
+	smtpUTF8, _ := c.Extension("SMTPUTF8")
+
+	envelopeFrom := msg.EnvelopeFrom
+	if envelopeFrom == "" {
+		envelopeFrom = msg.From
+	}
+
+	from, err := EnvelopeAddress(envelopeFrom, smtpUTF8)
+	if err != nil {
+		return c.Error(logger, err)
+	}
+
+	to, err := EnvelopeAddress(msg.To, smtpUTF8)
 	if err != nil {
 		return c.Error(logger, err)
 	}
 
-	c.PrintLog(logger, "setting-msg-to", lager.Data{"to": msg.To})
-	err = c.client.Rcpt(msg.To)
+	c.PrintLog(logger, "setting-msg-from", lager.Data{"from": from})
+	err = c.client.Mail(from)
+	if err != nil {
+		return c.Error(logger, err)
+	}
+
+	c.PrintLog(logger, "setting-msg-to", lager.Data{"to": to})
+	err = c.client.Rcpt(to)
 	if err != nil {
 		return c.Error(logger, err)
 	}
@@ -187,10 +243,7 @@ func (c *Client) Extension(name string) (bool, string) {
 
 func (c *Client) StartTLS() error {
 	if ok, _ := c.Extension("STARTTLS"); ok {
-		err := c.client.StartTLS(&tls.Config{
-			ServerName:         c.config.Host,
-			InsecureSkipVerify: c.config.SkipVerifySSL,
-		})
+		err := c.client.StartTLS(c.tlsConfig())
 		if err != nil {
 			return err
 		}
@@ -199,11 +252,33 @@ func (c *Client) StartTLS() error {
 	return nil
 }
 
+func (c *Client) tlsConfig() *tls.Config {
+	return &tls.Config{
+		ServerName:         c.config.Host,
+		InsecureSkipVerify: c.config.SkipVerifySSL,
+	}
+}
+
+// Auth authenticates the connection when the server supports it. Failures
+// are logged as "authentication-failed" regardless of whether SMTP logging
+// is enabled, so that rejected or expired credentials are easy to spot.
 func (c *Client) Auth(logger lager.Logger) error {
 	if ok, _ := c.Extension("AUTH"); ok {
-		if mechanism := c.AuthMechanism(logger); mechanism != nil {
-			err := c.client.Auth(mechanism)
+		mechanism, err := c.AuthMechanism(logger)
+		if err != nil {
+			logger.Error("authentication-failed", err, lager.Data{"host": c.config.Host})
+			return err
+		}
+
+		if mechanism != nil {
+			err = c.client.Auth(mechanism)
 			if err != nil {
+				// net/smtp sends QUIT when authentication fails, so the
+				// connection cannot be reused or quit again.
+				c.client.Close()
+				c.client = nil
+
+				logger.Error("authentication-failed", err, lager.Data{"host": c.config.Host})
 				return err
 			}
 		}
@@ -212,18 +287,49 @@ func (c *Client) Auth(logger lager.Logger) error {
 	return nil
 }
 
-func (c *Client) AuthMechanism(logger lager.Logger) smtp.Auth {
+// AuthMechanism returns the configured authentication strategy. An XOAUTH2
+// token file is read each time, so that a token refreshed by another process
+// is picked up by the next connection.
+func (c *Client) AuthMechanism(logger lager.Logger) (smtp.Auth, error) {
 	switch c.config.AuthMechanism {
 	case AuthCRAMMD5:
 		c.PrintLog(logger, "crammd5-authentication")
-		return smtp.CRAMMD5Auth(c.config.User, c.config.Secret)
+		return smtp.CRAMMD5Auth(c.config.User, c.config.Secret), nil
 	case AuthPlain:
 		c.PrintLog(logger, "plain-authentication")
-		return smtp.PlainAuth("", c.config.User, c.config.Pass, c.config.Host)
+		return smtp.PlainAuth("", c.config.User, c.config.Pass, c.config.Host), nil
+	case AuthLogin:
+		c.PrintLog(logger, "login-authentication")
+		return LoginAuth(c.config.User, c.config.Pass, c.config.Host), nil
+	case AuthXOAUTH2:
+		c.PrintLog(logger, "xoauth2-authentication")
+		token, err := c.xoauth2Token()
+		if err != nil {
+			return nil, err
+		}
+
+		return XOAUTH2Auth(c.config.User, token), nil
 	default:
 		c.PrintLog(logger, "no-authentication")
-		return nil
+		return nil, nil
+	}
+}
+
+func (c *Client) xoauth2Token() (string, error) {
+	if c.config.TokenFile == "" {
+		return c.config.Token, nil
 	}
+
+	token, err := ioutil.ReadFile(c.config.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("could not read XOAUTH2 token file: %s", err)
+	}
+
+	if len(bytes.TrimSpace(token)) == 0 {
+		return "", fmt.Errorf("XOAUTH2 token file %q is empty", c.config.TokenFile)
+	}
+
+	return string(bytes.TrimSpace(token)), nil
 }
 
 func (c *Client) Data(msg Message) error {
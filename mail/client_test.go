@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net"
 	"net/smtp"
+	"net/textproto"
+	"os"
 	"strings"
 	"time"
 
@@ -64,7 +67,7 @@ var _ = Describe("Mail", func() {
 			Pass:          "pass",
 			TestMode:      false,
 			SkipVerifySSL: true,
-			DisableTLS:    false,
+			TLSMode:       mail.TLSModeStartTLS,
 		}
 
 		config.Host, config.Port, err = net.SplitHostPort(mailServer.URL.String())
@@ -207,6 +210,32 @@ var _ = Describe("Mail", func() {
 			Expect(delivery.UsedTLS).To(BeTrue())
 		})
 
+		It("sends from the envelope sender when one is set", func() {
+			msg := mail.Message{
+				From:         `"Billing" <billing@example.com>`,
+				EnvelopeFrom: "bounces@example.com",
+				To:           "you@example.com",
+				Subject:      "Your invoice",
+				Body: []mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "Your invoice is attached.",
+					},
+				},
+			}
+
+			err := client.Send(msg, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(mailServer.Deliveries)
+			}).Should(Equal(1))
+			delivery := mailServer.Deliveries[0]
+
+			Expect(delivery.Sender).To(Equal("bounces@example.com"))
+			Expect(delivery.Data).To(ContainElement(ContainSubstring("From: \"Billing\" <billing@example.com>")))
+		})
+
 		It("can make multiple requests", func() {
 			firstMsg := mail.Message{
 				From:    "me@example.com",
@@ -261,6 +290,67 @@ var _ = Describe("Mail", func() {
 			Expect(delivery.Data).To(Equal(strings.Split(secondMsg.Data(), "\n")))
 		})
 
+		It("converts internationalized domains to ASCII in the envelope", func() {
+			msg := mail.Message{
+				From:    "me@example.com",
+				To:      "you@bücher.example",
+				Subject: "Urgent! Read now!",
+				Body: []mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "This email is the most important thing you will read all day!",
+					},
+				},
+			}
+
+			err := client.Send(msg, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int {
+				return len(mailServer.Deliveries)
+			}).Should(Equal(1))
+
+			Expect(mailServer.Deliveries[0].Recipient).To(Equal("you@xn--bcher-kva.example"))
+		})
+
+		Context("when the recipient has a non-ASCII local part", func() {
+			var msg mail.Message
+
+			BeforeEach(func() {
+				msg = mail.Message{
+					From:    "me@example.com",
+					To:      "ユーザー@例え.jp",
+					Subject: "Urgent! Read now!",
+					Body: []mail.Part{
+						{
+							ContentType: "text/plain",
+							Content:     "This email is the most important thing you will read all day!",
+						},
+					},
+				}
+			})
+
+			It("uses SMTPUTF8 when the server advertises it", func() {
+				mailServer.SupportsUTF8 = true
+
+				err := client.Send(msg, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() int {
+					return len(mailServer.Deliveries)
+				}).Should(Equal(1))
+				delivery := mailServer.Deliveries[0]
+
+				Expect(delivery.Recipient).To(Equal("ユーザー@xn--r8jz45g.jp"))
+				Expect(delivery.MailParams).To(ContainSubstring("SMTPUTF8"))
+			})
+
+			It("returns an undeliverable error when the server does not advertise SMTPUTF8", func() {
+				err := client.Send(msg, logger)
+				Expect(err).To(MatchError(mail.UndeliverableError{Err: errors.New(`address "ユーザー@例え.jp" requires SMTPUTF8, which the mail server does not support`)}))
+			})
+		})
+
 		Context("when configured to use TLS", func() {
 			BeforeEach(func() {
 				config.SkipVerifySSL = true
@@ -294,10 +384,175 @@ var _ = Describe("Mail", func() {
 			})
 		})
 
+		Context("when configured to use implicit TLS", func() {
+			BeforeEach(func() {
+				mailServer.ImplicitTLS = true
+				config.TLSMode = mail.TLSModeImplicit
+				config.AuthMechanism = mail.AuthPlain
+				client = mail.NewClient(config)
+			})
+
+			It("communicates over TLS from the start of the connection", func() {
+				msg := mail.Message{
+					From:    "me@example.com",
+					To:      "you@example.com",
+					Subject: "Urgent! Read now!",
+					Body: []mail.Part{
+						{
+							ContentType: "text/plain",
+							Content:     "This email is the most important thing you will read all day!",
+						},
+					},
+				}
+
+				err := client.Send(msg, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() int {
+					return len(mailServer.Deliveries)
+				}).Should(Equal(1))
+
+				delivery := mailServer.Deliveries[0]
+				Expect(delivery.UsedTLS).To(BeTrue())
+				Expect(delivery.Auth).To(Equal("PLAIN"))
+				Expect(delivery.Recipient).To(Equal("you@example.com"))
+			})
+		})
+
+		Context("when configured to use LOGIN auth", func() {
+			BeforeEach(func() {
+				config.AuthMechanism = mail.AuthLogin
+				client = mail.NewClient(config)
+			})
+
+			It("authenticates using the LOGIN mechanism", func() {
+				err := client.Send(mail.Message{
+					From:    "me@example.com",
+					To:      "you@example.com",
+					Subject: "Urgent! Read now!",
+				}, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() int {
+					return len(mailServer.Deliveries)
+				}).Should(Equal(1))
+				Expect(mailServer.Deliveries[0].Auth).To(Equal("LOGIN"))
+			})
+		})
+
+		Context("when configured to use XOAUTH2 auth", func() {
+			BeforeEach(func() {
+				config.AuthMechanism = mail.AuthXOAUTH2
+				config.Token = "some-oauth-token"
+				client = mail.NewClient(config)
+			})
+
+			It("authenticates using the XOAUTH2 mechanism", func() {
+				err := client.Send(mail.Message{
+					From:    "me@example.com",
+					To:      "you@example.com",
+					Subject: "Urgent! Read now!",
+				}, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() int {
+					return len(mailServer.Deliveries)
+				}).Should(Equal(1))
+				Expect(mailServer.Deliveries[0].Auth).To(Equal("XOAUTH2"))
+				Expect(mailServer.Deliveries[0].AuthToken).To(Equal("some-oauth-token"))
+			})
+
+			It("logs when the server rejects the token", func() {
+				mailServer.RejectsAuth = true
+
+				err := client.Send(mail.Message{
+					From:    "me@example.com",
+					To:      "you@example.com",
+					Subject: "Urgent! Read now!",
+				}, logger)
+				rejection := &textproto.Error{Code: 535, Msg: "5.7.8 Username and Password not accepted"}
+				Expect(err).To(Equal(rejection))
+
+				lines, err := parseLogLines(buffer.Bytes())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lines).To(ContainElement(logLine{
+					Source:   "notifications",
+					Message:  "notifications.smtp.authentication-failed",
+					LogLevel: int(lager.ERROR),
+					Data: map[string]interface{}{
+						"session": "1",
+						"host":    config.Host,
+						"error":   rejection.Error(),
+					},
+				}))
+			})
+		})
+
+		Context("when configured to use an XOAUTH2 token file", func() {
+			var tokenFile string
+
+			BeforeEach(func() {
+				file, err := ioutil.TempFile("", "xoauth2-token")
+				Expect(err).NotTo(HaveOccurred())
+				tokenFile = file.Name()
+				file.Close()
+
+				config.AuthMechanism = mail.AuthXOAUTH2
+				config.TokenFile = tokenFile
+				client = mail.NewClient(config)
+			})
+
+			AfterEach(func() {
+				os.Remove(tokenFile)
+			})
+
+			It("reads the token from the file on each connection", func() {
+				for _, token := range []string{"first-oauth-token", "refreshed-oauth-token"} {
+					err := ioutil.WriteFile(tokenFile, []byte(token+"\n"), 0600)
+					Expect(err).NotTo(HaveOccurred())
+
+					err = client.Send(mail.Message{
+						From:    "me@example.com",
+						To:      "you@example.com",
+						Subject: "Urgent! Read now!",
+					}, logger)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				Eventually(func() int {
+					return len(mailServer.Deliveries)
+				}).Should(Equal(2))
+				Expect(mailServer.Deliveries[0].AuthToken).To(Equal("first-oauth-token"))
+				Expect(mailServer.Deliveries[1].AuthToken).To(Equal("refreshed-oauth-token"))
+			})
+
+			It("fails and logs when the file is empty", func() {
+				err := client.Send(mail.Message{
+					From:    "me@example.com",
+					To:      "you@example.com",
+					Subject: "Urgent! Read now!",
+				}, logger)
+				Expect(err).To(MatchError(`XOAUTH2 token file "` + tokenFile + `" is empty`))
+
+				lines, err := parseLogLines(buffer.Bytes())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(lines).To(ContainElement(logLine{
+					Source:   "notifications",
+					Message:  "notifications.smtp.authentication-failed",
+					LogLevel: int(lager.ERROR),
+					Data: map[string]interface{}{
+						"session": "1",
+						"host":    config.Host,
+						"error":   `XOAUTH2 token file "` + tokenFile + `" is empty`,
+					},
+				}))
+			})
+		})
+
 		Context("when configured to not use TLS", func() {
 			BeforeEach(func() {
 				mailServer.SupportsTLS = false
-				config.DisableTLS = true
+				config.TLSMode = mail.TLSModeNone
 				client = mail.NewClient(config)
 			})
 
@@ -425,7 +680,8 @@ var _ = Describe("Mail", func() {
 
 			It("creates a PlainAuth strategy", func() {
 				auth := smtp.PlainAuth("", config.User, config.Pass, config.Host)
-				mechanism := client.AuthMechanism(logger)
+				mechanism, err := client.AuthMechanism(logger)
+				Expect(err).NotTo(HaveOccurred())
 
 				Expect(mechanism).To(BeAssignableToTypeOf(auth))
 			})
@@ -439,12 +695,58 @@ var _ = Describe("Mail", func() {
 
 			It("creates a CRAMMD5Auth strategy", func() {
 				auth := smtp.CRAMMD5Auth(config.User, config.Secret)
-				mechanism := client.AuthMechanism(logger)
+				mechanism, err := client.AuthMechanism(logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mechanism).To(BeAssignableToTypeOf(auth))
+			})
+		})
+
+		Context("when configured to use LOGIN auth", func() {
+			BeforeEach(func() {
+				config.AuthMechanism = mail.AuthLogin
+				client = mail.NewClient(config)
+			})
+
+			It("creates a LoginAuth strategy", func() {
+				auth := mail.LoginAuth(config.User, config.Pass, config.Host)
+				mechanism, err := client.AuthMechanism(logger)
+				Expect(err).NotTo(HaveOccurred())
 
 				Expect(mechanism).To(BeAssignableToTypeOf(auth))
 			})
 		})
 
+		Context("when configured to use XOAUTH2 auth", func() {
+			BeforeEach(func() {
+				config.AuthMechanism = mail.AuthXOAUTH2
+				config.Token = "some-oauth-token"
+				client = mail.NewClient(config)
+			})
+
+			It("creates an XOAUTH2Auth strategy", func() {
+				auth := mail.XOAUTH2Auth(config.User, config.Token)
+				mechanism, err := client.AuthMechanism(logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mechanism).To(BeAssignableToTypeOf(auth))
+			})
+		})
+
+		Context("when configured to use an XOAUTH2 token file that cannot be read", func() {
+			BeforeEach(func() {
+				config.AuthMechanism = mail.AuthXOAUTH2
+				config.TokenFile = "/nonexistent/xoauth2-token"
+				client = mail.NewClient(config)
+			})
+
+			It("returns an error", func() {
+				_, err := client.AuthMechanism(logger)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("could not read XOAUTH2 token file"))
+			})
+		})
+
 		Context("when configured to use no auth", func() {
 			BeforeEach(func() {
 				config.AuthMechanism = mail.AuthNone
@@ -452,7 +754,8 @@ var _ = Describe("Mail", func() {
 			})
 
 			It("creates a CRAMMD5Auth strategy", func() {
-				mechanism := client.AuthMechanism(logger)
+				mechanism, err := client.AuthMechanism(logger)
+				Expect(err).NotTo(HaveOccurred())
 
 				Expect(mechanism).To(BeNil())
 			})
@@ -0,0 +1,172 @@
+package mail
+
+import (
+	"fmt"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+const (
+	MaxHeaders         = 20
+	MaxHeaderValueSize = 998
+)
+
+// reservedHeaders are set by the packager or the relay and cannot be
+// supplied by API clients.
+var reservedHeaders = []string{
+	"Bcc",
+	"Cc",
+	"Content-Transfer-Encoding",
+	"Content-Type",
+	"Date",
+	"Dkim-Signature",
+	"Domainkey-Signature",
+	"From",
+	"In-Reply-To",
+	"List-Unsubscribe",
+	"List-Unsubscribe-Post",
+	"Message-Id",
+	"Mime-Version",
+	"Received",
+	"References",
+	"Reply-To",
+	"Return-Path",
+	"Sender",
+	"Subject",
+	"To",
+	"X-Cf-Client-Id",
+}
+
+type HeaderError struct {
+	Err error
+}
+
+func (e HeaderError) Error() string {
+	return e.Err.Error()
+}
+
+// ValidateHeaders checks custom headers supplied by an API client. Names are
+// compared case-insensitively and errors are reported in name order.
+func ValidateHeaders(headers map[string]string) error {
+	if len(headers) > MaxHeaders {
+		return HeaderError{fmt.Errorf("at most %d headers may be supplied", MaxHeaders)}
+	}
+
+	seen := map[string]bool{}
+	for _, name := range sortedNames(headers) {
+		if !validHeaderName(name) {
+			return HeaderError{fmt.Errorf("header %q is not a valid header name", name)}
+		}
+
+		key := textproto.CanonicalMIMEHeaderKey(name)
+		if seen[key] {
+			return HeaderError{fmt.Errorf("header %q is supplied more than once", name)}
+		}
+		seen[key] = true
+
+		for _, reserved := range reservedHeaders {
+			if key == reserved {
+				return HeaderError{fmt.Errorf("header %q cannot be overridden", name)}
+			}
+		}
+
+		if strings.HasPrefix(key, "X-Cf-Notification-") {
+			return HeaderError{fmt.Errorf("header %q cannot be overridden", name)}
+		}
+
+		value := headers[name]
+		if strings.ContainsAny(value, "\r\n") {
+			return HeaderError{fmt.Errorf("header %q must not contain line breaks", name)}
+		}
+
+		if !validHeaderValue(value) {
+			return HeaderError{fmt.Errorf("header %q must not contain control characters", name)}
+		}
+
+		if len(value) > MaxHeaderValueSize {
+			return HeaderError{fmt.Errorf("header %q exceeds the maximum size of %d bytes", name, MaxHeaderValueSize)}
+		}
+	}
+
+	return nil
+}
+
+// FormatHeaders renders custom headers as "Name: value" lines, sorted by name
+// so that the generated message is stable. Values containing non-ASCII
+// characters are RFC 2047 encoded.
+func FormatHeaders(headers map[string]string) []string {
+	var lines []string
+	for _, name := range sortedNames(headers) {
+		lines = append(lines, fmt.Sprintf("%s: %s", name, encodeHeader(headers[name])))
+	}
+
+	return lines
+}
+
+// CriticalHeaders are added to critical notifications so that mail clients
+// flag them as important. Custom headers with the same names take precedence.
+func CriticalHeaders() map[string]string {
+	return map[string]string{
+		"Importance": "high",
+		"Priority":   "urgent",
+		"X-Priority": "1",
+	}
+}
+
+// MergeHeaders returns the defaults overlaid with the overrides. Names that
+// differ only in case are treated as the same header.
+func MergeHeaders(defaults, overrides map[string]string) map[string]string {
+	if len(defaults) == 0 && len(overrides) == 0 {
+		return nil
+	}
+
+	names := map[string]string{}
+	merged := map[string]string{}
+	for _, headers := range []map[string]string{defaults, overrides} {
+		for name, value := range headers {
+			key := textproto.CanonicalMIMEHeaderKey(name)
+			delete(merged, names[key])
+			names[key] = name
+			merged[name] = value
+		}
+	}
+
+	return merged
+}
+
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, c := range name {
+		if c < '!' || c > '~' || c == ':' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validHeaderValue allows tabs but no other control characters, which mail
+// clients and relays handle inconsistently.
+func validHeaderValue(value string) bool {
+	for _, c := range value {
+		if (c < ' ' && c != '\t') || c == 0x7f {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedNames(headers map[string]string) []string {
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,106 @@
+package mail_test
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Headers", func() {
+	Describe("ValidateHeaders", func() {
+		It("accepts tracking, priority and auto-submitted headers", func() {
+			err := mail.ValidateHeaders(map[string]string{
+				"X-Tracking-ID":  "some-tracking-id",
+				"Importance":     "high",
+				"Priority":       "urgent",
+				"Auto-Submitted": "auto-generated",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects headers that the packager or relay sets", func() {
+			for _, name := range []string{"From", "to", "SUBJECT", "DKIM-Signature", "Message-ID", "X-CF-Notification-Timestamp"} {
+				err := mail.ValidateHeaders(map[string]string{name: "some-value"})
+				Expect(err).To(MatchError(mail.HeaderError{Err: errors.New(`header "` + name + `" cannot be overridden`)}))
+			}
+		})
+
+		It("rejects invalid header names", func() {
+			err := mail.ValidateHeaders(map[string]string{"X-Bad Name": "some-value"})
+			Expect(err).To(MatchError(mail.HeaderError{Err: errors.New(`header "X-Bad Name" is not a valid header name`)}))
+		})
+
+		It("rejects values containing line breaks", func() {
+			err := mail.ValidateHeaders(map[string]string{"X-Injected": "value\r\nBcc: someone@example.com"})
+			Expect(err).To(MatchError(mail.HeaderError{Err: errors.New(`header "X-Injected" must not contain line breaks`)}))
+		})
+
+		It("rejects values containing control characters", func() {
+			for _, value := range []string{"value\x00", "value\x1b[31m", "value\x7f"} {
+				err := mail.ValidateHeaders(map[string]string{"X-Control": value})
+				Expect(err).To(MatchError(mail.HeaderError{Err: errors.New(`header "X-Control" must not contain control characters`)}))
+			}
+		})
+
+		It("accepts values containing tabs and non-ASCII characters", func() {
+			err := mail.ValidateHeaders(map[string]string{"X-Team": "plat\tform", "X-Owner": "Zoë"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects values that are too long", func() {
+			err := mail.ValidateHeaders(map[string]string{"X-Long": strings.Repeat("a", mail.MaxHeaderValueSize+1)})
+			Expect(err).To(MatchError(mail.HeaderError{Err: errors.New(`header "X-Long" exceeds the maximum size of 998 bytes`)}))
+		})
+
+		It("rejects names that are supplied more than once", func() {
+			err := mail.ValidateHeaders(map[string]string{"X-Tag": "one", "x-tag": "two"})
+			Expect(err).To(MatchError(mail.HeaderError{Err: errors.New(`header "x-tag" is supplied more than once`)}))
+		})
+	})
+
+	Describe("FormatHeaders", func() {
+		It("renders the headers sorted by name", func() {
+			Expect(mail.FormatHeaders(map[string]string{
+				"X-Tracking-ID": "some-tracking-id",
+				"Importance":    "high",
+			})).To(Equal([]string{
+				"Importance: high",
+				"X-Tracking-ID: some-tracking-id",
+			}))
+		})
+
+		It("encodes values containing non-ASCII characters", func() {
+			Expect(mail.FormatHeaders(map[string]string{
+				"X-Owner": "Zoë Müller",
+				"X-Team":  "platform",
+			})).To(Equal([]string{
+				"X-Owner: =?utf-8?q?Zo=C3=AB_M=C3=BCller?=",
+				"X-Team: platform",
+			}))
+		})
+	})
+
+	Describe("MergeHeaders", func() {
+		It("overlays the overrides onto the defaults", func() {
+			Expect(mail.MergeHeaders(map[string]string{
+				"Importance": "normal",
+				"X-Team":     "platform",
+			}, map[string]string{
+				"importance":    "high",
+				"X-Tracking-ID": "some-tracking-id",
+			})).To(Equal(map[string]string{
+				"importance":    "high",
+				"X-Team":        "platform",
+				"X-Tracking-ID": "some-tracking-id",
+			}))
+		})
+
+		It("returns nil when there are no headers", func() {
+			Expect(mail.MergeHeaders(nil, map[string]string{})).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,160 @@
+package mail
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+const (
+	punycodeBase        = 36
+	punycodeTMin        = 1
+	punycodeTMax        = 26
+	punycodeSkew        = 38
+	punycodeDamp        = 700
+	punycodeInitialBias = 72
+	punycodeInitialN    = 128
+)
+
+var errPunycodeOverflow = errors.New("domain label is too long to encode")
+
+// labelSeparators are the full stops that IDNA treats as equivalent to ".",
+// as typed by users with CJK input methods.
+var labelSeparators = strings.NewReplacer("。", ".", "．", ".", "｡", ".")
+
+// ToASCII converts an internationalized domain name into its ASCII form,
+// encoding each non-ASCII label with punycode (RFC 3492). ASCII domains are
+// returned unchanged.
+func ToASCII(domain string) (string, error) {
+	if isASCII(domain) {
+		return domain, nil
+	}
+
+	labels := strings.Split(labelSeparators.Replace(domain), ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+
+		encoded, err := encodePunycode(strings.ToLower(label))
+		if err != nil {
+			return "", err
+		}
+
+		labels[i] = "xn--" + encoded
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+func encodePunycode(label string) (string, error) {
+	runes := []rune(label)
+
+	var output []byte
+	for _, r := range runes {
+		if r < 0x80 {
+			output = append(output, byte(r))
+		}
+	}
+
+	basic := len(output)
+	handled := basic
+	if basic > 0 {
+		output = append(output, '-')
+	}
+
+	n := punycodeInitialN
+	delta := 0
+	bias := punycodeInitialBias
+
+	for handled < len(runes) {
+		next := math.MaxInt32
+		for _, r := range runes {
+			if int(r) >= n && int(r) < next {
+				next = int(r)
+			}
+		}
+
+		if next-n > (math.MaxInt32-delta)/(handled+1) {
+			return "", errPunycodeOverflow
+		}
+		delta += (next - n) * (handled + 1)
+		n = next
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+				if delta == math.MaxInt32 {
+					return "", errPunycodeOverflow
+				}
+			}
+
+			if int(r) != n {
+				continue
+			}
+
+			q := delta
+			for k := punycodeBase; ; k += punycodeBase {
+				t := k - bias
+				if t < punycodeTMin {
+					t = punycodeTMin
+				} else if t > punycodeTMax {
+					t = punycodeTMax
+				}
+
+				if q < t {
+					break
+				}
+
+				output = append(output, punycodeDigit(t+(q-t)%(punycodeBase-t)))
+				q = (q - t) / (punycodeBase - t)
+			}
+
+			output = append(output, punycodeDigit(q))
+			bias = punycodeAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+
+		delta++
+		n++
+	}
+
+	return string(output), nil
+}
+
+func punycodeAdapt(delta, points int, first bool) int {
+	if first {
+		delta /= punycodeDamp
+	} else {
+		delta /= 2
+	}
+
+	delta += delta / points
+
+	k := 0
+	for delta > ((punycodeBase-punycodeTMin)*punycodeTMax)/2 {
+		delta /= punycodeBase - punycodeTMin
+		k += punycodeBase
+	}
+
+	return k + (punycodeBase-punycodeTMin+1)*delta/(delta+punycodeSkew)
+}
+
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+
+	return byte('0' + d - 26)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+
+	return true
+}
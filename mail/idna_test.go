@@ -0,0 +1,55 @@
+package mail_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ToASCII", func() {
+	It("returns ASCII domains unchanged", func() {
+		domain, err := mail.ToASCII("example.com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(domain).To(Equal("example.com"))
+	})
+
+	It("encodes each non-ASCII label with punycode", func() {
+		domain, err := mail.ToASCII("Bücher.example")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(domain).To(Equal("xn--bcher-kva.example"))
+
+		domain, err = mail.ToASCII("mail.münchen.de")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(domain).To(Equal("mail.xn--mnchen-3ya.de"))
+
+		domain, err = mail.ToASCII("日本語.jp")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(domain).To(Equal("xn--wgv71a119e.jp"))
+	})
+
+	It("treats ideographic full stops as label separators", func() {
+		domain, err := mail.ToASCII("例え。テスト")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(domain).To(Equal("xn--r8jz45g.xn--zckzah"))
+	})
+})
+
+var _ = Describe("EnvelopeAddress", func() {
+	It("strips display names and converts the domain", func() {
+		address, err := mail.EnvelopeAddress("Jürgen <juergen@bücher.de>", false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(address).To(Equal("juergen@xn--bcher-kva.de"))
+	})
+
+	It("keeps non-ASCII local parts when SMTPUTF8 is available", func() {
+		address, err := mail.EnvelopeAddress("ユーザー@例え.jp", true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(address).To(Equal("ユーザー@xn--r8jz45g.jp"))
+	})
+
+	It("returns an undeliverable error for non-ASCII local parts without SMTPUTF8", func() {
+		_, err := mail.EnvelopeAddress("ユーザー@例え.jp", false)
+		Expect(err).To(BeAssignableToTypeOf(mail.UndeliverableError{}))
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"crypto/rand"
 	"crypto/tls"
+	"encoding/base64"
 	"log"
 	"net"
 	"net/url"
@@ -82,17 +83,24 @@ type SMTPServer struct {
 	Deliveries      []Delivery
 	Listener        *net.TCPListener
 	SupportsTLS     bool
+	ImplicitTLS     bool
 	ConnectWait     time.Duration
 	halt            chan bool
 	ConnectionState string
 	FailsHello      bool
+	MailFromReply   string
+	SupportsUTF8    bool
+	RejectsAuth     bool
 }
 
 type Delivery struct {
-	Recipient string
-	Sender    string
-	Data      []string
-	UsedTLS   bool
+	Recipient  string
+	Sender     string
+	MailParams string
+	Data       []string
+	UsedTLS    bool
+	Auth       string
+	AuthToken  string
 }
 
 func NewSMTPServer(user, pass string) *SMTPServer {
@@ -155,6 +163,11 @@ func (server *SMTPServer) Respond(conn net.Conn) {
 	<-time.After(server.ConnectWait)
 	server.ConnectionState = StateConnected
 
+	if server.ImplicitTLS {
+		conn = server.wrapTLS(conn)
+		server.CurrentDelivery.UsedTLS = true
+	}
+
 	input := bufio.NewReader(conn)
 	output := bufio.NewWriter(conn)
 	server.Broadcast(output)
@@ -168,7 +181,17 @@ Loop:
 		case strings.Contains(msg, "STARTTLS"):
 			conn, input, output = server.RespondToStartTLS(conn, input, output)
 		case strings.Contains(msg, "AUTH PLAIN"):
+			server.CurrentDelivery.Auth = "PLAIN"
 			server.RespondToAuthPlain(output)
+		case strings.Contains(msg, "AUTH LOGIN"):
+			server.CurrentDelivery.Auth = "LOGIN"
+			server.RespondToAuthLogin(input, output)
+		case strings.Contains(msg, "AUTH XOAUTH2"):
+			server.CurrentDelivery.Auth = "XOAUTH2"
+			server.RespondToAuthXOAUTH2(output, msg)
+		case strings.TrimSpace(msg) == "*":
+			output.WriteString("501 5.7.0 Authentication cancelled\r\n")
+			output.Flush()
 		case strings.Contains(msg, "MAIL FROM"):
 			server.RespondToMailFrom(output, msg)
 		case strings.Contains(msg, "RCPT TO"):
@@ -199,7 +222,13 @@ func (server *SMTPServer) RespondToEHLO(output *bufio.Writer) {
 	}
 
 	output.WriteString("250-localhost Hello\n")
-	if server.SupportsTLS {
+	if server.SupportsUTF8 {
+		output.WriteString("250-8BITMIME\n")
+		output.WriteString("250-SMTPUTF8\n")
+	}
+	if server.ImplicitTLS {
+		output.WriteString("250 AUTH PLAIN LOGIN\r\n")
+	} else if server.SupportsTLS {
 		output.WriteString("250-STARTTLS\n")
 		output.WriteString("250 AUTH PLAIN LOGIN\r\n")
 	} else {
@@ -214,15 +243,33 @@ func (server *SMTPServer) RespondToStartTLS(conn net.Conn, input *bufio.Reader,
 
 	server.CurrentDelivery.UsedTLS = true
 
+	tlsConn := server.wrapTLS(conn)
+
+	return tlsConn, bufio.NewReader(tlsConn), bufio.NewWriter(tlsConn)
+}
+
+func (server *SMTPServer) wrapTLS(conn net.Conn) *tls.Conn {
 	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
 	if err != nil {
 		log.Fatalf("server: loadkeys: %s", err)
 	}
 	config := tls.Config{Certificates: []tls.Certificate{cert}}
 	config.Rand = rand.Reader
-	tlsConn := tls.Server(conn, &config)
 
-	return tlsConn, bufio.NewReader(tlsConn), bufio.NewWriter(tlsConn)
+	return tls.Server(conn, &config)
+}
+
+func (server *SMTPServer) RespondToAuthLogin(input *bufio.Reader, output *bufio.Writer) {
+	output.WriteString("334 VXNlcm5hbWU6\r\n")
+	output.Flush()
+	input.ReadString('\n')
+
+	output.WriteString("334 UGFzc3dvcmQ6\r\n")
+	output.Flush()
+	input.ReadString('\n')
+
+	output.WriteString("235 OK, Go ahead\r\n")
+	output.Flush()
 }
 
 func (server *SMTPServer) RespondToAuthPlain(output *bufio.Writer) {
@@ -230,12 +277,40 @@ func (server *SMTPServer) RespondToAuthPlain(output *bufio.Writer) {
 	output.Flush()
 }
 
+func (server *SMTPServer) RespondToAuthXOAUTH2(output *bufio.Writer, msg string) {
+	fields := strings.Fields(msg)
+	response, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+	for _, field := range strings.Split(string(response), "\x01") {
+		if strings.HasPrefix(field, "auth=Bearer ") {
+			server.CurrentDelivery.AuthToken = strings.TrimPrefix(field, "auth=Bearer ")
+		}
+	}
+
+	if server.RejectsAuth {
+		output.WriteString("535 5.7.8 Username and Password not accepted\r\n")
+		output.Flush()
+		return
+	}
+
+	server.RespondToAuthPlain(output)
+}
+
 func (server *SMTPServer) RespondToMailFrom(output *bufio.Writer, msg string) {
 	sender := strings.TrimSpace(msg)
 	sender = strings.TrimPrefix(sender, "MAIL FROM:")
+	if index := strings.Index(sender, ">"); index >= 0 {
+		server.CurrentDelivery.MailParams = strings.TrimSpace(sender[index+1:])
+		sender = sender[:index]
+	}
 	sender = strings.Trim(sender, "<>")
 	server.CurrentDelivery.Sender = sender
 
+	if server.MailFromReply != "" {
+		output.WriteString(server.MailFromReply + "\r\n")
+		output.Flush()
+		return
+	}
+
 	output.WriteString("250 OK\r\n")
 	output.Flush()
 }
@@ -33,7 +33,16 @@ type Message struct {
 	Subject                 string
 	Body                    []Part
 	Headers                 []string
+	Attachments             []Attachment
 	CompiledBody            string
+
+	// Transport names the relay the message is routed through. It is not
+	// rendered into the message.
+	Transport string
+
+	// EnvelopeFrom is the SMTP MAIL FROM address that bounces are returned
+	// to. It is not rendered into the message and defaults to From.
+	EnvelopeFrom string
 }
 
 type Part struct {
@@ -41,6 +50,13 @@ type Part struct {
 	Content     string
 }
 
+type Attachment struct {
+	Filename    string
+	ContentType string
+	ContentID   string
+	Content     []byte
+}
+
 func (msg *Message) Data() string {
 	buf := bytes.NewBuffer([]byte{})
 
@@ -54,7 +70,13 @@ func (msg *Message) Data() string {
 		panic(err)
 	}
 
-	err = tmpl.Execute(buf, msg)
+	encoded := *msg
+	encoded.From = encodeAddressHeader(msg.From)
+	encoded.ReplyTo = encodeAddressHeader(msg.ReplyTo)
+	encoded.To = encodeAddressHeader(msg.To)
+	encoded.Subject = encodeHeader(msg.Subject)
+
+	err = tmpl.Execute(buf, encoded)
 	if err != nil {
 		panic(err)
 	}
@@ -68,6 +90,22 @@ func (msg *Message) CompileBody() error {
 		message.AddAlternative(part.ContentType, part.Content)
 	}
 
+	for _, attachment := range msg.Attachments {
+		if attachment.ContentID != "" {
+			message.Embed(&gomail.File{
+				Name:     attachment.ContentID,
+				MimeType: attachment.ContentType,
+				Content:  attachment.Content,
+			})
+		} else {
+			message.Attach(&gomail.File{
+				Name:     attachment.Filename,
+				MimeType: attachment.ContentType,
+				Content:  attachment.Content,
+			})
+		}
+	}
+
 	m := message.Export()
 	body, err := ioutil.ReadAll(m.Body)
 	if err != nil {
@@ -1,6 +1,7 @@
 package mail_test
 
 import (
+	"encoding/base64"
 	"strings"
 	"time"
 
@@ -118,6 +119,18 @@ var _ = Describe("Message", func() {
 				}))
 			})
 
+			It("encodes non-ASCII display names, domains and subjects", func() {
+				msg.From = "Benachrichtigungen <no-reply@example.com>"
+				msg.To = "Jürgen Müller <juergen@bücher.de>"
+				msg.Subject = "Grüße aus München"
+
+				parts := strings.Split(msg.Data(), "\n")
+
+				Expect(parts).To(ContainElement(`From: "Benachrichtigungen" <no-reply@example.com>`))
+				Expect(parts).To(ContainElement("To: =?utf-8?q?J=C3=BCrgen_M=C3=BCller?= <juergen@xn--bcher-kva.de>"))
+				Expect(parts).To(ContainElement("Subject: =?utf-8?q?Gr=C3=BC=C3=9Fe_aus_M=C3=BCnchen?="))
+			})
+
 			It("includes only the parts necessary", func() {
 				msg.Body = []mail.Part{
 					{
@@ -141,5 +154,46 @@ var _ = Describe("Message", func() {
 				}))
 			})
 		})
+
+		Context("when there are attachments", func() {
+			It("compiles the body as multipart/mixed with the attachments", func() {
+				msg.Attachments = []mail.Attachment{
+					{
+						Filename:    "invoice.pdf",
+						ContentType: "application/pdf",
+						Content:     []byte("some pdf content"),
+					},
+				}
+
+				data := msg.Data()
+
+				Expect(msg.ContentType).To(HavePrefix("multipart/mixed; boundary="))
+				Expect(data).To(ContainSubstring("Content-Type: multipart/alternative; boundary="))
+				Expect(data).To(ContainSubstring(`Content-Type: application/pdf; name="invoice.pdf"`))
+				Expect(data).To(ContainSubstring(`Content-Disposition: attachment; filename="invoice.pdf"`))
+				Expect(data).To(ContainSubstring("Content-Transfer-Encoding: base64"))
+				Expect(data).To(ContainSubstring(base64.StdEncoding.EncodeToString([]byte("some pdf content"))))
+			})
+
+			It("compiles inline images as multipart/related parts referenced by content id", func() {
+				msg.Attachments = []mail.Attachment{
+					{
+						Filename:    "logo.png",
+						ContentType: "image/png",
+						ContentID:   "logo",
+						Content:     []byte("some png content"),
+					},
+				}
+
+				data := msg.Data()
+
+				Expect(msg.ContentType).To(HavePrefix("multipart/related; boundary="))
+				Expect(data).To(ContainSubstring("Content-Type: multipart/alternative; boundary="))
+				Expect(data).To(ContainSubstring(`Content-Type: image/png; name="logo"`))
+				Expect(data).To(ContainSubstring(`Content-Disposition: inline; filename="logo"`))
+				Expect(data).To(ContainSubstring("Content-ID: <logo>"))
+				Expect(data).To(ContainSubstring(base64.StdEncoding.EncodeToString([]byte("some png content"))))
+			})
+		})
 	})
 })
@@ -0,0 +1,137 @@
+package mail
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+)
+
+type clock interface {
+	Now() time.Time
+}
+
+type metricsEmitter interface {
+	Increment(counter string)
+}
+
+type RelayPool struct {
+	relays       []*Client
+	cooldown     time.Duration
+	clock        clock
+	emitter      metricsEmitter
+	active       int
+	failedOverAt time.Time
+}
+
+func NewRelayPool(relays []*Client, cooldown time.Duration, clock clock, emitter metricsEmitter) *RelayPool {
+	return &RelayPool{
+		relays:   relays,
+		cooldown: cooldown,
+		clock:    clock,
+		emitter:  emitter,
+	}
+}
+
+func (p *RelayPool) Connect(logger lager.Logger) error {
+	var err error
+	for _, index := range p.order(logger) {
+		relay := p.relays[index]
+		relayLogger := p.relayLogger(logger, index)
+
+		err = relay.Connect(relayLogger)
+		if err == nil {
+			p.activate(index, logger)
+			return nil
+		}
+
+		relayLogger.Error("relay-connect-failed", err)
+	}
+
+	return err
+}
+
+func (p *RelayPool) Send(msg Message, logger lager.Logger) error {
+	var err error
+	for _, index := range p.order(logger) {
+		relay := p.relays[index]
+		relayLogger := p.relayLogger(logger, index)
+
+		relayLogger.Info("relay-attempt")
+		p.emitter.Increment(fmt.Sprintf("notifications.smtp.relay.%d.attempt", index))
+
+		err = relay.Connect(relayLogger)
+		if err != nil {
+			relayLogger.Error("relay-connect-failed", err)
+			p.emitter.Increment(fmt.Sprintf("notifications.smtp.relay.%d.failure", index))
+			continue
+		}
+
+		err = relay.Send(msg, relayLogger)
+		if err == nil {
+			p.activate(index, logger)
+			return nil
+		}
+
+		relayLogger.Error("relay-failed", err)
+		p.emitter.Increment(fmt.Sprintf("notifications.smtp.relay.%d.failure", index))
+
+		if !IsTransient(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (p *RelayPool) order(logger lager.Logger) []int {
+	if p.active != 0 && p.clock.Now().Sub(p.failedOverAt) >= p.cooldown {
+		logger.Info("relay-returning-to-primary", lager.Data{"cooldown": p.cooldown.String()})
+		p.active = 0
+	}
+
+	var indexes []int
+	for i := range p.relays {
+		indexes = append(indexes, (p.active+i)%len(p.relays))
+	}
+
+	return indexes
+}
+
+func (p *RelayPool) activate(index int, logger lager.Logger) {
+	if index == p.active {
+		return
+	}
+
+	if index != 0 {
+		logger.Info("relay-failover", lager.Data{"from": p.relays[p.active].address(), "to": p.relays[index].address()})
+		p.emitter.Increment("notifications.smtp.failover")
+		p.failedOverAt = p.clock.Now()
+	}
+
+	p.active = index
+}
+
+func (p *RelayPool) relayLogger(logger lager.Logger, index int) lager.Logger {
+	return logger.Session("relay", lager.Data{
+		"relay":       p.relays[index].address(),
+		"relay_index": index,
+	})
+}
+
+// IsTransient reports whether an error returned while talking to a relay
+// is worth retrying against the next one: dropped connections, timeouts
+// and 4xx replies.
+func IsTransient(err error) bool {
+	switch e := err.(type) {
+	case *textproto.Error:
+		return e.Code >= 400 && e.Code < 500
+	case net.Error:
+		return true
+	}
+
+	return err == io.EOF || err == io.ErrUnexpectedEOF || err == errServerTimeout
+}
@@ -0,0 +1,186 @@
+package mail_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/textproto"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RelayPool", func() {
+	var (
+		primaryServer   *SMTPServer
+		secondaryServer *SMTPServer
+		pool            *mail.RelayPool
+		clock           *mocks.Clock
+		emitter         *mocks.MetricsEmitter
+		logger          lager.Logger
+		buffer          *bytes.Buffer
+		msg             mail.Message
+	)
+
+	newClient := func(server *SMTPServer) *mail.Client {
+		host, port, err := net.SplitHostPort(server.URL.String())
+		Expect(err).NotTo(HaveOccurred())
+
+		return mail.NewClient(mail.Config{
+			Host:          host,
+			Port:          port,
+			User:          "user",
+			Pass:          "pass",
+			SkipVerifySSL: true,
+			TLSMode:       mail.TLSModeNone,
+		})
+	}
+
+	BeforeEach(func() {
+		buffer = bytes.NewBuffer([]byte{})
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+
+		primaryServer = NewSMTPServer("user", "pass")
+		secondaryServer = NewSMTPServer("user", "pass")
+
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Now()
+		emitter = mocks.NewMetricsEmitter()
+
+		pool = mail.NewRelayPool([]*mail.Client{
+			newClient(primaryServer),
+			newClient(secondaryServer),
+		}, 1*time.Minute, clock, emitter)
+
+		msg = mail.Message{
+			From:    "me@example.com",
+			To:      "you@example.com",
+			Subject: "Urgent! Read now!",
+			Body: []mail.Part{
+				{
+					ContentType: "text/plain",
+					Content:     "This email is the most important thing you will read all day!",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		primaryServer.Close()
+		secondaryServer.Close()
+	})
+
+	It("sends through the primary relay when it is healthy", func() {
+		err := pool.Send(msg, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() int {
+			return len(primaryServer.Deliveries)
+		}).Should(Equal(1))
+		Expect(primaryServer.Deliveries[0].Recipient).To(Equal("you@example.com"))
+		Expect(secondaryServer.Deliveries).To(BeEmpty())
+
+		Expect(emitter.IncrementCall.Receives.Counters).To(Equal([]string{
+			"notifications.smtp.relay.0.attempt",
+		}))
+	})
+
+	It("fails over to the next relay when the primary returns a transient reply", func() {
+		primaryServer.MailFromReply = "421 Service not available"
+
+		err := pool.Send(msg, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() int {
+			return len(secondaryServer.Deliveries)
+		}).Should(Equal(1))
+		Expect(secondaryServer.Deliveries[0].Recipient).To(Equal("you@example.com"))
+
+		Expect(emitter.IncrementCall.Receives.Counters).To(Equal([]string{
+			"notifications.smtp.relay.0.attempt",
+			"notifications.smtp.relay.0.failure",
+			"notifications.smtp.relay.1.attempt",
+			"notifications.smtp.failover",
+		}))
+		Expect(buffer.String()).To(ContainSubstring(`"relay":"` + secondaryServer.URL.String() + `"`))
+	})
+
+	It("fails over when the primary cannot be reached", func() {
+		primaryServer.Close()
+		primaryServer = NewSMTPServer("user", "pass")
+
+		err := pool.Send(msg, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(func() int {
+			return len(secondaryServer.Deliveries)
+		}).Should(Equal(1))
+		Expect(emitter.IncrementCall.Receives.Counters).To(ContainElement("notifications.smtp.relay.0.failure"))
+	})
+
+	It("does not fail over on a permanent reply", func() {
+		primaryServer.MailFromReply = "550 Mailbox unavailable"
+
+		err := pool.Send(msg, logger)
+		Expect(err).To(MatchError(&textproto.Error{Code: 550, Msg: "Mailbox unavailable"}))
+
+		Expect(secondaryServer.Deliveries).To(BeEmpty())
+		Expect(emitter.IncrementCall.Receives.Counters).NotTo(ContainElement("notifications.smtp.relay.1.attempt"))
+	})
+
+	It("returns the last error when every relay fails", func() {
+		primaryServer.MailFromReply = "421 Service not available"
+		secondaryServer.MailFromReply = "451 Try again later"
+
+		err := pool.Send(msg, logger)
+		Expect(err).To(MatchError(&textproto.Error{Code: 451, Msg: "Try again later"}))
+	})
+
+	It("stays on the secondary relay until the cool-down has elapsed", func() {
+		primaryServer.MailFromReply = "421 Service not available"
+
+		Expect(pool.Send(msg, logger)).To(Succeed())
+		Eventually(func() int {
+			return len(secondaryServer.Deliveries)
+		}).Should(Equal(1))
+
+		primaryServer.MailFromReply = ""
+		clock.NowCall.Returns.Time = clock.NowCall.Returns.Time.Add(30 * time.Second)
+
+		Expect(pool.Send(msg, logger)).To(Succeed())
+		Eventually(func() int {
+			return len(secondaryServer.Deliveries)
+		}).Should(Equal(2))
+
+		clock.NowCall.Returns.Time = clock.NowCall.Returns.Time.Add(31 * time.Second)
+
+		Expect(pool.Send(msg, logger)).To(Succeed())
+		Eventually(func() int {
+			var delivered int
+			for _, delivery := range primaryServer.Deliveries {
+				if len(delivery.Data) > 0 {
+					delivered++
+				}
+			}
+			return delivered
+		}).Should(Equal(1))
+		Expect(buffer.String()).To(ContainSubstring("relay-returning-to-primary"))
+	})
+
+	Describe("IsTransient", func() {
+		It("treats connection failures and 4xx replies as transient", func() {
+			Expect(mail.IsTransient(io.EOF)).To(BeTrue())
+			Expect(mail.IsTransient(&net.OpError{Op: "dial", Err: errors.New("connection refused")})).To(BeTrue())
+			Expect(mail.IsTransient(&textproto.Error{Code: 421})).To(BeTrue())
+			Expect(mail.IsTransient(&textproto.Error{Code: 554})).To(BeFalse())
+			Expect(mail.IsTransient(errors.New("banana"))).To(BeFalse())
+		})
+	})
+})
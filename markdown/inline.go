@@ -0,0 +1,375 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	autolink       = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.-]{1,31}:[^<>\s]*)>`)
+	emailAutolink  = regexp.MustCompile(`^<([^\s<>@]+@[^\s<>@]+\.[^\s<>@]+)>`)
+	entity         = regexp.MustCompile(`^&(?:[a-zA-Z][a-zA-Z0-9]{1,31}|#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6});`)
+	linkTitle      = regexp.MustCompile(`^\s+(?:"((?:[^"\\]|\\.)*)"|'((?:[^'\\]|\\.)*)'|\(((?:[^()\\]|\\.)*)\))`)
+	urlScheme      = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*):`)
+	escapable      = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+	specialInlines = "\\`<![*_&\n"
+)
+
+func parseInlines(source string) []*node {
+	var (
+		nodes []*node
+		text  []byte
+	)
+
+	flush := func() {
+		if len(text) > 0 {
+			nodes = append(nodes, &node{kind: textNode, literal: string(text)})
+			text = nil
+		}
+	}
+
+	for i := 0; i < len(source); {
+		c := source[i]
+
+		switch c {
+		case '\\':
+			if i+1 < len(source) && source[i+1] == '\n' {
+				flush()
+				nodes = append(nodes, &node{kind: lineBreakNode})
+				i += 2
+				continue
+			}
+
+			if i+1 < len(source) && strings.IndexByte(escapable, source[i+1]) >= 0 {
+				text = append(text, source[i+1])
+				i += 2
+				continue
+			}
+
+		case '`':
+			if code, consumed := parseCodeSpan(source[i:]); consumed > 0 {
+				flush()
+				nodes = append(nodes, code)
+				i += consumed
+				continue
+			}
+
+			run := runLength(source[i:], '`')
+			text = append(text, source[i:i+run]...)
+			i += run
+			continue
+
+		case '<':
+			if match := autolink.FindStringSubmatch(source[i:]); match != nil {
+				flush()
+				nodes = append(nodes, &node{kind: linkNode, destination: match[1], children: []*node{{kind: textNode, literal: match[1]}}})
+				i += len(match[0])
+				continue
+			}
+
+			if match := emailAutolink.FindStringSubmatch(source[i:]); match != nil {
+				flush()
+				nodes = append(nodes, &node{kind: linkNode, destination: "mailto:" + match[1], children: []*node{{kind: textNode, literal: match[1]}}})
+				i += len(match[0])
+				continue
+			}
+
+		case '!':
+			if i+1 < len(source) && source[i+1] == '[' {
+				if image, consumed := parseLink(source[i+1:], imageNode); consumed > 0 {
+					flush()
+					nodes = append(nodes, image)
+					i += consumed + 1
+					continue
+				}
+			}
+
+		case '[':
+			if link, consumed := parseLink(source[i:], linkNode); consumed > 0 {
+				flush()
+				nodes = append(nodes, link)
+				i += consumed
+				continue
+			}
+
+		case '*', '_':
+			if emphasis, consumed := parseEmphasis(source, i); consumed > 0 {
+				flush()
+				nodes = append(nodes, emphasis)
+				i += consumed
+				continue
+			}
+
+			run := runLength(source[i:], c)
+			text = append(text, source[i:i+run]...)
+			i += run
+			continue
+
+		case '&':
+			if match := entity.FindString(source[i:]); match != "" {
+				text = append(text, html.UnescapeString(match)...)
+				i += len(match)
+				continue
+			}
+
+		case '\n':
+			trimmed := strings.TrimRight(string(text), " ")
+			hardBreak := len(text)-len(trimmed) >= 2
+			text = []byte(trimmed)
+			flush()
+
+			if hardBreak {
+				nodes = append(nodes, &node{kind: lineBreakNode})
+			} else {
+				nodes = append(nodes, &node{kind: softBreakNode})
+			}
+
+			i++
+			for i < len(source) && source[i] == ' ' {
+				i++
+			}
+			continue
+		}
+
+		text = append(text, c)
+		i++
+		for i < len(source) && strings.IndexByte(specialInlines, source[i]) < 0 {
+			text = append(text, source[i])
+			i++
+		}
+	}
+
+	flush()
+	return nodes
+}
+
+func parseCodeSpan(source string) (*node, int) {
+	run := runLength(source, '`')
+
+	for i := run; i < len(source); {
+		if source[i] != '`' {
+			i++
+			continue
+		}
+
+		closing := runLength(source[i:], '`')
+		if closing == run {
+			content := strings.Replace(source[run:i], "\n", " ", -1)
+			if len(content) > 2 && content[0] == ' ' && content[len(content)-1] == ' ' && strings.Trim(content, " ") != "" {
+				content = content[1 : len(content)-1]
+			}
+
+			return &node{kind: codeNode, literal: content}, i + closing
+		}
+
+		i += closing
+	}
+
+	return nil, 0
+}
+
+func parseLink(source string, linkKind kind) (*node, int) {
+	end := closingBracket(source)
+	if end < 0 || end+1 >= len(source) || source[end+1] != '(' {
+		return nil, 0
+	}
+
+	rest := source[end+2:]
+	trimmed := strings.TrimLeft(rest, " \n")
+	offset := len(rest) - len(trimmed)
+
+	destination, consumed := parseDestination(trimmed)
+	if consumed < 0 {
+		return nil, 0
+	}
+	offset += consumed
+	rest = rest[offset:]
+
+	var title string
+	if match := linkTitle.FindStringSubmatch(rest); match != nil {
+		title = unescape(match[1] + match[2] + match[3])
+		offset += len(match[0])
+		rest = rest[len(match[0]):]
+	}
+
+	trimmed = strings.TrimLeft(rest, " \n")
+	if !strings.HasPrefix(trimmed, ")") {
+		return nil, 0
+	}
+	offset += len(rest) - len(trimmed) + 1
+
+	return &node{
+		kind:        linkKind,
+		destination: destination,
+		title:       title,
+		children:    parseInlines(source[1:end]),
+	}, end + 2 + offset
+}
+
+func closingBracket(source string) int {
+	depth := 0
+	for i := 0; i < len(source); i++ {
+		switch source[i] {
+		case '\\':
+			i++
+		case '`':
+			if _, consumed := parseCodeSpan(source[i:]); consumed > 0 {
+				i += consumed - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func parseDestination(source string) (string, int) {
+	if strings.HasPrefix(source, "<") {
+		end := strings.IndexAny(source[1:], ">\n")
+		if end < 0 || source[end+1] != '>' {
+			return "", -1
+		}
+
+		return unescape(source[1 : end+1]), end + 2
+	}
+
+	depth := 0
+	for i := 0; i < len(source); i++ {
+		switch c := source[i]; {
+		case c == '\\' && i+1 < len(source):
+			i++
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				return unescape(source[:i]), i
+			}
+			depth--
+		case c == ' ' || c == '\n' || c < 0x20:
+			if depth > 0 {
+				return "", -1
+			}
+			return unescape(source[:i]), i
+		}
+	}
+
+	return "", -1
+}
+
+func parseEmphasis(source string, start int) (*node, int) {
+	c := source[start]
+	run := runLength(source[start:], c)
+	if c == '_' && start > 0 && isWordCharacter(source[start-1]) {
+		return nil, 0
+	}
+
+	for _, width := range []int{2, 1} {
+		if run < width {
+			continue
+		}
+
+		contentStart := start + width
+		if contentStart >= len(source) || isWhitespace(source[contentStart]) {
+			continue
+		}
+
+		end := closingDelimiter(source, contentStart, c, width)
+		if end < 0 {
+			continue
+		}
+
+		emphasisKind := emphasisNode
+		if width == 2 {
+			emphasisKind = strongNode
+		}
+
+		return &node{
+			kind:     emphasisKind,
+			children: parseInlines(source[contentStart:end]),
+		}, end + width - start
+	}
+
+	return nil, 0
+}
+
+func closingDelimiter(source string, from int, c byte, width int) int {
+	for i := from; i < len(source); {
+		switch source[i] {
+		case '\\':
+			i += 2
+			continue
+		case '`':
+			if _, consumed := parseCodeSpan(source[i:]); consumed > 0 {
+				i += consumed
+				continue
+			}
+		case c:
+			run := runLength(source[i:], c)
+			if run == width || (run > width && run != width*2 && width == 1 && run%2 == 1) {
+				closer := i + run - width
+				if i > from && !isWhitespace(source[i-1]) && (c != '_' || closer+width >= len(source) || !isWordCharacter(source[closer+width])) {
+					return closer
+				}
+			}
+			i += run
+			continue
+		}
+		i++
+	}
+
+	return -1
+}
+
+func runLength(source string, c byte) int {
+	n := 0
+	for n < len(source) && source[n] == c {
+		n++
+	}
+
+	return n
+}
+
+func unescape(source string) string {
+	var unescaped []byte
+	for i := 0; i < len(source); i++ {
+		if source[i] == '\\' && i+1 < len(source) && strings.IndexByte(escapable, source[i+1]) >= 0 {
+			i++
+		}
+		unescaped = append(unescaped, source[i])
+	}
+
+	return html.UnescapeString(string(unescaped))
+}
+
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\n'
+}
+
+func isWordCharacter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// safeURL reports whether a link may be rendered. Addresses without a scheme
+// are kept, so that relative and anchor links still work.
+func safeURL(destination string, schemes ...string) bool {
+	match := urlScheme.FindStringSubmatch(destination)
+	if match == nil {
+		return true
+	}
+
+	scheme := strings.ToLower(match[1])
+	for _, allowed := range schemes {
+		if scheme == allowed {
+			return true
+		}
+	}
+
+	return false
+}
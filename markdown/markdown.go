@@ -0,0 +1,394 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type kind int
+
+const (
+	documentNode kind = iota
+	paragraphNode
+	headingNode
+	thematicBreakNode
+	codeBlockNode
+	blockQuoteNode
+	listNode
+	listItemNode
+	textNode
+	emphasisNode
+	strongNode
+	codeNode
+	linkNode
+	imageNode
+	lineBreakNode
+	softBreakNode
+)
+
+type node struct {
+	kind     kind
+	children []*node
+	literal  string
+
+	level   int
+	ordered bool
+	start   int
+	tight   bool
+
+	destination string
+	title       string
+}
+
+var (
+	atxHeading    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))??(?:[ \t]+#+)?[ \t]*$`)
+	thematicBreak = regexp.MustCompile(`^ {0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	setextH1      = regexp.MustCompile(`^ {0,3}=+[ \t]*$`)
+	setextH2      = regexp.MustCompile(`^ {0,3}-+[ \t]*$`)
+	openingFence  = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})[ \t]*([^`]*)$")
+	blockQuote    = regexp.MustCompile(`^ {0,3}> ?`)
+	bulletMarker  = regexp.MustCompile(`^( {0,3})([-+*])( +|$)`)
+	orderedMarker = regexp.MustCompile(`^( {0,3})([0-9]{1,9})([.)])( +|$)`)
+)
+
+// Render converts Markdown into an HTML fragment and a plain-text rendering
+// of the same content. Raw HTML in the source is escaped rather than passed
+// through, and links are only kept for web and mailto addresses.
+func Render(source string) (string, string) {
+	document := parse(source)
+	return renderHTML(document), renderText(document)
+}
+
+func parse(source string) *node {
+	source = strings.Replace(source, "\r\n", "\n", -1)
+	source = strings.Replace(source, "\r", "\n", -1)
+	source = strings.Replace(source, "\t", "    ", -1)
+
+	return &node{
+		kind:     documentNode,
+		children: parseBlocks(strings.Split(source, "\n")),
+	}
+}
+
+func parseBlocks(lines []string) []*node {
+	var blocks []*node
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case isBlank(line):
+			i++
+
+		case openingFence.MatchString(line):
+			block, consumed := parseFencedCode(lines[i:])
+			blocks = append(blocks, block)
+			i += consumed
+
+		case atxHeading.MatchString(line):
+			match := atxHeading.FindStringSubmatch(line)
+			blocks = append(blocks, &node{
+				kind:     headingNode,
+				level:    len(match[1]),
+				children: parseInlines(strings.TrimSpace(match[2])),
+			})
+			i++
+
+		case thematicBreak.MatchString(line):
+			blocks = append(blocks, &node{kind: thematicBreakNode})
+			i++
+
+		case blockQuote.MatchString(line):
+			block, consumed := parseBlockQuote(lines[i:])
+			blocks = append(blocks, block)
+			i += consumed
+
+		case isListItem(line):
+			block, consumed := parseList(lines[i:])
+			blocks = append(blocks, block)
+			i += consumed
+
+		case indentation(line) >= 4:
+			block, consumed := parseIndentedCode(lines[i:])
+			blocks = append(blocks, block)
+			i += consumed
+
+		default:
+			block, consumed := parseParagraph(lines[i:])
+			blocks = append(blocks, block)
+			i += consumed
+		}
+	}
+
+	return blocks
+}
+
+func parseFencedCode(lines []string) (*node, int) {
+	match := openingFence.FindStringSubmatch(lines[0])
+	indent, fence := len(match[1]), match[2]
+
+	var content []string
+	i := 1
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if indentation(line) < 4 && strings.HasPrefix(trimmed, fence[:1]) && len(trimmed) >= len(fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			i++
+			break
+		}
+
+		content = append(content, stripIndentation(line, indent))
+	}
+
+	return &node{
+		kind:    codeBlockNode,
+		literal: joinLines(content),
+	}, i
+}
+
+func parseIndentedCode(lines []string) (*node, int) {
+	var content []string
+
+	i := 0
+	for ; i < len(lines); i++ {
+		if !isBlank(lines[i]) && indentation(lines[i]) < 4 {
+			break
+		}
+
+		content = append(content, stripIndentation(lines[i], 4))
+	}
+
+	for len(content) > 0 && isBlank(content[len(content)-1]) {
+		content = content[:len(content)-1]
+	}
+
+	return &node{
+		kind:    codeBlockNode,
+		literal: joinLines(content),
+	}, i
+}
+
+func parseBlockQuote(lines []string) (*node, int) {
+	var content []string
+
+	i := 0
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if blockQuote.MatchString(line) {
+			content = append(content, blockQuote.ReplaceAllString(line, ""))
+			continue
+		}
+
+		if isBlank(line) || i == 0 || isBlank(content[len(content)-1]) || interruptsParagraph(line) {
+			break
+		}
+
+		content = append(content, line)
+	}
+
+	return &node{
+		kind:     blockQuoteNode,
+		children: parseBlocks(content),
+	}, i
+}
+
+func parseParagraph(lines []string) (*node, int) {
+	content := []string{strings.TrimLeft(lines[0], " ")}
+
+	i := 1
+	for ; i < len(lines); i++ {
+		line := lines[i]
+
+		if setextH1.MatchString(line) || setextH2.MatchString(line) {
+			level := 1
+			if setextH2.MatchString(line) {
+				level = 2
+			}
+
+			return &node{
+				kind:     headingNode,
+				level:    level,
+				children: parseInlines(strings.Join(content, "\n")),
+			}, i + 1
+		}
+
+		if isBlank(line) || interruptsParagraph(line) {
+			break
+		}
+
+		content = append(content, strings.TrimLeft(line, " "))
+	}
+
+	return &node{
+		kind:     paragraphNode,
+		children: parseInlines(strings.TrimRight(strings.Join(content, "\n"), " ")),
+	}, i
+}
+
+type listMarker struct {
+	ordered   bool
+	start     int
+	delimiter string
+	indent    int
+}
+
+func parseListMarker(line string) (listMarker, bool) {
+	if match := bulletMarker.FindStringSubmatch(line); match != nil {
+		return listMarker{
+			delimiter: match[2],
+			indent:    contentIndent(len(match[1])+1, match[3]),
+		}, true
+	}
+
+	if match := orderedMarker.FindStringSubmatch(line); match != nil {
+		start, _ := strconv.Atoi(match[2])
+		return listMarker{
+			ordered:   true,
+			start:     start,
+			delimiter: match[3],
+			indent:    contentIndent(len(match[1])+len(match[2])+1, match[4]),
+		}, true
+	}
+
+	return listMarker{}, false
+}
+
+func contentIndent(markerWidth int, spacing string) int {
+	if len(spacing) == 0 || len(spacing) > 4 {
+		return markerWidth + 1
+	}
+
+	return markerWidth + len(spacing)
+}
+
+func isListItem(line string) bool {
+	if thematicBreak.MatchString(line) {
+		return false
+	}
+
+	_, ok := parseListMarker(line)
+	return ok
+}
+
+func parseList(lines []string) (*node, int) {
+	first, _ := parseListMarker(lines[0])
+	list := &node{
+		kind:    listNode,
+		ordered: first.ordered,
+		start:   first.start,
+		tight:   true,
+	}
+
+	i := 0
+	for i < len(lines) {
+		marker, ok := parseListMarker(lines[i])
+		if !ok || thematicBreak.MatchString(lines[i]) || marker.ordered != first.ordered || marker.delimiter != first.delimiter {
+			break
+		}
+
+		content := []string{markerContent(lines[i], marker)}
+		i++
+
+		for i < len(lines) {
+			line := lines[i]
+
+			if isBlank(line) {
+				content = append(content, "")
+				i++
+				continue
+			}
+
+			if indentation(line) >= marker.indent {
+				content = append(content, stripIndentation(line, marker.indent))
+				i++
+				continue
+			}
+
+			if isBlank(content[len(content)-1]) || interruptsParagraph(line) || isListItem(line) {
+				break
+			}
+
+			content = append(content, strings.TrimLeft(line, " "))
+			i++
+		}
+
+		trailing := 0
+		for len(content) > 1 && isBlank(content[len(content)-1]) {
+			content = content[:len(content)-1]
+			trailing++
+		}
+
+		for _, line := range content {
+			if isBlank(line) {
+				list.tight = false
+			}
+		}
+
+		list.children = append(list.children, &node{
+			kind:     listItemNode,
+			children: parseBlocks(content),
+		})
+
+		if trailing > 0 {
+			if next, ok := parseListMarker(lineAt(lines, i)); ok && next.ordered == first.ordered && next.delimiter == first.delimiter {
+				list.tight = false
+				continue
+			}
+
+			i -= trailing
+			break
+		}
+	}
+
+	return list, i
+}
+
+func interruptsParagraph(line string) bool {
+	if atxHeading.MatchString(line) || thematicBreak.MatchString(line) || openingFence.MatchString(line) || blockQuote.MatchString(line) {
+		return true
+	}
+
+	marker, ok := parseListMarker(line)
+	return ok && (!marker.ordered || marker.start == 1) && !isBlank(markerContent(line, marker))
+}
+
+func markerContent(line string, marker listMarker) string {
+	if len(line) <= marker.indent {
+		return ""
+	}
+
+	return line[marker.indent:]
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func stripIndentation(line string, width int) string {
+	if indentation(line) < width {
+		return strings.TrimLeft(line, " ")
+	}
+
+	return line[width:]
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+
+	return ""
+}
+
+func joinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,98 @@
+package markdown_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/markdown"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Render", func() {
+	render := func(source string) (string, string) {
+		return markdown.Render(source)
+	}
+
+	It("renders paragraphs and headings", func() {
+		html, text := render("# Welcome\n\nHello there,\nfriend.\n\nSecond paragraph\n---------------")
+
+		Expect(html).To(Equal("<h1>Welcome</h1>\n<p>Hello there,\nfriend.</p>\n<h2>Second paragraph</h2>\n"))
+		Expect(text).To(Equal("Welcome\n=======\n\nHello there,\nfriend.\n\nSecond paragraph\n----------------\n"))
+	})
+
+	It("renders emphasis, code spans and line breaks", func() {
+		html, text := render("some *emphasis*, __strong__ and `<code>`  \nnext line\\\nlast")
+
+		Expect(html).To(Equal("<p>some <em>emphasis</em>, <strong>strong</strong> and <code>&lt;code&gt;</code><br>\nnext line<br>\nlast</p>\n"))
+		Expect(text).To(Equal("some emphasis, strong and <code>\nnext line\nlast\n"))
+	})
+
+	It("leaves intraword underscores and unmatched delimiters alone", func() {
+		html, _ := render("snake_case_name and 2 * 3 * 4")
+
+		Expect(html).To(Equal("<p>snake_case_name and 2 * 3 * 4</p>\n"))
+	})
+
+	It("renders links and images", func() {
+		html, text := render(`[the docs](https://example.com/docs "Docs") and <https://example.com> and ![logo](https://example.com/logo.png)`)
+
+		Expect(html).To(Equal(`<p><a href="https://example.com/docs" title="Docs">the docs</a> and <a href="https://example.com">https://example.com</a> and <img src="https://example.com/logo.png" alt="logo"></p>` + "\n"))
+		Expect(text).To(Equal("the docs (https://example.com/docs) and https://example.com and logo (https://example.com/logo.png)\n"))
+	})
+
+	It("renders email autolinks", func() {
+		html, text := render("write to <help@example.com>")
+
+		Expect(html).To(Equal(`<p>write to <a href="mailto:help@example.com">help@example.com</a></p>` + "\n"))
+		Expect(text).To(Equal("write to help@example.com\n"))
+	})
+
+	It("renders lists", func() {
+		html, text := render("- one\n- two\n  1. nested\n  2. items\n- three")
+
+		Expect(html).To(Equal("<ul>\n<li>one</li>\n<li>two\n<ol>\n<li>nested</li>\n<li>items</li>\n</ol>\n</li>\n<li>three</li>\n</ul>\n"))
+		Expect(text).To(Equal("- one\n- two\n  1. nested\n  2. items\n- three\n"))
+	})
+
+	It("renders loose and numbered lists", func() {
+		html, text := render("3. first\n\n4. second")
+
+		Expect(html).To(Equal("<ol start=\"3\">\n<li>\n<p>first</p>\n</li>\n<li>\n<p>second</p>\n</li>\n</ol>\n"))
+		Expect(text).To(Equal("3. first\n\n4. second\n"))
+	})
+
+	It("renders code blocks, block quotes and rules", func() {
+		html, text := render("```go\nif a < b {\n}\n```\n\n    indented\n\n> quoted\n> text\n\n***")
+
+		Expect(html).To(Equal("<pre><code>if a &lt; b {\n}\n</code></pre>\n<pre><code>indented\n</code></pre>\n<blockquote>\n<p>quoted\ntext</p>\n</blockquote>\n<hr>\n"))
+		Expect(text).To(Equal("    if a < b {\n    }\n\n    indented\n\n> quoted\n> text\n\n----------\n"))
+	})
+
+	It("decodes entities and backslash escapes", func() {
+		html, text := render(`AT&amp;T \*not emphasis\* &copy;`)
+
+		Expect(html).To(Equal("<p>AT&amp;T *not emphasis* ©</p>\n"))
+		Expect(text).To(Equal("AT&T *not emphasis* ©\n"))
+	})
+
+	Context("sanitization", func() {
+		It("escapes raw HTML", func() {
+			html, text := render(`<script>alert("hi")</script> <b onclick="x">bold</b>`)
+
+			Expect(html).To(Equal(`<p>&lt;script&gt;alert(&quot;hi&quot;)&lt;/script&gt; &lt;b onclick=&quot;x&quot;&gt;bold&lt;/b&gt;</p>` + "\n"))
+			Expect(text).To(Equal(`<script>alert("hi")</script> <b onclick="x">bold</b>` + "\n"))
+		})
+
+		It("drops links with unsafe schemes", func() {
+			html, text := render(`[click](javascript:alert(1)) [data](DATA:text/html,hi) ![img](javascript:x)`)
+
+			Expect(html).To(Equal("<p>click data img</p>\n"))
+			Expect(text).To(Equal("click data img\n"))
+		})
+
+		It("escapes attribute values", func() {
+			html, _ := render(`[x](https://example.com/"onmouseover="y)`)
+
+			Expect(html).To(Equal(`<p><a href="https://example.com/&quot;onmouseover=&quot;y">x</a></p>` + "\n"))
+		})
+	})
+})
@@ -0,0 +1,288 @@
+package markdown
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func renderHTML(document *node) string {
+	var buffer bytes.Buffer
+	for _, block := range document.children {
+		writeHTMLBlock(&buffer, block, false)
+	}
+
+	return buffer.String()
+}
+
+func writeHTMLBlock(buffer *bytes.Buffer, block *node, tight bool) {
+	switch block.kind {
+	case paragraphNode:
+		if tight {
+			writeHTMLInlines(buffer, block.children)
+			return
+		}
+
+		buffer.WriteString("<p>")
+		writeHTMLInlines(buffer, block.children)
+		buffer.WriteString("</p>\n")
+
+	case headingNode:
+		level := strconv.Itoa(block.level)
+		buffer.WriteString("<h" + level + ">")
+		writeHTMLInlines(buffer, block.children)
+		buffer.WriteString("</h" + level + ">\n")
+
+	case thematicBreakNode:
+		buffer.WriteString("<hr>\n")
+
+	case codeBlockNode:
+		buffer.WriteString("<pre><code>")
+		buffer.WriteString(htmlEscaper.Replace(block.literal))
+		buffer.WriteString("</code></pre>\n")
+
+	case blockQuoteNode:
+		buffer.WriteString("<blockquote>\n")
+		for _, child := range block.children {
+			writeHTMLBlock(buffer, child, false)
+		}
+		buffer.WriteString("</blockquote>\n")
+
+	case listNode:
+		tag := "ul"
+		if block.ordered {
+			tag = "ol"
+		}
+
+		buffer.WriteString("<" + tag)
+		if block.ordered && block.start != 1 {
+			buffer.WriteString(` start="` + strconv.Itoa(block.start) + `"`)
+		}
+		buffer.WriteString(">\n")
+
+		for _, item := range block.children {
+			buffer.WriteString("<li>")
+			previousInline := true
+			for i, child := range item.children {
+				inline := block.tight && child.kind == paragraphNode
+				if (i == 0 && !inline) || (i > 0 && previousInline) {
+					buffer.WriteString("\n")
+				}
+
+				writeHTMLBlock(buffer, child, block.tight)
+				previousInline = inline
+			}
+			buffer.WriteString("</li>\n")
+		}
+
+		buffer.WriteString("</" + tag + ">\n")
+	}
+}
+
+func writeHTMLInlines(buffer *bytes.Buffer, inlines []*node) {
+	for _, inline := range inlines {
+		switch inline.kind {
+		case textNode:
+			buffer.WriteString(htmlEscaper.Replace(inline.literal))
+
+		case codeNode:
+			buffer.WriteString("<code>" + htmlEscaper.Replace(inline.literal) + "</code>")
+
+		case emphasisNode:
+			buffer.WriteString("<em>")
+			writeHTMLInlines(buffer, inline.children)
+			buffer.WriteString("</em>")
+
+		case strongNode:
+			buffer.WriteString("<strong>")
+			writeHTMLInlines(buffer, inline.children)
+			buffer.WriteString("</strong>")
+
+		case linkNode:
+			if !safeURL(inline.destination, "http", "https", "mailto") {
+				writeHTMLInlines(buffer, inline.children)
+				continue
+			}
+
+			buffer.WriteString(`<a href="` + htmlEscaper.Replace(inline.destination) + `"`)
+			if inline.title != "" {
+				buffer.WriteString(` title="` + htmlEscaper.Replace(inline.title) + `"`)
+			}
+			buffer.WriteString(">")
+			writeHTMLInlines(buffer, inline.children)
+			buffer.WriteString("</a>")
+
+		case imageNode:
+			if !safeURL(inline.destination, "http", "https") {
+				buffer.WriteString(htmlEscaper.Replace(plainText(inline.children)))
+				continue
+			}
+
+			buffer.WriteString(`<img src="` + htmlEscaper.Replace(inline.destination) + `" alt="` + htmlEscaper.Replace(plainText(inline.children)) + `"`)
+			if inline.title != "" {
+				buffer.WriteString(` title="` + htmlEscaper.Replace(inline.title) + `"`)
+			}
+			buffer.WriteString(">")
+
+		case lineBreakNode:
+			buffer.WriteString("<br>\n")
+
+		case softBreakNode:
+			buffer.WriteString("\n")
+		}
+	}
+}
+
+func renderText(document *node) string {
+	if len(document.children) == 0 {
+		return ""
+	}
+
+	return strings.Join(textBlocks(document.children), "\n\n") + "\n"
+}
+
+func textBlocks(blocks []*node) []string {
+	var rendered []string
+	for _, block := range blocks {
+		rendered = append(rendered, textBlock(block))
+	}
+
+	return rendered
+}
+
+func textBlock(block *node) string {
+	switch block.kind {
+	case paragraphNode:
+		return inlineText(block.children)
+
+	case headingNode:
+		heading := inlineText(block.children)
+		switch block.level {
+		case 1:
+			return heading + "\n" + strings.Repeat("=", underlineLength(heading))
+		case 2:
+			return heading + "\n" + strings.Repeat("-", underlineLength(heading))
+		default:
+			return heading
+		}
+
+	case thematicBreakNode:
+		return "----------"
+
+	case codeBlockNode:
+		return prefixLines(strings.TrimSuffix(block.literal, "\n"), "    ", "    ")
+
+	case blockQuoteNode:
+		return prefixLines(strings.Join(textBlocks(block.children), "\n\n"), "> ", "> ")
+
+	case listNode:
+		separator := "\n"
+		if !block.tight {
+			separator = "\n\n"
+		}
+
+		var items []string
+		for i, item := range block.children {
+			marker := "- "
+			if block.ordered {
+				marker = strconv.Itoa(block.start+i) + ". "
+			}
+
+			itemSeparator := "\n\n"
+			if block.tight {
+				itemSeparator = "\n"
+			}
+
+			content := strings.Join(textBlocks(item.children), itemSeparator)
+			items = append(items, prefixLines(content, marker, strings.Repeat(" ", len(marker))))
+		}
+
+		return strings.Join(items, separator)
+	}
+
+	return ""
+}
+
+func inlineText(inlines []*node) string {
+	var buffer bytes.Buffer
+	for _, inline := range inlines {
+		switch inline.kind {
+		case textNode, codeNode:
+			buffer.WriteString(inline.literal)
+
+		case emphasisNode, strongNode:
+			buffer.WriteString(inlineText(inline.children))
+
+		case linkNode, imageNode:
+			label := inlineText(inline.children)
+			destination := inline.destination
+
+			safe := safeURL(destination, "http", "https", "mailto")
+			if inline.kind == imageNode {
+				safe = safeURL(destination, "http", "https")
+			}
+
+			switch {
+			case !safe, label == strings.TrimPrefix(destination, "mailto:"):
+				buffer.WriteString(label)
+			case label == "":
+				buffer.WriteString(destination)
+			default:
+				buffer.WriteString(label + " (" + destination + ")")
+			}
+
+		case lineBreakNode, softBreakNode:
+			buffer.WriteString("\n")
+		}
+	}
+
+	return buffer.String()
+}
+
+func plainText(inlines []*node) string {
+	var buffer bytes.Buffer
+	for _, inline := range inlines {
+		switch inline.kind {
+		case textNode, codeNode:
+			buffer.WriteString(inline.literal)
+		case lineBreakNode, softBreakNode:
+			buffer.WriteString(" ")
+		default:
+			buffer.WriteString(plainText(inline.children))
+		}
+	}
+
+	return buffer.String()
+}
+
+func prefixLines(content, first, rest string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+			continue
+		}
+
+		lines[i] = prefix + line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func underlineLength(heading string) int {
+	longest := 0
+	for _, line := range strings.Split(heading, "\n") {
+		if length := len([]rune(line)); length > longest {
+			longest = length
+		}
+	}
+
+	return longest
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/postal/v1"
 	"github.com/cloudfoundry-incubator/notifications/postal/v2"
+	"github.com/cloudfoundry-incubator/notifications/sanitize"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/util"
 	v1models "github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -31,7 +32,7 @@ import (
 type mother interface {
 	SQLDatabase() *sql.DB
 	Database() db.DatabaseInterface
-	MailClient() *mail.Client
+	MailRelayPool() *mail.RelayPool
 }
 
 type uaaTokenValidator interface {
@@ -50,8 +51,13 @@ type Config struct {
 	DBLoggingEnabled     bool
 	Sender               string
 	Domain               string
+	PublicURL            string
 	QueueWaitMaxDuration int
 	CCHost               string
+	TestMode             bool
+	SMTPLoggingEnabled   bool
+	HTMLPolicy           sanitize.Policy
+	HTMLClientPolicies   map[string]sanitize.Policy
 }
 
 func Boot(mom mother, config Config) {
@@ -75,22 +81,28 @@ func Boot(mom mother, config Config) {
 		panic(err)
 	}
 
+	sanitizer := sanitize.NewSanitizer(config.HTMLPolicy, config.HTMLClientPolicies)
+
 	guidGenerator := util.NewIDGenerator(rand.Reader)
 
 	// V1
 	receiptsRepo := v1models.NewReceiptsRepo()
 	unsubscribesRepo := v1models.NewUnsubscribesRepo()
 	globalUnsubscribesRepo := v1models.NewGlobalUnsubscribesRepo()
+	suppressionsRepo := v1models.NewSuppressionsRepo()
+	userLocalesRepo := v1models.NewUserLocalesRepo()
 	messagesRepo := v1models.NewMessagesRepo(guidGenerator.Generate)
 	clientsRepo := v1models.NewClientsRepo()
 	kindsRepo := v1models.NewKindsRepo()
 	templatesRepo := v1models.NewTemplatesRepo()
 	v1TemplateLoader := v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo)
+	attachmentsRepo := v1models.NewAttachmentsRepo(guidGenerator.Generate)
+	v1AttachmentsLoader := v1.NewAttachmentsLoader(database, attachmentsRepo)
 	deliveryFailureHandler := common.NewDeliveryFailureHandler()
 	messageStatusUpdater := v1.NewMessageStatusUpdater(messagesRepo)
 	userLoader := common.NewUserLoader(uaaClient)
 	tokenLoader := uaa.NewTokenLoader(uaaClient)
-	packager := common.NewPackager(v1TemplateLoader, cloak)
+	packager := common.NewPackager(v1TemplateLoader, v1AttachmentsLoader, cloak, sanitizer)
 
 	// V2
 	metricsEmitter := metrics.NewEmitter(metrics.DefaultLogger)
@@ -114,10 +126,22 @@ func Boot(mom mother, config Config) {
 	v2messageStatusUpdater := v2.NewV2MessageStatusUpdater(messagesRepository)
 	unsubscribersRepository := v2models.NewUnsubscribersRepository(guidGenerator.Generate)
 	campaignsRepository := v2models.NewCampaignsRepository(guidGenerator.Generate, clock)
+	campaignTypesRepository := v2models.NewCampaignTypesRepository(guidGenerator.Generate)
+	suppressionsRepository := v2models.NewSuppressionsRepository()
+	userLocalesRepository := v2models.NewUserLocalesRepository()
 	v2templatesRepo := v2models.NewTemplatesRepository(guidGenerator.Generate)
-	templatesCollection := collections.NewTemplatesCollection(v2templatesRepo)
-	v2TemplateLoader := v2.NewTemplatesLoader(v2database, templatesCollection)
+	templatesCollection := collections.NewTemplatesCollection(v2templatesRepo, v2models.NewTemplateVersionsRepository(guidGenerator.Generate, clock), campaignTypesRepository, campaignsRepository, v2models.NewClientTemplatesRepository(clock))
+	templatePartialsCollection := collections.NewTemplatePartialsCollection(v2models.NewTemplatePartialsRepository(guidGenerator.Generate, clock), v2templatesRepo)
+	v2TemplateLoader := v2.NewTemplatesLoader(v2database, templatesCollection, templatePartialsCollection)
+	attachmentsRepository := v2models.NewAttachmentsRepository(guidGenerator.Generate, clock)
+	v2AttachmentsLoader := v2.NewAttachmentsLoader(v2database, attachmentsRepository)
 	v2deliveryFailureHandler := common.NewDeliveryFailureHandler()
+	transportsCollection := collections.NewTransportsCollection(v2models.NewTransportsRepository(clock), v2models.NewTransportGrantsRepository(clock), cloak)
+	transportConfig := mail.Config{
+		TestMode:       config.TestMode,
+		SkipVerifySSL:  !config.VerifySSL,
+		LoggingEnabled: config.SMTPLoggingEnabled,
+	}
 	campaignJobProcessor := v2.NewCampaignJobProcessor(notify.EmailFormatter{}, notify.HTMLExtractor{},
 		emailsAudienceGenerator, spacesAudienceGenerator, orgsAudienceGenerator, usersAudienceGenerator, v2enqueuer)
 
@@ -126,13 +150,14 @@ func Boot(mom mother, config Config) {
 		Count:         config.WorkerCount,
 	}.Work(func(index int) Worker {
 
-		mailClient := mom.MailClient()
+		mailClient := NewTransportRouter(mom.MailRelayPool(), transportsCollection, v2database, transportConfig, newMailClient)
 
 		v1DeliveryJobProcessor := v1.NewDeliveryJobProcessor(v1.DeliveryJobProcessorConfig{
-			DBTrace: config.DBLoggingEnabled,
-			UAAHost: config.UAAHost,
-			Sender:  config.Sender,
-			Domain:  config.Domain,
+			DBTrace:   config.DBLoggingEnabled,
+			UAAHost:   config.UAAHost,
+			Sender:    config.Sender,
+			Domain:    config.Domain,
+			PublicURL: config.PublicURL,
 
 			Packager:    packager,
 			MailClient:  mailClient,
@@ -144,15 +169,17 @@ func Boot(mom mother, config Config) {
 			ReceiptsRepo:           receiptsRepo,
 			UnsubscribesRepo:       unsubscribesRepo,
 			GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+			SuppressionsRepo:       suppressionsRepo,
+			UserLocalesRepo:        userLocalesRepo,
 			MessageStatusUpdater:   messageStatusUpdater,
 			DeliveryFailureHandler: deliveryFailureHandler,
 		})
 
-		v2mailClient := mom.MailClient()
+		v2mailClient := NewTransportRouter(mom.MailRelayPool(), transportsCollection, v2database, transportConfig, newMailClient)
 
-		v2DeliveryJobProcessor := v2.NewDeliveryJobProcessor(v2mailClient, common.NewPackager(v2TemplateLoader, cloak),
+		v2DeliveryJobProcessor := v2.NewDeliveryJobProcessor(v2mailClient, common.NewPackager(v2TemplateLoader, v2AttachmentsLoader, cloak, sanitizer),
 			common.NewUserLoader(uaaClient), uaa.NewTokenLoader(uaaClient), v2messageStatusUpdater, v2database,
-			unsubscribersRepository, campaignsRepository, config.Sender, config.Domain, config.UAAHost, metricsEmitter)
+			unsubscribersRepository, campaignsRepository, campaignTypesRepository, suppressionsRepository, userLocalesRepository, config.Sender, config.Domain, config.UAAHost, config.PublicURL, metricsEmitter)
 
 		worker := NewDeliveryWorker(v1DeliveryJobProcessor, v2DeliveryJobProcessor, DeliveryWorkerConfig{
 			ID:      index,
@@ -0,0 +1,285 @@
+package common
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+var (
+	cssComments      = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	selectorIDs      = regexp.MustCompile(`#[\w-]+`)
+	selectorClasses  = regexp.MustCompile(`\.[\w-]+|\[[^\]]*\]`)
+	selectorElements = regexp.MustCompile(`(?:^|[\s>+~])[a-zA-Z][\w-]*`)
+)
+
+type cssRule struct {
+	selector     cascadia.Selector
+	specificity  int
+	order        int
+	declarations []cssDeclaration
+}
+
+type cssDeclaration struct {
+	property  string
+	value     string
+	important bool
+}
+
+// InlineCSS copies the rules from the <style> blocks of an HTML document into
+// the style attribute of each element they match, since many mail clients
+// ignore stylesheets. Rules that cannot be inlined, such as those with
+// pseudo-classes or inside media queries, are left in the stylesheet, which
+// is kept for the clients that do support it.
+func InlineCSS(document string) (string, error) {
+	if !strings.Contains(strings.ToLower(document), "<style") {
+		return document, nil
+	}
+
+	root, err := html.Parse(strings.NewReader(document))
+	if err != nil {
+		return "", err
+	}
+
+	var stylesheet bytes.Buffer
+	for _, style := range cascadia.MustCompile("style").MatchAll(root) {
+		for child := style.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.TextNode {
+				stylesheet.WriteString(child.Data)
+				stylesheet.WriteString("\n")
+			}
+		}
+	}
+
+	rules := parseStylesheet(stylesheet.String())
+	if len(rules) == 0 {
+		return document, nil
+	}
+
+	sort.Stable(bySpecificity(rules))
+
+	matches := map[*html.Node][]cssDeclaration{}
+	var elements []*html.Node
+	for _, rule := range rules {
+		for _, element := range rule.selector.MatchAll(root) {
+			if !inBody(element) {
+				continue
+			}
+
+			if _, ok := matches[element]; !ok {
+				elements = append(elements, element)
+			}
+			matches[element] = append(matches[element], rule.declarations...)
+		}
+	}
+
+	for _, element := range elements {
+		inline := parseDeclarations(attribute(element, "style"))
+		setAttribute(element, "style", mergeDeclarations(matches[element], inline))
+	}
+
+	var buffer bytes.Buffer
+	err = html.Render(&buffer, root)
+	if err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+func parseStylesheet(stylesheet string) []cssRule {
+	stylesheet = cssComments.ReplaceAllString(stylesheet, "")
+
+	var rules []cssRule
+	for len(stylesheet) > 0 {
+		stylesheet = strings.TrimSpace(stylesheet)
+		if stylesheet == "" {
+			break
+		}
+
+		if strings.HasPrefix(stylesheet, "@") {
+			stylesheet = skipAtRule(stylesheet)
+			continue
+		}
+
+		open := strings.Index(stylesheet, "{")
+		if open < 0 {
+			break
+		}
+
+		end := strings.Index(stylesheet[open:], "}")
+		if end < 0 {
+			break
+		}
+		end += open
+
+		declarations := parseDeclarations(stylesheet[open+1 : end])
+		for _, selector := range strings.Split(stylesheet[:open], ",") {
+			selector = strings.TrimSpace(selector)
+			if selector == "" || strings.Contains(selector, ":") {
+				continue
+			}
+
+			compiled, err := cascadia.Compile(selector)
+			if err != nil {
+				continue
+			}
+
+			rules = append(rules, cssRule{
+				selector:     compiled,
+				specificity:  specificity(selector),
+				order:        len(rules),
+				declarations: declarations,
+			})
+		}
+
+		stylesheet = stylesheet[end+1:]
+	}
+
+	return rules
+}
+
+func skipAtRule(stylesheet string) string {
+	depth := 0
+	for i, c := range stylesheet {
+		switch c {
+		case ';':
+			if depth == 0 {
+				return stylesheet[i+1:]
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return stylesheet[i+1:]
+			}
+		}
+	}
+
+	return ""
+}
+
+func parseDeclarations(block string) []cssDeclaration {
+	var declarations []cssDeclaration
+	for _, declaration := range strings.Split(block, ";") {
+		parts := strings.SplitN(declaration, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		property := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+		important := false
+		if index := strings.Index(strings.ToLower(value), "!important"); index >= 0 {
+			value = strings.TrimSpace(value[:index])
+			important = true
+		}
+
+		if property == "" || value == "" {
+			continue
+		}
+
+		declarations = append(declarations, cssDeclaration{
+			property:  property,
+			value:     value,
+			important: important,
+		})
+	}
+
+	return declarations
+}
+
+// mergeDeclarations applies stylesheet rules in cascade order, followed by
+// the element's own style attribute. Important stylesheet declarations still
+// win over the style attribute.
+func mergeDeclarations(stylesheet, inline []cssDeclaration) string {
+	var properties []string
+	values := map[string]string{}
+	important := map[string]bool{}
+
+	set := func(declaration cssDeclaration) {
+		if _, ok := values[declaration.property]; !ok {
+			properties = append(properties, declaration.property)
+		}
+		values[declaration.property] = declaration.value
+	}
+
+	for _, declaration := range stylesheet {
+		if important[declaration.property] && !declaration.important {
+			continue
+		}
+
+		set(declaration)
+		important[declaration.property] = important[declaration.property] || declaration.important
+	}
+
+	for _, declaration := range inline {
+		if important[declaration.property] && !declaration.important {
+			continue
+		}
+
+		set(declaration)
+	}
+
+	var style []string
+	for _, property := range properties {
+		style = append(style, property+": "+values[property])
+	}
+
+	return strings.Join(style, "; ")
+}
+
+func specificity(selector string) int {
+	ids := len(selectorIDs.FindAllString(selector, -1))
+	classes := len(selectorClasses.FindAllString(selector, -1))
+	elements := len(selectorElements.FindAllString(selectorClasses.ReplaceAllString(selector, " "), -1))
+
+	return ids*10000 + classes*100 + elements
+}
+
+func inBody(node *html.Node) bool {
+	for ; node != nil; node = node.Parent {
+		switch node.Data {
+		case "body":
+			return true
+		case "head", "html":
+			return false
+		}
+	}
+
+	return false
+}
+
+func setAttribute(node *html.Node, key, value string) {
+	for i, attr := range node.Attr {
+		if attr.Key == key {
+			node.Attr[i].Val = value
+			return
+		}
+	}
+
+	node.Attr = append(node.Attr, html.Attribute{Key: key, Val: value})
+}
+
+type bySpecificity []cssRule
+
+func (rules bySpecificity) Len() int {
+	return len(rules)
+}
+
+func (rules bySpecificity) Less(i, j int) bool {
+	if rules[i].specificity != rules[j].specificity {
+		return rules[i].specificity < rules[j].specificity
+	}
+
+	return rules[i].order < rules[j].order
+}
+
+func (rules bySpecificity) Swap(i, j int) {
+	rules[i], rules[j] = rules[j], rules[i]
+}
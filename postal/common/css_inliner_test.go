@@ -0,0 +1,40 @@
+package common_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InlineCSS", func() {
+	It("copies stylesheet rules into the style attribute of matching elements", func() {
+		document, err := common.InlineCSS(`<html><head><style>
+			/* base styles */
+			p { color: red; margin: 0 }
+			.note, #footer { font-size: 12px }
+			p.note { color: green }
+			a:hover { color: purple }
+			@media (max-width: 600px) { p { color: black } }
+		</style></head><body><p>plain</p><p class="note">note</p><div id="footer"><a href="#">link</a></div></body></html>`)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(document).To(ContainSubstring(`<p style="color: red; margin: 0">plain</p>`))
+		Expect(document).To(ContainSubstring(`<p class="note" style="color: green; margin: 0; font-size: 12px">note</p>`))
+		Expect(document).To(ContainSubstring(`<div id="footer" style="font-size: 12px"><a href="#">link</a></div>`))
+		Expect(document).To(ContainSubstring("a:hover { color: purple }"))
+	})
+
+	It("lets the style attribute win unless the rule is important", func() {
+		document, err := common.InlineCSS(`<style>p { color: red; font-weight: bold !important }</style><p style="color: blue; font-weight: normal">text</p>`)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(document).To(ContainSubstring(`<p style="color: blue; font-weight: bold">text</p>`))
+	})
+
+	It("returns documents without a stylesheet unchanged", func() {
+		document, err := common.InlineCSS(`<head></head><html><body><p>text</p></body></html>`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(document).To(Equal(`<head></head><html><body><p>text</p></body></html>`))
+	})
+})
@@ -0,0 +1,148 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var dataTypes = []string{"string", "number", "boolean", "object", "array"}
+
+// DataSchema lists the custom data a template expects, as declared under the
+// "variables" key of its metadata, e.g.
+//
+//	{"variables": {"app_name": {"type": "string", "required": true}}}
+type DataSchema struct {
+	Variables map[string]DataVariable `json:"variables"`
+}
+
+type DataVariable struct {
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+}
+
+type DataSchemaError struct {
+	Err error
+}
+
+func (e DataSchemaError) Error() string {
+	return e.Err.Error()
+}
+
+// DataValidationError lists every way some data fails to satisfy a schema.
+type DataValidationError struct {
+	Messages []string
+}
+
+func (e DataValidationError) Error() string {
+	return strings.Join(e.Messages, "; ")
+}
+
+// ParseDataSchema reads the schema from template metadata. Metadata that
+// does not declare any variables yields an empty schema that accepts any
+// data.
+func ParseDataSchema(metadata string) (DataSchema, error) {
+	var schema DataSchema
+	if strings.TrimSpace(metadata) == "" {
+		return schema, nil
+	}
+
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal([]byte(metadata), &fields)
+	if err != nil {
+		return schema, nil
+	}
+
+	variables, ok := fields["variables"]
+	if !ok {
+		return schema, nil
+	}
+
+	err = json.Unmarshal(variables, &schema.Variables)
+	if err != nil {
+		return DataSchema{}, DataSchemaError{fmt.Errorf("metadata variables must map each name to a type and whether it is required")}
+	}
+
+	for _, name := range schema.names() {
+		variable := schema.Variables[name]
+		if variable.Type != "" && !containsString(dataTypes, variable.Type) {
+			return DataSchema{}, DataSchemaError{fmt.Errorf("metadata variable %q has an unknown type %q, expected one of %s", name, variable.Type, strings.Join(dataTypes, ", "))}
+		}
+	}
+
+	return schema, nil
+}
+
+// Validate checks that the data holds every required variable and that each
+// declared variable has the declared type. Variables without a type accept
+// any value, and data the schema does not mention is allowed.
+func (schema DataSchema) Validate(data map[string]interface{}) error {
+	var messages []string
+	for _, name := range schema.names() {
+		variable := schema.Variables[name]
+
+		value, ok := data[name]
+		if !ok || value == nil {
+			if variable.Required {
+				messages = append(messages, fmt.Sprintf("data.%s is required", name))
+			}
+			continue
+		}
+
+		if variable.Type != "" && dataType(value) != variable.Type {
+			messages = append(messages, fmt.Sprintf("data.%s must be %s", name, withArticle(variable.Type)))
+		}
+	}
+
+	if len(messages) > 0 {
+		return DataValidationError{Messages: messages}
+	}
+
+	return nil
+}
+
+func (schema DataSchema) names() []string {
+	var names []string
+	for name := range schema.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func dataType(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64, float32, int, int64, json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return ""
+	}
+}
+
+func withArticle(noun string) string {
+	if strings.IndexAny(noun[:1], "aeiou") == 0 {
+		return "an " + noun
+	}
+
+	return "a " + noun
+}
+
+func containsString(elements []string, element string) bool {
+	for _, e := range elements {
+		if e == element {
+			return true
+		}
+	}
+
+	return false
+}
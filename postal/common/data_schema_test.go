@@ -0,0 +1,85 @@
+package common_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DataSchema", func() {
+	Describe("ParseDataSchema", func() {
+		It("reads the variables declared in the metadata", func() {
+			schema, err := common.ParseDataSchema(`{"variables": {"app_name": {"type": "string", "required": true}, "count": {"type": "number"}}}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(schema).To(Equal(common.DataSchema{
+				Variables: map[string]common.DataVariable{
+					"app_name": {Type: "string", Required: true},
+					"count":    {Type: "number"},
+				},
+			}))
+		})
+
+		It("returns an empty schema when the metadata declares no variables", func() {
+			for _, metadata := range []string{"", "{}", `{"owner": "someone"}`} {
+				schema, err := common.ParseDataSchema(metadata)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(schema.Variables).To(BeEmpty())
+			}
+		})
+
+		It("returns a DataSchemaError when the variables are malformed", func() {
+			_, err := common.ParseDataSchema(`{"variables": ["app_name"]}`)
+			Expect(err).To(BeAssignableToTypeOf(common.DataSchemaError{}))
+		})
+
+		It("returns a DataSchemaError when a variable has an unknown type", func() {
+			_, err := common.ParseDataSchema(`{"variables": {"app_name": {"type": "text"}}}`)
+			Expect(err).To(MatchError(`metadata variable "app_name" has an unknown type "text", expected one of string, number, boolean, object, array`))
+			Expect(err).To(BeAssignableToTypeOf(common.DataSchemaError{}))
+		})
+	})
+
+	Describe("Validate", func() {
+		var schema common.DataSchema
+
+		BeforeEach(func() {
+			schema = common.DataSchema{
+				Variables: map[string]common.DataVariable{
+					"app_name": {Type: "string", Required: true},
+					"count":    {Type: "number"},
+					"details":  {Type: "object"},
+					"extra":    {},
+				},
+			}
+		})
+
+		It("accepts data that satisfies the schema", func() {
+			err := schema.Validate(map[string]interface{}{
+				"app_name":   "my-app",
+				"count":      float64(3),
+				"details":    map[string]interface{}{"region": "us"},
+				"extra":      []interface{}{"anything"},
+				"undeclared": true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("lists every missing or mistyped variable", func() {
+			err := schema.Validate(map[string]interface{}{
+				"count":   "three",
+				"details": []interface{}{},
+			})
+			Expect(err).To(Equal(common.DataValidationError{Messages: []string{
+				"data.app_name is required",
+				"data.count must be a number",
+				"data.details must be an object",
+			}}))
+		})
+
+		It("treats a null value as missing", func() {
+			err := schema.Validate(map[string]interface{}{"app_name": nil})
+			Expect(err).To(MatchError("data.app_name is required"))
+		})
+	})
+})
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/uaa"
@@ -53,3 +55,30 @@ func UAAErrorFor(err error) error {
 		return UAAGenericError{errors.New("UAA Unknown Error: " + err.Error())}
 	}
 }
+
+var templateErrorLine = regexp.MustCompile(`^template: [^:]*:(\d+):`)
+
+// TemplateCompileError reports a template part that could not be parsed.
+// Line is taken from the parser message and is zero when it cannot be found.
+type TemplateCompileError struct {
+	Part string
+	Line int
+	Err  error
+}
+
+func NewTemplateCompileError(part string, err error) TemplateCompileError {
+	var line int
+	if matches := templateErrorLine.FindStringSubmatch(err.Error()); matches != nil {
+		line, _ = strconv.Atoi(matches[1])
+	}
+
+	return TemplateCompileError{
+		Part: part,
+		Line: line,
+		Err:  err,
+	}
+}
+
+func (e TemplateCompileError) Error() string {
+	return e.Err.Error()
+}
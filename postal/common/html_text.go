@@ -0,0 +1,272 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// HTMLToText derives a plain-text alternative from an HTML body. Headings,
+// paragraphs and lists keep their shape, and links are listed as numbered
+// footnotes after the text.
+func HTMLToText(body string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(body), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return ""
+	}
+
+	renderer := &textRenderer{}
+	for _, node := range nodes {
+		renderer.render(node)
+	}
+
+	text := strings.TrimSpace(renderer.buffer.String())
+	if len(renderer.links) > 0 {
+		var footnotes []string
+		for i, link := range renderer.links {
+			footnotes = append(footnotes, fmt.Sprintf("[%d] %s", i+1, link))
+		}
+
+		text += "\n\n" + strings.Join(footnotes, "\n")
+	}
+
+	return text
+}
+
+type textRenderer struct {
+	buffer   bytes.Buffer
+	links    []string
+	prefix   string
+	newlines int
+	space    bool
+	pre      bool
+	marker   bool
+	lists    int
+
+	blankPrefix string
+}
+
+func (r *textRenderer) render(node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		r.text(node.Data)
+		return
+	case html.ElementNode, html.DocumentNode:
+	default:
+		return
+	}
+
+	switch node.Data {
+	case "head", "script", "style", "title":
+		return
+
+	case "br":
+		r.newline(1)
+		r.flush()
+		return
+
+	case "hr":
+		r.newline(2)
+		r.write("----------")
+		r.newline(2)
+		return
+
+	case "img":
+		r.text(attribute(node, "alt"))
+		return
+
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		heading := &textRenderer{links: r.links}
+		heading.renderChildren(node)
+		r.links = heading.links
+
+		text := strings.Join(strings.Fields(heading.buffer.String()), " ")
+		r.newline(2)
+		r.write(text)
+		switch node.Data {
+		case "h1":
+			r.newline(1)
+			r.write(strings.Repeat("=", len([]rune(text))))
+		case "h2":
+			r.newline(1)
+			r.write(strings.Repeat("-", len([]rune(text))))
+		}
+		r.newline(2)
+		return
+
+	case "a":
+		start := r.buffer.Len()
+		r.renderChildren(node)
+		label := strings.TrimSpace(r.buffer.String()[start:])
+
+		href := strings.TrimSpace(attribute(node, "href"))
+		if href == "" || strings.HasPrefix(href, "#") || href == label || href == "mailto:"+label {
+			return
+		}
+
+		r.links = append(r.links, strings.TrimPrefix(href, "mailto:"))
+		r.write(" [" + strconv.Itoa(len(r.links)) + "]")
+		return
+
+	case "ul", "ol":
+		spacing := 2
+		if r.lists > 0 {
+			spacing = 1
+		}
+
+		r.lists++
+		r.newline(spacing)
+		number := 1
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode || child.Data != "li" {
+				r.render(child)
+				continue
+			}
+
+			marker := "- "
+			if node.Data == "ol" {
+				marker = strconv.Itoa(number) + ". "
+				number++
+			}
+
+			r.newline(1)
+			r.write(marker)
+			r.marker = true
+			prefix := r.prefix
+			r.prefix += strings.Repeat(" ", len(marker))
+			r.renderChildren(child)
+			r.prefix = prefix
+		}
+		r.lists--
+		r.newline(spacing)
+		return
+
+	case "blockquote":
+		r.newline(2)
+		prefix := r.prefix
+		r.prefix += "> "
+		r.renderChildren(node)
+		r.prefix = prefix
+		r.newline(2)
+		return
+
+	case "pre":
+		r.newline(2)
+		r.pre = true
+		r.renderChildren(node)
+		r.pre = false
+		r.newline(2)
+		return
+
+	case "td", "th":
+		if node.PrevSibling != nil {
+			r.space = true
+		}
+		r.renderChildren(node)
+		return
+	}
+
+	blocks := map[string]int{
+		"p": 2, "table": 2, "dl": 2,
+		"div": 1, "tr": 1, "li": 1, "dt": 1, "dd": 1,
+		"section": 1, "article": 1, "header": 1, "footer": 1, "center": 1,
+	}
+
+	spacing := blocks[node.Data]
+	r.newline(spacing)
+	r.renderChildren(node)
+	r.newline(spacing)
+}
+
+func (r *textRenderer) renderChildren(node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		r.render(child)
+	}
+}
+
+func (r *textRenderer) text(text string) {
+	if r.pre {
+		for i, line := range strings.Split(text, "\n") {
+			if i > 0 {
+				r.newline(1)
+			}
+			r.write(line)
+		}
+		return
+	}
+
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		if text != "" {
+			r.space = true
+		}
+		return
+	}
+
+	if isSpace(text[0]) {
+		r.space = true
+	}
+
+	r.write(strings.Join(words, " "))
+	r.space = isSpace(text[len(text)-1])
+}
+
+func (r *textRenderer) write(text string) {
+	if text == "" {
+		return
+	}
+
+	if r.newlines > 0 {
+		r.flush()
+	} else if r.space && r.buffer.Len() > 0 && !endsLine(r.buffer.Bytes()) {
+		r.buffer.WriteString(" ")
+	}
+
+	r.space = false
+	r.marker = false
+	r.buffer.WriteString(text)
+}
+
+func (r *textRenderer) newline(count int) {
+	if r.buffer.Len() > 0 && !r.marker && count > r.newlines {
+		r.newlines = count
+		r.blankPrefix = r.prefix
+	}
+}
+
+func (r *textRenderer) flush() {
+	for ; r.newlines > 0; r.newlines-- {
+		r.buffer.WriteString("\n")
+		if r.newlines > 1 {
+			r.buffer.WriteString(strings.TrimRight(r.blankPrefix, " "))
+		}
+	}
+	r.buffer.WriteString(r.prefix)
+	r.space = false
+}
+
+func attribute(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+func endsLine(content []byte) bool {
+	return content[len(content)-1] == '\n' || content[len(content)-1] == ' '
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
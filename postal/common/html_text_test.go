@@ -0,0 +1,35 @@
+package common_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HTMLToText", func() {
+	It("keeps headings, paragraphs and line breaks", func() {
+		text := common.HTMLToText(`<h1>Welcome   aboard</h1><p>Hello
+			there,<br>friend.</p><h3>Details</h3><div>one</div><div>two</div>`)
+
+		Expect(text).To(Equal("Welcome aboard\n==============\n\nHello there,\nfriend.\n\nDetails\n\none\ntwo"))
+	})
+
+	It("renders lists and block quotes", func() {
+		text := common.HTMLToText(`<ul><li>first</li><li>second<ol><li>nested</li></ol></li></ul><blockquote><p>quoted</p><p>twice</p></blockquote>`)
+
+		Expect(text).To(Equal("- first\n- second\n  1. nested\n\n> quoted\n>\n> twice"))
+	})
+
+	It("lists links as footnotes", func() {
+		text := common.HTMLToText(`<p>Read <a href="https://example.com/docs">the docs</a>, visit <a href="https://example.com">https://example.com</a> or <a href="mailto:help@example.com">email us</a>.</p>`)
+
+		Expect(text).To(Equal("Read the docs [1], visit https://example.com or email us [2].\n\n[1] https://example.com/docs\n[2] help@example.com"))
+	})
+
+	It("skips scripts and styles and keeps preformatted text", func() {
+		text := common.HTMLToText("<style>p { color: red }</style><script>alert(1)</script><pre>line one\n  line two</pre><img alt=\"logo\" src=\"x.png\">")
+
+		Expect(text).To(Equal("line one\n  line two\n\nlogo"))
+	})
+})
@@ -0,0 +1,205 @@
+package common
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LayoutContent is the name a layout uses to include the template it wraps,
+// as in {{template "content" .}}.
+const LayoutContent = "content"
+
+var (
+	templateReference = regexp.MustCompile(`\{\{-?\s*template\s+"([^"]*)"`)
+	contentReference  = regexp.MustCompile(`(\{\{-?\s*template\s+)"` + LayoutContent + `"`)
+)
+
+// Partial is a named snippet that templates and layouts include with
+// {{template "name" .}}. The text is used in the subject and text parts and
+// the HTML in the html part.
+type Partial struct {
+	Name string
+	Text string
+	HTML string
+}
+
+// TemplateReferences lists the names of the templates included by the source.
+func TemplateReferences(source string) []string {
+	var names []string
+	for _, match := range templateReference.FindAllStringSubmatch(source, -1) {
+		names = append(names, match[1])
+	}
+
+	return names
+}
+
+// UndefinedReferences lists, in order and without repeats, the templates
+// each part includes without defining them itself. The content a layout
+// wraps is not counted, and parts that do not parse are skipped.
+func UndefinedReferences(templates Templates, localizations Localizations) []string {
+	sources := []string{templates.Subject, templates.Text, templates.HTML}
+
+	var locales []string
+	for locale := range localizations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		localization := localizations[locale]
+		sources = append(sources, localization.Subject, localization.Text, localization.HTML)
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, source := range sources {
+		parsed, err := NewTemplate("source").Parse(source)
+		if err != nil {
+			continue
+		}
+
+		for _, name := range TemplateReferences(source) {
+			if name == LayoutContent || seen[name] || parsed.Lookup(name) != nil {
+				continue
+			}
+
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// IncludesLayoutContent reports whether a layout part includes the template
+// it wraps.
+func IncludesLayoutContent(source string) bool {
+	for _, name := range TemplateReferences(source) {
+		if name == LayoutContent {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ComposeTemplates flattens the templates, the layouts that wrap them and the
+// partials they include into sources the Packager can compile on their own.
+// Layouts are ordered from the innermost outwards, and only wrap the parts
+// they define.
+func ComposeTemplates(templates Templates, layouts []Templates, partials []Partial) Templates {
+	var textLayouts, htmlLayouts []string
+	for _, layout := range layouts {
+		textLayouts = append(textLayouts, layout.Text)
+		htmlLayouts = append(htmlLayouts, layout.HTML)
+	}
+
+	textPartials := map[string]string{}
+	htmlPartials := map[string]string{}
+	var names []string
+	for _, partial := range partials {
+		names = append(names, partial.Name)
+		textPartials[partial.Name] = partial.Text
+		htmlPartials[partial.Name] = partial.HTML
+	}
+	sort.Strings(names)
+
+	return Templates{
+		Name:    templates.Name,
+		Subject: composePart(templates.Subject, nil, names, textPartials),
+		Text:    composePart(templates.Text, textLayouts, names, textPartials),
+		HTML:    composePart(templates.HTML, htmlLayouts, names, htmlPartials),
+
+		DisableAutoText:    templates.DisableAutoText,
+		DisableCSSInlining: templates.DisableCSSInlining,
+	}
+}
+
+func composePart(body string, layouts []string, names []string, partials map[string]string) string {
+	var definitions []string
+	for _, layout := range layouts {
+		if layout == "" {
+			continue
+		}
+
+		name := LayoutContent
+		if len(definitions) > 0 {
+			name = fmt.Sprintf("%s.%d", LayoutContent, len(definitions))
+			layout = contentReference.ReplaceAllString(layout, fmt.Sprintf("${1}%q", name))
+		}
+
+		definitions = append(definitions, define(name, body))
+		body = layout
+	}
+
+	if len(definitions) == 0 && len(names) == 0 {
+		return body
+	}
+
+	for _, name := range names {
+		definitions = append(definitions, define(name, partials[name]))
+	}
+
+	return body + strings.Join(definitions, "")
+}
+
+func define(name, source string) string {
+	return fmt.Sprintf("{{define %q}}%s{{end}}", name, source)
+}
+
+// PartialCycle returns the chain of names that leads from a partial back to
+// itself, or nil when the partials do not include one another in a loop.
+func PartialCycle(partials []Partial) []string {
+	references := map[string][]string{}
+	var names []string
+	for _, partial := range partials {
+		names = append(names, partial.Name)
+		references[partial.Name] = append(TemplateReferences(partial.Text), TemplateReferences(partial.HTML)...)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+
+	var visit func(name string, path []string) []string
+	visit = func(name string, path []string) []string {
+		if _, ok := references[name]; !ok {
+			return nil
+		}
+
+		path = append(path, name)
+		switch state[name] {
+		case visiting:
+			for i, step := range path {
+				if step == name {
+					return path[i:]
+				}
+			}
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		for _, reference := range references[name] {
+			if cycle := visit(reference, path); cycle != nil {
+				return cycle
+			}
+		}
+		state[name] = visited
+
+		return nil
+	}
+
+	for _, name := range names {
+		if cycle := visit(name, nil); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
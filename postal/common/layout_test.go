@@ -0,0 +1,115 @@
+package common_test
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Layouts and partials", func() {
+	render := func(source string) string {
+		buffer := bytes.NewBuffer([]byte{})
+		err := template.Must(template.New("part").Parse(source)).Execute(buffer, map[string]string{"To": "user@example.com"})
+		Expect(err).NotTo(HaveOccurred())
+
+		return buffer.String()
+	}
+
+	Describe("TemplateReferences", func() {
+		It("lists the templates a source includes", func() {
+			Expect(common.TemplateReferences(`{{template "header" .}} body {{- template "footer"}}`)).To(Equal([]string{"header", "footer"}))
+			Expect(common.TemplateReferences(`{{.Text}}`)).To(BeEmpty())
+		})
+	})
+
+	Describe("UndefinedReferences", func() {
+		It("lists the templates the parts include without defining them", func() {
+			Expect(common.UndefinedReferences(common.Templates{
+				Subject: `{{template "subject-prefix"}} {{.Subject}}`,
+				Text:    `{{template "footer" .}}`,
+				HTML:    `<body>{{template "content" .}}{{template "footer" .}}{{template "local" .}}</body>{{define "local"}}here{{end}}`,
+			}, common.Localizations{
+				"fr": {HTML: `{{template "signature" .}}`},
+			})).To(Equal([]string{"subject-prefix", "footer", "signature"}))
+		})
+
+		It("returns nothing when the parts only include the content they wrap", func() {
+			Expect(common.UndefinedReferences(common.Templates{
+				HTML: `<body>{{template "content" .}}</body>`,
+			}, nil)).To(BeEmpty())
+		})
+	})
+
+	Describe("IncludesLayoutContent", func() {
+		It("reports whether the layout includes the content it wraps", func() {
+			Expect(common.IncludesLayoutContent(`<body>{{template "content" .}}</body>`)).To(BeTrue())
+			Expect(common.IncludesLayoutContent(`<body>{{template "footer" .}}</body>`)).To(BeFalse())
+		})
+	})
+
+	Describe("ComposeTemplates", func() {
+		It("returns the templates untouched when there are no layouts or partials", func() {
+			templates := common.Templates{Subject: "{{.Subject}}", Text: "{{.Text}}", HTML: "{{.HTML}}"}
+
+			Expect(common.ComposeTemplates(templates, nil, nil)).To(Equal(templates))
+		})
+
+		It("includes the partials in each part", func() {
+			templates := common.ComposeTemplates(common.Templates{
+				Subject: `[{{template "tag" .}}] hello`,
+				Text:    `hello {{template "signature" .}}`,
+				HTML:    `<p>hello</p>{{template "signature" .}}`,
+			}, nil, []common.Partial{
+				{Name: "signature", Text: "-- {{.To}}", HTML: "<p>{{.To}}</p>"},
+				{Name: "tag", Text: "ops"},
+			})
+
+			Expect(render(templates.Subject)).To(Equal("[ops] hello"))
+			Expect(render(templates.Text)).To(Equal("hello -- user@example.com"))
+			Expect(render(templates.HTML)).To(Equal("<p>hello</p><p>user@example.com</p>"))
+		})
+
+		It("wraps the text and html parts in their layouts, innermost first", func() {
+			templates := common.ComposeTemplates(common.Templates{
+				Subject: "subject",
+				Text:    "body",
+				HTML:    "<p>body</p>",
+			}, []common.Templates{
+				{Text: `inner({{template "content" .}})`, HTML: `<div>{{template "content" .}}</div>`},
+				{Text: `outer({{template "content" .}}) {{template "footer" .}}`},
+			}, []common.Partial{
+				{Name: "footer", Text: "bye {{.To}}"},
+			})
+
+			Expect(render(templates.Subject)).To(Equal("subject"))
+			Expect(render(templates.Text)).To(Equal("outer(inner(body)) bye user@example.com"))
+			Expect(render(templates.HTML)).To(Equal("<div><p>body</p></div>"))
+		})
+	})
+
+	Describe("PartialCycle", func() {
+		It("returns nil when partials do not include each other in a loop", func() {
+			Expect(common.PartialCycle([]common.Partial{
+				{Name: "a", Text: `{{template "b" .}}`},
+				{Name: "b", HTML: `{{template "c" .}}`},
+				{Name: "c", Text: `{{template "content" .}}`},
+			})).To(BeNil())
+		})
+
+		It("returns the chain of partials that loops back on itself", func() {
+			Expect(common.PartialCycle([]common.Partial{
+				{Name: "a", Text: `{{template "b" .}}`},
+				{Name: "b", HTML: `{{template "c" .}}`},
+				{Name: "c", Text: `{{template "a" .}}`},
+			})).To(Equal([]string{"a", "b", "c", "a"}))
+
+			Expect(common.PartialCycle([]common.Partial{
+				{Name: "self", Text: `{{template "self" .}}`},
+			})).To(Equal([]string{"self", "self"}))
+		})
+	})
+})
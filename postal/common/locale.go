@@ -0,0 +1,136 @@
+package common
+
+import (
+	"sort"
+	"strings"
+)
+
+// Localization holds the template parts written for a single locale.
+type Localization struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+type Localizations map[string]Localization
+
+// Select returns the variant that best matches the locale, if there is one.
+func (l Localizations) Select(locale string) (Localization, bool) {
+	var available []string
+	for key := range l {
+		available = append(available, key)
+	}
+	sort.Strings(available)
+
+	key, ok := MatchLocale(locale, available)
+	if !ok {
+		return Localization{}, false
+	}
+
+	return l[key], true
+}
+
+// Apply returns the templates with any parts defined by the best matching
+// variant swapped in. Parts the variant leaves empty fall back to the
+// templates' own content.
+func (l Localizations) Apply(templates Templates, locale string) Templates {
+	localization, ok := l.Select(locale)
+	if !ok {
+		return templates
+	}
+
+	if localization.Subject != "" {
+		templates.Subject = localization.Subject
+	}
+
+	if localization.Text != "" {
+		templates.Text = localization.Text
+	}
+
+	if localization.HTML != "" {
+		templates.HTML = localization.HTML
+	}
+
+	return templates
+}
+
+// LocalizedContent is the message content a sender supplies for a single
+// locale.
+type LocalizedContent struct {
+	Subject string
+	Text    string
+	HTML    HTML
+}
+
+// localize swaps in the content the sender supplied for the locale that best
+// matches the recipient's.
+func (o Options) localize(locale string) Options {
+	var available []string
+	for key := range o.Localizations {
+		available = append(available, key)
+	}
+	sort.Strings(available)
+
+	key, ok := MatchLocale(locale, available)
+	if !ok {
+		return o
+	}
+
+	content := o.Localizations[key]
+	if content.Subject != "" {
+		o.Subject = content.Subject
+	}
+
+	if content.Text != "" {
+		o.Text = content.Text
+	}
+
+	if content.HTML.BodyContent != "" {
+		o.HTML = content.HTML
+	}
+
+	return o
+}
+
+// NormalizeLocale lowercases a locale and uses "-" as the separator, so that
+// "pt_BR", "pt-BR" and "pt-br" are treated as the same locale.
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(strings.TrimSpace(locale), "_", "-", -1))
+}
+
+// MatchLocale picks the entry of available that best serves the locale. An
+// exact match wins, followed by the base language ("pt" for "pt-BR"), and
+// finally any regional variant of the same base language. When nothing
+// matches, the caller should fall back to its default content.
+func MatchLocale(locale string, available []string) (string, bool) {
+	locale = NormalizeLocale(locale)
+	if locale == "" {
+		return "", false
+	}
+
+	base := strings.SplitN(locale, "-", 2)[0]
+
+	var baseMatch, regionalMatch string
+	for _, candidate := range available {
+		normalized := NormalizeLocale(candidate)
+
+		switch {
+		case normalized == locale:
+			return candidate, true
+		case normalized == base:
+			baseMatch = candidate
+		case regionalMatch == "" && strings.HasPrefix(normalized, base+"-"):
+			regionalMatch = candidate
+		}
+	}
+
+	if baseMatch != "" {
+		return baseMatch, true
+	}
+
+	if regionalMatch != "" {
+		return regionalMatch, true
+	}
+
+	return "", false
+}
@@ -2,9 +2,12 @@ package common
 
 import (
 	"html"
+	"strings"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/pivotal-golang/conceal"
 )
 
@@ -20,6 +23,14 @@ type Options struct {
 	Role              string
 	Endorsement       string
 	TemplateID        string
+	TemplateVersion   int
+	AttachmentIDs     []string
+	ThreadKey         string
+	Headers           map[string]string
+	From              string
+	Transport         string
+	Localizations     map[string]LocalizedContent
+	Data              map[string]interface{}
 }
 
 type Delivery struct {
@@ -35,6 +46,44 @@ type Delivery struct {
 	VCAPRequestID   string
 	RequestReceived time.Time
 	CampaignID      string
+	Locale          string
+	Profile         uaa.User
+}
+
+// User describes the recipient of a message to its templates. Attributes UAA
+// does not know about are left empty, and Name falls back from the full name
+// to the username and then to the email address, so it is always safe to use
+// in a greeting.
+type User struct {
+	GUID       string
+	Email      string
+	UserName   string
+	GivenName  string
+	FamilyName string
+	Name       string
+	Locale     string
+}
+
+func newUser(delivery Delivery) User {
+	profile := delivery.Profile
+	name := strings.TrimSpace(profile.GivenName + " " + profile.FamilyName)
+	if name == "" {
+		name = profile.UserName
+	}
+
+	if name == "" {
+		name = delivery.Email
+	}
+
+	return User{
+		GUID:       delivery.UserGUID,
+		Email:      delivery.Email,
+		UserName:   profile.UserName,
+		GivenName:  profile.GivenName,
+		FamilyName: profile.FamilyName,
+		Name:       name,
+		Locale:     delivery.Locale,
+	}
 }
 
 type Templates struct {
@@ -42,6 +91,9 @@ type Templates struct {
 	Subject string
 	Text    string
 	HTML    string
+
+	DisableAutoText    bool
+	DisableCSSInlining bool
 }
 
 type HTML struct {
@@ -52,35 +104,48 @@ type HTML struct {
 }
 
 type MessageContext struct {
-	From              string
-	ReplyTo           string
-	To                string
-	Subject           string
-	Text              string
-	HTML              string
-	HTMLComponents    HTML
-	TextTemplate      string
-	HTMLTemplate      string
-	SubjectTemplate   string
-	KindDescription   string
-	SourceDescription string
-	UserGUID          string
-	ClientID          string
-	MessageID         string
-	Space             string
-	SpaceGUID         string
-	Organization      string
-	OrganizationGUID  string
-	UnsubscribeID     string
-	Scope             string
-	Endorsement       string
-	OrganizationRole  string
-	RequestReceived   time.Time
-	Domain            string
+	From               string
+	Sender             string
+	ReplyTo            string
+	To                 string
+	Subject            string
+	Text               string
+	HTML               string
+	HTMLComponents     HTML
+	TextTemplate       string
+	HTMLTemplate       string
+	SubjectTemplate    string
+	KindDescription    string
+	SourceDescription  string
+	UserGUID           string
+	ClientID           string
+	MessageID          string
+	Space              string
+	SpaceGUID          string
+	Organization       string
+	OrganizationGUID   string
+	UnsubscribeID      string
+	ListUnsubscribeURL string
+	ThreadKey          string
+	Threading          bool
+	Critical           bool
+	Transport          string
+	Headers            map[string]string
+	Scope              string
+	Endorsement        string
+	OrganizationRole   string
+	RequestReceived    time.Time
+	Domain             string
+	Locale             string
+	Attachments        []mail.Attachment
+	User               User
+	Data               map[string]interface{}
+	DisableAutoText    bool
+	DisableCSSInlining bool
 }
 
 func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.CloakInterface, templates Templates) MessageContext {
-	options := delivery.Options
+	options := delivery.Options.localize(delivery.Locale)
 
 	var kindDescription string
 	if options.KindDescription == "" {
@@ -96,31 +161,45 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 		sourceDescription = options.SourceDescription
 	}
 
+	from := sender
+	if options.From != "" {
+		from = options.From
+	}
+
 	messageContext := MessageContext{
-		From:              sender,
-		ReplyTo:           options.ReplyTo,
-		To:                delivery.Email,
-		Subject:           options.Subject,
-		Text:              options.Text,
-		HTML:              options.HTML.BodyContent,
-		HTMLComponents:    options.HTML,
-		TextTemplate:      templates.Text,
-		HTMLTemplate:      templates.HTML,
-		SubjectTemplate:   templates.Subject,
-		KindDescription:   kindDescription,
-		SourceDescription: sourceDescription,
-		UserGUID:          delivery.UserGUID,
-		ClientID:          delivery.ClientID,
-		MessageID:         delivery.MessageID,
-		Space:             delivery.Space.Name,
-		SpaceGUID:         delivery.Space.GUID,
-		Organization:      delivery.Organization.Name,
-		OrganizationGUID:  delivery.Organization.GUID,
-		Scope:             delivery.Scope,
-		Endorsement:       options.Endorsement,
-		OrganizationRole:  options.Role,
-		RequestReceived:   delivery.RequestReceived,
-		Domain:            domain,
+		From:               from,
+		Sender:             sender,
+		ReplyTo:            options.ReplyTo,
+		To:                 delivery.Email,
+		Subject:            options.Subject,
+		Text:               options.Text,
+		HTML:               options.HTML.BodyContent,
+		HTMLComponents:     options.HTML,
+		TextTemplate:       templates.Text,
+		HTMLTemplate:       templates.HTML,
+		SubjectTemplate:    templates.Subject,
+		KindDescription:    kindDescription,
+		SourceDescription:  sourceDescription,
+		UserGUID:           delivery.UserGUID,
+		ClientID:           delivery.ClientID,
+		MessageID:          delivery.MessageID,
+		Space:              delivery.Space.Name,
+		SpaceGUID:          delivery.Space.GUID,
+		Organization:       delivery.Organization.Name,
+		OrganizationGUID:   delivery.Organization.GUID,
+		Scope:              delivery.Scope,
+		Endorsement:        options.Endorsement,
+		OrganizationRole:   options.Role,
+		RequestReceived:    delivery.RequestReceived,
+		Domain:             domain,
+		Locale:             delivery.Locale,
+		ThreadKey:          options.ThreadKey,
+		Transport:          options.Transport,
+		Headers:            options.Headers,
+		User:               newUser(delivery),
+		Data:               options.Data,
+		DisableAutoText:    templates.DisableAutoText,
+		DisableCSSInlining: templates.DisableCSSInlining,
 	}
 
 	if messageContext.Subject == "" {
@@ -149,4 +228,36 @@ func (context *MessageContext) Escape() {
 	context.Space = html.EscapeString(context.Space)
 	context.Organization = html.EscapeString(context.Organization)
 	context.Endorsement = html.EscapeString(context.Endorsement)
+	context.User.Email = html.EscapeString(context.User.Email)
+	context.User.UserName = html.EscapeString(context.User.UserName)
+	context.User.GivenName = html.EscapeString(context.User.GivenName)
+	context.User.FamilyName = html.EscapeString(context.User.FamilyName)
+	context.User.Name = html.EscapeString(context.User.Name)
+
+	if context.Data != nil {
+		context.Data = escapeData(context.Data).(map[string]interface{})
+	}
+}
+
+// escapeData returns a copy of client-supplied template data with every
+// string escaped, leaving the original untouched for the other parts.
+func escapeData(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return html.EscapeString(value)
+	case map[string]interface{}:
+		escaped := map[string]interface{}{}
+		for key, element := range value {
+			escaped[key] = escapeData(element)
+		}
+		return escaped
+	case []interface{}:
+		escaped := make([]interface{}, len(value))
+		for i, element := range value {
+			escaped[i] = escapeData(element)
+		}
+		return escaped
+	default:
+		return value
+	}
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/uaa"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -45,6 +46,8 @@ var _ = Describe("MessageContext", func() {
 			KindID:            "the-kind-id",
 			Endorsement:       "this is the endorsement",
 			Role:              "OrgRole",
+			Headers:           map[string]string{"X-Tracking-ID": "some-tracking-id"},
+			Data:              map[string]interface{}{"app_name": "some-app"},
 		}
 
 		reqReceived, _ = time.Parse(time.RFC3339Nano, "2015-06-08T14:40:12.207187819-07:00")
@@ -102,6 +105,62 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.OrganizationRole).To(Equal("OrgRole"))
 			Expect(context.RequestReceived).To(Equal(reqReceived))
 			Expect(context.Domain).To(Equal(domain))
+			Expect(context.Headers).To(Equal(map[string]string{"X-Tracking-ID": "some-tracking-id"}))
+			Expect(context.Data).To(Equal(map[string]interface{}{"app_name": "some-app"}))
+		})
+
+		Describe("the user", func() {
+			BeforeEach(func() {
+				delivery.Locale = "fr-CA"
+				delivery.Profile = uaa.User{
+					ID:         "the-user",
+					UserName:   "jdoe",
+					GivenName:  "Jane",
+					FamilyName: "Doe",
+					Locale:     "fr",
+				}
+			})
+
+			It("describes the recipient using their UAA profile", func() {
+				context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+
+				Expect(context.User).To(Equal(common.User{
+					GUID:       "the-user",
+					Email:      email,
+					UserName:   "jdoe",
+					GivenName:  "Jane",
+					FamilyName: "Doe",
+					Name:       "Jane Doe",
+					Locale:     "fr-CA",
+				}))
+			})
+
+			It("falls back to the username and then the email address for the name", func() {
+				delivery.Profile.GivenName = ""
+				delivery.Profile.FamilyName = ""
+				context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+				Expect(context.User.Name).To(Equal("jdoe"))
+
+				delivery.Profile = uaa.User{}
+				context = common.NewMessageContext(delivery, sender, domain, cloak, templates)
+				Expect(context.User.Name).To(Equal(email))
+				Expect(context.User.GivenName).To(BeEmpty())
+			})
+		})
+
+		It("uses the From option instead of the sender when it is present", func() {
+			delivery.Options.From = `"Billing" <billing@example.com>`
+			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+
+			Expect(context.From).To(Equal(`"Billing" <billing@example.com>`))
+			Expect(context.Sender).To(Equal(sender))
+		})
+
+		It("carries the transport option", func() {
+			delivery.Options.Transport = "billing"
+			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+
+			Expect(context.Transport).To(Equal("billing"))
 		})
 
 		It("falls back to Kind if KindDescription is missing", func() {
@@ -171,5 +230,24 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.Endorsement).To(Equal("this &amp; is the endorsement"))
 			Expect(context.OrganizationRole).To(Equal("OrgRole"))
 		})
+
+		It("html escapes the strings in the template data without changing the original", func() {
+			data := map[string]interface{}{
+				"app_name": "<my app>",
+				"quota":    map[string]interface{}{"owner": "R&D", "used": 90.0},
+				"tags":     []interface{}{"a<b", true},
+			}
+			delivery.Options.Data = data
+
+			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+			context.Escape()
+
+			Expect(context.Data).To(Equal(map[string]interface{}{
+				"app_name": "&lt;my app&gt;",
+				"quota":    map[string]interface{}{"owner": "R&amp;D", "used": 90.0},
+				"tags":     []interface{}{"a&lt;b", true},
+			}))
+			Expect(data["app_name"]).To(Equal("<my app>"))
+		})
 	})
 })
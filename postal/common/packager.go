@@ -1,10 +1,9 @@
 package common
 
 import (
-	"bytes"
+	"crypto/sha1"
 	"fmt"
 	"strings"
-	"text/template"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/mail"
@@ -20,28 +19,52 @@ const HTMLWrapperTemplate = `{{.HTMLComponents.Doctype}}
 </html>`
 
 type templatesLoader interface {
-	LoadTemplates(clientID, kindID, templateID string) (Templates, error)
+	LoadTemplates(clientID, kindID, templateID string, templateVersion int, locale string) (Templates, error)
+}
+
+type attachmentsLoader interface {
+	LoadAttachments(attachmentIDs []string) ([]mail.Attachment, error)
+}
+
+type htmlSanitizer interface {
+	Sanitize(clientID, fragment string) string
+	SanitizeAttributes(clientID, attributes string) string
 }
 
 type Packager struct {
-	templates templatesLoader
-	cloak     conceal.CloakInterface
+	templates   templatesLoader
+	attachments attachmentsLoader
+	cloak       conceal.CloakInterface
+	sanitizer   htmlSanitizer
+	limits      TemplateLimits
 }
 
-func NewPackager(templates templatesLoader, cloak conceal.CloakInterface) Packager {
+func NewPackager(templates templatesLoader, attachments attachmentsLoader, cloak conceal.CloakInterface, sanitizer htmlSanitizer) Packager {
 	return Packager{
-		templates: templates,
-		cloak:     cloak,
+		templates:   templates,
+		attachments: attachments,
+		cloak:       cloak,
+		sanitizer:   sanitizer,
+		limits:      DefaultTemplateLimits,
 	}
 }
 
 func (packager Packager) PrepareContext(delivery Delivery, sender, domain string) (MessageContext, error) {
-	templates, err := packager.templates.LoadTemplates(delivery.ClientID, delivery.Options.KindID, delivery.Options.TemplateID)
+	templates, err := packager.templates.LoadTemplates(delivery.ClientID, delivery.Options.KindID, delivery.Options.TemplateID, delivery.Options.TemplateVersion, delivery.Locale)
 	if err != nil {
 		return MessageContext{}, err
 	}
 
-	return NewMessageContext(delivery, sender, domain, packager.cloak, templates), nil
+	context := NewMessageContext(delivery, sender, domain, packager.cloak, templates)
+
+	if len(delivery.Options.AttachmentIDs) > 0 {
+		context.Attachments, err = packager.attachments.LoadAttachments(delivery.Options.AttachmentIDs)
+		if err != nil {
+			return MessageContext{}, err
+		}
+	}
+
+	return context, nil
 }
 
 func (packager Packager) Pack(context MessageContext) (mail.Message, error) {
@@ -50,61 +73,128 @@ func (packager Packager) Pack(context MessageContext) (mail.Message, error) {
 		return mail.Message{}, err
 	}
 
-	compiledSubject, err := packager.compileTemplate(context, context.SubjectTemplate, false)
+	compiledSubject, err := packager.compileTemplate("subject", context, context.SubjectTemplate, false)
 	if err != nil {
 		return mail.Message{}, err
 	}
 
+	headers := []string{
+		fmt.Sprintf("X-CF-Client-ID: %s", context.ClientID),
+		fmt.Sprintf("X-CF-Notification-ID: %s", context.MessageID),
+		fmt.Sprintf("X-CF-Notification-Timestamp: %s", time.Now().Format(time.RFC3339Nano)),
+		fmt.Sprintf("X-CF-Notification-Request-Received: %s", context.RequestReceived.Format(time.RFC3339Nano)),
+	}
+
+	if context.MessageID != "" && context.Domain != "" {
+		headers = append(headers, fmt.Sprintf("Message-ID: %s", MessageIDHeader(context.MessageID, context.Domain)))
+	}
+
+	if context.Threading && context.ThreadKey != "" {
+		threadID := ThreadMessageID(context.ClientID, context.ThreadKey, context.Domain)
+		headers = append(headers,
+			fmt.Sprintf("In-Reply-To: %s", threadID),
+			fmt.Sprintf("References: %s", threadID))
+	}
+
+	if context.ListUnsubscribeURL != "" {
+		headers = append(headers,
+			fmt.Sprintf("List-Unsubscribe: <%s>", context.ListUnsubscribeURL),
+			"List-Unsubscribe-Post: List-Unsubscribe=One-Click")
+	}
+
+	customHeaders := context.Headers
+	if context.Critical {
+		customHeaders = mail.MergeHeaders(mail.CriticalHeaders(), context.Headers)
+	}
+
+	headers = append(headers, mail.FormatHeaders(customHeaders)...)
+
 	return mail.Message{
-		From:    context.From,
-		ReplyTo: context.ReplyTo,
-		To:      context.To,
-		Subject: compiledSubject,
-		Body:    parts,
-		Headers: []string{
-			fmt.Sprintf("X-CF-Client-ID: %s", context.ClientID),
-			fmt.Sprintf("X-CF-Notification-ID: %s", context.MessageID),
-			fmt.Sprintf("X-CF-Notification-Timestamp: %s", time.Now().Format(time.RFC3339Nano)),
-			fmt.Sprintf("X-CF-Notification-Request-Received: %s", context.RequestReceived.Format(time.RFC3339Nano)),
-		},
+		From:        context.From,
+		ReplyTo:     context.ReplyTo,
+		To:          context.To,
+		Subject:     compiledSubject,
+		Body:        parts,
+		Attachments: context.Attachments,
+		Headers:     headers,
+		Transport:   context.Transport,
+
+		EnvelopeFrom: context.Sender,
 	}, nil
 }
 
+func MessageIDHeader(messageID, domain string) string {
+	return fmt.Sprintf("<%s@%s>", messageID, domain)
+}
+
+// ThreadMessageID derives a stable identifier for the thread named by a
+// client-supplied key. Every message sent with the same client and key
+// references it, so mail clients group them into one conversation.
+func ThreadMessageID(clientID, threadKey, domain string) string {
+	return fmt.Sprintf("<thread.%x@%s>", sha1.Sum([]byte(clientID+"|"+threadKey)), domain)
+}
+
+// ListUnsubscribeURL builds the one-click unsubscribe link advertised in the
+// List-Unsubscribe header. The version is passed as a query parameter since
+// mailbox providers cannot set the X-NOTIFICATIONS-VERSION header.
+func ListUnsubscribeURL(publicURL, unsubscribeID string, version int) string {
+	link := fmt.Sprintf("%s/list_unsubscribe/%s", strings.TrimSuffix(publicURL, "/"), unsubscribeID)
+	if version != 1 {
+		link = fmt.Sprintf("%s?version=%d", link, version)
+	}
+
+	return link
+}
+
 func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, error) {
 	var parts []mail.Part
 	var err error
 
-	context.Endorsement, err = packager.compileTemplate(context, context.Endorsement, false)
+	context.Endorsement, err = packager.compileTemplate("endorsement", context, context.Endorsement, false)
 	if err != nil {
 		return parts, err
 	}
 
-	if context.Text != "" {
-		plainText, err := packager.compileTemplate(context, context.TextTemplate, false)
+	var htmlPart string
+	if context.HTML != "" {
+		context.HTMLComponents.BodyContent, err = packager.compileTemplate("html", context, context.HTMLTemplate, true)
 		if err != nil {
 			return parts, err
 		}
 
-		parts = append(parts, mail.Part{
-			ContentType: "text/plain",
-			Content:     plainText,
-		})
-
-	}
-
-	if context.HTML != "" {
-		var err error
+		context.HTMLComponents = packager.sanitizeHTML(context.ClientID, context.HTMLComponents)
 
-		context.HTMLComponents.BodyContent, err = packager.compileTemplate(context, context.HTMLTemplate, true)
+		htmlPart, err = packager.compileTemplate("html", context, HTMLWrapperTemplate, true)
 		if err != nil {
 			return parts, err
 		}
 
-		htmlPart, err := packager.compileTemplate(context, HTMLWrapperTemplate, true)
+		if !context.DisableCSSInlining {
+			htmlPart, err = InlineCSS(htmlPart)
+			if err != nil {
+				return parts, err
+			}
+		}
+	}
+
+	if context.Text != "" {
+		plainText, err := packager.compileTemplate("text", context, context.TextTemplate, false)
 		if err != nil {
 			return parts, err
 		}
 
+		parts = append(parts, mail.Part{
+			ContentType: "text/plain",
+			Content:     plainText,
+		})
+	} else if htmlPart != "" && !context.DisableAutoText {
+		parts = append(parts, mail.Part{
+			ContentType: "text/plain",
+			Content:     HTMLToText(context.HTMLComponents.BodyContent),
+		})
+	}
+
+	if htmlPart != "" {
 		parts = append(parts, mail.Part{
 			ContentType: "text/html",
 			Content:     htmlPart,
@@ -114,20 +204,32 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 	return parts, nil
 }
 
-func (packager Packager) compileTemplate(context MessageContext, theTemplate string, escapeContext bool) (string, error) {
-	buffer := bytes.NewBuffer([]byte{})
+// sanitizeHTML holds the client-supplied parts of the HTML, as rendered into
+// the template, to the sanitization policy of the client.
+func (packager Packager) sanitizeHTML(clientID string, components HTML) HTML {
+	components.Head = packager.sanitizer.Sanitize(clientID, components.Head)
+	components.BodyAttributes = packager.sanitizer.SanitizeAttributes(clientID, components.BodyAttributes)
+	components.BodyContent = packager.sanitizer.Sanitize(clientID, components.BodyContent)
+
+	return components
+}
 
-	source, err := template.New("compileTemplate").Parse(theTemplate)
+func (packager Packager) compileTemplate(part string, context MessageContext, theTemplate string, escapeContext bool) (string, error) {
+	source, err := NewTemplate(part).Parse(theTemplate)
 	if err != nil {
-		return "", err
+		return "", NewTemplateCompileError(part, err)
 	}
 
 	if escapeContext {
 		context.Escape()
 	}
 
-	source.Execute(buffer, context)
-	compiledTemplate := strings.TrimSuffix(buffer.String(), "\n")
+	output, err := ExecuteTemplate(source, context, packager.limits)
+	if _, ok := err.(TemplateLimitError); ok {
+		return "", NewTemplateCompileError(part, err)
+	}
+
+	compiledTemplate := strings.TrimSuffix(output, "\n")
 
 	return compiledTemplate, nil
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/sanitize"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 
 	. "github.com/onsi/ginkgo"
@@ -15,17 +16,19 @@ import (
 
 var _ = Describe("Packager", func() {
 	var (
-		packager        common.Packager
-		context         common.MessageContext
-		client          mail.Client
-		templatesLoader *mocks.TemplatesLoader
-		delivery        common.Delivery
-		cloak           *mocks.Cloak
+		packager          common.Packager
+		context           common.MessageContext
+		client            mail.Client
+		templatesLoader   *mocks.TemplatesLoader
+		attachmentsLoader *mocks.AttachmentsLoader
+		delivery          common.Delivery
+		cloak             *mocks.Cloak
 	)
 
 	BeforeEach(func() {
 		client = mail.Client{}
 		templatesLoader = mocks.NewTemplatesLoader()
+		attachmentsLoader = mocks.NewAttachmentsLoader()
 		cloak = mocks.NewCloak()
 
 		delivery = common.Delivery{
@@ -45,12 +48,13 @@ var _ = Describe("Packager", func() {
 			},
 		}
 
-		packager = common.NewPackager(templatesLoader, cloak)
+		packager = common.NewPackager(templatesLoader, attachmentsLoader, cloak, sanitize.NewSanitizer(sanitize.StrictPolicy, nil))
 
 		requestReceivedTime, _ := time.Parse(time.RFC3339Nano, "2015-06-08T14:38:03.180764129-07:00")
 
 		context = common.MessageContext{
 			From:      "banana man",
+			Sender:    "no-reply@example.com",
 			ReplyTo:   "awesomeness",
 			To:        "endless monkeys",
 			Subject:   "we will be eaten",
@@ -102,6 +106,7 @@ var _ = Describe("Packager", func() {
 				UnsubscribeID: "some-encrypted-text",
 				Domain:        "example.com",
 				From:          "some-sender@example.com",
+				Sender:        "some-sender@example.com",
 				Subject:       "Some crazy subject",
 				UserGUID:      "some-user-guid",
 				ClientID:      "some-client-id",
@@ -118,9 +123,49 @@ var _ = Describe("Packager", func() {
 				SubjectTemplate:   "subject template: {{.Subject}}",
 				KindDescription:   "some-kind-id",
 				SourceDescription: "some-client-id",
+				User:              common.User{GUID: "some-user-guid"},
 			}))
 		})
 
+		It("does not load attachments when there are none", func() {
+			_, err := packager.PrepareContext(delivery, "some-sender", "some-domain")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attachmentsLoader.LoadAttachmentsCall.WasCalled).To(BeFalse())
+		})
+
+		Context("when the delivery has attachments", func() {
+			It("loads the attachments into the context", func() {
+				delivery.Options.AttachmentIDs = []string{"some-attachment-id", "another-attachment-id"}
+				attachmentsLoader.LoadAttachmentsCall.Returns.Attachments = []mail.Attachment{
+					{
+						Filename:    "invoice.pdf",
+						ContentType: "application/pdf",
+						Content:     []byte("some-content"),
+					},
+				}
+
+				context, err := packager.PrepareContext(delivery, "some-sender", "some-domain")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(attachmentsLoader.LoadAttachmentsCall.Receives.AttachmentIDs).To(Equal([]string{"some-attachment-id", "another-attachment-id"}))
+				Expect(context.Attachments).To(Equal([]mail.Attachment{
+					{
+						Filename:    "invoice.pdf",
+						ContentType: "application/pdf",
+						Content:     []byte("some-content"),
+					},
+				}))
+			})
+
+			It("returns an error when the attachments cannot be loaded", func() {
+				delivery.Options.AttachmentIDs = []string{"some-attachment-id"}
+				attachmentsLoader.LoadAttachmentsCall.Returns.Error = errors.New("some attachment error")
+
+				_, err := packager.PrepareContext(delivery, "some-sender", "some-domain")
+				Expect(err).To(MatchError(errors.New("some attachment error")))
+			})
+		})
+
 		Context("when the template cannot be loaded", func() {
 			It("returns an error", func() {
 				templatesLoader.LoadTemplatesCall.Returns.Error = errors.New("some error")
@@ -136,6 +181,7 @@ var _ = Describe("Packager", func() {
 			msg, err := packager.Pack(context)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(msg.From).To(Equal("banana man"))
+			Expect(msg.EnvelopeFrom).To(Equal("no-reply@example.com"))
 			Expect(msg.ReplyTo).To(Equal("awesomeness"))
 			Expect(msg.To).To(Equal("endless monkeys"))
 			Expect(msg.Subject).To(Equal("The Subject: we will be eaten"))
@@ -167,6 +213,157 @@ var _ = Describe("Packager", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(timestamp).To(BeTemporally("~", time.Now(), 2*time.Second))
 		})
+
+		It("includes the attachments from the context", func() {
+			context.Attachments = []mail.Attachment{
+				{
+					Filename:    "logo.png",
+					ContentType: "image/png",
+					ContentID:   "logo",
+					Content:     []byte("some-content"),
+				},
+			}
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Attachments).To(Equal(context.Attachments))
+		})
+
+		It("includes one-click unsubscribe headers when the context has a list unsubscribe URL", func() {
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Headers).To(HaveLen(4))
+
+			context.ListUnsubscribeURL = "https://notifications.example.com/list_unsubscribe/some-token"
+
+			msg, err = packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Headers).To(ContainElement("List-Unsubscribe: <https://notifications.example.com/list_unsubscribe/some-token>"))
+			Expect(msg.Headers).To(ContainElement("List-Unsubscribe-Post: List-Unsubscribe=One-Click"))
+		})
+
+		It("includes a Message-ID derived from the message ID and domain", func() {
+			context.MessageID = "some-message-id"
+			context.Domain = "example.com"
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Headers).To(ContainElement("Message-ID: <some-message-id@example.com>"))
+		})
+
+		It("includes threading headers when the context opts into threading with a thread key", func() {
+			context.Domain = "example.com"
+			context.ThreadKey = "some-thread"
+			context.Threading = true
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+
+			threadID := common.ThreadMessageID("3&3", "some-thread", "example.com")
+			Expect(msg.Headers).To(ContainElement("In-Reply-To: " + threadID))
+			Expect(msg.Headers).To(ContainElement("References: " + threadID))
+		})
+
+		It("omits threading headers when the context has not opted into threading", func() {
+			context.Domain = "example.com"
+			context.ThreadKey = "some-thread"
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, header := range msg.Headers {
+				Expect(header).NotTo(HavePrefix("In-Reply-To:"))
+				Expect(header).NotTo(HavePrefix("References:"))
+			}
+		})
+
+		It("routes the message through the context's transport", func() {
+			context.Transport = "billing"
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Transport).To(Equal("billing"))
+		})
+
+		It("includes the custom headers from the context", func() {
+			context.Headers = map[string]string{
+				"X-Tracking-ID":  "some-tracking-id",
+				"Auto-Submitted": "auto-generated",
+			}
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Headers).To(ContainElement("X-Tracking-ID: some-tracking-id"))
+			Expect(msg.Headers).To(ContainElement("Auto-Submitted: auto-generated"))
+		})
+
+		It("marks critical messages as important", func() {
+			context.Critical = true
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Headers).To(ContainElement("Importance: high"))
+			Expect(msg.Headers).To(ContainElement("Priority: urgent"))
+			Expect(msg.Headers).To(ContainElement("X-Priority: 1"))
+		})
+
+		It("lets custom headers override the critical defaults", func() {
+			context.Critical = true
+			context.Headers = map[string]string{
+				"importance": "normal",
+			}
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Headers).To(ContainElement("importance: normal"))
+			Expect(msg.Headers).NotTo(ContainElement("Importance: high"))
+			Expect(msg.Headers).To(ContainElement("Priority: urgent"))
+		})
+
+		It("does not mark other messages as important", func() {
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, header := range msg.Headers {
+				Expect(header).NotTo(HavePrefix("Importance:"))
+				Expect(header).NotTo(HavePrefix("Priority:"))
+			}
+		})
+
+		It("returns a compile error naming the template part that failed to parse", func() {
+			context.TextTemplate = "first line\n{{.Text"
+
+			_, err := packager.Pack(context)
+			Expect(err).To(BeAssignableToTypeOf(common.TemplateCompileError{}))
+
+			compileError := err.(common.TemplateCompileError)
+			Expect(compileError.Part).To(Equal("text"))
+			Expect(compileError.Line).To(Equal(2))
+			Expect(compileError.Error()).To(ContainSubstring("template: text:2:"))
+		})
+
+		It("returns a compile error when a template exceeds its limits", func() {
+			context.HTMLTemplate = "{{range 2000000}}<p>row</p>{{end}}"
+
+			_, err := packager.Pack(context)
+			Expect(err).To(MatchError(common.NewTemplateCompileError("html", common.TemplateLimitError{Err: errors.New("template: html: output exceeds the limit of 1048576 bytes")})))
+		})
+	})
+
+	Describe("ThreadMessageID", func() {
+		It("is stable for a client and thread key", func() {
+			threadID := common.ThreadMessageID("some-client", "some-thread", "example.com")
+			Expect(threadID).To(MatchRegexp(`^<thread\.[0-9a-f]{40}@example\.com>$`))
+			Expect(common.ThreadMessageID("some-client", "some-thread", "example.com")).To(Equal(threadID))
+			Expect(common.ThreadMessageID("other-client", "some-thread", "example.com")).NotTo(Equal(threadID))
+		})
+	})
+
+	Describe("ListUnsubscribeURL", func() {
+		It("builds a link to the one-click unsubscribe endpoint for the given API version", func() {
+			Expect(common.ListUnsubscribeURL("https://notifications.example.com/", "some-token", 1)).To(Equal("https://notifications.example.com/list_unsubscribe/some-token"))
+			Expect(common.ListUnsubscribeURL("https://notifications.example.com", "some-token", 2)).To(Equal("https://notifications.example.com/list_unsubscribe/some-token?version=2"))
+		})
 	})
 
 	Describe("CompileParts", func() {
@@ -218,15 +415,39 @@ This is an endorsement for the development space and banana org.`
 		})
 
 		Context("when no text is set", func() {
-			It("omits the plaintext portion of the email", func() {
+			BeforeEach(func() {
 				context.Text = ""
+			})
 
+			It("derives the plaintext portion from the html", func() {
 				parts, err := packager.CompileParts(context)
 				if err != nil {
 					panic(err)
 				}
 
-				htmlBody := `<!DOCTYPE html>
+				Expect(parts).To(HaveLen(2))
+				Expect(parts[0]).To(Equal(mail.Part{
+					ContentType: "text/plain",
+					Content: `This is an endorsement for the development space and banana org.
+Banana preamble
+
+user supplied banana html
+
+3&3 4'4 user-123`,
+				}))
+				Expect(parts[1].ContentType).To(Equal("text/html"))
+			})
+
+			Context("when automatic text is disabled for the template", func() {
+				It("omits the plaintext portion of the email", func() {
+					context.DisableAutoText = true
+
+					parts, err := packager.CompileParts(context)
+					if err != nil {
+						panic(err)
+					}
+
+					htmlBody := `<!DOCTYPE html>
 <head><title>The title</title></head>
 <html>
 	<body class="bananaBody">
@@ -234,12 +455,92 @@ This is an endorsement for the development space and banana org.`
 Banana preamble <p>user supplied banana html</p>  3&amp;3 4&#39;4 user-123
 	</body>
 </html>`
-				Expect(parts).To(ConsistOf([]mail.Part{
-					{
-						ContentType: "text/html",
-						Content:     htmlBody,
-					},
-				}))
+					Expect(parts).To(ConsistOf([]mail.Part{
+						{
+							ContentType: "text/html",
+							Content:     htmlBody,
+						},
+					}))
+				})
+			})
+		})
+
+		Context("when the html includes a stylesheet", func() {
+			BeforeEach(func() {
+				context.HTMLComponents.Head = "<style>p { color: red; } .note { font-weight: bold }</style>"
+				context.HTMLTemplate = `<p class="note" style="color: blue">{{.HTML}}</p>`
+			})
+
+			It("inlines the stylesheet into the matching elements", func() {
+				parts, err := packager.CompileParts(context)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(parts[1].ContentType).To(Equal("text/html"))
+				Expect(parts[1].Content).To(ContainSubstring(`<p class="note" style="color: blue; font-weight: bold">`))
+				Expect(parts[1].Content).To(ContainSubstring("<style>p { color: red; } .note { font-weight: bold }</style>"))
+			})
+
+			Context("when css inlining is disabled for the template", func() {
+				It("leaves the html as it was compiled", func() {
+					context.DisableCSSInlining = true
+
+					parts, err := packager.CompileParts(context)
+					if err != nil {
+						panic(err)
+					}
+
+					Expect(parts[1].Content).To(ContainSubstring(`<p class="note" style="color: blue">`))
+				})
+			})
+		})
+
+		Context("when the html contains markup the client's policy does not allow", func() {
+			BeforeEach(func() {
+				context.HTMLComponents.Head = `<title>The title</title><script src="https://tracker.example.com/t.js"></script>`
+				context.HTMLComponents.BodyAttributes = `class="bananaBody" onload="track()"`
+				context.HTML = `<a href="javascript:steal()">Sign in</a><iframe src="https://tracker.example.com"></iframe>`
+				context.HTMLTemplate = "<p>{{.HTML}}</p>"
+			})
+
+			It("sanitizes the html portion and the plaintext derived from it", func() {
+				context.Text = ""
+
+				parts, err := packager.CompileParts(context)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(parts[0].Content).To(Equal("Sign in"))
+				Expect(parts[1].Content).To(Equal(`<!DOCTYPE html>
+<head><title>The title</title></head>
+<html>
+	<body class="bananaBody">
+		<p><a>Sign in</a></p>
+	</body>
+</html>`))
+			})
+		})
+
+		Context("when the context has template data", func() {
+			BeforeEach(func() {
+				context.Data = map[string]interface{}{
+					"app_name": "<my-app>",
+					"quota":    map[string]interface{}{"used": 90},
+				}
+				context.TextTemplate = "{{.Data.app_name}} has used {{.Data.quota.used}}%"
+				context.HTMLTemplate = "<p>{{.Data.app_name}} has used {{.Data.quota.used}}%</p>"
+			})
+
+			It("makes the data available to each part, escaping it for the html portion", func() {
+				parts, err := packager.CompileParts(context)
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(parts[0].Content).To(Equal("<my-app> has used 90%"))
+				Expect(parts[1].Content).To(ContainSubstring("<p>&lt;my-app&gt; has used 90%</p>"))
 			})
 		})
 	})
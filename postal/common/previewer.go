@@ -0,0 +1,51 @@
+package common
+
+import "github.com/pivotal-golang/conceal"
+
+type Preview struct {
+	Subject string
+	Text    string
+	HTML    string
+	MIME    string
+}
+
+// Previewer renders templates through the same packaging path used for
+// delivery, without loading anything from the database or sending mail.
+type Previewer struct {
+	packager Packager
+	sender   string
+	domain   string
+}
+
+func NewPreviewer(cloak conceal.CloakInterface, sanitizer htmlSanitizer, sender, domain string) Previewer {
+	return Previewer{
+		packager: Packager{cloak: cloak, sanitizer: sanitizer, limits: DefaultTemplateLimits},
+		sender:   sender,
+		domain:   domain,
+	}
+}
+
+func (previewer Previewer) Preview(delivery Delivery, templates Templates) (Preview, error) {
+	context := NewMessageContext(delivery, previewer.sender, previewer.domain, previewer.packager.cloak, templates)
+
+	message, err := previewer.packager.Pack(context)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	preview := Preview{
+		Subject: message.Subject,
+		MIME:    message.Data(),
+	}
+
+	for _, part := range message.Body {
+		switch part.ContentType {
+		case "text/plain":
+			preview.Text = part.Content
+		case "text/html":
+			preview.HTML = part.Content
+		}
+	}
+
+	return preview, nil
+}
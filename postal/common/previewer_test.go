@@ -0,0 +1,63 @@
+package common_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/sanitize"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Previewer", func() {
+	var (
+		previewer common.Previewer
+		delivery  common.Delivery
+		templates common.Templates
+	)
+
+	BeforeEach(func() {
+		previewer = common.NewPreviewer(mocks.NewCloak(), sanitize.NewSanitizer(sanitize.StrictPolicy, nil), "no-reply@example.com", "example.com")
+
+		delivery = common.Delivery{
+			ClientID:     "some-client-id",
+			Email:        "user@example.com",
+			Organization: cf.CloudControllerOrganization{GUID: "some-org-guid", Name: "some-org"},
+			Space:        cf.CloudControllerSpace{GUID: "some-space-guid", Name: "some-space"},
+			Options: common.Options{
+				Subject:     "some subject",
+				Text:        "some text",
+				HTML:        common.HTML{BodyContent: "<p>some html</p>"},
+				Endorsement: "sent to {{.Space}} in {{.Organization}}",
+			},
+		}
+
+		templates = common.Templates{
+			Subject: "Preview: {{.Subject}}",
+			Text:    "{{.Text}}\n{{.Endorsement}}",
+			HTML:    "{{.HTML}}<footer>{{.Endorsement}}</footer>",
+		}
+	})
+
+	It("renders the subject, text, html and raw message", func() {
+		preview, err := previewer.Preview(delivery, templates)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(preview.Subject).To(Equal("Preview: some subject"))
+		Expect(preview.Text).To(Equal("some text\nsent to some-space in some-org"))
+		Expect(preview.HTML).To(ContainSubstring("<p>some html</p><footer>sent to some-space in some-org</footer>"))
+		Expect(preview.MIME).To(ContainSubstring("From: no-reply@example.com"))
+		Expect(preview.MIME).To(ContainSubstring("To: user@example.com"))
+		Expect(preview.MIME).To(ContainSubstring("Subject: Preview: some subject"))
+	})
+
+	It("returns template compile errors", func() {
+		templates.HTML = "<p>\n\n{{if}}</p>"
+
+		_, err := previewer.Preview(delivery, templates)
+		Expect(err).To(BeAssignableToTypeOf(common.TemplateCompileError{}))
+		Expect(err.(common.TemplateCompileError).Part).To(Equal("html"))
+		Expect(err.(common.TemplateCompileError).Line).To(Equal(3))
+	})
+})
@@ -0,0 +1,427 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"text/template"
+	"text/template/parse"
+	"time"
+	"unicode/utf8"
+)
+
+// TemplateLimits bound the work a template may do when it is executed. A
+// zero field leaves that limit off.
+type TemplateLimits struct {
+	MaxOutputBytes int
+	MaxDuration    time.Duration
+	MaxDepth       int
+}
+
+var DefaultTemplateLimits = TemplateLimits{
+	MaxOutputBytes: 1 << 20,
+	MaxDuration:    2 * time.Second,
+	MaxDepth:       20,
+}
+
+// TemplateLimitError reports a template that exceeded one of its limits.
+type TemplateLimitError struct {
+	Err error
+}
+
+func (e TemplateLimitError) Error() string {
+	return e.Err.Error()
+}
+
+// TemplateFuncs are the functions available to every template, in addition
+// to the text/template builtins.
+var TemplateFuncs = template.FuncMap{
+	"formatDate": formatDate,
+	"truncate":   truncate,
+	"pluralize":  pluralize,
+	"buildURL":   buildURL,
+}
+
+// NewTemplate returns an empty template with TemplateFuncs defined, ready
+// to be parsed.
+func NewTemplate(name string) *template.Template {
+	return template.New(name).Funcs(TemplateFuncs)
+}
+
+// ExecuteTemplate applies a parsed template to data within the limits.
+// Templates nested deeper than the limit are rejected before they run.
+// Execution stops at the first write past the output or time limit, or at
+// the next range iteration once the time limit has passed, so a template
+// that loops without writing does not outlive its limit.
+func ExecuteTemplate(source *template.Template, data interface{}, limits TemplateLimits) (string, error) {
+	if limits.MaxDepth > 0 {
+		checker := depthChecker{
+			source:   source,
+			depths:   map[string]int{},
+			visiting: map[string]bool{},
+		}
+
+		depth, err := checker.treeDepth(source.Name())
+		if err != nil {
+			return "", TemplateLimitError{fmt.Errorf("template: %s: %s", source.Name(), err)}
+		}
+
+		if depth > limits.MaxDepth {
+			return "", TemplateLimitError{fmt.Errorf("template: %s: nesting exceeds the limit of %d levels", source.Name(), limits.MaxDepth)}
+		}
+	}
+
+	writer := &limitedWriter{maxBytes: limits.MaxOutputBytes}
+	if limits.MaxDuration > 0 {
+		writer.deadline = time.Now().Add(limits.MaxDuration)
+
+		var err error
+		source, err = guardRanges(source, writer)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- source.Execute(writer, data)
+	}()
+
+	var err error
+	if limits.MaxDuration > 0 {
+		timer := time.NewTimer(limits.MaxDuration)
+		defer timer.Stop()
+
+		select {
+		case err = <-done:
+			if err != nil && writer.checkDeadline() != nil {
+				err = errExecutionTimeLimit
+			}
+		case <-timer.C:
+			writer.expire()
+			err = errExecutionTimeLimit
+		}
+	} else {
+		err = <-done
+	}
+
+	switch err {
+	case errOutputLimit:
+		return "", TemplateLimitError{fmt.Errorf("template: %s: output exceeds the limit of %d bytes", source.Name(), limits.MaxOutputBytes)}
+	case errExecutionTimeLimit:
+		return "", TemplateLimitError{fmt.Errorf("template: %s: execution exceeds the limit of %s", source.Name(), limits.MaxDuration)}
+	}
+
+	return writer.String(), err
+}
+
+var (
+	errOutputLimit        = errors.New("output limit exceeded")
+	errExecutionTimeLimit = errors.New("execution time limit exceeded")
+)
+
+const deadlineGuardFunc = "checkExecutionDeadline"
+
+// guardRanges returns a copy of the template whose range bodies each begin by
+// checking the writer's deadline. The source trees are left untouched, so a
+// parsed template can be executed more than once.
+func guardRanges(source *template.Template, writer *limitedWriter) (*template.Template, error) {
+	guarded, err := source.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	guarded.Funcs(template.FuncMap{
+		deadlineGuardFunc: func() (string, error) {
+			return "", writer.checkDeadline()
+		},
+	})
+
+	guardTemplate, err := template.New("guard").Funcs(template.FuncMap{
+		deadlineGuardFunc: func() string { return "" },
+	}).Parse("{{" + deadlineGuardFunc + "}}")
+	if err != nil {
+		return nil, err
+	}
+	guard := guardTemplate.Tree.Root.Nodes[0]
+
+	for _, named := range source.Templates() {
+		if named.Tree == nil {
+			continue
+		}
+
+		tree := named.Tree.Copy()
+		guardNode(tree.Root, guard)
+
+		_, err = guarded.AddParseTree(named.Name(), tree)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return guarded, nil
+}
+
+func guardNode(node parse.Node, guard parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			guardNode(child, guard)
+		}
+	case *parse.IfNode:
+		guardNode(n.List, guard)
+		guardNode(n.ElseList, guard)
+	case *parse.WithNode:
+		guardNode(n.List, guard)
+		guardNode(n.ElseList, guard)
+	case *parse.RangeNode:
+		guardNode(n.List, guard)
+		guardNode(n.ElseList, guard)
+		n.List.Nodes = append([]parse.Node{guard}, n.List.Nodes...)
+	}
+}
+
+type limitedWriter struct {
+	mutex    sync.Mutex
+	buffer   bytes.Buffer
+	maxBytes int
+	deadline time.Time
+	expired  bool
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.pastDeadline() {
+		return 0, errExecutionTimeLimit
+	}
+
+	if w.maxBytes > 0 && w.buffer.Len()+len(p) > w.maxBytes {
+		return 0, errOutputLimit
+	}
+
+	return w.buffer.Write(p)
+}
+
+func (w *limitedWriter) checkDeadline() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.pastDeadline() {
+		return errExecutionTimeLimit
+	}
+
+	return nil
+}
+
+func (w *limitedWriter) pastDeadline() bool {
+	return w.expired || (!w.deadline.IsZero() && time.Now().After(w.deadline))
+}
+
+func (w *limitedWriter) expire() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.expired = true
+}
+
+func (w *limitedWriter) String() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.buffer.String()
+}
+
+// depthChecker measures how deeply the control structures and template
+// calls of a template nest. The depth of each named template is measured
+// once, so templates that call one another many times are cheap to check.
+type depthChecker struct {
+	source   *template.Template
+	depths   map[string]int
+	visiting map[string]bool
+}
+
+func (c depthChecker) treeDepth(name string) (int, error) {
+	if depth, ok := c.depths[name]; ok {
+		return depth, nil
+	}
+
+	if c.visiting[name] {
+		return 0, fmt.Errorf("template %q includes itself", name)
+	}
+
+	named := c.source.Lookup(name)
+	if named == nil || named.Tree == nil {
+		return 0, nil
+	}
+
+	c.visiting[name] = true
+	depth, err := c.nodeDepth(named.Tree.Root)
+	c.visiting[name] = false
+	if err != nil {
+		return 0, err
+	}
+
+	c.depths[name] = depth
+	return depth, nil
+}
+
+func (c depthChecker) nodeDepth(node parse.Node) (int, error) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return 0, nil
+		}
+
+		var deepest int
+		for _, child := range n.Nodes {
+			depth, err := c.nodeDepth(child)
+			if err != nil {
+				return 0, err
+			}
+
+			if depth > deepest {
+				deepest = depth
+			}
+		}
+
+		return deepest, nil
+	case *parse.IfNode:
+		return c.branchDepth(&n.BranchNode)
+	case *parse.RangeNode:
+		return c.branchDepth(&n.BranchNode)
+	case *parse.WithNode:
+		return c.branchDepth(&n.BranchNode)
+	case *parse.TemplateNode:
+		depth, err := c.treeDepth(n.Name)
+		return depth + 1, err
+	default:
+		return 0, nil
+	}
+}
+
+// branchDepth counts the body of a branch one level deeper than the branch
+// itself. The else list stays at the same level, so that a chain of
+// {{else if}} clauses is not mistaken for nesting.
+func (c depthChecker) branchDepth(branch *parse.BranchNode) (int, error) {
+	body, err := c.nodeDepth(branch.List)
+	if err != nil {
+		return 0, err
+	}
+
+	alternative, err := c.nodeDepth(branch.ElseList)
+	if err != nil {
+		return 0, err
+	}
+
+	if alternative > body+1 {
+		return alternative, nil
+	}
+
+	return body + 1, nil
+}
+
+// formatDate formats a time, or a string holding an RFC 3339 time, using a
+// Go reference layout, as in {{.RequestReceived | formatDate "Jan 2, 2006"}}.
+// A missing or empty value formats as an empty string.
+func formatDate(layout string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case time.Time:
+		return v.Format(layout), nil
+	case string:
+		if v == "" {
+			return "", nil
+		}
+
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return "", fmt.Errorf("formatDate: %q is not an RFC 3339 time", v)
+		}
+
+		return t.Format(layout), nil
+	default:
+		return "", fmt.Errorf("formatDate: cannot format %T", value)
+	}
+}
+
+// truncate shortens text to at most length characters, ending it with an
+// ellipsis when anything was cut.
+func truncate(length int, text string) string {
+	if length < 1 {
+		return ""
+	}
+
+	if utf8.RuneCountInString(text) <= length {
+		return text
+	}
+
+	runes := []rune(text)
+	return string(runes[:length-1]) + "…"
+}
+
+// pluralize picks the singular or plural word for a count, which may be any
+// number, including those decoded from JSON. A missing count is treated as
+// zero. The count comes last so it can be piped in, as with formatDate and
+// truncate.
+func pluralize(singular, plural string, count interface{}) (string, error) {
+	var n float64
+	switch v := count.(type) {
+	case nil:
+		n = 0
+	case int:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case float64:
+		n = v
+	case string:
+		if v == "" {
+			break
+		}
+
+		var err error
+		n, err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			return "", fmt.Errorf("pluralize: %q is not a number", v)
+		}
+	default:
+		return "", fmt.Errorf("pluralize: cannot count %T", count)
+	}
+
+	if n == 1 {
+		return singular, nil
+	}
+
+	return plural, nil
+}
+
+// buildURL adds query parameters, given as alternating keys and values, to a
+// base URL, escaping each of them.
+func buildURL(base string, pairs ...string) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", errors.New("buildURL: query parameters must be given as key and value pairs")
+	}
+
+	link, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("buildURL: %q is not a valid URL", base)
+	}
+
+	query := link.Query()
+	for i := 0; i < len(pairs); i += 2 {
+		query.Add(pairs[i], pairs[i+1])
+	}
+	link.RawQuery = query.Encode()
+
+	return link.String(), nil
+}
@@ -0,0 +1,140 @@
+package common_test
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExecuteTemplate", func() {
+	execute := func(source string, data interface{}, limits common.TemplateLimits) (string, error) {
+		tmpl, err := common.NewTemplate("text").Parse(source)
+		Expect(err).NotTo(HaveOccurred())
+
+		return common.ExecuteTemplate(tmpl, data, limits)
+	}
+
+	It("executes templates within their limits", func() {
+		output, err := execute("Hello {{.}}", "world", common.DefaultTemplateLimits)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("Hello world"))
+	})
+
+	It("stops templates that write too much", func() {
+		_, err := execute("{{range 1000}}0123456789{{end}}", nil, common.TemplateLimits{MaxOutputBytes: 100})
+		Expect(err).To(MatchError(common.TemplateLimitError{Err: errors.New("template: text: output exceeds the limit of 100 bytes")}))
+	})
+
+	It("stops templates that run too long", func() {
+		_, err := execute("{{range 1000000000}}x{{end}}", nil, common.TemplateLimits{MaxDuration: 10 * time.Millisecond})
+		Expect(err).To(MatchError(common.TemplateLimitError{Err: errors.New("template: text: execution exceeds the limit of 10ms")}))
+	})
+
+	It("stops templates that loop without writing once they run too long", func() {
+		_, err := execute("{{range 1000000000}}{{range 1000000000}}{{end}}{{end}}", nil, common.TemplateLimits{MaxDuration: 10 * time.Millisecond})
+		Expect(err).To(MatchError(common.TemplateLimitError{Err: errors.New("template: text: execution exceeds the limit of 10ms")}))
+
+		executing := func() string {
+			stacks := make([]byte, 1<<20)
+			return string(stacks[:runtime.Stack(stacks, true)])
+		}
+		Eventually(executing).ShouldNot(ContainSubstring("text/template.(*state).walk"))
+	})
+
+	It("leaves the parsed template unchanged so that it can be executed again", func() {
+		tmpl, err := common.NewTemplate("text").Parse("{{range .}}{{.}}{{end}}")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = common.ExecuteTemplate(tmpl, []int{1, 2}, common.DefaultTemplateLimits)
+		Expect(err).NotTo(HaveOccurred())
+
+		output, err := common.ExecuteTemplate(tmpl, []int{3, 4}, common.DefaultTemplateLimits)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("34"))
+		Expect(tmpl.Tree.Root.String()).To(Equal("{{range .}}{{.}}{{end}}"))
+	})
+
+	It("rejects templates that nest too deeply", func() {
+		source := strings.Repeat("{{range .}}", 4) + strings.Repeat("{{end}}", 4)
+
+		_, err := execute(source, nil, common.TemplateLimits{MaxDepth: 4})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = execute(source, nil, common.TemplateLimits{MaxDepth: 3})
+		Expect(err).To(MatchError(common.TemplateLimitError{Err: errors.New("template: text: nesting exceeds the limit of 3 levels")}))
+	})
+
+	It("counts the templates a template includes towards its nesting", func() {
+		source := `{{template "outer" .}}{{define "outer"}}{{range .}}{{template "inner" .}}{{end}}{{end}}{{define "inner"}}{{if .}}x{{end}}{{end}}`
+
+		_, err := execute(source, nil, common.TemplateLimits{MaxDepth: 4})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = execute(source, nil, common.TemplateLimits{MaxDepth: 3})
+		Expect(err).To(BeAssignableToTypeOf(common.TemplateLimitError{}))
+	})
+
+	It("does not count a chain of else if clauses as nesting", func() {
+		source := "{{if eq . 1}}one{{else if eq . 2}}two{{else if eq . 3}}three{{else}}many{{end}}"
+
+		output, err := execute(source, 3, common.TemplateLimits{MaxDepth: 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(Equal("three"))
+	})
+
+	It("rejects templates that include themselves", func() {
+		_, err := execute(`{{define "loop"}}{{template "loop" .}}{{end}}{{template "loop" .}}`, nil, common.DefaultTemplateLimits)
+		Expect(err).To(MatchError(common.TemplateLimitError{Err: errors.New(`template: text: template "loop" includes itself`)}))
+	})
+
+	It("returns other execution errors with the partial output", func() {
+		output, err := execute("before {{.Missing}}", struct{}{}, common.DefaultTemplateLimits)
+		Expect(err).To(HaveOccurred())
+		Expect(err).NotTo(BeAssignableToTypeOf(common.TemplateLimitError{}))
+		Expect(output).To(Equal("before "))
+	})
+
+	Describe("functions", func() {
+		examples := []struct {
+			source string
+			data   interface{}
+			output string
+		}{
+			{`{{formatDate "Jan 2, 2006" .}}`, time.Date(2015, time.June, 8, 0, 0, 0, 0, time.UTC), "Jun 8, 2015"},
+			{`{{. | formatDate "2006-01-02"}}`, "2015-06-08T14:38:03Z", "2015-06-08"},
+			{`{{truncate 5 .}}`, "abc", "abc"},
+			{`{{truncate 5 .}}`, "abcdefgh", "abcd…"},
+			{`{{pluralize "file" "files" .}}`, 1, "file"},
+			{`{{. | pluralize "file" "files"}}`, float64(3), "files"},
+			{`{{formatDate "Jan 2, 2006" .missing}}`, map[string]interface{}{}, ""},
+			{`{{.missing | formatDate "Jan 2, 2006"}}`, map[string]interface{}{"missing": ""}, ""},
+			{`{{pluralize "file" "files" .missing}}`, map[string]interface{}{}, "files"},
+			{`{{.missing | pluralize "file" "files"}}`, map[string]interface{}{"missing": ""}, "files"},
+			{`{{buildURL "https://example.com/apps?sort=name" "space" . "page" "2"}}`, "my space", "https://example.com/apps?page=2&sort=name&space=my+space"},
+		}
+
+		for _, example := range examples {
+			example := example
+
+			It("renders "+example.source, func() {
+				output, err := execute(example.source, example.data, common.DefaultTemplateLimits)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output).To(Equal(example.output))
+			})
+		}
+
+		It("reports values the functions cannot handle", func() {
+			_, err := execute(`{{formatDate "2006" .}}`, "yesterday", common.DefaultTemplateLimits)
+			Expect(err).To(MatchError(ContainSubstring(`formatDate: "yesterday" is not an RFC 3339 time`)))
+
+			_, err = execute(`{{buildURL "https://example.com" "key"}}`, nil, common.DefaultTemplateLimits)
+			Expect(err).To(MatchError(ContainSubstring("buildURL: query parameters must be given as key and value pairs")))
+		})
+	})
+})
@@ -0,0 +1,169 @@
+package common
+
+import (
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const MissingUnsubscribeLinkWarning = "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe"
+
+var templateValidationContext = MessageContext{
+	From:               "no-reply@example.com",
+	ReplyTo:            "reply-to@example.com",
+	To:                 "user@example.com",
+	Subject:            "subject",
+	Text:               "text",
+	HTML:               "<p>html</p>",
+	HTMLComponents:     HTML{BodyContent: "<p>html</p>"},
+	KindDescription:    "kind",
+	SourceDescription:  "source",
+	UserGUID:           "user-guid",
+	ClientID:           "client-id",
+	MessageID:          "message-id",
+	Space:              "space",
+	SpaceGUID:          "space-guid",
+	Organization:       "organization",
+	OrganizationGUID:   "organization-guid",
+	UnsubscribeID:      "unsubscribe-id",
+	ListUnsubscribeURL: "https://notifications.example.com/list_unsubscribe/unsubscribe-id",
+	ThreadKey:          "thread-key",
+	Headers:            map[string]string{},
+	Scope:              "scope",
+	Endorsement:        "endorsement",
+	OrganizationRole:   "OrgManager",
+	RequestReceived:    time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC),
+	Domain:             "example.com",
+	Locale:             "en",
+	Data:               map[string]interface{}{},
+	User: User{
+		GUID:       "user-guid",
+		Email:      "user@example.com",
+		UserName:   "user",
+		GivenName:  "Given",
+		FamilyName: "Family",
+		Name:       "Given Family",
+		Locale:     "en",
+	},
+}
+
+// TemplateValidationError lists every template part that failed to parse or
+// referenced something the message context does not provide.
+type TemplateValidationError struct {
+	Errors []TemplateCompileError
+}
+
+func (e TemplateValidationError) Messages() []string {
+	var messages []string
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return messages
+}
+
+func (e TemplateValidationError) Error() string {
+	return strings.Join(e.Messages(), "; ")
+}
+
+// ValidateTemplates parses each part and executes it against a sample
+// message, so mistakes are caught when a template is saved instead of when
+// it is delivered. Problems that do not prevent delivery are returned as
+// warnings.
+func ValidateTemplates(templates Templates) ([]string, error) {
+	return ValidateLocalizedTemplates(templates, nil, false)
+}
+
+// ValidateLocalizedTemplates validates the templates along with every part
+// set by their locale variants. Errors in a variant name the locale, e.g.
+// "localizations.fr.subject". Templates that only critical notifications use
+// are not warned about a missing unsubscribe link, since their recipients
+// cannot unsubscribe anyway.
+func ValidateLocalizedTemplates(templates Templates, localizations Localizations, critical bool) ([]string, error) {
+	var validationError TemplateValidationError
+
+	validationError.Errors = append(validationError.Errors, validateParts("", templates)...)
+
+	var locales []string
+	for locale := range localizations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		localization := localizations[locale]
+		validationError.Errors = append(validationError.Errors, validateParts("localizations."+locale+".", Templates{
+			Subject: localization.Subject,
+			Text:    localization.Text,
+			HTML:    localization.HTML,
+		})...)
+	}
+
+	if len(validationError.Errors) > 0 {
+		return nil, validationError
+	}
+
+	var warnings []string
+	if !critical && !hasUnsubscribeLink(templates.Text) && !hasUnsubscribeLink(templates.HTML) {
+		warnings = append(warnings, MissingUnsubscribeLinkWarning)
+	}
+
+	return warnings, nil
+}
+
+func validateParts(prefix string, templates Templates) []TemplateCompileError {
+	var errors []TemplateCompileError
+
+	parts := []struct {
+		name   string
+		source string
+	}{
+		{"subject", templates.Subject},
+		{"text", templates.Text},
+		{"html", templates.HTML},
+	}
+
+	for _, part := range parts {
+		if prefix != "" && part.source == "" {
+			continue
+		}
+
+		source, err := NewTemplate(prefix + part.name).Parse(part.source)
+		if err != nil {
+			errors = append(errors, NewTemplateCompileError(prefix+part.name, err))
+			continue
+		}
+
+		for _, name := range TemplateReferences(part.source) {
+			if source.Lookup(name) == nil {
+				template.Must(source.New(name).Parse(""))
+			}
+		}
+
+		_, err = ExecuteTemplate(source, templateValidationContext, DefaultTemplateLimits)
+		if err != nil {
+			errors = append(errors, NewTemplateCompileError(prefix+part.name, err))
+		}
+	}
+
+	return errors
+}
+
+// ValidatePartial checks that each part of a partial compiles against a
+// sample message.
+func ValidatePartial(partial Partial) error {
+	errors := validateParts("", Templates{
+		Text: partial.Text,
+		HTML: partial.HTML,
+	})
+	if len(errors) > 0 {
+		return TemplateValidationError{Errors: errors}
+	}
+
+	return nil
+}
+
+func hasUnsubscribeLink(source string) bool {
+	return strings.Contains(source, ".UnsubscribeID") || strings.Contains(source, ".ListUnsubscribeURL")
+}
@@ -0,0 +1,131 @@
+package common_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateTemplates", func() {
+	It("accepts templates that compile against a message", func() {
+		warnings, err := common.ValidateTemplates(common.Templates{
+			Subject: "Notice: {{.Subject}}",
+			Text:    "{{.Text}}\n{{.Endorsement}}\nUnsubscribe: {{.ListUnsubscribeURL}}",
+			HTML:    "{{if .HTML}}{{.HTML}}{{end}}<a href=\"{{.ListUnsubscribeURL}}\">unsubscribe</a>",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("returns every syntax error and unknown field with its part and line", func() {
+		_, err := common.ValidateTemplates(common.Templates{
+			Subject: "{{.Subjct}}",
+			Text:    "first line\n{{.Text",
+			HTML:    "<p>{{.HTML}}</p>",
+		})
+		Expect(err).To(BeAssignableToTypeOf(common.TemplateValidationError{}))
+
+		validationError := err.(common.TemplateValidationError)
+		Expect(validationError.Errors).To(HaveLen(2))
+
+		Expect(validationError.Errors[0].Part).To(Equal("subject"))
+		Expect(validationError.Errors[0].Line).To(Equal(1))
+		Expect(validationError.Errors[0].Error()).To(ContainSubstring("can't evaluate field Subjct"))
+
+		Expect(validationError.Errors[1].Part).To(Equal("text"))
+		Expect(validationError.Errors[1].Line).To(Equal(2))
+
+		Expect(validationError.Messages()).To(HaveLen(2))
+	})
+
+	It("warns when neither the text nor the html includes an unsubscribe link", func() {
+		warnings, err := common.ValidateTemplates(common.Templates{
+			Subject: "{{.Subject}}",
+			Text:    "{{.Text}}",
+			HTML:    "{{.HTML}}",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(common.MissingUnsubscribeLinkWarning))
+	})
+
+	It("does not warn about the unsubscribe link for templates only critical notifications use", func() {
+		warnings, err := common.ValidateLocalizedTemplates(common.Templates{
+			Subject: "{{.Subject}}",
+			Text:    "{{.Text}}",
+			HTML:    "{{.HTML}}",
+		}, nil, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("accepts templates that include partials and layout content defined elsewhere", func() {
+		_, err := common.ValidateTemplates(common.Templates{
+			Subject: "{{template \"prefix\" .}} {{.Subject}}",
+			Text:    "{{template \"content\" .}}\n{{template \"footer\" .}}",
+			HTML:    "{{.HTML}}",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects templates that exceed their limits", func() {
+		_, err := common.ValidateTemplates(common.Templates{
+			Subject: "{{.Subject}}",
+			Text:    "{{range 2000000}}{{$.Text}}{{end}}",
+			HTML:    "{{.HTML}}",
+		})
+		Expect(err).To(MatchError("template: text: output exceeds the limit of 1048576 bytes"))
+	})
+
+	It("accepts templates that use the template functions", func() {
+		_, err := common.ValidateTemplates(common.Templates{
+			Subject: "{{truncate 40 .Subject}}",
+			Text:    "Received {{.RequestReceived | formatDate \"Jan 2, 2006\"}}",
+			HTML:    "<a href=\"{{buildURL \"https://example.com\" \"space\" .Space}}\">{{pluralize \"app\" \"apps\" 2}}</a>",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("accepts formatDate on data the sample message does not include", func() {
+		_, err := common.ValidateTemplates(common.Templates{
+			Subject: "{{.Subject}}",
+			Text:    "Expires {{.Data.expires_at | formatDate \"Jan 2, 2006\"}}",
+			HTML:    "<p>Expires {{formatDate \"Jan 2, 2006\" .Data.expires_at}}</p>",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("accepts pluralize on data the sample message does not include", func() {
+		_, err := common.ValidateTemplates(common.Templates{
+			Subject: "{{.Subject}}",
+			Text:    "{{.Data.count}} {{.Data.count | pluralize \"app\" \"apps\"}}",
+			HTML:    "<p>{{.Data.count}} {{.Data.count | pluralize \"app\" \"apps\"}}</p>",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("ValidatePartial", func() {
+	It("accepts partials that compile against a message", func() {
+		err := common.ValidatePartial(common.Partial{
+			Name: "footer",
+			Text: "Sent to {{.To}}",
+			HTML: "<p>Sent to {{.To}}</p>{{template \"legal\" .}}",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns the parts that fail to compile", func() {
+		err := common.ValidatePartial(common.Partial{
+			Name: "footer",
+			Text: "{{.Nope}}",
+			HTML: "<p>{{.To</p>",
+		})
+		Expect(err).To(BeAssignableToTypeOf(common.TemplateValidationError{}))
+
+		validationError := err.(common.TemplateValidationError)
+		Expect(validationError.Errors).To(HaveLen(2))
+		Expect(validationError.Errors[0].Part).To(Equal("text"))
+		Expect(validationError.Errors[1].Part).To(Equal("html"))
+	})
+})
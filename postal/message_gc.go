@@ -12,8 +12,13 @@ type messagesDeleter interface {
 	DeleteBefore(models.ConnectionInterface, time.Time) (int, error)
 }
 
+type attachmentsDeleter interface {
+	DeleteUnreferencedBefore(models.ConnectionInterface, time.Time) (int, error)
+}
+
 type MessageGC struct {
 	messages        messagesDeleter
+	attachments     attachmentsDeleter
 	db              db.DatabaseInterface
 	lifetime        time.Duration
 	logger          *log.Logger
@@ -21,9 +26,10 @@ type MessageGC struct {
 	pollingInterval time.Duration
 }
 
-func NewMessageGC(lifetime time.Duration, db db.DatabaseInterface, messages messagesDeleter, pollingInterval time.Duration, logger *log.Logger) MessageGC {
+func NewMessageGC(lifetime time.Duration, db db.DatabaseInterface, messages messagesDeleter, attachments attachmentsDeleter, pollingInterval time.Duration, logger *log.Logger) MessageGC {
 	return MessageGC{
 		messages:        messages,
+		attachments:     attachments,
 		db:              db,
 		lifetime:        lifetime,
 		logger:          logger,
@@ -38,6 +44,11 @@ func (gc MessageGC) Collect() {
 	if err != nil {
 		gc.logger.Printf("MessageGC.Collect() failed: " + err.Error())
 	}
+
+	_, err = gc.attachments.DeleteUnreferencedBefore(gc.db.Connection(), threshold)
+	if err != nil {
+		gc.logger.Printf("MessageGC.Collect() failed to delete attachments: " + err.Error())
+	}
 }
 
 func (gc MessageGC) Run() {
@@ -18,6 +18,7 @@ var _ = Describe("MessageGC", func() {
 	var (
 		messageGC       postal.MessageGC
 		repo            *mocks.MessagesRepo
+		attachmentsRepo *mocks.AttachmentsRepo
 		oldMessageID    string
 		newMessageID    string
 		database        *mocks.Database
@@ -36,13 +37,14 @@ var _ = Describe("MessageGC", func() {
 		database.ConnectionCall.Returns.Connection = conn
 
 		repo = mocks.NewMessagesRepo()
+		attachmentsRepo = mocks.NewAttachmentsRepo()
 
 		lifetime = 2 * time.Minute
 		pollingInterval = 500 * time.Millisecond
 		oldMessageID = "that-message"
 		newMessageID = "this-message"
 
-		messageGC = postal.NewMessageGC(lifetime, database, repo, pollingInterval, logger)
+		messageGC = postal.NewMessageGC(lifetime, database, repo, attachmentsRepo, pollingInterval, logger)
 	})
 
 	Describe("Run", func() {
@@ -78,5 +80,22 @@ var _ = Describe("MessageGC", func() {
 			})
 		})
 
+		It("deletes attachments older than the specified time that no job references", func() {
+			messageGC.Collect()
+
+			Expect(attachmentsRepo.DeleteUnreferencedBeforeCall.Receives.Connection).To(Equal(conn))
+			Expect(attachmentsRepo.DeleteUnreferencedBeforeCall.Receives.ThresholdTime).To(BeTemporally("~", time.Now().Add(-2*time.Minute), 10*time.Second))
+		})
+
+		Context("when deleting attachments fails", func() {
+			It("logs the error", func() {
+				attachmentsRepo.DeleteUnreferencedBeforeCall.Returns.Error = errors.New("attachments table is gone")
+
+				messageGC.Collect()
+
+				Expect(loggerBuffer.String()).To(ContainSubstring("attachments table is gone"))
+			})
+		})
+
 	})
 })
@@ -0,0 +1,134 @@
+package postal
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/pivotal-golang/lager"
+)
+
+type MailSender interface {
+	Connect(lager.Logger) error
+	Send(mail.Message, lager.Logger) error
+}
+
+type transportsGetter interface {
+	Get(conn collections.ConnectionInterface, name string) (collections.Transport, error)
+}
+
+type routedClient struct {
+	config mail.Config
+	client MailSender
+}
+
+// TransportRouter sends messages that name a transport through a client
+// configured for that transport, and every other message through the
+// platform relays. Clients are cached per transport and rebuilt when the
+// stored configuration changes.
+type TransportRouter struct {
+	platform   MailSender
+	transports transportsGetter
+	database   db.DatabaseInterface
+	config     mail.Config
+	newClient  func(mail.Config) MailSender
+	clients    map[string]routedClient
+}
+
+func NewTransportRouter(platform MailSender, transports transportsGetter, database db.DatabaseInterface,
+	config mail.Config, newClient func(mail.Config) MailSender) *TransportRouter {
+
+	return &TransportRouter{
+		platform:   platform,
+		transports: transports,
+		database:   database,
+		config:     config,
+		newClient:  newClient,
+		clients:    map[string]routedClient{},
+	}
+}
+
+// Connect is a no-op: the relay to connect to depends on the message, so
+// connections are made in Send.
+func (r *TransportRouter) Connect(logger lager.Logger) error {
+	return nil
+}
+
+func (r *TransportRouter) Send(message mail.Message, logger lager.Logger) error {
+	if message.Transport == "" {
+		return r.platform.Send(message, logger)
+	}
+
+	logger = logger.Session("transport", lager.Data{"transport": message.Transport})
+
+	client, err := r.client(message.Transport)
+	if err != nil {
+		logger.Error("transport-lookup-failed", err)
+		return err
+	}
+
+	err = client.Connect(logger)
+	if err != nil {
+		return err
+	}
+
+	return client.Send(message, logger)
+}
+
+func (r *TransportRouter) client(name string) (MailSender, error) {
+	transport, err := r.transports.Get(r.database.Connection(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	config := r.config
+	config.Host = transport.Host
+	config.Port = transport.Port
+	config.User = transport.User
+	config.Pass = transport.Pass
+	config.Secret = transport.CRAMMD5Secret
+	config.Token = transport.XOAUTH2Token
+	config.AuthMechanism = authMechanism(transport.AuthMechanism)
+	config.TLSMode = tlsMode(transport.TLSMode)
+
+	if routed, ok := r.clients[name]; ok && routed.config == config {
+		return routed.client, nil
+	}
+
+	client := r.newClient(config)
+	r.clients[name] = routedClient{
+		config: config,
+		client: client,
+	}
+
+	return client, nil
+}
+
+func newMailClient(config mail.Config) MailSender {
+	return mail.NewClient(config)
+}
+
+func authMechanism(mechanism string) mail.AuthMechanism {
+	switch mechanism {
+	case "plain":
+		return mail.AuthPlain
+	case "cram-md5":
+		return mail.AuthCRAMMD5
+	case "login":
+		return mail.AuthLogin
+	case "xoauth2":
+		return mail.AuthXOAUTH2
+	default:
+		return mail.AuthNone
+	}
+}
+
+func tlsMode(mode string) mail.TLSMode {
+	switch mode {
+	case "none":
+		return mail.TLSModeNone
+	case "implicit":
+		return mail.TLSModeImplicit
+	default:
+		return mail.TLSModeStartTLS
+	}
+}
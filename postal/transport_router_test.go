@@ -0,0 +1,147 @@
+package postal_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransportRouter", func() {
+	var (
+		router               *postal.TransportRouter
+		platform             *mocks.MailClient
+		transportClient      *mocks.MailClient
+		transportsCollection *mocks.TransportsCollection
+		connection           *mocks.Connection
+		clientConfigs        []mail.Config
+		logger               lager.Logger
+	)
+
+	BeforeEach(func() {
+		platform = mocks.NewMailClient()
+		transportClient = mocks.NewMailClient()
+		transportsCollection = mocks.NewTransportsCollection()
+		transportsCollection.GetCall.Returns.Transport = collections.Transport{
+			Name:          "billing",
+			Host:          "smtp.example.com",
+			Port:          "465",
+			User:          "billing-user",
+			Pass:          "billing-pass",
+			AuthMechanism: "plain",
+			TLSMode:       "implicit",
+		}
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		clientConfigs = []mail.Config{}
+		newClient := func(config mail.Config) postal.MailSender {
+			clientConfigs = append(clientConfigs, config)
+			return transportClient
+		}
+
+		logger = lager.NewLogger("notifications")
+		router = postal.NewTransportRouter(platform, transportsCollection, database, mail.Config{
+			TestMode:       true,
+			LoggingEnabled: true,
+		}, newClient)
+	})
+
+	Context("when the message does not name a transport", func() {
+		It("sends the message through the platform relays", func() {
+			err := router.Send(mail.Message{Subject: "platform"}, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(platform.SendCall.Receives.Message).To(Equal(mail.Message{Subject: "platform"}))
+			Expect(transportClient.SendCall.CallCount).To(Equal(0))
+			Expect(clientConfigs).To(BeEmpty())
+		})
+	})
+
+	Context("when the message names a transport", func() {
+		It("sends the message through a client configured for that transport", func() {
+			message := mail.Message{Subject: "billing", Transport: "billing"}
+
+			err := router.Send(message, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transportsCollection.GetCall.Receives.Connection).To(Equal(connection))
+			Expect(transportsCollection.GetCall.Receives.Name).To(Equal("billing"))
+
+			Expect(clientConfigs).To(Equal([]mail.Config{
+				{
+					Host:           "smtp.example.com",
+					Port:           "465",
+					User:           "billing-user",
+					Pass:           "billing-pass",
+					AuthMechanism:  mail.AuthPlain,
+					TLSMode:        mail.TLSModeImplicit,
+					TestMode:       true,
+					LoggingEnabled: true,
+				},
+			}))
+
+			Expect(transportClient.ConnectCall.Receives.Logger).NotTo(BeNil())
+			Expect(transportClient.SendCall.Receives.Message).To(Equal(message))
+			Expect(platform.SendCall.CallCount).To(Equal(0))
+		})
+
+		It("reuses the client while the transport configuration is unchanged", func() {
+			message := mail.Message{Transport: "billing"}
+
+			Expect(router.Send(message, logger)).To(Succeed())
+			Expect(router.Send(message, logger)).To(Succeed())
+
+			Expect(clientConfigs).To(HaveLen(1))
+			Expect(transportClient.SendCall.CallCount).To(Equal(2))
+		})
+
+		It("rebuilds the client when the transport configuration changes", func() {
+			message := mail.Message{Transport: "billing"}
+
+			Expect(router.Send(message, logger)).To(Succeed())
+
+			transportsCollection.GetCall.Returns.Transport.Host = "smtp2.example.com"
+			Expect(router.Send(message, logger)).To(Succeed())
+
+			Expect(clientConfigs).To(HaveLen(2))
+			Expect(clientConfigs[1].Host).To(Equal("smtp2.example.com"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the transport cannot be loaded", func() {
+				transportsCollection.GetCall.Returns.Error = collections.NotFoundError{Err: errors.New("not found")}
+
+				err := router.Send(mail.Message{Transport: "missing"}, logger)
+				Expect(err).To(MatchError(collections.NotFoundError{Err: errors.New("not found")}))
+
+				Expect(platform.SendCall.CallCount).To(Equal(0))
+				Expect(transportClient.SendCall.CallCount).To(Equal(0))
+			})
+
+			It("returns an error when the transport client cannot connect", func() {
+				transportClient.ConnectCall.Returns.Error = errors.New("connection refused")
+
+				err := router.Send(mail.Message{Transport: "billing"}, logger)
+				Expect(err).To(MatchError(errors.New("connection refused")))
+
+				Expect(transportClient.SendCall.CallCount).To(Equal(0))
+			})
+
+			It("returns the error from the transport client", func() {
+				transportClient.SendCall.Returns.Error = errors.New("send failed")
+
+				err := router.Send(mail.Message{Transport: "billing"}, logger)
+				Expect(err).To(MatchError(errors.New("send failed")))
+			})
+		})
+	})
+})
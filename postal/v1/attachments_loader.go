@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type attachmentFinder interface {
+	FindByID(connection models.ConnectionInterface, attachmentID string) (models.Attachment, error)
+}
+
+type AttachmentsLoader struct {
+	database        db.DatabaseInterface
+	attachmentsRepo attachmentFinder
+}
+
+func NewAttachmentsLoader(database db.DatabaseInterface, attachmentsRepo attachmentFinder) AttachmentsLoader {
+	return AttachmentsLoader{
+		database:        database,
+		attachmentsRepo: attachmentsRepo,
+	}
+}
+
+func (loader AttachmentsLoader) LoadAttachments(attachmentIDs []string) ([]mail.Attachment, error) {
+	conn := loader.database.Connection()
+
+	var attachments []mail.Attachment
+	for _, attachmentID := range attachmentIDs {
+		attachment, err := loader.attachmentsRepo.FindByID(conn, attachmentID)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, mail.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			ContentID:   attachment.ContentID,
+			Content:     attachment.Content,
+		})
+	}
+
+	return attachments, nil
+}
@@ -50,11 +50,20 @@ type globalUnsubscribesGetter interface {
 	Get(connection models.ConnectionInterface, userGUID string) (bool, error)
 }
 
+type suppressionsFinder interface {
+	Find(connection models.ConnectionInterface, address string) (models.Suppression, error)
+}
+
+type userLocalesGetter interface {
+	Get(connection models.ConnectionInterface, userGUID string) (string, error)
+}
+
 type DeliveryJobProcessorConfig struct {
-	DBTrace bool
-	UAAHost string
-	Sender  string
-	Domain  string
+	DBTrace   bool
+	UAAHost   string
+	Sender    string
+	Domain    string
+	PublicURL string
 
 	Packager    common.Packager
 	MailClient  mailSender
@@ -66,15 +75,18 @@ type DeliveryJobProcessorConfig struct {
 	ReceiptsRepo           receiptsCreator
 	UnsubscribesRepo       unsubscribesGetter
 	GlobalUnsubscribesRepo globalUnsubscribesGetter
+	SuppressionsRepo       suppressionsFinder
+	UserLocalesRepo        userLocalesGetter
 	MessageStatusUpdater   messageStatusUpdater
 	DeliveryFailureHandler deliveryFailureHandler
 }
 
 type DeliveryJobProcessor struct {
-	dbTrace bool
-	uaaHost string
-	sender  string
-	domain  string
+	dbTrace   bool
+	uaaHost   string
+	sender    string
+	domain    string
+	publicURL string
 
 	packager    common.Packager
 	mailClient  mailSender
@@ -86,16 +98,19 @@ type DeliveryJobProcessor struct {
 	receiptsRepo           receiptsCreator
 	unsubscribesRepo       unsubscribesGetter
 	globalUnsubscribesRepo globalUnsubscribesGetter
+	suppressionsRepo       suppressionsFinder
+	userLocalesRepo        userLocalesGetter
 	messageStatusUpdater   messageStatusUpdater
 	deliveryFailureHandler deliveryFailureHandler
 }
 
 func NewDeliveryJobProcessor(config DeliveryJobProcessorConfig) DeliveryJobProcessor {
 	return DeliveryJobProcessor{
-		dbTrace: config.DBTrace,
-		uaaHost: config.UAAHost,
-		sender:  config.Sender,
-		domain:  config.Domain,
+		dbTrace:   config.DBTrace,
+		uaaHost:   config.UAAHost,
+		sender:    config.Sender,
+		domain:    config.Domain,
+		publicURL: config.PublicURL,
 
 		packager:    config.Packager,
 		mailClient:  config.MailClient,
@@ -107,6 +122,8 @@ func NewDeliveryJobProcessor(config DeliveryJobProcessorConfig) DeliveryJobProce
 		receiptsRepo:           config.ReceiptsRepo,
 		unsubscribesRepo:       config.UnsubscribesRepo,
 		globalUnsubscribesRepo: config.GlobalUnsubscribesRepo,
+		suppressionsRepo:       config.SuppressionsRepo,
+		userLocalesRepo:        config.UserLocalesRepo,
 		messageStatusUpdater:   config.MessageStatusUpdater,
 		deliveryFailureHandler: config.DeliveryFailureHandler,
 	}
@@ -154,20 +171,56 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 			return nil
 		}
 
-		emails := users[delivery.UserGUID].Emails
-		if len(emails) > 0 {
-			delivery.Email = emails[0]
+		delivery.Profile = users[delivery.UserGUID]
+		if len(delivery.Profile.Emails) > 0 {
+			delivery.Email = delivery.Profile.Emails[0]
 		}
 	}
 
+	if delivery.UserGUID != "" {
+		delivery.Locale, err = p.userLocalesRepo.Get(p.database.Connection(), delivery.UserGUID)
+		if err != nil {
+			p.deliveryFailureHandler.Handle(job, logger)
+			return nil
+		}
+	}
+
+	if delivery.Locale == "" {
+		delivery.Locale = delivery.Profile.Locale
+	}
+
 	logger = logger.WithData(lager.Data{
 		"recipient": delivery.Email,
 	})
 
-	if p.shouldDeliver(delivery, logger) {
-		status := p.process(delivery, logger)
+	if delivery.Email != "" {
+		_, err = p.suppressionsRepo.Find(p.database.Connection(), delivery.Email)
+		if err == nil {
+			logger.Info("recipient-suppressed")
+			p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusSuppressed, "", logger)
+			metrics.NewMetric("counter", map[string]interface{}{
+				"name": "notifications.worker.suppressed",
+			}).Log()
+			return nil
+		}
+
+		if _, ok := err.(models.NotFoundError); !ok {
+			logger.Error("suppression-lookup-failed", err)
+			p.deliveryFailureHandler.Handle(job, logger)
+			return nil
+		}
+	}
+
+	kind := p.findKind(p.database.Connection(), delivery.Options.KindID, delivery.ClientID)
 
-		if status != common.StatusDelivered {
+	if p.shouldDeliver(delivery, kind.Critical, logger) {
+		status := p.process(delivery, kind, logger)
+
+		if status == common.StatusUndeliverable {
+			metrics.NewMetric("counter", map[string]interface{}{
+				"name": "notifications.worker.undeliverable",
+			}).Log()
+		} else if status != common.StatusDelivered {
 			p.deliveryFailureHandler.Handle(job, logger)
 			return nil
 		} else {
@@ -184,10 +237,19 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 	return nil
 }
 
-func (p DeliveryJobProcessor) process(delivery common.Delivery, logger lager.Logger) string {
+func (p DeliveryJobProcessor) process(delivery common.Delivery, kind models.Kind, logger lager.Logger) string {
 	context, err := p.packager.PrepareContext(delivery, p.sender, p.domain)
 	if err != nil {
-		panic(err)
+		logger.Error("message-context-failed", err)
+		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusFailed, "", logger)
+		return common.StatusFailed
+	}
+
+	context.Threading = kind.Threading
+	context.Critical = kind.Critical
+
+	if p.publicURL != "" && delivery.UserGUID != "" && !kind.Critical {
+		context.ListUnsubscribeURL = common.ListUnsubscribeURL(p.publicURL, context.UnsubscribeID, 1)
 	}
 
 	message, err := p.packager.Pack(context)
@@ -203,9 +265,10 @@ func (p DeliveryJobProcessor) process(delivery common.Delivery, logger lager.Log
 	return status
 }
 
-func (p DeliveryJobProcessor) shouldDeliver(delivery common.Delivery, logger lager.Logger) bool {
+func (p DeliveryJobProcessor) shouldDeliver(delivery common.Delivery, critical bool, logger lager.Logger) bool {
 	conn := p.database.Connection()
-	if p.isCritical(conn, delivery.Options.KindID, delivery.ClientID) {
+
+	if critical {
 		return true
 	}
 
@@ -249,6 +312,11 @@ func (p DeliveryJobProcessor) sendMail(messageID string, message mail.Message, l
 
 	err = p.mailClient.Send(message, logger)
 	if err != nil {
+		if _, ok := err.(mail.UndeliverableError); ok {
+			logger.Error("delivery-undeliverable", err)
+			return common.StatusUndeliverable
+		}
+
 		logger.Error("delivery-failed-smtp-error", err)
 		return common.StatusFailed
 	}
@@ -258,11 +326,11 @@ func (p DeliveryJobProcessor) sendMail(messageID string, message mail.Message, l
 	return common.StatusDelivered
 }
 
-func (p DeliveryJobProcessor) isCritical(conn db.ConnectionInterface, kindID, clientID string) bool {
+func (p DeliveryJobProcessor) findKind(conn db.ConnectionInterface, kindID, clientID string) models.Kind {
 	kind, err := p.kindsRepo.Find(conn, kindID, clientID)
 	if _, ok := err.(models.NotFoundError); ok {
-		return false
+		return models.Kind{}
 	}
 
-	return kind.Critical
+	return kind
 }
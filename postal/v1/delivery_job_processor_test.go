@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"crypto/md5"
 	"errors"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/metrics"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/postal/v1"
+	"github.com/cloudfoundry-incubator/notifications/sanitize"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -31,6 +34,8 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		queue                  *mocks.Queue
 		unsubscribesRepo       *mocks.UnsubscribesRepo
 		globalUnsubscribesRepo *mocks.GlobalUnsubscribesRepo
+		suppressionsRepo       *mocks.SuppressionsRepo
+		userLocalesRepo        *mocks.UserLocalesRepo
 		kindsRepo              *mocks.KindsRepo
 		database               *mocks.Database
 		campaignJobProcessor   *mocks.CampaignJobProcessor
@@ -57,6 +62,9 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		queue = mocks.NewQueue()
 		unsubscribesRepo = mocks.NewUnsubscribesRepo()
 		globalUnsubscribesRepo = mocks.NewGlobalUnsubscribesRepo()
+		suppressionsRepo = mocks.NewSuppressionsRepo()
+		suppressionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not suppressed")}
+		userLocalesRepo = mocks.NewUserLocalesRepo()
 
 		kindsRepo = mocks.NewKindsRepo()
 		kindsRepo.FindCall.Returns.Kinds = []models.Kind{
@@ -101,7 +109,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			Sender:  "from@example.com",
 			Domain:  "example.com",
 
-			Packager:    common.NewPackager(templateLoader, cloak),
+			Packager:    common.NewPackager(templateLoader, mocks.NewAttachmentsLoader(), cloak, sanitize.NewSanitizer(sanitize.StrictPolicy, nil)),
 			MailClient:  mailClient,
 			Database:    database,
 			TokenLoader: tokenLoader,
@@ -111,6 +119,8 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			ReceiptsRepo:           receiptsRepo,
 			UnsubscribesRepo:       unsubscribesRepo,
 			GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+			SuppressionsRepo:       suppressionsRepo,
+			UserLocalesRepo:        userLocalesRepo,
 			MessageStatusUpdater:   messageStatusUpdater,
 			DeliveryFailureHandler: deliveryFailureHandler,
 		})
@@ -166,6 +176,48 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			Expect(templateLoader.LoadTemplatesCall.Receives.TemplateID).To(Equal("some-template-id"))
 		})
 
+		It("loads the template for the locale in the user's UAA profile", func() {
+			userLoader.LoadCall.Returns.Users["user-123"] = uaa.User{
+				Emails: []string{fakeUserEmail},
+				Locale: "es-MX",
+			}
+
+			processor.Process(job, logger)
+
+			Expect(templateLoader.LoadTemplatesCall.Receives.Locale).To(Equal("es-MX"))
+		})
+
+		It("makes the user's UAA profile available to the templates", func() {
+			userLoader.LoadCall.Returns.Users["user-123"] = uaa.User{
+				Emails:     []string{fakeUserEmail},
+				UserName:   "jdoe",
+				GivenName:  "Jane",
+				FamilyName: "Doe",
+			}
+			templateLoader.LoadTemplatesCall.Returns.Templates.Text = "Hello {{.User.GivenName}} ({{.User.Name}})"
+
+			processor.Process(job, logger)
+
+			Expect(mailClient.SendCall.Receives.Message.Body).To(ContainElement(mail.Part{
+				ContentType: "text/plain",
+				Content:     "Hello Jane (Jane Doe)",
+			}))
+		})
+
+		It("prefers the locale the user has chosen", func() {
+			userLoader.LoadCall.Returns.Users["user-123"] = uaa.User{
+				Emails: []string{fakeUserEmail},
+				Locale: "es-MX",
+			}
+			userLocalesRepo.GetCall.Returns.Locale = "pt-BR"
+
+			processor.Process(job, logger)
+
+			Expect(userLocalesRepo.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(userLocalesRepo.GetCall.Receives.UserID).To(Equal("user-123"))
+			Expect(templateLoader.LoadTemplatesCall.Receives.Locale).To(Equal("pt-BR"))
+		})
+
 		It("logs successful delivery", func() {
 			processor.Process(job, logger)
 
@@ -197,7 +249,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				Sender:  "from@example.com",
 				Domain:  "example.com",
 
-				Packager:    common.NewPackager(templateLoader, cloak),
+				Packager:    common.NewPackager(templateLoader, mocks.NewAttachmentsLoader(), cloak, sanitize.NewSanitizer(sanitize.StrictPolicy, nil)),
 				MailClient:  mailClient,
 				Database:    database,
 				TokenLoader: tokenLoader,
@@ -207,6 +259,8 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				ReceiptsRepo:           receiptsRepo,
 				UnsubscribesRepo:       unsubscribesRepo,
 				GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+				SuppressionsRepo:       suppressionsRepo,
+				UserLocalesRepo:        userLocalesRepo,
 				MessageStatusUpdater:   messageStatusUpdater,
 				DeliveryFailureHandler: deliveryFailureHandler,
 			})
@@ -240,6 +294,123 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			Expect(receiptsRepo.CreateReceiptsCall.Receives.UserGUIDs).To(Equal([]string{"user-123"}))
 		})
 
+		It("marks messages for critical kinds as important", func() {
+			kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+				{
+					ID:       "some-kind",
+					ClientID: "some-client",
+					Critical: true,
+				},
+			}
+
+			processor.Process(job, logger)
+
+			Expect(mailClient.SendCall.Receives.Message.Headers).To(ContainElement("Importance: high"))
+			Expect(mailClient.SendCall.Receives.Message.Headers).To(ContainElement("Priority: urgent"))
+		})
+
+		Context("when the delivery has a thread key", func() {
+			BeforeEach(func() {
+				delivery.Options.ThreadKey = "some-thread"
+				job = gobble.NewJob(delivery)
+			})
+
+			It("adds threading headers when the kind opts into threading", func() {
+				kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+					{
+						ID:        "some-kind",
+						ClientID:  "some-client",
+						Threading: true,
+					},
+				}
+
+				processor.Process(job, logger)
+
+				threadID := common.ThreadMessageID("some-client", "some-thread", "example.com")
+				Expect(mailClient.SendCall.Receives.Message.Headers).To(ContainElement("In-Reply-To: " + threadID))
+				Expect(mailClient.SendCall.Receives.Message.Headers).To(ContainElement("References: " + threadID))
+			})
+
+			It("does not add threading headers when the kind has not opted in", func() {
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+				for _, header := range mailClient.SendCall.Receives.Message.Headers {
+					Expect(header).NotTo(HavePrefix("In-Reply-To"))
+					Expect(header).NotTo(HavePrefix("References"))
+				}
+			})
+		})
+
+		Context("when a public URL is configured", func() {
+			var cloak conceal.Cloak
+
+			BeforeEach(func() {
+				sum := md5.Sum([]byte("banana's are so very tasty"))
+				var err error
+				cloak, err = conceal.NewCloak(sum[:])
+				Expect(err).NotTo(HaveOccurred())
+
+				processor = v1.NewDeliveryJobProcessor(v1.DeliveryJobProcessorConfig{
+					UAAHost:   "https://uaa.example.com",
+					Sender:    "from@example.com",
+					Domain:    "example.com",
+					PublicURL: "https://notifications.example.com",
+
+					Packager:    common.NewPackager(templateLoader, mocks.NewAttachmentsLoader(), cloak, sanitize.NewSanitizer(sanitize.StrictPolicy, nil)),
+					MailClient:  mailClient,
+					Database:    database,
+					TokenLoader: tokenLoader,
+					UserLoader:  userLoader,
+
+					KindsRepo:              kindsRepo,
+					ReceiptsRepo:           receiptsRepo,
+					UnsubscribesRepo:       unsubscribesRepo,
+					GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+					SuppressionsRepo:       suppressionsRepo,
+					UserLocalesRepo:        userLocalesRepo,
+					MessageStatusUpdater:   messageStatusUpdater,
+					DeliveryFailureHandler: deliveryFailureHandler,
+				})
+			})
+
+			It("adds one-click unsubscribe headers for the kind", func() {
+				processor.Process(job, logger)
+
+				headers := mailClient.SendCall.Receives.Message.Headers
+				Expect(headers).To(ContainElement("List-Unsubscribe-Post: List-Unsubscribe=One-Click"))
+
+				var link string
+				for _, header := range headers {
+					if strings.HasPrefix(header, "List-Unsubscribe: ") {
+						link = strings.Trim(strings.TrimPrefix(header, "List-Unsubscribe: "), "<>")
+					}
+				}
+				Expect(link).To(HavePrefix("https://notifications.example.com/list_unsubscribe/"))
+
+				token, err := cloak.Unveil([]byte(strings.TrimPrefix(link, "https://notifications.example.com/list_unsubscribe/")))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(token)).To(Equal("user-123|some-client|some-kind"))
+			})
+
+			It("does not add the headers for critical kinds", func() {
+				kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+					{
+						ID:       "some-kind",
+						ClientID: "some-client",
+						Critical: true,
+					},
+				}
+
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+				for _, header := range mailClient.SendCall.Receives.Message.Headers {
+					Expect(header).NotTo(HavePrefix("List-Unsubscribe"))
+				}
+			})
+		})
+
 		Context("when the receipt fails to be created", func() {
 			It("retries the job", func() {
 				receiptsRepo.CreateReceiptsCall.Returns.Error = errors.New("something happened")
@@ -250,6 +421,15 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the user's locale cannot be loaded", func() {
+			It("retries the job", func() {
+				userLocalesRepo.GetCall.Returns.Error = errors.New("something happened")
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+			})
+		})
+
 		Context("when loading a zoned token fails", func() {
 			It("retries the job", func() {
 				job := gobble.NewJob(delivery)
@@ -345,6 +525,25 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				})
 			})
 
+			Context("because the recipient address cannot be delivered to", func() {
+				BeforeEach(func() {
+					mailClient.SendCall.Returns.Error = mail.UndeliverableError{Err: errors.New("address requires SMTPUTF8")}
+				})
+
+				It("does not retry the job", func() {
+					processor.Process(job, logger)
+
+					Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+				})
+
+				It("updates the message status as undeliverable", func() {
+					processor.Process(job, logger)
+
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+				})
+			})
+
 			Context("and the error is a connect error", func() {
 				It("logs an SMTP connection error", func() {
 					mailClient.ConnectCall.Returns.Error = errors.New("server timeout")
@@ -424,6 +623,73 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the recipient's address is suppressed", func() {
+			BeforeEach(func() {
+				suppressionsRepo.FindCall.Returns.Error = nil
+				suppressionsRepo.FindCall.Returns.Suppression = models.Suppression{
+					Address: "user-123@example.com",
+					Reason:  "bounce",
+				}
+			})
+
+			It("does not send the notification", func() {
+				processor.Process(job, logger)
+
+				Expect(suppressionsRepo.FindCall.Receives.Connection).To(Equal(conn))
+				Expect(suppressionsRepo.FindCall.Receives.Address).To(Equal("user-123@example.com"))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+
+			It("updates the message status as suppressed", func() {
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusSuppressed))
+			})
+
+			It("counts the message as suppressed", func() {
+				metricsBuffer := bytes.NewBuffer([]byte{})
+				metricsLogger := metrics.DefaultLogger
+				metrics.DefaultLogger = log.New(metricsBuffer, "", 0)
+				defer func() {
+					metrics.DefaultLogger = metricsLogger
+				}()
+
+				processor.Process(job, logger)
+
+				Expect(metricsBuffer.String()).To(ContainSubstring(`"name":"notifications.worker.suppressed"`))
+				Expect(metricsBuffer.String()).NotTo(ContainSubstring(`"name":"notifications.worker.unsubscribed"`))
+			})
+
+			It("does not send critical notifications either", func() {
+				kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+					{
+						ID:       "some-kind",
+						ClientID: "some-client",
+						Critical: true,
+					},
+				}
+
+				processor.Process(job, logger)
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusSuppressed))
+			})
+
+			Context("when the suppression lookup fails", func() {
+				It("hands the job to the failure handler to be retried", func() {
+					suppressionsRepo.FindCall.Returns.Error = errors.New("some database error")
+
+					processor.Process(job, logger)
+
+					Expect(mailClient.SendCall.CallCount).To(Equal(0))
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(BeEmpty())
+					Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+					Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
+				})
+			})
+		})
+
 		Context("when the recipient hasn't unsubscribed, but doesn't have a valid email address", func() {
 			Context("when the recipient has no emails", func() {
 				BeforeEach(func() {
@@ -629,6 +895,33 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the message context cannot be prepared", func() {
+			BeforeEach(func() {
+				templateLoader.LoadTemplatesCall.Returns.Error = errors.New("missing attachment")
+				job = gobble.NewJob(delivery)
+			})
+
+			It("does not panic", func() {
+				Expect(func() {
+					processor.Process(job, logger)
+				}).ToNot(Panic())
+			})
+
+			It("marks the job for retry later", func() {
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+
+			It("updates the message status as failed", func() {
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
+			})
+		})
+
 		Context("when the job contains malformed JSON", func() {
 			BeforeEach(func() {
 				job.Payload = `{"Space":"my-space","Options":{"HTML":"<p>some text that just abruptly ends`
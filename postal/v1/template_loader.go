@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"encoding/json"
+
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -16,6 +18,7 @@ type kindFinder interface {
 
 type templateFinder interface {
 	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
+	FindLayouts(connection models.ConnectionInterface, template models.Template) ([]models.Template, error)
 }
 
 type TemplatesLoader struct {
@@ -35,7 +38,7 @@ func NewTemplatesLoader(database db.DatabaseInterface, clientsRepo clientFinder,
 	}
 }
 
-func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string) (common.Templates, error) {
+func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string, templateVersion int, locale string) (common.Templates, error) {
 	conn := loader.database.Connection()
 
 	if kindID != "" {
@@ -45,7 +48,7 @@ func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string)
 		}
 
 		if kind.TemplateID != models.DefaultTemplateID {
-			return loader.loadTemplate(conn, kind.TemplateID)
+			return loader.loadTemplate(conn, kind.TemplateID, locale)
 		}
 	}
 
@@ -54,18 +57,57 @@ func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string)
 		return common.Templates{}, err
 	}
 
-	return loader.loadTemplate(conn, client.TemplateID)
+	return loader.loadTemplate(conn, client.TemplateID, locale)
 }
 
-func (loader TemplatesLoader) loadTemplate(conn db.ConnectionInterface, templateID string) (common.Templates, error) {
+func (loader TemplatesLoader) loadTemplate(conn db.ConnectionInterface, templateID, locale string) (common.Templates, error) {
 	template, err := loader.templatesRepo.FindByID(conn, templateID)
 	if err != nil {
 		return common.Templates{}, err
 	}
 
-	return common.Templates{
+	templates, err := localize(template, locale)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	layouts, err := loader.templatesRepo.FindLayouts(conn, template)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	var localizedLayouts []common.Templates
+	for _, layout := range layouts {
+		localized, err := localize(layout, locale)
+		if err != nil {
+			return common.Templates{}, err
+		}
+
+		localizedLayouts = append(localizedLayouts, localized)
+	}
+
+	return common.ComposeTemplates(templates, localizedLayouts, nil), nil
+}
+
+func localize(template models.Template, locale string) (common.Templates, error) {
+	templates := common.Templates{
 		Subject: template.Subject,
 		Text:    template.Text,
 		HTML:    template.HTML,
-	}, nil
+
+		DisableAutoText:    template.DisableAutoText,
+		DisableCSSInlining: template.DisableCSSInlining,
+	}
+
+	if template.Localizations == "" || locale == "" {
+		return templates, nil
+	}
+
+	var localizations common.Localizations
+	err := json.Unmarshal([]byte(template.Localizations), &localizations)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	return localizations.Apply(templates, locale), nil
 }
@@ -59,6 +59,74 @@ var _ = Describe("TemplateLoader", func() {
 			}
 		})
 
+		Context("when the recipient has a locale", func() {
+			BeforeEach(func() {
+				templatesRepo.FindByIDCall.Returns.Template = models.Template{
+					ID:            models.DefaultTemplateID,
+					Name:          "Default Template",
+					HTML:          "<p>The default template</p>",
+					Text:          "The default template",
+					Subject:       "default subject",
+					Localizations: `{"es": {"subject": "asunto predeterminado", "text": "La plantilla predeterminada"}}`,
+				}
+			})
+
+			It("returns the variant for the recipient's language", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", 0, "es-MX")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(templates).To(Equal(common.Templates{
+					Subject: "asunto predeterminado",
+					Text:    "La plantilla predeterminada",
+					HTML:    "<p>The default template</p>",
+				}))
+			})
+
+			It("returns the template itself when there is no variant for the locale", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", 0, "ja")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(templates.Subject).To(Equal("default subject"))
+			})
+		})
+
+		Context("when the template declares a layout", func() {
+			BeforeEach(func() {
+				templatesRepo.FindByIDCall.Returns.Template = models.Template{
+					ID:       models.DefaultTemplateID,
+					HTML:     "<p>The default template</p>",
+					Text:     "The default template",
+					Subject:  "default subject",
+					LayoutID: "some-layout-id",
+				}
+				templatesRepo.FindLayoutsCall.Returns.Layouts = []models.Template{
+					{
+						ID:            "some-layout-id",
+						HTML:          `<body>{{template "content" .}}</body>`,
+						Localizations: `{"es": {"html": "<body lang=\"es\">{{template \"content\" .}}</body>"}}`,
+					},
+				}
+			})
+
+			It("wraps the template in the localized layouts", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", 0, "es")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(templates).To(Equal(common.Templates{
+					Subject: "default subject",
+					Text:    "The default template",
+					HTML:    `<body lang="es">{{template "content" .}}</body>{{define "content"}}<p>The default template</p>{{end}}`,
+				}))
+
+				Expect(templatesRepo.FindLayoutsCall.Receives.Connection).To(Equal(conn))
+				Expect(templatesRepo.FindLayoutsCall.Receives.Template.LayoutID).To(Equal("some-layout-id"))
+			})
+
+			It("bubbles up an error finding the layouts", func() {
+				templatesRepo.FindLayoutsCall.Returns.Error = errors.New("layout not found")
+
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", 0, "")
+				Expect(err).To(MatchError("layout not found"))
+			})
+		})
+
 		Context("when the kind has a template", func() {
 			BeforeEach(func() {
 				templatesRepo.FindByIDCall.Returns.Template = models.Template{
@@ -79,7 +147,7 @@ var _ = Describe("TemplateLoader", func() {
 			})
 
 			It("returns the template belonging to the kind", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", 0, "")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
 					HTML:    "<p>kind template</p>",
@@ -109,7 +177,7 @@ var _ = Describe("TemplateLoader", func() {
 			})
 
 			It("returns the template belonging to the client", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", 0, "")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
 					HTML:    "<p>client template</p>",
@@ -124,7 +192,7 @@ var _ = Describe("TemplateLoader", func() {
 
 		Context("when the neither client nor kind has a template", func() {
 			It("returns the default template", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", 0, "")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
 					HTML:    "<p>The default template</p>",
@@ -136,7 +204,7 @@ var _ = Describe("TemplateLoader", func() {
 
 		Context("when kindID is an empty string", func() {
 			It("does not look for a template belonging to the kind", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "", "")
+				templates, err := loader.LoadTemplates("my-client-id", "", "", 0, "")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
 					HTML:    "<p>The default template</p>",
@@ -150,7 +218,7 @@ var _ = Describe("TemplateLoader", func() {
 			It("bubbles up the error", func() {
 				kindsRepo.FindCall.Returns.Error = errors.New("BOOM!")
 
-				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", 0, "")
 				Expect(err).To(HaveOccurred())
 			})
 
@@ -160,7 +228,7 @@ var _ = Describe("TemplateLoader", func() {
 			It("bubbles up the error", func() {
 				clientsRepo.FindCall.Returns.Error = errors.New("BOOM!")
 
-				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", 0, "")
 				Expect(err).To(HaveOccurred())
 			})
 		})
@@ -0,0 +1,44 @@
+package v2
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+)
+
+type attachmentGetter interface {
+	Get(connection models.ConnectionInterface, attachmentID string) (models.Attachment, error)
+}
+
+type AttachmentsLoader struct {
+	database        db.DatabaseInterface
+	attachmentsRepo attachmentGetter
+}
+
+func NewAttachmentsLoader(database db.DatabaseInterface, attachmentsRepo attachmentGetter) AttachmentsLoader {
+	return AttachmentsLoader{
+		database:        database,
+		attachmentsRepo: attachmentsRepo,
+	}
+}
+
+func (loader AttachmentsLoader) LoadAttachments(attachmentIDs []string) ([]mail.Attachment, error) {
+	conn := loader.database.Connection()
+
+	var attachments []mail.Attachment
+	for _, attachmentID := range attachmentIDs {
+		attachment, err := loader.attachmentsRepo.Get(conn, attachmentID)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, mail.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			ContentID:   attachment.ContentID,
+			Content:     attachment.Content,
+		})
+	}
+
+	return attachments, nil
+}
@@ -0,0 +1,80 @@
+package v2_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/v2"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AttachmentsLoader", func() {
+	var (
+		conn            db.ConnectionInterface
+		database        *mocks.Database
+		attachmentsRepo *mocks.AttachmentsRepository
+		loader          v2.AttachmentsLoader
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		attachmentsRepo = mocks.NewAttachmentsRepository()
+		loader = v2.NewAttachmentsLoader(database, attachmentsRepo)
+	})
+
+	Describe("LoadAttachments", func() {
+		It("returns the attachments in the order they were requested", func() {
+			attachmentsRepo.GetCall.Returns.Attachments = []models.Attachment{
+				{
+					ID:          "some-attachment-id",
+					Filename:    "invoice.pdf",
+					ContentType: "application/pdf",
+					Content:     []byte("some-pdf-content"),
+				},
+				{
+					ID:          "another-attachment-id",
+					Filename:    "logo.png",
+					ContentType: "image/png",
+					ContentID:   "logo",
+					Content:     []byte("some-png-content"),
+				},
+			}
+
+			attachments, err := loader.LoadAttachments([]string{"some-attachment-id", "another-attachment-id"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attachments).To(Equal([]mail.Attachment{
+				{
+					Filename:    "invoice.pdf",
+					ContentType: "application/pdf",
+					Content:     []byte("some-pdf-content"),
+				},
+				{
+					Filename:    "logo.png",
+					ContentType: "image/png",
+					ContentID:   "logo",
+					Content:     []byte("some-png-content"),
+				},
+			}))
+
+			Expect(attachmentsRepo.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(attachmentsRepo.GetCall.Receives.AttachmentIDs).To(Equal([]string{"some-attachment-id", "another-attachment-id"}))
+		})
+
+		Context("when the attachment cannot be found", func() {
+			It("returns the error", func() {
+				attachmentsRepo.GetCall.Returns.Error = errors.New("some-error")
+
+				_, err := loader.LoadAttachments([]string{"some-attachment-id"})
+				Expect(err).To(MatchError(errors.New("some-error")))
+			})
+		})
+	})
+})
@@ -106,6 +106,7 @@ func (p CampaignJobProcessor) Process(conn services.ConnectionInterface, uaaHost
 				GUID:        user.GUID,
 				Email:       user.Email,
 				Endorsement: audience.Endorsement,
+				Data:        recipientData(campaignJob.Campaign.Data, campaignJob.Campaign.RecipientData, user),
 			}
 		}
 	}
@@ -115,6 +116,39 @@ func (p CampaignJobProcessor) Process(conn services.ConnectionInterface, uaaHost
 		usersSlice = append(usersSlice, v)
 	}
 
+	var attachmentIDs []string
+	for _, attachment := range campaignJob.Campaign.Attachments {
+		attachmentIDs = append(attachmentIDs, attachment.ID)
+	}
+
+	var localizations map[string]queue.Localization
+	for locale, localization := range campaignJob.Campaign.Localizations {
+		if localizations == nil {
+			localizations = map[string]queue.Localization{}
+		}
+
+		localized := queue.Localization{
+			Subject: localization.Subject,
+			Text:    localization.Text,
+		}
+
+		if localization.HTML != "" {
+			doctype, head, bodyContent, bodyAttributes, err := p.htmlExtractor.Extract(localization.HTML)
+			if err != nil {
+				return err
+			}
+
+			localized.HTML = queue.HTML{
+				Doctype:        doctype,
+				Head:           head,
+				BodyContent:    bodyContent,
+				BodyAttributes: bodyAttributes,
+			}
+		}
+
+		localizations[locale] = localized
+	}
+
 	options := queue.Options{
 		ReplyTo: campaignJob.Campaign.ReplyTo,
 		Subject: campaignJob.Campaign.Subject,
@@ -125,7 +159,15 @@ func (p CampaignJobProcessor) Process(conn services.ConnectionInterface, uaaHost
 			BodyContent:    bodyContent,
 			BodyAttributes: bodyAttributes,
 		},
-		TemplateID: campaignJob.Campaign.TemplateID,
+		KindID:          campaignJob.Campaign.CampaignTypeID,
+		TemplateID:      campaignJob.Campaign.TemplateID,
+		TemplateVersion: campaignJob.Campaign.TemplateVersion,
+		AttachmentIDs:   attachmentIDs,
+		ThreadKey:       campaignJob.Campaign.ThreadKey,
+		Headers:         campaignJob.Campaign.Headers,
+		From:            campaignJob.Campaign.From,
+		Transport:       campaignJob.Campaign.Transport,
+		Localizations:   localizations,
 	}
 
 	p.enqueuer.Enqueue(conn, usersSlice, options, cf.CloudControllerSpace{},
@@ -134,6 +176,35 @@ func (p CampaignJobProcessor) Process(conn services.ConnectionInterface, uaaHost
 	return nil
 }
 
+// recipientData merges the data given for a single recipient, keyed by their
+// GUID or email address, over the data shared by the whole campaign.
+func recipientData(shared map[string]interface{}, recipients map[string]map[string]interface{}, user horde.User) map[string]interface{} {
+	var data map[string]interface{}
+	var ok bool
+	if user.GUID != "" {
+		data, ok = recipients[user.GUID]
+	}
+
+	if !ok && user.Email != "" {
+		data, ok = recipients[user.Email]
+	}
+
+	if !ok {
+		return shared
+	}
+
+	merged := map[string]interface{}{}
+	for key, value := range shared {
+		merged[key] = value
+	}
+
+	for key, value := range data {
+		merged[key] = value
+	}
+
+	return merged
+}
+
 func (p CampaignJobProcessor) findAudienceGenerator(audience string) (audienceGenerator, error) {
 	switch audience {
 	case "users":
@@ -98,7 +98,7 @@ var _ = Describe("CampaignJobProcessor", func() {
 					Head:           "",
 					Doctype:        "",
 				},
-				KindID:      "",
+				KindID:      "some-campaign-type-id",
 				To:          "",
 				Role:        "",
 				Endorsement: "",
@@ -115,6 +115,252 @@ var _ = Describe("CampaignJobProcessor", func() {
 		})
 	})
 
+	Context("when the campaign has attachments", func() {
+		It("enqueues a job that references the attachments by id", func() {
+			users.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
+				{
+					Users: []horde.User{
+						{GUID: "some-user-guid"},
+					},
+				},
+			}
+
+			err := processor.Process(database.Connection(), "some-uaa-host", *gobble.NewJob(queue.CampaignJob{
+				Campaign: collections.Campaign{
+					ID: "some-id",
+					SendTo: map[string][]string{
+						"users": {"some-user-guid"},
+					},
+					CampaignTypeID: "some-campaign-type-id",
+					Text:           "some-text",
+					Subject:        "The Best subject",
+					TemplateID:     "some-template-id",
+					ClientID:       "some-client-id",
+					Attachments: []collections.Attachment{
+						{ID: "some-attachment-id", Filename: "invoice.pdf"},
+						{ID: "another-attachment-id", Filename: "logo.png", ContentID: "logo"},
+					},
+				},
+			}), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(enqueuer.EnqueueCall.Receives.Options.AttachmentIDs).To(Equal([]string{
+				"some-attachment-id",
+				"another-attachment-id",
+			}))
+		})
+	})
+
+	Context("when the campaign has a thread key", func() {
+		It("enqueues a job with the thread key", func() {
+			users.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
+				{
+					Users: []horde.User{
+						{GUID: "some-user-guid"},
+					},
+				},
+			}
+
+			err := processor.Process(database.Connection(), "some-uaa-host", *gobble.NewJob(queue.CampaignJob{
+				Campaign: collections.Campaign{
+					ID: "some-id",
+					SendTo: map[string][]string{
+						"users": {"some-user-guid"},
+					},
+					CampaignTypeID: "some-campaign-type-id",
+					Text:           "some-text",
+					Subject:        "The Best subject",
+					TemplateID:     "some-template-id",
+					ClientID:       "some-client-id",
+					ThreadKey:      "some-thread-key",
+				},
+			}), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(enqueuer.EnqueueCall.Receives.Options.ThreadKey).To(Equal("some-thread-key"))
+		})
+	})
+
+	Context("when the campaign has custom headers", func() {
+		It("enqueues a job with the headers", func() {
+			users.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
+				{
+					Users: []horde.User{
+						{GUID: "some-user-guid"},
+					},
+				},
+			}
+
+			err := processor.Process(database.Connection(), "some-uaa-host", *gobble.NewJob(queue.CampaignJob{
+				Campaign: collections.Campaign{
+					ID: "some-id",
+					SendTo: map[string][]string{
+						"users": {"some-user-guid"},
+					},
+					CampaignTypeID: "some-campaign-type-id",
+					Text:           "some-text",
+					Subject:        "The Best subject",
+					TemplateID:     "some-template-id",
+					ClientID:       "some-client-id",
+					Headers:        map[string]string{"X-Tracking-ID": "some-tracking-id"},
+				},
+			}), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(enqueuer.EnqueueCall.Receives.Options.Headers).To(Equal(map[string]string{"X-Tracking-ID": "some-tracking-id"}))
+		})
+	})
+
+	Context("when the campaign has template data", func() {
+		It("gives each user the campaign data merged with their own", func() {
+			users.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
+				{
+					Users: []horde.User{
+						{GUID: "some-user-guid"},
+						{GUID: "some-other-user-guid"},
+					},
+				},
+			}
+			emails.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
+				{
+					Users: []horde.User{
+						{Email: "someone@example.com"},
+					},
+				},
+			}
+
+			err := processor.Process(database.Connection(), "some-uaa-host", *gobble.NewJob(queue.CampaignJob{
+				Campaign: collections.Campaign{
+					ID: "some-id",
+					SendTo: map[string][]string{
+						"users":  {"some-user-guid", "some-other-user-guid"},
+						"emails": {"someone@example.com"},
+					},
+					CampaignTypeID: "some-campaign-type-id",
+					Text:           "some-text",
+					Subject:        "The Best subject",
+					TemplateID:     "some-template-id",
+					ClientID:       "some-client-id",
+					Data: map[string]interface{}{
+						"product":  "widget",
+						"discount": 10,
+					},
+					RecipientData: map[string]map[string]interface{}{
+						"some-other-user-guid": {"discount": 20},
+						"someone@example.com":  {"name": "Someone"},
+					},
+				},
+			}), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(enqueuer.EnqueueCall.Receives.Users).To(ConsistOf([]queue.User{
+				{
+					GUID: "some-user-guid",
+					Data: map[string]interface{}{"product": "widget", "discount": 10.0},
+				},
+				{
+					GUID: "some-other-user-guid",
+					Data: map[string]interface{}{"product": "widget", "discount": 20.0},
+				},
+				{
+					Email: "someone@example.com",
+					Data:  map[string]interface{}{"product": "widget", "discount": 10.0, "name": "Someone"},
+				},
+			}))
+		})
+	})
+
+	Context("when the campaign has a from address", func() {
+		It("enqueues a job with the from address", func() {
+			users.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
+				{
+					Users: []horde.User{
+						{GUID: "some-user-guid"},
+					},
+				},
+			}
+
+			err := processor.Process(database.Connection(), "some-uaa-host", *gobble.NewJob(queue.CampaignJob{
+				Campaign: collections.Campaign{
+					ID: "some-id",
+					SendTo: map[string][]string{
+						"users": {"some-user-guid"},
+					},
+					CampaignTypeID: "some-campaign-type-id",
+					Text:           "some-text",
+					Subject:        "The Best subject",
+					TemplateID:     "some-template-id",
+					ClientID:       "some-client-id",
+					From:           `"Billing" <billing@example.com>`,
+				},
+			}), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(enqueuer.EnqueueCall.Receives.Options.From).To(Equal(`"Billing" <billing@example.com>`))
+		})
+	})
+
+	Context("when the campaign is routed through a transport", func() {
+		It("enqueues a job with the transport", func() {
+			users.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
+				{
+					Users: []horde.User{
+						{GUID: "some-user-guid"},
+					},
+				},
+			}
+
+			err := processor.Process(database.Connection(), "some-uaa-host", *gobble.NewJob(queue.CampaignJob{
+				Campaign: collections.Campaign{
+					ID: "some-id",
+					SendTo: map[string][]string{
+						"users": {"some-user-guid"},
+					},
+					CampaignTypeID: "some-campaign-type-id",
+					Text:           "some-text",
+					Subject:        "The Best subject",
+					TemplateID:     "some-template-id",
+					ClientID:       "some-client-id",
+					Transport:      "billing",
+				},
+			}), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(enqueuer.EnqueueCall.Receives.Options.Transport).To(Equal("billing"))
+		})
+	})
+
+	Context("when the campaign records a template version", func() {
+		It("enqueues a job pinned to that template version", func() {
+			users.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
+				{
+					Users: []horde.User{
+						{GUID: "some-user-guid"},
+					},
+				},
+			}
+
+			err := processor.Process(database.Connection(), "some-uaa-host", *gobble.NewJob(queue.CampaignJob{
+				Campaign: collections.Campaign{
+					ID: "some-id",
+					SendTo: map[string][]string{
+						"users": {"some-user-guid"},
+					},
+					CampaignTypeID:  "some-campaign-type-id",
+					Text:            "some-text",
+					Subject:         "The Best subject",
+					TemplateID:      "some-template-id",
+					TemplateVersion: 3,
+					ClientID:        "some-client-id",
+				},
+			}), logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(enqueuer.EnqueueCall.Receives.Options.TemplateID).To(Equal("some-template-id"))
+			Expect(enqueuer.EnqueueCall.Receives.Options.TemplateVersion).To(Equal(3))
+		})
+	})
+
 	Context("when the audience is emails", func() {
 		It("enqueues a job based on the emails audience", func() {
 			emails.GenerateAudiencesCall.Returns.Audiences = []horde.Audience{
@@ -172,7 +418,7 @@ var _ = Describe("CampaignJobProcessor", func() {
 					Head:           "",
 					Doctype:        "",
 				},
-				KindID:      "",
+				KindID:      "some-campaign-type-id",
 				To:          "",
 				Role:        "",
 				Endorsement: "",
@@ -246,7 +492,7 @@ var _ = Describe("CampaignJobProcessor", func() {
 					Head:           "",
 					Doctype:        "",
 				},
-				KindID:      "",
+				KindID:      "some-campaign-type-id",
 				To:          "",
 				Role:        "",
 				Endorsement: "",
@@ -319,7 +565,7 @@ var _ = Describe("CampaignJobProcessor", func() {
 					Head:           "",
 					Doctype:        "",
 				},
-				KindID:      "",
+				KindID:      "some-campaign-type-id",
 				To:          "",
 				Role:        "",
 				Endorsement: "",
@@ -445,6 +691,7 @@ var _ = Describe("CampaignJobProcessor", func() {
 				HTML: queue.HTML{
 					BodyContent: "<h1>my-html</h1>",
 				},
+				KindID:      "some-campaign-type-id",
 				Endorsement: "",
 				TemplateID:  "some-template-id",
 			}))
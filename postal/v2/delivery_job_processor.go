@@ -41,6 +41,18 @@ type campaignsRepositoryInterface interface {
 	Get(connection models.ConnectionInterface, campaignID string) (models.Campaign, error)
 }
 
+type campaignTypesRepositoryInterface interface {
+	Get(connection models.ConnectionInterface, campaignTypeID string) (models.CampaignType, error)
+}
+
+type suppressionsRepositoryInterface interface {
+	Get(connection models.ConnectionInterface, address string) (models.Suppression, error)
+}
+
+type userLocalesRepositoryInterface interface {
+	Get(connection models.ConnectionInterface, userGUID string) (string, error)
+}
+
 type metricsEmitter interface {
 	Increment(counter string)
 }
@@ -53,16 +65,21 @@ type DeliveryJobProcessor struct {
 	messageStatusUpdater    messageStatusUpdater
 	unsubscribersRepository unsubscribersRepositoryInterface
 	campaignsRepository     campaignsRepositoryInterface
+	campaignTypesRepository campaignTypesRepositoryInterface
+	suppressionsRepository  suppressionsRepositoryInterface
+	userLocalesRepository   userLocalesRepositoryInterface
 	database                db.DatabaseInterface
 	sender                  string
 	domain                  string
 	uaaHost                 string
+	publicURL               string
 	metricsEmitter          metricsEmitter
 }
 
 func NewDeliveryJobProcessor(mailClient mailSender, packager messagePackager, userLoader userLoader, tokenLoader tokenLoader,
 	messageStatusUpdater messageStatusUpdater, database db.DatabaseInterface, unsubscribersRepository unsubscribersRepositoryInterface,
-	campaignsRepository campaignsRepositoryInterface, sender, domain, uaaHost string, metricsEmitter metricsEmitter) DeliveryJobProcessor {
+	campaignsRepository campaignsRepositoryInterface, campaignTypesRepository campaignTypesRepositoryInterface,
+	suppressionsRepository suppressionsRepositoryInterface, userLocalesRepository userLocalesRepositoryInterface, sender, domain, uaaHost, publicURL string, metricsEmitter metricsEmitter) DeliveryJobProcessor {
 
 	return DeliveryJobProcessor{
 		mailClient:              mailClient,
@@ -71,11 +88,15 @@ func NewDeliveryJobProcessor(mailClient mailSender, packager messagePackager, us
 		tokenLoader:             tokenLoader,
 		messageStatusUpdater:    messageStatusUpdater,
 		campaignsRepository:     campaignsRepository,
+		campaignTypesRepository: campaignTypesRepository,
 		unsubscribersRepository: unsubscribersRepository,
+		suppressionsRepository:  suppressionsRepository,
+		userLocalesRepository:   userLocalesRepository,
 		database:                database,
 		sender:                  sender,
 		domain:                  domain,
 		uaaHost:                 uaaHost,
+		publicURL:               publicURL,
 		metricsEmitter:          metricsEmitter,
 	}
 }
@@ -117,9 +138,18 @@ func (p DeliveryJobProcessor) Process(delivery common.Delivery, logger lager.Log
 			return err
 		}
 
-		emails := users[delivery.UserGUID].Emails
-		if len(emails) > 0 {
-			delivery.Email = emails[0]
+		delivery.Profile = users[delivery.UserGUID]
+		if len(delivery.Profile.Emails) > 0 {
+			delivery.Email = delivery.Profile.Emails[0]
+		}
+
+		delivery.Locale, err = p.userLocalesRepository.Get(conn, delivery.UserGUID)
+		if err != nil {
+			return err
+		}
+
+		if delivery.Locale == "" {
+			delivery.Locale = delivery.Profile.Locale
 		}
 	}
 
@@ -128,18 +158,54 @@ func (p DeliveryJobProcessor) Process(delivery common.Delivery, logger lager.Log
 		return nil
 	}
 
+	_, err = p.suppressionsRepository.Get(conn, delivery.Email)
+	if err == nil {
+		p.messageStatusUpdater.Update(conn, delivery.MessageID, common.StatusSuppressed, delivery.CampaignID, logger)
+		p.metricsEmitter.Increment("notifications.worker.suppressed")
+		return nil
+	}
+
+	if _, ok := err.(models.RecordNotFoundError); !ok {
+		return err
+	}
+
 	context, err := p.packager.PrepareContext(delivery, p.sender, p.domain)
 	if err != nil {
 		return err
 	}
 
+	campaignType, err := p.campaignTypesRepository.Get(conn, campaign.CampaignTypeID)
+	if err != nil {
+		return err
+	}
+
+	context.Threading = campaignType.Threading
+	context.Critical = campaignType.Critical
+
+	if p.publicURL != "" && delivery.UserGUID != "" && !campaignType.Critical {
+		context.ListUnsubscribeURL = common.ListUnsubscribeURL(p.publicURL, context.UnsubscribeID, 2)
+	}
+
 	message, err := p.packager.Pack(context)
 	if err != nil {
+		if _, ok := err.(common.TemplateCompileError); ok {
+			logger.Error("template-pack-failed", err)
+			p.messageStatusUpdater.Update(conn, delivery.MessageID, common.StatusFailed, delivery.CampaignID, logger)
+			return nil
+		}
+
 		return err
 	}
 
 	err = p.mailClient.Send(message, logger)
 	if err != nil {
+		if _, ok := err.(mail.UndeliverableError); ok {
+			logger.Error("delivery-undeliverable", err)
+			p.messageStatusUpdater.Update(conn, delivery.MessageID, common.StatusUndeliverable, delivery.CampaignID, logger)
+			p.metricsEmitter.Increment("notifications.worker.undeliverable")
+			return nil
+		}
+
 		return err
 	}
 
@@ -31,7 +31,10 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		database                *mocks.Database
 		delivery                common.Delivery
 		campaignsRepository     *mocks.CampaignsRepository
+		campaignTypesRepository *mocks.CampaignTypesRepository
+		suppressionsRepository  *mocks.SuppressionsRepository
 		unsubscribersRepository *mocks.UnsubscribersRepository
+		userLocalesRepository   *mocks.UserLocalesRepository
 		metricsEmitter          *mocks.MetricsEmitter
 	)
 
@@ -58,8 +61,12 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		}
 
 		campaignsRepository = mocks.NewCampaignsRepository()
+		campaignTypesRepository = mocks.NewCampaignTypesRepository()
+		suppressionsRepository = mocks.NewSuppressionsRepository()
+		suppressionsRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("not suppressed")}
 		unsubscribersRepository = mocks.NewUnsubscribersRepository()
 		unsubscribersRepository.GetCall.Returns.Error = models.RecordNotFoundError{errors.New("not unsubscribed == will be delivered!")}
+		userLocalesRepository = mocks.NewUserLocalesRepository()
 
 		packager = mocks.NewPackager()
 		packager.PrepareContextCall.Returns.MessageContext = common.MessageContext{
@@ -138,8 +145,8 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		metricsEmitter = mocks.NewMetricsEmitter()
 
 		processor = v2.NewDeliveryJobProcessor(mailClient, packager, userLoader, tokenLoader,
-			messageStatusUpdater, database, unsubscribersRepository, campaignsRepository,
-			"from@example.com", "example.com", "uaa-host", metricsEmitter)
+			messageStatusUpdater, database, unsubscribersRepository, campaignsRepository, campaignTypesRepository, suppressionsRepository, userLocalesRepository,
+			"from@example.com", "example.com", "uaa-host", "", metricsEmitter)
 	})
 
 	It("ensures message delivery", func() {
@@ -152,6 +159,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		Expect(userLoader.LoadCall.Receives.Token).To(Equal("some-token"))
 
 		delivery.Email = "user-123@example.com"
+		delivery.Profile = uaa.User{Emails: []string{"user-123@example.com"}}
 		Expect(packager.PrepareContextCall.Receives.Delivery).To(Equal(delivery))
 		Expect(packager.PrepareContextCall.Receives.Sender).To(Equal("from@example.com"))
 		Expect(packager.PrepareContextCall.Receives.Domain).To(Equal("example.com"))
@@ -196,6 +204,137 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		Expect(metricsEmitter.IncrementCall.Receives.Counter).To(Equal("notifications.worker.delivered"))
 	})
 
+	It("passes the user's UAA profile to the packager", func() {
+		userLoader.LoadCall.Returns.Users["user-123"] = uaa.User{
+			ID:        "user-123",
+			Emails:    []string{"user-123@example.com"},
+			UserName:  "jdoe",
+			GivenName: "Jane",
+		}
+
+		err := processor.Process(delivery, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(packager.PrepareContextCall.Receives.Delivery.Profile).To(Equal(uaa.User{
+			ID:        "user-123",
+			Emails:    []string{"user-123@example.com"},
+			UserName:  "jdoe",
+			GivenName: "Jane",
+		}))
+	})
+
+	Context("when the recipient has a locale", func() {
+		It("uses the locale from the user's UAA profile", func() {
+			userLoader.LoadCall.Returns.Users["user-123"] = uaa.User{
+				Emails: []string{"user-123@example.com"},
+				Locale: "fr-CA",
+			}
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(packager.PrepareContextCall.Receives.Delivery.Locale).To(Equal("fr-CA"))
+		})
+
+		It("prefers the locale the user has chosen", func() {
+			userLoader.LoadCall.Returns.Users["user-123"] = uaa.User{
+				Emails: []string{"user-123@example.com"},
+				Locale: "fr-CA",
+			}
+			userLocalesRepository.GetCall.Returns.Locale = "de"
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(userLocalesRepository.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(userLocalesRepository.GetCall.Receives.UserGUID).To(Equal("user-123"))
+			Expect(packager.PrepareContextCall.Receives.Delivery.Locale).To(Equal("de"))
+		})
+	})
+
+	Context("when a public URL is configured", func() {
+		BeforeEach(func() {
+			campaignsRepository.GetCall.Returns.Campaign = models.Campaign{
+				ID:             "some-campaign-id",
+				CampaignTypeID: "some-campaign-type-id",
+			}
+			campaignTypesRepository.GetCall.Returns.CampaignType = models.CampaignType{
+				ID: "some-campaign-type-id",
+			}
+
+			processor = v2.NewDeliveryJobProcessor(mailClient, packager, userLoader, tokenLoader,
+				messageStatusUpdater, database, unsubscribersRepository, campaignsRepository, campaignTypesRepository, suppressionsRepository, userLocalesRepository,
+				"from@example.com", "example.com", "uaa-host", "https://notifications.example.com", metricsEmitter)
+		})
+
+		It("adds a one-click unsubscribe link for the campaign type", func() {
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(campaignTypesRepository.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(campaignTypesRepository.GetCall.Receives.CampaignTypeID).To(Equal("some-campaign-type-id"))
+
+			Expect(packager.PackCall.Receives.MessageContext.ListUnsubscribeURL).To(Equal("https://notifications.example.com/list_unsubscribe/eFGlsyNvaxtJ_lbV6KcY9BCb6O7H78pEPcLIARVkbTQt4dDrf2sqFjd9pfOOi439mVtNrTZJwhM=?version=2"))
+		})
+
+		It("does not add the link for critical campaign types", func() {
+			campaignTypesRepository.GetCall.Returns.CampaignType.Critical = true
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(packager.PackCall.Receives.MessageContext.ListUnsubscribeURL).To(BeEmpty())
+		})
+
+		It("does not add the link when the delivery has no user GUID", func() {
+			delivery.UserGUID = ""
+			delivery.Email = "someone@example.com"
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(packager.PackCall.Receives.MessageContext.ListUnsubscribeURL).To(BeEmpty())
+		})
+
+		Context("when the campaign type cannot be retrieved", func() {
+			It("returns the error", func() {
+				campaignTypesRepository.GetCall.Returns.Error = errors.New("some database error")
+
+				err := processor.Process(delivery, logger)
+				Expect(err).To(MatchError(errors.New("some database error")))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+		})
+	})
+
+	Context("when the campaign type opts into threading", func() {
+		It("marks the message context for threading", func() {
+			campaignTypesRepository.GetCall.Returns.CampaignType = models.CampaignType{
+				ID:        "some-campaign-type-id",
+				Threading: true,
+			}
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(packager.PackCall.Receives.MessageContext.Threading).To(BeTrue())
+		})
+	})
+
+	Context("when the campaign type is critical", func() {
+		It("marks the message context as critical", func() {
+			campaignTypesRepository.GetCall.Returns.CampaignType = models.CampaignType{
+				ID:       "some-campaign-type-id",
+				Critical: true,
+			}
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(packager.PackCall.Receives.MessageContext.Critical).To(BeTrue())
+		})
+	})
+
 	Context("when the delivery does not have a user GUID", func() {
 		BeforeEach(func() {
 			delivery.Email = "user-123@example.com"
@@ -308,7 +447,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			unsubscribersRepository.GetCall.Returns.Unsubscriber = models.Unsubscriber{
 				ID:             "some-id",
 				CampaignTypeID: "some-campaign-type-id",
-				UserGUID:       "user-123",
+			UserGUID:          "user-123",
 			}
 			campaignsRepository.GetCall.Returns.Campaign = models.Campaign{
 				CampaignTypeID: "some-campaign-type-id",
@@ -342,6 +481,36 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		})
 	})
 
+	Context("when the recipient's address is suppressed", func() {
+		BeforeEach(func() {
+			suppressionsRepository.GetCall.Returns.Error = nil
+			suppressionsRepository.GetCall.Returns.Suppression = models.Suppression{
+				Address: "user-123@example.com",
+				Reason:  "bounce",
+			}
+
+			err := processor.Process(delivery, logger)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not send the notification", func() {
+			Expect(suppressionsRepository.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(suppressionsRepository.GetCall.Receives.Address).To(Equal("user-123@example.com"))
+
+			Expect(mailClient.SendCall.CallCount).To(Equal(0))
+		})
+
+		It("marks the message as suppressed", func() {
+			Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal("randomly-generated-guid"))
+			Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusSuppressed))
+			Expect(messageStatusUpdater.UpdateCall.Receives.CampaignID).To(Equal("some-campaign-id"))
+		})
+
+		It("emits a metric indicating the suppression", func() {
+			Expect(metricsEmitter.IncrementCall.Receives.Counter).To(Equal("notifications.worker.suppressed"))
+		})
+	})
+
 	Context("failure cases", func() {
 		Context("when the campaigns repository has an error", func() {
 			It("returns the error", func() {
@@ -363,6 +532,16 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the suppression lookup has an unknown error", func() {
+			It("returns the error", func() {
+				suppressionsRepository.GetCall.Returns.Error = errors.New("some-suppression-error")
+
+				err := processor.Process(delivery, logger)
+				Expect(err).To(MatchError(errors.New("some-suppression-error")))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+		})
+
 		Context("when the token cannot be loaded", func() {
 			It("returns the error", func() {
 				tokenLoader.LoadCall.Returns.Error = errors.New("some-token-error")
@@ -383,6 +562,15 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the user's locale cannot be loaded", func() {
+			It("returns the error", func() {
+				userLocalesRepository.GetCall.Returns.Error = errors.New("some-locale-error")
+
+				err := processor.Process(delivery, logger)
+				Expect(err).To(MatchError(errors.New("some-locale-error")))
+			})
+		})
+
 		Context("when the packager fails to prepare the context", func() {
 			It("returns the error", func() {
 				packager.PrepareContextCall.Returns.Error = errors.New("some-packaging-error")
@@ -391,6 +579,16 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(MatchError(errors.New("some-packaging-error")))
 			})
+
+			It("marks the message as failed when the template cannot be rendered", func() {
+				packager.PackCall.Returns.Error = common.NewTemplateCompileError("html", common.TemplateLimitError{Err: errors.New("template: html: output exceeds the limit of 1048576 bytes")})
+
+				err := processor.Process(delivery, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
 		})
 
 		Context("when the packager fails to pack the message", func() {
@@ -401,6 +599,16 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(MatchError(errors.New("some-packaging-error")))
 			})
+
+			It("marks the message as failed when the template cannot be rendered", func() {
+				packager.PackCall.Returns.Error = common.NewTemplateCompileError("html", common.TemplateLimitError{Err: errors.New("template: html: output exceeds the limit of 1048576 bytes")})
+
+				err := processor.Process(delivery, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
 		})
 
 		Context("when the mail client fails to send the message", func() {
@@ -411,6 +619,16 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				Expect(err).To(HaveOccurred())
 				Expect(err).To(MatchError(errors.New("smtp error")))
 			})
+
+			It("marks the message as undeliverable when the address cannot be delivered to", func() {
+				mailClient.SendCall.Returns.Error = mail.UndeliverableError{Err: errors.New("address requires SMTPUTF8")}
+
+				err := processor.Process(delivery, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+				Expect(metricsEmitter.IncrementCall.Receives.Counter).To(Equal("notifications.worker.undeliverable"))
+			})
 		})
 	})
 })
@@ -1,6 +1,8 @@
 package v2
 
 import (
+	"encoding/json"
+
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
@@ -8,30 +10,119 @@ import (
 
 type templateGetter interface {
 	Get(connection collections.ConnectionInterface, templateID, clientID string) (collections.Template, error)
+	GetVersion(connection collections.ConnectionInterface, templateID, clientID string, version int) (collections.TemplateVersion, error)
+	Layouts(connection collections.ConnectionInterface, template collections.Template) ([]collections.Template, error)
+}
+
+type partialsLister interface {
+	List(connection collections.ConnectionInterface, clientID string) ([]collections.TemplatePartial, error)
 }
 
 type TemplatesLoader struct {
 	database            db.DatabaseInterface
 	templatesCollection templateGetter
+	partialsCollection  partialsLister
 }
 
-func NewTemplatesLoader(database db.DatabaseInterface, templatesCollection templateGetter) TemplatesLoader {
+func NewTemplatesLoader(database db.DatabaseInterface, templatesCollection templateGetter, partialsCollection partialsLister) TemplatesLoader {
 	return TemplatesLoader{
 		database:            database,
 		templatesCollection: templatesCollection,
+		partialsCollection:  partialsCollection,
 	}
 }
 
-func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string) (common.Templates, error) {
+// LoadTemplates composes the template with its layouts and the client's
+// partials. A templateVersion above zero pins the template itself to that
+// stored version; layouts and partials are not versioned with it and are
+// always loaded as they currently are.
+func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string, templateVersion int, locale string) (common.Templates, error) {
 	conn := loader.database.Connection()
-	template, err := loader.templatesCollection.Get(conn, templateID, clientID)
-	if err != nil {
-		return common.Templates{}, err
+
+	var template collections.Template
+	if templateVersion > 0 {
+		version, err := loader.templatesCollection.GetVersion(conn, templateID, clientID, templateVersion)
+		if err != nil {
+			return common.Templates{}, err
+		}
+
+		template = collections.Template{
+			ID:            templateID,
+			Subject:       version.Subject,
+			Text:          version.Text,
+			HTML:          version.HTML,
+			Localizations: version.Localizations,
+			LayoutID:      version.LayoutID,
+			ClientID:      clientID,
+
+			DisableAutoText:    version.DisableAutoText,
+			DisableCSSInlining: version.DisableCSSInlining,
+		}
+	} else {
+		var err error
+		template, err = loader.templatesCollection.Get(conn, templateID, clientID)
+		if err != nil {
+			return common.Templates{}, err
+		}
 	}
 
-	return common.Templates{
+	templates, err := localize(common.Templates{
 		Subject: template.Subject,
 		Text:    template.Text,
 		HTML:    template.HTML,
-	}, nil
+	}, template.Localizations, locale)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	layouts, err := loader.templatesCollection.Layouts(conn, template)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	var localizedLayouts []common.Templates
+	for _, layout := range layouts {
+		localized, err := localize(common.Templates{
+			Text: layout.Text,
+			HTML: layout.HTML,
+		}, layout.Localizations, locale)
+		if err != nil {
+			return common.Templates{}, err
+		}
+
+		localizedLayouts = append(localizedLayouts, localized)
+	}
+
+	partials, err := loader.partialsCollection.List(conn, clientID)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	var commonPartials []common.Partial
+	for _, partial := range partials {
+		commonPartials = append(commonPartials, common.Partial{
+			Name: partial.Name,
+			Text: partial.Text,
+			HTML: partial.HTML,
+		})
+	}
+
+	templates.DisableAutoText = template.DisableAutoText
+	templates.DisableCSSInlining = template.DisableCSSInlining
+
+	return common.ComposeTemplates(templates, localizedLayouts, commonPartials), nil
+}
+
+func localize(templates common.Templates, localizations, locale string) (common.Templates, error) {
+	if localizations == "" || locale == "" {
+		return templates, nil
+	}
+
+	var variants common.Localizations
+	err := json.Unmarshal([]byte(localizations), &variants)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	return variants.Apply(templates, locale), nil
 }
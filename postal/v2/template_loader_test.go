@@ -18,6 +18,7 @@ var _ = Describe("V2TemplateLoader", func() {
 		conn                db.ConnectionInterface
 		database            *mocks.Database
 		templatesCollection *mocks.TemplatesCollection
+		partialsCollection  *mocks.TemplatePartialsCollection
 		loader              v2.TemplatesLoader
 	)
 
@@ -27,7 +28,8 @@ var _ = Describe("V2TemplateLoader", func() {
 		database.ConnectionCall.Returns.Connection = conn
 
 		templatesCollection = mocks.NewTemplatesCollection()
-		loader = v2.NewTemplatesLoader(database, templatesCollection)
+		partialsCollection = mocks.NewTemplatePartialsCollection()
+		loader = v2.NewTemplatesLoader(database, templatesCollection, partialsCollection)
 	})
 
 	Describe("LoadTemplates", func() {
@@ -42,7 +44,7 @@ var _ = Describe("V2TemplateLoader", func() {
 			})
 
 			It("returns the template", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id")
+				templates, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 0, "")
 				Expect(err).ToNot(HaveOccurred())
 
 				Expect(templates).To(Equal(common.Templates{
@@ -56,11 +58,193 @@ var _ = Describe("V2TemplateLoader", func() {
 			})
 		})
 
+		Context("when the template disables automatic text or css inlining", func() {
+			It("carries the settings through to the packager", func() {
+				templatesCollection.GetCall.Returns.Template = collections.Template{
+					HTML:            "<p>v2 awesome</p>",
+					Subject:         "some subject",
+					ClientID:        "my-client-id",
+					DisableAutoText: true,
+				}
+
+				templates, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 0, "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates.DisableAutoText).To(BeTrue())
+				Expect(templates.DisableCSSInlining).To(BeFalse())
+			})
+
+			It("uses the settings recorded with a template version", func() {
+				templatesCollection.GetVersionCall.Returns.TemplateVersion = collections.TemplateVersion{
+					TemplateID:         "some-v2-template-id",
+					Version:            2,
+					HTML:               "<p>version two</p>",
+					DisableCSSInlining: true,
+				}
+
+				templates, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 2, "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates.DisableAutoText).To(BeFalse())
+				Expect(templates.DisableCSSInlining).To(BeTrue())
+			})
+		})
+
+		Context("when the recipient has a locale", func() {
+			BeforeEach(func() {
+				templatesCollection.GetCall.Returns.Template = collections.Template{
+					Text:          "some testing text",
+					Subject:       "some subject",
+					HTML:          "<p>v2 awesome</p>",
+					Localizations: `{"fr": {"subject": "un sujet", "text": "du texte"}, "pt-BR": {"html": "<p>incrível</p>"}}`,
+					ClientID:      "my-client-id",
+				}
+			})
+
+			It("returns the best matching variant, falling back to the default parts", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 0, "fr_CA")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(templates).To(Equal(common.Templates{
+					HTML:    "<p>v2 awesome</p>",
+					Text:    "du texte",
+					Subject: "un sujet",
+				}))
+			})
+
+			It("matches the region of a locale", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 0, "pt-br")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(templates.HTML).To(Equal("<p>incrível</p>"))
+				Expect(templates.Subject).To(Equal("some subject"))
+			})
+
+			It("returns the default template when no variant matches", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 0, "de")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(templates).To(Equal(common.Templates{
+					HTML:    "<p>v2 awesome</p>",
+					Text:    "some testing text",
+					Subject: "some subject",
+				}))
+			})
+		})
+
+		Context("when a template version is passed", func() {
+			BeforeEach(func() {
+				templatesCollection.GetVersionCall.Returns.TemplateVersion = collections.TemplateVersion{
+					TemplateID: "some-v2-template-id",
+					Version:    2,
+					Text:       "version two text",
+					Subject:    "version two subject",
+					HTML:       "<p>version two</p>",
+				}
+			})
+
+			It("returns the content of that version", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 2, "")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(templates).To(Equal(common.Templates{
+					HTML:    "<p>version two</p>",
+					Text:    "version two text",
+					Subject: "version two subject",
+				}))
+				Expect(templatesCollection.GetVersionCall.Receives.Connection).To(Equal(conn))
+				Expect(templatesCollection.GetVersionCall.Receives.TemplateID).To(Equal("some-v2-template-id"))
+				Expect(templatesCollection.GetVersionCall.Receives.ClientID).To(Equal("my-client-id"))
+				Expect(templatesCollection.GetVersionCall.Receives.Version).To(Equal(2))
+				Expect(templatesCollection.GetCall.Receives.TemplateID).To(BeEmpty())
+			})
+
+			It("returns an error when the version cannot be loaded", func() {
+				templatesCollection.GetVersionCall.Returns.Error = errors.New("version not found")
+
+				_, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 2, "")
+				Expect(err).To(MatchError("version not found"))
+			})
+		})
+
+		Context("when the template declares a layout and includes partials", func() {
+			BeforeEach(func() {
+				templatesCollection.GetCall.Returns.Template = collections.Template{
+					ID:            "some-v2-template-id",
+					Text:          `hello {{template "signature" .}}`,
+					Subject:       "some subject",
+					HTML:          "<p>hello</p>",
+					Localizations: `{"fr": {"text": "bonjour"}}`,
+					LayoutID:      "some-layout-id",
+					ClientID:      "my-client-id",
+				}
+				templatesCollection.LayoutsCall.Returns.Layouts = []collections.Template{
+					{
+						ID:            "some-layout-id",
+						Text:          `{{template "content" .}} -- footer`,
+						HTML:          `<div>{{template "content" .}}</div>`,
+						Localizations: `{"fr": {"text": "{{template \"content\" .}} -- pied de page"}}`,
+						ClientID:      "my-client-id",
+					},
+				}
+				partialsCollection.ListCall.Returns.Partials = []collections.TemplatePartial{
+					{Name: "signature", ClientID: "my-client-id", Text: "the team", HTML: "<b>the team</b>"},
+				}
+			})
+
+			It("wraps the template in the localized layouts and defines the partials", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 0, "fr")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(templates).To(Equal(common.Templates{
+					Subject: `some subject{{define "signature"}}the team{{end}}`,
+					Text:    `{{template "content" .}} -- pied de page{{define "content"}}bonjour{{end}}{{define "signature"}}the team{{end}}`,
+					HTML:    `<div>{{template "content" .}}</div>{{define "content"}}<p>hello</p>{{end}}{{define "signature"}}<b>the team</b>{{end}}`,
+				}))
+
+				Expect(templatesCollection.LayoutsCall.Receives.Connection).To(Equal(conn))
+				Expect(templatesCollection.LayoutsCall.Receives.Template.LayoutID).To(Equal("some-layout-id"))
+				Expect(partialsCollection.ListCall.Receives.Connection).To(Equal(conn))
+				Expect(partialsCollection.ListCall.Receives.ClientID).To(Equal("my-client-id"))
+			})
+
+			It("uses the layout recorded with a template version", func() {
+				templatesCollection.GetVersionCall.Returns.TemplateVersion = collections.TemplateVersion{
+					TemplateID: "some-v2-template-id",
+					Version:    2,
+					Text:       "version two text",
+					LayoutID:   "older-layout-id",
+				}
+
+				_, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 2, "")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(templatesCollection.LayoutsCall.Receives.Template).To(Equal(collections.Template{
+					ID:       "some-v2-template-id",
+					Text:     "version two text",
+					LayoutID: "older-layout-id",
+					ClientID: "my-client-id",
+				}))
+			})
+
+			It("returns an error when the layouts cannot be loaded", func() {
+				templatesCollection.LayoutsCall.Returns.Error = errors.New("layout not found")
+
+				_, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 0, "")
+				Expect(err).To(MatchError("layout not found"))
+			})
+
+			It("returns an error when the partials cannot be loaded", func() {
+				partialsCollection.ListCall.Returns.Error = errors.New("partials are unavailable")
+
+				_, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 0, "")
+				Expect(err).To(MatchError("partials are unavailable"))
+			})
+		})
+
 		Context("when the templates collection has an error", func() {
 			It("returns the error", func() {
 				templatesCollection.GetCall.Returns.Error = errors.New("some error on the collection")
 
-				_, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id")
+				_, err := loader.LoadTemplates("my-client-id", "", "some-v2-template-id", 0, "")
 				Expect(err).To(MatchError("some error on the collection"))
 			})
 		})
@@ -0,0 +1,55 @@
+package sanitize
+
+import "strings"
+
+// Policy allows HTML elements, attributes and URL schemes by name. Anything
+// it does not list is removed.
+type Policy struct {
+	Elements   []string `json:"elements"`
+	Attributes []string `json:"attributes"`
+	URLSchemes []string `json:"url_schemes"`
+}
+
+// StrictPolicy allows the formatting, table and image markup found in
+// ordinary mail. Scripts, frames, forms, embedded objects and event handlers
+// are all removed, and links may only use http, https, mailto and cid URLs.
+var StrictPolicy = Policy{
+	Elements: []string{
+		"a", "abbr", "address", "article", "aside", "b", "big", "blockquote", "br", "caption",
+		"center", "cite", "code", "col", "colgroup", "dd", "del", "div", "dl", "dt", "em",
+		"figcaption", "figure", "font", "footer", "h1", "h2", "h3", "h4", "h5", "h6", "header",
+		"hr", "i", "img", "ins", "li", "main", "mark", "meta", "nav", "ol", "p", "pre", "q", "s",
+		"section", "small", "span", "strike", "strong", "style", "sub", "sup", "table", "tbody",
+		"td", "tfoot", "th", "thead", "title", "tr", "tt", "u", "ul",
+	},
+	Attributes: []string{
+		"align", "alt", "bgcolor", "border", "cellpadding", "cellspacing", "charset", "class",
+		"color", "colspan", "content", "dir", "face", "height", "href", "hspace", "id", "lang",
+		"name", "rowspan", "size", "src", "style", "summary", "target", "title", "valign",
+		"vspace", "width",
+	},
+	URLSchemes: []string{"http", "https", "mailto", "cid"},
+}
+
+type compiledPolicy struct {
+	elements   map[string]bool
+	attributes map[string]bool
+	urlSchemes map[string]bool
+}
+
+func compile(policy Policy) compiledPolicy {
+	return compiledPolicy{
+		elements:   lowercaseSet(policy.Elements),
+		attributes: lowercaseSet(policy.Attributes),
+		urlSchemes: lowercaseSet(policy.URLSchemes),
+	}
+}
+
+func lowercaseSet(names []string) map[string]bool {
+	set := map[string]bool{}
+	for _, name := range names {
+		set[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	return set
+}
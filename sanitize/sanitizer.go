@@ -0,0 +1,262 @@
+package sanitize
+
+import (
+	"bytes"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Elements whose content is removed along with them when they are not
+// allowed, since it is code or markup that makes no sense on its own.
+var droppedWithContent = map[string]bool{
+	"applet":   true,
+	"embed":    true,
+	"frame":    true,
+	"frameset": true,
+	"head":     true,
+	"iframe":   true,
+	"math":     true,
+	"noembed":  true,
+	"noframes": true,
+	"noscript": true,
+	"object":   true,
+	"script":   true,
+	"select":   true,
+	"style":    true,
+	"svg":      true,
+	"template": true,
+	"textarea": true,
+	"title":    true,
+}
+
+var urlAttributes = map[string]bool{
+	"action":     true,
+	"background": true,
+	"cite":       true,
+	"formaction": true,
+	"href":       true,
+	"longdesc":   true,
+	"poster":     true,
+	"src":        true,
+	"usemap":     true,
+}
+
+var (
+	styleURL        = regexp.MustCompile(`(?i)url\(\s*['"]?([^'")]*)`)
+	styleExpression = regexp.MustCompile(`(?i)expression\s*\(`)
+)
+
+// Sanitizer removes the HTML a client's policy does not allow. Clients
+// without a policy of their own are held to the default policy.
+type Sanitizer struct {
+	policy         compiledPolicy
+	clientPolicies map[string]compiledPolicy
+}
+
+func NewSanitizer(policy Policy, clientPolicies map[string]Policy) Sanitizer {
+	sanitizer := Sanitizer{
+		policy:         compile(policy),
+		clientPolicies: map[string]compiledPolicy{},
+	}
+
+	for clientID, clientPolicy := range clientPolicies {
+		sanitizer.clientPolicies[clientID] = compile(clientPolicy)
+	}
+
+	return sanitizer
+}
+
+// Sanitize removes disallowed elements, attributes and URLs from an HTML
+// fragment. Most disallowed elements are replaced by their content, while
+// scripts, frames, embedded objects and the like are removed entirely.
+// Fragments that need no changes are returned exactly as they were given.
+func (s Sanitizer) Sanitize(clientID, fragment string) string {
+	context := &nethtml.Node{
+		Type:     nethtml.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	}
+
+	nodes, err := nethtml.ParseFragment(strings.NewReader(fragment), context)
+	if err != nil {
+		return ""
+	}
+
+	cleaner := cleaner{policy: s.policyFor(clientID)}
+	nodes = cleaner.cleanNodes(nodes)
+	if !cleaner.changed {
+		return fragment
+	}
+
+	buffer := bytes.NewBuffer([]byte{})
+	for _, node := range nodes {
+		nethtml.Render(buffer, node)
+	}
+
+	return buffer.String()
+}
+
+// SanitizeAttributes removes disallowed attributes from a list of attributes
+// written as they would be in a tag, e.g. `class="main" onload="track()"`.
+func (s Sanitizer) SanitizeAttributes(clientID, attributes string) string {
+	if strings.TrimSpace(attributes) == "" {
+		return attributes
+	}
+
+	context := &nethtml.Node{
+		Type:     nethtml.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	}
+
+	nodes, err := nethtml.ParseFragment(strings.NewReader("<div "+attributes+"></div>"), context)
+	if err != nil || len(nodes) != 1 {
+		return ""
+	}
+
+	cleaner := cleaner{policy: s.policyFor(clientID)}
+	kept := cleaner.cleanAttributes(nodes[0].Attr)
+	if !cleaner.changed {
+		return attributes
+	}
+
+	var written []string
+	for _, attribute := range kept {
+		written = append(written, attribute.Key+`="`+html.EscapeString(attribute.Val)+`"`)
+	}
+
+	return strings.Join(written, " ")
+}
+
+func (s Sanitizer) policyFor(clientID string) compiledPolicy {
+	if policy, ok := s.clientPolicies[clientID]; ok {
+		return policy
+	}
+
+	return s.policy
+}
+
+type cleaner struct {
+	policy  compiledPolicy
+	changed bool
+}
+
+func (c *cleaner) cleanNodes(nodes []*nethtml.Node) []*nethtml.Node {
+	var cleaned []*nethtml.Node
+	for _, node := range nodes {
+		cleaned = append(cleaned, c.cleanNode(node)...)
+	}
+
+	return cleaned
+}
+
+// cleanNode returns the nodes that take the place of the node once it has
+// been cleaned: the node itself, its cleaned children, or nothing at all.
+func (c *cleaner) cleanNode(node *nethtml.Node) []*nethtml.Node {
+	switch node.Type {
+	case nethtml.TextNode:
+		return []*nethtml.Node{node}
+	case nethtml.ElementNode:
+	default:
+		c.changed = true
+		return nil
+	}
+
+	var children []*nethtml.Node
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		children = append(children, child)
+	}
+
+	name := strings.ToLower(node.Data)
+	if !c.policy.elements[name] || node.Namespace != "" {
+		c.changed = true
+		if droppedWithContent[name] || node.Namespace != "" {
+			return nil
+		}
+
+		for _, child := range children {
+			node.RemoveChild(child)
+		}
+
+		return c.cleanNodes(children)
+	}
+
+	node.Attr = c.cleanAttributes(node.Attr)
+
+	for _, child := range children {
+		node.RemoveChild(child)
+	}
+
+	if name == "style" {
+		for _, child := range children {
+			if child.Type == nethtml.TextNode && !c.allowedStyle(child.Data) {
+				c.changed = true
+				continue
+			}
+			node.AppendChild(child)
+		}
+
+		return []*nethtml.Node{node}
+	}
+
+	for _, child := range c.cleanNodes(children) {
+		node.AppendChild(child)
+	}
+
+	return []*nethtml.Node{node}
+}
+
+func (c *cleaner) cleanAttributes(attributes []nethtml.Attribute) []nethtml.Attribute {
+	var kept []nethtml.Attribute
+	for _, attribute := range attributes {
+		key := strings.ToLower(attribute.Key)
+
+		allowed := attribute.Namespace == "" && c.policy.attributes[key] && !strings.HasPrefix(key, "on")
+		if allowed && urlAttributes[key] {
+			allowed = c.allowedURL(attribute.Val)
+		}
+		if allowed && key == "style" {
+			allowed = c.allowedStyle(attribute.Val)
+		}
+
+		if !allowed {
+			c.changed = true
+			continue
+		}
+
+		kept = append(kept, attribute)
+	}
+
+	return kept
+}
+
+// allowedURL accepts relative URLs and those with an allowed scheme.
+func (c *cleaner) allowedURL(value string) bool {
+	link, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return false
+	}
+
+	return link.Scheme == "" || c.policy.urlSchemes[strings.ToLower(link.Scheme)]
+}
+
+// allowedStyle rejects CSS that runs script through expression() or loads a
+// URL with a disallowed scheme.
+func (c *cleaner) allowedStyle(css string) bool {
+	if styleExpression.MatchString(css) {
+		return false
+	}
+
+	for _, match := range styleURL.FindAllStringSubmatch(css, -1) {
+		if !c.allowedURL(match[1]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,70 @@
+package sanitize_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/sanitize"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sanitizer", func() {
+	var sanitizer sanitize.Sanitizer
+
+	BeforeEach(func() {
+		sanitizer = sanitize.NewSanitizer(sanitize.StrictPolicy, map[string]sanitize.Policy{
+			"trusted-client": {
+				Elements:   []string{"p", "form", "input"},
+				Attributes: []string{"action", "name"},
+				URLSchemes: []string{"https"},
+			},
+		})
+	})
+
+	Describe("Sanitize", func() {
+		It("returns allowed HTML exactly as it was given", func() {
+			html := `<P class=intro>Hello <a href="https://example.com/?a=1&b=2">there</a><br></P>`
+
+			Expect(sanitizer.Sanitize("some-client", html)).To(Equal(html))
+		})
+
+		examples := []struct {
+			description string
+			html        string
+			sanitized   string
+		}{
+			{"scripts", `<p>hi<script>alert("x")</script></p>`, `<p>hi</p>`},
+			{"tracking frames", `<p>hi</p><iframe src="https://tracker.example.com"></iframe>`, `<p>hi</p>`},
+			{"forms, keeping their text", `<form action="https://evil.example.com"><label>Password</label><input name="password"></form>`, `Password`},
+			{"event handlers", `<img src="cid:logo" onerror="alert(1)">`, `<img src="cid:logo"/>`},
+			{"links with disallowed schemes", `<a href=" javascript:alert(1)">click</a>`, `<a>click</a>`},
+			{"styles that run script", `<div style="width: expression(alert(1))">hi</div>`, `<div>hi</div>`},
+			{"style sheets that load disallowed URLs", `<style>body { background: url("javascript:alert(1)") }</style><p>hi</p>`, `<style></style><p>hi</p>`},
+			{"comments", `<p>hi<!-- hidden --></p>`, `<p>hi</p>`},
+			{"svg", `<svg><script>alert(1)</script></svg><p>hi</p>`, `<p>hi</p>`},
+		}
+
+		for _, example := range examples {
+			example := example
+
+			It("removes "+example.description, func() {
+				Expect(sanitizer.Sanitize("some-client", example.html)).To(Equal(example.sanitized))
+			})
+		}
+
+		It("applies the policy granted to a client in place of the default", func() {
+			html := `<form action="https://example.com/unsubscribe"><input name="email"></form><img src="https://example.com/logo.png">`
+
+			Expect(sanitizer.Sanitize("trusted-client", html)).To(Equal(`<form action="https://example.com/unsubscribe"><input name="email"/></form>`))
+		})
+	})
+
+	Describe("SanitizeAttributes", func() {
+		It("returns allowed attributes exactly as they were given", func() {
+			Expect(sanitizer.SanitizeAttributes("some-client", `class='main' bgcolor=white`)).To(Equal(`class='main' bgcolor=white`))
+		})
+
+		It("removes disallowed attributes", func() {
+			Expect(sanitizer.SanitizeAttributes("some-client", `class="main" onload="track()" title="a &quot;b&quot;"`)).To(Equal(`class="main" title="a &#34;b&#34;"`))
+		})
+	})
+})
@@ -0,0 +1,27 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/mail"
+
+type AttachmentsLoader struct {
+	LoadAttachmentsCall struct {
+		WasCalled bool
+		Receives  struct {
+			AttachmentIDs []string
+		}
+		Returns struct {
+			Attachments []mail.Attachment
+			Error       error
+		}
+	}
+}
+
+func NewAttachmentsLoader() *AttachmentsLoader {
+	return &AttachmentsLoader{}
+}
+
+func (al *AttachmentsLoader) LoadAttachments(attachmentIDs []string) ([]mail.Attachment, error) {
+	al.LoadAttachmentsCall.WasCalled = true
+	al.LoadAttachmentsCall.Receives.AttachmentIDs = attachmentIDs
+
+	return al.LoadAttachmentsCall.Returns.Attachments, al.LoadAttachmentsCall.Returns.Error
+}
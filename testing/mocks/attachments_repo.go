@@ -0,0 +1,80 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type AttachmentsRepo struct {
+	CreateCall struct {
+		CallCount int
+		Receives  struct {
+			Connection  models.ConnectionInterface
+			Attachments []models.Attachment
+		}
+		Returns struct {
+			Attachments []models.Attachment
+			Error       error
+		}
+	}
+
+	FindByIDCall struct {
+		CallCount int
+		Receives  struct {
+			Connection    models.ConnectionInterface
+			AttachmentIDs []string
+		}
+		Returns struct {
+			Attachments []models.Attachment
+			Error       error
+		}
+	}
+
+	DeleteUnreferencedBeforeCall struct {
+		Receives struct {
+			Connection    models.ConnectionInterface
+			ThresholdTime time.Time
+		}
+		Returns struct {
+			RowsAffected int
+			Error        error
+		}
+	}
+}
+
+func NewAttachmentsRepo() *AttachmentsRepo {
+	return &AttachmentsRepo{}
+}
+
+func (ar *AttachmentsRepo) Create(conn models.ConnectionInterface, attachment models.Attachment) (models.Attachment, error) {
+	ar.CreateCall.Receives.Connection = conn
+	ar.CreateCall.Receives.Attachments = append(ar.CreateCall.Receives.Attachments, attachment)
+
+	if ar.CreateCall.CallCount < len(ar.CreateCall.Returns.Attachments) {
+		attachment = ar.CreateCall.Returns.Attachments[ar.CreateCall.CallCount]
+	}
+	ar.CreateCall.CallCount++
+
+	return attachment, ar.CreateCall.Returns.Error
+}
+
+func (ar *AttachmentsRepo) FindByID(conn models.ConnectionInterface, attachmentID string) (models.Attachment, error) {
+	ar.FindByIDCall.Receives.Connection = conn
+	ar.FindByIDCall.Receives.AttachmentIDs = append(ar.FindByIDCall.Receives.AttachmentIDs, attachmentID)
+
+	var attachment models.Attachment
+	if ar.FindByIDCall.CallCount < len(ar.FindByIDCall.Returns.Attachments) {
+		attachment = ar.FindByIDCall.Returns.Attachments[ar.FindByIDCall.CallCount]
+	}
+	ar.FindByIDCall.CallCount++
+
+	return attachment, ar.FindByIDCall.Returns.Error
+}
+
+func (ar *AttachmentsRepo) DeleteUnreferencedBefore(conn models.ConnectionInterface, threshold time.Time) (int, error) {
+	ar.DeleteUnreferencedBeforeCall.Receives.Connection = conn
+	ar.DeleteUnreferencedBeforeCall.Receives.ThresholdTime = threshold
+
+	return ar.DeleteUnreferencedBeforeCall.Returns.RowsAffected, ar.DeleteUnreferencedBeforeCall.Returns.Error
+}
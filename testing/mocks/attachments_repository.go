@@ -0,0 +1,58 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/models"
+
+type AttachmentsRepository struct {
+	InsertCall struct {
+		CallCount int
+		Receives  struct {
+			Connection  models.ConnectionInterface
+			Attachments []models.Attachment
+		}
+		Returns struct {
+			Attachments []models.Attachment
+			Error       error
+		}
+	}
+
+	GetCall struct {
+		CallCount int
+		Receives  struct {
+			Connection    models.ConnectionInterface
+			AttachmentIDs []string
+		}
+		Returns struct {
+			Attachments []models.Attachment
+			Error       error
+		}
+	}
+}
+
+func NewAttachmentsRepository() *AttachmentsRepository {
+	return &AttachmentsRepository{}
+}
+
+func (r *AttachmentsRepository) Insert(conn models.ConnectionInterface, attachment models.Attachment) (models.Attachment, error) {
+	r.InsertCall.Receives.Connection = conn
+	r.InsertCall.Receives.Attachments = append(r.InsertCall.Receives.Attachments, attachment)
+
+	if r.InsertCall.CallCount < len(r.InsertCall.Returns.Attachments) {
+		attachment = r.InsertCall.Returns.Attachments[r.InsertCall.CallCount]
+	}
+	r.InsertCall.CallCount++
+
+	return attachment, r.InsertCall.Returns.Error
+}
+
+func (r *AttachmentsRepository) Get(conn models.ConnectionInterface, attachmentID string) (models.Attachment, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.AttachmentIDs = append(r.GetCall.Receives.AttachmentIDs, attachmentID)
+
+	var attachment models.Attachment
+	if r.GetCall.CallCount < len(r.GetCall.Returns.Attachments) {
+		attachment = r.GetCall.Returns.Attachments[r.GetCall.CallCount]
+	}
+	r.GetCall.CallCount++
+
+	return attachment, r.GetCall.Returns.Error
+}
@@ -0,0 +1,54 @@
+package mocks
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
+
+type BounceProcessor struct {
+	ProcessCall struct {
+		Receives struct {
+			Database services.DatabaseInterface
+			Report   string
+		}
+		Returns struct {
+			Suppressed []string
+			Error      error
+		}
+	}
+
+	UnsuppressCall struct {
+		Receives struct {
+			Database services.DatabaseInterface
+			Address  string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewBounceProcessor() *BounceProcessor {
+	return &BounceProcessor{}
+}
+
+func (p *BounceProcessor) Process(database services.DatabaseInterface, report io.Reader) ([]string, error) {
+	body, err := ioutil.ReadAll(report)
+	if err != nil {
+		panic(err)
+	}
+
+	p.ProcessCall.Receives.Database = database
+	p.ProcessCall.Receives.Report = string(body)
+
+	return p.ProcessCall.Returns.Suppressed, p.ProcessCall.Returns.Error
+}
+
+func (p *BounceProcessor) Unsuppress(database services.DatabaseInterface, address string) error {
+	p.UnsuppressCall.Receives.Database = database
+	p.UnsuppressCall.Receives.Address = address
+
+	return p.UnsuppressCall.Returns.Error
+}
@@ -0,0 +1,51 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/collections"
+
+type BundlesCollection struct {
+	ExportCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ClientID   string
+		}
+		Returns struct {
+			Bundle collections.Bundle
+			Error  error
+		}
+	}
+
+	ImportCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection collections.ConnectionInterface
+			Bundle     collections.Bundle
+			ClientID   string
+			Options    collections.ImportOptions
+		}
+		Returns struct {
+			Records []collections.ImportedRecord
+			Error   error
+		}
+	}
+}
+
+func NewBundlesCollection() *BundlesCollection {
+	return &BundlesCollection{}
+}
+
+func (c *BundlesCollection) Export(conn collections.ConnectionInterface, clientID string) (collections.Bundle, error) {
+	c.ExportCall.Receives.Connection = conn
+	c.ExportCall.Receives.ClientID = clientID
+
+	return c.ExportCall.Returns.Bundle, c.ExportCall.Returns.Error
+}
+
+func (c *BundlesCollection) Import(conn collections.ConnectionInterface, bundle collections.Bundle, clientID string, options collections.ImportOptions) ([]collections.ImportedRecord, error) {
+	c.ImportCall.WasCalled = true
+	c.ImportCall.Receives.Connection = conn
+	c.ImportCall.Receives.Bundle = bundle
+	c.ImportCall.Receives.ClientID = clientID
+	c.ImportCall.Receives.Options = options
+
+	return c.ImportCall.Returns.Records, c.ImportCall.Returns.Error
+}
@@ -5,14 +5,17 @@ import "github.com/cloudfoundry-incubator/notifications/v2/collections"
 type CampaignTypesCollection struct {
 	SetCall struct {
 		Receives struct {
-			CampaignType collections.CampaignType
-			Conn         collections.ConnectionInterface
+			CampaignType  collections.CampaignType
+			CampaignTypes []collections.CampaignType
+			Conn          collections.ConnectionInterface
 		}
 		Returns struct {
-			CampaignType collections.CampaignType
-			Err          error
+			CampaignType  collections.CampaignType
+			CampaignTypes []collections.CampaignType
+			Err           error
 		}
 		WasCalled bool
+		CallCount int
 	}
 
 	ListCall struct {
@@ -59,8 +62,15 @@ func (c *CampaignTypesCollection) Set(conn collections.ConnectionInterface, camp
 	c.SetCall.WasCalled = true
 	c.SetCall.Receives.Conn = conn
 	c.SetCall.Receives.CampaignType = campaignType
+	c.SetCall.Receives.CampaignTypes = append(c.SetCall.Receives.CampaignTypes, campaignType)
+
+	returned := c.SetCall.Returns.CampaignType
+	if c.SetCall.CallCount < len(c.SetCall.Returns.CampaignTypes) {
+		returned = c.SetCall.Returns.CampaignTypes[c.SetCall.CallCount]
+	}
+	c.SetCall.CallCount++
 
-	return c.SetCall.Returns.CampaignType, c.SetCall.Returns.Err
+	return returned, c.SetCall.Returns.Err
 }
 
 func (c *CampaignTypesCollection) List(conn collections.ConnectionInterface, senderID, clientID string) ([]collections.CampaignType, error) {
@@ -25,6 +25,17 @@ type CampaignTypesRepository struct {
 		}
 	}
 
+	ListByTemplateIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			CampaignTypeList []models.CampaignType
+			Error            error
+		}
+	}
+
 	GetCall struct {
 		Receives struct {
 			Connection     models.ConnectionInterface
@@ -37,7 +48,8 @@ type CampaignTypesRepository struct {
 	}
 
 	UpdateCall struct {
-		Receives struct {
+		CallCount int
+		Receives  struct {
 			Connection   models.ConnectionInterface
 			CampaignType models.CampaignType
 		}
@@ -80,6 +92,13 @@ func (r *CampaignTypesRepository) List(conn models.ConnectionInterface, senderID
 	return r.ListCall.Returns.CampaignTypeList, r.ListCall.Returns.Error
 }
 
+func (r *CampaignTypesRepository) ListByTemplateID(conn models.ConnectionInterface, templateID string) ([]models.CampaignType, error) {
+	r.ListByTemplateIDCall.Receives.Connection = conn
+	r.ListByTemplateIDCall.Receives.TemplateID = templateID
+
+	return r.ListByTemplateIDCall.Returns.CampaignTypeList, r.ListByTemplateIDCall.Returns.Error
+}
+
 func (r *CampaignTypesRepository) Get(conn models.ConnectionInterface, campaignTypeID string) (models.CampaignType, error) {
 	r.GetCall.Receives.Connection = conn
 	r.GetCall.Receives.CampaignTypeID = campaignTypeID
@@ -88,6 +107,7 @@ func (r *CampaignTypesRepository) Get(conn models.ConnectionInterface, campaignT
 }
 
 func (r *CampaignTypesRepository) Update(conn models.ConnectionInterface, campaignType models.CampaignType) (models.CampaignType, error) {
+	r.UpdateCall.CallCount++
 	r.UpdateCall.Receives.Connection = conn
 	r.UpdateCall.Receives.CampaignType = campaignType
 
@@ -40,6 +40,17 @@ type CampaignsRepository struct {
 		}
 	}
 
+	ListSendingCampaignsByTemplateIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Campaigns []models.Campaign
+			Error     error
+		}
+	}
+
 	UpdateCall struct {
 		Receives struct {
 			Connection   models.ConnectionInterface
@@ -77,6 +88,13 @@ func (r *CampaignsRepository) ListSendingCampaigns(conn models.ConnectionInterfa
 	return r.ListSendingCampaignsCall.Returns.Campaigns, r.ListSendingCampaignsCall.Returns.Error
 }
 
+func (r *CampaignsRepository) ListSendingCampaignsByTemplateID(conn models.ConnectionInterface, templateID string) ([]models.Campaign, error) {
+	r.ListSendingCampaignsByTemplateIDCall.Receives.Connection = conn
+	r.ListSendingCampaignsByTemplateIDCall.Receives.TemplateID = templateID
+
+	return r.ListSendingCampaignsByTemplateIDCall.Returns.Campaigns, r.ListSendingCampaignsByTemplateIDCall.Returns.Error
+}
+
 func (r *CampaignsRepository) Update(conn models.ConnectionInterface, campaign models.Campaign) (models.Campaign, error) {
 	r.UpdateCall.Receives.Connection = conn
 	r.UpdateCall.Receives.CampaignList = append(r.UpdateCall.Receives.CampaignList, campaign)
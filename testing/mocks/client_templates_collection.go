@@ -0,0 +1,45 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/collections"
+
+type ClientTemplatesCollection struct {
+	GetCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ClientID   string
+		}
+		Returns struct {
+			ClientTemplate collections.ClientTemplate
+			Error          error
+		}
+	}
+
+	SetCall struct {
+		Receives struct {
+			Connection     collections.ConnectionInterface
+			ClientTemplate collections.ClientTemplate
+		}
+		Returns struct {
+			ClientTemplate collections.ClientTemplate
+			Error          error
+		}
+	}
+}
+
+func NewClientTemplatesCollection() *ClientTemplatesCollection {
+	return &ClientTemplatesCollection{}
+}
+
+func (c *ClientTemplatesCollection) Get(conn collections.ConnectionInterface, clientID string) (collections.ClientTemplate, error) {
+	c.GetCall.Receives.Connection = conn
+	c.GetCall.Receives.ClientID = clientID
+
+	return c.GetCall.Returns.ClientTemplate, c.GetCall.Returns.Error
+}
+
+func (c *ClientTemplatesCollection) Set(conn collections.ConnectionInterface, clientTemplate collections.ClientTemplate) (collections.ClientTemplate, error) {
+	c.SetCall.Receives.Connection = conn
+	c.SetCall.Receives.ClientTemplate = clientTemplate
+
+	return c.SetCall.Returns.ClientTemplate, c.SetCall.Returns.Error
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/models"
+
+type ClientTemplatesRepository struct {
+	UpsertCall struct {
+		Receives struct {
+			Connection     models.ConnectionInterface
+			ClientTemplate models.ClientTemplate
+		}
+		Returns struct {
+			ClientTemplate models.ClientTemplate
+			Error          error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+		}
+		Returns struct {
+			ClientTemplate models.ClientTemplate
+			Error          error
+		}
+	}
+}
+
+func NewClientTemplatesRepository() *ClientTemplatesRepository {
+	return &ClientTemplatesRepository{}
+}
+
+func (r *ClientTemplatesRepository) Upsert(conn models.ConnectionInterface, clientTemplate models.ClientTemplate) (models.ClientTemplate, error) {
+	r.UpsertCall.Receives.Connection = conn
+	r.UpsertCall.Receives.ClientTemplate = clientTemplate
+
+	return r.UpsertCall.Returns.ClientTemplate, r.UpsertCall.Returns.Error
+}
+
+func (r *ClientTemplatesRepository) Get(conn models.ConnectionInterface, clientID string) (models.ClientTemplate, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.ClientID = clientID
+
+	return r.GetCall.Returns.ClientTemplate, r.GetCall.Returns.Error
+}
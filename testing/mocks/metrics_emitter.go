@@ -3,7 +3,8 @@ package mocks
 type MetricsEmitter struct {
 	IncrementCall struct {
 		Receives struct {
-			Counter string
+			Counter  string
+			Counters []string
 		}
 	}
 }
@@ -14,4 +15,5 @@ func NewMetricsEmitter() *MetricsEmitter {
 
 func (e *MetricsEmitter) Increment(counter string) {
 	e.IncrementCall.Receives.Counter = counter
+	e.IncrementCall.Receives.Counters = append(e.IncrementCall.Receives.Counters, counter)
 }
@@ -17,6 +17,17 @@ type PreferenceUpdater struct {
 			Error error
 		}
 	}
+
+	SetLocaleCall struct {
+		Receives struct {
+			Connection services.ConnectionInterface
+			UserID     string
+			Locale     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
 }
 
 func NewPreferenceUpdater() *PreferenceUpdater {
@@ -31,3 +42,11 @@ func (pu *PreferenceUpdater) Update(conn services.ConnectionInterface, preferenc
 
 	return pu.UpdateCall.Returns.Error
 }
+
+func (pu *PreferenceUpdater) SetLocale(conn services.ConnectionInterface, userID, locale string) error {
+	pu.SetLocaleCall.Receives.Connection = conn
+	pu.SetLocaleCall.Receives.UserID = userID
+	pu.SetLocaleCall.Receives.Locale = locale
+
+	return pu.SetLocaleCall.Returns.Error
+}
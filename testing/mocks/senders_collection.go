@@ -4,13 +4,16 @@ import "github.com/cloudfoundry-incubator/notifications/v2/collections"
 
 type SendersCollection struct {
 	SetCall struct {
-		Receives struct {
+		CallCount int
+		Receives  struct {
 			Connection collections.ConnectionInterface
 			Sender     collections.Sender
+			Senders    []collections.Sender
 		}
 		Returns struct {
-			Sender collections.Sender
-			Error  error
+			Sender  collections.Sender
+			Senders []collections.Sender
+			Error   error
 		}
 	}
 
@@ -56,8 +59,15 @@ func NewSendersCollection() *SendersCollection {
 func (c *SendersCollection) Set(conn collections.ConnectionInterface, sender collections.Sender) (collections.Sender, error) {
 	c.SetCall.Receives.Connection = conn
 	c.SetCall.Receives.Sender = sender
+	c.SetCall.Receives.Senders = append(c.SetCall.Receives.Senders, sender)
+
+	returned := c.SetCall.Returns.Sender
+	if c.SetCall.CallCount < len(c.SetCall.Returns.Senders) {
+		returned = c.SetCall.Returns.Senders[c.SetCall.CallCount]
+	}
+	c.SetCall.CallCount++
 
-	return c.SetCall.Returns.Sender, c.SetCall.Returns.Error
+	return returned, c.SetCall.Returns.Error
 }
 
 func (c *SendersCollection) Get(conn collections.ConnectionInterface, senderID, clientID string) (collections.Sender, error) {
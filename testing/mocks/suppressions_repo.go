@@ -0,0 +1,63 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type SuppressionsRepo struct {
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Address    string
+		}
+		Returns struct {
+			Suppression models.Suppression
+			Error       error
+		}
+	}
+
+	UpsertCall struct {
+		CallCount int
+		Receives  struct {
+			Connection   models.ConnectionInterface
+			Suppressions []models.Suppression
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Address    string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewSuppressionsRepo() *SuppressionsRepo {
+	return &SuppressionsRepo{}
+}
+
+func (r *SuppressionsRepo) Find(conn models.ConnectionInterface, address string) (models.Suppression, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.Address = address
+
+	return r.FindCall.Returns.Suppression, r.FindCall.Returns.Error
+}
+
+func (r *SuppressionsRepo) Upsert(conn models.ConnectionInterface, suppression models.Suppression) (models.Suppression, error) {
+	r.UpsertCall.CallCount++
+	r.UpsertCall.Receives.Connection = conn
+	r.UpsertCall.Receives.Suppressions = append(r.UpsertCall.Receives.Suppressions, suppression)
+
+	return suppression, r.UpsertCall.Returns.Error
+}
+
+func (r *SuppressionsRepo) Delete(conn models.ConnectionInterface, address string) error {
+	r.DeleteCall.Receives.Connection = conn
+	r.DeleteCall.Receives.Address = address
+
+	return r.DeleteCall.Returns.Error
+}
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+)
+
+type SuppressionsRepository struct {
+	GetCall struct {
+		Receives struct {
+			Address    string
+			Connection db.ConnectionInterface
+		}
+		Returns struct {
+			Suppression models.Suppression
+			Error       error
+		}
+	}
+}
+
+func NewSuppressionsRepository() *SuppressionsRepository {
+	return &SuppressionsRepository{}
+}
+
+func (sr *SuppressionsRepository) Get(connection models.ConnectionInterface, address string) (models.Suppression, error) {
+	sr.GetCall.Receives.Connection = connection
+	sr.GetCall.Receives.Address = address
+
+	return sr.GetCall.Returns.Suppression, sr.GetCall.Returns.Error
+}
@@ -0,0 +1,47 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type TemplateBundler struct {
+	ExportCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+		}
+		Returns struct {
+			Bundle collections.Bundle
+			Error  error
+		}
+	}
+
+	ImportCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection collections.ConnectionInterface
+			Bundle     collections.Bundle
+			Options    collections.ImportOptions
+		}
+		Returns struct {
+			Records []collections.ImportedRecord
+			Error   error
+		}
+	}
+}
+
+func NewTemplateBundler() *TemplateBundler {
+	return &TemplateBundler{}
+}
+
+func (b *TemplateBundler) Export(conn collections.ConnectionInterface) (collections.Bundle, error) {
+	b.ExportCall.Receives.Connection = conn
+
+	return b.ExportCall.Returns.Bundle, b.ExportCall.Returns.Error
+}
+
+func (b *TemplateBundler) Import(conn collections.ConnectionInterface, bundle collections.Bundle, options collections.ImportOptions) ([]collections.ImportedRecord, error) {
+	b.ImportCall.WasCalled = true
+	b.ImportCall.Receives.Connection = conn
+	b.ImportCall.Receives.Bundle = bundle
+	b.ImportCall.Receives.Options = options
+
+	return b.ImportCall.Returns.Records, b.ImportCall.Returns.Error
+}
@@ -0,0 +1,86 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/collections"
+
+type TemplatePartialsCollection struct {
+	SetCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			Partial    collections.TemplatePartial
+			Partials   []collections.TemplatePartial
+		}
+		Returns struct {
+			Partial collections.TemplatePartial
+			Error   error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ClientID   string
+			Name       string
+		}
+		Returns struct {
+			Partial collections.TemplatePartial
+			Error   error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ClientID   string
+		}
+		Returns struct {
+			Partials []collections.TemplatePartial
+			Error    error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ClientID   string
+			Name       string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewTemplatePartialsCollection() *TemplatePartialsCollection {
+	return &TemplatePartialsCollection{}
+}
+
+func (c *TemplatePartialsCollection) Set(conn collections.ConnectionInterface, partial collections.TemplatePartial) (collections.TemplatePartial, error) {
+	c.SetCall.Receives.Connection = conn
+	c.SetCall.Receives.Partial = partial
+	c.SetCall.Receives.Partials = append(c.SetCall.Receives.Partials, partial)
+
+	return c.SetCall.Returns.Partial, c.SetCall.Returns.Error
+}
+
+func (c *TemplatePartialsCollection) Get(conn collections.ConnectionInterface, clientID, name string) (collections.TemplatePartial, error) {
+	c.GetCall.Receives.Connection = conn
+	c.GetCall.Receives.ClientID = clientID
+	c.GetCall.Receives.Name = name
+
+	return c.GetCall.Returns.Partial, c.GetCall.Returns.Error
+}
+
+func (c *TemplatePartialsCollection) List(conn collections.ConnectionInterface, clientID string) ([]collections.TemplatePartial, error) {
+	c.ListCall.Receives.Connection = conn
+	c.ListCall.Receives.ClientID = clientID
+
+	return c.ListCall.Returns.Partials, c.ListCall.Returns.Error
+}
+
+func (c *TemplatePartialsCollection) Delete(conn collections.ConnectionInterface, clientID, name string) error {
+	c.DeleteCall.Receives.Connection = conn
+	c.DeleteCall.Receives.ClientID = clientID
+	c.DeleteCall.Receives.Name = name
+
+	return c.DeleteCall.Returns.Error
+}
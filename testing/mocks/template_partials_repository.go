@@ -0,0 +1,82 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/models"
+
+type TemplatePartialsRepository struct {
+	UpsertCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Partial    models.TemplatePartial
+		}
+		Returns struct {
+			Partial models.TemplatePartial
+			Error   error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+			Name       string
+		}
+		Returns struct {
+			Partial models.TemplatePartial
+			Error   error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+		}
+		Returns struct {
+			Partials []models.TemplatePartial
+			Error    error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Partial    models.TemplatePartial
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewTemplatePartialsRepository() *TemplatePartialsRepository {
+	return &TemplatePartialsRepository{}
+}
+
+func (r *TemplatePartialsRepository) Upsert(conn models.ConnectionInterface, partial models.TemplatePartial) (models.TemplatePartial, error) {
+	r.UpsertCall.Receives.Connection = conn
+	r.UpsertCall.Receives.Partial = partial
+
+	return r.UpsertCall.Returns.Partial, r.UpsertCall.Returns.Error
+}
+
+func (r *TemplatePartialsRepository) Get(conn models.ConnectionInterface, clientID, name string) (models.TemplatePartial, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.ClientID = clientID
+	r.GetCall.Receives.Name = name
+
+	return r.GetCall.Returns.Partial, r.GetCall.Returns.Error
+}
+
+func (r *TemplatePartialsRepository) List(conn models.ConnectionInterface, clientID string) ([]models.TemplatePartial, error) {
+	r.ListCall.Receives.Connection = conn
+	r.ListCall.Receives.ClientID = clientID
+
+	return r.ListCall.Returns.Partials, r.ListCall.Returns.Error
+}
+
+func (r *TemplatePartialsRepository) Delete(conn models.ConnectionInterface, partial models.TemplatePartial) error {
+	r.DeleteCall.Receives.Connection = conn
+	r.DeleteCall.Receives.Partial = partial
+
+	return r.DeleteCall.Returns.Error
+}
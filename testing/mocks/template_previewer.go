@@ -0,0 +1,27 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/postal/common"
+
+type TemplatePreviewer struct {
+	PreviewCall struct {
+		Receives struct {
+			Delivery  common.Delivery
+			Templates common.Templates
+		}
+		Returns struct {
+			Preview common.Preview
+			Error   error
+		}
+	}
+}
+
+func NewTemplatePreviewer() *TemplatePreviewer {
+	return &TemplatePreviewer{}
+}
+
+func (p *TemplatePreviewer) Preview(delivery common.Delivery, templates common.Templates) (common.Preview, error) {
+	p.PreviewCall.Receives.Delivery = delivery
+	p.PreviewCall.Receives.Templates = templates
+
+	return p.PreviewCall.Returns.Preview, p.PreviewCall.Returns.Error
+}
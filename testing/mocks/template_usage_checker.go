@@ -0,0 +1,27 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type TemplateUsageChecker struct {
+	UsedOnlyByCriticalCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Critical bool
+			Error    error
+		}
+	}
+}
+
+func NewTemplateUsageChecker() *TemplateUsageChecker {
+	return &TemplateUsageChecker{}
+}
+
+func (tc *TemplateUsageChecker) UsedOnlyByCritical(connection collections.ConnectionInterface, templateID string) (bool, error) {
+	tc.UsedOnlyByCriticalCall.Receives.Connection = connection
+	tc.UsedOnlyByCriticalCall.Receives.TemplateID = templateID
+
+	return tc.UsedOnlyByCriticalCall.Returns.Critical, tc.UsedOnlyByCriticalCall.Returns.Error
+}
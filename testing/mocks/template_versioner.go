@@ -0,0 +1,89 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type TemplateVersioner struct {
+	ListVersionsCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			TemplateVersions []collections.TemplateVersion
+			Error            error
+		}
+	}
+
+	GetVersionCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			Version    int
+		}
+		Returns struct {
+			TemplateVersion collections.TemplateVersion
+			Error           error
+		}
+	}
+
+	DiffVersionsCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			From       int
+			To         int
+		}
+		Returns struct {
+			TemplateDiff collections.TemplateDiff
+			Error        error
+		}
+	}
+
+	RollbackCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			Version    int
+		}
+		Returns struct {
+			Template collections.Template
+			Error    error
+		}
+	}
+}
+
+func NewTemplateVersioner() *TemplateVersioner {
+	return &TemplateVersioner{}
+}
+
+func (v *TemplateVersioner) ListVersions(connection collections.ConnectionInterface, templateID string) ([]collections.TemplateVersion, error) {
+	v.ListVersionsCall.Receives.Connection = connection
+	v.ListVersionsCall.Receives.TemplateID = templateID
+
+	return v.ListVersionsCall.Returns.TemplateVersions, v.ListVersionsCall.Returns.Error
+}
+
+func (v *TemplateVersioner) GetVersion(connection collections.ConnectionInterface, templateID string, version int) (collections.TemplateVersion, error) {
+	v.GetVersionCall.Receives.Connection = connection
+	v.GetVersionCall.Receives.TemplateID = templateID
+	v.GetVersionCall.Receives.Version = version
+
+	return v.GetVersionCall.Returns.TemplateVersion, v.GetVersionCall.Returns.Error
+}
+
+func (v *TemplateVersioner) DiffVersions(connection collections.ConnectionInterface, templateID string, from, to int) (collections.TemplateDiff, error) {
+	v.DiffVersionsCall.Receives.Connection = connection
+	v.DiffVersionsCall.Receives.TemplateID = templateID
+	v.DiffVersionsCall.Receives.From = from
+	v.DiffVersionsCall.Receives.To = to
+
+	return v.DiffVersionsCall.Returns.TemplateDiff, v.DiffVersionsCall.Returns.Error
+}
+
+func (v *TemplateVersioner) Rollback(connection collections.ConnectionInterface, templateID string, version int) (collections.Template, error) {
+	v.RollbackCall.Receives.Connection = connection
+	v.RollbackCall.Receives.TemplateID = templateID
+	v.RollbackCall.Receives.Version = version
+
+	return v.RollbackCall.Returns.Template, v.RollbackCall.Returns.Error
+}
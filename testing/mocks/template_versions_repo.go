@@ -0,0 +1,84 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type TemplateVersionsRepo struct {
+	CreateCall struct {
+		CallCount int
+		Receives  struct {
+			Connection      models.ConnectionInterface
+			TemplateVersion models.TemplateVersion
+		}
+		Returns struct {
+			TemplateVersion models.TemplateVersion
+			Error           error
+		}
+	}
+
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+			Version    int
+		}
+		Returns struct {
+			TemplateVersions map[int]models.TemplateVersion
+			Error            error
+		}
+	}
+
+	FindAllByTemplateIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			TemplateVersions []models.TemplateVersion
+			Error            error
+		}
+	}
+
+	DestroyAllByTemplateIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewTemplateVersionsRepo() *TemplateVersionsRepo {
+	return &TemplateVersionsRepo{}
+}
+
+func (r *TemplateVersionsRepo) Create(conn models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error) {
+	r.CreateCall.CallCount++
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.TemplateVersion = version
+
+	return r.CreateCall.Returns.TemplateVersion, r.CreateCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepo) Find(conn models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.TemplateID = templateID
+	r.FindCall.Receives.Version = version
+
+	return r.FindCall.Returns.TemplateVersions[version], r.FindCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepo) FindAllByTemplateID(conn models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error) {
+	r.FindAllByTemplateIDCall.Receives.Connection = conn
+	r.FindAllByTemplateIDCall.Receives.TemplateID = templateID
+
+	return r.FindAllByTemplateIDCall.Returns.TemplateVersions, r.FindAllByTemplateIDCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepo) DestroyAllByTemplateID(conn models.ConnectionInterface, templateID string) error {
+	r.DestroyAllByTemplateIDCall.Receives.Connection = conn
+	r.DestroyAllByTemplateIDCall.Receives.TemplateID = templateID
+
+	return r.DestroyAllByTemplateIDCall.Returns.Error
+}
@@ -0,0 +1,84 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/models"
+
+type TemplateVersionsRepository struct {
+	InsertCall struct {
+		CallCount int
+		Receives  struct {
+			Connection      models.ConnectionInterface
+			TemplateVersion models.TemplateVersion
+		}
+		Returns struct {
+			TemplateVersion models.TemplateVersion
+			Error           error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+			Version    int
+		}
+		Returns struct {
+			TemplateVersions map[int]models.TemplateVersion
+			Error            error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			TemplateVersions []models.TemplateVersion
+			Error            error
+		}
+	}
+
+	DeleteAllCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewTemplateVersionsRepository() *TemplateVersionsRepository {
+	return &TemplateVersionsRepository{}
+}
+
+func (r *TemplateVersionsRepository) Insert(conn models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error) {
+	r.InsertCall.CallCount++
+	r.InsertCall.Receives.Connection = conn
+	r.InsertCall.Receives.TemplateVersion = version
+
+	return r.InsertCall.Returns.TemplateVersion, r.InsertCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepository) Get(conn models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.TemplateID = templateID
+	r.GetCall.Receives.Version = version
+
+	return r.GetCall.Returns.TemplateVersions[version], r.GetCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepository) List(conn models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error) {
+	r.ListCall.Receives.Connection = conn
+	r.ListCall.Receives.TemplateID = templateID
+
+	return r.ListCall.Returns.TemplateVersions, r.ListCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepository) DeleteAll(conn models.ConnectionInterface, templateID string) error {
+	r.DeleteAllCall.Receives.Connection = conn
+	r.DeleteAllCall.Receives.TemplateID = templateID
+
+	return r.DeleteAllCall.Returns.Error
+}
@@ -7,13 +7,16 @@ import (
 
 type TemplatesCollection struct {
 	SetCall struct {
-		Receives struct {
+		CallCount int
+		Receives  struct {
 			Connection db.ConnectionInterface
 			Template   collections.Template
+			Templates  []collections.Template
 		}
 		Returns struct {
-			Template collections.Template
-			Error    error
+			Template  collections.Template
+			Templates []collections.Template
+			Error     error
 		}
 	}
 
@@ -30,12 +33,38 @@ type TemplatesCollection struct {
 	}
 
 	DeleteCall struct {
+		Receives struct {
+			Connection    collections.ConnectionInterface
+			TemplateID    string
+			ClientID      string
+			ReplacementID string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	ListAssociationsCall struct {
 		Receives struct {
 			Connection collections.ConnectionInterface
 			TemplateID string
+			ClientID   string
 		}
 		Returns struct {
-			Error error
+			Associations collections.TemplateAssociations
+			Error        error
+		}
+	}
+
+	UsedOnlyByCriticalCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			ClientID   string
+		}
+		Returns struct {
+			Critical bool
+			Error    error
 		}
 	}
 
@@ -49,6 +78,69 @@ type TemplatesCollection struct {
 			Error     error
 		}
 	}
+
+	GetVersionCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			ClientID   string
+			Version    int
+		}
+		Returns struct {
+			TemplateVersion collections.TemplateVersion
+			Error           error
+		}
+	}
+
+	ListVersionsCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			ClientID   string
+		}
+		Returns struct {
+			TemplateVersions []collections.TemplateVersion
+			Error            error
+		}
+	}
+
+	DiffVersionsCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			ClientID   string
+			From       int
+			To         int
+		}
+		Returns struct {
+			TemplateDiff collections.TemplateDiff
+			Error        error
+		}
+	}
+
+	RollbackCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			TemplateID string
+			ClientID   string
+			Version    int
+		}
+		Returns struct {
+			Template collections.Template
+			Error    error
+		}
+	}
+
+	LayoutsCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			Template   collections.Template
+		}
+		Returns struct {
+			Layouts []collections.Template
+			Error   error
+		}
+	}
 }
 
 func NewTemplatesCollection() *TemplatesCollection {
@@ -58,8 +150,15 @@ func NewTemplatesCollection() *TemplatesCollection {
 func (c *TemplatesCollection) Set(conn collections.ConnectionInterface, template collections.Template) (collections.Template, error) {
 	c.SetCall.Receives.Connection = conn
 	c.SetCall.Receives.Template = template
+	c.SetCall.Receives.Templates = append(c.SetCall.Receives.Templates, template)
 
-	return c.SetCall.Returns.Template, c.SetCall.Returns.Error
+	returned := c.SetCall.Returns.Template
+	if c.SetCall.CallCount < len(c.SetCall.Returns.Templates) {
+		returned = c.SetCall.Returns.Templates[c.SetCall.CallCount]
+	}
+	c.SetCall.CallCount++
+
+	return returned, c.SetCall.Returns.Error
 }
 
 func (c *TemplatesCollection) Get(conn collections.ConnectionInterface, templateID, clientID string) (collections.Template, error) {
@@ -70,15 +169,76 @@ func (c *TemplatesCollection) Get(conn collections.ConnectionInterface, template
 	return c.GetCall.Returns.Template, c.GetCall.Returns.Error
 }
 
-func (c *TemplatesCollection) Delete(conn collections.ConnectionInterface, templateID string) error {
+func (c *TemplatesCollection) Delete(conn collections.ConnectionInterface, templateID, clientID, replacementID string) error {
 	c.DeleteCall.Receives.Connection = conn
 	c.DeleteCall.Receives.TemplateID = templateID
+	c.DeleteCall.Receives.ClientID = clientID
+	c.DeleteCall.Receives.ReplacementID = replacementID
 
 	return c.DeleteCall.Returns.Error
 }
 
+func (c *TemplatesCollection) ListAssociations(conn collections.ConnectionInterface, templateID, clientID string) (collections.TemplateAssociations, error) {
+	c.ListAssociationsCall.Receives.Connection = conn
+	c.ListAssociationsCall.Receives.TemplateID = templateID
+	c.ListAssociationsCall.Receives.ClientID = clientID
+
+	return c.ListAssociationsCall.Returns.Associations, c.ListAssociationsCall.Returns.Error
+}
+
 func (c *TemplatesCollection) List(conn collections.ConnectionInterface, clientID string) ([]collections.Template, error) {
 	c.ListCall.Receives.Connection = conn
 	c.ListCall.Receives.ClientID = clientID
 	return c.ListCall.Returns.Templates, c.ListCall.Returns.Error
 }
+
+func (c *TemplatesCollection) GetVersion(conn collections.ConnectionInterface, templateID, clientID string, version int) (collections.TemplateVersion, error) {
+	c.GetVersionCall.Receives.Connection = conn
+	c.GetVersionCall.Receives.TemplateID = templateID
+	c.GetVersionCall.Receives.ClientID = clientID
+	c.GetVersionCall.Receives.Version = version
+
+	return c.GetVersionCall.Returns.TemplateVersion, c.GetVersionCall.Returns.Error
+}
+
+func (c *TemplatesCollection) ListVersions(conn collections.ConnectionInterface, templateID, clientID string) ([]collections.TemplateVersion, error) {
+	c.ListVersionsCall.Receives.Connection = conn
+	c.ListVersionsCall.Receives.TemplateID = templateID
+	c.ListVersionsCall.Receives.ClientID = clientID
+
+	return c.ListVersionsCall.Returns.TemplateVersions, c.ListVersionsCall.Returns.Error
+}
+
+func (c *TemplatesCollection) DiffVersions(conn collections.ConnectionInterface, templateID, clientID string, from, to int) (collections.TemplateDiff, error) {
+	c.DiffVersionsCall.Receives.Connection = conn
+	c.DiffVersionsCall.Receives.TemplateID = templateID
+	c.DiffVersionsCall.Receives.ClientID = clientID
+	c.DiffVersionsCall.Receives.From = from
+	c.DiffVersionsCall.Receives.To = to
+
+	return c.DiffVersionsCall.Returns.TemplateDiff, c.DiffVersionsCall.Returns.Error
+}
+
+func (c *TemplatesCollection) Rollback(conn collections.ConnectionInterface, templateID, clientID string, version int) (collections.Template, error) {
+	c.RollbackCall.Receives.Connection = conn
+	c.RollbackCall.Receives.TemplateID = templateID
+	c.RollbackCall.Receives.ClientID = clientID
+	c.RollbackCall.Receives.Version = version
+
+	return c.RollbackCall.Returns.Template, c.RollbackCall.Returns.Error
+}
+
+func (c *TemplatesCollection) Layouts(conn collections.ConnectionInterface, template collections.Template) ([]collections.Template, error) {
+	c.LayoutsCall.Receives.Connection = conn
+	c.LayoutsCall.Receives.Template = template
+
+	return c.LayoutsCall.Returns.Layouts, c.LayoutsCall.Returns.Error
+}
+
+func (c *TemplatesCollection) UsedOnlyByCritical(conn collections.ConnectionInterface, templateID, clientID string) (bool, error) {
+	c.UsedOnlyByCriticalCall.Receives.Connection = conn
+	c.UsedOnlyByCriticalCall.Receives.TemplateID = templateID
+	c.UsedOnlyByCriticalCall.Receives.ClientID = clientID
+
+	return c.UsedOnlyByCriticalCall.Returns.Critical, c.UsedOnlyByCriticalCall.Returns.Error
+}
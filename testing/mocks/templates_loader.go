@@ -5,9 +5,11 @@ import "github.com/cloudfoundry-incubator/notifications/postal/common"
 type TemplatesLoader struct {
 	LoadTemplatesCall struct {
 		Receives struct {
-			ClientID   string
-			KindID     string
-			TemplateID string
+			ClientID        string
+			KindID          string
+			TemplateID      string
+			TemplateVersion int
+			Locale          string
 		}
 		Returns struct {
 			Templates common.Templates
@@ -20,10 +22,12 @@ func NewTemplatesLoader() *TemplatesLoader {
 	return &TemplatesLoader{}
 }
 
-func (tl *TemplatesLoader) LoadTemplates(clientID, kindID, templateID string) (common.Templates, error) {
+func (tl *TemplatesLoader) LoadTemplates(clientID, kindID, templateID string, templateVersion int, locale string) (common.Templates, error) {
 	tl.LoadTemplatesCall.Receives.ClientID = clientID
 	tl.LoadTemplatesCall.Receives.KindID = kindID
 	tl.LoadTemplatesCall.Receives.TemplateID = templateID
+	tl.LoadTemplatesCall.Receives.TemplateVersion = templateVersion
+	tl.LoadTemplatesCall.Receives.Locale = locale
 
 	return tl.LoadTemplatesCall.Returns.Templates, tl.LoadTemplatesCall.Returns.Error
 }
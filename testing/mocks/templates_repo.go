@@ -35,6 +35,28 @@ type TemplatesRepo struct {
 		}
 	}
 
+	FindLayoutsCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Template   models.Template
+		}
+		Returns struct {
+			Layouts []models.Template
+			Error   error
+		}
+	}
+
+	FindAllByLayoutIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			LayoutID   string
+		}
+		Returns struct {
+			Templates []models.Template
+			Error     error
+		}
+	}
+
 	ListIDsAndNamesCall struct {
 		Receives struct {
 			Connection models.ConnectionInterface
@@ -83,6 +105,13 @@ func (tr *TemplatesRepo) FindByID(conn models.ConnectionInterface, templateID st
 	return tr.FindByIDCall.Returns.Template, tr.FindByIDCall.Returns.Error
 }
 
+func (tr *TemplatesRepo) FindAllByLayoutID(conn models.ConnectionInterface, layoutID string) ([]models.Template, error) {
+	tr.FindAllByLayoutIDCall.Receives.Connection = conn
+	tr.FindAllByLayoutIDCall.Receives.LayoutID = layoutID
+
+	return tr.FindAllByLayoutIDCall.Returns.Templates, tr.FindAllByLayoutIDCall.Returns.Error
+}
+
 func (tr *TemplatesRepo) ListIDsAndNames(conn models.ConnectionInterface) ([]models.Template, error) {
 	tr.ListIDsAndNamesCall.Receives.Connection = conn
 
@@ -96,3 +125,10 @@ func (tr *TemplatesRepo) Update(conn models.ConnectionInterface, templateID stri
 
 	return tr.UpdateCall.Returns.Template, tr.UpdateCall.Returns.Error
 }
+
+func (tr *TemplatesRepo) FindLayouts(conn models.ConnectionInterface, template models.Template) ([]models.Template, error) {
+	tr.FindLayoutsCall.Receives.Connection = conn
+	tr.FindLayoutsCall.Receives.Template = template
+
+	return tr.FindLayoutsCall.Returns.Layouts, tr.FindLayoutsCall.Returns.Error
+}
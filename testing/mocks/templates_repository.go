@@ -26,6 +26,20 @@ type TemplatesRepository struct {
 	}
 
 	GetCall struct {
+		CallCount int
+		Receives  struct {
+			Connection  models.ConnectionInterface
+			TemplateID  string
+			TemplateIDs []string
+		}
+		Returns struct {
+			Template  models.Template
+			Templates []models.Template
+			Error     error
+		}
+	}
+
+	GetForUpdateCall struct {
 		Receives struct {
 			Connection models.ConnectionInterface
 			TemplateID string
@@ -57,6 +71,17 @@ type TemplatesRepository struct {
 			Error     error
 		}
 	}
+
+	ListByLayoutIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			LayoutID   string
+		}
+		Returns struct {
+			Templates []models.Template
+			Error     error
+		}
+	}
 }
 
 func NewTemplatesRepository() *TemplatesRepository {
@@ -73,8 +98,22 @@ func (r *TemplatesRepository) Insert(conn models.ConnectionInterface, template m
 func (r *TemplatesRepository) Get(conn models.ConnectionInterface, templateID string) (models.Template, error) {
 	r.GetCall.Receives.Connection = conn
 	r.GetCall.Receives.TemplateID = templateID
+	r.GetCall.Receives.TemplateIDs = append(r.GetCall.Receives.TemplateIDs, templateID)
+
+	template := r.GetCall.Returns.Template
+	if r.GetCall.CallCount < len(r.GetCall.Returns.Templates) {
+		template = r.GetCall.Returns.Templates[r.GetCall.CallCount]
+	}
+	r.GetCall.CallCount++
 
-	return r.GetCall.Returns.Template, r.GetCall.Returns.Error
+	return template, r.GetCall.Returns.Error
+}
+
+func (r *TemplatesRepository) GetForUpdate(conn models.ConnectionInterface, templateID string) (models.Template, error) {
+	r.GetForUpdateCall.Receives.Connection = conn
+	r.GetForUpdateCall.Receives.TemplateID = templateID
+
+	return r.GetForUpdateCall.Returns.Template, r.GetForUpdateCall.Returns.Error
 }
 
 func (r *TemplatesRepository) Delete(conn models.ConnectionInterface, templateID string) error {
@@ -97,3 +136,10 @@ func (r *TemplatesRepository) List(conn models.ConnectionInterface, clientID str
 
 	return r.ListCall.Returns.Templates, r.ListCall.Returns.Error
 }
+
+func (r *TemplatesRepository) ListByLayoutID(conn models.ConnectionInterface, layoutID string) ([]models.Template, error) {
+	r.ListByLayoutIDCall.Receives.Connection = conn
+	r.ListByLayoutIDCall.Receives.LayoutID = layoutID
+
+	return r.ListByLayoutIDCall.Returns.Templates, r.ListByLayoutIDCall.Returns.Error
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type TransportAssigner struct {
+	AssignToClientCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ClientID   string
+			Transport  string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewTransportAssigner() *TransportAssigner {
+	return &TransportAssigner{}
+}
+
+func (a *TransportAssigner) AssignToClient(connection collections.ConnectionInterface, clientID, transport string) error {
+	a.AssignToClientCall.Receives.Connection = connection
+	a.AssignToClientCall.Receives.ClientID = clientID
+	a.AssignToClientCall.Receives.Transport = transport
+
+	return a.AssignToClientCall.Returns.Error
+}
@@ -0,0 +1,99 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/models"
+
+type TransportGrantsRepository struct {
+	UpsertCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Grant      models.TransportGrant
+		}
+		Returns struct {
+			Grant models.TransportGrant
+			Error error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection    models.ConnectionInterface
+			TransportName string
+			ClientID      string
+		}
+		Returns struct {
+			Grant models.TransportGrant
+			Error error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection    models.ConnectionInterface
+			TransportName string
+		}
+		Returns struct {
+			Grants []models.TransportGrant
+			Error  error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Grant      models.TransportGrant
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	DeleteByTransportNameCall struct {
+		Receives struct {
+			Connection    models.ConnectionInterface
+			TransportName string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewTransportGrantsRepository() *TransportGrantsRepository {
+	return &TransportGrantsRepository{}
+}
+
+func (r *TransportGrantsRepository) Upsert(conn models.ConnectionInterface, grant models.TransportGrant) (models.TransportGrant, error) {
+	r.UpsertCall.Receives.Connection = conn
+	r.UpsertCall.Receives.Grant = grant
+
+	return r.UpsertCall.Returns.Grant, r.UpsertCall.Returns.Error
+}
+
+func (r *TransportGrantsRepository) Get(conn models.ConnectionInterface, transportName, clientID string) (models.TransportGrant, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.TransportName = transportName
+	r.GetCall.Receives.ClientID = clientID
+
+	return r.GetCall.Returns.Grant, r.GetCall.Returns.Error
+}
+
+func (r *TransportGrantsRepository) List(conn models.ConnectionInterface, transportName string) ([]models.TransportGrant, error) {
+	r.ListCall.Receives.Connection = conn
+	r.ListCall.Receives.TransportName = transportName
+
+	return r.ListCall.Returns.Grants, r.ListCall.Returns.Error
+}
+
+func (r *TransportGrantsRepository) Delete(conn models.ConnectionInterface, grant models.TransportGrant) error {
+	r.DeleteCall.Receives.Connection = conn
+	r.DeleteCall.Receives.Grant = grant
+
+	return r.DeleteCall.Returns.Error
+}
+
+func (r *TransportGrantsRepository) DeleteByTransportName(conn models.ConnectionInterface, transportName string) error {
+	r.DeleteByTransportNameCall.Receives.Connection = conn
+	r.DeleteByTransportNameCall.Receives.TransportName = transportName
+
+	return r.DeleteByTransportNameCall.Returns.Error
+}
@@ -0,0 +1,134 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/collections"
+
+type TransportsCollection struct {
+	SetCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			Transport  collections.Transport
+		}
+		Returns struct {
+			Transport collections.Transport
+			Error     error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			Transport collections.Transport
+			Error     error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+		}
+		Returns struct {
+			TransportList []collections.Transport
+			Error         error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	GrantCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			Name       string
+			ClientID   string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	RevokeCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			Name       string
+			ClientID   string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	ListGrantsCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			ClientIDs []string
+			Error     error
+		}
+	}
+}
+
+func NewTransportsCollection() *TransportsCollection {
+	return &TransportsCollection{}
+}
+
+func (c *TransportsCollection) Set(conn collections.ConnectionInterface, transport collections.Transport) (collections.Transport, error) {
+	c.SetCall.Receives.Connection = conn
+	c.SetCall.Receives.Transport = transport
+
+	return c.SetCall.Returns.Transport, c.SetCall.Returns.Error
+}
+
+func (c *TransportsCollection) Get(conn collections.ConnectionInterface, name string) (collections.Transport, error) {
+	c.GetCall.Receives.Connection = conn
+	c.GetCall.Receives.Name = name
+
+	return c.GetCall.Returns.Transport, c.GetCall.Returns.Error
+}
+
+func (c *TransportsCollection) List(conn collections.ConnectionInterface) ([]collections.Transport, error) {
+	c.ListCall.Receives.Connection = conn
+
+	return c.ListCall.Returns.TransportList, c.ListCall.Returns.Error
+}
+
+func (c *TransportsCollection) Delete(conn collections.ConnectionInterface, name string) error {
+	c.DeleteCall.Receives.Connection = conn
+	c.DeleteCall.Receives.Name = name
+
+	return c.DeleteCall.Returns.Error
+}
+
+func (c *TransportsCollection) Grant(conn collections.ConnectionInterface, name, clientID string) error {
+	c.GrantCall.Receives.Connection = conn
+	c.GrantCall.Receives.Name = name
+	c.GrantCall.Receives.ClientID = clientID
+
+	return c.GrantCall.Returns.Error
+}
+
+func (c *TransportsCollection) Revoke(conn collections.ConnectionInterface, name, clientID string) error {
+	c.RevokeCall.Receives.Connection = conn
+	c.RevokeCall.Receives.Name = name
+	c.RevokeCall.Receives.ClientID = clientID
+
+	return c.RevokeCall.Returns.Error
+}
+
+func (c *TransportsCollection) ListGrants(conn collections.ConnectionInterface, name string) ([]string, error) {
+	c.ListGrantsCall.Receives.Connection = conn
+	c.ListGrantsCall.Receives.Name = name
+
+	return c.ListGrantsCall.Returns.ClientIDs, c.ListGrantsCall.Returns.Error
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type TransportsRepo struct {
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			Transport models.Transport
+			Error     error
+		}
+	}
+}
+
+func NewTransportsRepo() *TransportsRepo {
+	return &TransportsRepo{}
+}
+
+func (r *TransportsRepo) Find(connection models.ConnectionInterface, name string) (models.Transport, error) {
+	r.FindCall.Receives.Connection = connection
+	r.FindCall.Receives.Name = name
+
+	return r.FindCall.Returns.Transport, r.FindCall.Returns.Error
+}
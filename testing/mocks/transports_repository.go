@@ -0,0 +1,116 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/models"
+
+type TransportsRepository struct {
+	UpsertCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Transport  models.Transport
+		}
+		Returns struct {
+			Transport models.Transport
+			Error     error
+		}
+	}
+
+	GetCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			Transport models.Transport
+			Error     error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+		}
+		Returns struct {
+			Transports []models.Transport
+			Error      error
+		}
+	}
+
+	DeleteCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Transport  models.Transport
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	CountReferencesCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			Count int64
+			Error error
+		}
+	}
+
+	CountClientReferencesCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Name       string
+			ClientID   string
+		}
+		Returns struct {
+			Count int64
+			Error error
+		}
+	}
+}
+
+func NewTransportsRepository() *TransportsRepository {
+	return &TransportsRepository{}
+}
+
+func (r *TransportsRepository) Upsert(conn models.ConnectionInterface, transport models.Transport) (models.Transport, error) {
+	r.UpsertCall.Receives.Connection = conn
+	r.UpsertCall.Receives.Transport = transport
+
+	return r.UpsertCall.Returns.Transport, r.UpsertCall.Returns.Error
+}
+
+func (r *TransportsRepository) Get(conn models.ConnectionInterface, name string) (models.Transport, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.Name = name
+
+	return r.GetCall.Returns.Transport, r.GetCall.Returns.Error
+}
+
+func (r *TransportsRepository) List(conn models.ConnectionInterface) ([]models.Transport, error) {
+	r.ListCall.Receives.Connection = conn
+
+	return r.ListCall.Returns.Transports, r.ListCall.Returns.Error
+}
+
+func (r *TransportsRepository) Delete(conn models.ConnectionInterface, transport models.Transport) error {
+	r.DeleteCall.Receives.Connection = conn
+	r.DeleteCall.Receives.Transport = transport
+
+	return r.DeleteCall.Returns.Error
+}
+
+func (r *TransportsRepository) CountReferences(conn models.ConnectionInterface, name string) (int64, error) {
+	r.CountReferencesCall.Receives.Connection = conn
+	r.CountReferencesCall.Receives.Name = name
+
+	return r.CountReferencesCall.Returns.Count, r.CountReferencesCall.Returns.Error
+}
+
+func (r *TransportsRepository) CountClientReferences(conn models.ConnectionInterface, name, clientID string) (int64, error) {
+	r.CountClientReferencesCall.Receives.Connection = conn
+	r.CountClientReferencesCall.Receives.Name = name
+	r.CountClientReferencesCall.Receives.ClientID = clientID
+
+	return r.CountClientReferencesCall.Returns.Count, r.CountClientReferencesCall.Returns.Error
+}
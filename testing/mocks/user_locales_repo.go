@@ -0,0 +1,46 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type UserLocalesRepo struct {
+	GetCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			UserID     string
+		}
+		Returns struct {
+			Locale string
+			Error  error
+		}
+	}
+
+	SetCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			UserID     string
+			Locale     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewUserLocalesRepo() *UserLocalesRepo {
+	return &UserLocalesRepo{}
+}
+
+func (r *UserLocalesRepo) Get(conn models.ConnectionInterface, userID string) (string, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.UserID = userID
+
+	return r.GetCall.Returns.Locale, r.GetCall.Returns.Error
+}
+
+func (r *UserLocalesRepo) Set(conn models.ConnectionInterface, userID, locale string) error {
+	r.SetCall.Receives.Connection = conn
+	r.SetCall.Receives.UserID = userID
+	r.SetCall.Receives.Locale = locale
+
+	return r.SetCall.Returns.Error
+}
@@ -0,0 +1,27 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v2/models"
+
+type UserLocalesRepository struct {
+	GetCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			UserGUID   string
+		}
+		Returns struct {
+			Locale string
+			Error  error
+		}
+	}
+}
+
+func NewUserLocalesRepository() *UserLocalesRepository {
+	return &UserLocalesRepository{}
+}
+
+func (r *UserLocalesRepository) Get(conn models.ConnectionInterface, userGUID string) (string, error) {
+	r.GetCall.Receives.Connection = conn
+	r.GetCall.Receives.UserGUID = userGUID
+
+	return r.GetCall.Returns.Locale, r.GetCall.Returns.Error
+}
@@ -1,8 +1,10 @@
 package uaa
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/pivotal-cf-experimental/warrant"
 	uaaSSOGolang "github.com/pivotal-cf/uaa-sso-golang/uaa"
@@ -47,27 +49,91 @@ func (z ZonedUAAClient) GetClientToken(host string) (string, error) {
 	return uaaClient.Clients.GetToken(z.clientID, z.clientSecret)
 }
 
+// UsersEmailsByIDs fetches the email addresses, names and preferred locale of
+// each user. The IDs are split across as many queries as needed to keep each
+// request URI within the length UAA accepts.
 func (z ZonedUAAClient) UsersEmailsByIDs(token string, ids ...string) ([]User, error) {
 	uaaHost, err := z.tokenHost(token)
 	if err != nil {
 		return nil, err
 	}
 
-	uaaClient := uaaSSOGolang.NewUAA("", uaaHost, z.clientID, z.clientSecret, "")
-	uaaClient.VerifySSL = z.verifySSL
-	uaaClient.SetToken(token)
+	client := uaaSSOGolang.NewClient(uaaHost, z.verifySSL).WithAuthorizationToken(token)
 
 	var myUsers []User
-	users, err := uaaClient.UsersEmailsByIDs(ids...)
-	if err != nil {
-		return myUsers, err
+	for _, query := range usersQueries(ids) {
+		code, body, err := client.MakeRequest("GET", query, nil)
+		if err != nil {
+			return myUsers, err
+		}
+
+		if code > 399 {
+			return myUsers, NewFailure(code, body)
+		}
+
+		var response struct {
+			Resources []struct {
+				ID       string `json:"id"`
+				UserName string `json:"userName"`
+				Locale   string `json:"locale"`
+				Name     struct {
+					GivenName  string `json:"givenName"`
+					FamilyName string `json:"familyName"`
+				} `json:"name"`
+				Emails []struct {
+					Value string `json:"value"`
+				} `json:"emails"`
+			} `json:"resources"`
+		}
+
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return myUsers, err
+		}
+
+		for _, resource := range response.Resources {
+			user := User{
+				ID:         resource.ID,
+				UserName:   resource.UserName,
+				GivenName:  resource.Name.GivenName,
+				FamilyName: resource.Name.FamilyName,
+				Locale:     resource.Locale,
+			}
+
+			for _, email := range resource.Emails {
+				user.Emails = append(user.Emails, email.Value)
+			}
+
+			myUsers = append(myUsers, user)
+		}
 	}
 
-	for _, user := range users {
-		myUsers = append(myUsers, newUserFromSSOGolangUser(user))
+	return myUsers, nil
+}
+
+func usersQueries(ids []string) []string {
+	var filters, queries []string
+
+	query := func(filters []string) string {
+		return fmt.Sprintf("/Users?attributes=id,userName,name,emails,locale&filter=%s", url.QueryEscape(strings.Join(filters, " or ")))
 	}
 
-	return myUsers, nil
+	for _, id := range ids {
+		filter := fmt.Sprintf(`Id eq "%s"`, id)
+
+		if len(filters) > 0 && len(query(append(filters, filter))) > uaaSSOGolang.MaxQueryLength {
+			queries = append(queries, query(filters))
+			filters = nil
+		}
+
+		filters = append(filters, filter)
+	}
+
+	if len(filters) > 0 {
+		queries = append(queries, query(filters))
+	}
+
+	return queries
 }
 
 func (z ZonedUAAClient) tokenHost(token string) (string, error) {
@@ -118,6 +184,9 @@ func (z ZonedUAAClient) UsersGUIDsByScope(token string, scope string) ([]string,
 func newUserFromWarrantUser(warrantUser warrant.User) User {
 	user := User{}
 	user.ID = warrantUser.ID
+	user.UserName = warrantUser.UserName
+	user.GivenName = warrantUser.GivenName
+	user.FamilyName = warrantUser.FamilyName
 	user.Emails = warrantUser.Emails
 
 	return user
@@ -126,14 +195,21 @@ func newUserFromWarrantUser(warrantUser warrant.User) User {
 func newUserFromSSOGolangUser(uaaUser uaaSSOGolang.User) User {
 	user := User{}
 	user.ID = uaaUser.ID
+	user.UserName = uaaUser.Username
+	user.GivenName = uaaUser.Name.GivenName
+	user.FamilyName = uaaUser.Name.FamilyName
 	user.Emails = uaaUser.Emails
 
 	return user
 }
 
 type User struct {
-	ID     string
-	Emails []string
+	ID         string
+	UserName   string
+	GivenName  string
+	FamilyName string
+	Emails     []string
+	Locale     string
 }
 
 type Failure struct {
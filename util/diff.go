@@ -0,0 +1,68 @@
+package util
+
+import "strings"
+
+// DiffLines compares two texts line by line and returns every line of the
+// result prefixed with "-" (removed), "+" (added) or " " (unchanged). It
+// returns an empty string when the texts are equal.
+func DiffLines(from, to string) string {
+	if from == to {
+		return ""
+	}
+
+	a := splitLines(from)
+	b := splitLines(to)
+
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		lines = append(lines, "-"+a[i])
+	}
+
+	for ; j < len(b); j++ {
+		lines = append(lines, "+"+b[j])
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,25 @@
+package util_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/util"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffLines", func() {
+	It("returns an empty string when the texts are equal", func() {
+		Expect(util.DiffLines("same\ntext", "same\ntext")).To(BeEmpty())
+	})
+
+	It("marks removed, added and unchanged lines", func() {
+		diff := util.DiffLines("<p>Hello</p>\n<p>{{.Text}}</p>\n<p>Bye</p>", "<p>Hello</p>\n<p>{{.HTML}}</p>\n<p>Bye</p>\n<p>PS</p>")
+
+		Expect(diff).To(Equal(" <p>Hello</p>\n-<p>{{.Text}}</p>\n+<p>{{.HTML}}</p>\n <p>Bye</p>\n+<p>PS</p>"))
+	})
+
+	It("handles texts that were empty", func() {
+		Expect(util.DiffLines("", "new")).To(Equal("+new"))
+		Expect(util.DiffLines("old", "")).To(Equal("-old"))
+	})
+})
@@ -0,0 +1,120 @@
+package util
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+const tarManifest = "manifest.json"
+
+// WriteJSONTar writes a JSON object as a tar archive. Each member holding an
+// array or object is written to a file named after its key, e.g.
+// templates.json, and the remaining members are collected in manifest.json.
+func WriteJSONTar(w io.Writer, document []byte) error {
+	var members map[string]json.RawMessage
+	err := json.Unmarshal(document, &members)
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	for key := range members {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	manifest := map[string]json.RawMessage{}
+	names := []string{tarManifest}
+	files := map[string][]byte{}
+	for _, key := range keys {
+		value := bytes.TrimSpace(members[key])
+		if len(value) > 0 && (value[0] == '[' || value[0] == '{') {
+			names = append(names, key+".json")
+			files[key+".json"] = value
+		} else {
+			manifest[key] = value
+		}
+	}
+
+	files[tarManifest], err = json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	archive := tar.NewWriter(w)
+	for _, name := range names {
+		err = archive.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(files[name])),
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = archive.Write(files[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// ReadJSONTar reassembles the JSON object written by WriteJSONTar.
+func ReadJSONTar(r io.Reader) ([]byte, error) {
+	members := map[string]json.RawMessage{}
+
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Base(header.Name)
+		if !strings.HasSuffix(name, ".json") {
+			return nil, fmt.Errorf("unexpected file %q in archive", header.Name)
+		}
+
+		contents, err := ioutil.ReadAll(archive)
+		if err != nil {
+			return nil, err
+		}
+
+		if name == tarManifest {
+			var manifest map[string]json.RawMessage
+			err = json.Unmarshal(contents, &manifest)
+			if err != nil {
+				return nil, fmt.Errorf("%s is not a JSON object", header.Name)
+			}
+
+			for key, value := range manifest {
+				members[key] = value
+			}
+			continue
+		}
+
+		if !json.Valid(contents) {
+			return nil, fmt.Errorf("%s is not valid JSON", header.Name)
+		}
+		members[strings.TrimSuffix(name, ".json")] = json.RawMessage(contents)
+	}
+
+	return json.Marshal(members)
+}
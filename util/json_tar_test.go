@@ -0,0 +1,70 @@
+package util_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+
+	"github.com/cloudfoundry-incubator/notifications/util"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON tar archives", func() {
+	It("writes each collection to its own file and the rest to a manifest", func() {
+		buffer := bytes.NewBuffer([]byte{})
+		err := util.WriteJSONTar(buffer, []byte(`{"version": 1, "templates": [{"id": "some-id"}], "api": "v2", "senders": []}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		files := map[string]string{}
+		var names []string
+		archive := tar.NewReader(buffer)
+		for {
+			header, err := archive.Next()
+			if err != nil {
+				break
+			}
+
+			contents, err := ioutil.ReadAll(archive)
+			Expect(err).NotTo(HaveOccurred())
+
+			names = append(names, header.Name)
+			files[header.Name] = string(contents)
+		}
+
+		Expect(names).To(Equal([]string{"manifest.json", "senders.json", "templates.json"}))
+		Expect(files["manifest.json"]).To(MatchJSON(`{"version": 1, "api": "v2"}`))
+		Expect(files["senders.json"]).To(MatchJSON(`[]`))
+		Expect(files["templates.json"]).To(MatchJSON(`[{"id": "some-id"}]`))
+	})
+
+	It("reads back the document it wrote", func() {
+		document := `{"version": 1, "api": "v2", "templates": [{"id": "some-id", "name": "some-name"}], "partials": []}`
+
+		buffer := bytes.NewBuffer([]byte{})
+		err := util.WriteJSONTar(buffer, []byte(document))
+		Expect(err).NotTo(HaveOccurred())
+
+		read, err := util.ReadJSONTar(buffer)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read).To(MatchJSON(document))
+	})
+
+	It("rejects files that are not JSON", func() {
+		buffer := bytes.NewBuffer([]byte{})
+		archive := tar.NewWriter(buffer)
+		Expect(archive.WriteHeader(&tar.Header{Name: "templates.json", Mode: 0644, Size: 3, Typeflag: tar.TypeReg})).To(Succeed())
+		_, err := archive.Write([]byte("not"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(archive.Close()).To(Succeed())
+
+		_, err = util.ReadJSONTar(buffer)
+		Expect(err).To(MatchError("templates.json is not valid JSON"))
+	})
+
+	It("returns an error when the document is not a JSON object", func() {
+		err := util.WriteJSONTar(bytes.NewBuffer([]byte{}), []byte(`[]`))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,284 @@
+package collections
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictRename    = "rename"
+
+	ImportCreated  = "created"
+	ImportUpdated  = "updated"
+	ImportRenamed  = "renamed"
+	ImportSkipped  = "skipped"
+	ImportAssigned = "assigned"
+	ImportMissing  = "missing"
+)
+
+type TemplateImportError struct {
+	Err error
+}
+
+func (e TemplateImportError) Error() string {
+	return e.Err.Error()
+}
+
+// Bundle holds the templates and their client and notification assignments,
+// so that they can be copied from one deployment to another.
+type Bundle struct {
+	Templates   []Template
+	Assignments []TemplateAssignment
+}
+
+// TemplateAssignment assigns a template to a client, or to one of the
+// client's notifications when NotificationID is set.
+type TemplateAssignment struct {
+	ClientID       string
+	NotificationID string
+	TemplateID     string
+}
+
+// ImportOptions control how a bundle is imported. Conflict decides what
+// happens to templates that already exist, matched through IDMap or by name.
+// IDMap maps IDs in the bundle to the IDs of existing templates.
+type ImportOptions struct {
+	Conflict string
+	IDMap    map[string]string
+}
+
+type ImportedRecord struct {
+	Type     string
+	Name     string
+	SourceID string
+	ID       string
+	Action   string
+}
+
+func (c TemplatesCollection) Export(conn ConnectionInterface) (Bundle, error) {
+	bundle := Bundle{
+		Templates:   []Template{},
+		Assignments: []TemplateAssignment{},
+	}
+
+	templates, err := c.templatesRepo.ListIDsAndNames(conn)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	for _, template := range templates {
+		if template.ID == models.DefaultTemplateID {
+			continue
+		}
+
+		model, err := c.templatesRepo.FindByID(conn, template.ID)
+		if err != nil {
+			return Bundle{}, err
+		}
+
+		bundle.Templates = append(bundle.Templates, newTemplate(model))
+	}
+
+	clients, err := c.clientsRepo.FindAll(conn)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	for _, client := range clients {
+		if isAssigned(client.TemplateID) {
+			bundle.Assignments = append(bundle.Assignments, TemplateAssignment{
+				ClientID:   client.ID,
+				TemplateID: client.TemplateID,
+			})
+		}
+	}
+
+	kinds, err := c.kindsRepo.FindAll(conn)
+	if err != nil {
+		return Bundle{}, err
+	}
+
+	for _, kind := range kinds {
+		if isAssigned(kind.TemplateID) {
+			bundle.Assignments = append(bundle.Assignments, TemplateAssignment{
+				ClientID:       kind.ClientID,
+				NotificationID: kind.ID,
+				TemplateID:     kind.TemplateID,
+			})
+		}
+	}
+
+	return bundle, nil
+}
+
+// Import copies the templates of a bundle and then applies its assignments,
+// rewriting references to templates in the bundle to the IDs the templates
+// receive. Assignments to clients or notifications that are not registered
+// in this deployment are reported as missing. Callers wanting an all or
+// nothing import should pass a transaction.
+func (c TemplatesCollection) Import(conn ConnectionInterface, bundle Bundle, options ImportOptions) ([]ImportedRecord, error) {
+	switch options.Conflict {
+	case "":
+		options.Conflict = ConflictSkip
+	case ConflictSkip, ConflictOverwrite, ConflictRename:
+	default:
+		return nil, TemplateImportError{fmt.Errorf("conflict must be one of %s, %s or %s", ConflictSkip, ConflictOverwrite, ConflictRename)}
+	}
+
+	existing, err := c.templatesRepo.ListIDsAndNames(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]bool{}
+	names := map[string]string{}
+	for _, template := range existing {
+		ids[template.ID] = true
+		names[template.Name] = template.ID
+	}
+
+	records := []ImportedRecord{}
+	imported := map[string]string{}
+
+	targetID := func(sourceID string) string {
+		if id, ok := imported[sourceID]; ok {
+			return id
+		}
+
+		if id, ok := options.IDMap[sourceID]; ok {
+			return id
+		}
+
+		return sourceID
+	}
+
+	for _, template := range layoutsFirst(bundle.Templates) {
+		sourceID := template.ID
+		if template.LayoutID != "" {
+			template.LayoutID = targetID(template.LayoutID)
+		}
+
+		id, found := options.IDMap[sourceID], false
+		if id != "" && ids[id] {
+			found = true
+		} else {
+			id, found = names[template.Name]
+		}
+
+		var saved Template
+		action := ImportCreated
+		if found {
+			switch options.Conflict {
+			case ConflictSkip:
+				imported[sourceID] = id
+				records = append(records, ImportedRecord{Type: "template", Name: template.Name, SourceID: sourceID, ID: id, Action: ImportSkipped})
+				continue
+			case ConflictOverwrite:
+				saved, err = c.update(conn, id, template)
+				action = ImportUpdated
+			case ConflictRename:
+				template.Name = uniqueName(names, template.Name)
+				saved, err = c.Create(conn, template)
+				action = ImportRenamed
+			}
+		} else {
+			saved, err = c.Create(conn, template)
+		}
+		if err != nil {
+			return nil, importError(fmt.Sprintf("template %q", template.Name), err)
+		}
+
+		ids[saved.ID] = true
+		names[saved.Name] = saved.ID
+		imported[sourceID] = saved.ID
+		records = append(records, ImportedRecord{Type: "template", Name: saved.Name, SourceID: sourceID, ID: saved.ID, Action: action})
+	}
+
+	for _, assignment := range bundle.Assignments {
+		templateID := targetID(assignment.TemplateID)
+
+		name := assignment.ClientID
+		if assignment.NotificationID == "" {
+			err = c.AssignToClient(conn, assignment.ClientID, templateID)
+		} else {
+			name = assignment.ClientID + "/" + assignment.NotificationID
+			err = c.AssignToNotification(conn, assignment.ClientID, assignment.NotificationID, templateID)
+		}
+
+		action := ImportAssigned
+		if err != nil {
+			if _, ok := err.(models.NotFoundError); !ok {
+				return nil, importError(fmt.Sprintf("assignment %q", name), err)
+			}
+			action = ImportMissing
+		}
+
+		records = append(records, ImportedRecord{Type: "assignment", Name: name, SourceID: assignment.TemplateID, ID: templateID, Action: action})
+	}
+
+	return records, nil
+}
+
+func isAssigned(templateID string) bool {
+	return templateID != "" && templateID != models.DefaultTemplateID && templateID != models.DoNotSetTemplateID
+}
+
+func uniqueName(names map[string]string, name string) string {
+	candidate := fmt.Sprintf("%s (imported)", name)
+	for n := 2; ; n++ {
+		if _, taken := names[candidate]; !taken {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (imported %d)", name, n)
+	}
+}
+
+// layoutsFirst orders templates so that every layout in the bundle is
+// imported before the templates it wraps.
+func layoutsFirst(templates []Template) []Template {
+	positions := map[string]int{}
+	for position, template := range templates {
+		if template.ID != "" {
+			positions[template.ID] = position
+		}
+	}
+
+	var ordered []Template
+	visited := make([]bool, len(templates))
+
+	var visit func(position int)
+	visit = func(position int) {
+		if visited[position] {
+			return
+		}
+		visited[position] = true
+
+		if layout, ok := positions[templates[position].LayoutID]; ok && templates[position].LayoutID != "" {
+			visit(layout)
+		}
+
+		ordered = append(ordered, templates[position])
+	}
+
+	for position := range templates {
+		visit(position)
+	}
+
+	return ordered
+}
+
+func importError(record string, err error) error {
+	switch e := err.(type) {
+	case models.TemplateLayoutError:
+		return TemplateImportError{fmt.Errorf("%s: %s", record, e.Err)}
+	case TemplateAssignmentError:
+		return TemplateImportError{fmt.Errorf("%s: %s", record, e.Err)}
+	case models.DuplicateError:
+		return TemplateImportError{fmt.Errorf("%s: %s", record, e.Err)}
+	default:
+		return err
+	}
+}
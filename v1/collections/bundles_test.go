@@ -0,0 +1,236 @@
+package collections_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template bundles", func() {
+	var (
+		kindsRepo            *mocks.KindsRepo
+		clientsRepo          *mocks.ClientsRepository
+		templatesRepo        *mocks.TemplatesRepo
+		templateVersionsRepo *mocks.TemplateVersionsRepo
+		conn                 *mocks.Connection
+
+		collection collections.TemplatesCollection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+
+		clientsRepo = mocks.NewClientsRepository()
+		kindsRepo = mocks.NewKindsRepo()
+		templatesRepo = mocks.NewTemplatesRepo()
+		templateVersionsRepo = mocks.NewTemplateVersionsRepo()
+
+		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, templateVersionsRepo)
+	})
+
+	Describe("Export", func() {
+		BeforeEach(func() {
+			templatesRepo.ListIDsAndNamesCall.Returns.Templates = []models.Template{
+				{ID: models.DefaultTemplateID, Name: "Default Template"},
+				{ID: "some-template-id", Name: "some-template"},
+			}
+			templatesRepo.FindByIDCall.Returns.Template = models.Template{
+				ID:       "some-template-id",
+				Name:     "some-template",
+				HTML:     "<p>hi</p>",
+				Subject:  "{{.Subject}}",
+				Metadata: "{}",
+				Version:  2,
+			}
+			clientsRepo.FindAllCall.Returns.Clients = []models.Client{
+				{ID: "assigned-client", TemplateID: "some-template-id"},
+				{ID: "default-client", TemplateID: models.DefaultTemplateID},
+				{ID: "unassigned-client"},
+			}
+			kindsRepo.FindAllCall.Returns.Kinds = []models.Kind{
+				{ID: "assigned-kind", ClientID: "unassigned-client", TemplateID: "some-template-id"},
+				{ID: "unassigned-kind", ClientID: "unassigned-client"},
+			}
+		})
+
+		It("exports every template except the default, along with its assignments", func() {
+			bundle, err := collection.Export(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bundle).To(Equal(collections.Bundle{
+				Templates: []collections.Template{{
+					ID:       "some-template-id",
+					Name:     "some-template",
+					HTML:     "<p>hi</p>",
+					Subject:  "{{.Subject}}",
+					Metadata: "{}",
+					Version:  2,
+				}},
+				Assignments: []collections.TemplateAssignment{
+					{ClientID: "assigned-client", TemplateID: "some-template-id"},
+					{ClientID: "unassigned-client", NotificationID: "assigned-kind", TemplateID: "some-template-id"},
+				},
+			}))
+
+			Expect(templatesRepo.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+		})
+
+		It("returns an error when the templates cannot be listed", func() {
+			templatesRepo.ListIDsAndNamesCall.Returns.Error = errors.New("database is gone")
+
+			_, err := collection.Export(conn)
+			Expect(err).To(MatchError(errors.New("database is gone")))
+		})
+	})
+
+	Describe("Import", func() {
+		var bundle collections.Bundle
+
+		BeforeEach(func() {
+			bundle = collections.Bundle{
+				Templates: []collections.Template{{
+					ID:       "source-template-id",
+					Name:     "welcome",
+					HTML:     "<p>welcome</p>",
+					Subject:  "{{.Subject}}",
+					Metadata: "{}",
+				}},
+				Assignments: []collections.TemplateAssignment{
+					{ClientID: "some-client", TemplateID: "source-template-id"},
+				},
+			}
+
+			templatesRepo.ListIDsAndNamesCall.Returns.Templates = []models.Template{
+				{ID: models.DefaultTemplateID, Name: "Default Template"},
+			}
+			templatesRepo.CreateCall.Returns.Template = models.Template{
+				ID:       "new-template-id",
+				Name:     "welcome",
+				HTML:     "<p>welcome</p>",
+				Subject:  "{{.Subject}}",
+				Metadata: "{}",
+			}
+			templatesRepo.UpdateCall.Returns.Template = models.Template{
+				ID:   "existing-template-id",
+				Name: "welcome",
+			}
+			clientsRepo.FindCall.Returns.Client = models.Client{ID: "some-client"}
+		})
+
+		It("creates the templates and assigns them to the bundle's clients", func() {
+			records, err := collection.Import(conn, bundle, collections.ImportOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal([]collections.ImportedRecord{
+				{Type: "template", Name: "welcome", SourceID: "source-template-id", ID: "new-template-id", Action: collections.ImportCreated},
+				{Type: "assignment", Name: "some-client", SourceID: "source-template-id", ID: "new-template-id", Action: collections.ImportAssigned},
+			}))
+
+			Expect(templatesRepo.CreateCall.Receives.Template.Name).To(Equal("welcome"))
+			Expect(clientsRepo.UpdateCall.Receives.Client).To(Equal(models.Client{
+				ID:         "some-client",
+				TemplateID: "new-template-id",
+			}))
+		})
+
+		Context("when a template with the same name exists", func() {
+			BeforeEach(func() {
+				templatesRepo.ListIDsAndNamesCall.Returns.Templates = []models.Template{
+					{ID: "existing-template-id", Name: "welcome"},
+				}
+			})
+
+			It("skips it and assigns the existing template", func() {
+				records, err := collection.Import(conn, bundle, collections.ImportOptions{Conflict: collections.ConflictSkip})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(records[0]).To(Equal(collections.ImportedRecord{
+					Type:     "template",
+					Name:     "welcome",
+					SourceID: "source-template-id",
+					ID:       "existing-template-id",
+					Action:   collections.ImportSkipped,
+				}))
+
+				Expect(templatesRepo.CreateCall.Receives.Template).To(Equal(models.Template{}))
+				Expect(clientsRepo.UpdateCall.Receives.Client.TemplateID).To(Equal("existing-template-id"))
+			})
+
+			It("overwrites it as a new version", func() {
+				records, err := collection.Import(conn, bundle, collections.ImportOptions{Conflict: collections.ConflictOverwrite})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(records[0].Action).To(Equal(collections.ImportUpdated))
+				Expect(records[0].ID).To(Equal("existing-template-id"))
+
+				Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("existing-template-id"))
+				Expect(templatesRepo.UpdateCall.Receives.Template.HTML).To(Equal("<p>welcome</p>"))
+				Expect(templateVersionsRepo.CreateCall.Receives.TemplateVersion.TemplateID).To(Equal("existing-template-id"))
+			})
+
+			It("creates a renamed copy", func() {
+				templatesRepo.CreateCall.Returns.Template.Name = "welcome (imported)"
+
+				records, err := collection.Import(conn, bundle, collections.ImportOptions{Conflict: collections.ConflictRename})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(records[0].Action).To(Equal(collections.ImportRenamed))
+				Expect(records[0].Name).To(Equal("welcome (imported)"))
+
+				Expect(templatesRepo.CreateCall.Receives.Template.Name).To(Equal("welcome (imported)"))
+			})
+		})
+
+		It("matches existing templates through the ID map", func() {
+			templatesRepo.ListIDsAndNamesCall.Returns.Templates = []models.Template{
+				{ID: "mapped-template-id", Name: "greeting"},
+			}
+
+			records, err := collection.Import(conn, bundle, collections.ImportOptions{
+				IDMap: map[string]string{"source-template-id": "mapped-template-id"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records[0].Action).To(Equal(collections.ImportSkipped))
+			Expect(records[0].ID).To(Equal("mapped-template-id"))
+		})
+
+		It("assigns templates to notifications", func() {
+			bundle.Assignments = []collections.TemplateAssignment{
+				{ClientID: "some-client", NotificationID: "some-kind", TemplateID: "source-template-id"},
+			}
+			kindsRepo.FindCall.Returns.Kinds = []models.Kind{{ID: "some-kind", ClientID: "some-client"}}
+
+			records, err := collection.Import(conn, bundle, collections.ImportOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records[1]).To(Equal(collections.ImportedRecord{
+				Type:     "assignment",
+				Name:     "some-client/some-kind",
+				SourceID: "source-template-id",
+				ID:       "new-template-id",
+				Action:   collections.ImportAssigned,
+			}))
+
+			Expect(kindsRepo.UpdateCall.Receives.Kind.TemplateID).To(Equal("new-template-id"))
+		})
+
+		It("reports assignments to clients that are not registered", func() {
+			clientsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			records, err := collection.Import(conn, bundle, collections.ImportOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records[1].Action).To(Equal(collections.ImportMissing))
+		})
+
+		It("rejects an unknown conflict strategy", func() {
+			_, err := collection.Import(conn, bundle, collections.ImportOptions{Conflict: "merge"})
+			Expect(err).To(MatchError(collections.TemplateImportError{Err: errors.New("conflict must be one of skip, overwrite or rename")}))
+		})
+
+		It("names the template when its layout is unusable", func() {
+			templatesRepo.FindLayoutsCall.Returns.Error = models.TemplateLayoutError{Err: errors.New(`Layout "missing" could not be found`)}
+
+			_, err := collection.Import(conn, bundle, collections.ImportOptions{})
+			Expect(err).To(MatchError(collections.TemplateImportError{Err: errors.New(`template "welcome": Layout "missing" could not be found`)}))
+		})
+	})
+})
@@ -2,7 +2,12 @@ package collections
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/util"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
 
@@ -22,49 +27,112 @@ func (e TemplateAssignmentError) Error() string {
 	return e.Err.Error()
 }
 
+type TemplateInUseError struct {
+	Err error
+}
+
+func (e TemplateInUseError) Error() string {
+	return e.Err.Error()
+}
+
 type clientsRepository interface {
 	Find(connection models.ConnectionInterface, clientID string) (models.Client, error)
+	FindAll(connection models.ConnectionInterface) ([]models.Client, error)
 	FindAllByTemplateID(connection models.ConnectionInterface, templateID string) ([]models.Client, error)
 	Update(connection models.ConnectionInterface, client models.Client) (models.Client, error)
 }
 
 type kindsRepository interface {
 	Find(connection models.ConnectionInterface, kindID string, clientID string) (models.Kind, error)
+	FindAll(connection models.ConnectionInterface) ([]models.Kind, error)
 	FindAllByTemplateID(connection models.ConnectionInterface, templateID string) ([]models.Kind, error)
 	Update(connection models.ConnectionInterface, kind models.Kind) (models.Kind, error)
 }
 
 type templatesRepository interface {
 	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
+	FindLayouts(connection models.ConnectionInterface, template models.Template) ([]models.Template, error)
+	FindAllByLayoutID(connection models.ConnectionInterface, layoutID string) ([]models.Template, error)
+	ListIDsAndNames(connection models.ConnectionInterface) ([]models.Template, error)
 	Create(connection models.ConnectionInterface, template models.Template) (models.Template, error)
+	Update(connection models.ConnectionInterface, templateID string, template models.Template) (models.Template, error)
 	Destroy(connection models.ConnectionInterface, templateID string) error
 }
 
+type templateVersionsRepository interface {
+	Create(connection models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error)
+	Find(connection models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error)
+	FindAllByTemplateID(connection models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error)
+	DestroyAllByTemplateID(connection models.ConnectionInterface, templateID string) error
+}
+
 type TemplateAssociation struct {
 	ClientID       string
 	NotificationID string
 }
 
 type Template struct {
-	ID       string
-	Name     string
-	Text     string
-	HTML     string
-	Subject  string
-	Metadata string
+	ID            string
+	Name          string
+	Text          string
+	HTML          string
+	Subject       string
+	Metadata      string
+	Localizations string
+	LayoutID      string
+	Version       int
+
+	DisableAutoText    bool
+	DisableCSSInlining bool
+}
+
+type TemplateVersion struct {
+	TemplateID    string
+	Version       int
+	Name          string
+	Text          string
+	HTML          string
+	Subject       string
+	Metadata      string
+	Localizations string
+	LayoutID      string
+	CreatedAt     time.Time
+
+	DisableAutoText    bool
+	DisableCSSInlining bool
+}
+
+// TemplateDiff holds a line diff for each field that differs between two
+// versions of a template. Unchanged fields are left empty.
+type TemplateDiff struct {
+	TemplateID    string
+	From          int
+	To            int
+	Name          string
+	Text          string
+	HTML          string
+	Subject       string
+	Metadata      string
+	Localizations string
+	LayoutID      string
+
+	DisableAutoText    string
+	DisableCSSInlining string
 }
 
 type TemplatesCollection struct {
-	clientsRepo   clientsRepository
-	kindsRepo     kindsRepository
-	templatesRepo templatesRepository
+	clientsRepo          clientsRepository
+	kindsRepo            kindsRepository
+	templatesRepo        templatesRepository
+	templateVersionsRepo templateVersionsRepository
 }
 
-func NewTemplatesCollection(clientsRepo clientsRepository, kindsRepo kindsRepository, templatesRepo templatesRepository) TemplatesCollection {
+func NewTemplatesCollection(clientsRepo clientsRepository, kindsRepo kindsRepository, templatesRepo templatesRepository, templateVersionsRepo templateVersionsRepository) TemplatesCollection {
 	return TemplatesCollection{
-		clientsRepo:   clientsRepo,
-		kindsRepo:     kindsRepo,
-		templatesRepo: templatesRepo,
+		clientsRepo:          clientsRepo,
+		kindsRepo:            kindsRepo,
+		templatesRepo:        templatesRepo,
+		templateVersionsRepo: templateVersionsRepo,
 	}
 }
 
@@ -173,28 +241,263 @@ func (c TemplatesCollection) ListAssociations(conn ConnectionInterface, template
 	return associations, nil
 }
 
+// UsedOnlyByCritical reports whether the template is used by at least one
+// kind and every kind using it is critical. A client default template also
+// covers kinds without a template of their own, and a layout wraps whatever
+// its templates are sent for, so neither qualifies.
+func (c TemplatesCollection) UsedOnlyByCritical(conn ConnectionInterface, templateID string) (bool, error) {
+	clients, err := c.clientsRepo.FindAllByTemplateID(conn, templateID)
+	if err != nil {
+		return false, err
+	}
+
+	if len(clients) > 0 {
+		return false, nil
+	}
+
+	wrapped, err := c.templatesRepo.FindAllByLayoutID(conn, templateID)
+	if err != nil {
+		return false, err
+	}
+
+	if len(wrapped) > 0 {
+		return false, nil
+	}
+
+	kinds, err := c.kindsRepo.FindAllByTemplateID(conn, templateID)
+	if err != nil {
+		return false, err
+	}
+
+	if len(kinds) == 0 {
+		return false, nil
+	}
+
+	for _, kind := range kinds {
+		if !kind.Critical {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func (c TemplatesCollection) Create(connection ConnectionInterface, template Template) (Template, error) {
-	tmpl, err := c.templatesRepo.Create(connection, models.Template{
-		Name:     template.Name,
-		Text:     template.Text,
-		HTML:     template.HTML,
-		Subject:  template.Subject,
-		Metadata: template.Metadata,
+	model := models.Template{
+		Name:          template.Name,
+		Text:          template.Text,
+		HTML:          template.HTML,
+		Subject:       template.Subject,
+		Metadata:      template.Metadata,
+		Localizations: template.Localizations,
+		LayoutID:      template.LayoutID,
+
+		DisableAutoText:    template.DisableAutoText,
+		DisableCSSInlining: template.DisableCSSInlining,
+	}
+
+	err := c.checkLayout(connection, model)
+	if err != nil {
+		return Template{}, err
+	}
+
+	tmpl, err := c.templatesRepo.Create(connection, model)
+	if err != nil {
+		return Template{}, err
+	}
+
+	_, err = c.templateVersionsRepo.Create(connection, models.NewTemplateVersion(tmpl))
+	if err != nil {
+		return Template{}, err
+	}
+
+	return newTemplate(tmpl), nil
+}
+
+func (c TemplatesCollection) Delete(connection ConnectionInterface, templateID string) error {
+	wrapped, err := c.templatesRepo.FindAllByLayoutID(connection, templateID)
+	if err != nil {
+		return err
+	}
+
+	if len(wrapped) > 0 {
+		var names []string
+		for _, template := range wrapped {
+			names = append(names, template.Name)
+		}
+
+		return TemplateInUseError{fmt.Errorf("Template %q is used as a layout by %s and cannot be deleted", templateID, strings.Join(names, ", "))}
+	}
+
+	err = c.templatesRepo.Destroy(connection, templateID)
+	if err != nil {
+		return err
+	}
+
+	return c.templateVersionsRepo.DestroyAllByTemplateID(connection, templateID)
+}
+
+// ListVersions returns the stored versions of a template, newest first.
+func (c TemplatesCollection) ListVersions(connection ConnectionInterface, templateID string) ([]TemplateVersion, error) {
+	versions := []TemplateVersion{}
+
+	_, err := c.templatesRepo.FindByID(connection, templateID)
+	if err != nil {
+		return versions, err
+	}
+
+	versionModels, err := c.templateVersionsRepo.FindAllByTemplateID(connection, templateID)
+	if err != nil {
+		return versions, err
+	}
+
+	for _, model := range versionModels {
+		versions = append(versions, newTemplateVersion(model))
+	}
+
+	return versions, nil
+}
+
+func (c TemplatesCollection) GetVersion(connection ConnectionInterface, templateID string, version int) (TemplateVersion, error) {
+	model, err := c.templateVersionsRepo.Find(connection, templateID, version)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	return newTemplateVersion(model), nil
+}
+
+func (c TemplatesCollection) DiffVersions(connection ConnectionInterface, templateID string, from, to int) (TemplateDiff, error) {
+	fromVersion, err := c.GetVersion(connection, templateID, from)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+
+	toVersion, err := c.GetVersion(connection, templateID, to)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+
+	return TemplateDiff{
+		TemplateID:    templateID,
+		From:          from,
+		To:            to,
+		Name:          util.DiffLines(fromVersion.Name, toVersion.Name),
+		Text:          util.DiffLines(fromVersion.Text, toVersion.Text),
+		HTML:          util.DiffLines(fromVersion.HTML, toVersion.HTML),
+		Subject:       util.DiffLines(fromVersion.Subject, toVersion.Subject),
+		Metadata:      util.DiffLines(fromVersion.Metadata, toVersion.Metadata),
+		Localizations: util.DiffLines(fromVersion.Localizations, toVersion.Localizations),
+		LayoutID:      util.DiffLines(fromVersion.LayoutID, toVersion.LayoutID),
+
+		DisableAutoText:    util.DiffLines(strconv.FormatBool(fromVersion.DisableAutoText), strconv.FormatBool(toVersion.DisableAutoText)),
+		DisableCSSInlining: util.DiffLines(strconv.FormatBool(fromVersion.DisableCSSInlining), strconv.FormatBool(toVersion.DisableCSSInlining)),
+	}, nil
+}
+
+// Rollback restores the content of an earlier version. The restored content
+// is stored as a new version so that history is never rewritten.
+func (c TemplatesCollection) Rollback(connection ConnectionInterface, templateID string, version int) (Template, error) {
+	previous, err := c.templateVersionsRepo.Find(connection, templateID, version)
+	if err != nil {
+		return Template{}, err
+	}
+
+	return c.update(connection, templateID, Template{
+		Name:          previous.Name,
+		Text:          previous.Text,
+		HTML:          previous.HTML,
+		Subject:       previous.Subject,
+		Metadata:      previous.Metadata,
+		Localizations: previous.Localizations,
+		LayoutID:      previous.LayoutID,
+
+		DisableAutoText:    previous.DisableAutoText,
+		DisableCSSInlining: previous.DisableCSSInlining,
+	})
+}
+
+func (c TemplatesCollection) update(connection ConnectionInterface, templateID string, template Template) (Template, error) {
+	model := models.Template{
+		Name:          template.Name,
+		Text:          template.Text,
+		HTML:          template.HTML,
+		Subject:       template.Subject,
+		Metadata:      template.Metadata,
+		Localizations: template.Localizations,
+		LayoutID:      template.LayoutID,
+
+		DisableAutoText:    template.DisableAutoText,
+		DisableCSSInlining: template.DisableCSSInlining,
+	}
+
+	err := c.checkLayout(connection, models.Template{
+		ID:       templateID,
+		LayoutID: model.LayoutID,
 	})
 	if err != nil {
 		return Template{}, err
 	}
 
+	tmpl, err := c.templatesRepo.Update(connection, templateID, model)
+	if err != nil {
+		return Template{}, err
+	}
+
+	_, err = c.templateVersionsRepo.Create(connection, models.NewTemplateVersion(tmpl))
+	if err != nil {
+		return Template{}, err
+	}
+
+	return newTemplate(tmpl), nil
+}
+
+func (c TemplatesCollection) checkLayout(connection ConnectionInterface, template models.Template) error {
+	layouts, err := c.templatesRepo.FindLayouts(connection, template)
+	if err != nil {
+		return err
+	}
+
+	for _, layout := range layouts {
+		if !common.IncludesLayoutContent(layout.Text) && !common.IncludesLayoutContent(layout.HTML) {
+			return models.TemplateLayoutError{Err: fmt.Errorf("Layout %q does not include {{template \"content\" .}}", layout.ID)}
+		}
+	}
+
+	return nil
+}
+
+func newTemplate(model models.Template) Template {
 	return Template{
-		ID:       tmpl.ID,
-		Name:     tmpl.Name,
-		Text:     tmpl.Text,
-		HTML:     tmpl.HTML,
-		Subject:  tmpl.Subject,
-		Metadata: tmpl.Metadata,
-	}, nil
+		ID:            model.ID,
+		Name:          model.Name,
+		Text:          model.Text,
+		HTML:          model.HTML,
+		Subject:       model.Subject,
+		Metadata:      model.Metadata,
+		Localizations: model.Localizations,
+		LayoutID:      model.LayoutID,
+		Version:       model.Version,
+
+		DisableAutoText:    model.DisableAutoText,
+		DisableCSSInlining: model.DisableCSSInlining,
+	}
 }
 
-func (c TemplatesCollection) Delete(connection ConnectionInterface, templateID string) error {
-	return c.templatesRepo.Destroy(connection, templateID)
+func newTemplateVersion(model models.TemplateVersion) TemplateVersion {
+	return TemplateVersion{
+		TemplateID:    model.TemplateID,
+		Version:       model.Version,
+		Name:          model.Name,
+		Text:          model.Text,
+		HTML:          model.HTML,
+		Subject:       model.Subject,
+		Metadata:      model.Metadata,
+		Localizations: model.Localizations,
+		LayoutID:      model.LayoutID,
+		CreatedAt:     model.CreatedAt,
+
+		DisableAutoText:    model.DisableAutoText,
+		DisableCSSInlining: model.DisableCSSInlining,
+	}
 }
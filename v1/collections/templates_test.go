@@ -2,6 +2,7 @@ package collections_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
@@ -13,10 +14,11 @@ import (
 
 var _ = Describe("TemplatesCollection", func() {
 	var (
-		kindsRepo     *mocks.KindsRepo
-		clientsRepo   *mocks.ClientsRepository
-		templatesRepo *mocks.TemplatesRepo
-		conn          *mocks.Connection
+		kindsRepo            *mocks.KindsRepo
+		clientsRepo          *mocks.ClientsRepository
+		templatesRepo        *mocks.TemplatesRepo
+		templateVersionsRepo *mocks.TemplateVersionsRepo
+		conn                 *mocks.Connection
 
 		collection collections.TemplatesCollection
 	)
@@ -27,8 +29,9 @@ var _ = Describe("TemplatesCollection", func() {
 		clientsRepo = mocks.NewClientsRepository()
 		kindsRepo = mocks.NewKindsRepo()
 		templatesRepo = mocks.NewTemplatesRepo()
+		templateVersionsRepo = mocks.NewTemplateVersionsRepo()
 
-		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo)
+		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, templateVersionsRepo)
 	})
 
 	Describe("AssignToClient", func() {
@@ -325,6 +328,99 @@ var _ = Describe("TemplatesCollection", func() {
 		})
 	})
 
+	Describe("UsedOnlyByCritical", func() {
+		BeforeEach(func() {
+			kindsRepo.FindAllByTemplateIDCall.Returns.Kinds = []models.Kind{
+				{
+					ID:         "some-notification",
+					ClientID:   "some-client",
+					TemplateID: "some-template-id",
+					Critical:   true,
+				},
+				{
+					ID:         "another-notification",
+					ClientID:   "another-client",
+					TemplateID: "some-template-id",
+					Critical:   true,
+				},
+			}
+		})
+
+		It("returns true when every kind using the template is critical", func() {
+			critical, err := collection.UsedOnlyByCritical(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(critical).To(BeTrue())
+
+			Expect(clientsRepo.FindAllByTemplateIDCall.Receives.Connection).To(Equal(conn))
+			Expect(clientsRepo.FindAllByTemplateIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(kindsRepo.FindAllByTemplateIDCall.Receives.Connection).To(Equal(conn))
+			Expect(kindsRepo.FindAllByTemplateIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+		})
+
+		It("returns false when any kind using the template is not critical", func() {
+			kindsRepo.FindAllByTemplateIDCall.Returns.Kinds[1].Critical = false
+
+			critical, err := collection.UsedOnlyByCritical(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(critical).To(BeFalse())
+		})
+
+		It("returns false when no kinds use the template", func() {
+			kindsRepo.FindAllByTemplateIDCall.Returns.Kinds = []models.Kind{}
+
+			critical, err := collection.UsedOnlyByCritical(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(critical).To(BeFalse())
+		})
+
+		It("returns false when a client uses the template as its default", func() {
+			clientsRepo.FindAllByTemplateIDCall.Returns.Clients = []models.Client{
+				{
+					ID:         "some-client",
+					TemplateID: "some-template-id",
+				},
+			}
+
+			critical, err := collection.UsedOnlyByCritical(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(critical).To(BeFalse())
+		})
+
+		It("returns false when another template uses it as a layout", func() {
+			templatesRepo.FindAllByLayoutIDCall.Returns.Templates = []models.Template{
+				{ID: "wrapped-template", LayoutID: "some-template-id"},
+			}
+
+			critical, err := collection.UsedOnlyByCritical(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(critical).To(BeFalse())
+			Expect(templatesRepo.FindAllByLayoutIDCall.Receives.LayoutID).To(Equal("some-template-id"))
+		})
+
+		Context("when errors occur", func() {
+			It("returns the layout lookup error", func() {
+				templatesRepo.FindAllByLayoutIDCall.Returns.Error = errors.New("lookup failed")
+
+				_, err := collection.UsedOnlyByCritical(conn, "some-template-id")
+				Expect(err).To(MatchError(errors.New("lookup failed")))
+			})
+
+			It("returns the clients repo error", func() {
+				clientsRepo.FindAllByTemplateIDCall.Returns.Error = errors.New("something bad happened")
+
+				_, err := collection.UsedOnlyByCritical(conn, "some-template-id")
+				Expect(err).To(MatchError(errors.New("something bad happened")))
+			})
+
+			It("returns the kinds repo error", func() {
+				kindsRepo.FindAllByTemplateIDCall.Returns.Error = errors.New("more bad happened")
+
+				_, err := collection.UsedOnlyByCritical(conn, "some-template-id")
+				Expect(err).To(MatchError(errors.New("more bad happened")))
+			})
+		})
+	})
+
 	Describe("Create", func() {
 		It("creates a new template via the templates repo", func() {
 			templatesRepo.CreateCall.Returns.Template = models.Template{
@@ -334,6 +430,7 @@ var _ = Describe("TemplatesCollection", func() {
 				HTML:     "some-html",
 				Subject:  "some-subject",
 				Metadata: "some-metadata",
+				Version:  1,
 			}
 
 			template, err := collection.Create(conn, collections.Template{
@@ -351,6 +448,7 @@ var _ = Describe("TemplatesCollection", func() {
 				HTML:     "some-html",
 				Subject:  "some-subject",
 				Metadata: "some-metadata",
+				Version:  1,
 			}))
 
 			Expect(templatesRepo.CreateCall.Receives.Connection).To(Equal(conn))
@@ -363,11 +461,79 @@ var _ = Describe("TemplatesCollection", func() {
 			}))
 		})
 
+		It("records the new template as its first version", func() {
+			templatesRepo.CreateCall.Returns.Template = models.Template{
+				ID:      "some-template-guid",
+				Name:    "some-template-name",
+				Text:    "some-text",
+				Version: 1,
+			}
+
+			_, err := collection.Create(conn, collections.Template{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(templateVersionsRepo.CreateCall.Receives.Connection).To(Equal(conn))
+			Expect(templateVersionsRepo.CreateCall.Receives.TemplateVersion).To(Equal(models.TemplateVersion{
+				TemplateID: "some-template-guid",
+				Version:    1,
+				Name:       "some-template-name",
+				Text:       "some-text",
+			}))
+		})
+
 		It("propagates errors from repo", func() {
 			templatesRepo.CreateCall.Returns.Error = errors.New("Boom!")
 
 			_, err := collection.Create(conn, collections.Template{})
 			Expect(err).To(Equal(errors.New("Boom!")))
+			Expect(templateVersionsRepo.CreateCall.CallCount).To(Equal(0))
+		})
+
+		It("propagates errors from the versions repo", func() {
+			templateVersionsRepo.CreateCall.Returns.Error = errors.New("versions boom")
+
+			_, err := collection.Create(conn, collections.Template{})
+			Expect(err).To(Equal(errors.New("versions boom")))
+		})
+
+		Context("when the template declares a layout", func() {
+			It("checks the chain of layouts before creating the template", func() {
+				templatesRepo.FindLayoutsCall.Returns.Layouts = []models.Template{
+					{ID: "some-layout-id", HTML: `<body>{{template "content" .}}</body>`},
+				}
+
+				_, err := collection.Create(conn, collections.Template{
+					Name:     "some-template-name",
+					LayoutID: "some-layout-id",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(templatesRepo.FindLayoutsCall.Receives.Connection).To(Equal(conn))
+				Expect(templatesRepo.FindLayoutsCall.Receives.Template.LayoutID).To(Equal("some-layout-id"))
+				Expect(templatesRepo.CreateCall.Receives.Template.LayoutID).To(Equal("some-layout-id"))
+			})
+
+			It("returns a layout error when a layout does not include the content it wraps", func() {
+				templatesRepo.FindLayoutsCall.Returns.Layouts = []models.Template{
+					{ID: "some-layout-id", HTML: "<body></body>"},
+				}
+
+				_, err := collection.Create(conn, collections.Template{
+					LayoutID: "some-layout-id",
+				})
+				Expect(err).To(MatchError(models.TemplateLayoutError{Err: errors.New(`Layout "some-layout-id" does not include {{template "content" .}}`)}))
+				Expect(templatesRepo.CreateCall.Receives.Template).To(Equal(models.Template{}))
+			})
+
+			It("propagates errors finding the layouts", func() {
+				templatesRepo.FindLayoutsCall.Returns.Error = models.TemplateLayoutError{Err: errors.New(`Layout "some-layout-id" creates a circular reference`)}
+
+				_, err := collection.Create(conn, collections.Template{
+					LayoutID: "some-layout-id",
+				})
+				Expect(err).To(MatchError(models.TemplateLayoutError{Err: errors.New(`Layout "some-layout-id" creates a circular reference`)}))
+				Expect(templatesRepo.CreateCall.Receives.Template).To(Equal(models.Template{}))
+			})
 		})
 	})
 
@@ -378,6 +544,9 @@ var _ = Describe("TemplatesCollection", func() {
 
 			Expect(templatesRepo.DestroyCall.Receives.Connection).To(Equal(conn))
 			Expect(templatesRepo.DestroyCall.Receives.TemplateID).To(Equal("templateID"))
+
+			Expect(templateVersionsRepo.DestroyAllByTemplateIDCall.Receives.Connection).To(Equal(conn))
+			Expect(templateVersionsRepo.DestroyAllByTemplateIDCall.Receives.TemplateID).To(Equal("templateID"))
 		})
 
 		It("returns an error if repo destroy returns an error", func() {
@@ -386,5 +555,192 @@ var _ = Describe("TemplatesCollection", func() {
 			err := collection.Delete(conn, "templateID")
 			Expect(err).To(MatchError(errors.New("Boom!!")))
 		})
+
+		It("refuses to delete a template that other templates use as a layout", func() {
+			templatesRepo.FindAllByLayoutIDCall.Returns.Templates = []models.Template{
+				{ID: "first-id", Name: "First", LayoutID: "templateID"},
+				{ID: "second-id", Name: "Second", LayoutID: "templateID"},
+			}
+
+			err := collection.Delete(conn, "templateID")
+			Expect(err).To(MatchError(collections.TemplateInUseError{Err: errors.New(`Template "templateID" is used as a layout by First, Second and cannot be deleted`)}))
+
+			Expect(templatesRepo.FindAllByLayoutIDCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepo.FindAllByLayoutIDCall.Receives.LayoutID).To(Equal("templateID"))
+			Expect(templatesRepo.DestroyCall.Receives.TemplateID).To(BeEmpty())
+		})
+
+		It("returns an error if the layout lookup fails", func() {
+			templatesRepo.FindAllByLayoutIDCall.Returns.Error = errors.New("lookup failed")
+
+			err := collection.Delete(conn, "templateID")
+			Expect(err).To(MatchError(errors.New("lookup failed")))
+			Expect(templatesRepo.DestroyCall.Receives.TemplateID).To(BeEmpty())
+		})
+	})
+
+	Describe("ListVersions", func() {
+		It("lists the versions of a template", func() {
+			createdAt := time.Now().UTC()
+			templateVersionsRepo.FindAllByTemplateIDCall.Returns.TemplateVersions = []models.TemplateVersion{
+				{Primary: 2, TemplateID: "templateID", Version: 2, Text: "new", CreatedAt: createdAt},
+				{Primary: 1, TemplateID: "templateID", Version: 1, Text: "old", CreatedAt: createdAt},
+			}
+
+			versions, err := collection.ListVersions(conn, "templateID")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]collections.TemplateVersion{
+				{TemplateID: "templateID", Version: 2, Text: "new", CreatedAt: createdAt},
+				{TemplateID: "templateID", Version: 1, Text: "old", CreatedAt: createdAt},
+			}))
+
+			Expect(templatesRepo.FindByIDCall.Receives.TemplateID).To(Equal("templateID"))
+			Expect(templateVersionsRepo.FindAllByTemplateIDCall.Receives.Connection).To(Equal(conn))
+			Expect(templateVersionsRepo.FindAllByTemplateIDCall.Receives.TemplateID).To(Equal("templateID"))
+		})
+
+		It("returns an error when the template does not exist", func() {
+			templatesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.ListVersions(conn, "templateID")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("GetVersion", func() {
+		It("returns a single version of a template", func() {
+			templateVersionsRepo.FindCall.Returns.TemplateVersions = map[int]models.TemplateVersion{
+				3: {TemplateID: "templateID", Version: 3, Subject: "some-subject"},
+			}
+
+			version, err := collection.GetVersion(conn, "templateID", 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version).To(Equal(collections.TemplateVersion{
+				TemplateID: "templateID",
+				Version:    3,
+				Subject:    "some-subject",
+			}))
+
+			Expect(templateVersionsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(templateVersionsRepo.FindCall.Receives.TemplateID).To(Equal("templateID"))
+			Expect(templateVersionsRepo.FindCall.Receives.Version).To(Equal(3))
+		})
+
+		It("returns an error when the version does not exist", func() {
+			templateVersionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.GetVersion(conn, "templateID", 3)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("DiffVersions", func() {
+		It("diffs the fields of two versions", func() {
+			templateVersionsRepo.FindCall.Returns.TemplateVersions = map[int]models.TemplateVersion{
+				1: {TemplateID: "templateID", Version: 1, Name: "name", Text: "old text", Subject: "subject"},
+				2: {TemplateID: "templateID", Version: 2, Name: "name", Text: "new text", Subject: "new subject"},
+			}
+
+			diff, err := collection.DiffVersions(conn, "templateID", 1, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(Equal(collections.TemplateDiff{
+				TemplateID: "templateID",
+				From:       1,
+				To:         2,
+				Text:       "-old text\n+new text",
+				Subject:    "-subject\n+new subject",
+			}))
+		})
+
+		It("returns an error when a version cannot be found", func() {
+			templateVersionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.DiffVersions(conn, "templateID", 1, 2)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("Rollback", func() {
+		BeforeEach(func() {
+			templateVersionsRepo.FindCall.Returns.TemplateVersions = map[int]models.TemplateVersion{
+				1: {
+					TemplateID: "templateID",
+					Version:    1,
+					Name:       "old name",
+					Text:       "old text",
+					HTML:       "old html",
+					Subject:    "old subject",
+					Metadata:   "{}",
+				},
+			}
+			templatesRepo.UpdateCall.Returns.Template = models.Template{
+				ID:       "templateID",
+				Name:     "old name",
+				Text:     "old text",
+				HTML:     "old html",
+				Subject:  "old subject",
+				Metadata: "{}",
+				Version:  4,
+			}
+		})
+
+		It("restores the content of the version as a new version", func() {
+			template, err := collection.Rollback(conn, "templateID", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(template).To(Equal(collections.Template{
+				ID:       "templateID",
+				Name:     "old name",
+				Text:     "old text",
+				HTML:     "old html",
+				Subject:  "old subject",
+				Metadata: "{}",
+				Version:  4,
+			}))
+
+			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("templateID"))
+			Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{
+				Name:     "old name",
+				Text:     "old text",
+				HTML:     "old html",
+				Subject:  "old subject",
+				Metadata: "{}",
+			}))
+
+			Expect(templateVersionsRepo.CreateCall.Receives.TemplateVersion).To(Equal(models.TemplateVersion{
+				TemplateID: "templateID",
+				Version:    4,
+				Name:       "old name",
+				Text:       "old text",
+				HTML:       "old html",
+				Subject:    "old subject",
+				Metadata:   "{}",
+			}))
+		})
+
+		It("returns an error when the version cannot be found", func() {
+			templateVersionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.Rollback(conn, "templateID", 1)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+			Expect(templateVersionsRepo.CreateCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when the restored layout can no longer be used", func() {
+			templatesRepo.FindLayoutsCall.Returns.Error = models.TemplateLayoutError{Err: errors.New(`Layout "some-layout-id" could not be found`)}
+
+			_, err := collection.Rollback(conn, "templateID", 1)
+			Expect(err).To(MatchError(models.TemplateLayoutError{Err: errors.New(`Layout "some-layout-id" could not be found`)}))
+			Expect(templatesRepo.FindLayoutsCall.Receives.Template.ID).To(Equal("templateID"))
+			Expect(templateVersionsRepo.CreateCall.CallCount).To(Equal(0))
+		})
+
+		It("returns an error when the template cannot be updated", func() {
+			templatesRepo.UpdateCall.Returns.Error = errors.New("update failed")
+
+			_, err := collection.Rollback(conn, "templateID", 1)
+			Expect(err).To(MatchError(errors.New("update failed")))
+			Expect(templateVersionsRepo.CreateCall.CallCount).To(Equal(0))
+		})
 	})
 })
@@ -0,0 +1,59 @@
+package collections
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type TransportAssignmentError struct {
+	Err error
+}
+
+func (e TransportAssignmentError) Error() string {
+	return e.Err.Error()
+}
+
+type transportsRepository interface {
+	Find(connection models.ConnectionInterface, name string) (models.Transport, error)
+}
+
+type TransportsCollection struct {
+	clientsRepo    clientsRepository
+	transportsRepo transportsRepository
+}
+
+func NewTransportsCollection(clientsRepo clientsRepository, transportsRepo transportsRepository) TransportsCollection {
+	return TransportsCollection{
+		clientsRepo:    clientsRepo,
+		transportsRepo: transportsRepo,
+	}
+}
+
+// AssignToClient binds the client to the named transport. An empty name
+// returns the client to the platform SMTP relays.
+func (c TransportsCollection) AssignToClient(conn ConnectionInterface, clientID, transport string) error {
+	client, err := c.clientsRepo.Find(conn, clientID)
+	if err != nil {
+		return err
+	}
+
+	if transport != "" {
+		_, err = c.transportsRepo.Find(conn, transport)
+		if err != nil {
+			if _, ok := err.(models.NotFoundError); ok {
+				return TransportAssignmentError{fmt.Errorf("No transport named %q", transport)}
+			}
+			return err
+		}
+	}
+
+	client.Transport = transport
+
+	_, err = c.clientsRepo.Update(conn, client)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package collections_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransportsCollection", func() {
+	var (
+		clientsRepo    *mocks.ClientsRepository
+		transportsRepo *mocks.TransportsRepo
+		conn           *mocks.Connection
+
+		collection collections.TransportsCollection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+
+		clientsRepo = mocks.NewClientsRepository()
+		clientsRepo.FindCall.Returns.Client = models.Client{
+			ID:        "my-client",
+			Transport: "old-transport",
+		}
+		transportsRepo = mocks.NewTransportsRepo()
+
+		collection = collections.NewTransportsCollection(clientsRepo, transportsRepo)
+	})
+
+	Describe("AssignToClient", func() {
+		It("binds the transport to the given client", func() {
+			err := collection.AssignToClient(conn, "my-client", "billing")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(clientsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(clientsRepo.FindCall.Receives.ClientID).To(Equal("my-client"))
+
+			Expect(transportsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(transportsRepo.FindCall.Receives.Name).To(Equal("billing"))
+
+			Expect(clientsRepo.UpdateCall.Receives.Connection).To(Equal(conn))
+			Expect(clientsRepo.UpdateCall.Receives.Client).To(Equal(models.Client{
+				ID:        "my-client",
+				Transport: "billing",
+			}))
+		})
+
+		It("clears the binding when the transport is empty", func() {
+			err := collection.AssignToClient(conn, "my-client", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transportsRepo.FindCall.Receives.Name).To(BeEmpty())
+			Expect(clientsRepo.UpdateCall.Receives.Client).To(Equal(models.Client{
+				ID: "my-client",
+			}))
+		})
+
+		Context("failure cases", func() {
+			It("returns the error when the client cannot be found", func() {
+				clientsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+				err := collection.AssignToClient(conn, "missing-client", "billing")
+				Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+			})
+
+			It("reports that the transport cannot be found", func() {
+				transportsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+				err := collection.AssignToClient(conn, "my-client", "missing")
+				Expect(err).To(MatchError(collections.TransportAssignmentError{Err: errors.New(`No transport named "missing"`)}))
+			})
+
+			It("returns any other error finding the transport", func() {
+				transportsRepo.FindCall.Returns.Error = errors.New("database failure")
+
+				err := collection.AssignToClient(conn, "my-client", "billing")
+				Expect(err).To(MatchError(errors.New("database failure")))
+			})
+
+			It("returns the error when the client cannot be updated", func() {
+				clientsRepo.UpdateCall.Returns.Error = errors.New("database fail")
+
+				err := collection.AssignToClient(conn, "my-client", "billing")
+				Expect(err).To(MatchError(errors.New("database fail")))
+			})
+		})
+	})
+})
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+type Attachment struct {
+	ID          string    `db:"id"`
+	CampaignID  string    `db:"campaign_id"`
+	Filename    string    `db:"filename"`
+	ContentType string    `db:"content_type"`
+	ContentID   string    `db:"content_id"`
+	Content     []byte    `db:"content"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (a *Attachment) PreInsert(s gorp.SqlExecutor) error {
+	if (a.CreatedAt == time.Time{}) {
+		a.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type AttachmentsRepo struct {
+	generateID IDGeneratorFunc
+}
+
+func NewAttachmentsRepo(guidGenerator IDGeneratorFunc) AttachmentsRepo {
+	return AttachmentsRepo{
+		generateID: guidGenerator,
+	}
+}
+
+func (repo AttachmentsRepo) Create(conn ConnectionInterface, attachment Attachment) (Attachment, error) {
+	var err error
+	attachment.ID, err = repo.generateID()
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	err = conn.Insert(&attachment)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+func (repo AttachmentsRepo) FindByID(conn ConnectionInterface, attachmentID string) (Attachment, error) {
+	attachment := Attachment{}
+	err := conn.SelectOne(&attachment, "SELECT * FROM `attachments` WHERE `id`=?", attachmentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Attachment{}, NotFoundError{fmt.Errorf("Attachment with ID %q could not be found", attachmentID)}
+		}
+		return Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// DeleteUnreferencedBefore deletes attachments created before the threshold
+// that no queued job mentions, either by ID or by the campaign they belong
+// to. Jobs stay queued until their message is delivered or has failed for
+// good, so the attachments left behind are no longer needed.
+func (repo AttachmentsRepo) DeleteUnreferencedBefore(conn ConnectionInterface, threshold time.Time) (int, error) {
+	result, err := conn.Exec("DELETE FROM `attachments` WHERE `created_at` < ? AND NOT EXISTS ("+
+		"SELECT 1 FROM `jobs` WHERE `jobs`.`payload` LIKE CONCAT('%', `attachments`.`id`, '%') "+
+		"OR (`attachments`.`campaign_id` <> '' AND `jobs`.`payload` LIKE CONCAT('%', `attachments`.`campaign_id`, '%')))", threshold.UTC())
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
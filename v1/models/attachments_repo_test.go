@@ -0,0 +1,129 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/application"
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AttachmentsRepo", func() {
+	var (
+		repo          models.AttachmentsRepo
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid"}
+
+		repo = models.NewAttachmentsRepo(guidGenerator.Generate)
+	})
+
+	Describe("Create", func() {
+		It("inserts an attachment into the database", func() {
+			attachment, err := repo.Create(conn, models.Attachment{
+				Filename:    "invoice.pdf",
+				ContentType: "application/pdf",
+				Content:     []byte("some-content"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attachment.ID).To(Equal("first-random-guid"))
+			Expect(attachment.CreatedAt).NotTo(BeZero())
+		})
+
+		It("returns an error when the guid generator errors", func() {
+			guidGenerator.GenerateCall.Returns.Error = errors.New("something bad")
+
+			_, err := repo.Create(conn, models.Attachment{})
+			Expect(err).To(MatchError(errors.New("something bad")))
+		})
+	})
+
+	Describe("FindByID", func() {
+		It("finds attachments created in the database", func() {
+			attachment, err := repo.Create(conn, models.Attachment{
+				Filename:    "logo.png",
+				ContentType: "image/png",
+				ContentID:   "logo",
+				Content:     []byte("some-content"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			foundAttachment, err := repo.FindByID(conn, attachment.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundAttachment.Filename).To(Equal("logo.png"))
+			Expect(foundAttachment.ContentType).To(Equal("image/png"))
+			Expect(foundAttachment.ContentID).To(Equal("logo"))
+			Expect(foundAttachment.Content).To(Equal([]byte("some-content")))
+		})
+
+		It("returns a NotFoundError when the attachment does not exist", func() {
+			_, err := repo.FindByID(conn, "missing-attachment-id")
+			Expect(err).To(MatchError(models.NotFoundError{errors.New(`Attachment with ID "missing-attachment-id" could not be found`)}))
+		})
+	})
+
+	Describe("DeleteUnreferencedBefore", func() {
+		var (
+			gobbleDatabase *gobble.DB
+			createdAt      time.Time
+		)
+
+		BeforeEach(func() {
+			env, err := application.NewEnvironment()
+			Expect(err).NotTo(HaveOccurred())
+
+			gobbleDatabase = gobble.NewDatabase(sqlDB)
+			gobbleDatabase.Migrate(env.GobbleMigrationsPath)
+			_, err = gobbleDatabase.Connection.Exec("DELETE FROM `jobs`")
+			Expect(err).NotTo(HaveOccurred())
+
+			createdAt = time.Now().Add(-2 * time.Hour).Truncate(time.Second).UTC()
+			guidGenerator.GenerateCall.Returns.IDs = []string{"queued-attachment", "campaign-attachment", "sent-attachment", "new-attachment"}
+
+			for _, attachment := range []models.Attachment{
+				{Filename: "queued.pdf", CreatedAt: createdAt},
+				{Filename: "campaign.pdf", CampaignID: "queued-campaign", CreatedAt: createdAt},
+				{Filename: "sent.pdf", CreatedAt: createdAt},
+				{Filename: "new.pdf", CreatedAt: time.Now().Truncate(time.Second).UTC()},
+			} {
+				_, err := repo.Create(conn, attachment)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			err = gobbleDatabase.Connection.Insert(
+				&gobble.Job{Payload: `{"Options": {"AttachmentIDs": ["queued-attachment"]}}`, ActiveAt: time.Now()},
+				&gobble.Job{Payload: `{"JobType": "campaign", "Campaign": {"ID": "queued-campaign"}}`, ActiveAt: time.Now()},
+			)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("deletes old attachments that no queued job references", func() {
+			count, err := repo.DeleteUnreferencedBefore(conn, time.Now().Add(-1*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(1))
+
+			_, err = repo.FindByID(conn, "sent-attachment")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+
+			for _, id := range []string{"queued-attachment", "campaign-attachment", "new-attachment"} {
+				_, err = repo.FindByID(conn, id)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	})
+})
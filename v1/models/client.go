@@ -12,6 +12,7 @@ type Client struct {
 	Description string    `db:"description"`
 	CreatedAt   time.Time `db:"created_at"`
 	TemplateID  string    `db:"template_id"`
+	Transport   string    `db:"transport"`
 }
 
 func (c Client) TemplateToUse() string {
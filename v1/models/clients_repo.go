@@ -57,6 +57,7 @@ func (repo ClientsRepo) Upsert(conn ConnectionInterface, client Client) (Client,
 	existingClient, err := repo.Find(conn, client.ID)
 	client.Primary = existingClient.Primary
 	client.CreatedAt = existingClient.CreatedAt
+	client.Transport = existingClient.Transport
 
 	switch err.(type) {
 	case NotFoundError:
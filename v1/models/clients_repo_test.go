@@ -179,6 +179,22 @@ var _ = Describe("ClientsRepo", func() {
 				Expect(client.Description).To(Equal("My Client"))
 				Expect(client.CreatedAt).To(BeTemporally("~", time.Now(), 2*time.Second))
 			})
+
+			It("keeps the transport bound to the client", func() {
+				client, err := repo.Upsert(conn, models.Client{ID: "my-client"})
+				Expect(err).NotTo(HaveOccurred())
+
+				client.Transport = "billing"
+				_, err = repo.Update(conn, client)
+				Expect(err).NotTo(HaveOccurred())
+
+				client, err = repo.Upsert(conn, models.Client{
+					ID:          "my-client",
+					Description: "My Client",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(client.Transport).To(Equal("billing"))
+			})
 		})
 
 		Context("when the record comes into existence after the Find, but before we create it", func() {
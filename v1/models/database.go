@@ -34,5 +34,9 @@ func Setup(database *db.DB) {
 	database.TableMap().AddTableWithName(Unsubscribe{}, "unsubscribes").SetKeys(true, "Primary").SetUniqueTogether("user_id", "client_id", "kind_id")
 	database.TableMap().AddTableWithName(GlobalUnsubscribe{}, "global_unsubscribes").SetKeys(true, "Primary").ColMap("UserID").SetUnique(true)
 	database.TableMap().AddTableWithName(Template{}, "templates").SetKeys(true, "Primary").ColMap("Name").SetUnique(true)
+	database.TableMap().AddTableWithName(TemplateVersion{}, "template_versions").SetKeys(true, "Primary").SetUniqueTogether("template_id", "version")
 	database.TableMap().AddTableWithName(Message{}, "messages").SetKeys(false, "ID")
+	database.TableMap().AddTableWithName(Attachment{}, "attachments").SetKeys(false, "ID")
+	database.TableMap().AddTableWithName(Suppression{}, "suppressions").SetKeys(false, "Address")
+	database.TableMap().AddTableWithName(UserLocale{}, "user_locales").SetKeys(false, "UserID")
 }
@@ -52,7 +52,7 @@ func (d DatabaseMigrator) Seed(database DatabaseInterface, defaultTemplatePath s
 			panic(err)
 		}
 
-		_, err = repo.Create(conn, Template{
+		createdTemplate, err := repo.Create(conn, Template{
 			ID:       DefaultTemplateID,
 			Name:     template.Name,
 			Subject:  template.Subject,
@@ -64,6 +64,11 @@ func (d DatabaseMigrator) Seed(database DatabaseInterface, defaultTemplatePath s
 			panic(err)
 		}
 
+		_, err = NewTemplateVersionsRepo().Create(conn, NewTemplateVersion(createdTemplate))
+		if err != nil {
+			panic(err)
+		}
+
 		return
 	}
 
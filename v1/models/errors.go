@@ -24,6 +24,14 @@ func (e TransactionCommitError) Error() string {
 	return e.Err.Error()
 }
 
+type TemplateLayoutError struct {
+	Err error
+}
+
+func (e TemplateLayoutError) Error() string {
+	return e.Err.Error()
+}
+
 type TemplateUpdateError struct {
 	Err error
 }
@@ -11,6 +11,7 @@ type Kind struct {
 	ID          string    `db:"id"`
 	Description string    `db:"description"`
 	Critical    bool      `db:"critical"`
+	Threading   bool      `db:"threading"`
 	ClientID    string    `db:"client_id"`
 	CreatedAt   time.Time `db:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at"`
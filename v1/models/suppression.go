@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+type Suppression struct {
+	Address    string    `db:"address"`
+	Reason     string    `db:"reason"`
+	Status     string    `db:"status"`
+	Diagnostic string    `db:"diagnostic"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+func (s *Suppression) PreInsert(e gorp.SqlExecutor) error {
+	now := time.Now().Truncate(1 * time.Second).UTC()
+	if (s.CreatedAt == time.Time{}) {
+		s.CreatedAt = now
+	}
+	s.UpdatedAt = now
+
+	return nil
+}
+
+func (s *Suppression) PreUpdate(e gorp.SqlExecutor) error {
+	s.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+type SuppressionsRepo struct{}
+
+func NewSuppressionsRepo() SuppressionsRepo {
+	return SuppressionsRepo{}
+}
+
+func (repo SuppressionsRepo) Upsert(conn ConnectionInterface, suppression Suppression) (Suppression, error) {
+	suppression.Address = strings.ToLower(suppression.Address)
+
+	existing, err := repo.Find(conn, suppression.Address)
+	switch err.(type) {
+	case NotFoundError:
+		err = conn.Insert(&suppression)
+	case nil:
+		suppression.CreatedAt = existing.CreatedAt
+		_, err = conn.Update(&suppression)
+	}
+	if err != nil {
+		return Suppression{}, err
+	}
+
+	return suppression, nil
+}
+
+func (repo SuppressionsRepo) Find(conn ConnectionInterface, address string) (Suppression, error) {
+	suppression := Suppression{}
+	err := conn.SelectOne(&suppression, "SELECT * FROM `suppressions` WHERE `address` = ?", strings.ToLower(address))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Suppression{}, NotFoundError{fmt.Errorf("Suppression for %q could not be found", address)}
+		}
+		return Suppression{}, err
+	}
+
+	return suppression, nil
+}
+
+func (repo SuppressionsRepo) Delete(conn ConnectionInterface, address string) error {
+	result, err := conn.Exec("DELETE FROM `suppressions` WHERE `address` = ?", strings.ToLower(address))
+	if err != nil {
+		return err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return NotFoundError{fmt.Errorf("Suppression for %q could not be found", address)}
+	}
+
+	return nil
+}
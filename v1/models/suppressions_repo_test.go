@@ -0,0 +1,93 @@
+package models_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SuppressionsRepo", func() {
+	var (
+		repo models.SuppressionsRepo
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		repo = models.NewSuppressionsRepo()
+	})
+
+	Describe("Upsert", func() {
+		It("inserts a suppression keyed by the lowercased address", func() {
+			suppression, err := repo.Upsert(conn, models.Suppression{
+				Address: "Some-User@Example.com",
+				Reason:  "bounce",
+				Status:  "5.1.1",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppression.Address).To(Equal("some-user@example.com"))
+
+			found, err := repo.Find(conn, "some-user@example.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.Reason).To(Equal("bounce"))
+			Expect(found.Status).To(Equal("5.1.1"))
+		})
+
+		It("updates an existing suppression", func() {
+			_, err := repo.Upsert(conn, models.Suppression{
+				Address: "some-user@example.com",
+				Reason:  "bounce",
+				Status:  "5.1.1",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Upsert(conn, models.Suppression{
+				Address: "some-user@example.com",
+				Reason:  "complaint",
+				Status:  "abuse",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			found, err := repo.Find(conn, "SOME-USER@example.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.Reason).To(Equal("complaint"))
+			Expect(found.Status).To(Equal("abuse"))
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns a not found error when the address is not suppressed", func() {
+			_, err := repo.Find(conn, "missing@example.com")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Suppression for \"missing@example.com\" could not be found")}))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("removes the suppression", func() {
+			_, err := repo.Upsert(conn, models.Suppression{
+				Address: "some-user@example.com",
+				Reason:  "bounce",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Delete(conn, "some-user@example.com")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Find(conn, "some-user@example.com")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+
+		It("returns a not found error when the address is not suppressed", func() {
+			err := repo.Delete(conn, "missing@example.com")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+	})
+})
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+// TemplateVersion is an immutable copy of a template as it was after a
+// create or update.
+type TemplateVersion struct {
+	Primary       int       `db:"primary"`
+	TemplateID    string    `db:"template_id"`
+	Version       int       `db:"version"`
+	Name          string    `db:"name"`
+	Subject       string    `db:"subject"`
+	Text          string    `db:"text"`
+	HTML          string    `db:"html"`
+	Metadata      string    `db:"metadata"`
+	Localizations string    `db:"localizations"`
+	LayoutID      string    `db:"layout_id"`
+	CreatedAt     time.Time `db:"created_at"`
+
+	DisableAutoText    bool `db:"disable_auto_text"`
+	DisableCSSInlining bool `db:"disable_css_inlining"`
+}
+
+func NewTemplateVersion(template Template) TemplateVersion {
+	return TemplateVersion{
+		TemplateID:    template.ID,
+		Version:       template.Version,
+		Name:          template.Name,
+		Subject:       template.Subject,
+		Text:          template.Text,
+		HTML:          template.HTML,
+		Metadata:      template.Metadata,
+		Localizations: template.Localizations,
+		LayoutID:      template.LayoutID,
+
+		DisableAutoText:    template.DisableAutoText,
+		DisableCSSInlining: template.DisableCSSInlining,
+	}
+}
+
+func (v *TemplateVersion) PreInsert(s gorp.SqlExecutor) error {
+	if (v.CreatedAt == time.Time{}) {
+		v.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type TemplateVersionsRepo struct{}
+
+func NewTemplateVersionsRepo() TemplateVersionsRepo {
+	return TemplateVersionsRepo{}
+}
+
+func (repo TemplateVersionsRepo) Create(conn ConnectionInterface, version TemplateVersion) (TemplateVersion, error) {
+	err := conn.Insert(&version)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	return version, nil
+}
+
+func (repo TemplateVersionsRepo) Find(conn ConnectionInterface, templateID string, version int) (TemplateVersion, error) {
+	templateVersion := TemplateVersion{}
+	err := conn.SelectOne(&templateVersion, "SELECT * FROM `template_versions` WHERE `template_id` = ? AND `version` = ?", templateID, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return TemplateVersion{}, NotFoundError{fmt.Errorf("Version %d of template with ID %q could not be found", version, templateID)}
+		}
+		return TemplateVersion{}, err
+	}
+
+	return templateVersion, nil
+}
+
+func (repo TemplateVersionsRepo) FindAllByTemplateID(conn ConnectionInterface, templateID string) ([]TemplateVersion, error) {
+	versions := []TemplateVersion{}
+	_, err := conn.Select(&versions, "SELECT * FROM `template_versions` WHERE `template_id` = ? ORDER BY `version` DESC", templateID)
+	if err != nil {
+		return []TemplateVersion{}, err
+	}
+
+	return versions, nil
+}
+
+func (repo TemplateVersionsRepo) DestroyAllByTemplateID(conn ConnectionInterface, templateID string) error {
+	_, err := conn.Exec("DELETE FROM `template_versions` WHERE `template_id` = ?", templateID)
+	return err
+}
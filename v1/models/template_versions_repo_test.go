@@ -0,0 +1,83 @@
+package models_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionsRepo", func() {
+	var (
+		repo models.TemplateVersionsRepo
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		repo = models.NewTemplateVersionsRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		for version, text := range []string{"first text", "second text"} {
+			_, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "raptor_template",
+				Version:    version + 1,
+				Name:       "Raptors On The Run",
+				Text:       text,
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	Describe("Create", func() {
+		It("rejects a second copy of the same version", func() {
+			_, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "raptor_template",
+				Version:    2,
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Find", func() {
+		It("finds a single version of a template", func() {
+			version, err := repo.Find(conn, "raptor_template", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.TemplateID).To(Equal("raptor_template"))
+			Expect(version.Version).To(Equal(1))
+			Expect(version.Text).To(Equal("first text"))
+			Expect(version.CreatedAt).NotTo(BeZero())
+		})
+
+		It("returns a not found error when the version does not exist", func() {
+			_, err := repo.Find(conn, "raptor_template", 3)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New(`Version 3 of template with ID "raptor_template" could not be found`)}))
+		})
+	})
+
+	Describe("FindAllByTemplateID", func() {
+		It("lists the versions of a template, newest first", func() {
+			versions, err := repo.FindAllByTemplateID(conn, "raptor_template")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0].Version).To(Equal(2))
+			Expect(versions[1].Version).To(Equal(1))
+		})
+	})
+
+	Describe("DestroyAllByTemplateID", func() {
+		It("removes every version of a template", func() {
+			err := repo.DestroyAllByTemplateID(conn, "raptor_template")
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := repo.FindAllByTemplateID(conn, "raptor_template")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(BeEmpty())
+		})
+	})
+})
@@ -13,8 +13,12 @@ func NewTemplatesRepo() TemplatesRepo {
 }
 
 func (repo TemplatesRepo) FindByID(conn ConnectionInterface, templateID string) (Template, error) {
+	return repo.find(conn, "SELECT * FROM `templates` WHERE `id`=?", templateID)
+}
+
+func (repo TemplatesRepo) find(conn ConnectionInterface, query, templateID string) (Template, error) {
 	template := Template{}
-	err := conn.SelectOne(&template, "SELECT * FROM `templates` WHERE `id`=?", templateID)
+	err := conn.SelectOne(&template, query, templateID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return template, NotFoundError{fmt.Errorf("Template with ID %q could not be found", templateID)}
@@ -24,8 +28,10 @@ func (repo TemplatesRepo) FindByID(conn ConnectionInterface, templateID string)
 	return template, nil
 }
 
+// Update locks the template row while it bumps the version, so that callers
+// running in a transaction can record the version before anyone else claims it.
 func (repo TemplatesRepo) Update(conn ConnectionInterface, templateID string, template Template) (Template, error) {
-	existingTemplate, err := repo.FindByID(conn, templateID)
+	existingTemplate, err := repo.find(conn, "SELECT * FROM `templates` WHERE `id`=? FOR UPDATE", templateID)
 	if err != nil {
 		return existingTemplate, err
 	}
@@ -35,6 +41,7 @@ func (repo TemplatesRepo) Update(conn ConnectionInterface, templateID string, te
 	template.CreatedAt = existingTemplate.CreatedAt
 	template.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
 	template.Overridden = true
+	template.Version = existingTemplate.Version + 1
 
 	_, err = conn.Update(&template)
 	if err != nil {
@@ -44,6 +51,43 @@ func (repo TemplatesRepo) Update(conn ConnectionInterface, templateID string, te
 	return template, nil
 }
 
+// FindLayouts returns the chain of layouts wrapping the template, starting
+// with the innermost.
+func (repo TemplatesRepo) FindLayouts(conn ConnectionInterface, template Template) ([]Template, error) {
+	var layouts []Template
+	seen := map[string]bool{template.ID: true}
+
+	for layoutID := template.LayoutID; layoutID != ""; {
+		if seen[layoutID] {
+			return nil, TemplateLayoutError{fmt.Errorf("Layout %q creates a circular reference", template.LayoutID)}
+		}
+		seen[layoutID] = true
+
+		layout, err := repo.FindByID(conn, layoutID)
+		if err != nil {
+			if _, ok := err.(NotFoundError); ok {
+				return nil, TemplateLayoutError{fmt.Errorf("Layout %q could not be found", layoutID)}
+			}
+			return nil, err
+		}
+
+		layouts = append(layouts, layout)
+		layoutID = layout.LayoutID
+	}
+
+	return layouts, nil
+}
+
+func (repo TemplatesRepo) FindAllByLayoutID(conn ConnectionInterface, layoutID string) ([]Template, error) {
+	templates := []Template{}
+	_, err := conn.Select(&templates, "SELECT * FROM `templates` WHERE `layout_id` = ? ORDER BY `name`", layoutID)
+	if err != nil {
+		return []Template{}, err
+	}
+
+	return templates, nil
+}
+
 func (repo TemplatesRepo) ListIDsAndNames(conn ConnectionInterface) ([]Template, error) {
 	templates := []Template{}
 	_, err := conn.Select(&templates, "SELECT ID, Name FROM `templates`")
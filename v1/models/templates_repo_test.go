@@ -86,6 +86,7 @@ var _ = Describe("TemplatesRepo", func() {
 			Expect(foundTemplate.HTML).To(Equal(newTemplate.HTML))
 			Expect(foundTemplate.CreatedAt).To(BeTemporally("~", time.Now().UTC(), 2*time.Second))
 			Expect(foundTemplate.UpdatedAt).To(BeTemporally("~", time.Now().UTC(), 2*time.Second))
+			Expect(foundTemplate.Version).To(Equal(1))
 		})
 	})
 
@@ -121,6 +122,7 @@ var _ = Describe("TemplatesRepo", func() {
 				Expect(foundTemplate.UpdatedAt).ToNot(Equal(createdAt))
 				Expect(foundTemplate.UpdatedAt).To(BeTemporally(">", createdAt))
 				Expect(foundTemplate.Overridden).To(BeTrue())
+				Expect(foundTemplate.Version).To(Equal(2))
 			})
 		})
 
@@ -132,6 +134,68 @@ var _ = Describe("TemplatesRepo", func() {
 		})
 	})
 
+	Describe("#FindLayouts", func() {
+		BeforeEach(func() {
+			layouts := []models.Template{
+				{ID: "inner_layout", Name: "Inner", HTML: `<div>{{template "content" .}}</div>`, LayoutID: "outer_layout", CreatedAt: createdAt},
+				{ID: "outer_layout", Name: "Outer", HTML: `<body>{{template "content" .}}</body>`, CreatedAt: createdAt},
+			}
+			for i := range layouts {
+				err := conn.Insert(&layouts[i])
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("returns the layouts wrapping the template, innermost first", func() {
+			template.LayoutID = "inner_layout"
+
+			layouts, err := repo.FindLayouts(conn, template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(layouts).To(HaveLen(2))
+			Expect(layouts[0].ID).To(Equal("inner_layout"))
+			Expect(layouts[1].ID).To(Equal("outer_layout"))
+		})
+
+		It("returns a layout error when a layout is missing", func() {
+			template.LayoutID = "missing_layout"
+
+			_, err := repo.FindLayouts(conn, template)
+			Expect(err).To(MatchError(models.TemplateLayoutError{Err: errors.New(`Layout "missing_layout" could not be found`)}))
+		})
+
+		It("returns a layout error when the layouts lead back to the template", func() {
+			template.ID = "outer_layout"
+			template.LayoutID = "inner_layout"
+
+			_, err := repo.FindLayouts(conn, template)
+			Expect(err).To(MatchError(models.TemplateLayoutError{Err: errors.New(`Layout "inner_layout" creates a circular reference`)}))
+		})
+	})
+
+	Describe("#FindAllByLayoutID", func() {
+		It("returns the templates wrapped in the layout", func() {
+			templates := []models.Template{
+				{ID: "some_layout", Name: "Layout", HTML: `<div>{{template "content" .}}</div>`, CreatedAt: createdAt},
+				{ID: "wrapped_template", Name: "Wrapped", HTML: "<p>wrapped</p>", LayoutID: "some_layout", CreatedAt: createdAt},
+			}
+			for i := range templates {
+				err := conn.Insert(&templates[i])
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			wrapped, err := repo.FindAllByLayoutID(conn, "some_layout")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(wrapped).To(HaveLen(1))
+			Expect(wrapped[0].ID).To(Equal("wrapped_template"))
+		})
+
+		It("returns an empty list when no templates use the layout", func() {
+			wrapped, err := repo.FindAllByLayoutID(conn, "raptor_template")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(wrapped).To(BeEmpty())
+		})
+	})
+
 	Describe("#ListIDsAndNames", func() {
 		Context("there are templates in the database", func() {
 			It("returns a list of templates - ID and Name only", func() {
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Transport is the v1 view of a named SMTP transport. Transports are managed
+// through the v2 API; v1 only needs to know that one exists.
+type Transport struct {
+	Name string `db:"name"`
+}
+
+type TransportsRepo struct{}
+
+func NewTransportsRepo() TransportsRepo {
+	return TransportsRepo{}
+}
+
+func (repo TransportsRepo) Find(conn ConnectionInterface, name string) (Transport, error) {
+	transport := Transport{}
+	err := conn.SelectOne(&transport, "SELECT `name` FROM `transports` WHERE `name` = ?", name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Transport{}, NotFoundError{fmt.Errorf("Transport %q could not be found", name)}
+		}
+		return Transport{}, err
+	}
+
+	return transport, nil
+}
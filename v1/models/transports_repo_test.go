@@ -0,0 +1,45 @@
+package models_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransportsRepo", func() {
+	var (
+		repo models.TransportsRepo
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		repo = models.NewTransportsRepo()
+	})
+
+	Describe("Find", func() {
+		It("finds a transport by name", func() {
+			_, err := conn.Exec("INSERT INTO `transports` (`name`, `host`, `port`) VALUES (?, ?, ?)", "billing", "smtp.example.com", "587")
+			Expect(err).NotTo(HaveOccurred())
+
+			transport, err := repo.Find(conn, "billing")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transport).To(Equal(models.Transport{Name: "billing"}))
+		})
+
+		Context("when the transport does not exist", func() {
+			It("returns a not found error", func() {
+				_, err := repo.Find(conn, "missing")
+				Expect(err).To(MatchError(models.NotFoundError{Err: errors.New(`Transport "missing" could not be found`)}))
+			})
+		})
+	})
+})
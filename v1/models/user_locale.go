@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+type UserLocale struct {
+	UserID    string    `db:"user_id"`
+	Locale    string    `db:"locale"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type UserLocalesRepo struct{}
+
+func NewUserLocalesRepo() UserLocalesRepo {
+	return UserLocalesRepo{}
+}
+
+// Set stores the locale a user prefers to receive notifications in. An empty
+// locale clears the preference.
+func (repo UserLocalesRepo) Set(conn ConnectionInterface, userGUID, locale string) error {
+	userLocale, err := repo.find(conn, userGUID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if locale == "" {
+			return nil
+		}
+
+		return conn.Insert(&UserLocale{
+			UserID:    userGUID,
+			Locale:    locale,
+			UpdatedAt: time.Now().Truncate(1 * time.Second).UTC(),
+		})
+	}
+
+	if locale == "" {
+		_, err = conn.Delete(&userLocale)
+		return err
+	}
+
+	userLocale.Locale = locale
+	userLocale.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	_, err = conn.Update(&userLocale)
+
+	return err
+}
+
+func (repo UserLocalesRepo) Get(conn ConnectionInterface, userGUID string) (string, error) {
+	userLocale, err := repo.find(conn, userGUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return userLocale.Locale, nil
+}
+
+func (repo UserLocalesRepo) find(conn ConnectionInterface, userGUID string) (UserLocale, error) {
+	userLocale := UserLocale{}
+	err := conn.SelectOne(&userLocale, "SELECT * FROM `user_locales` WHERE `user_id` = ?", userGUID)
+	if err != nil {
+		return UserLocale{}, err
+	}
+
+	return userLocale, nil
+}
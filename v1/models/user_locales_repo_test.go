@@ -0,0 +1,58 @@
+package models_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UserLocalesRepo", func() {
+	var (
+		repo models.UserLocalesRepo
+		conn *db.Connection
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection().(*db.Connection)
+		repo = models.NewUserLocalesRepo()
+	})
+
+	It("stores the locale for a user, allowing it to be retrieved later", func() {
+		err := repo.Set(conn, "my-user", "fr-CA")
+		Expect(err).NotTo(HaveOccurred())
+
+		locale, err := repo.Get(conn, "my-user")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(locale).To(Equal("fr-CA"))
+
+		err = repo.Set(conn, "my-user", "de")
+		Expect(err).NotTo(HaveOccurred())
+
+		locale, err = repo.Get(conn, "my-user")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(locale).To(Equal("de"))
+	})
+
+	It("clears the locale when it is set to an empty string", func() {
+		err := repo.Set(conn, "my-user", "fr-CA")
+		Expect(err).NotTo(HaveOccurred())
+
+		err = repo.Set(conn, "my-user", "")
+		Expect(err).NotTo(HaveOccurred())
+
+		locale, err := repo.Get(conn, "my-user")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(locale).To(BeEmpty())
+	})
+
+	It("returns an empty locale for a user without a preference", func() {
+		locale, err := repo.Get(conn, "unknown-user")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(locale).To(BeEmpty())
+	})
+})
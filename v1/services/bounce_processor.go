@@ -0,0 +1,55 @@
+package services
+
+import (
+	"io"
+
+	"github.com/cloudfoundry-incubator/notifications/bounces"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type BounceProcessor struct {
+	suppressionsRepo SuppressionsRepo
+}
+
+func NewBounceProcessor(suppressionsRepo SuppressionsRepo) BounceProcessor {
+	return BounceProcessor{
+		suppressionsRepo: suppressionsRepo,
+	}
+}
+
+// Process records every recipient that hard bounced or complained in the
+// given report, returning the suppressed addresses. Transient failures such
+// as delayed deliveries are ignored.
+func (p BounceProcessor) Process(database DatabaseInterface, report io.Reader) ([]string, error) {
+	feedback, err := bounces.Parse(report)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := database.Connection()
+	suppressed := []string{}
+
+	for _, f := range feedback {
+		if !f.Permanent() {
+			continue
+		}
+
+		suppression, err := p.suppressionsRepo.Upsert(conn, models.Suppression{
+			Address:    f.Recipient,
+			Reason:     f.Type,
+			Status:     f.Status,
+			Diagnostic: f.Diagnostic,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		suppressed = append(suppressed, suppression.Address)
+	}
+
+	return suppressed, nil
+}
+
+func (p BounceProcessor) Unsuppress(database DatabaseInterface, address string) error {
+	return p.suppressionsRepo.Delete(database.Connection(), address)
+}
@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/bounces"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const bounceReport = `From: MAILER-DAEMON@mx.example.com
+Content-Type: multipart/report; report-type=delivery-status; boundary="dsn-boundary"
+
+--dsn-boundary
+Content-Type: message/delivery-status
+
+Reporting-MTA: dns; mx.example.com
+
+Final-Recipient: rfc822; missing-user@example.com
+Action: failed
+Status: 5.1.1
+Diagnostic-Code: smtp; 550 5.1.1 Recipient address rejected
+
+Final-Recipient: rfc822; full-mailbox@example.com
+Action: delayed
+Status: 4.2.2
+
+--dsn-boundary--
+`
+
+var _ = Describe("BounceProcessor", func() {
+	var (
+		processor        services.BounceProcessor
+		suppressionsRepo *mocks.SuppressionsRepo
+		database         *mocks.Database
+		conn             *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		suppressionsRepo = mocks.NewSuppressionsRepo()
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		processor = services.NewBounceProcessor(suppressionsRepo)
+	})
+
+	Describe("Process", func() {
+		It("suppresses recipients that permanently failed", func() {
+			suppressed, err := processor.Process(database, strings.NewReader(bounceReport))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppressed).To(Equal([]string{"missing-user@example.com"}))
+
+			Expect(suppressionsRepo.UpsertCall.Receives.Connection).To(Equal(conn))
+			Expect(suppressionsRepo.UpsertCall.Receives.Suppressions).To(Equal([]models.Suppression{
+				{
+					Address:    "missing-user@example.com",
+					Reason:     bounces.TypeBounce,
+					Status:     "5.1.1",
+					Diagnostic: "550 5.1.1 Recipient address rejected",
+				},
+			}))
+		})
+
+		Context("when the report cannot be parsed", func() {
+			It("returns the parse error", func() {
+				_, err := processor.Process(database, strings.NewReader("Content-Type: text/plain\n\nhello\n"))
+				Expect(err).To(BeAssignableToTypeOf(bounces.ParseError{}))
+				Expect(suppressionsRepo.UpsertCall.CallCount).To(Equal(0))
+			})
+		})
+
+		Context("when the suppression cannot be saved", func() {
+			It("returns the error", func() {
+				suppressionsRepo.UpsertCall.Returns.Error = errors.New("some database error")
+
+				_, err := processor.Process(database, strings.NewReader(bounceReport))
+				Expect(err).To(MatchError(errors.New("some database error")))
+			})
+		})
+	})
+
+	Describe("Unsuppress", func() {
+		It("deletes the suppression for the address", func() {
+			err := processor.Unsuppress(database, "missing-user@example.com")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressionsRepo.DeleteCall.Receives.Connection).To(Equal(conn))
+			Expect(suppressionsRepo.DeleteCall.Receives.Address).To(Equal("missing-user@example.com"))
+		})
+
+		It("returns the error from the repo", func() {
+			suppressionsRepo.DeleteCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			err := processor.Unsuppress(database, "missing-user@example.com")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+})
@@ -30,16 +30,21 @@ type DispatchVCAPRequest struct {
 }
 
 type DispatchMessage struct {
-	To      string
-	ReplyTo string
-	Subject string
-	Text    string
-	HTML    HTML
+	To            string
+	ReplyTo       string
+	Subject       string
+	Text          string
+	HTML          HTML
+	AttachmentIDs []string
+	ThreadKey     string
+	Headers       map[string]string
+	Data          map[string]interface{}
 }
 
 type DispatchClient struct {
 	ID          string
 	Description string
+	Transport   string
 }
 
 type DispatchKind struct {
@@ -43,6 +43,11 @@ func (strategy EmailStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 		Endorsement:       EmailEndorsement,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		AttachmentIDs:     dispatch.Message.AttachmentIDs,
+		ThreadKey:         dispatch.Message.ThreadKey,
+		Headers:           dispatch.Message.Headers,
+		Data:              dispatch.Message.Data,
+		Transport:         dispatch.Client.Transport,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
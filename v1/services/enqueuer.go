@@ -24,6 +24,11 @@ type Options struct {
 	Role              string
 	Endorsement       string
 	TemplateID        string
+	AttachmentIDs     []string
+	ThreadKey         string
+	Headers           map[string]string
+	Transport         string
+	Data              map[string]interface{}
 }
 
 type Delivery struct {
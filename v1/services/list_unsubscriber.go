@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pivotal-golang/conceal"
+)
+
+type InvalidUnsubscribeTokenError struct {
+	Err error
+}
+
+func (e InvalidUnsubscribeTokenError) Error() string {
+	return e.Err.Error()
+}
+
+type ListUnsubscriber struct {
+	cloak            conceal.CloakInterface
+	unsubscribesRepo UnsubscribesRepo
+	kindsRepo        KindsRepo
+}
+
+func NewListUnsubscriber(cloak conceal.CloakInterface, unsubscribesRepo UnsubscribesRepo, kindsRepo KindsRepo) ListUnsubscriber {
+	return ListUnsubscriber{
+		cloak:            cloak,
+		unsubscribesRepo: unsubscribesRepo,
+		kindsRepo:        kindsRepo,
+	}
+}
+
+func (u ListUnsubscriber) Unsubscribe(conn ConnectionInterface, token string) error {
+	plainText, err := u.cloak.Unveil([]byte(token))
+	if err != nil {
+		return InvalidUnsubscribeTokenError{errors.New("The unsubscribe token is invalid")}
+	}
+
+	parts := strings.Split(string(plainText), "|")
+	if len(parts) != 3 || parts[0] == "" {
+		return InvalidUnsubscribeTokenError{errors.New("The unsubscribe token is invalid")}
+	}
+
+	userID, clientID, kindID := parts[0], parts[1], parts[2]
+
+	kind, err := u.kindsRepo.Find(conn, kindID, clientID)
+	if err != nil {
+		return MissingKindOrClientError{fmt.Errorf("The kind '%s' cannot be found for client '%s'", kindID, clientID)}
+	}
+
+	if kind.Critical {
+		return CriticalKindError{fmt.Errorf("The kind '%s' for the '%s' client is critical and cannot be unsubscribed from", kindID, clientID)}
+	}
+
+	return u.unsubscribesRepo.Set(conn, userID, clientID, kindID, true)
+}
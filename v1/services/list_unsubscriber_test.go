@@ -0,0 +1,97 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListUnsubscriber", func() {
+	var (
+		cloak            *mocks.Cloak
+		unsubscribesRepo *mocks.UnsubscribesRepo
+		kindsRepo        *mocks.KindsRepo
+		conn             *mocks.Connection
+		unsubscriber     services.ListUnsubscriber
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+
+		cloak = mocks.NewCloak()
+		cloak.UnveilCall.Returns.PlainText = []byte("some-user|some-client|some-kind")
+
+		unsubscribesRepo = mocks.NewUnsubscribesRepo()
+
+		kindsRepo = mocks.NewKindsRepo()
+		kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+			{
+				ID:       "some-kind",
+				ClientID: "some-client",
+			},
+		}
+
+		unsubscriber = services.NewListUnsubscriber(cloak, unsubscribesRepo, kindsRepo)
+	})
+
+	It("unsubscribes the user from the kind encoded in the token", func() {
+		err := unsubscriber.Unsubscribe(conn, "some-token")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cloak.UnveilCall.Receives.CipherText).To(Equal([]byte("some-token")))
+
+		Expect(kindsRepo.FindCall.Receives.Connection).To(Equal(conn))
+		Expect(kindsRepo.FindCall.Receives.KindID).To(Equal("some-kind"))
+		Expect(kindsRepo.FindCall.Receives.ClientID).To(Equal("some-client"))
+
+		Expect(unsubscribesRepo.SetCall.Receives.Connection).To(Equal(conn))
+		Expect(unsubscribesRepo.SetCall.Receives.UserID).To(Equal("some-user"))
+		Expect(unsubscribesRepo.SetCall.Receives.ClientID).To(Equal("some-client"))
+		Expect(unsubscribesRepo.SetCall.Receives.KindID).To(Equal("some-kind"))
+		Expect(unsubscribesRepo.SetCall.Receives.Unsubscribe).To(BeTrue())
+	})
+
+	Context("when an error occurs", func() {
+		It("returns an invalid token error when the token cannot be decrypted", func() {
+			cloak.UnveilCall.Returns.Error = errors.New("bad cipher")
+
+			err := unsubscriber.Unsubscribe(conn, "some-token")
+			Expect(err).To(MatchError(services.InvalidUnsubscribeTokenError{errors.New("The unsubscribe token is invalid")}))
+		})
+
+		It("returns an invalid token error when the token does not identify a user", func() {
+			cloak.UnveilCall.Returns.PlainText = []byte("|some-client|some-kind")
+
+			err := unsubscriber.Unsubscribe(conn, "some-token")
+			Expect(err).To(MatchError(services.InvalidUnsubscribeTokenError{errors.New("The unsubscribe token is invalid")}))
+		})
+
+		It("returns a missing kind error when the kind cannot be found", func() {
+			kindsRepo.FindCall.Returns.Error = models.NotFoundError{errors.New("not found")}
+
+			err := unsubscriber.Unsubscribe(conn, "some-token")
+			Expect(err).To(BeAssignableToTypeOf(services.MissingKindOrClientError{}))
+			Expect(unsubscribesRepo.SetCall.Receives.UserID).To(BeEmpty())
+		})
+
+		It("returns a critical kind error when the kind is critical", func() {
+			kindsRepo.FindCall.Returns.Kinds[0].Critical = true
+
+			err := unsubscriber.Unsubscribe(conn, "some-token")
+			Expect(err).To(BeAssignableToTypeOf(services.CriticalKindError{}))
+			Expect(unsubscribesRepo.SetCall.Receives.UserID).To(BeEmpty())
+		})
+
+		It("returns the error when the unsubscribe cannot be saved", func() {
+			unsubscribesRepo.SetCall.Returns.Error = errors.New("some database error")
+
+			err := unsubscriber.Unsubscribe(conn, "some-token")
+			Expect(err).To(MatchError(errors.New("some database error")))
+		})
+	})
+})
@@ -43,6 +43,11 @@ func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) ([]Response, er
 		Endorsement:       OrganizationEndorsement,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		AttachmentIDs:     dispatch.Message.AttachmentIDs,
+		ThreadKey:         dispatch.Message.ThreadKey,
+		Headers:           dispatch.Message.Headers,
+		Data:              dispatch.Message.Data,
+		Transport:         dispatch.Client.Transport,
 		Role:              dispatch.Role,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
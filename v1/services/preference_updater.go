@@ -3,6 +3,7 @@ package services
 import (
 	"fmt"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
 
@@ -10,13 +11,15 @@ type PreferenceUpdater struct {
 	globalUnsubscribesRepo GlobalUnsubscribesRepo
 	unsubscribesRepo       UnsubscribesRepo
 	kindsRepo              KindsRepo
+	userLocalesRepo        UserLocalesRepo
 }
 
-func NewPreferenceUpdater(globalUnsubscribesRepo GlobalUnsubscribesRepo, unsubscribesRepo UnsubscribesRepo, kindsRepo KindsRepo) PreferenceUpdater {
+func NewPreferenceUpdater(globalUnsubscribesRepo GlobalUnsubscribesRepo, unsubscribesRepo UnsubscribesRepo, kindsRepo KindsRepo, userLocalesRepo UserLocalesRepo) PreferenceUpdater {
 	return PreferenceUpdater{
 		globalUnsubscribesRepo: globalUnsubscribesRepo,
 		unsubscribesRepo:       unsubscribesRepo,
 		kindsRepo:              kindsRepo,
+		userLocalesRepo:        userLocalesRepo,
 	}
 }
 
@@ -43,3 +46,9 @@ func (updater PreferenceUpdater) Update(conn ConnectionInterface, preferences []
 	}
 	return nil
 }
+
+// SetLocale records the locale the user would like to receive notifications
+// in. An empty locale clears the preference.
+func (updater PreferenceUpdater) SetLocale(conn ConnectionInterface, userID, locale string) error {
+	return updater.userLocalesRepo.Set(conn, userID, common.NormalizeLocale(locale))
+}
@@ -28,7 +28,7 @@ var _ = Describe("PreferenceUpdater", func() {
 			unsubscribesRepo = mocks.NewUnsubscribesRepo()
 			kindsRepo = mocks.NewKindsRepo()
 			fakeGlobalUnsubscribesRepo = mocks.NewGlobalUnsubscribesRepo()
-			updater = services.NewPreferenceUpdater(fakeGlobalUnsubscribesRepo, unsubscribesRepo, kindsRepo)
+			updater = services.NewPreferenceUpdater(fakeGlobalUnsubscribesRepo, unsubscribesRepo, kindsRepo, mocks.NewUserLocalesRepo())
 		})
 
 		Context("when globally unsubscribing", func() {
@@ -218,4 +218,34 @@ var _ = Describe("PreferenceUpdater", func() {
 			})
 		})
 	})
+
+	Describe("SetLocale", func() {
+		var (
+			userLocalesRepo *mocks.UserLocalesRepo
+			conn            *mocks.Connection
+			updater         services.PreferenceUpdater
+		)
+
+		BeforeEach(func() {
+			conn = mocks.NewConnection()
+			userLocalesRepo = mocks.NewUserLocalesRepo()
+			updater = services.NewPreferenceUpdater(mocks.NewGlobalUnsubscribesRepo(), mocks.NewUnsubscribesRepo(), mocks.NewKindsRepo(), userLocalesRepo)
+		})
+
+		It("stores the normalized locale for the user", func() {
+			err := updater.SetLocale(conn, "the-user", "pt_BR")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(userLocalesRepo.SetCall.Receives.Connection).To(Equal(conn))
+			Expect(userLocalesRepo.SetCall.Receives.UserID).To(Equal("the-user"))
+			Expect(userLocalesRepo.SetCall.Receives.Locale).To(Equal("pt-br"))
+		})
+
+		It("returns the error when the repo fails", func() {
+			userLocalesRepo.SetCall.Returns.Error = errors.New("locale db error")
+
+			err := updater.SetLocale(conn, "the-user", "fr")
+			Expect(err).To(MatchError(errors.New("locale db error")))
+		})
+	})
 })
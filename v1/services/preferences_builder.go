@@ -17,6 +17,7 @@ type ClientsMap map[string]ClientMap
 
 type PreferencesBuilder struct {
 	GlobalUnsubscribe bool       `json:"global_unsubscribe"`
+	Locale            *string    `json:"locale,omitempty"`
 	Clients           ClientsMap `json:"clients"`
 }
 
@@ -3,12 +3,14 @@ package services
 type PreferencesFinder struct {
 	preferencesRepo        PreferencesRepo
 	globalUnsubscribesRepo GlobalUnsubscribesRepo
+	userLocalesRepo        UserLocalesRepo
 }
 
-func NewPreferencesFinder(preferencesRepo PreferencesRepo, globalUnsubscribesRepo GlobalUnsubscribesRepo) *PreferencesFinder {
+func NewPreferencesFinder(preferencesRepo PreferencesRepo, globalUnsubscribesRepo GlobalUnsubscribesRepo, userLocalesRepo UserLocalesRepo) *PreferencesFinder {
 	return &PreferencesFinder{
 		preferencesRepo:        preferencesRepo,
 		globalUnsubscribesRepo: globalUnsubscribesRepo,
+		userLocalesRepo:        userLocalesRepo,
 	}
 }
 
@@ -26,7 +28,16 @@ func (finder PreferencesFinder) Find(database DatabaseInterface, userGUID string
 		return builder, err
 	}
 
+	locale, err := finder.userLocalesRepo.Get(conn, userGUID)
+	if err != nil {
+		return builder, err
+	}
+
 	builder.GlobalUnsubscribe = globallyUnsubscribed
+	if locale != "" {
+		builder.Locale = &locale
+	}
+
 	for _, preference := range preferences {
 		builder.Add(preference)
 	}
@@ -15,6 +15,7 @@ var _ = Describe("PreferencesFinder", func() {
 	var (
 		finder          *services.PreferencesFinder
 		preferencesRepo *mocks.PreferencesRepo
+		userLocalesRepo *mocks.UserLocalesRepo
 		preferences     []models.Preference
 		database        *mocks.Database
 		conn            *mocks.Connection
@@ -48,7 +49,9 @@ var _ = Describe("PreferencesFinder", func() {
 		database = mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 
-		finder = services.NewPreferencesFinder(preferencesRepo, fakeGlobalUnsubscribesRepo)
+		userLocalesRepo = mocks.NewUserLocalesRepo()
+
+		finder = services.NewPreferencesFinder(preferencesRepo, fakeGlobalUnsubscribesRepo, userLocalesRepo)
 	})
 
 	Describe("Find", func() {
@@ -66,6 +69,27 @@ var _ = Describe("PreferencesFinder", func() {
 			Expect(preferencesRepo.FindNonCriticalPreferencesCall.Receives.UserGUID).To(Equal("correct-user"))
 		})
 
+		It("includes the locale preference when the user has one", func() {
+			userLocalesRepo.GetCall.Returns.Locale = "pt-br"
+
+			resultPreferences, err := finder.Find(database, "correct-user")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resultPreferences.Locale).NotTo(BeNil())
+			Expect(*resultPreferences.Locale).To(Equal("pt-br"))
+
+			Expect(userLocalesRepo.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(userLocalesRepo.GetCall.Receives.UserID).To(Equal("correct-user"))
+		})
+
+		Context("when the user locales repo returns an error", func() {
+			It("should propagate the error", func() {
+				userLocalesRepo.GetCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := finder.Find(database, "correct-user")
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
+		})
+
 		Context("when the preferences repo returns an error", func() {
 			It("should propagate the error", func() {
 				preferencesRepo.FindNonCriticalPreferencesCall.Returns.Error = errors.New("BOOM!")
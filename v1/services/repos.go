@@ -27,10 +27,15 @@ type TemplatesRepo interface {
 	Create(connection models.ConnectionInterface, template models.Template) (models.Template, error)
 	Destroy(connection models.ConnectionInterface, templateID string) error
 	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
+	FindLayouts(connection models.ConnectionInterface, template models.Template) ([]models.Template, error)
 	ListIDsAndNames(connection models.ConnectionInterface) ([]models.Template, error)
 	Update(connection models.ConnectionInterface, templateID string, template models.Template) (models.Template, error)
 }
 
+type TemplateVersionsRepo interface {
+	Create(connection models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error)
+}
+
 type UnsubscribesRepo interface {
 	Set(connection models.ConnectionInterface, userID string, clientID string, kindID string, unsubscribe bool) error
 }
@@ -39,3 +44,13 @@ type GlobalUnsubscribesRepo interface {
 	Get(connection models.ConnectionInterface, userGUID string) (bool, error)
 	Set(connection models.ConnectionInterface, userGUID string, unsubscribe bool) error
 }
+
+type UserLocalesRepo interface {
+	Get(connection models.ConnectionInterface, userID string) (string, error)
+	Set(connection models.ConnectionInterface, userID, locale string) error
+}
+
+type SuppressionsRepo interface {
+	Upsert(connection models.ConnectionInterface, suppression models.Suppression) (models.Suppression, error)
+	Delete(connection models.ConnectionInterface, address string) error
+}
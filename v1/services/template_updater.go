@@ -1,21 +1,65 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/v1/models"
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
 
 type TemplateUpdater struct {
-	templatesRepo TemplatesRepo
+	templatesRepo        TemplatesRepo
+	templateVersionsRepo TemplateVersionsRepo
 }
 
-func NewTemplateUpdater(templatesRepo TemplatesRepo) TemplateUpdater {
+func NewTemplateUpdater(templatesRepo TemplatesRepo, templateVersionsRepo TemplateVersionsRepo) TemplateUpdater {
 	return TemplateUpdater{
-		templatesRepo: templatesRepo,
+		templatesRepo:        templatesRepo,
+		templateVersionsRepo: templateVersionsRepo,
 	}
 }
 
+// Update stores the template and records it as a new version in a single
+// transaction, holding the template row lock until the version is recorded.
 func (updater TemplateUpdater) Update(database DatabaseInterface, templateID string, template models.Template) error {
-	_, err := updater.templatesRepo.Update(database.Connection(), templateID, template)
+	transaction := database.Connection().Transaction()
+	if err := transaction.Begin(); err != nil {
+		return err
+	}
+
+	err := updater.update(transaction, templateID, template)
 	if err != nil {
+		transaction.Rollback()
 		return err
 	}
+
+	return transaction.Commit()
+}
+
+func (updater TemplateUpdater) update(connection models.ConnectionInterface, templateID string, template models.Template) error {
+	layouts, err := updater.templatesRepo.FindLayouts(connection, models.Template{
+		ID:       templateID,
+		LayoutID: template.LayoutID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, layout := range layouts {
+		if !common.IncludesLayoutContent(layout.Text) && !common.IncludesLayoutContent(layout.HTML) {
+			return models.TemplateLayoutError{Err: fmt.Errorf("Layout %q does not include {{template \"content\" .}}", layout.ID)}
+		}
+	}
+
+	updatedTemplate, err := updater.templatesRepo.Update(connection, templateID, template)
+	if err != nil {
+		return err
+	}
+
+	_, err = updater.templateVersionsRepo.Create(connection, models.NewTemplateVersion(updatedTemplate))
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -14,19 +14,24 @@ import (
 var _ = Describe("Updater", func() {
 	Describe("Update", func() {
 		var (
-			conn          *mocks.Connection
-			database      *mocks.Database
-			templatesRepo *mocks.TemplatesRepo
-			updater       services.TemplateUpdater
+			conn                 *mocks.Connection
+			transaction          *mocks.Transaction
+			database             *mocks.Database
+			templatesRepo        *mocks.TemplatesRepo
+			templateVersionsRepo *mocks.TemplateVersionsRepo
+			updater              services.TemplateUpdater
 		)
 
 		BeforeEach(func() {
+			transaction = mocks.NewTransaction()
 			conn = mocks.NewConnection()
+			conn.TransactionCall.Returns.Transaction = transaction
 			database = mocks.NewDatabase()
 			database.ConnectionCall.Returns.Connection = conn
 			templatesRepo = mocks.NewTemplatesRepo()
+			templateVersionsRepo = mocks.NewTemplateVersionsRepo()
 
-			updater = services.NewTemplateUpdater(templatesRepo)
+			updater = services.NewTemplateUpdater(templatesRepo, templateVersionsRepo)
 		})
 
 		It("Inserts templates into the templates repo", func() {
@@ -37,7 +42,7 @@ var _ = Describe("Updater", func() {
 			})
 			Expect(err).ToNot(HaveOccurred())
 
-			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("my-awesome-id"))
 			Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{
 				Name: "gobble template",
@@ -46,11 +51,108 @@ var _ = Describe("Updater", func() {
 			}))
 		})
 
+		It("records the updated template as a new version", func() {
+			templatesRepo.UpdateCall.Returns.Template = models.Template{
+				ID:       "my-awesome-id",
+				Name:     "gobble template",
+				Subject:  "gobble subject",
+				Text:     "gobble",
+				HTML:     "<p>gobble</p>",
+				Metadata: "{}",
+				Version:  3,
+			}
+
+			err := updater.Update(database, "my-awesome-id", models.Template{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(templateVersionsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(templateVersionsRepo.CreateCall.Receives.TemplateVersion).To(Equal(models.TemplateVersion{
+				TemplateID: "my-awesome-id",
+				Version:    3,
+				Name:       "gobble template",
+				Subject:    "gobble subject",
+				Text:       "gobble",
+				HTML:       "<p>gobble</p>",
+				Metadata:   "{}",
+			}))
+		})
+
+		It("updates the template and records the version in one transaction", func() {
+			err := updater.Update(database, "my-awesome-id", models.Template{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+		})
+
+		It("rolls the transaction back when the version cannot be recorded", func() {
+			templateVersionsRepo.CreateCall.Returns.Error = errors.New("versions boom")
+
+			err := updater.Update(database, "my-awesome-id", models.Template{})
+			Expect(err).To(MatchError(errors.New("versions boom")))
+
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("propagates errors beginning the transaction", func() {
+			transaction.BeginCall.Returns.Error = errors.New("begin failed")
+
+			err := updater.Update(database, "my-awesome-id", models.Template{})
+			Expect(err).To(MatchError(errors.New("begin failed")))
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
+		})
+
+		It("propagates errors from the versions repo", func() {
+			templateVersionsRepo.CreateCall.Returns.Error = errors.New("versions boom")
+
+			err := updater.Update(database, "unimportant", models.Template{})
+			Expect(err).To(MatchError(errors.New("versions boom")))
+		})
+
 		It("propagates errors from repo", func() {
 			templatesRepo.UpdateCall.Returns.Error = errors.New("Boom!")
 
 			err := updater.Update(database, "unimportant", models.Template{})
 			Expect(err).To(MatchError(errors.New("Boom!")))
+			Expect(templateVersionsRepo.CreateCall.CallCount).To(Equal(0))
+		})
+
+		Context("when the template declares a layout", func() {
+			It("checks the chain of layouts wrapping the template", func() {
+				templatesRepo.FindLayoutsCall.Returns.Layouts = []models.Template{
+					{ID: "some-layout-id", Text: `{{template "content" .}}`},
+				}
+
+				err := updater.Update(database, "my-awesome-id", models.Template{LayoutID: "some-layout-id"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(templatesRepo.FindLayoutsCall.Receives.Connection).To(Equal(transaction))
+				Expect(templatesRepo.FindLayoutsCall.Receives.Template).To(Equal(models.Template{
+					ID:       "my-awesome-id",
+					LayoutID: "some-layout-id",
+				}))
+				Expect(templatesRepo.UpdateCall.Receives.Template.LayoutID).To(Equal("some-layout-id"))
+			})
+
+			It("returns a layout error when a layout does not include the content it wraps", func() {
+				templatesRepo.FindLayoutsCall.Returns.Layouts = []models.Template{
+					{ID: "some-layout-id", Text: "no content"},
+				}
+
+				err := updater.Update(database, "my-awesome-id", models.Template{LayoutID: "some-layout-id"})
+				Expect(err).To(MatchError(models.TemplateLayoutError{Err: errors.New(`Layout "some-layout-id" does not include {{template "content" .}}`)}))
+				Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
+			})
+
+			It("propagates errors finding the layouts", func() {
+				templatesRepo.FindLayoutsCall.Returns.Error = models.TemplateLayoutError{Err: errors.New(`Layout "some-layout-id" creates a circular reference`)}
+
+				err := updater.Update(database, "my-awesome-id", models.Template{LayoutID: "some-layout-id"})
+				Expect(err).To(MatchError(models.TemplateLayoutError{Err: errors.New(`Layout "some-layout-id" creates a circular reference`)}))
+				Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(BeEmpty())
+			})
 		})
 	})
 })
@@ -36,6 +36,11 @@ func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		SourceDescription: dispatch.Client.Description,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		AttachmentIDs:     dispatch.Message.AttachmentIDs,
+		ThreadKey:         dispatch.Message.ThreadKey,
+		Headers:           dispatch.Message.Headers,
+		Data:              dispatch.Message.Data,
+		Transport:         dispatch.Client.Transport,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -44,6 +44,10 @@ var _ = Describe("UserStrategy", func() {
 							Head:           "<head></head>",
 							Doctype:        "<html>",
 						},
+						AttachmentIDs: []string{"some-attachment-id"},
+						ThreadKey:     "some-thread-key",
+						Headers:       map[string]string{"X-Tracking-ID": "some-tracking-id"},
+						Data:          map[string]interface{}{"bottle": "blue"},
 					},
 					TemplateID: "some-template-id",
 					UAAHost:    "uaa",
@@ -54,6 +58,7 @@ var _ = Describe("UserStrategy", func() {
 					Client: services.DispatchClient{
 						ID:          "mister-client",
 						Description: "The Water Bottle System",
+						Transport:   "billing",
 					},
 					VCAPRequest: services.DispatchVCAPRequest{
 						ID:          "some-vcap-request-id",
@@ -75,6 +80,11 @@ var _ = Describe("UserStrategy", func() {
 					SourceDescription: "The Water Bottle System",
 					Text:              "Please make sure to leave your bottle in a place that is safe and dry",
 					TemplateID:        "some-template-id",
+					AttachmentIDs:     []string{"some-attachment-id"},
+					ThreadKey:         "some-thread-key",
+					Headers:           map[string]string{"X-Tracking-ID": "some-tracking-id"},
+					Data:              map[string]interface{}{"bottle": "blue"},
+					Transport:         "billing",
 					HTML: services.HTML{
 						BodyContent:    "<p>The water bottle needs to be safe and dry</p>",
 						BodyAttributes: "some-html-body-attributes",
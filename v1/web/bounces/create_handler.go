@@ -0,0 +1,54 @@
+package bounces
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/bounces"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type errorWriter interface {
+	Write(writer http.ResponseWriter, err error)
+}
+
+type bounceProcessor interface {
+	Process(database services.DatabaseInterface, report io.Reader) ([]string, error)
+}
+
+type CreateHandler struct {
+	processor   bounceProcessor
+	errorWriter errorWriter
+}
+
+func NewCreateHandler(processor bounceProcessor, errWriter errorWriter) CreateHandler {
+	return CreateHandler{
+		processor:   processor,
+		errorWriter: errWriter,
+	}
+}
+
+func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	suppressed, err := h.processor.Process(context.Get("database").(DatabaseInterface), req.Body)
+	if err != nil {
+		if _, ok := err.(bounces.ParseError); ok {
+			err = webutil.ValidationError{Err: err}
+		}
+
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output, err := json.Marshal(map[string][]string{
+		"suppressed": suppressed,
+	})
+	if err != nil {
+		panic(err) // No JSON we write into a response should ever panic
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(output)
+}
@@ -0,0 +1,78 @@
+package bounces_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	bouncesparser "github.com/cloudfoundry-incubator/notifications/bounces"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/bounces"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreateHandler", func() {
+	var (
+		handler     bounces.CreateHandler
+		processor   *mocks.BounceProcessor
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		processor = mocks.NewBounceProcessor()
+		processor.ProcessCall.Returns.Suppressed = []string{"missing-user@example.com"}
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		var err error
+		request, err = http.NewRequest("POST", "/bounces", strings.NewReader("some-raw-report"))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = bounces.NewCreateHandler(processor, errorWriter)
+	})
+
+	It("processes the report in the request body", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(processor.ProcessCall.Receives.Database).To(Equal(database))
+		Expect(processor.ProcessCall.Receives.Report).To(Equal("some-raw-report"))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"suppressed": ["missing-user@example.com"]
+		}`))
+	})
+
+	Context("when the report cannot be parsed", func() {
+		It("writes a validation error", func() {
+			processor.ProcessCall.Returns.Error = bouncesparser.ParseError{Err: errors.New("not a report")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: bouncesparser.ParseError{Err: errors.New("not a report")}}))
+		})
+	})
+
+	Context("when processing fails", func() {
+		It("delegates to the error writer", func() {
+			processor.ProcessCall.Returns.Error = errors.New("some database error")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("some database error")))
+		})
+	})
+})
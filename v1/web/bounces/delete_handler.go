@@ -0,0 +1,37 @@
+package bounces
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/ryanmoran/stack"
+)
+
+type unsuppressor interface {
+	Unsuppress(database services.DatabaseInterface, address string) error
+}
+
+type DeleteHandler struct {
+	unsuppressor unsuppressor
+	errorWriter  errorWriter
+}
+
+func NewDeleteHandler(unsuppressor unsuppressor, errWriter errorWriter) DeleteHandler {
+	return DeleteHandler{
+		unsuppressor: unsuppressor,
+		errorWriter:  errWriter,
+	}
+}
+
+func (h DeleteHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	address := strings.Split(req.URL.Path, "/suppressions/")[1]
+
+	err := h.unsuppressor.Unsuppress(context.Get("database").(DatabaseInterface), address)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
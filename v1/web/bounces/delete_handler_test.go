@@ -0,0 +1,61 @@
+package bounces_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/bounces"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteHandler", func() {
+	var (
+		handler      bounces.DeleteHandler
+		unsuppressor *mocks.BounceProcessor
+		errorWriter  *mocks.ErrorWriter
+		writer       *httptest.ResponseRecorder
+		request      *http.Request
+		database     *mocks.Database
+		context      stack.Context
+	)
+
+	BeforeEach(func() {
+		unsuppressor = mocks.NewBounceProcessor()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/suppressions/missing-user@example.com", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = bounces.NewDeleteHandler(unsuppressor, errorWriter)
+	})
+
+	It("removes the suppression for the address", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(unsuppressor.UnsuppressCall.Receives.Database).To(Equal(database))
+		Expect(unsuppressor.UnsuppressCall.Receives.Address).To(Equal("missing-user@example.com"))
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+	})
+
+	Context("when the address is not suppressed", func() {
+		It("delegates to the error writer", func() {
+			unsuppressor.UnsuppressCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+})
@@ -0,0 +1,23 @@
+package bounces
+
+import "github.com/ryanmoran/stack"
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type Routes struct {
+	RequestCounter                   stack.Middleware
+	RequestLogging                   stack.Middleware
+	NotificationsManageAuthenticator stack.Middleware
+	DatabaseAllocator                stack.Middleware
+
+	BounceProcessor bounceProcessor
+	Unsuppressor    unsuppressor
+	ErrorWriter     errorWriter
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("POST", "/bounces", NewCreateHandler(r.BounceProcessor, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/suppressions/{address}", NewDeleteHandler(r.Unsuppressor, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+}
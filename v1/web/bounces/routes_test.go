@@ -0,0 +1,57 @@
+package bounces_test
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/bounces"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/web"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Routes", func() {
+	var muxer web.Muxer
+
+	BeforeEach(func() {
+		muxer = web.NewMuxer()
+		bounces.Routes{
+			RequestCounter:                   middleware.RequestCounter{},
+			RequestLogging:                   middleware.RequestLogging{},
+			DatabaseAllocator:                middleware.DatabaseAllocator{},
+			NotificationsManageAuthenticator: middleware.Authenticator{Scopes: []string{"notifications.manage"}},
+
+			ErrorWriter:     mocks.NewErrorWriter(),
+			BounceProcessor: mocks.NewBounceProcessor(),
+			Unsuppressor:    mocks.NewBounceProcessor(),
+		}.Register(muxer)
+	})
+
+	It("routes POST /bounces", func() {
+		request, err := http.NewRequest("POST", "/bounces", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(bounces.CreateHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.manage"}))
+	})
+
+	It("routes DELETE /suppressions/{address}", func() {
+		request, err := http.NewRequest("DELETE", "/suppressions/user@example.com", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(bounces.DeleteHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.manage"}))
+	})
+})
@@ -0,0 +1,52 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type assignsTransports interface {
+	AssignToClient(connection collections.ConnectionInterface, clientID, transport string) error
+}
+
+type AssignTransportHandler struct {
+	transportAssigner assignsTransports
+	errorWriter       errorWriter
+}
+
+func NewAssignTransportHandler(assigner assignsTransports, errWriter errorWriter) AssignTransportHandler {
+	return AssignTransportHandler{
+		transportAssigner: assigner,
+		errorWriter:       errWriter,
+	}
+}
+
+type TransportAssignment struct {
+	Transport string `json:"transport"`
+}
+
+func (h AssignTransportHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	routeRegex := regexp.MustCompile("/clients/(.*)/transport")
+	clientID := routeRegex.FindStringSubmatch(req.URL.Path)[1]
+
+	var transportAssignment TransportAssignment
+	err := json.NewDecoder(req.Body).Decode(&transportAssignment)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ParseError{})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	err = h.transportAssigner.AssignToClient(database.Connection(), clientID, transportAssignment.Transport)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -12,10 +12,12 @@ type Routes struct {
 	NotificationsManageAuthenticator stack.Middleware
 	DatabaseAllocator                stack.Middleware
 
-	ErrorWriter      errorWriter
-	TemplateAssigner assignsTemplates
+	ErrorWriter       errorWriter
+	TemplateAssigner  assignsTemplates
+	TransportAssigner assignsTransports
 }
 
 func (r Routes) Register(m muxer) {
 	m.Handle("PUT", "/clients/{client_id}/template", NewAssignTemplateHandler(r.TemplateAssigner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/clients/{client_id}/transport", NewAssignTransportHandler(r.TransportAssigner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
 }
@@ -25,8 +25,9 @@ var _ = Describe("Routes", func() {
 			DatabaseAllocator:                middleware.DatabaseAllocator{},
 			NotificationsManageAuthenticator: middleware.Authenticator{Scopes: []string{"notifications.manage"}},
 
-			ErrorWriter:      mocks.NewErrorWriter(),
-			TemplateAssigner: mocks.NewTemplateAssigner(),
+			ErrorWriter:       mocks.NewErrorWriter(),
+			TemplateAssigner:  mocks.NewTemplateAssigner(),
+			TransportAssigner: mocks.NewTransportAssigner(),
 		}.Register(muxer)
 	})
 
@@ -41,4 +42,16 @@ var _ = Describe("Routes", func() {
 		authenticator := s.Middleware[2].(middleware.Authenticator)
 		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
 	})
+
+	It("routes PUT /clients/{client_id}/transport", func() {
+		request, err := http.NewRequest("PUT", "/clients/some-client-id/transport", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(clients.AssignTransportHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
 })
@@ -0,0 +1,43 @@
+package messages
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ryanmoran/stack"
+)
+
+type LookupHandler struct {
+	finder      messageFinder
+	errorWriter errorWriter
+}
+
+func NewLookupHandler(finder messageFinder, errWriter errorWriter) LookupHandler {
+	return LookupHandler{
+		finder:      finder,
+		errorWriter: errWriter,
+	}
+}
+
+func (h LookupHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	messageID := strings.Split(req.URL.Path, "/message_ids/")[1]
+	messageID = strings.Trim(messageID, "<>")
+	if index := strings.LastIndex(messageID, "@"); index >= 0 {
+		messageID = messageID[:index]
+	}
+
+	message, err := h.finder.Find(context.Get("database").(DatabaseInterface), messageID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	var document struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	document.ID = messageID
+	document.Status = message.Status
+
+	writeJSON(w, http.StatusOK, document)
+}
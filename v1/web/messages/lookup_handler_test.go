@@ -0,0 +1,84 @@
+package messages_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/messages"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LookupHandler", func() {
+	var (
+		handler       messages.LookupHandler
+		errorWriter   *mocks.ErrorWriter
+		writer        *httptest.ResponseRecorder
+		request       *http.Request
+		err           error
+		messageFinder *mocks.MessageFinder
+		database      *mocks.Database
+		context       stack.Context
+	)
+
+	BeforeEach(func() {
+		errorWriter = mocks.NewErrorWriter()
+		messageFinder = mocks.NewMessageFinder()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		request, err = http.NewRequest("GET", "/message_ids/"+url.QueryEscape("<message-123@example.com>"), nil)
+		if err != nil {
+			panic(err)
+		}
+
+		handler = messages.NewLookupHandler(messageFinder, errorWriter)
+	})
+
+	Describe("ServeHTTP", func() {
+		It("returns the message record for the given Message-ID", func() {
+			messageFinder.FindCall.Returns.Message = services.Message{
+				Status: "delivered",
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.Bytes()).To(MatchJSON(`{
+				"id": "message-123",
+				"status": "delivered"
+			}`))
+
+			Expect(messageFinder.FindCall.Receives.Database).To(Equal(database))
+			Expect(messageFinder.FindCall.Receives.MessageID).To(Equal("message-123"))
+		})
+
+		It("accepts a Message-ID without angle brackets", func() {
+			request, err = http.NewRequest("GET", "/message_ids/message-123@example.com", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(messageFinder.FindCall.Receives.MessageID).To(Equal("message-123"))
+		})
+
+		Context("when the finder errors", func() {
+			It("delegates to the error writer", func() {
+				findError := errors.New("The finder returns a generic error")
+				messageFinder.FindCall.Returns.Error = findError
+
+				handler.ServeHTTP(writer, request, context)
+				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(findError))
+			})
+		})
+	})
+})
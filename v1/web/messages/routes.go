@@ -18,4 +18,5 @@ type Routes struct {
 
 func (r Routes) Register(m muxer) {
 	m.Handle("GET", "/messages/{message_id}", NewGetHandler(r.MessageFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/message_ids/{message_id}", NewLookupHandler(r.MessageFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteAuthenticator, r.DatabaseAllocator)
 }
@@ -41,4 +41,16 @@ var _ = Describe("Routes", func() {
 		authenticator := s.Middleware[2].(middleware.Authenticator)
 		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write"}))
 	})
+
+	It("routes GET /message_ids/{message_id}", func() {
+		request, err := http.NewRequest("GET", "/message_ids/some-message-id@example.com", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(messages.LookupHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write"}))
+	})
 })
@@ -20,6 +20,7 @@ type NotificationStruct struct {
 	ID          string
 	Description string `json:"description"`
 	Critical    bool   `json:"critical"`
+	Threading   bool   `json:"threading"`
 }
 
 func NewClientRegistrationParams(body io.Reader) (ClientRegistrationParams, error) {
@@ -67,7 +68,7 @@ func strictValidateJSON(bytes []byte) error {
 				}
 				notificationMap := notificationData.(map[string]interface{})
 				for propertyName := range notificationMap {
-					if propertyName == "description" || propertyName == "critical" {
+					if propertyName == "description" || propertyName == "critical" || propertyName == "threading" {
 						continue
 					} else {
 						return webutil.SchemaError{fmt.Errorf("%q is not a valid property", propertyName)}
@@ -25,6 +25,7 @@ var _ = Describe("ClientRegistrationParams", func() {
 					},
 					"feeding_time": map[string]interface{}{
 						"description": "Feeding Time",
+						"threading":   true,
 					},
 				},
 			})
@@ -44,6 +45,7 @@ var _ = Describe("ClientRegistrationParams", func() {
 				ID:          "feeding_time",
 				Description: "Feeding Time",
 				Critical:    false,
+				Threading:   true,
 			}))
 		})
 
@@ -25,6 +25,7 @@ type Notification struct {
 	Description string `json:"description"`
 	Template    string `json:"template"`
 	Critical    bool   `json:"critical"`
+	Threading   bool   `json:"threading"`
 }
 
 type ListHandler struct {
@@ -67,6 +68,7 @@ func (h ListHandler) constructNotifications(clients []models.Client, notificatio
 					Description: notification.Description,
 					Template:    notification.TemplateToUse(),
 					Critical:    notification.Critical,
+					Threading:   notification.Threading,
 				}
 			}
 		}
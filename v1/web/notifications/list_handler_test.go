@@ -76,6 +76,7 @@ var _ = Describe("ListHandler", func() {
 					ID:          "fence-works",
 					Description: "even better",
 					Critical:    true,
+					Threading:   true,
 					ClientID:    "client-456",
 				},
 			}
@@ -92,12 +93,14 @@ var _ = Describe("ListHandler", func() {
 						"perimeter-breach": {
 							"description": "very bad",
 							"template": "default",
-							"critical": true
+							"critical": true,
+							"threading": false
 						},
 						"fence-broken": {
 							"description": "even worse",
 							"template": "default",
-							"critical": true
+							"critical": true,
+							"threading": false
 						}
 					}
 				},
@@ -108,12 +111,14 @@ var _ = Describe("ListHandler", func() {
 						"perimeter-is-good": {
 							"description": "very good",
 							"template": "default",
-							"critical": false
+							"critical": false,
+							"threading": false
 						},
 						"fence-works": {
 							"description": "even better",
 							"template": "default",
-							"critical": true
+							"critical": true,
+							"threading": true
 						}
 					}
 				}
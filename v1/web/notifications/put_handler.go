@@ -50,6 +50,7 @@ func (h PutHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 			ID:          notification.ID,
 			Description: notification.Description,
 			Critical:    notification.Critical,
+			Threading:   notification.Threading,
 			TemplateID:  models.DoNotSetTemplateID,
 		})
 	}
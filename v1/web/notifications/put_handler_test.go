@@ -56,6 +56,7 @@ var _ = Describe("PutHandler", func() {
 				},
 				"feeding_time": map[string]interface{}{
 					"description": "Feeding Time",
+					"threading":   true,
 				},
 			},
 		})
@@ -99,6 +100,7 @@ var _ = Describe("PutHandler", func() {
 			{
 				ID:          "feeding_time",
 				Description: "Feeding Time",
+				Threading:   true,
 				ClientID:    client.ID,
 			},
 		}
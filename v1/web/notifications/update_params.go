@@ -11,6 +11,7 @@ import (
 type NotificationUpdateParams struct {
 	Description string `json:"description" validate-required:"true"`
 	Critical    bool   `json:"critical"    validate-required:"true"`
+	Threading   bool   `json:"threading"`
 	TemplateID  string `json:"template"    validate-required:"true"`
 }
 
@@ -34,6 +35,7 @@ func (params NotificationUpdateParams) ToModel(clientID, notificationID string)
 	return models.Kind{
 		Description: params.Description,
 		Critical:    params.Critical,
+		Threading:   params.Threading,
 		TemplateID:  params.TemplateID,
 		ClientID:    clientID,
 		ID:          notificationID,
@@ -42,13 +42,14 @@ var _ = Describe("Notification", func() {
 
 	Describe("ToModel", func() {
 		It("returns a model.Kind composed of the NotificationUpdateParams", func() {
-			body := strings.NewReader(`{"description":"my awesome notification", "critical":true, "template":"my-awesome-template"}`)
+			body := strings.NewReader(`{"description":"my awesome notification", "critical":true, "threading":true, "template":"my-awesome-template"}`)
 			updateParams, err := notifications.NewNotificationParams(body)
 			Expect(err).NotTo(HaveOccurred())
 
 			notification := updateParams.ToModel("client-id", "notification-id")
 			Expect(notification.Description).To(Equal("my awesome notification"))
 			Expect(notification.Critical).To(Equal(true))
+			Expect(notification.Threading).To(Equal(true))
 			Expect(notification.TemplateID).To(Equal("my-awesome-template"))
 			Expect(notification.ClientID).To(Equal("client-id"))
 			Expect(notification.ID).To(Equal("notification-id"))
@@ -3,11 +3,14 @@ package notify
 import (
 	"encoding/json"
 	"errors"
+	"mime"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
@@ -24,15 +27,27 @@ type registrar interface {
 	Prune(services.ConnectionInterface, models.Client, []models.Kind) error
 }
 
+type attachmentCreator interface {
+	Create(models.ConnectionInterface, models.Attachment) (models.Attachment, error)
+}
+
+type templateFinder interface {
+	FindByID(services.DatabaseInterface, string) (models.Template, error)
+}
+
 type Notify struct {
-	finder    clientAndKindFinder
-	registrar registrar
+	finder          clientAndKindFinder
+	registrar       registrar
+	attachmentsRepo attachmentCreator
+	templateFinder  templateFinder
 }
 
-func NewNotify(finder clientAndKindFinder, registrar registrar) Notify {
+func NewNotify(finder clientAndKindFinder, registrar registrar, attachmentsRepo attachmentCreator, templateFinder templateFinder) Notify {
 	return Notify{
-		finder:    finder,
-		registrar: registrar,
+		finder:          finder,
+		registrar:       registrar,
+		attachmentsRepo: attachmentsRepo,
+		templateFinder:  templateFinder,
 	}
 }
 
@@ -65,7 +80,9 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 	}
 	uaaHost := tokenIssuerURL.Scheme + "://" + tokenIssuerURL.Host
 
-	client, kind, err := h.finder.ClientAndKind(context.Get("database").(DatabaseInterface), clientID, parameters.KindID)
+	database := context.Get("database").(DatabaseInterface)
+
+	client, kind, err := h.finder.ClientAndKind(database, clientID, parameters.KindID)
 	if err != nil {
 		return []byte{}, err
 	}
@@ -74,11 +91,40 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 		return []byte{}, webutil.NewCriticalNotificationError(kind.ID)
 	}
 
+	err = h.checkData(database, client, kind, parameters.Data)
+	if err != nil {
+		return []byte{}, err
+	}
+
 	err = h.registrar.Register(connection, client, []models.Kind{kind})
 	if err != nil {
 		return []byte{}, err
 	}
 
+	var attachmentIDs []string
+	for _, attachment := range parameters.Attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(attachment.Filename))
+		}
+
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		createdAttachment, err := h.attachmentsRepo.Create(connection, models.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: contentType,
+			ContentID:   attachment.ContentID,
+			Content:     attachment.Content,
+		})
+		if err != nil {
+			return []byte{}, err
+		}
+
+		attachmentIDs = append(attachmentIDs, createdAttachment.ID)
+	}
+
 	var responses []services.Response
 
 	responses, err = strategy.Dispatch(services.Dispatch{
@@ -88,6 +134,7 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 		Client: services.DispatchClient{
 			ID:          clientID,
 			Description: client.Description,
+			Transport:   client.Transport,
 		},
 		Kind: services.DispatchKind{
 			ID:          parameters.KindID,
@@ -109,6 +156,10 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 				Head:           parameters.ParsedHTML.Head,
 				Doctype:        parameters.ParsedHTML.Doctype,
 			},
+			AttachmentIDs: attachmentIDs,
+			ThreadKey:     parameters.ThreadKey,
+			Headers:       parameters.Headers,
+			Data:          parameters.Data,
 		},
 	})
 	if err != nil {
@@ -123,6 +174,32 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 	return output, nil
 }
 
+// checkData validates the data against the schema declared by the template
+// the notification will be rendered with, the same template the delivery
+// worker resolves from the kind and client.
+func (h Notify) checkData(database DatabaseInterface, client models.Client, kind models.Kind, data map[string]interface{}) error {
+	templateID := kind.TemplateToUse()
+	if templateID == models.DefaultTemplateID {
+		templateID = client.TemplateToUse()
+	}
+
+	template, err := h.templateFinder.FindByID(database, templateID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); ok {
+			return nil
+		}
+
+		return err
+	}
+
+	schema, err := common.ParseDataSchema(template.Metadata)
+	if err != nil {
+		return err
+	}
+
+	return schema.Validate(data)
+}
+
 func (h Notify) hasCriticalNotificationsWriteScope(elements interface{}) bool {
 	for _, elem := range elements.([]interface{}) {
 		if elem.(string) == "critical_notifications.write" {
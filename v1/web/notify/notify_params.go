@@ -4,14 +4,22 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"net/mail"
 	"regexp"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/cloudfoundry-incubator/notifications/markdown"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 )
 
-const InvalidEmail = "<>InvalidEmail<>"
+const (
+	InvalidEmail = "<>InvalidEmail<>"
+
+	MaxAttachmentSize   = 3 << 20
+	MaxAttachmentsTotal = 10 << 20
+	MaxDataSize         = 16 << 10
+)
 
 var (
 	validOrganizationRoles = []string{"OrgManager", "OrgAuditor", "BillingManager"}
@@ -19,13 +27,20 @@ var (
 )
 
 type NotifyParams struct {
-	ReplyTo string `json:"reply_to"`
-	Subject string `json:"subject"`
-	Text    string `json:"text"`
-	RawHTML string `json:"html"`
-	KindID  string `json:"kind_id"`
-	To      string `json:"to"`
-	Role    string `json:"role"`
+	ReplyTo  string `json:"reply_to"`
+	Subject  string `json:"subject"`
+	Text     string `json:"text"`
+	RawHTML  string `json:"html"`
+	Markdown string `json:"markdown"`
+	KindID   string `json:"kind_id"`
+	To       string `json:"to"`
+	Role     string `json:"role"`
+
+	ThreadKey string                 `json:"thread_key"`
+	Headers   map[string]string      `json:"headers"`
+	Data      map[string]interface{} `json:"data"`
+
+	Attachments []Attachment `json:"attachments"`
 
 	ParsedHTML        HTML
 	KindDescription   string
@@ -40,6 +55,13 @@ type HTML struct {
 	Doctype        string
 }
 
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	ContentID   string `json:"content_id"`
+	Content     []byte `json:"content"`
+}
+
 func NewNotifyParams(body io.ReadCloser) (NotifyParams, error) {
 	notify := NotifyParams{}
 
@@ -73,6 +95,18 @@ func (notify *NotifyParams) parseRequestBody(body io.ReadCloser) error {
 func (notify *NotifyParams) FormatEmailAndExtractHTML() error {
 	notify.To = EmailFormatter{}.Format(notify.To)
 
+	if notify.Markdown != "" {
+		html, text := markdown.Render(notify.Markdown)
+		if notify.Text == "" {
+			notify.Text = text
+		}
+
+		if notify.RawHTML == "" {
+			notify.ParsedHTML.BodyContent = html
+			return nil
+		}
+	}
+
 	doctype, head, bodyContent, bodyAttributes, err := HTMLExtractor{}.Extract(notify.RawHTML)
 	if err != nil {
 		return err
@@ -93,6 +127,11 @@ func (EmailFormatter) Format(email string) string {
 		return email
 	}
 
+	address, err := mail.ParseAddress(email)
+	if err == nil {
+		return address.Address
+	}
+
 	matches := emailRegexp.FindStringSubmatch(email)
 
 	if len(matches) == 0 {
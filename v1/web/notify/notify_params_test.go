@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -36,6 +37,37 @@ var _ = Describe("NotifyParams", func() {
 			}).NotTo(Panic())
 		})
 
+		Describe("attachments parsing", func() {
+			It("decodes base64 attachment content", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+					"text": "Contents of the email message",
+					"attachments": [{
+						"filename": "logo.png",
+						"content_type": "image/png",
+						"content_id": "logo",
+						"content": "c29tZS1wbmctY29udGVudA=="
+					}]
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(parameters.Attachments).To(Equal([]notify.Attachment{
+					{
+						Filename:    "logo.png",
+						ContentType: "image/png",
+						ContentID:   "logo",
+						Content:     []byte("some-png-content"),
+					},
+				}))
+			})
+
+			It("returns a parse error when the content is not base64 encoded", func() {
+				_, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+					"attachments": [{"filename": "logo.png", "content": "not base64!"}]
+				}`)))
+				Expect(err).To(Equal(webutil.ParseError{}))
+			})
+		})
+
 		Describe("to field parsing", func() {
 			It("handles when a name is attached to the address", func() {
 				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
@@ -53,6 +85,14 @@ var _ = Describe("NotifyParams", func() {
 				Expect(parameters.To).To(Equal("user@example.com"))
 			})
 
+			It("handles internationalized addresses and encoded names", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+					"to": "=?utf-8?q?J=C3=BCrgen?= <ユーザー@例え.jp>"
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.To).To(Equal("ユーザー@例え.jp"))
+			})
+
 			It("sets the to field to InvalidEmail cannot be parsed", func() {
 				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
                     "to": "<The User"
@@ -87,6 +127,30 @@ var _ = Describe("NotifyParams", func() {
 			})
 		})
 
+		Describe("markdown parsing", func() {
+			It("renders the markdown into the text and html parts", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "kind_id": "test_email",
+                    "markdown": "Hello **there**, see [the docs](https://example.com)"
+                }`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.Text).To(Equal("Hello there, see the docs (https://example.com)\n"))
+				Expect(parameters.ParsedHTML.BodyContent).To(Equal(`<p>Hello <strong>there</strong>, see <a href="https://example.com">the docs</a></p>` + "\n"))
+			})
+
+			It("keeps the text and html parts that were supplied", func() {
+				parameters, err := notify.NewNotifyParams(ioutil.NopCloser(strings.NewReader(`{
+                    "kind_id": "test_email",
+                    "markdown": "*rendered*",
+                    "text": "supplied text",
+                    "html": "<p>supplied html</p>"
+                }`)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.Text).To(Equal("supplied text"))
+				Expect(parameters.ParsedHTML.BodyContent).To(Equal("<p>supplied html</p>"))
+			})
+		})
+
 		Describe("html parsing", func() {
 			Context("when a doctype is passed in", func() {
 				It("pulls out the doctype", func() {
@@ -1,6 +1,12 @@
 package notify
 
-import "regexp"
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+)
 
 var kindIDFormat = regexp.MustCompile(`^[0-9a-zA-Z_\-.]+$`)
 
@@ -18,9 +24,13 @@ func (validator EmailValidator) Validate(notify *NotifyParams) bool {
 	}
 
 	if missingTextOrHTMLFields(notify) {
-		notify.Errors = append(notify.Errors, `"text" or "html" fields must be supplied`)
+		notify.Errors = append(notify.Errors, `"text", "html" or "markdown" fields must be supplied`)
 	}
 
+	checkAttachmentsField(notify)
+	checkHeadersField(notify)
+	checkDataField(notify)
+
 	return len(notify.Errors) == 0
 }
 
@@ -32,13 +42,17 @@ func (validator GUIDValidator) Validate(notify *NotifyParams) bool {
 	validator.checkKindIDField(notify)
 
 	if missingTextOrHTMLFields(notify) {
-		notify.Errors = append(notify.Errors, `"text" or "html" fields must be supplied`)
+		notify.Errors = append(notify.Errors, `"text", "html" or "markdown" fields must be supplied`)
 	}
 
 	if validator.invalidRoleField(notify.Role) {
 		notify.Errors = append(notify.Errors, `"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`)
 	}
 
+	checkAttachmentsField(notify)
+	checkHeadersField(notify)
+	checkDataField(notify)
+
 	return len(notify.Errors) == 0
 }
 
@@ -46,6 +60,47 @@ func missingTextOrHTMLFields(notify *NotifyParams) bool {
 	return notify.Text == "" && notify.ParsedHTML.BodyContent == ""
 }
 
+func checkAttachmentsField(notify *NotifyParams) {
+	var total int
+	for _, attachment := range notify.Attachments {
+		if attachment.Filename == "" {
+			notify.Errors = append(notify.Errors, `"attachments" must each have a "filename"`)
+		}
+
+		if len(attachment.Content) == 0 {
+			notify.Errors = append(notify.Errors, `"attachments" must each have "content"`)
+		}
+
+		if len(attachment.Content) > MaxAttachmentSize {
+			notify.Errors = append(notify.Errors, fmt.Sprintf(`"attachments" must each be at most %d bytes`, MaxAttachmentSize))
+		}
+
+		total += len(attachment.Content)
+	}
+
+	if total > MaxAttachmentsTotal {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"attachments" must be at most %d bytes in total`, MaxAttachmentsTotal))
+	}
+}
+
+func checkHeadersField(notify *NotifyParams) {
+	err := mail.ValidateHeaders(notify.Headers)
+	if err != nil {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"headers" are invalid: %s`, err))
+	}
+}
+
+func checkDataField(notify *NotifyParams) {
+	if notify.Data == nil {
+		return
+	}
+
+	data, err := json.Marshal(notify.Data)
+	if err != nil || len(data) > MaxDataSize {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"data" must be at most %d bytes`, MaxDataSize))
+	}
+}
+
 func (validator GUIDValidator) invalidRoleField(roleName string) bool {
 	if roleName == "" {
 		return false
@@ -1,6 +1,8 @@
 package notify_test
 
 import (
+	"strings"
+
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
 
 	. "github.com/onsi/ginkgo"
@@ -38,7 +40,7 @@ var _ = Describe("Validator", func() {
 				Expect(validator.Validate(params)).To(BeFalse())
 				Expect(len(params.Errors)).To(Equal(2))
 				Expect(params.Errors).To(ContainElement(`"to" is a required field`))
-				Expect(params.Errors).To(ContainElement(`"text" or "html" fields must be supplied`))
+				Expect(params.Errors).To(ContainElement(`"text", "html" or "markdown" fields must be supplied`))
 
 				params.To = "otherUser@example.com"
 				params.ParsedHTML = notify.HTML{BodyContent: "<p>Contents of this email message</p>"}
@@ -90,7 +92,7 @@ var _ = Describe("Validator", func() {
 				Expect(validator.Validate(params)).To(BeFalse())
 				Expect(len(params.Errors)).To(Equal(2))
 				Expect(params.Errors).To(ContainElement(`"kind_id" is a required field`))
-				Expect(params.Errors).To(ContainElement(`"text" or "html" fields must be supplied`))
+				Expect(params.Errors).To(ContainElement(`"text", "html" or "markdown" fields must be supplied`))
 
 				params.KindID = "something"
 				params.ParsedHTML.BodyContent = "<p>banana</p>"
@@ -124,6 +126,78 @@ var _ = Describe("Validator", func() {
 				Expect(len(params.Errors)).To(Equal(1))
 				Expect(params.Errors).To(ContainElement(`"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`))
 			})
+
+			It("validates the attachments", func() {
+				params.Attachments = []notify.Attachment{
+					{Filename: "invoice.pdf", Content: []byte("some-content")},
+				}
+				Expect(validator.Validate(params)).To(BeTrue())
+				Expect(len(params.Errors)).To(Equal(0))
+
+				params.Attachments = []notify.Attachment{
+					{Content: []byte("some-content")},
+					{Filename: "empty.pdf"},
+				}
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf([]string{
+					`"attachments" must each have a "filename"`,
+					`"attachments" must each have "content"`,
+				}))
+
+				params.Attachments = []notify.Attachment{
+					{Filename: "huge.pdf", Content: make([]byte, notify.MaxAttachmentSize+1)},
+				}
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf([]string{
+					`"attachments" must each be at most 3145728 bytes`,
+				}))
+
+				content := make([]byte, notify.MaxAttachmentSize)
+				params.Attachments = []notify.Attachment{
+					{Filename: "first.pdf", Content: content},
+					{Filename: "second.pdf", Content: content},
+					{Filename: "third.pdf", Content: content},
+					{Filename: "fourth.pdf", Content: content},
+				}
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf([]string{
+					`"attachments" must be at most 10485760 bytes in total`,
+				}))
+			})
+
+			It("validates the headers", func() {
+				params.Headers = map[string]string{
+					"X-Tracking-ID": "some-tracking-id",
+					"Importance":    "high",
+				}
+				Expect(validator.Validate(params)).To(BeTrue())
+				Expect(len(params.Errors)).To(Equal(0))
+
+				params.Headers = map[string]string{
+					"Subject": "something else",
+				}
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf([]string{
+					`"headers" are invalid: header "Subject" cannot be overridden`,
+				}))
+			})
+
+			It("validates the size of the data", func() {
+				params.Data = map[string]interface{}{
+					"app_name": "some-app",
+					"quota":    map[string]interface{}{"used": 90, "total": 100},
+				}
+				Expect(validator.Validate(params)).To(BeTrue())
+				Expect(len(params.Errors)).To(Equal(0))
+
+				params.Data = map[string]interface{}{
+					"notes": strings.Repeat("x", notify.MaxDataSize),
+				}
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ConsistOf([]string{
+					`"data" must be at most 16384 bytes`,
+				}))
+			})
 		})
 	})
 })
@@ -2,12 +2,14 @@ package notify_test
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -29,6 +31,7 @@ var _ = Describe("Notify", func() {
 				finder          *mocks.NotificationsFinder
 				validator       *mocks.Validator
 				registrar       *mocks.Registrar
+				attachmentsRepo *mocks.AttachmentsRepo
 				request         *http.Request
 				rawToken        string
 				client          models.Client
@@ -40,6 +43,7 @@ var _ = Describe("Notify", func() {
 				tokenClaims     map[string]interface{}
 				vcapRequestID   string
 				database        *mocks.Database
+				templateFinder  *mocks.TemplateFinder
 				reqReceivedTime time.Time
 			)
 
@@ -47,6 +51,7 @@ var _ = Describe("Notify", func() {
 				client = models.Client{
 					ID:          "mister-client",
 					Description: "Health Monitor",
+					Transport:   "billing",
 				}
 				kind = models.Kind{
 					ID:          "test_email",
@@ -59,6 +64,8 @@ var _ = Describe("Notify", func() {
 				finder.ClientAndKindCall.Returns.Kind = kind
 
 				registrar = mocks.NewRegistrar()
+				attachmentsRepo = mocks.NewAttachmentsRepo()
+				templateFinder = mocks.NewTemplateFinder()
 
 				body, err := json.Marshal(map[string]string{
 					"kind_id":  "test_email",
@@ -108,7 +115,7 @@ var _ = Describe("Notify", func() {
 				validator = mocks.NewValidator()
 				validator.ValidateCall.Returns.Valid = true
 
-				handler = notify.NewNotify(finder, registrar)
+				handler = notify.NewNotify(finder, registrar, attachmentsRepo, templateFinder)
 			})
 
 			It("delegates to the strategy", func() {
@@ -122,6 +129,7 @@ var _ = Describe("Notify", func() {
 					Client: services.DispatchClient{
 						ID:          "mister-client",
 						Description: "Health Monitor",
+						Transport:   "billing",
 					},
 					Kind: services.DispatchKind{
 						ID:          "test_email",
@@ -159,6 +167,172 @@ var _ = Describe("Notify", func() {
 				Expect(registrar.RegisterCall.Receives.Kinds).To(ConsistOf([]models.Kind{kind}))
 			})
 
+			It("stores attachments and dispatches their ids", func() {
+				attachmentsRepo.CreateCall.Returns.Attachments = []models.Attachment{
+					{ID: "some-attachment-id"},
+					{ID: "another-attachment-id"},
+				}
+
+				body, err := json.Marshal(map[string]interface{}{
+					"kind_id": "test_email",
+					"text":    "This is the plain text body of the email",
+					"subject": "Your instance is down",
+					"attachments": []map[string]interface{}{
+						{
+							"filename":     "invoice.pdf",
+							"content_type": "application/pdf",
+							"content":      base64.StdEncoding.EncodeToString([]byte("some-pdf-content")),
+						},
+						{
+							"filename":   "logo.png",
+							"content_id": "logo",
+							"content":    base64.StdEncoding.EncodeToString([]byte("some-png-content")),
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+				request.Header.Set("Authorization", "Bearer "+rawToken)
+
+				_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(attachmentsRepo.CreateCall.Receives.Connection).To(Equal(conn))
+				Expect(attachmentsRepo.CreateCall.Receives.Attachments).To(Equal([]models.Attachment{
+					{
+						Filename:    "invoice.pdf",
+						ContentType: "application/pdf",
+						Content:     []byte("some-pdf-content"),
+					},
+					{
+						Filename:    "logo.png",
+						ContentType: "image/png",
+						ContentID:   "logo",
+						Content:     []byte("some-png-content"),
+					},
+				}))
+
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.AttachmentIDs).To(Equal([]string{
+					"some-attachment-id",
+					"another-attachment-id",
+				}))
+			})
+
+			It("passes the thread key through to the strategy", func() {
+				body, err := json.Marshal(map[string]interface{}{
+					"kind_id":    "test_email",
+					"text":       "This is the plain text body of the email",
+					"subject":    "Your instance is down",
+					"thread_key": "some-thread-key",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+				request.Header.Set("Authorization", "Bearer "+rawToken)
+
+				_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.ThreadKey).To(Equal("some-thread-key"))
+			})
+
+			It("passes the custom headers through to the strategy", func() {
+				body, err := json.Marshal(map[string]interface{}{
+					"kind_id": "test_email",
+					"text":    "This is the plain text body of the email",
+					"subject": "Your instance is down",
+					"headers": map[string]string{
+						"X-Tracking-ID": "some-tracking-id",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+				request.Header.Set("Authorization", "Bearer "+rawToken)
+
+				_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.Headers).To(Equal(map[string]string{
+					"X-Tracking-ID": "some-tracking-id",
+				}))
+			})
+
+			It("passes the template data through to the strategy", func() {
+				body, err := json.Marshal(map[string]interface{}{
+					"kind_id": "test_email",
+					"text":    "This is the plain text body of the email",
+					"subject": "Your instance is down",
+					"data": map[string]interface{}{
+						"app_name": "some-app",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+				request.Header.Set("Authorization", "Bearer "+rawToken)
+
+				_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Message.Data).To(Equal(map[string]interface{}{
+					"app_name": "some-app",
+				}))
+			})
+
+			Context("when the template declares the data it requires", func() {
+				BeforeEach(func() {
+					templateFinder.FindByIDCall.Returns.Template = models.Template{
+						ID:       "some-template-id",
+						Metadata: `{"variables": {"app_name": {"type": "string", "required": true}}}`,
+					}
+				})
+
+				It("checks the data against the template assigned to the kind", func() {
+					kind.TemplateID = "some-template-id"
+					finder.ClientAndKindCall.Returns.Kind = kind
+
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).To(MatchError(common.DataValidationError{
+						Messages: []string{"data.app_name is required"},
+					}))
+
+					Expect(templateFinder.FindByIDCall.Receives.Database).To(Equal(database))
+					Expect(templateFinder.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+					Expect(strategy.DispatchCallsCount).To(Equal(0))
+				})
+
+				It("falls back to the template assigned to the client", func() {
+					client.TemplateID = "some-client-template-id"
+					finder.ClientAndKindCall.Returns.Client = client
+
+					handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+
+					Expect(templateFinder.FindByIDCall.Receives.TemplateID).To(Equal("some-client-template-id"))
+				})
+
+				It("sends the notification when the data satisfies the schema", func() {
+					body, err := json.Marshal(map[string]interface{}{
+						"kind_id": "test_email",
+						"text":    "This is the plain text body of the email",
+						"data":    map[string]interface{}{"app_name": "some-app"},
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(strategy.DispatchCallsCount).To(Equal(1))
+				})
+			})
+
 			Context("failure cases", func() {
 				Context("when validating params", func() {
 					It("returns a error response when params are missing", func() {
@@ -201,6 +375,29 @@ var _ = Describe("Notify", func() {
 					})
 				})
 
+				Context("when storing an attachment fails", func() {
+					It("returns the error", func() {
+						attachmentsRepo.CreateCall.Returns.Error = errors.New("attachment error")
+
+						body, err := json.Marshal(map[string]interface{}{
+							"kind_id": "test_email",
+							"text":    "This is the plain text body of the email",
+							"attachments": []map[string]interface{}{
+								{"filename": "invoice.pdf", "content": base64.StdEncoding.EncodeToString([]byte("some-content"))},
+							},
+						})
+						Expect(err).NotTo(HaveOccurred())
+
+						request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+						Expect(err).NotTo(HaveOccurred())
+						request.Header.Set("Authorization", "Bearer "+rawToken)
+
+						_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+						Expect(err).To(MatchError(errors.New("attachment error")))
+						Expect(strategy.DispatchCallsCount).To(Equal(0))
+					})
+				})
+
 				Context("when the finder return errors", func() {
 					It("returns the error", func() {
 						finder.ClientAndKindCall.Returns.Error = errors.New("BOOM!")
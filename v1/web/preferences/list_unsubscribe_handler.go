@@ -0,0 +1,45 @@
+package preferences
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type listUnsubscriber interface {
+	Unsubscribe(connection services.ConnectionInterface, token string) error
+}
+
+type ListUnsubscribeHandler struct {
+	unsubscriber listUnsubscriber
+	errorWriter  errorWriter
+}
+
+func NewListUnsubscribeHandler(unsubscriber listUnsubscriber, errWriter errorWriter) ListUnsubscribeHandler {
+	return ListUnsubscribeHandler{
+		unsubscriber: unsubscriber,
+		errorWriter:  errWriter,
+	}
+}
+
+func (h ListUnsubscribeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	database := context.Get("database").(DatabaseInterface)
+
+	token := regexp.MustCompile(".*/list_unsubscribe/(.*)").FindStringSubmatch(req.URL.Path)[1]
+
+	err := h.unsubscriber.Unsubscribe(database.Connection(), token)
+	if err != nil {
+		switch err.(type) {
+		case services.CriticalKindError:
+			h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+		default:
+			h.errorWriter.Write(w, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,76 @@
+package preferences_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/preferences"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListUnsubscribeHandler", func() {
+	var (
+		handler      preferences.ListUnsubscribeHandler
+		writer       *httptest.ResponseRecorder
+		request      *http.Request
+		connection   *mocks.Connection
+		context      stack.Context
+		unsubscriber *mocks.ListUnsubscriber
+		errorWriter  *mocks.ErrorWriter
+	)
+
+	BeforeEach(func() {
+		connection = mocks.NewConnection()
+
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		var err error
+		request, err = http.NewRequest("POST", "/list_unsubscribe/some-token=", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		unsubscriber = mocks.NewListUnsubscriber()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		handler = preferences.NewListUnsubscribeHandler(unsubscriber, errorWriter)
+	})
+
+	It("unsubscribes using the token from the path", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(unsubscriber.UnsubscribeCall.Receives.Connection).To(Equal(connection))
+		Expect(unsubscriber.UnsubscribeCall.Receives.Token).To(Equal("some-token="))
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+	})
+
+	Context("when the kind is critical", func() {
+		It("writes a validation error", func() {
+			unsubscriber.UnsubscribeCall.Returns.Error = services.CriticalKindError{Err: errors.New("critical")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: services.CriticalKindError{Err: errors.New("critical")}}))
+		})
+	})
+
+	Context("when the unsubscribe fails", func() {
+		It("writes the error", func() {
+			unsubscriber.UnsubscribeCall.Returns.Error = services.InvalidUnsubscribeTokenError{Err: errors.New("invalid")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(services.InvalidUnsubscribeTokenError{Err: errors.New("invalid")}))
+		})
+	})
+})
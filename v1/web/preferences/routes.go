@@ -12,6 +12,7 @@ type muxer interface {
 
 type preferenceUpdater interface {
 	Update(connection services.ConnectionInterface, preferences []models.Preference, globallyUnsubscribe bool, userID string) error
+	SetLocale(connection services.ConnectionInterface, userID, locale string) error
 }
 
 type Routes struct {
@@ -26,6 +27,7 @@ type Routes struct {
 	ErrorWriter       errorWriter
 	PreferencesFinder preferencesFinder
 	PreferenceUpdater preferenceUpdater
+	ListUnsubscriber  listUnsubscriber
 }
 
 func (r Routes) Register(m muxer) {
@@ -35,4 +37,5 @@ func (r Routes) Register(m muxer) {
 	m.Handle("PATCH", "/user_preferences", NewUpdatePreferencesHandler(r.PreferenceUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.CORS, r.NotificationPreferencesWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/user_preferences/{user_id}", NewGetUserPreferencesHandler(r.PreferencesFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.CORS, r.NotificationPreferencesAdminAuthenticator, r.DatabaseAllocator)
 	m.Handle("PATCH", "/user_preferences/{user_id}", NewUpdateUserPreferencesHandler(r.PreferenceUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.CORS, r.NotificationPreferencesAdminAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/list_unsubscribe/{token}", NewListUnsubscribeHandler(r.ListUnsubscriber, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.DatabaseAllocator)
 }
@@ -23,6 +23,7 @@ var _ = Describe("Routes", func() {
 			ErrorWriter:       mocks.NewErrorWriter(),
 			PreferencesFinder: mocks.NewPreferencesFinder(),
 			PreferenceUpdater: mocks.NewPreferenceUpdater(),
+			ListUnsubscriber:  mocks.NewListUnsubscriber(),
 
 			CORS:                                      middleware.CORS{},
 			RequestCounter:                            middleware.RequestCounter{},
@@ -103,4 +104,15 @@ var _ = Describe("Routes", func() {
 			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.CORS{})
 		})
 	})
+
+	Describe("/list_unsubscribe/{token}", func() {
+		It("routes POST /list_unsubscribe/{token} without authentication", func() {
+			request, err := http.NewRequest("POST", "/list_unsubscribe/some-token", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(preferences.ListUnsubscribeHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.DatabaseAllocator{})
+		})
+	})
 })
@@ -54,6 +54,9 @@ func (h UpdatePreferencesHandler) ServeHTTP(w http.ResponseWriter, req *http.Req
 	transaction := connection.Transaction()
 	transaction.Begin()
 	err = h.preferences.Update(transaction, preferences, builder.GlobalUnsubscribe, userID)
+	if err == nil && builder.Locale != nil {
+		err = h.preferences.SetLocale(transaction, userID, *builder.Locale)
+	}
 	if err != nil {
 		transaction.Rollback()
 
@@ -121,6 +121,43 @@ var _ = Describe("UpdatePreferencesHandler", func() {
 			Expect(updater.UpdateCall.Receives.UserID).To(Equal("correct-user"))
 		})
 
+		It("leaves the locale preference alone when the request omits it", func() {
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(updater.SetLocaleCall.Receives.UserID).To(BeEmpty())
+		})
+
+		Context("when the request includes a locale", func() {
+			BeforeEach(func() {
+				var err error
+				request, err = http.NewRequest("PATCH", "/user_preferences", bytes.NewBuffer([]byte(`{
+					"global_unsubscribe": false,
+					"locale": "fr-CA",
+					"clients": {}
+				}`)))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("stores the locale preference within the transaction", func() {
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(http.StatusNoContent))
+				Expect(reflect.ValueOf(updater.SetLocaleCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(transaction).Pointer()))
+				Expect(updater.SetLocaleCall.Receives.UserID).To(Equal("correct-user"))
+				Expect(updater.SetLocaleCall.Receives.Locale).To(Equal("fr-CA"))
+			})
+
+			It("rolls back the transaction when the locale cannot be stored", func() {
+				updater.SetLocaleCall.Returns.Error = errors.New("locale db error")
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("locale db error")))
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+			})
+		})
+
 		It("Returns a 204 status code when the Preference object does not error", func() {
 			handler.ServeHTTP(writer, request, context)
 
@@ -47,6 +47,9 @@ func (h UpdateUserPreferencesHandler) ServeHTTP(w http.ResponseWriter, req *http
 	transaction := connection.Transaction()
 	transaction.Begin()
 	err = h.preferences.Update(transaction, preferences, builder.GlobalUnsubscribe, userGUID)
+	if err == nil && builder.Locale != nil {
+		err = h.preferences.SetLocale(transaction, userGUID, *builder.Locale)
+	}
 	if err != nil {
 		transaction.Rollback()
 
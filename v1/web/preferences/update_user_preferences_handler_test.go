@@ -121,6 +121,22 @@ var _ = Describe("UpdateUserPreferencesHandler", func() {
 			Expect(updater.UpdateCall.Receives.UserID).To(Equal(userGUID))
 		})
 
+		It("stores the locale preference when the request includes one", func() {
+			var err error
+			request, err = http.NewRequest("PATCH", "domain/user_preferences/"+userGUID, bytes.NewBuffer([]byte(`{
+				"global_unsubscribe": false,
+				"locale": "ja",
+				"clients": {}
+			}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNoContent))
+			Expect(updater.SetLocaleCall.Receives.UserID).To(Equal(userGUID))
+			Expect(updater.SetLocaleCall.Receives.Locale).To(Equal("ja"))
+		})
+
 		It("Returns a 204 status code when the Preference object does not error", func() {
 			handler.ServeHTTP(writer, request, context)
 
@@ -9,11 +9,14 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/metrics"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/sanitize"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/util"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/bounces"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/clients"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/info"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/messages"
@@ -24,6 +27,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/gorilla/mux"
+	"github.com/pivotal-golang/conceal"
 	"github.com/pivotal-golang/lager"
 	"github.com/ryanmoran/stack"
 )
@@ -46,34 +50,53 @@ type Config struct {
 	CORSOrigin           string
 	SQLDB                *sql.DB
 	QueueWaitMaxDuration int
+	EncryptionKey        []byte
+	Sender               string
+	Domain               string
+	HTMLPolicy           sanitize.Policy
+	HTMLClientPolicies   map[string]sanitize.Policy
 }
 
 func NewRouter(mx muxer, config Config) http.Handler {
 	guidGenerator := util.NewIDGenerator(rand.Reader)
 	clock := util.NewClock()
 
+	cloak, err := conceal.NewCloak(config.EncryptionKey)
+	if err != nil {
+		panic(err)
+	}
+
 	clientsRepo := models.NewClientsRepo()
 	kindsRepo := models.NewKindsRepo()
 	globalUnsubscribesRepo := models.NewGlobalUnsubscribesRepo()
 	preferencesRepo := models.NewPreferencesRepo()
 	unsubscribesRepo := models.NewUnsubscribesRepo()
 	messagesRepo := models.NewMessagesRepo(guidGenerator.Generate)
+	attachmentsRepo := models.NewAttachmentsRepo(guidGenerator.Generate)
 	templatesRepo := models.NewTemplatesRepo()
+	templateVersionsRepo := models.NewTemplateVersionsRepo()
+	suppressionsRepo := models.NewSuppressionsRepo()
+	transportsRepo := models.NewTransportsRepo()
+	userLocalesRepo := models.NewUserLocalesRepo()
 
 	registrar := services.NewRegistrar(clientsRepo, kindsRepo)
 	notificationsFinder := services.NewNotificationsFinder(clientsRepo, kindsRepo)
-	preferencesFinder := services.NewPreferencesFinder(preferencesRepo, globalUnsubscribesRepo)
-	preferenceUpdater := services.NewPreferenceUpdater(globalUnsubscribesRepo, unsubscribesRepo, kindsRepo)
+	preferencesFinder := services.NewPreferencesFinder(preferencesRepo, globalUnsubscribesRepo, userLocalesRepo)
+	preferenceUpdater := services.NewPreferenceUpdater(globalUnsubscribesRepo, unsubscribesRepo, kindsRepo, userLocalesRepo)
+	listUnsubscriber := services.NewListUnsubscriber(cloak, unsubscribesRepo, kindsRepo)
 	notificationsUpdater := services.NewNotificationsUpdater(kindsRepo)
 	messageFinder := services.NewMessageFinder(messagesRepo)
+	bounceProcessor := services.NewBounceProcessor(suppressionsRepo)
 
-	templatesCollection := collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo)
+	templatesCollection := collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, templateVersionsRepo)
+	transportsCollection := collections.NewTransportsCollection(clientsRepo, transportsRepo)
 
 	templateFinder := services.NewTemplateFinder(templatesRepo)
-	templateUpdater := services.NewTemplateUpdater(templatesRepo)
+	templateUpdater := services.NewTemplateUpdater(templatesRepo, templateVersionsRepo)
 	templateLister := services.NewTemplateLister(templatesRepo)
+	templatePreviewer := common.NewPreviewer(cloak, sanitize.NewSanitizer(config.HTMLPolicy, config.HTMLClientPolicies), config.Sender, config.Domain)
 
-	notifyObj := notify.NewNotify(notificationsFinder, registrar)
+	notifyObj := notify.NewNotify(notificationsFinder, registrar, attachmentsRepo, templateFinder)
 
 	gobbleQueue := gobble.NewQueue(gobble.NewDatabase(config.SQLDB), clock, gobble.Config{
 		WaitMaxDuration: time.Duration(config.QueueWaitMaxDuration) * time.Millisecond,
@@ -123,6 +146,7 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		ErrorWriter:       errorWriter,
 		PreferencesFinder: preferencesFinder,
 		PreferenceUpdater: preferenceUpdater,
+		ListUnsubscriber:  listUnsubscriber,
 	}.Register(mx)
 
 	clients.Routes{
@@ -131,8 +155,9 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		DatabaseAllocator:                databaseAllocator,
 		NotificationsManageAuthenticator: auth("notifications.manage"),
 
-		ErrorWriter:      errorWriter,
-		TemplateAssigner: templatesCollection,
+		ErrorWriter:       errorWriter,
+		TemplateAssigner:  templatesCollection,
+		TransportAssigner: transportsCollection,
 	}.Register(mx)
 
 	messages.Routes{
@@ -145,6 +170,17 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		MessageFinder: messageFinder,
 	}.Register(mx)
 
+	bounces.Routes{
+		RequestCounter:                   requestCounter,
+		RequestLogging:                   requestLogging,
+		DatabaseAllocator:                databaseAllocator,
+		NotificationsManageAuthenticator: auth("notifications.manage"),
+
+		ErrorWriter:     errorWriter,
+		BounceProcessor: bounceProcessor,
+		Unsuppressor:    bounceProcessor,
+	}.Register(mx)
+
 	templates.Routes{
 		RequestCounter:                          requestCounter,
 		RequestLogging:                          requestLogging,
@@ -156,10 +192,14 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		ErrorWriter:               errorWriter,
 		TemplateFinder:            templateFinder,
 		TemplateUpdater:           templateUpdater,
+		TemplateUsageChecker:      templatesCollection,
 		TemplateCreator:           templatesCollection,
 		TemplateDeleter:           templatesCollection,
 		TemplateLister:            templateLister,
 		TemplateAssociationLister: templatesCollection,
+		TemplateVersioner:         templatesCollection,
+		TemplatePreviewer:         templatePreviewer,
+		TemplateBundler:           templatesCollection,
 	}.Register(mx)
 
 	notifications.Routes{
@@ -0,0 +1,195 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+)
+
+const (
+	bundleVersion = 1
+	bundleAPI     = "v1"
+)
+
+type bundleDocument struct {
+	Version     int                  `json:"version"`
+	API         string               `json:"api"`
+	Templates   []bundleTemplate     `json:"templates"`
+	Assignments []assignmentDocument `json:"assignments"`
+}
+
+type bundleTemplate struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Subject       string          `json:"subject"`
+	Text          string          `json:"text"`
+	HTML          string          `json:"html"`
+	Metadata      json.RawMessage `json:"metadata,omitempty"`
+	Localizations json.RawMessage `json:"localizations,omitempty"`
+	LayoutID      string          `json:"layout_id,omitempty"`
+
+	DisableAutoText    bool `json:"disable_auto_text,omitempty"`
+	DisableCSSInlining bool `json:"disable_css_inlining,omitempty"`
+}
+
+type assignmentDocument struct {
+	ClientID       string `json:"client_id"`
+	NotificationID string `json:"notification_id,omitempty"`
+	TemplateID     string `json:"template_id"`
+}
+
+func newBundleDocument(bundle collections.Bundle) bundleDocument {
+	document := bundleDocument{
+		Version:     bundleVersion,
+		API:         bundleAPI,
+		Templates:   []bundleTemplate{},
+		Assignments: []assignmentDocument{},
+	}
+
+	for _, template := range bundle.Templates {
+		document.Templates = append(document.Templates, bundleTemplate{
+			ID:            template.ID,
+			Name:          template.Name,
+			Subject:       template.Subject,
+			Text:          template.Text,
+			HTML:          template.HTML,
+			Metadata:      rawJSON(template.Metadata),
+			Localizations: rawJSON(template.Localizations),
+			LayoutID:      template.LayoutID,
+
+			DisableAutoText:    template.DisableAutoText,
+			DisableCSSInlining: template.DisableCSSInlining,
+		})
+	}
+
+	for _, assignment := range bundle.Assignments {
+		document.Assignments = append(document.Assignments, assignmentDocument{
+			ClientID:       assignment.ClientID,
+			NotificationID: assignment.NotificationID,
+			TemplateID:     assignment.TemplateID,
+		})
+	}
+
+	return document
+}
+
+// validate applies the checks the API makes when each template is created on
+// its own.
+func (d bundleDocument) validate() error {
+	if d.Version != bundleVersion {
+		return webutil.ValidationError{Err: fmt.Errorf("unsupported bundle version %d", d.Version)}
+	}
+
+	if d.API != bundleAPI {
+		return webutil.ValidationError{Err: fmt.Errorf("bundle must be exported from the %s API", bundleAPI)}
+	}
+
+	for _, template := range d.Templates {
+		if template.Name == "" {
+			return webutil.ValidationError{Err: errors.New(`template "name" field cannot be empty`)}
+		}
+
+		if template.HTML == "" {
+			return webutil.ValidationError{Err: fmt.Errorf("template %q: missing template html", template.Name)}
+		}
+
+		if len(template.Metadata) > 0 {
+			_, err := common.ParseDataSchema(string(template.Metadata))
+			if err != nil {
+				return webutil.ValidationError{Err: fmt.Errorf("template %q: %s", template.Name, err)}
+			}
+		}
+
+		var localizations common.Localizations
+		if len(template.Localizations) > 0 {
+			err := json.Unmarshal(template.Localizations, &localizations)
+			if err != nil {
+				return webutil.ValidationError{Err: fmt.Errorf("template %q: localizations must map each locale to a subject, text and html", template.Name)}
+			}
+		}
+
+		templates := common.Templates{
+			Subject: template.subject(),
+			Text:    template.Text,
+			HTML:    template.HTML,
+		}
+
+		_, err := common.ValidateLocalizedTemplates(templates, localizations, false)
+		if err != nil {
+			return webutil.ValidationError{Err: fmt.Errorf("template %q: %s", template.Name, err)}
+		}
+
+		err = checkPartials(templates, localizations)
+		if err != nil {
+			return webutil.ValidationError{Err: fmt.Errorf("template %q: %s", template.Name, err)}
+		}
+	}
+
+	for _, assignment := range d.Assignments {
+		if assignment.ClientID == "" {
+			return webutil.ValidationError{Err: errors.New(`assignment "client_id" field cannot be empty`)}
+		}
+	}
+
+	return nil
+}
+
+func (d bundleDocument) bundle() collections.Bundle {
+	var bundle collections.Bundle
+
+	for _, template := range d.Templates {
+		metadata := "{}"
+		if len(template.Metadata) > 0 {
+			metadata = string(template.Metadata)
+		}
+
+		localizations := "{}"
+		if len(template.Localizations) > 0 {
+			localizations = string(template.Localizations)
+		}
+
+		bundle.Templates = append(bundle.Templates, collections.Template{
+			ID:            template.ID,
+			Name:          template.Name,
+			Subject:       template.subject(),
+			Text:          template.Text,
+			HTML:          template.HTML,
+			Metadata:      metadata,
+			Localizations: localizations,
+			LayoutID:      template.LayoutID,
+
+			DisableAutoText:    template.DisableAutoText,
+			DisableCSSInlining: template.DisableCSSInlining,
+		})
+	}
+
+	for _, assignment := range d.Assignments {
+		bundle.Assignments = append(bundle.Assignments, collections.TemplateAssignment{
+			ClientID:       assignment.ClientID,
+			NotificationID: assignment.NotificationID,
+			TemplateID:     assignment.TemplateID,
+		})
+	}
+
+	return bundle
+}
+
+func (t bundleTemplate) subject() string {
+	if t.Subject == "" {
+		return "{{.Subject}}"
+	}
+
+	return t.Subject
+}
+
+func rawJSON(value string) json.RawMessage {
+	if value == "" {
+		return nil
+	}
+
+	return json.RawMessage(value)
+}
@@ -2,9 +2,11 @@ package templates
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/ryanmoran/stack"
 )
@@ -30,7 +32,7 @@ func NewCreateHandler(creator templateCreator, errWriter errorWriter) CreateHand
 }
 
 func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
-	templateParams, err := NewTemplateParams(req.Body)
+	templateParams, err := NewTemplateParams(req.Body, false)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
@@ -39,17 +41,28 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	connection := context.Get("database").(DatabaseInterface).Connection()
 
 	template, err := h.creator.Create(connection, collections.Template{
-		Name:     templateParams.Name,
-		Text:     templateParams.Text,
-		HTML:     templateParams.HTML,
-		Subject:  templateParams.Subject,
-		Metadata: string(templateParams.Metadata),
+		Name:          templateParams.Name,
+		Text:          templateParams.Text,
+		HTML:          templateParams.HTML,
+		Subject:       templateParams.Subject,
+		Metadata:      string(templateParams.Metadata),
+		Localizations: templateParams.encodedLocalizations(),
+		LayoutID:      templateParams.LayoutID,
+
+		DisableAutoText:    templateParams.DisableAutoText,
+		DisableCSSInlining: templateParams.DisableCSSInlining,
 	})
 	if err != nil {
+		if _, ok := err.(models.TemplateLayoutError); ok {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
 		h.errorWriter.Write(w, webutil.TemplateCreateError{})
 		return
 	}
 
+	writeWarnings(w, templateParams.Warnings)
 	w.WriteHeader(http.StatusCreated)
 	w.Write([]byte(`{"template_id":"` + template.ID + `"}`))
 }
@@ -63,3 +76,9 @@ func writeJSON(w http.ResponseWriter, status int, object interface{}) {
 	w.WriteHeader(status)
 	w.Write(output)
 }
+
+func writeWarnings(w http.ResponseWriter, warnings []string) {
+	for _, warning := range warnings {
+		w.Header().Add("Warning", fmt.Sprintf("299 - %q", warning))
+	}
+}
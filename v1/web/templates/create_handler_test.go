@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/cloudfoundry-incubator/notifications/valiant"
@@ -71,17 +74,45 @@ var _ = Describe("CreateHandler", func() {
 
 			Expect(creator.CreateCall.Receives.Connection).To(Equal(connection))
 			Expect(creator.CreateCall.Receives.Template).To(Equal(collections.Template{
-				Name:     "Emergency Template",
-				Text:     "Message to: {{.To}}. Raptor Alert.",
-				HTML:     "<p>{{.ClientID}} you should run.</p>",
-				Subject:  "Raptor Containment Unit Breached",
-				Metadata: "{}",
+				Name:          "Emergency Template",
+				Text:          "Message to: {{.To}}. Raptor Alert.",
+				HTML:          "<p>{{.ClientID}} you should run.</p>",
+				Subject:       "Raptor Containment Unit Breached",
+				Metadata:      "{}",
+				Localizations: "{}",
 			}))
 
 			Expect(writer.Code).To(Equal(http.StatusCreated))
 			Expect(writer.Body.String()).To(MatchJSON(`{"template_id":"template-guid"}`))
 		})
 
+		It("passes along the layout of the template", func() {
+			request, err = http.NewRequest("POST", "/templates", bytes.NewBuffer([]byte(`{"name": "gobble", "html": "<p>gobble</p>", "layout_id": "some-layout-id"}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusCreated))
+			Expect(creator.CreateCall.Receives.Template.LayoutID).To(Equal("some-layout-id"))
+		})
+
+		It("warns when the template has no unsubscribe link", func() {
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusCreated))
+			Expect(writer.HeaderMap["Warning"]).To(ConsistOf(fmt.Sprintf("299 - %q", common.MissingUnsubscribeLinkWarning)))
+		})
+
+		It("does not warn when the template has an unsubscribe link", func() {
+			request, err = http.NewRequest("POST", "/templates", bytes.NewBuffer([]byte(`{"name": "gobble", "html": "<a href=\"{{.ListUnsubscribeURL}}\">unsubscribe</a>"}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusCreated))
+			Expect(writer.HeaderMap).NotTo(HaveKey("Warning"))
+		})
+
 		Context("when an errors occurs", func() {
 			It("Writes a validation error to the errorwriter when the request is missing the name field", func() {
 				request, err = http.NewRequest("POST", "/templates", bytes.NewBuffer([]byte(`{"html": "<p>gobble</p>"}`)))
@@ -99,6 +130,16 @@ var _ = Describe("CreateHandler", func() {
 				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{valiant.RequiredFieldError{"Missing required field 'html'"}}))
 			})
 
+			It("writes a template validation error when a template does not compile", func() {
+				request, err = http.NewRequest("POST", "/templates", bytes.NewBuffer([]byte(`{"name": "gobble", "html": "<p>{{.Gobble}}</p>", "subject": "{{.Subject"}`)))
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+				Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(common.TemplateValidationError{}))
+				Expect(errorWriter.WriteCall.Receives.Error.(common.TemplateValidationError).Errors).To(HaveLen(2))
+				Expect(creator.CreateCall.Receives.Template).To(Equal(collections.Template{}))
+			})
+
 			It("writes a parse error for an invalid request", func() {
 				request, err = http.NewRequest("POST", "/templates", bytes.NewBuffer([]byte(`{"name":"foobar", "html": forgot to close the curly brace`)))
 				Expect(err).NotTo(HaveOccurred())
@@ -107,6 +148,13 @@ var _ = Describe("CreateHandler", func() {
 				Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ParseError{}))
 			})
 
+			It("writes a layout error when the layout cannot be used", func() {
+				creator.CreateCall.Returns.Error = models.TemplateLayoutError{Err: errors.New(`Layout "some-layout-id" could not be found`)}
+
+				handler.ServeHTTP(writer, request, context)
+				Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.TemplateLayoutError{Err: errors.New(`Layout "some-layout-id" could not be found`)}))
+			})
+
 			It("returns a 500 for all other error cases", func() {
 				creator.CreateCall.Returns.Error = errors.New("my new error")
 
@@ -0,0 +1,89 @@
+package templates
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type TemplateDiffOutput struct {
+	From int                      `json:"from"`
+	To   int                      `json:"to"`
+	Diff TemplateDiffFieldsOutput `json:"diff"`
+}
+
+type TemplateDiffFieldsOutput struct {
+	Name          string `json:"name,omitempty"`
+	Subject       string `json:"subject,omitempty"`
+	HTML          string `json:"html,omitempty"`
+	Text          string `json:"text,omitempty"`
+	Metadata      string `json:"metadata,omitempty"`
+	Localizations string `json:"localizations,omitempty"`
+	LayoutID      string `json:"layout_id,omitempty"`
+
+	DisableAutoText    string `json:"disable_auto_text,omitempty"`
+	DisableCSSInlining string `json:"disable_css_inlining,omitempty"`
+}
+
+type templateVersionDiffer interface {
+	DiffVersions(connection collections.ConnectionInterface, templateID string, from, to int) (collections.TemplateDiff, error)
+}
+
+type DiffHandler struct {
+	differ      templateVersionDiffer
+	errorWriter errorWriter
+}
+
+func NewDiffHandler(differ templateVersionDiffer, errWriter errorWriter) DiffHandler {
+	return DiffHandler{
+		differ:      differ,
+		errorWriter: errWriter,
+	}
+}
+
+func (h DiffHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := regexp.MustCompile(`\/templates\/(.*)\/diff`).FindStringSubmatch(req.URL.Path)[1]
+	query := req.URL.Query()
+
+	from, err := strconv.Atoi(query.Get("from"))
+	if err != nil || from < 1 {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: errors.New(`"from" must be a template version number`)})
+		return
+	}
+
+	to, err := strconv.Atoi(query.Get("to"))
+	if err != nil || to < 1 {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: errors.New(`"to" must be a template version number`)})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	diff, err := h.differ.DiffVersions(database.Connection(), templateID, from, to)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TemplateDiffOutput{
+		From: diff.From,
+		To:   diff.To,
+		Diff: TemplateDiffFieldsOutput{
+			Name:          diff.Name,
+			Subject:       diff.Subject,
+			HTML:          diff.HTML,
+			Text:          diff.Text,
+			Metadata:      diff.Metadata,
+			Localizations: diff.Localizations,
+			LayoutID:      diff.LayoutID,
+
+			DisableAutoText:    diff.DisableAutoText,
+			DisableCSSInlining: diff.DisableCSSInlining,
+		},
+	})
+}
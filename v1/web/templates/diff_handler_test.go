@@ -0,0 +1,100 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffHandler", func() {
+	var (
+		handler     templates.DiffHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		versioner   *mocks.TemplateVersioner
+		errorWriter *mocks.ErrorWriter
+		connection  *mocks.Connection
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		versioner = mocks.NewTemplateVersioner()
+		versioner.DiffVersionsCall.Returns.TemplateDiff = collections.TemplateDiff{
+			TemplateID: "banana-template",
+			From:       1,
+			To:         2,
+			Text:       "-old text\n+new text",
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("GET", "/templates/banana-template/diff?from=1&to=2", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = templates.NewDiffHandler(versioner, errorWriter)
+	})
+
+	It("returns the differences between the two versions", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"from": 1,
+			"to": 2,
+			"diff": {
+				"text": "-old text\n+new text"
+			}
+		}`))
+
+		Expect(versioner.DiffVersionsCall.Receives.Connection).To(Equal(connection))
+		Expect(versioner.DiffVersionsCall.Receives.TemplateID).To(Equal("banana-template"))
+		Expect(versioner.DiffVersionsCall.Receives.From).To(Equal(1))
+		Expect(versioner.DiffVersionsCall.Receives.To).To(Equal(2))
+	})
+
+	Context("when errors occur", func() {
+		It("writes a validation error when from is missing", func() {
+			var err error
+			request, err = http.NewRequest("GET", "/templates/banana-template/diff?to=2", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"from" must be a template version number`)}))
+		})
+
+		It("writes a validation error when to is not a number", func() {
+			var err error
+			request, err = http.NewRequest("GET", "/templates/banana-template/diff?from=1&to=two", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"to" must be a template version number`)}))
+		})
+
+		It("delegates errors from the versioner to the error writer", func() {
+			versioner.DiffVersionsCall.Returns.Error = errors.New("db failed or something")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("db failed or something")))
+		})
+	})
+})
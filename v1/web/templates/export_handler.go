@@ -0,0 +1,65 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/util"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type templateExporter interface {
+	Export(connection collections.ConnectionInterface) (collections.Bundle, error)
+}
+
+type ExportHandler struct {
+	exporter    templateExporter
+	errorWriter errorWriter
+}
+
+func NewExportHandler(exporter templateExporter, errWriter errorWriter) ExportHandler {
+	return ExportHandler{
+		exporter:    exporter,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ExportHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	format := req.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "tar" {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: errors.New(`"format" must be json or tar`)})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	bundle, err := h.exporter.Export(database.Connection())
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	if format == "tar" {
+		document, err := json.Marshal(newBundleDocument(bundle))
+		if err != nil {
+			panic(err)
+		}
+
+		archive := bytes.NewBuffer([]byte{})
+		err = util.WriteJSONTar(archive, document)
+		if err != nil {
+			panic(err)
+		}
+
+		w.Header().Set("Content-Type", "application/x-tar")
+		w.Header().Set("Content-Disposition", `attachment; filename="notifications-templates.tar"`)
+		w.Write(archive.Bytes())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newBundleDocument(bundle))
+}
@@ -0,0 +1,120 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/util"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExportHandler", func() {
+	var (
+		handler     templates.ExportHandler
+		writer      *httptest.ResponseRecorder
+		bundler     *mocks.TemplateBundler
+		errorWriter *mocks.ErrorWriter
+		connection  *mocks.Connection
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		bundler = mocks.NewTemplateBundler()
+		bundler.ExportCall.Returns.Bundle = collections.Bundle{
+			Templates: []collections.Template{{
+				ID:            "some-template-id",
+				Name:          "some-template",
+				Subject:       "{{.Subject}}",
+				HTML:          "<p>hi</p>",
+				Metadata:      "{}",
+				Localizations: "{}",
+				Version:       2,
+			}},
+			Assignments: []collections.TemplateAssignment{
+				{ClientID: "some-client", TemplateID: "some-template-id"},
+				{ClientID: "some-client", NotificationID: "some-kind", TemplateID: "some-template-id"},
+			},
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = templates.NewExportHandler(bundler, errorWriter)
+	})
+
+	It("exports the templates and their assignments as a versioned JSON bundle", func() {
+		request, err := http.NewRequest("GET", "/templates/export", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(bundler.ExportCall.Receives.Connection).To(Equal(connection))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"version": 1,
+			"api": "v1",
+			"templates": [{
+				"id": "some-template-id",
+				"name": "some-template",
+				"subject": "{{.Subject}}",
+				"text": "",
+				"html": "<p>hi</p>",
+				"metadata": {},
+				"localizations": {}
+			}],
+			"assignments": [
+				{"client_id": "some-client", "template_id": "some-template-id"},
+				{"client_id": "some-client", "notification_id": "some-kind", "template_id": "some-template-id"}
+			]
+		}`))
+	})
+
+	It("exports a tar bundle", func() {
+		request, err := http.NewRequest("GET", "/templates/export?format=tar", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Header().Get("Content-Type")).To(Equal("application/x-tar"))
+
+		document, err := util.ReadJSONTar(writer.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(document)).To(ContainSubstring(`"api":"v1"`))
+		Expect(string(document)).To(ContainSubstring(`"notification_id":"some-kind"`))
+	})
+
+	It("writes a validation error when the format is not supported", func() {
+		request, err := http.NewRequest("GET", "/templates/export?format=zip", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New(`"format" must be json or tar`)}))
+	})
+
+	It("writes the error when the bundle cannot be exported", func() {
+		bundler.ExportCall.Returns.Error = errors.New("database is gone")
+		request, err := http.NewRequest("GET", "/templates/export", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("database is gone")))
+	})
+})
@@ -39,11 +39,16 @@ func (h GetDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, c
 	}
 
 	templateOutput := TemplateOutput{
-		Name:     template.Name,
-		Subject:  template.Subject,
-		HTML:     template.HTML,
-		Text:     template.Text,
-		Metadata: metadata,
+		Name:          template.Name,
+		Subject:       template.Subject,
+		HTML:          template.HTML,
+		Text:          template.Text,
+		Metadata:      metadata,
+		Localizations: decodeLocalizations(template.Localizations),
+		LayoutID:      template.LayoutID,
+
+		DisableAutoText:    template.DisableAutoText,
+		DisableCSSInlining: template.DisableCSSInlining,
 	}
 
 	writeJSON(w, http.StatusOK, templateOutput)
@@ -5,15 +5,22 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/ryanmoran/stack"
 )
 
 type TemplateOutput struct {
-	Name     string                 `json:"name"`
-	Subject  string                 `json:"subject"`
-	HTML     string                 `json:"html"`
-	Text     string                 `json:"text"`
-	Metadata map[string]interface{} `json:"metadata"`
+	Name          string                 `json:"name"`
+	Subject       string                 `json:"subject"`
+	HTML          string                 `json:"html"`
+	Text          string                 `json:"text"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	Localizations common.Localizations   `json:"localizations,omitempty"`
+	LayoutID      string                 `json:"layout_id,omitempty"`
+	Version       int                    `json:"version,omitempty"`
+
+	DisableAutoText    bool `json:"disable_auto_text,omitempty"`
+	DisableCSSInlining bool `json:"disable_css_inlining,omitempty"`
 }
 
 type GetHandler struct {
@@ -45,11 +52,17 @@ func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 	}
 
 	templateOutput := TemplateOutput{
-		Name:     template.Name,
-		Subject:  template.Subject,
-		HTML:     template.HTML,
-		Text:     template.Text,
-		Metadata: metadata,
+		Name:          template.Name,
+		Subject:       template.Subject,
+		HTML:          template.HTML,
+		Text:          template.Text,
+		Metadata:      metadata,
+		Localizations: decodeLocalizations(template.Localizations),
+		LayoutID:      template.LayoutID,
+		Version:       template.Version,
+
+		DisableAutoText:    template.DisableAutoText,
+		DisableCSSInlining: template.DisableCSSInlining,
 	}
 
 	writeJSON(w, http.StatusOK, templateOutput)
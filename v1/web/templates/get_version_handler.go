@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/ryanmoran/stack"
+)
+
+type templateVersionGetter interface {
+	GetVersion(connection collections.ConnectionInterface, templateID string, version int) (collections.TemplateVersion, error)
+}
+
+type GetVersionHandler struct {
+	getter      templateVersionGetter
+	errorWriter errorWriter
+}
+
+func NewGetVersionHandler(getter templateVersionGetter, errWriter errorWriter) GetVersionHandler {
+	return GetVersionHandler{
+		getter:      getter,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetVersionHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	matches := regexp.MustCompile(`\/templates\/(.*)\/versions\/(.*)`).FindStringSubmatch(req.URL.Path)
+	templateID := matches[1]
+
+	version, err := strconv.Atoi(matches[2])
+	if err != nil || version < 1 {
+		h.errorWriter.Write(w, models.NotFoundError{Err: fmt.Errorf("Version %q of template with ID %q could not be found", matches[2], templateID)})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	templateVersion, err := h.getter.GetVersion(database.Connection(), templateID, version)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output, err := newTemplateVersionOutput(templateVersion)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
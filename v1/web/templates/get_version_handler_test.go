@@ -0,0 +1,97 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetVersionHandler", func() {
+	var (
+		handler     templates.GetVersionHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		versioner   *mocks.TemplateVersioner
+		errorWriter *mocks.ErrorWriter
+		connection  *mocks.Connection
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		versioner = mocks.NewTemplateVersioner()
+		versioner.GetVersionCall.Returns.TemplateVersion = collections.TemplateVersion{
+			TemplateID: "banana-template",
+			Version:    2,
+			Name:       "Banana",
+			Subject:    "subject",
+			Text:       "text",
+			HTML:       "<p>html</p>",
+			Metadata:   "{}",
+			CreatedAt:  time.Date(2015, time.July, 2, 0, 0, 0, 0, time.UTC),
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("GET", "/templates/banana-template/versions/2", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = templates.NewGetVersionHandler(versioner, errorWriter)
+	})
+
+	It("returns the requested version of the template", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"version": 2,
+			"name": "Banana",
+			"subject": "subject",
+			"html": "<p>html</p>",
+			"text": "text",
+			"metadata": {},
+			"created_at": "2015-07-02T00:00:00Z"
+		}`))
+
+		Expect(versioner.GetVersionCall.Receives.Connection).To(Equal(connection))
+		Expect(versioner.GetVersionCall.Receives.TemplateID).To(Equal("banana-template"))
+		Expect(versioner.GetVersionCall.Receives.Version).To(Equal(2))
+	})
+
+	Context("when errors occur", func() {
+		It("writes a not found error when the version is not a number", func() {
+			var err error
+			request, err = http.NewRequest("GET", "/templates/banana-template/versions/latest", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New(`Version "latest" of template with ID "banana-template" could not be found`)}))
+		})
+
+		It("delegates errors from the versioner to the error writer", func() {
+			versioner.GetVersionCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+})
@@ -0,0 +1,154 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/util"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type templateImporter interface {
+	Import(connection collections.ConnectionInterface, bundle collections.Bundle, options collections.ImportOptions) ([]collections.ImportedRecord, error)
+}
+
+type ImportHandler struct {
+	importer    templateImporter
+	errorWriter errorWriter
+}
+
+func NewImportHandler(importer templateImporter, errWriter errorWriter) ImportHandler {
+	return ImportHandler{
+		importer:    importer,
+		errorWriter: errWriter,
+	}
+}
+
+type importResponse struct {
+	DryRun  bool                   `json:"dry_run"`
+	Records []importRecordResponse `json:"records"`
+}
+
+type importRecordResponse struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	SourceID string `json:"source_id,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Action   string `json:"action"`
+}
+
+func (h ImportHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	query := req.URL.Query()
+	options := collections.ImportOptions{
+		Conflict: query.Get("conflict"),
+		IDMap:    map[string]string{},
+	}
+
+	var dryRun bool
+	if value := query.Get("dry_run"); value != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			h.errorWriter.Write(w, webutil.ValidationError{Err: errors.New(`"dry_run" must be true or false`)})
+			return
+		}
+	}
+
+	for _, mapping := range query["id_map"] {
+		ids := strings.SplitN(mapping, ":", 2)
+		if len(ids) != 2 || ids[0] == "" || ids[1] == "" {
+			h.errorWriter.Write(w, webutil.ValidationError{Err: errors.New(`"id_map" entries must take the form <bundle-id>:<id>`)})
+			return
+		}
+
+		options.IDMap[ids[0]] = ids[1]
+	}
+
+	var body io.Reader = req.Body
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType == "application/x-tar" {
+		document, err := util.ReadJSONTar(req.Body)
+		if err != nil {
+			h.errorWriter.Write(w, webutil.ValidationError{Err: fmt.Errorf("invalid tar bundle: %s", err)})
+			return
+		}
+
+		body = bytes.NewReader(document)
+	}
+
+	var document bundleDocument
+	err := json.NewDecoder(body).Decode(&document)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ParseError{})
+		return
+	}
+
+	err = document.validate()
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	transaction := context.Get("database").(DatabaseInterface).Connection().Transaction()
+	transaction.Begin()
+
+	records, err := h.importer.Import(transaction, document.bundle(), options)
+	if err != nil {
+		transaction.Rollback()
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	if dryRun {
+		err = transaction.Rollback()
+	} else {
+		err = transaction.Commit()
+	}
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newImportResponse(records, dryRun))
+}
+
+// newImportResponse builds the response for an import. The templates a dry
+// run would create have been rolled back, so their IDs are left out.
+func newImportResponse(records []collections.ImportedRecord, dryRun bool) importResponse {
+	response := importResponse{
+		DryRun:  dryRun,
+		Records: []importRecordResponse{},
+	}
+
+	discarded := map[string]bool{}
+	for _, record := range records {
+		if dryRun && (record.Action == collections.ImportCreated || record.Action == collections.ImportRenamed) {
+			discarded[record.ID] = true
+		}
+	}
+
+	for _, record := range records {
+		id := record.ID
+		if discarded[id] {
+			id = ""
+		}
+
+		response.Records = append(response.Records, importRecordResponse{
+			Type:     record.Type,
+			Name:     record.Name,
+			SourceID: record.SourceID,
+			ID:       id,
+			Action:   record.Action,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,174 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/util"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImportHandler", func() {
+	var (
+		handler     templates.ImportHandler
+		writer      *httptest.ResponseRecorder
+		bundler     *mocks.TemplateBundler
+		errorWriter *mocks.ErrorWriter
+		transaction *mocks.Transaction
+		context     stack.Context
+		document    string
+	)
+
+	importBundle := func(path, contentType, body string) {
+		request, err := http.NewRequest("POST", path, bytes.NewBufferString(body))
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Content-Type", contentType)
+
+		handler.ServeHTTP(writer, request, context)
+	}
+
+	BeforeEach(func() {
+		document = `{
+			"version": 1,
+			"api": "v1",
+			"templates": [{"id": "source-template-id", "name": "welcome", "html": "<p>Hello {{.HTML}}</p>"}],
+			"assignments": [{"client_id": "some-client", "template_id": "source-template-id"}]
+		}`
+
+		bundler = mocks.NewTemplateBundler()
+		bundler.ImportCall.Returns.Records = []collections.ImportedRecord{
+			{Type: "template", Name: "welcome", SourceID: "source-template-id", ID: "new-template-id", Action: collections.ImportCreated},
+			{Type: "assignment", Name: "some-client", SourceID: "source-template-id", ID: "new-template-id", Action: collections.ImportAssigned},
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		transaction = mocks.NewTransaction()
+		connection := mocks.NewConnection()
+		connection.TransactionCall.Returns.Transaction = transaction
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = templates.NewImportHandler(bundler, errorWriter)
+	})
+
+	It("imports the bundle within a transaction", func() {
+		importBundle("/templates/import?conflict=overwrite&id_map=source-template-id:existing-template-id", "application/json", document)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"dry_run": false,
+			"records": [
+				{"type": "template", "name": "welcome", "source_id": "source-template-id", "id": "new-template-id", "action": "created"},
+				{"type": "assignment", "name": "some-client", "source_id": "source-template-id", "id": "new-template-id", "action": "assigned"}
+			]
+		}`))
+
+		Expect(bundler.ImportCall.Receives.Connection).To(Equal(transaction))
+		Expect(bundler.ImportCall.Receives.Options).To(Equal(collections.ImportOptions{
+			Conflict: "overwrite",
+			IDMap:    map[string]string{"source-template-id": "existing-template-id"},
+		}))
+		Expect(bundler.ImportCall.Receives.Bundle).To(Equal(collections.Bundle{
+			Templates: []collections.Template{{
+				ID:            "source-template-id",
+				Name:          "welcome",
+				Subject:       "{{.Subject}}",
+				HTML:          "<p>Hello {{.HTML}}</p>",
+				Metadata:      "{}",
+				Localizations: "{}",
+			}},
+			Assignments: []collections.TemplateAssignment{
+				{ClientID: "some-client", TemplateID: "source-template-id"},
+			},
+		}))
+
+		Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+		Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+	})
+
+	It("rolls a dry run back and leaves out the IDs of templates it would create", func() {
+		importBundle("/templates/import?dry_run=true", "application/json", document)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"dry_run": true,
+			"records": [
+				{"type": "template", "name": "welcome", "source_id": "source-template-id", "action": "created"},
+				{"type": "assignment", "name": "some-client", "source_id": "source-template-id", "action": "assigned"}
+			]
+		}`))
+
+		Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+	})
+
+	It("imports a tar bundle", func() {
+		archive := bytes.NewBuffer([]byte{})
+		Expect(util.WriteJSONTar(archive, []byte(document))).To(Succeed())
+
+		importBundle("/templates/import", "application/x-tar", archive.String())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(bundler.ImportCall.Receives.Bundle.Templates).To(HaveLen(1))
+		Expect(bundler.ImportCall.Receives.Bundle.Assignments).To(HaveLen(1))
+	})
+
+	Context("when the bundle is invalid", func() {
+		examples := []struct {
+			description string
+			path        string
+			body        string
+			message     string
+		}{
+			{"an unsupported version", "/templates/import", `{"version": 2, "api": "v1"}`, "unsupported bundle version 2"},
+			{"a bundle from another API", "/templates/import", `{"version": 1, "api": "v2"}`, "bundle must be exported from the v1 API"},
+			{"an invalid dry run", "/templates/import?dry_run=maybe", `{"version": 1, "api": "v1"}`, `"dry_run" must be true or false`},
+			{"an invalid ID map", "/templates/import?id_map=some-id", `{"version": 1, "api": "v1"}`, `"id_map" entries must take the form <bundle-id>:<id>`},
+			{"a template without html", "/templates/import", `{"version": 1, "api": "v1", "templates": [{"name": "empty", "text": "hi"}]}`, `template "empty": missing template html`},
+			{"a template that includes a partial", "/templates/import", `{"version": 1, "api": "v1", "templates": [{"name": "partial", "html": "{{template \"footer\" .}}"}]}`, `template "partial": v1 templates cannot include partials: footer`},
+			{"an assignment without a client", "/templates/import", `{"version": 1, "api": "v1", "assignments": [{"template_id": "some-id"}]}`, `assignment "client_id" field cannot be empty`},
+		}
+
+		for _, example := range examples {
+			example := example
+
+			It("writes a validation error for "+example.description, func() {
+				importBundle(example.path, "application/json", example.body)
+
+				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New(example.message)}))
+				Expect(bundler.ImportCall.WasCalled).To(BeFalse())
+			})
+		}
+	})
+
+	It("writes a parse error when the body is not JSON", func() {
+		importBundle("/templates/import", "application/json", "not json")
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ParseError{}))
+	})
+
+	It("rolls back and writes the error when the import fails", func() {
+		bundler.ImportCall.Returns.Error = collections.TemplateImportError{Err: errors.New(`template "welcome": Layout "missing" could not be found`)}
+
+		importBundle("/templates/import", "application/json", document)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(bundler.ImportCall.Returns.Error))
+		Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+		Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+	})
+})
@@ -0,0 +1,92 @@
+package templates
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type TemplateVersionOutput struct {
+	Version       int                    `json:"version"`
+	Name          string                 `json:"name"`
+	Subject       string                 `json:"subject"`
+	HTML          string                 `json:"html"`
+	Text          string                 `json:"text"`
+	Metadata      map[string]interface{} `json:"metadata"`
+	Localizations common.Localizations   `json:"localizations,omitempty"`
+	LayoutID      string                 `json:"layout_id,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+
+	DisableAutoText    bool `json:"disable_auto_text,omitempty"`
+	DisableCSSInlining bool `json:"disable_css_inlining,omitempty"`
+}
+
+type templateVersionLister interface {
+	ListVersions(connection collections.ConnectionInterface, templateID string) ([]collections.TemplateVersion, error)
+}
+
+type ListVersionsHandler struct {
+	lister      templateVersionLister
+	errorWriter errorWriter
+}
+
+func NewListVersionsHandler(lister templateVersionLister, errWriter errorWriter) ListVersionsHandler {
+	return ListVersionsHandler{
+		lister:      lister,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListVersionsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := regexp.MustCompile(`\/templates\/(.*)\/versions`).FindStringSubmatch(req.URL.Path)[1]
+	database := context.Get("database").(DatabaseInterface)
+
+	versions, err := h.lister.ListVersions(database.Connection(), templateID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	outputs := []TemplateVersionOutput{}
+	for _, version := range versions {
+		output, err := newTemplateVersionOutput(version)
+		if err != nil {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
+		outputs = append(outputs, output)
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]TemplateVersionOutput{
+		"versions": outputs,
+	})
+}
+
+func newTemplateVersionOutput(version collections.TemplateVersion) (TemplateVersionOutput, error) {
+	var metadata map[string]interface{}
+	err := json.Unmarshal([]byte(version.Metadata), &metadata)
+	if err != nil {
+		return TemplateVersionOutput{}, err
+	}
+
+	return TemplateVersionOutput{
+		Version:       version.Version,
+		Name:          version.Name,
+		Subject:       version.Subject,
+		HTML:          version.HTML,
+		Text:          version.Text,
+		Metadata:      metadata,
+		Localizations: decodeLocalizations(version.Localizations),
+		LayoutID:      version.LayoutID,
+		CreatedAt:     version.CreatedAt,
+
+		DisableAutoText:    version.DisableAutoText,
+		DisableCSSInlining: version.DisableCSSInlining,
+	}, nil
+}
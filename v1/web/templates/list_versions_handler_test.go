@@ -0,0 +1,109 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListVersionsHandler", func() {
+	var (
+		handler     templates.ListVersionsHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		versioner   *mocks.TemplateVersioner
+		errorWriter *mocks.ErrorWriter
+		connection  *mocks.Connection
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		versioner = mocks.NewTemplateVersioner()
+		versioner.ListVersionsCall.Returns.TemplateVersions = []collections.TemplateVersion{
+			{
+				TemplateID: "banana-template",
+				Version:    2,
+				Name:       "Banana",
+				Subject:    "new subject",
+				Text:       "new text",
+				HTML:       "<p>new</p>",
+				Metadata:   `{"peel": true}`,
+				CreatedAt:  time.Date(2015, time.July, 2, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				TemplateID: "banana-template",
+				Version:    1,
+				Name:       "Banana",
+				Subject:    "old subject",
+				Text:       "old text",
+				HTML:       "<p>old</p>",
+				Metadata:   "{}",
+				CreatedAt:  time.Date(2015, time.July, 1, 0, 0, 0, 0, time.UTC),
+			},
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("GET", "/templates/banana-template/versions", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = templates.NewListVersionsHandler(versioner, errorWriter)
+	})
+
+	It("returns the versions of the template", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"versions": [
+				{
+					"version": 2,
+					"name": "Banana",
+					"subject": "new subject",
+					"html": "<p>new</p>",
+					"text": "new text",
+					"metadata": { "peel": true },
+					"created_at": "2015-07-02T00:00:00Z"
+				},
+				{
+					"version": 1,
+					"name": "Banana",
+					"subject": "old subject",
+					"html": "<p>old</p>",
+					"text": "old text",
+					"metadata": {},
+					"created_at": "2015-07-01T00:00:00Z"
+				}
+			]
+		}`))
+
+		Expect(versioner.ListVersionsCall.Receives.Connection).To(Equal(connection))
+		Expect(versioner.ListVersionsCall.Receives.TemplateID).To(Equal("banana-template"))
+	})
+
+	It("delegates errors from the versioner to the error writer", func() {
+		versioner.ListVersionsCall.Returns.Error = errors.New("db failed or something")
+
+		handler.ServeHTTP(writer, request, context)
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("db failed or something")))
+	})
+})
@@ -0,0 +1,128 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type templatePreviewer interface {
+	Preview(delivery common.Delivery, templates common.Templates) (common.Preview, error)
+}
+
+type PreviewOutput struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+	MIME    string `json:"mime"`
+}
+
+type PreviewHandler struct {
+	finder      templateFinder
+	previewer   templatePreviewer
+	errorWriter errorWriter
+}
+
+func NewPreviewHandler(templateFinder templateFinder, previewer templatePreviewer, errWriter errorWriter) PreviewHandler {
+	return PreviewHandler{
+		finder:      templateFinder,
+		previewer:   previewer,
+		errorWriter: errWriter,
+	}
+}
+
+func (h PreviewHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	var params struct {
+		Subject      string                 `json:"subject"`
+		Text         string                 `json:"text"`
+		HTML         string                 `json:"html"`
+		Endorsement  string                 `json:"endorsement"`
+		Data         map[string]interface{} `json:"data"`
+		Organization struct {
+			GUID string `json:"guid"`
+			Name string `json:"name"`
+		} `json:"organization"`
+		Space struct {
+			GUID string `json:"guid"`
+			Name string `json:"name"`
+		} `json:"space"`
+		Template *struct {
+			Subject string `json:"subject"`
+			Text    string `json:"text"`
+			HTML    string `json:"html"`
+		} `json:"template"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ParseError{})
+		return
+	}
+
+	var templates common.Templates
+	if matches := regexp.MustCompile(`^\/templates\/(.+)\/preview$`).FindStringSubmatch(req.URL.Path); matches != nil {
+		template, err := h.finder.FindByID(context.Get("database").(DatabaseInterface), matches[1])
+		if err != nil {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
+		templates = common.Templates{
+			Name:    template.Name,
+			Subject: template.Subject,
+			Text:    template.Text,
+			HTML:    template.HTML,
+		}
+	} else {
+		if params.Template == nil {
+			h.errorWriter.Write(w, webutil.ValidationError{Err: errors.New(`"template" is required to preview an inline template`)})
+			return
+		}
+
+		templates = common.Templates{
+			Subject: params.Template.Subject,
+			Text:    params.Template.Text,
+			HTML:    params.Template.HTML,
+		}
+	}
+
+	if templates.Subject == "" {
+		templates.Subject = "{{.Subject}}"
+	}
+
+	var clientID string
+	if token, ok := context.Get("token").(*jwt.Token); ok {
+		clientID, _ = token.Claims["client_id"].(string)
+	}
+
+	preview, err := h.previewer.Preview(common.Delivery{
+		ClientID:     clientID,
+		Organization: cf.CloudControllerOrganization{GUID: params.Organization.GUID, Name: params.Organization.Name},
+		Space:        cf.CloudControllerSpace{GUID: params.Space.GUID, Name: params.Space.Name},
+		Options: common.Options{
+			Subject:     params.Subject,
+			Text:        params.Text,
+			HTML:        common.HTML{BodyContent: params.HTML},
+			Endorsement: params.Endorsement,
+			Data:        params.Data,
+		},
+	}, templates)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PreviewOutput{
+		Subject: preview.Subject,
+		Text:    preview.Text,
+		HTML:    preview.HTML,
+		MIME:    preview.MIME,
+	})
+}
@@ -0,0 +1,178 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreviewHandler", func() {
+	var (
+		handler     templates.PreviewHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		finder      *mocks.TemplateFinder
+		previewer   *mocks.TemplatePreviewer
+		errorWriter *mocks.ErrorWriter
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		finder = mocks.NewTemplateFinder()
+		finder.FindByIDCall.Returns.Template = models.Template{
+			Name:    "Banana",
+			Subject: "Banana {{.Subject}}",
+			Text:    "banana {{.Text}}",
+			HTML:    "<p>banana {{.HTML}}</p>",
+		}
+
+		previewer = mocks.NewTemplatePreviewer()
+		previewer.PreviewCall.Returns.Preview = common.Preview{
+			Subject: "Banana some subject",
+			Text:    "banana some text",
+			HTML:    "<p>banana <b>some html</b></p>",
+			MIME:    "Subject: Banana some subject",
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("POST", "/templates/banana-template/preview", bytes.NewBufferString(`{
+			"subject": "some subject",
+			"text": "some text",
+			"html": "<b>some html</b>",
+			"endorsement": "some endorsement",
+			"organization": {"guid": "some-org-guid", "name": "some-org"},
+			"space": {"guid": "some-space-guid", "name": "some-space"}
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		rawToken := helpers.BuildToken(map[string]interface{}{
+			"alg": "RS256",
+		}, map[string]interface{}{
+			"client_id": "banana-client",
+			"exp":       int64(3404281214),
+			"scope":     []string{"notification_templates.read"},
+		})
+
+		token, err := jwt.Parse(rawToken, func(*jwt.Token) (interface{}, error) {
+			return []byte(helpers.UAAPublicKey), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		database = mocks.NewDatabase()
+
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("token", token)
+
+		handler = templates.NewPreviewHandler(finder, previewer, errorWriter)
+	})
+
+	It("renders the stored template with the sample data", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"subject": "Banana some subject",
+			"text": "banana some text",
+			"html": "<p>banana <b>some html</b></p>",
+			"mime": "Subject: Banana some subject"
+		}`))
+
+		Expect(finder.FindByIDCall.Receives.Database).To(Equal(database))
+		Expect(finder.FindByIDCall.Receives.TemplateID).To(Equal("banana-template"))
+
+		Expect(previewer.PreviewCall.Receives.Templates).To(Equal(common.Templates{
+			Name:    "Banana",
+			Subject: "Banana {{.Subject}}",
+			Text:    "banana {{.Text}}",
+			HTML:    "<p>banana {{.HTML}}</p>",
+		}))
+		Expect(previewer.PreviewCall.Receives.Delivery).To(Equal(common.Delivery{
+			ClientID:     "banana-client",
+			Organization: cf.CloudControllerOrganization{GUID: "some-org-guid", Name: "some-org"},
+			Space:        cf.CloudControllerSpace{GUID: "some-space-guid", Name: "some-space"},
+			Options: common.Options{
+				Subject:     "some subject",
+				Text:        "some text",
+				HTML:        common.HTML{BodyContent: "<b>some html</b>"},
+				Endorsement: "some endorsement",
+			},
+		}))
+	})
+
+	It("renders an inline template", func() {
+		var err error
+		request, err = http.NewRequest("POST", "/templates/preview", bytes.NewBufferString(`{
+			"text": "some text",
+			"template": {"text": "inline {{.Text}}"}
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(finder.FindByIDCall.Receives.TemplateID).To(BeEmpty())
+		Expect(previewer.PreviewCall.Receives.Templates).To(Equal(common.Templates{
+			Subject: "{{.Subject}}",
+			Text:    "inline {{.Text}}",
+		}))
+	})
+
+	Context("when an error occurs", func() {
+		It("writes a parse error when the body is not valid JSON", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates/banana-template/preview", bytes.NewBufferString(`%%%`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ParseError{}))
+		})
+
+		It("writes a validation error when an inline preview has no template", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates/preview", bytes.NewBufferString(`{"text": "some text"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+		})
+
+		It("writes the error when the template cannot be found", func() {
+			finder.FindByIDCall.Returns.Error = models.NotFoundError{}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(models.NotFoundError{}))
+		})
+
+		It("writes the compile error when the template cannot be compiled", func() {
+			compileError := common.TemplateCompileError{Part: "text", Line: 1, Err: errors.New("template: text:1: unexpected EOF")}
+			previewer.PreviewCall.Returns.Error = compileError
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(compileError))
+		})
+	})
+})
@@ -0,0 +1,76 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type templateRollbacker interface {
+	Rollback(connection collections.ConnectionInterface, templateID string, version int) (collections.Template, error)
+}
+
+type RollbackHandler struct {
+	rollbacker  templateRollbacker
+	errorWriter errorWriter
+}
+
+func NewRollbackHandler(rollbacker templateRollbacker, errWriter errorWriter) RollbackHandler {
+	return RollbackHandler{
+		rollbacker:  rollbacker,
+		errorWriter: errWriter,
+	}
+}
+
+func (h RollbackHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := regexp.MustCompile(`\/templates\/(.*)\/rollback`).FindStringSubmatch(req.URL.Path)[1]
+
+	var params struct {
+		Version int `json:"version"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ParseError{})
+		return
+	}
+
+	if params.Version < 1 {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: errors.New(`"version" must be a template version number`)})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	template, err := h.rollbacker.Rollback(database.Connection(), templateID, params.Version)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	var metadata map[string]interface{}
+	err = json.Unmarshal([]byte(template.Metadata), &metadata)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TemplateOutput{
+		Name:          template.Name,
+		Subject:       template.Subject,
+		HTML:          template.HTML,
+		Text:          template.Text,
+		Metadata:      metadata,
+		Localizations: decodeLocalizations(template.Localizations),
+		LayoutID:      template.LayoutID,
+		Version:       template.Version,
+
+		DisableAutoText:    template.DisableAutoText,
+		DisableCSSInlining: template.DisableCSSInlining,
+	})
+}
@@ -0,0 +1,104 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RollbackHandler", func() {
+	var (
+		handler     templates.RollbackHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		versioner   *mocks.TemplateVersioner
+		errorWriter *mocks.ErrorWriter
+		connection  *mocks.Connection
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		versioner = mocks.NewTemplateVersioner()
+		versioner.RollbackCall.Returns.Template = collections.Template{
+			ID:       "banana-template",
+			Name:     "Banana",
+			Subject:  "old subject",
+			Text:     "old text",
+			HTML:     "<p>old</p>",
+			Metadata: "{}",
+			Version:  3,
+		}
+
+		errorWriter = mocks.NewErrorWriter()
+
+		writer = httptest.NewRecorder()
+		request, err = http.NewRequest("POST", "/templates/banana-template/rollback", bytes.NewBufferString(`{"version": 1}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = templates.NewRollbackHandler(versioner, errorWriter)
+	})
+
+	It("rolls the template back and returns the restored template", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"name": "Banana",
+			"subject": "old subject",
+			"html": "<p>old</p>",
+			"text": "old text",
+			"metadata": {},
+			"version": 3
+		}`))
+
+		Expect(versioner.RollbackCall.Receives.Connection).To(Equal(connection))
+		Expect(versioner.RollbackCall.Receives.TemplateID).To(Equal("banana-template"))
+		Expect(versioner.RollbackCall.Receives.Version).To(Equal(1))
+	})
+
+	Context("when errors occur", func() {
+		It("writes a parse error when the body is not valid JSON", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates/banana-template/rollback", bytes.NewBufferString(`%%%`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ParseError{}))
+		})
+
+		It("writes a validation error when the version is missing", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates/banana-template/rollback", bytes.NewBufferString(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(webutil.ValidationError{Err: errors.New(`"version" must be a template version number`)}))
+		})
+
+		It("delegates errors from the versioner to the error writer", func() {
+			versioner.RollbackCall.Returns.Error = errors.New("db failed or something")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("db failed or something")))
+		})
+	})
+})
@@ -18,9 +18,25 @@ type Routes struct {
 	TemplateFinder            templateFinder
 	TemplateLister            templateLister
 	TemplateUpdater           templateUpdater
+	TemplateUsageChecker      templateUsageChecker
 	TemplateCreator           templateCreator
 	TemplateDeleter           templateDeleter
 	TemplateAssociationLister templateAssociationLister
+	TemplateVersioner         templateVersioner
+	TemplatePreviewer         templatePreviewer
+	TemplateBundler           templateBundler
+}
+
+type templateVersioner interface {
+	templateVersionLister
+	templateVersionGetter
+	templateVersionDiffer
+	templateRollbacker
+}
+
+type templateBundler interface {
+	templateExporter
+	templateImporter
 }
 
 func (r Routes) Register(m muxer) {
@@ -28,8 +44,16 @@ func (r Routes) Register(m muxer) {
 	m.Handle("PUT", "/default_template", NewUpdateDefaultHandler(r.TemplateUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/templates", NewListHandler(r.TemplateLister, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
 	m.Handle("POST", "/templates", NewCreateHandler(r.TemplateCreator, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/export", NewExportHandler(r.TemplateBundler, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/import", NewImportHandler(r.TemplateBundler, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/templates/{template_id}", NewGetHandler(r.TemplateFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
-	m.Handle("PUT", "/templates/{template_id}", NewUpdateHandler(r.TemplateUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/templates/{template_id}", NewUpdateHandler(r.TemplateUpdater, r.TemplateUsageChecker, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("DELETE", "/templates/{template_id}", NewDeleteHandler(r.TemplateDeleter, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/versions", NewListVersionsHandler(r.TemplateVersioner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/versions/{version}", NewGetVersionHandler(r.TemplateVersioner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/diff", NewDiffHandler(r.TemplateVersioner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/{template_id}/rollback", NewRollbackHandler(r.TemplateVersioner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/preview", NewPreviewHandler(r.TemplateFinder, r.TemplatePreviewer, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator)
+	m.Handle("POST", "/templates/{template_id}/preview", NewPreviewHandler(r.TemplateFinder, r.TemplatePreviewer, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/templates/{template_id}/associations", NewListAssociationsHandler(r.TemplateAssociationLister, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
 }
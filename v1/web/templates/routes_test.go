@@ -23,10 +23,13 @@ var _ = Describe("Routes", func() {
 			ErrorWriter:               mocks.NewErrorWriter(),
 			TemplateFinder:            mocks.NewTemplateFinder(),
 			TemplateUpdater:           mocks.NewTemplateUpdater(),
+			TemplateUsageChecker:      mocks.NewTemplateUsageChecker(),
 			TemplateCreator:           mocks.NewTemplateCreator(),
 			TemplateDeleter:           mocks.NewTemplateDeleter(),
 			TemplateLister:            mocks.NewTemplateLister(),
 			TemplateAssociationLister: mocks.NewTemplateAssociationLister(),
+			TemplateVersioner:         mocks.NewTemplateVersioner(),
+			TemplateBundler:           mocks.NewTemplateBundler(),
 
 			RequestCounter:                          middleware.RequestCounter{},
 			RequestLogging:                          middleware.RequestLogging{},
@@ -100,6 +103,30 @@ var _ = Describe("Routes", func() {
 			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
 		})
 
+		It("routes GET /templates/export", func() {
+			request, err := http.NewRequest("GET", "/templates/export", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.ExportHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+		})
+
+		It("routes POST /templates/import", func() {
+			request, err := http.NewRequest("POST", "/templates/import", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.ImportHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+		})
+
 		It("routes GET /templates/{template_id}/associations", func() {
 			request, err := http.NewRequest("GET", "/templates/{template_id}/associations", nil)
 			Expect(err).NotTo(HaveOccurred())
@@ -111,6 +138,78 @@ var _ = Describe("Routes", func() {
 			authenticator := s.Middleware[2].(middleware.Authenticator)
 			Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
 		})
+
+		It("routes GET /templates/{template_id}/versions", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/versions", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.ListVersionsHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes GET /templates/{template_id}/versions/{version}", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/versions/{version}", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.GetVersionHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes GET /templates/{template_id}/diff", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/diff", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.DiffHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes POST /templates/{template_id}/rollback", func() {
+			request, err := http.NewRequest("POST", "/templates/{template_id}/rollback", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.RollbackHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
+		})
+
+		It("routes POST /templates/preview", func() {
+			request, err := http.NewRequest("POST", "/templates/preview", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.PreviewHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes POST /templates/{template_id}/preview", func() {
+			request, err := http.NewRequest("POST", "/templates/{template_id}/preview", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.PreviewHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
 	})
 
 	Describe("/default_template", func() {
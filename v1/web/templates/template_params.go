@@ -4,22 +4,29 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"text/template"
+	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/cloudfoundry-incubator/notifications/valiant"
 )
 
 type TemplateParams struct {
-	Name     string          `json:"name" validate-required:"true"`
-	Text     string          `json:"text"`
-	HTML     string          `json:"html" validate-required:"true"`
-	Subject  string          `json:"subject"`
-	Metadata json.RawMessage `json:"metadata"`
+	Name          string               `json:"name" validate-required:"true"`
+	Text          string               `json:"text"`
+	HTML          string               `json:"html" validate-required:"true"`
+	Subject       string               `json:"subject"`
+	Metadata      json.RawMessage      `json:"metadata"`
+	Localizations common.Localizations `json:"localizations"`
+	LayoutID      string               `json:"layout_id"`
+	Warnings      []string             `json:"-"`
+
+	DisableAutoText    bool `json:"disable_auto_text"`
+	DisableCSSInlining bool `json:"disable_css_inlining"`
 }
 
-func NewTemplateParams(body io.ReadCloser) (TemplateParams, error) {
+func NewTemplateParams(body io.ReadCloser, critical bool) (TemplateParams, error) {
 	defer body.Close()
 
 	var template TemplateParams
@@ -39,28 +46,38 @@ func NewTemplateParams(body io.ReadCloser) (TemplateParams, error) {
 		template.Metadata = json.RawMessage("{}")
 	}
 
-	err = template.validateSyntax()
+	_, err = common.ParseDataSchema(string(template.Metadata))
 	if err != nil {
-		return TemplateParams{}, err
+		return template, webutil.ValidationError{err}
 	}
 
 	template.setDefaults()
 
-	return template, nil
-}
+	templates := common.Templates{
+		Subject: template.Subject,
+		Text:    template.Text,
+		HTML:    template.HTML,
+	}
 
-func (t TemplateParams) validateSyntax() error {
-	toValidate := map[string]string{
-		"Subject": t.Subject,
-		"Text":    t.Text,
-		"HTML":    t.HTML,
+	template.Warnings, err = common.ValidateLocalizedTemplates(templates, template.Localizations, critical)
+	if err != nil {
+		return TemplateParams{}, err
 	}
 
-	for field, contents := range toValidate {
-		_, err := template.New("test").Parse(contents)
-		if err != nil {
-			return webutil.ValidationError{fmt.Errorf("%s syntax is malformed please check your braces", field)}
-		}
+	err = checkPartials(templates, template.Localizations)
+	if err != nil {
+		return TemplateParams{}, err
+	}
+
+	return template, nil
+}
+
+// checkPartials rejects templates that include partials, since only v2
+// clients own partials for the loader to resolve.
+func checkPartials(templates common.Templates, localizations common.Localizations) error {
+	names := common.UndefinedReferences(templates, localizations)
+	if len(names) > 0 {
+		return webutil.ValidationError{Err: fmt.Errorf("v1 templates cannot include partials: %s", strings.Join(names, ", "))}
 	}
 
 	return nil
@@ -68,14 +85,41 @@ func (t TemplateParams) validateSyntax() error {
 
 func (t TemplateParams) ToModel() models.Template {
 	return models.Template{
-		Name:     t.Name,
-		Text:     t.Text,
-		HTML:     t.HTML,
-		Subject:  t.Subject,
-		Metadata: string(t.Metadata),
+		Name:          t.Name,
+		Text:          t.Text,
+		HTML:          t.HTML,
+		Subject:       t.Subject,
+		Metadata:      string(t.Metadata),
+		Localizations: t.encodedLocalizations(),
+		LayoutID:      t.LayoutID,
+
+		DisableAutoText:    t.DisableAutoText,
+		DisableCSSInlining: t.DisableCSSInlining,
 	}
 }
 
+func (t TemplateParams) encodedLocalizations() string {
+	if len(t.Localizations) == 0 {
+		return "{}"
+	}
+
+	localizations, err := json.Marshal(t.Localizations)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(localizations)
+}
+
+func decodeLocalizations(localizations string) common.Localizations {
+	var decoded common.Localizations
+	if localizations != "" {
+		json.Unmarshal([]byte(localizations), &decoded)
+	}
+
+	return decoded
+}
+
 func (t *TemplateParams) setDefaults() {
 	if t.Subject == "" {
 		t.Subject = "{{.Subject}}"
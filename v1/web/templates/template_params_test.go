@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 
@@ -37,7 +38,7 @@ var _ = Describe("TemplateParams", func() {
 				})
 				Expect(err).NotTo(HaveOccurred())
 
-				parameters, err := templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+				parameters, err := templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)), false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(parameters.Name).To(Equal("Foo Bar Baz"))
 				Expect(parameters.Text).To(Equal("its foobar of course"))
@@ -53,7 +54,7 @@ var _ = Describe("TemplateParams", func() {
 				})
 				Expect(err).NotTo(HaveOccurred())
 
-				parameters, err := templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+				parameters, err := templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)), false)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(parameters.Name).To(Equal("Foo Bar Baz"))
 				Expect(parameters.Text).To(Equal(""))
@@ -71,8 +72,10 @@ var _ = Describe("TemplateParams", func() {
 							HTML:    "HTML template",
 							Subject: "{{.bad}",
 						})
-						_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
-						Expect(err).To(MatchError(webutil.ValidationError{errors.New("Subject syntax is malformed please check your braces")}))
+						_, err := templates.NewTemplateParams(ioutil.NopCloser(body), false)
+						Expect(err).To(BeAssignableToTypeOf(common.TemplateValidationError{}))
+						Expect(err.(common.TemplateValidationError).Errors).To(HaveLen(1))
+						Expect(err.(common.TemplateValidationError).Errors[0].Part).To(Equal("subject"))
 					})
 				})
 
@@ -84,8 +87,10 @@ var _ = Describe("TemplateParams", func() {
 							HTML:    "<h1> Amazing </h1>",
 							Subject: "Great Subject",
 						})
-						_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
-						Expect(err).To(MatchError(webutil.ValidationError{errors.New("Text syntax is malformed please check your braces")}))
+						_, err := templates.NewTemplateParams(ioutil.NopCloser(body), false)
+						Expect(err).To(BeAssignableToTypeOf(common.TemplateValidationError{}))
+						Expect(err.(common.TemplateValidationError).Errors).To(HaveLen(1))
+						Expect(err.(common.TemplateValidationError).Errors[0].Part).To(Equal("text"))
 					})
 				})
 
@@ -97,10 +102,59 @@ var _ = Describe("TemplateParams", func() {
 							HTML:    "{{.bad}",
 							Subject: "Great Subject",
 						})
-						_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
-						Expect(err).To(MatchError(webutil.ValidationError{errors.New("HTML syntax is malformed please check your braces")}))
+						_, err := templates.NewTemplateParams(ioutil.NopCloser(body), false)
+						Expect(err).To(BeAssignableToTypeOf(common.TemplateValidationError{}))
+						Expect(err.(common.TemplateValidationError).Errors).To(HaveLen(1))
+						Expect(err.(common.TemplateValidationError).Errors[0].Part).To(Equal("html"))
 					})
 				})
+
+				Context("when a template refers to a field that does not exist", func() {
+					It("returns a validation error", func() {
+						body := buildTemplateRequestBody(templates.TemplateParams{
+							Name:    "Template name",
+							Text:    "Hello {{.Txet}}",
+							HTML:    "<h1> Amazing </h1>",
+							Subject: "Great Subject",
+						})
+						_, err := templates.NewTemplateParams(ioutil.NopCloser(body), false)
+						Expect(err).To(BeAssignableToTypeOf(common.TemplateValidationError{}))
+						Expect(err.Error()).To(ContainSubstring("can't evaluate field Txet"))
+					})
+				})
+			})
+
+			It("returns a validation error when the metadata declares an unknown data type", func() {
+				body := buildTemplateRequestBody(templates.TemplateParams{
+					Name:     "Template name",
+					Text:     "Textual template",
+					HTML:     "HTML template",
+					Subject:  "Great Subject",
+					Metadata: json.RawMessage(`{"variables": {"app_name": {"type": "text"}}}`),
+				})
+				_, err := templates.NewTemplateParams(ioutil.NopCloser(body), false)
+				Expect(err).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+			})
+
+			It("warns when the template has no unsubscribe link", func() {
+				body := buildTemplateRequestBody(templates.TemplateParams{
+					Name: "Template name",
+					Text: "{{.Text}}",
+					HTML: "<p>{{.HTML}}</p>",
+				})
+				parameters, err := templates.NewTemplateParams(ioutil.NopCloser(body), false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.Warnings).To(ConsistOf(common.MissingUnsubscribeLinkWarning))
+			})
+
+			It("returns a validation error when the template includes a partial", func() {
+				body := buildTemplateRequestBody(templates.TemplateParams{
+					Name: "Template name",
+					Text: `{{.Text}} {{template "footer" .}}`,
+					HTML: `<body>{{template "content" .}}</body>`,
+				})
+				_, err := templates.NewTemplateParams(ioutil.NopCloser(body), false)
+				Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("v1 templates cannot include partials: footer")}))
 			})
 		})
 	})
@@ -113,6 +167,10 @@ var _ = Describe("TemplateParams", func() {
 				HTML:     "<p>its foobar</p>",
 				Subject:  "Foobar Yah",
 				Metadata: json.RawMessage(`{"some_property": "some_value"}`),
+				LayoutID: "some-layout-id",
+
+				DisableAutoText:    true,
+				DisableCSSInlining: true,
 			}
 			templateModel := templateParams.ToModel()
 
@@ -121,6 +179,9 @@ var _ = Describe("TemplateParams", func() {
 			Expect(templateModel.HTML).To(Equal("<p>its foobar</p>"))
 			Expect(templateModel.Subject).To(Equal("Foobar Yah"))
 			Expect(templateModel.Metadata).To(MatchJSON(`{"some_property": "some_value"}`))
+			Expect(templateModel.LayoutID).To(Equal("some-layout-id"))
+			Expect(templateModel.DisableAutoText).To(BeTrue())
+			Expect(templateModel.DisableCSSInlining).To(BeTrue())
 			Expect(templateModel.CreatedAt).To(BeZero())
 			Expect(templateModel.UpdatedAt).To(BeZero())
 		})
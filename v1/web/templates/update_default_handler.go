@@ -25,7 +25,7 @@ func NewUpdateDefaultHandler(updater templateUpdater, errWriter errorWriter) Upd
 }
 
 func (h UpdateDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
-	template, err := NewTemplateParams(req.Body)
+	template, err := NewTemplateParams(req.Body, false)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
@@ -36,5 +36,6 @@ func (h UpdateDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request
 		h.errorWriter.Write(w, err)
 	}
 
+	writeWarnings(w, template.Warnings)
 	w.WriteHeader(http.StatusNoContent)
 }
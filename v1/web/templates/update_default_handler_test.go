@@ -56,11 +56,12 @@ var _ = Describe("UpdateDefaultHandler", func() {
 		Expect(updater.UpdateCall.Receives.Database).To(Equal(database))
 		Expect(updater.UpdateCall.Receives.TemplateID).To(Equal(models.DefaultTemplateID))
 		Expect(updater.UpdateCall.Receives.Template).To(Equal(models.Template{
-			Name:     "Defaultish Template",
-			Subject:  "{{.Subject}}",
-			HTML:     "<p>something</p>",
-			Text:     "something",
-			Metadata: `{"hello": true}`,
+			Name:          "Defaultish Template",
+			Subject:       "{{.Subject}}",
+			HTML:          "<p>something</p>",
+			Text:          "something",
+			Metadata:      `{"hello": true}`,
+			Localizations: "{}",
 		}))
 	})
 
@@ -4,35 +4,50 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/ryanmoran/stack"
 )
 
+type templateUsageChecker interface {
+	UsedOnlyByCritical(connection collections.ConnectionInterface, templateID string) (bool, error)
+}
+
 type UpdateHandler struct {
 	updater     templateUpdater
+	checker     templateUsageChecker
 	errorWriter errorWriter
 }
 
-func NewUpdateHandler(updater templateUpdater, errWriter errorWriter) UpdateHandler {
+func NewUpdateHandler(updater templateUpdater, checker templateUsageChecker, errWriter errorWriter) UpdateHandler {
 	return UpdateHandler{
 		updater:     updater,
+		checker:     checker,
 		errorWriter: errWriter,
 	}
 }
 
 func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
 	templateID := strings.Split(req.URL.String(), "/templates/")[1]
+	database := context.Get("database").(DatabaseInterface)
+
+	critical, err := h.checker.UsedOnlyByCritical(database.Connection(), templateID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
 
-	templateParams, err := NewTemplateParams(req.Body)
+	templateParams, err := NewTemplateParams(req.Body, critical)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
 	}
 
-	err = h.updater.Update(context.Get("database").(DatabaseInterface), templateID, templateParams.ToModel())
+	err = h.updater.Update(database, templateID, templateParams.ToModel())
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
 	}
 
+	writeWarnings(w, templateParams.Warnings)
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -3,9 +3,11 @@ package templates_test
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
@@ -25,24 +27,29 @@ var _ = Describe("UpdateHandler", func() {
 		request     *http.Request
 		context     stack.Context
 		updater     *mocks.TemplateUpdater
+		checker     *mocks.TemplateUsageChecker
 		errorWriter *mocks.ErrorWriter
 		database    *mocks.Database
+		connection  *mocks.Connection
 	)
 
 	Describe("ServeHTTP", func() {
 		BeforeEach(func() {
 			updater = mocks.NewTemplateUpdater()
+			checker = mocks.NewTemplateUsageChecker()
 			errorWriter = mocks.NewErrorWriter()
 			writer = httptest.NewRecorder()
 			body := []byte(`{"name":"An Interesting Template", "subject":"very interesting subject", "text":"Here's the msg {{.Text}}", "html":"<p>turkey gobble</p>"}`)
 			request, err = http.NewRequest("PUT", "/templates/a-template-id", bytes.NewBuffer(body))
 			Expect(err).NotTo(HaveOccurred())
 
+			connection = mocks.NewConnection()
 			database = mocks.NewDatabase()
+			database.ConnectionCall.Returns.Connection = connection
 			context = stack.NewContext()
 			context.Set("database", database)
 
-			handler = templates.NewUpdateHandler(updater, errorWriter)
+			handler = templates.NewUpdateHandler(updater, checker, errorWriter)
 		})
 
 		It("calls update on its updater with appropriate arguments", func() {
@@ -52,14 +59,34 @@ var _ = Describe("UpdateHandler", func() {
 			Expect(updater.UpdateCall.Receives.Database).To(Equal(database))
 			Expect(updater.UpdateCall.Receives.TemplateID).To(Equal("a-template-id"))
 			Expect(updater.UpdateCall.Receives.Template).To(Equal(models.Template{
-				Name:     "An Interesting Template",
-				Subject:  "very interesting subject",
-				Text:     "Here's the msg {{.Text}}",
-				HTML:     "<p>turkey gobble</p>",
-				Metadata: "{}",
+				Name:          "An Interesting Template",
+				Subject:       "very interesting subject",
+				Text:          "Here's the msg {{.Text}}",
+				HTML:          "<p>turkey gobble</p>",
+				Metadata:      "{}",
+				Localizations: "{}",
 			}))
 		})
 
+		It("warns when the template has no unsubscribe link", func() {
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNoContent))
+			Expect(writer.HeaderMap["Warning"]).To(ConsistOf(fmt.Sprintf("299 - %q", common.MissingUnsubscribeLinkWarning)))
+
+			Expect(checker.UsedOnlyByCriticalCall.Receives.Connection).To(Equal(connection))
+			Expect(checker.UsedOnlyByCriticalCall.Receives.TemplateID).To(Equal("a-template-id"))
+		})
+
+		It("does not warn about the unsubscribe link when only critical notifications use the template", func() {
+			checker.UsedOnlyByCriticalCall.Returns.Critical = true
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNoContent))
+			Expect(writer.HeaderMap).NotTo(HaveKey("Warning"))
+		})
+
 		It("can update a template without a subject field", func() {
 			body := []byte(`{"name": "my template name", "html": "<p>gobble</p>", "text": "my awesome text"}`)
 			request, err = http.NewRequest("PUT", "/templates/a-template-id.", bytes.NewBuffer(body))
@@ -101,6 +128,16 @@ var _ = Describe("UpdateHandler", func() {
 				})
 			})
 
+			Describe("when the usage check returns an error", func() {
+				It("returns the error", func() {
+					checker.UsedOnlyByCriticalCall.Returns.Error = errors.New("some error")
+
+					handler.ServeHTTP(writer, request, context)
+					Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("some error")))
+					Expect(updater.UpdateCall.Receives.TemplateID).To(BeEmpty())
+				})
+			})
+
 			Describe("when the update returns an error", func() {
 				It("returns the error", func() {
 					updater.UpdateCall.Returns.Error = models.TemplateUpdateError{errors.New("some error")}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
@@ -17,16 +18,24 @@ func NewErrorWriter() ErrorWriter {
 }
 
 func (writer ErrorWriter) Write(w http.ResponseWriter, err error) {
+	messages := []string{err.Error()}
+
 	switch err.(type) {
-	case UAAScopesError, CriticalNotificationError, collections.TemplateAssignmentError, MissingUserTokenError, ValidationError:
+	case common.TemplateValidationError:
+		w.WriteHeader(422)
+		messages = err.(common.TemplateValidationError).Messages()
+	case common.DataValidationError:
+		w.WriteHeader(422)
+		messages = err.(common.DataValidationError).Messages
+	case UAAScopesError, CriticalNotificationError, collections.TemplateAssignmentError, collections.TemplateImportError, collections.TransportAssignmentError, MissingUserTokenError, ValidationError, common.TemplateCompileError, common.DataSchemaError, models.TemplateLayoutError:
 		w.WriteHeader(422)
 	case services.CCDownError:
 		w.WriteHeader(http.StatusBadGateway)
-	case services.CCNotFoundError, models.NotFoundError, cf.NotFoundError:
+	case services.CCNotFoundError, models.NotFoundError, cf.NotFoundError, services.InvalidUnsubscribeTokenError, services.MissingKindOrClientError:
 		w.WriteHeader(http.StatusNotFound)
 	case ParseError, SchemaError:
 		w.WriteHeader(http.StatusBadRequest)
-	case models.DuplicateError:
+	case models.DuplicateError, collections.TemplateInUseError:
 		w.WriteHeader(http.StatusConflict)
 	case services.DefaultScopeError:
 		w.WriteHeader(http.StatusNotAcceptable)
@@ -35,6 +44,6 @@ func (writer ErrorWriter) Write(w http.ResponseWriter, err error) {
 	}
 
 	json.NewEncoder(w).Encode(map[string][]string{
-		"errors": []string{err.Error()},
+		"errors": messages,
 	})
 }
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
@@ -90,6 +91,42 @@ var _ = Describe("ErrorWriter", func() {
 		}`))
 	})
 
+	It("returns a 422 when a template cannot be compiled", func() {
+		writer.Write(recorder, common.TemplateCompileError{Part: "text", Line: 2, Err: errors.New("template: text:2: unexpected EOF")})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["template: text:2: unexpected EOF"]
+		}`))
+	})
+
+	It("returns a 422 listing each problem when templates fail validation", func() {
+		writer.Write(recorder, common.TemplateValidationError{Errors: []common.TemplateCompileError{
+			{Part: "subject", Line: 1, Err: errors.New("template: subject:1: unexpected EOF")},
+			{Part: "html", Line: 3, Err: errors.New("template: html:3: function \"bad\" not defined")},
+		}})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": [
+				"template: subject:1: unexpected EOF",
+				"template: html:3: function \"bad\" not defined"
+			]
+		}`))
+	})
+
+	It("returns a 422 listing each problem when data does not satisfy the template schema", func() {
+		writer.Write(recorder, common.DataValidationError{Messages: []string{
+			"data.app_name is required",
+			"data.count must be a number",
+		}})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": [
+				"data.app_name is required",
+				"data.count must be a number"
+			]
+		}`))
+	})
+
 	It("returns a 422 when trying to send a critical notification without correct scope", func() {
 		writer.Write(recorder, webutil.NewCriticalNotificationError("raptors"))
 		Expect(recorder.Code).To(Equal(422))
@@ -106,6 +143,14 @@ var _ = Describe("ErrorWriter", func() {
 		}`))
 	})
 
+	It("returns a 409 when a template is still in use", func() {
+		writer.Write(recorder, collections.TemplateInUseError{Err: errors.New("template in use")})
+		Expect(recorder.Code).To(Equal(409))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["template in use"]
+		}`))
+	})
+
 	It("returns a 404 when a record cannot be found", func() {
 		writer.Write(recorder, models.NotFoundError{errors.New("not found")})
 		Expect(recorder.Code).To(Equal(404))
@@ -114,6 +159,14 @@ var _ = Describe("ErrorWriter", func() {
 		}`))
 	})
 
+	It("returns a 404 when an unsubscribe token is invalid", func() {
+		writer.Write(recorder, services.InvalidUnsubscribeTokenError{Err: errors.New("The unsubscribe token is invalid")})
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["The unsubscribe token is invalid"]
+		}`))
+	})
+
 	It("returns a 406 when a record cannot be found", func() {
 		writer.Write(recorder, services.DefaultScopeError{})
 		Expect(recorder.Code).To(Equal(406))
@@ -130,6 +183,30 @@ var _ = Describe("ErrorWriter", func() {
 		}`))
 	})
 
+	It("returns a 422 when a template bundle cannot be imported", func() {
+		writer.Write(recorder, collections.TemplateImportError{Err: errors.New("The bundle could not be imported")})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["The bundle could not be imported"]
+		}`))
+	})
+
+	It("returns a 422 when a transport cannot be assigned", func() {
+		writer.Write(recorder, collections.TransportAssignmentError{Err: errors.New("The transport could not be assigned")})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["The transport could not be assigned"]
+		}`))
+	})
+
+	It("returns a 422 when a template layout cannot be used", func() {
+		writer.Write(recorder, models.TemplateLayoutError{Err: errors.New(`Layout "some-layout-id" creates a circular reference`)})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["Layout \"some-layout-id\" creates a circular reference"]
+		}`))
+	})
+
 	It("returns a 422 when a user token was expected but is not present", func() {
 		writer.Write(recorder, webutil.MissingUserTokenError{errors.New("Missing user_id from token claims.")})
 		Expect(recorder.Code).To(Equal(422))
@@ -0,0 +1,142 @@
+package acceptance
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/acceptance/support"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Layouts and partials", func() {
+	var (
+		client *support.Client
+		token  string
+	)
+
+	BeforeEach(func() {
+		client = support.NewClient(support.Config{
+			Host:              Servers.Notifications.URL(),
+			Trace:             Trace,
+			RoundTripRecorder: roundtripRecorder,
+		})
+		var err error
+		token, err = GetClientTokenWithScopes("notifications.write")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("can set, retrieve, list and delete a partial", func() {
+		By("setting a partial", func() {
+			client.Document("partial-set")
+			status, response, err := client.Do("PUT", "/partials/footer", map[string]interface{}{
+				"text": "Sent by the platform team",
+				"html": "<p>Sent by the platform team</p>",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(response["name"]).To(Equal("footer"))
+			Expect(response["text"]).To(Equal("Sent by the platform team"))
+		})
+
+		By("getting the partial", func() {
+			client.Document("partial-get")
+			status, response, err := client.Do("GET", "/partials/footer", nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(response["html"]).To(Equal("<p>Sent by the platform team</p>"))
+		})
+
+		By("listing the partials", func() {
+			client.Document("partial-list")
+			status, response, err := client.Do("GET", "/partials", nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(response["partials"]).To(HaveLen(1))
+		})
+
+		By("failing to set a partial that includes itself", func() {
+			status, _, err := client.Do("PUT", "/partials/footer", map[string]interface{}{
+				"text": `{{template "footer" .}}`,
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(422))
+		})
+
+		By("deleting the partial", func() {
+			client.Document("partial-delete")
+			status, _, err := client.Do("DELETE", "/partials/footer", nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+
+			status, _, err = client.Do("GET", "/partials/footer", nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	It("wraps templates in layouts that include partials", func() {
+		var layoutID, templateID string
+
+		By("setting a partial", func() {
+			status, _, err := client.Do("PUT", "/partials/footer", map[string]interface{}{
+				"text": "-- the platform team",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+		})
+
+		By("creating a layout", func() {
+			status, response, err := client.Do("POST", "/templates", map[string]interface{}{
+				"name": "A layout",
+				"text": `{{template "content" .}}` + "\n" + `{{template "footer" .}}`,
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			layoutID = response["id"].(string)
+		})
+
+		By("creating a template wrapped in the layout", func() {
+			status, response, err := client.Do("POST", "/templates", map[string]interface{}{
+				"name":      "A wrapped template",
+				"text":      "{{.Text}}",
+				"layout_id": layoutID,
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+			Expect(response["layout_id"]).To(Equal(layoutID))
+
+			templateID = response["id"].(string)
+		})
+
+		By("previewing the wrapped template", func() {
+			status, response, err := client.Do("POST", fmt.Sprintf("/templates/%s/preview", templateID), map[string]interface{}{
+				"text": "your app is running",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(response["text"]).To(Equal("your app is running\n-- the platform team"))
+		})
+
+		By("failing to wrap the layout in the template that it wraps", func() {
+			status, _, err := client.Do("PUT", fmt.Sprintf("/templates/%s", layoutID), map[string]interface{}{
+				"layout_id": templateID,
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(422))
+		})
+
+		By("failing to delete the partial the layout includes", func() {
+			status, response, err := client.Do("DELETE", "/partials/footer", nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusConflict))
+			Expect(response["errors"]).To(ContainElement(`Partial "footer" is included by template "A layout" and cannot be deleted`))
+		})
+	})
+})
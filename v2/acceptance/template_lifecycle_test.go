@@ -181,6 +181,372 @@ var _ = Describe("Template lifecycle", func() {
 		})
 	})
 
+	It("keeps a history of template versions that can be diffed and rolled back", func() {
+		By("creating a template", func() {
+			status, response, err := client.Do("POST", "/templates", map[string]interface{}{
+				"name":    "A versioned template",
+				"text":    "first text",
+				"subject": "first subject",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			templateID = response["id"].(string)
+			Expect(response["version"]).To(Equal(float64(1)))
+		})
+
+		By("updating the template", func() {
+			status, response, err := client.Do("PUT", fmt.Sprintf("/templates/%s", templateID), map[string]interface{}{
+				"text":    "second text",
+				"subject": "second subject",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(response["version"]).To(Equal(float64(2)))
+		})
+
+		By("listing the versions", func() {
+			client.Document("template-version-list")
+			status, response, err := client.Do("GET", fmt.Sprintf("/templates/%s/versions", templateID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			versions := response["versions"].([]interface{})
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0].(map[string]interface{})["version"]).To(Equal(float64(2)))
+			Expect(versions[1].(map[string]interface{})["version"]).To(Equal(float64(1)))
+		})
+
+		By("getting the first version", func() {
+			client.Document("template-version-get")
+			status, response, err := client.Do("GET", fmt.Sprintf("/templates/%s/versions/1", templateID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(response["template_id"]).To(Equal(templateID))
+			Expect(response["version"]).To(Equal(float64(1)))
+			Expect(response["text"]).To(Equal("first text"))
+			Expect(response["subject"]).To(Equal("first subject"))
+		})
+
+		By("diffing the two versions", func() {
+			client.Document("template-diff")
+			status, response, err := client.Do("GET", fmt.Sprintf("/templates/%s/diff?from=1&to=2", templateID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(response["diff"]).To(Equal(map[string]interface{}{
+				"text":    "-first text\n+second text",
+				"subject": "-first subject\n+second subject",
+			}))
+		})
+
+		By("rolling back to the first version", func() {
+			client.Document("template-rollback")
+			status, response, err := client.Do("POST", fmt.Sprintf("/templates/%s/rollback", templateID), map[string]interface{}{
+				"version": 1,
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(response["version"]).To(Equal(float64(3)))
+			Expect(response["text"]).To(Equal("first text"))
+			Expect(response["subject"]).To(Equal("first subject"))
+		})
+
+		By("failing to get a version that does not exist", func() {
+			status, _, err := client.Do("GET", fmt.Sprintf("/templates/%s/versions/12", templateID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	It("reassigns campaign types to a replacement template when deleting one they use", func() {
+		var senderID, campaignTypeID, replacementID string
+
+		By("creating a template and its replacement", func() {
+			status, response, err := client.Do("POST", "/templates", map[string]interface{}{
+				"name": "An outgoing template",
+				"text": "outgoing text",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			templateID = response["id"].(string)
+
+			status, response, err = client.Do("POST", "/templates", map[string]interface{}{
+				"name": "An incoming template",
+				"text": "incoming text",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			replacementID = response["id"].(string)
+		})
+
+		By("creating a campaign type that uses the template", func() {
+			status, response, err := client.Do("POST", "/senders", map[string]interface{}{
+				"name": "my-sender",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			senderID = response["id"].(string)
+
+			status, response, err = client.Do("POST", fmt.Sprintf("/senders/%s/campaign_types", senderID), map[string]interface{}{
+				"name":        "some-campaign-type",
+				"description": "a campaign type",
+				"template_id": templateID,
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			campaignTypeID = response["id"].(string)
+		})
+
+		By("listing what uses the template", func() {
+			client.Document("template-associations")
+			status, response, err := client.Do("GET", fmt.Sprintf("/templates/%s/associations", templateID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			campaignTypes := response["campaign_types"].([]interface{})
+			Expect(campaignTypes).To(HaveLen(1))
+			Expect(campaignTypes[0].(map[string]interface{})["id"]).To(Equal(campaignTypeID))
+			Expect(response["campaigns"]).To(BeEmpty())
+		})
+
+		By("failing to delete the template without a replacement", func() {
+			status, response, err := client.Do("DELETE", fmt.Sprintf("/templates/%s", templateID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusConflict))
+			Expect(response["errors"]).To(ConsistOf(ContainSubstring("cannot be deleted without a replacement template")))
+		})
+
+		By("deleting the template with a replacement", func() {
+			status, _, err := client.Do("DELETE", fmt.Sprintf("/templates/%s?replacement_template_id=%s", templateID, replacementID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+
+		By("showing the campaign type now uses the replacement", func() {
+			status, response, err := client.Do("GET", fmt.Sprintf("/campaign_types/%s", campaignTypeID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(response["template_id"]).To(Equal(replacementID))
+		})
+	})
+
+	It("rewraps templates in a replacement layout when deleting the layout they use", func() {
+		var wrappedID, replacementID string
+
+		By("creating a layout, its replacement and a template wrapped in it", func() {
+			status, response, err := client.Do("POST", "/templates", map[string]interface{}{
+				"name": "An outgoing layout",
+				"text": `outgoing {{template "content" .}}`,
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			templateID = response["id"].(string)
+
+			status, response, err = client.Do("POST", "/templates", map[string]interface{}{
+				"name": "An incoming layout",
+				"text": `incoming {{template "content" .}}`,
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			replacementID = response["id"].(string)
+
+			status, response, err = client.Do("POST", "/templates", map[string]interface{}{
+				"name":      "A wrapped template",
+				"text":      "wrapped text",
+				"layout_id": templateID,
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			wrappedID = response["id"].(string)
+		})
+
+		By("listing the template that uses the layout", func() {
+			status, response, err := client.Do("GET", fmt.Sprintf("/templates/%s/associations", templateID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			templates := response["templates"].([]interface{})
+			Expect(templates).To(HaveLen(1))
+			Expect(templates[0].(map[string]interface{})["id"]).To(Equal(wrappedID))
+		})
+
+		By("failing to delete the layout without a replacement", func() {
+			status, response, err := client.Do("DELETE", fmt.Sprintf("/templates/%s", templateID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusConflict))
+			Expect(response["errors"]).To(ConsistOf(ContainSubstring("is used as a layout by other templates")))
+		})
+
+		By("deleting the layout with a replacement", func() {
+			status, _, err := client.Do("DELETE", fmt.Sprintf("/templates/%s?replacement_template_id=%s", templateID, replacementID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+
+		By("showing the template is now wrapped in the replacement", func() {
+			status, response, err := client.Do("GET", fmt.Sprintf("/templates/%s", wrappedID), nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(response["layout_id"]).To(Equal(replacementID))
+		})
+	})
+
+	It("sends campaigns with the default template of the client when nothing else names one", func() {
+		var senderID, campaignTypeID string
+
+		By("creating a template", func() {
+			status, response, err := client.Do("POST", "/templates", map[string]interface{}{
+				"name": "A client-wide template",
+				"text": "client-wide: {{.Text}}",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			templateID = response["id"].(string)
+		})
+
+		By("setting it as the default template of the client", func() {
+			client.Document("template-client-default-update")
+			status, response, err := client.Do("PUT", "/templates/client_default", map[string]interface{}{
+				"template_id": templateID,
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(response["template_id"]).To(Equal(templateID))
+		})
+
+		By("retrieving the default template of the client", func() {
+			client.Document("template-client-default-get")
+			status, response, err := client.Do("GET", "/templates/client_default", nil, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(response["template_id"]).To(Equal(templateID))
+		})
+
+		By("creating a campaign type without a template", func() {
+			status, response, err := client.Do("POST", "/senders", map[string]interface{}{
+				"name": "my-sender",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			senderID = response["id"].(string)
+
+			status, response, err = client.Do("POST", fmt.Sprintf("/senders/%s/campaign_types", senderID), map[string]interface{}{
+				"name":        "some-campaign-type",
+				"description": "a campaign type",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			campaignTypeID = response["id"].(string)
+		})
+
+		By("sending a campaign that does not name a template", func() {
+			status, response, err := client.Do("POST", fmt.Sprintf("/senders/%s/campaigns", senderID), map[string]interface{}{
+				"send_to": map[string][]string{
+					"emails": {"test@example.com"},
+				},
+				"campaign_type_id": campaignTypeID,
+				"text":             "campaign body",
+				"subject":          "campaign subject",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(response["template_id"]).To(Equal(templateID))
+			Expect(response["template_source"]).To(Equal("client"))
+		})
+
+		By("clearing the default template of the client", func() {
+			status, response, err := client.Do("PUT", "/templates/client_default", map[string]interface{}{
+				"template_id": nil,
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(response["template_id"]).To(BeNil())
+		})
+
+		By("failing to set a template that does not exist", func() {
+			status, _, err := client.Do("PUT", "/templates/client_default", map[string]interface{}{
+				"template_id": "missing-template-id",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	It("previews stored and inline templates without sending them", func() {
+		By("creating a template", func() {
+			status, response, err := client.Do("POST", "/templates", map[string]interface{}{
+				"name":    "A previewed template",
+				"text":    "{{.Text}} in {{.Space}}",
+				"html":    "<p>{{.HTML}}</p>",
+				"subject": "Preview: {{.Subject}}",
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+
+			templateID = response["id"].(string)
+		})
+
+		By("previewing the stored template", func() {
+			client.Document("template-preview")
+			status, response, err := client.Do("POST", fmt.Sprintf("/templates/%s/preview", templateID), map[string]interface{}{
+				"subject": "deploy finished",
+				"text":    "your app is running",
+				"html":    "your <b>app</b> is running",
+				"space": map[string]interface{}{
+					"guid": "some-space-guid",
+					"name": "production",
+				},
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(response["subject"]).To(Equal("Preview: deploy finished"))
+			Expect(response["text"]).To(Equal("your app is running in production"))
+			Expect(response["html"]).To(ContainSubstring("<p>your <b>app</b> is running</p>"))
+			Expect(response["mime"]).To(ContainSubstring("Subject: Preview: deploy finished"))
+		})
+
+		By("previewing an inline template", func() {
+			client.Document("template-preview-inline")
+			status, response, err := client.Do("POST", "/templates/preview", map[string]interface{}{
+				"text": "your app is running",
+				"template": map[string]interface{}{
+					"text": "Inline: {{.Text}}",
+				},
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(response["subject"]).To(Equal("[no subject]"))
+			Expect(response["text"]).To(Equal("Inline: your app is running"))
+		})
+
+		By("failing to preview a template that does not compile", func() {
+			status, response, err := client.Do("POST", "/templates/preview", map[string]interface{}{
+				"text": "your app is running",
+				"template": map[string]interface{}{
+					"text": "first line\n{{.Text",
+				},
+			}, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(422))
+			Expect(response["errors"]).To(ConsistOf(ContainSubstring("template: text:2:")))
+		})
+	})
+
 	Context("when omitting field values", func() {
 		It("uses the existing value", func() {
 			By("creating a template", func() {
@@ -292,6 +658,21 @@ var _ = Describe("Template lifecycle", func() {
 				Expect(status).To(Equal(422))
 				Expect(response["errors"]).To(ContainElement("Template \"name\" field cannot be empty"))
 			})
+
+			It("returns a 422 listing each part that does not compile", func() {
+				status, response, err := client.Do("POST", "/templates", map[string]interface{}{
+					"name":    "A broken template",
+					"text":    "hello {{.Txet}}",
+					"html":    "<p>{{.HTML</p>",
+					"subject": "template subject",
+				}, token)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status).To(Equal(422))
+				Expect(response["errors"]).To(ConsistOf(
+					ContainSubstring("template: text:1:"),
+					ContainSubstring("template: html:1:"),
+				))
+			})
 		})
 
 		Context("updating", func() {
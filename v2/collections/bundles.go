@@ -0,0 +1,489 @@
+package collections
+
+import "fmt"
+
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictRename    = "rename"
+
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportRenamed = "renamed"
+	ImportSkipped = "skipped"
+)
+
+// Bundle holds everything a client has configured, so that it can be copied
+// from one deployment to another.
+type Bundle struct {
+	Templates     []Template
+	Partials      []TemplatePartial
+	Senders       []Sender
+	CampaignTypes []CampaignType
+}
+
+// ImportOptions control how a bundle is imported. Conflict decides what
+// happens to records that already exist, matched through IDMap or by name.
+// IDMap maps IDs in the bundle to the IDs of existing records.
+type ImportOptions struct {
+	DryRun   bool
+	Conflict string
+	IDMap    map[string]string
+}
+
+type ImportedRecord struct {
+	Type     string
+	Name     string
+	SourceID string
+	ID       string
+	Action   string
+}
+
+type bundledTemplates interface {
+	Set(conn ConnectionInterface, template Template) (Template, error)
+	List(conn ConnectionInterface, clientID string) ([]Template, error)
+}
+
+type bundledPartials interface {
+	Set(conn ConnectionInterface, partial TemplatePartial) (TemplatePartial, error)
+	List(conn ConnectionInterface, clientID string) ([]TemplatePartial, error)
+}
+
+type bundledSenders interface {
+	Set(conn ConnectionInterface, sender Sender) (Sender, error)
+	List(conn ConnectionInterface, clientID string) ([]Sender, error)
+}
+
+type bundledCampaignTypes interface {
+	Set(conn ConnectionInterface, campaignType CampaignType, clientID string) (CampaignType, error)
+	List(conn ConnectionInterface, senderID, clientID string) ([]CampaignType, error)
+}
+
+type BundlesCollection struct {
+	templates     bundledTemplates
+	partials      bundledPartials
+	senders       bundledSenders
+	campaignTypes bundledCampaignTypes
+}
+
+func NewBundlesCollection(templates bundledTemplates, partials bundledPartials, senders bundledSenders, campaignTypes bundledCampaignTypes) BundlesCollection {
+	return BundlesCollection{
+		templates:     templates,
+		partials:      partials,
+		senders:       senders,
+		campaignTypes: campaignTypes,
+	}
+}
+
+func (c BundlesCollection) Export(conn ConnectionInterface, clientID string) (Bundle, error) {
+	var bundle Bundle
+
+	templates, err := c.templates.List(conn, clientID)
+	if err != nil {
+		return Bundle{}, err
+	}
+	bundle.Templates = templates
+
+	partials, err := c.partials.List(conn, clientID)
+	if err != nil {
+		return Bundle{}, err
+	}
+	bundle.Partials = partials
+
+	senders, err := c.senders.List(conn, clientID)
+	if err != nil {
+		return Bundle{}, err
+	}
+	bundle.Senders = senders
+
+	for _, sender := range senders {
+		campaignTypes, err := c.campaignTypes.List(conn, sender.ID, clientID)
+		if err != nil {
+			return Bundle{}, err
+		}
+
+		bundle.CampaignTypes = append(bundle.CampaignTypes, campaignTypes...)
+	}
+
+	return bundle, nil
+}
+
+// Import copies the records of a bundle into the client's own, within a
+// single transaction. References between records in the bundle are rewritten
+// to the IDs the records receive. A dry run performs every step and then
+// rolls the transaction back.
+func (c BundlesCollection) Import(conn ConnectionInterface, bundle Bundle, clientID string, options ImportOptions) ([]ImportedRecord, error) {
+	switch options.Conflict {
+	case "":
+		options.Conflict = ConflictSkip
+	case ConflictSkip, ConflictOverwrite, ConflictRename:
+	default:
+		return nil, ValidationError{fmt.Errorf("conflict must be one of %s, %s or %s", ConflictSkip, ConflictOverwrite, ConflictRename)}
+	}
+
+	transaction := conn.Transaction()
+	err := transaction.Begin()
+	if err != nil {
+		return nil, PersistenceError{err}
+	}
+
+	importer := bundleImporter{
+		collection: c,
+		conn:       transaction,
+		clientID:   clientID,
+		options:    options,
+		ids:        map[string]string{},
+	}
+
+	records, err := importer.importBundle(bundle)
+	if err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+
+	if options.DryRun {
+		err = transaction.Rollback()
+		if err != nil {
+			return nil, PersistenceError{err}
+		}
+
+		for i := range records {
+			if records[i].Action != ImportSkipped && records[i].Action != ImportUpdated {
+				records[i].ID = ""
+			}
+		}
+
+		return records, nil
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return nil, PersistenceError{err}
+	}
+
+	return records, nil
+}
+
+type bundleImporter struct {
+	collection BundlesCollection
+	conn       ConnectionInterface
+	clientID   string
+	options    ImportOptions
+	ids        map[string]string
+	records    []ImportedRecord
+}
+
+func (i *bundleImporter) importBundle(bundle Bundle) ([]ImportedRecord, error) {
+	err := i.importPartials(bundle.Partials)
+	if err != nil {
+		return nil, err
+	}
+
+	err = i.importTemplates(bundle.Templates)
+	if err != nil {
+		return nil, err
+	}
+
+	err = i.importSenders(bundle.Senders)
+	if err != nil {
+		return nil, err
+	}
+
+	err = i.importCampaignTypes(bundle.CampaignTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.records, nil
+}
+
+// importPartials matches partials by name only. Templates include partials
+// by name, so a conflicting partial is never renamed and is skipped instead.
+func (i *bundleImporter) importPartials(partials []TemplatePartial) error {
+	existing, err := i.collection.partials.List(i.conn, i.clientID)
+	if err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for _, partial := range existing {
+		names[partial.Name] = true
+	}
+
+	for _, partial := range partials {
+		action := ImportCreated
+		if names[partial.Name] {
+			if i.options.Conflict != ConflictOverwrite {
+				i.record("partial", partial.Name, "", "", ImportSkipped)
+				continue
+			}
+			action = ImportUpdated
+		}
+
+		partial.ClientID = i.clientID
+		_, err = i.collection.partials.Set(i.conn, partial)
+		if err != nil {
+			return importError("partial", partial.Name, err)
+		}
+
+		names[partial.Name] = true
+		i.record("partial", partial.Name, "", "", action)
+	}
+
+	return nil
+}
+
+func (i *bundleImporter) importTemplates(templates []Template) error {
+	existing, err := i.collection.templates.List(i.conn, i.clientID)
+	if err != nil {
+		return err
+	}
+
+	index := newBundleIndex()
+	for _, template := range existing {
+		index.add(template.ID, template.Name)
+	}
+
+	for _, template := range layoutsFirst(templates) {
+		sourceID := template.ID
+		template.ID = ""
+		template.ClientID = i.clientID
+		template.LayoutID = i.targetID(template.LayoutID)
+
+		action := ImportCreated
+		if id, ok := index.match(i.options.IDMap[sourceID], template.Name); ok {
+			switch i.options.Conflict {
+			case ConflictSkip:
+				i.ids[sourceID] = id
+				i.record("template", template.Name, sourceID, id, ImportSkipped)
+				continue
+			case ConflictOverwrite:
+				template.ID = id
+				action = ImportUpdated
+			case ConflictRename:
+				template.Name = index.uniqueName(template.Name)
+				action = ImportRenamed
+			}
+		}
+
+		saved, err := i.collection.templates.Set(i.conn, template)
+		if err != nil {
+			return importError("template", template.Name, err)
+		}
+
+		index.add(saved.ID, saved.Name)
+		i.ids[sourceID] = saved.ID
+		i.record("template", saved.Name, sourceID, saved.ID, action)
+	}
+
+	return nil
+}
+
+func (i *bundleImporter) importSenders(senders []Sender) error {
+	existing, err := i.collection.senders.List(i.conn, i.clientID)
+	if err != nil {
+		return err
+	}
+
+	index := newBundleIndex()
+	for _, sender := range existing {
+		index.add(sender.ID, sender.Name)
+	}
+
+	for _, sender := range senders {
+		sourceID := sender.ID
+		sender.ID = ""
+		sender.ClientID = i.clientID
+
+		action := ImportCreated
+		if id, ok := index.match(i.options.IDMap[sourceID], sender.Name); ok {
+			switch i.options.Conflict {
+			case ConflictSkip:
+				i.ids[sourceID] = id
+				i.record("sender", sender.Name, sourceID, id, ImportSkipped)
+				continue
+			case ConflictOverwrite:
+				sender.ID = id
+				action = ImportUpdated
+			case ConflictRename:
+				sender.Name = index.uniqueName(sender.Name)
+				action = ImportRenamed
+			}
+		}
+
+		saved, err := i.collection.senders.Set(i.conn, sender)
+		if err != nil {
+			return importError("sender", sender.Name, err)
+		}
+
+		index.add(saved.ID, saved.Name)
+		i.ids[sourceID] = saved.ID
+		i.record("sender", saved.Name, sourceID, saved.ID, action)
+	}
+
+	return nil
+}
+
+func (i *bundleImporter) importCampaignTypes(campaignTypes []CampaignType) error {
+	indexes := map[string]bundleIndex{}
+
+	for _, campaignType := range campaignTypes {
+		sourceID := campaignType.ID
+		campaignType.ID = ""
+		campaignType.SenderID = i.targetID(campaignType.SenderID)
+		campaignType.TemplateID = i.targetID(campaignType.TemplateID)
+
+		index, ok := indexes[campaignType.SenderID]
+		if !ok {
+			existing, err := i.collection.campaignTypes.List(i.conn, campaignType.SenderID, i.clientID)
+			if err != nil {
+				return importError("campaign type", campaignType.Name, err)
+			}
+
+			index = newBundleIndex()
+			for _, existingType := range existing {
+				index.add(existingType.ID, existingType.Name)
+			}
+			indexes[campaignType.SenderID] = index
+		}
+
+		action := ImportCreated
+		if id, ok := index.match(i.options.IDMap[sourceID], campaignType.Name); ok {
+			switch i.options.Conflict {
+			case ConflictSkip:
+				i.ids[sourceID] = id
+				i.record("campaign_type", campaignType.Name, sourceID, id, ImportSkipped)
+				continue
+			case ConflictOverwrite:
+				campaignType.ID = id
+				action = ImportUpdated
+			case ConflictRename:
+				campaignType.Name = index.uniqueName(campaignType.Name)
+				action = ImportRenamed
+			}
+		}
+
+		saved, err := i.collection.campaignTypes.Set(i.conn, campaignType, i.clientID)
+		if err != nil {
+			return importError("campaign type", campaignType.Name, err)
+		}
+
+		index.add(saved.ID, saved.Name)
+		i.ids[sourceID] = saved.ID
+		i.record("campaign_type", saved.Name, sourceID, saved.ID, action)
+	}
+
+	return nil
+}
+
+// targetID resolves a reference to a record in the bundle. References to
+// records outside the bundle are resolved through the ID map, or left as
+// they are.
+func (i *bundleImporter) targetID(sourceID string) string {
+	if sourceID == "" {
+		return ""
+	}
+
+	if id, ok := i.ids[sourceID]; ok {
+		return id
+	}
+
+	if id, ok := i.options.IDMap[sourceID]; ok {
+		return id
+	}
+
+	return sourceID
+}
+
+func (i *bundleImporter) record(recordType, name, sourceID, id, action string) {
+	i.records = append(i.records, ImportedRecord{
+		Type:     recordType,
+		Name:     name,
+		SourceID: sourceID,
+		ID:       id,
+		Action:   action,
+	})
+}
+
+type bundleIndex struct {
+	ids   map[string]bool
+	names map[string]string
+}
+
+func newBundleIndex() bundleIndex {
+	return bundleIndex{
+		ids:   map[string]bool{},
+		names: map[string]string{},
+	}
+}
+
+func (index bundleIndex) add(id, name string) {
+	index.ids[id] = true
+	index.names[name] = id
+}
+
+func (index bundleIndex) match(mappedID, name string) (string, bool) {
+	if mappedID != "" && index.ids[mappedID] {
+		return mappedID, true
+	}
+
+	id, ok := index.names[name]
+	return id, ok
+}
+
+func (index bundleIndex) uniqueName(name string) string {
+	candidate := fmt.Sprintf("%s (imported)", name)
+	for n := 2; ; n++ {
+		if _, taken := index.names[candidate]; !taken {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (imported %d)", name, n)
+	}
+}
+
+// layoutsFirst orders templates so that every layout in the bundle is
+// imported before the templates it wraps.
+func layoutsFirst(templates []Template) []Template {
+	positions := map[string]int{}
+	for position, template := range templates {
+		if template.ID != "" {
+			positions[template.ID] = position
+		}
+	}
+
+	var ordered []Template
+	visited := make([]bool, len(templates))
+
+	var visit func(position int)
+	visit = func(position int) {
+		if visited[position] {
+			return
+		}
+		visited[position] = true
+
+		if layout, ok := positions[templates[position].LayoutID]; ok && templates[position].LayoutID != "" {
+			visit(layout)
+		}
+
+		ordered = append(ordered, templates[position])
+	}
+
+	for position := range templates {
+		visit(position)
+	}
+
+	return ordered
+}
+
+func importError(recordType, name string, err error) error {
+	switch e := err.(type) {
+	case ValidationError:
+		return ValidationError{fmt.Errorf("%s %q: %s", recordType, name, e.Err)}
+	case NotFoundError:
+		return ValidationError{fmt.Errorf("%s %q: %s", recordType, name, e.Err)}
+	case DuplicateRecordError:
+		return ValidationError{fmt.Errorf("%s %q: %s", recordType, name, e.Err)}
+	default:
+		return err
+	}
+}
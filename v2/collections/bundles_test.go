@@ -0,0 +1,223 @@
+package collections_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BundlesCollection", func() {
+	var (
+		conn                    *mocks.Connection
+		transaction             *mocks.Transaction
+		templatesCollection     *mocks.TemplatesCollection
+		partialsCollection      *mocks.TemplatePartialsCollection
+		sendersCollection       *mocks.SendersCollection
+		campaignTypesCollection *mocks.CampaignTypesCollection
+		collection              collections.BundlesCollection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
+
+		templatesCollection = mocks.NewTemplatesCollection()
+		partialsCollection = mocks.NewTemplatePartialsCollection()
+		sendersCollection = mocks.NewSendersCollection()
+		campaignTypesCollection = mocks.NewCampaignTypesCollection()
+
+		collection = collections.NewBundlesCollection(templatesCollection, partialsCollection, sendersCollection, campaignTypesCollection)
+	})
+
+	Describe("Export", func() {
+		It("collects the client's templates, partials, senders and campaign types", func() {
+			templatesCollection.ListCall.Returns.Templates = []collections.Template{{ID: "some-template-id", Name: "some-template"}}
+			partialsCollection.ListCall.Returns.Partials = []collections.TemplatePartial{{Name: "footer"}}
+			sendersCollection.ListCall.Returns.SenderList = []collections.Sender{{ID: "some-sender-id", Name: "some-sender"}}
+			campaignTypesCollection.ListCall.Returns.CampaignTypeList = []collections.CampaignType{{ID: "some-campaign-type-id", SenderID: "some-sender-id"}}
+
+			bundle, err := collection.Export(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bundle).To(Equal(collections.Bundle{
+				Templates:     []collections.Template{{ID: "some-template-id", Name: "some-template"}},
+				Partials:      []collections.TemplatePartial{{Name: "footer"}},
+				Senders:       []collections.Sender{{ID: "some-sender-id", Name: "some-sender"}},
+				CampaignTypes: []collections.CampaignType{{ID: "some-campaign-type-id", SenderID: "some-sender-id"}},
+			}))
+
+			Expect(templatesCollection.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+			Expect(campaignTypesCollection.ListCall.Receives.SenderID).To(Equal("some-sender-id"))
+			Expect(campaignTypesCollection.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+		})
+
+		It("returns the error when a collection cannot be listed", func() {
+			sendersCollection.ListCall.Returns.Error = collections.PersistenceError{Err: errors.New("no senders")}
+
+			_, err := collection.Export(conn, "some-client-id")
+			Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("no senders")}))
+		})
+	})
+
+	Describe("Import", func() {
+		var bundle collections.Bundle
+
+		BeforeEach(func() {
+			bundle = collections.Bundle{
+				Templates: []collections.Template{
+					{ID: "source-template-id", Name: "welcome", LayoutID: "source-layout-id"},
+					{ID: "source-layout-id", Name: "layout", HTML: `{{template "content" .}}`},
+				},
+				Senders: []collections.Sender{
+					{ID: "source-sender-id", Name: "billing"},
+				},
+				CampaignTypes: []collections.CampaignType{
+					{ID: "source-campaign-type-id", Name: "invoices", SenderID: "source-sender-id", TemplateID: "source-template-id"},
+				},
+			}
+
+			templatesCollection.SetCall.Returns.Templates = []collections.Template{
+				{ID: "new-layout-id", Name: "layout"},
+				{ID: "new-template-id", Name: "welcome"},
+			}
+			sendersCollection.SetCall.Returns.Sender = collections.Sender{ID: "new-sender-id", Name: "billing"}
+			campaignTypesCollection.SetCall.Returns.CampaignType = collections.CampaignType{ID: "new-campaign-type-id", Name: "invoices"}
+		})
+
+		It("creates each record and rewrites the references between them", func() {
+			records, err := collection.Import(conn, bundle, "some-client-id", collections.ImportOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal([]collections.ImportedRecord{
+				{Type: "template", Name: "layout", SourceID: "source-layout-id", ID: "new-layout-id", Action: "created"},
+				{Type: "template", Name: "welcome", SourceID: "source-template-id", ID: "new-template-id", Action: "created"},
+				{Type: "sender", Name: "billing", SourceID: "source-sender-id", ID: "new-sender-id", Action: "created"},
+				{Type: "campaign_type", Name: "invoices", SourceID: "source-campaign-type-id", ID: "new-campaign-type-id", Action: "created"},
+			}))
+
+			Expect(templatesCollection.SetCall.Receives.Templates).To(Equal([]collections.Template{
+				{Name: "layout", ClientID: "some-client-id", HTML: `{{template "content" .}}`},
+				{Name: "welcome", ClientID: "some-client-id", LayoutID: "new-layout-id"},
+			}))
+			Expect(sendersCollection.SetCall.Receives.Sender).To(Equal(collections.Sender{Name: "billing", ClientID: "some-client-id"}))
+			Expect(campaignTypesCollection.SetCall.Receives.CampaignType).To(Equal(collections.CampaignType{
+				Name:       "invoices",
+				SenderID:   "new-sender-id",
+				TemplateID: "new-template-id",
+			}))
+			Expect(campaignTypesCollection.ListCall.Receives.SenderID).To(Equal("new-sender-id"))
+
+			Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(transaction))
+			Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+		})
+
+		It("rolls back a dry run and leaves out the IDs that were never kept", func() {
+			records, err := collection.Import(conn, bundle, "some-client-id", collections.ImportOptions{DryRun: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(HaveLen(4))
+			for _, record := range records {
+				Expect(record.Action).To(Equal("created"))
+				Expect(record.ID).To(BeEmpty())
+			}
+
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+		})
+
+		Context("when records already exist", func() {
+			BeforeEach(func() {
+				bundle.Templates = bundle.Templates[1:]
+				bundle.CampaignTypes = nil
+				templatesCollection.ListCall.Returns.Templates = []collections.Template{{ID: "existing-layout-id", Name: "layout"}}
+				sendersCollection.ListCall.Returns.SenderList = []collections.Sender{{ID: "existing-sender-id", Name: "payments"}}
+			})
+
+			It("skips them by default", func() {
+				bundle.Senders = nil
+
+				records, err := collection.Import(conn, bundle, "some-client-id", collections.ImportOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(records).To(Equal([]collections.ImportedRecord{
+					{Type: "template", Name: "layout", SourceID: "source-layout-id", ID: "existing-layout-id", Action: "skipped"},
+				}))
+				Expect(templatesCollection.SetCall.Receives.Templates).To(BeEmpty())
+			})
+
+			It("overwrites them", func() {
+				templatesCollection.SetCall.Returns.Templates = []collections.Template{{ID: "existing-layout-id", Name: "layout"}}
+				bundle.Senders = nil
+
+				records, err := collection.Import(conn, bundle, "some-client-id", collections.ImportOptions{Conflict: "overwrite"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(records).To(Equal([]collections.ImportedRecord{
+					{Type: "template", Name: "layout", SourceID: "source-layout-id", ID: "existing-layout-id", Action: "updated"},
+				}))
+				Expect(templatesCollection.SetCall.Receives.Template.ID).To(Equal("existing-layout-id"))
+			})
+
+			It("renames the records being imported", func() {
+				templatesCollection.ListCall.Returns.Templates = append(templatesCollection.ListCall.Returns.Templates, collections.Template{ID: "other-id", Name: "layout (imported)"})
+				templatesCollection.SetCall.Returns.Templates = []collections.Template{{ID: "new-layout-id", Name: "layout (imported 2)"}}
+				bundle.Senders = nil
+
+				records, err := collection.Import(conn, bundle, "some-client-id", collections.ImportOptions{Conflict: "rename"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(records).To(Equal([]collections.ImportedRecord{
+					{Type: "template", Name: "layout (imported 2)", SourceID: "source-layout-id", ID: "new-layout-id", Action: "renamed"},
+				}))
+				Expect(templatesCollection.SetCall.Receives.Template.Name).To(Equal("layout (imported 2)"))
+				Expect(templatesCollection.SetCall.Receives.Template.ID).To(BeEmpty())
+			})
+
+			It("matches records through the ID map before their names", func() {
+				records, err := collection.Import(conn, bundle, "some-client-id", collections.ImportOptions{
+					IDMap: map[string]string{"source-sender-id": "existing-sender-id"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(records).To(ContainElement(collections.ImportedRecord{
+					Type: "sender", Name: "billing", SourceID: "source-sender-id", ID: "existing-sender-id", Action: "skipped",
+				}))
+				Expect(sendersCollection.SetCall.Receives.Senders).To(BeEmpty())
+			})
+		})
+
+		It("overwrites partials with the same name and otherwise skips them", func() {
+			partialsCollection.ListCall.Returns.Partials = []collections.TemplatePartial{{Name: "footer"}}
+			bundle = collections.Bundle{
+				Partials: []collections.TemplatePartial{{Name: "footer", HTML: "<p>new</p>"}, {Name: "header"}},
+			}
+
+			records, err := collection.Import(conn, bundle, "some-client-id", collections.ImportOptions{Conflict: "rename"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal([]collections.ImportedRecord{
+				{Type: "partial", Name: "footer", Action: "skipped"},
+				{Type: "partial", Name: "header", Action: "created"},
+			}))
+
+			records, err = collection.Import(conn, bundle, "some-client-id", collections.ImportOptions{Conflict: "overwrite"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records[0]).To(Equal(collections.ImportedRecord{Type: "partial", Name: "footer", Action: "updated"}))
+			Expect(partialsCollection.SetCall.Receives.Partials).To(ContainElement(collections.TemplatePartial{Name: "footer", ClientID: "some-client-id", HTML: "<p>new</p>"}))
+		})
+
+		It("rejects an unknown conflict strategy", func() {
+			_, err := collection.Import(conn, bundle, "some-client-id", collections.ImportOptions{Conflict: "merge"})
+			Expect(err).To(MatchError(collections.ValidationError{Err: errors.New("conflict must be one of skip, overwrite or rename")}))
+			Expect(transaction.BeginCall.WasCalled).To(BeFalse())
+		})
+
+		It("rolls back and names the record that could not be imported", func() {
+			sendersCollection.SetCall.Returns.Error = collections.NotFoundError{Err: errors.New(`Transport "billing-smtp" could not be found`)}
+
+			_, err := collection.Import(conn, bundle, "some-client-id", collections.ImportOptions{})
+			Expect(err).To(MatchError(collections.ValidationError{Err: errors.New(`sender "billing": Transport "billing-smtp" could not be found`)}))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+	})
+})
@@ -28,6 +28,8 @@ type messageCountGetter interface {
 type CampaignStatus struct {
 	CampaignID            string
 	Status                string
+	TemplateID            string
+	TemplateVersion       int
 	TotalMessages         int
 	SentMessages          int
 	QueuedMessages        int
@@ -99,6 +101,8 @@ func (csc CampaignStatusesCollection) Get(conn ConnectionInterface, campaignID,
 	return CampaignStatus{
 		CampaignID:            campaign.ID,
 		Status:                status,
+		TemplateID:            campaign.TemplateID,
+		TemplateVersion:       campaign.TemplateVersion,
 		TotalMessages:         counts.Total,
 		SentMessages:          counts.Delivered,
 		FailedMessages:        counts.Failed,
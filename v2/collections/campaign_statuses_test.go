@@ -72,12 +72,17 @@ var _ = Describe("CampaignStatusesCollection", func() {
 		})
 
 		It("returns the status of the campaign", func() {
+			campaignsRepository.GetCall.Returns.Campaign.TemplateID = "template-id"
+			campaignsRepository.GetCall.Returns.Campaign.TemplateVersion = 2
+
 			campaignStatus, err := campaignStatusesCollection.Get(conn, "campaign-id", "client-id")
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(campaignStatus).To(Equal(collections.CampaignStatus{
 				CampaignID:            "campaign-id",
 				Status:                "completed",
+				TemplateID:            "template-id",
+				TemplateVersion:       2,
 				TotalMessages:         4,
 				SentMessages:          1,
 				FailedMessages:        1,
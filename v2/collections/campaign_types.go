@@ -1,6 +1,7 @@
 package collections
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/cloudfoundry-incubator/notifications/v2/models"
@@ -11,8 +12,12 @@ type CampaignType struct {
 	Name        string
 	Description string
 	Critical    bool
+	Threading   bool
 	TemplateID  string
 	SenderID    string
+	Headers     map[string]string
+	FromAddress string
+	FromName    string
 }
 
 type CampaignTypesCollection struct {
@@ -60,8 +65,12 @@ func (nc CampaignTypesCollection) Set(conn ConnectionInterface, campaignType Cam
 			Name:        campaignType.Name,
 			Description: campaignType.Description,
 			Critical:    campaignType.Critical,
+			Threading:   campaignType.Threading,
 			TemplateID:  campaignType.TemplateID,
 			SenderID:    campaignType.SenderID,
+			Headers:     marshalHeaders(campaignType.Headers),
+			FromAddress: campaignType.FromAddress,
+			FromName:    campaignType.FromName,
 		}
 	)
 
@@ -82,14 +91,7 @@ func (nc CampaignTypesCollection) Set(conn ConnectionInterface, campaignType Cam
 		}
 	}
 
-	return CampaignType{
-		ID:          returnCampaignType.ID,
-		Name:        returnCampaignType.Name,
-		Description: returnCampaignType.Description,
-		Critical:    returnCampaignType.Critical,
-		TemplateID:  returnCampaignType.TemplateID,
-		SenderID:    returnCampaignType.SenderID,
-	}, nil
+	return newCampaignType(returnCampaignType)
 }
 
 func (nc CampaignTypesCollection) Get(conn ConnectionInterface, campaignTypeID, clientID string) (CampaignType, error) {
@@ -109,14 +111,7 @@ func (nc CampaignTypesCollection) Get(conn ConnectionInterface, campaignTypeID,
 		return CampaignType{}, err
 	}
 
-	return CampaignType{
-		ID:          campaignType.ID,
-		Name:        campaignType.Name,
-		Description: campaignType.Description,
-		Critical:    campaignType.Critical,
-		TemplateID:  campaignType.TemplateID,
-		SenderID:    campaignType.SenderID,
-	}, nil
+	return newCampaignType(campaignType)
 }
 
 func (nc CampaignTypesCollection) List(conn ConnectionInterface, senderID, clientID string) ([]CampaignType, error) {
@@ -134,14 +129,11 @@ func (nc CampaignTypesCollection) List(conn ConnectionInterface, senderID, clien
 	campaignTypeList := []CampaignType{}
 
 	for _, model := range modelList {
-		campaignType := CampaignType{
-			ID:          model.ID,
-			Name:        model.Name,
-			Description: model.Description,
-			Critical:    model.Critical,
-			TemplateID:  model.TemplateID,
-			SenderID:    model.SenderID,
+		campaignType, err := newCampaignType(model)
+		if err != nil {
+			return []CampaignType{}, err
 		}
+
 		campaignTypeList = append(campaignTypeList, campaignType)
 	}
 
@@ -168,6 +160,57 @@ func (c CampaignTypesCollection) Delete(conn ConnectionInterface, campaignTypeID
 	return c.campaignTypesRepository.Delete(conn, campaignType)
 }
 
+func marshalHeaders(headers map[string]string) string {
+	if headers == nil {
+		headers = map[string]string{}
+	}
+
+	output, err := json.Marshal(headers)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(output)
+}
+
+func unmarshalHeaders(headers string) (map[string]string, error) {
+	var output map[string]string
+	if headers == "" {
+		return output, nil
+	}
+
+	err := json.Unmarshal([]byte(headers), &output)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	return output, nil
+}
+
+func newCampaignType(model models.CampaignType) (CampaignType, error) {
+	headers, err := unmarshalHeaders(model.Headers)
+	if err != nil {
+		return CampaignType{}, PersistenceError{fmt.Errorf("Campaign type %q has malformed headers: %s", model.ID, err)}
+	}
+
+	return CampaignType{
+		ID:          model.ID,
+		Name:        model.Name,
+		Description: model.Description,
+		Critical:    model.Critical,
+		Threading:   model.Threading,
+		TemplateID:  model.TemplateID,
+		SenderID:    model.SenderID,
+		Headers:     headers,
+		FromAddress: model.FromAddress,
+		FromName:    model.FromName,
+	}, nil
+}
+
 func validateSender(clientID, senderID string, sender models.Sender, err error) error {
 	if err != nil {
 		switch err.(type) {
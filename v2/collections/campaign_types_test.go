@@ -156,6 +156,7 @@ var _ = Describe("CampaignTypesCollection", func() {
 				Critical:    false,
 				TemplateID:  "",
 				SenderID:    "mysender",
+				Headers:     "{}",
 			}))
 		})
 
@@ -179,9 +180,40 @@ var _ = Describe("CampaignTypesCollection", func() {
 				Critical:    false,
 				TemplateID:  "",
 				SenderID:    "mysender",
+				Headers:     "{}",
 			}))
 		})
 
+		It("stores the default headers for the campaign type", func() {
+			fakeSendersRepository.GetCall.Returns.Sender = models.Sender{
+				ID:       "mysender",
+				Name:     "some-sender",
+				ClientID: "client-id",
+			}
+			fakeCampaignTypesRepository.InsertCall.Returns.CampaignType.Headers = `{"Importance":"high"}`
+			campaignType.Headers = map[string]string{"Importance": "high"}
+
+			returnedCampaignType, err := campaignTypesCollection.Set(fakeDatabaseConnection, campaignType, "client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeCampaignTypesRepository.InsertCall.Receives.CampaignType.Headers).To(Equal(`{"Importance":"high"}`))
+			Expect(returnedCampaignType.Headers).To(Equal(map[string]string{"Importance": "high"}))
+		})
+
+		It("stores whether the campaign type opts into threading", func() {
+			fakeSendersRepository.GetCall.Returns.Sender = models.Sender{
+				ID:       "mysender",
+				Name:     "some-sender",
+				ClientID: "client-id",
+			}
+			fakeCampaignTypesRepository.InsertCall.Returns.CampaignType.Threading = true
+			campaignType.Threading = true
+
+			returnedCampaignType, err := campaignTypesCollection.Set(fakeDatabaseConnection, campaignType, "client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeCampaignTypesRepository.InsertCall.Receives.CampaignType.Threading).To(BeTrue())
+			Expect(returnedCampaignType.Threading).To(BeTrue())
+		})
+
 		Context("failure cases", func() {
 			It("generates a not found error when the sender does not exist", func() {
 				recordNotFoundErr := models.NewRecordNotFoundError("sender with sender ID ROBOTS not found")
@@ -340,6 +372,22 @@ var _ = Describe("CampaignTypesCollection", func() {
 					Err: errors.New("BOOM!"),
 				}))
 			})
+
+			It("returns a persistence error when the stored headers are malformed", func() {
+				fakeCampaignTypesRepository.GetCall.Returns.CampaignType = models.CampaignType{
+					ID:       "some-campaign-type-id",
+					SenderID: "some-sender-id",
+					Headers:  `{"Importance":`,
+				}
+				fakeSendersRepository.GetCall.Returns.Sender = models.Sender{
+					ID:       "some-sender-id",
+					ClientID: "some-client-id",
+				}
+
+				_, err := campaignTypesCollection.Get(fakeDatabaseConnection, "some-campaign-type-id", "some-client-id")
+				Expect(err).To(BeAssignableToTypeOf(collections.PersistenceError{}))
+				Expect(err.Error()).To(HavePrefix(`Campaign type "some-campaign-type-id" has malformed headers`))
+			})
 		})
 	})
 
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v2/models"
 )
 
@@ -23,6 +26,10 @@ type campaignTypesGetter interface {
 	Get(conn models.ConnectionInterface, campaignTypeID string) (models.CampaignType, error)
 }
 
+type clientTemplatesGetter interface {
+	Get(conn models.ConnectionInterface, clientID string) (models.ClientTemplate, error)
+}
+
 type templatesGetter interface {
 	Get(conn models.ConnectionInterface, templateID string) (models.Template, error)
 }
@@ -31,35 +38,80 @@ type sendersGetter interface {
 	Get(conn models.ConnectionInterface, senderID string) (models.Sender, error)
 }
 
+type attachmentsPersister interface {
+	Insert(conn models.ConnectionInterface, attachment models.Attachment) (models.Attachment, error)
+}
+
+// A campaign's template is chosen by the first of these that names one: the
+// campaign itself, its campaign type, its client's default template and
+// finally the default template.
+const (
+	TemplateSourceCampaign     = "campaign"
+	TemplateSourceCampaignType = "campaign_type"
+	TemplateSourceClient       = "client"
+	TemplateSourceDefault      = "default"
+)
+
+type Attachment struct {
+	ID          string
+	Filename    string
+	ContentType string
+	ContentID   string
+	Content     []byte
+}
+
 type Campaign struct {
-	ID             string
-	SendTo         map[string][]string
-	CampaignTypeID string
-	Text           string
-	HTML           string
-	Subject        string
-	TemplateID     string
-	ReplyTo        string
-	SenderID       string
-	ClientID       string
-	StartTime      time.Time
+	ID              string
+	SendTo          map[string][]string
+	CampaignTypeID  string
+	Text            string
+	HTML            string
+	Subject         string
+	TemplateID      string
+	TemplateVersion int
+	TemplateSource  string
+	ReplyTo         string
+	SenderID        string
+	ClientID        string
+	StartTime       time.Time
+	Attachments     []Attachment
+	ThreadKey       string
+	Headers         map[string]string
+	From            string
+	Transport       string
+	Localizations   map[string]CampaignLocalization
+	Data            map[string]interface{}
+	RecipientData   map[string]map[string]interface{}
+}
+
+// CampaignLocalization is the content of a campaign written for a single
+// locale. Recipients whose locale matches receive it in place of the
+// campaign's own subject, text and html.
+type CampaignLocalization struct {
+	Subject string
+	Text    string
+	HTML    string
 }
 
 type CampaignsCollection struct {
-	enqueuer          campaignEnqueuer
-	campaignsRepo     campaignsPersister
-	campaignTypesRepo campaignTypesGetter
-	templatesRepo     templatesGetter
-	sendersRepo       sendersGetter
+	enqueuer            campaignEnqueuer
+	campaignsRepo       campaignsPersister
+	campaignTypesRepo   campaignTypesGetter
+	clientTemplatesRepo clientTemplatesGetter
+	templatesRepo       templatesGetter
+	sendersRepo         sendersGetter
+	attachmentsRepo     attachmentsPersister
 }
 
-func NewCampaignsCollection(enqueuer campaignEnqueuer, campaignsRepo campaignsPersister, campaignTypesRepo campaignTypesGetter, templatesRepo templatesGetter, sendersRepo sendersGetter) CampaignsCollection {
+func NewCampaignsCollection(enqueuer campaignEnqueuer, campaignsRepo campaignsPersister, campaignTypesRepo campaignTypesGetter, clientTemplatesRepo clientTemplatesGetter, templatesRepo templatesGetter, sendersRepo sendersGetter, attachmentsRepo attachmentsPersister) CampaignsCollection {
 	return CampaignsCollection{
-		enqueuer:          enqueuer,
-		campaignsRepo:     campaignsRepo,
-		campaignTypesRepo: campaignTypesRepo,
-		templatesRepo:     templatesRepo,
-		sendersRepo:       sendersRepo,
+		enqueuer:            enqueuer,
+		campaignsRepo:       campaignsRepo,
+		campaignTypesRepo:   campaignTypesRepo,
+		clientTemplatesRepo: clientTemplatesRepo,
+		templatesRepo:       templatesRepo,
+		sendersRepo:         sendersRepo,
+		attachmentsRepo:     attachmentsRepo,
 	}
 }
 
@@ -105,15 +157,21 @@ func (c CampaignsCollection) Create(conn ConnectionInterface, campaign Campaign,
 		return Campaign{}, PermissionsError{errors.New("Scope critical_notifications.write is required")}
 	}
 
-	if campaign.TemplateID == "" {
-		campaign.TemplateID = campaignType.TemplateID
+	defaults, err := newCampaignType(campaignType)
+	if err != nil {
+		return Campaign{}, err
 	}
 
-	if campaign.TemplateID == "" {
-		campaign.TemplateID = models.DefaultTemplate.ID
+	campaign.Headers = mail.MergeHeaders(defaults.Headers, campaign.Headers)
+	campaign.From = fromAddress(sender, campaignType)
+	campaign.Transport = sender.Transport
+
+	campaign.TemplateID, campaign.TemplateSource, err = c.resolveTemplateID(conn, campaign.TemplateID, campaignType, clientID)
+	if err != nil {
+		return Campaign{}, err
 	}
 
-	_, err = c.templatesRepo.Get(conn, campaign.TemplateID)
+	template, err := c.templatesRepo.Get(conn, campaign.TemplateID)
 	if err != nil {
 		switch err.(type) {
 		case models.RecordNotFoundError:
@@ -123,21 +181,30 @@ func (c CampaignsCollection) Create(conn ConnectionInterface, campaign Campaign,
 		}
 	}
 
+	campaign.TemplateVersion = template.Version
+
+	err = checkCampaignData(template, campaign)
+	if err != nil {
+		return Campaign{}, err
+	}
+
 	sendTo, err := json.Marshal(campaign.SendTo)
 	if err != nil {
 		panic(err)
 	}
 
 	campaignModel, err := c.campaignsRepo.Insert(conn, models.Campaign{
-		SendTo:         string(sendTo),
-		CampaignTypeID: campaign.CampaignTypeID,
-		Text:           campaign.Text,
-		HTML:           campaign.HTML,
-		Subject:        campaign.Subject,
-		TemplateID:     campaign.TemplateID,
-		ReplyTo:        campaign.ReplyTo,
-		SenderID:       campaign.SenderID,
-		StartTime:      campaign.StartTime,
+		SendTo:          string(sendTo),
+		CampaignTypeID:  campaign.CampaignTypeID,
+		Text:            campaign.Text,
+		HTML:            campaign.HTML,
+		Subject:         campaign.Subject,
+		TemplateID:      campaign.TemplateID,
+		TemplateVersion: campaign.TemplateVersion,
+		TemplateSource:  campaign.TemplateSource,
+		ReplyTo:         campaign.ReplyTo,
+		SenderID:        campaign.SenderID,
+		StartTime:       campaign.StartTime,
 	})
 	if err != nil {
 		return Campaign{}, PersistenceError{err}
@@ -146,6 +213,28 @@ func (c CampaignsCollection) Create(conn ConnectionInterface, campaign Campaign,
 	campaign.ID = campaignModel.ID
 	campaign.ClientID = clientID
 
+	var attachments []Attachment
+	for _, attachment := range campaign.Attachments {
+		attachmentModel, err := c.attachmentsRepo.Insert(conn, models.Attachment{
+			CampaignID:  campaign.ID,
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			ContentID:   attachment.ContentID,
+			Content:     attachment.Content,
+		})
+		if err != nil {
+			return Campaign{}, PersistenceError{err}
+		}
+
+		attachments = append(attachments, Attachment{
+			ID:          attachmentModel.ID,
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			ContentID:   attachment.ContentID,
+		})
+	}
+	campaign.Attachments = attachments
+
 	err = c.enqueuer.Enqueue(campaign, "campaign")
 	if err != nil {
 		return Campaign{}, PersistenceError{Err: err}
@@ -154,6 +243,100 @@ func (c CampaignsCollection) Create(conn ConnectionInterface, campaign Campaign,
 	return campaign, nil
 }
 
+func (c CampaignsCollection) resolveTemplateID(conn ConnectionInterface, templateID string, campaignType models.CampaignType, clientID string) (string, string, error) {
+	if templateID != "" {
+		return templateID, TemplateSourceCampaign, nil
+	}
+
+	if campaignType.TemplateID != "" {
+		return campaignType.TemplateID, TemplateSourceCampaignType, nil
+	}
+
+	clientTemplate, err := c.clientTemplatesRepo.Get(conn, clientID)
+	if err != nil {
+		if _, ok := err.(models.RecordNotFoundError); !ok {
+			return "", "", PersistenceError{err}
+		}
+	}
+
+	if clientTemplate.TemplateID != "" {
+		return clientTemplate.TemplateID, TemplateSourceClient, nil
+	}
+
+	return models.DefaultTemplate.ID, TemplateSourceDefault, nil
+}
+
+// checkCampaignData validates the data each recipient will receive against
+// the schema declared by the template. Recipients without data of their own,
+// including everyone reached through a space or organization, receive only
+// the campaign's shared data.
+func checkCampaignData(template models.Template, campaign Campaign) error {
+	schema, err := common.ParseDataSchema(template.Metadata)
+	if err != nil {
+		return ValidationError{err}
+	}
+
+	shared := len(campaign.SendTo["spaces"]) > 0 || len(campaign.SendTo["orgs"]) > 0
+	for _, audience := range []string{"users", "emails"} {
+		for _, member := range campaign.SendTo[audience] {
+			if _, ok := campaign.RecipientData[member]; !ok {
+				shared = true
+			}
+		}
+	}
+
+	if shared {
+		err := schema.Validate(campaign.Data)
+		if err != nil {
+			return ValidationError{err}
+		}
+	}
+
+	var recipients []string
+	for recipient := range campaign.RecipientData {
+		recipients = append(recipients, recipient)
+	}
+	sort.Strings(recipients)
+
+	for _, recipient := range recipients {
+		data := map[string]interface{}{}
+		for key, value := range campaign.Data {
+			data[key] = value
+		}
+
+		for key, value := range campaign.RecipientData[recipient] {
+			data[key] = value
+		}
+
+		err := schema.Validate(data)
+		if err != nil {
+			return ValidationError{fmt.Errorf("recipient %q: %s", recipient, err)}
+		}
+	}
+
+	return nil
+}
+
+// fromAddress resolves the From header for a campaign. Values set on the
+// campaign type override those of its sender. An empty result means the
+// deployment-wide sender is used.
+func fromAddress(sender models.Sender, campaignType models.CampaignType) string {
+	address, name := sender.FromAddress, sender.FromName
+	if campaignType.FromAddress != "" {
+		address = campaignType.FromAddress
+	}
+
+	if campaignType.FromName != "" {
+		name = campaignType.FromName
+	}
+
+	if address == "" {
+		return ""
+	}
+
+	return mail.FormatFromAddress(name, address)
+}
+
 func (c CampaignsCollection) checkForExistence(audience, guid string) (bool, error) {
 	switch audience {
 	case "users":
@@ -201,14 +384,16 @@ func (c CampaignsCollection) Get(connection ConnectionInterface, campaignID, cli
 	}
 
 	return Campaign{
-		ID:             campaignID,
-		SendTo:         sendTo,
-		CampaignTypeID: campaign.CampaignTypeID,
-		Text:           campaign.Text,
-		HTML:           campaign.HTML,
-		Subject:        campaign.Subject,
-		TemplateID:     campaign.TemplateID,
-		ReplyTo:        campaign.ReplyTo,
-		SenderID:       campaign.SenderID,
+		ID:              campaignID,
+		SendTo:          sendTo,
+		CampaignTypeID:  campaign.CampaignTypeID,
+		Text:            campaign.Text,
+		HTML:            campaign.HTML,
+		Subject:         campaign.Subject,
+		TemplateID:      campaign.TemplateID,
+		TemplateVersion: campaign.TemplateVersion,
+		TemplateSource:  campaign.TemplateSource,
+		ReplyTo:         campaign.ReplyTo,
+		SenderID:        campaign.SenderID,
 	}, nil
 }
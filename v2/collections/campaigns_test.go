@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/cloudfoundry-incubator/notifications/v2/models"
@@ -14,14 +15,16 @@ import (
 
 var _ = Describe("CampaignsCollection", func() {
 	var (
-		startTime         time.Time
-		conn              *mocks.Connection
-		enqueuer          *mocks.CampaignEnqueuer
-		collection        collections.CampaignsCollection
-		campaignsRepo     *mocks.CampaignsRepository
-		campaignTypesRepo *mocks.CampaignTypesRepository
-		templatesRepo     *mocks.TemplatesRepository
-		sendersRepo       *mocks.SendersRepository
+		startTime           time.Time
+		conn                *mocks.Connection
+		enqueuer            *mocks.CampaignEnqueuer
+		collection          collections.CampaignsCollection
+		campaignsRepo       *mocks.CampaignsRepository
+		campaignTypesRepo   *mocks.CampaignTypesRepository
+		clientTemplatesRepo *mocks.ClientTemplatesRepository
+		templatesRepo       *mocks.TemplatesRepository
+		sendersRepo         *mocks.SendersRepository
+		attachmentsRepo     *mocks.AttachmentsRepository
 	)
 
 	BeforeEach(func() {
@@ -29,14 +32,17 @@ var _ = Describe("CampaignsCollection", func() {
 		enqueuer = mocks.NewCampaignEnqueuer()
 		campaignsRepo = mocks.NewCampaignsRepository()
 		campaignTypesRepo = mocks.NewCampaignTypesRepository()
+		clientTemplatesRepo = mocks.NewClientTemplatesRepository()
+		clientTemplatesRepo.GetCall.Returns.Error = models.NewRecordNotFoundError("not found")
 		templatesRepo = mocks.NewTemplatesRepository()
 		sendersRepo = mocks.NewSendersRepository()
+		attachmentsRepo = mocks.NewAttachmentsRepository()
 
 		var err error
 		startTime, err = time.Parse(time.RFC3339, "2015-09-01T12:34:56-07:00")
 		Expect(err).NotTo(HaveOccurred())
 
-		collection = collections.NewCampaignsCollection(enqueuer, campaignsRepo, campaignTypesRepo, templatesRepo, sendersRepo)
+		collection = collections.NewCampaignsCollection(enqueuer, campaignsRepo, campaignTypesRepo, clientTemplatesRepo, templatesRepo, sendersRepo, attachmentsRepo)
 	})
 
 	Describe("Create", func() {
@@ -105,6 +111,7 @@ var _ = Describe("CampaignsCollection", func() {
 						HTML:           "no-html",
 						Subject:        "some-subject",
 						TemplateID:     "whoa-a-template-id",
+						TemplateSource: "campaign",
 						ReplyTo:        "nothing@example.com",
 						SenderID:       "some-sender-id",
 						StartTime:      startTime,
@@ -118,6 +125,7 @@ var _ = Describe("CampaignsCollection", func() {
 						HTML:           "no-html",
 						Subject:        "some-subject",
 						TemplateID:     "whoa-a-template-id",
+						TemplateSource: "campaign",
 						ReplyTo:        "nothing@example.com",
 						SenderID:       "some-sender-id",
 						ClientID:       "some-client-id",
@@ -170,6 +178,7 @@ var _ = Describe("CampaignsCollection", func() {
 						HTML:           "no-html",
 						Subject:        "some-subject",
 						TemplateID:     "whoa-a-template-id",
+						TemplateSource: "campaign",
 						ReplyTo:        "nothing@example.com",
 						SenderID:       "some-sender-id",
 						ClientID:       "some-client-id",
@@ -222,6 +231,7 @@ var _ = Describe("CampaignsCollection", func() {
 						HTML:           "no-html",
 						Subject:        "some-subject",
 						TemplateID:     "whoa-a-template-id",
+						TemplateSource: "campaign",
 						ReplyTo:        "nothing@example.com",
 						SenderID:       "some-sender-id",
 						ClientID:       "some-client-id",
@@ -274,6 +284,7 @@ var _ = Describe("CampaignsCollection", func() {
 						HTML:           "no-html",
 						Subject:        "some-subject",
 						TemplateID:     "whoa-a-template-id",
+						TemplateSource: "campaign",
 						ReplyTo:        "nothing@example.com",
 						SenderID:       "some-sender-id",
 						ClientID:       "some-client-id",
@@ -320,6 +331,7 @@ var _ = Describe("CampaignsCollection", func() {
 					HTML:           "no-html",
 					Subject:        "some-subject",
 					TemplateID:     "campaign-type-template-id",
+					TemplateSource: "campaign_type",
 					ReplyTo:        "nothing@example.com",
 					SenderID:       "some-sender-id",
 					ClientID:       "some-client-id",
@@ -350,11 +362,80 @@ var _ = Describe("CampaignsCollection", func() {
 					HTML:           "no-html",
 					Subject:        "some-subject",
 					TemplateID:     "default",
+					TemplateSource: "default",
 					ReplyTo:        "nothing@example.com",
 					SenderID:       "some-sender-id",
 					ClientID:       "some-client-id",
 					StartTime:      startTime,
 				}))
+
+				Expect(clientTemplatesRepo.GetCall.Receives.Connection).To(Equal(conn))
+				Expect(clientTemplatesRepo.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+			})
+
+			It("uses the default template of the client before the default template", func() {
+				clientTemplatesRepo.GetCall.Returns.Error = nil
+				clientTemplatesRepo.GetCall.Returns.ClientTemplate = models.ClientTemplate{
+					ClientID:   "some-client-id",
+					TemplateID: "client-template-id",
+				}
+
+				campaign := collections.Campaign{
+					SendTo:         map[string][]string{"users": {"some-guid"}},
+					CampaignTypeID: "some-id",
+					Subject:        "some-subject",
+					SenderID:       "some-sender-id",
+					StartTime:      startTime,
+				}
+
+				_, err := collection.Create(conn, campaign, "some-client-id", false)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(templatesRepo.GetCall.Receives.TemplateID).To(Equal("client-template-id"))
+				Expect(campaignsRepo.InsertCall.Receives.Campaign.TemplateID).To(Equal("client-template-id"))
+				Expect(campaignsRepo.InsertCall.Receives.Campaign.TemplateSource).To(Equal("client"))
+				Expect(enqueuer.EnqueueCall.Receives.Campaign.TemplateID).To(Equal("client-template-id"))
+				Expect(enqueuer.EnqueueCall.Receives.Campaign.TemplateSource).To(Equal("client"))
+			})
+
+			It("prefers the template of the campaign type to the default template of the client", func() {
+				campaignTypesRepo.GetCall.Returns.CampaignType = models.CampaignType{
+					TemplateID: "campaign-type-template-id",
+				}
+				clientTemplatesRepo.GetCall.Returns.Error = nil
+				clientTemplatesRepo.GetCall.Returns.ClientTemplate = models.ClientTemplate{
+					ClientID:   "some-client-id",
+					TemplateID: "client-template-id",
+				}
+
+				campaign := collections.Campaign{
+					SendTo:         map[string][]string{"users": {"some-guid"}},
+					CampaignTypeID: "some-id",
+					Subject:        "some-subject",
+					SenderID:       "some-sender-id",
+					StartTime:      startTime,
+				}
+
+				_, err := collection.Create(conn, campaign, "some-client-id", false)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(enqueuer.EnqueueCall.Receives.Campaign.TemplateID).To(Equal("campaign-type-template-id"))
+				Expect(enqueuer.EnqueueCall.Receives.Campaign.TemplateSource).To(Equal("campaign_type"))
+			})
+
+			It("returns a persistence error when the default template of the client cannot be retrieved", func() {
+				clientTemplatesRepo.GetCall.Returns.Error = errors.New("BOOM!")
+
+				campaign := collections.Campaign{
+					SendTo:         map[string][]string{"users": {"some-guid"}},
+					CampaignTypeID: "some-id",
+					Subject:        "some-subject",
+					SenderID:       "some-sender-id",
+					StartTime:      startTime,
+				}
+
+				_, err := collection.Create(conn, campaign, "some-client-id", false)
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("BOOM!")}))
 			})
 
 			It("allows requestors with critical_notifications.write scope to send critical notifications", func() {
@@ -388,6 +469,7 @@ var _ = Describe("CampaignsCollection", func() {
 					HTML:           "no-html",
 					Subject:        "some-subject",
 					TemplateID:     "some-template-id",
+					TemplateSource: "campaign",
 					ReplyTo:        "nothing@example.com",
 					SenderID:       "some-sender-id",
 					ClientID:       "some-client-id",
@@ -395,6 +477,259 @@ var _ = Describe("CampaignsCollection", func() {
 				}))
 			})
 
+			It("persists the attachments and enqueues the campaign without their content", func() {
+				attachmentsRepo.InsertCall.Returns.Attachments = []models.Attachment{
+					{ID: "some-attachment-id"},
+					{ID: "another-attachment-id"},
+				}
+
+				campaign := collections.Campaign{
+					SendTo:         map[string][]string{"users": {"some-guid"}},
+					CampaignTypeID: "some-id",
+					Text:           "some-test",
+					Subject:        "some-subject",
+					TemplateID:     "some-template-id",
+					SenderID:       "some-sender-id",
+					StartTime:      startTime,
+					Attachments: []collections.Attachment{
+						{
+							Filename:    "invoice.pdf",
+							ContentType: "application/pdf",
+							Content:     []byte("some-pdf-content"),
+						},
+						{
+							Filename:    "logo.png",
+							ContentType: "image/png",
+							ContentID:   "logo",
+							Content:     []byte("some-png-content"),
+						},
+					},
+				}
+
+				createdCampaign, err := collection.Create(conn, campaign, "some-client-id", false)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(attachmentsRepo.InsertCall.Receives.Connection).To(Equal(conn))
+				Expect(attachmentsRepo.InsertCall.Receives.Attachments).To(Equal([]models.Attachment{
+					{
+						CampaignID:  "a-new-id",
+						Filename:    "invoice.pdf",
+						ContentType: "application/pdf",
+						Content:     []byte("some-pdf-content"),
+					},
+					{
+						CampaignID:  "a-new-id",
+						Filename:    "logo.png",
+						ContentType: "image/png",
+						ContentID:   "logo",
+						Content:     []byte("some-png-content"),
+					},
+				}))
+
+				expectedAttachments := []collections.Attachment{
+					{
+						ID:          "some-attachment-id",
+						Filename:    "invoice.pdf",
+						ContentType: "application/pdf",
+					},
+					{
+						ID:          "another-attachment-id",
+						Filename:    "logo.png",
+						ContentType: "image/png",
+						ContentID:   "logo",
+					},
+				}
+				Expect(enqueuer.EnqueueCall.Receives.Campaign.Attachments).To(Equal(expectedAttachments))
+				Expect(createdCampaign.Attachments).To(Equal(expectedAttachments))
+			})
+
+			It("merges the campaign headers over the campaign type defaults", func() {
+				campaignTypesRepo.GetCall.Returns.CampaignType = models.CampaignType{
+					ID:         "some-id",
+					TemplateID: "some-template-id",
+					Headers:    `{"Importance":"normal","X-Team":"platform"}`,
+				}
+
+				campaign := collections.Campaign{
+					SendTo:         map[string][]string{"users": {"some-guid"}},
+					CampaignTypeID: "some-id",
+					Text:           "some-test",
+					Subject:        "some-subject",
+					TemplateID:     "some-template-id",
+					SenderID:       "some-sender-id",
+					Headers: map[string]string{
+						"Importance":    "high",
+						"X-Tracking-ID": "some-tracking-id",
+					},
+				}
+
+				createdCampaign, err := collection.Create(conn, campaign, "some-client-id", false)
+				Expect(err).NotTo(HaveOccurred())
+
+				expectedHeaders := map[string]string{
+					"Importance":    "high",
+					"X-Team":        "platform",
+					"X-Tracking-ID": "some-tracking-id",
+				}
+				Expect(enqueuer.EnqueueCall.Receives.Campaign.Headers).To(Equal(expectedHeaders))
+				Expect(createdCampaign.Headers).To(Equal(expectedHeaders))
+			})
+
+			It("returns a persistence error when the campaign type headers are malformed", func() {
+				campaignTypesRepo.GetCall.Returns.CampaignType = models.CampaignType{
+					ID:         "some-id",
+					TemplateID: "some-template-id",
+					Headers:    "not json",
+				}
+
+				_, err := collection.Create(conn, collections.Campaign{
+					SendTo:         map[string][]string{"users": {"some-guid"}},
+					CampaignTypeID: "some-id",
+					Text:           "some-test",
+					Subject:        "some-subject",
+					TemplateID:     "some-template-id",
+					SenderID:       "some-sender-id",
+				}, "some-client-id", false)
+				Expect(err).To(BeAssignableToTypeOf(collections.PersistenceError{}))
+				Expect(enqueuer.EnqueueCall.Receives.Campaign.CampaignTypeID).To(BeEmpty())
+			})
+
+			It("resolves the from address, letting the campaign type override the sender", func() {
+				sendersRepo.GetCall.Returns.Sender.FromAddress = "team@example.com"
+				sendersRepo.GetCall.Returns.Sender.FromName = "Team"
+				campaignTypesRepo.GetCall.Returns.CampaignType = models.CampaignType{
+					ID:          "some-id",
+					TemplateID:  "some-template-id",
+					FromAddress: "billing@example.com",
+				}
+
+				campaign := collections.Campaign{
+					SendTo:         map[string][]string{"users": {"some-guid"}},
+					CampaignTypeID: "some-id",
+					Text:           "some-test",
+					Subject:        "some-subject",
+					TemplateID:     "some-template-id",
+					SenderID:       "some-sender-id",
+				}
+
+				_, err := collection.Create(conn, campaign, "some-client-id", false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(enqueuer.EnqueueCall.Receives.Campaign.From).To(Equal(`"Team" <billing@example.com>`))
+			})
+
+			It("leaves the from address blank when neither the sender nor the campaign type has one", func() {
+				campaign := collections.Campaign{
+					SendTo:         map[string][]string{"users": {"some-guid"}},
+					CampaignTypeID: "some-id",
+					Text:           "some-test",
+					Subject:        "some-subject",
+					TemplateID:     "some-template-id",
+					SenderID:       "some-sender-id",
+				}
+
+				_, err := collection.Create(conn, campaign, "some-client-id", false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(enqueuer.EnqueueCall.Receives.Campaign.From).To(BeEmpty())
+			})
+
+			It("records the current version of the template", func() {
+				templatesRepo.GetCall.Returns.Template = models.Template{
+					ID:      "some-template-id",
+					Version: 7,
+				}
+
+				campaign := collections.Campaign{
+					SendTo:         map[string][]string{"users": {"some-guid"}},
+					CampaignTypeID: "some-id",
+					Text:           "some-test",
+					Subject:        "some-subject",
+					TemplateID:     "some-template-id",
+					SenderID:       "some-sender-id",
+				}
+
+				createdCampaign, err := collection.Create(conn, campaign, "some-client-id", false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(createdCampaign.TemplateVersion).To(Equal(7))
+				Expect(campaignsRepo.InsertCall.Receives.Campaign.TemplateVersion).To(Equal(7))
+				Expect(enqueuer.EnqueueCall.Receives.Campaign.TemplateVersion).To(Equal(7))
+			})
+
+			Context("when the template declares the data it requires", func() {
+				BeforeEach(func() {
+					templatesRepo.GetCall.Returns.Template = models.Template{
+						ID:       "some-template-id",
+						Metadata: `{"variables": {"app_name": {"type": "string", "required": true}}}`,
+					}
+				})
+
+				It("returns a ValidationError when the shared data is missing a required variable", func() {
+					campaign := collections.Campaign{
+						SendTo:         map[string][]string{"spaces": {"some-space-guid"}},
+						CampaignTypeID: "some-id",
+						Text:           "some-test",
+						Subject:        "some-subject",
+						TemplateID:     "some-template-id",
+						SenderID:       "some-sender-id",
+						Data:           map[string]interface{}{"app_name": 42},
+					}
+
+					_, err := collection.Create(conn, campaign, "some-client-id", false)
+					Expect(err).To(MatchError(collections.ValidationError{Err: common.DataValidationError{Messages: []string{"data.app_name must be a string"}}}))
+					Expect(campaignsRepo.InsertCall.Receives.Campaign).To(Equal(models.Campaign{}))
+				})
+
+				It("accepts recipient data that fills in for the shared data", func() {
+					campaign := collections.Campaign{
+						SendTo:         map[string][]string{"users": {"some-guid"}},
+						CampaignTypeID: "some-id",
+						Text:           "some-test",
+						Subject:        "some-subject",
+						TemplateID:     "some-template-id",
+						SenderID:       "some-sender-id",
+						RecipientData: map[string]map[string]interface{}{
+							"some-guid": {"app_name": "my-app"},
+						},
+					}
+
+					_, err := collection.Create(conn, campaign, "some-client-id", false)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("names the recipient whose data is invalid", func() {
+					campaign := collections.Campaign{
+						SendTo:         map[string][]string{"emails": {"test@example.com"}},
+						CampaignTypeID: "some-id",
+						Text:           "some-test",
+						Subject:        "some-subject",
+						TemplateID:     "some-template-id",
+						SenderID:       "some-sender-id",
+						RecipientData: map[string]map[string]interface{}{
+							"test@example.com": {"app_name": true},
+						},
+					}
+
+					_, err := collection.Create(conn, campaign, "some-client-id", false)
+					Expect(err).To(MatchError(`recipient "test@example.com": data.app_name must be a string`))
+				})
+			})
+
+			It("routes the campaign through the sender's transport", func() {
+				sendersRepo.GetCall.Returns.Sender.Transport = "billing"
+
+				campaign := collections.Campaign{
+					SendTo:         map[string][]string{"users": {"some-guid"}},
+					CampaignTypeID: "some-id",
+					Text:           "some-test",
+					Subject:        "some-subject",
+					TemplateID:     "some-template-id",
+					SenderID:       "some-sender-id",
+				}
+
+				_, err := collection.Create(conn, campaign, "some-client-id", false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(enqueuer.EnqueueCall.Receives.Campaign.Transport).To(Equal("billing"))
+			})
+
 			Context("when an error happens", func() {
 				Context("when enqueue fails", func() {
 					It("returns the error to the caller", func() {
@@ -416,6 +751,28 @@ var _ = Describe("CampaignsCollection", func() {
 					})
 				})
 
+				Context("when inserting an attachment fails", func() {
+					It("returns the error", func() {
+						campaign := collections.Campaign{
+							SendTo:         map[string][]string{"users": {"some-guid"}},
+							CampaignTypeID: "some-id",
+							Text:           "some-test",
+							Subject:        "some-subject",
+							TemplateID:     "whoa-a-template-id",
+							SenderID:       "some-sender-id",
+							Attachments: []collections.Attachment{
+								{Filename: "invoice.pdf", Content: []byte("some-content")},
+							},
+						}
+						attachmentsRepo.InsertCall.Returns.Error = errors.New("attachment insert failed")
+
+						_, err := collection.Create(conn, campaign, "some-client-id", false)
+
+						Expect(err).To(Equal(collections.PersistenceError{Err: errors.New("attachment insert failed")}))
+						Expect(enqueuer.EnqueueCall.Receives.JobType).To(BeEmpty())
+					})
+				})
+
 				Context("when inserting the campaign record fails", func() {
 					It("returns the error", func() {
 						campaign := collections.Campaign{
@@ -609,6 +966,7 @@ var _ = Describe("CampaignsCollection", func() {
 				HTML:           "no-html",
 				Subject:        "some-subject",
 				TemplateID:     "error",
+				TemplateSource: "campaign",
 				ReplyTo:        "nothing@example.com",
 				SenderID:       "some-sender-id",
 			}
@@ -625,6 +983,7 @@ var _ = Describe("CampaignsCollection", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(campaign.ID).To(Equal("my-campaign-id"))
 			Expect(campaign.Text).To(Equal("some-text"))
+			Expect(campaign.TemplateSource).To(Equal("campaign"))
 		})
 
 		Context("failure cases", func() {
@@ -0,0 +1,67 @@
+package collections
+
+import "github.com/cloudfoundry-incubator/notifications/v2/models"
+
+// ClientTemplate names the template a client's campaigns fall back to when
+// neither the campaign nor its campaign type chooses one. An empty
+// TemplateID means the client falls back to the default template.
+type ClientTemplate struct {
+	ClientID   string
+	TemplateID string
+}
+
+type clientTemplatesRepository interface {
+	Upsert(conn models.ConnectionInterface, clientTemplate models.ClientTemplate) (models.ClientTemplate, error)
+	Get(conn models.ConnectionInterface, clientID string) (models.ClientTemplate, error)
+}
+
+type ClientTemplatesCollection struct {
+	clientTemplates clientTemplatesRepository
+	templates       templatesGetter
+}
+
+func NewClientTemplatesCollection(clientTemplates clientTemplatesRepository, templates templatesGetter) ClientTemplatesCollection {
+	return ClientTemplatesCollection{
+		clientTemplates: clientTemplates,
+		templates:       templates,
+	}
+}
+
+func (c ClientTemplatesCollection) Get(conn ConnectionInterface, clientID string) (ClientTemplate, error) {
+	model, err := c.clientTemplates.Get(conn, clientID)
+	if err != nil {
+		if _, ok := err.(models.RecordNotFoundError); ok {
+			return ClientTemplate{ClientID: clientID}, nil
+		}
+
+		return ClientTemplate{}, PersistenceError{err}
+	}
+
+	return ClientTemplate{
+		ClientID:   model.ClientID,
+		TemplateID: model.TemplateID,
+	}, nil
+}
+
+func (c ClientTemplatesCollection) Set(conn ConnectionInterface, clientTemplate ClientTemplate) (ClientTemplate, error) {
+	if clientTemplate.TemplateID != "" {
+		template, err := c.templates.Get(conn, clientTemplate.TemplateID)
+		err = validateTemplate(clientTemplate.ClientID, template, err)
+		if err != nil {
+			return ClientTemplate{}, err
+		}
+	}
+
+	model, err := c.clientTemplates.Upsert(conn, models.ClientTemplate{
+		ClientID:   clientTemplate.ClientID,
+		TemplateID: clientTemplate.TemplateID,
+	})
+	if err != nil {
+		return ClientTemplate{}, PersistenceError{err}
+	}
+
+	return ClientTemplate{
+		ClientID:   model.ClientID,
+		TemplateID: model.TemplateID,
+	}, nil
+}
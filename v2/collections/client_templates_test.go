@@ -0,0 +1,138 @@
+package collections_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClientTemplatesCollection", func() {
+	var (
+		collection          collections.ClientTemplatesCollection
+		clientTemplatesRepo *mocks.ClientTemplatesRepository
+		templatesRepo       *mocks.TemplatesRepository
+		conn                *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		clientTemplatesRepo = mocks.NewClientTemplatesRepository()
+		templatesRepo = mocks.NewTemplatesRepository()
+		conn = mocks.NewConnection()
+
+		collection = collections.NewClientTemplatesCollection(clientTemplatesRepo, templatesRepo)
+	})
+
+	Describe("Get", func() {
+		It("returns the default template of the client", func() {
+			clientTemplatesRepo.GetCall.Returns.ClientTemplate = models.ClientTemplate{
+				ClientID:   "some-client-id",
+				TemplateID: "some-template-id",
+			}
+
+			clientTemplate, err := collection.Get(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clientTemplate).To(Equal(collections.ClientTemplate{
+				ClientID:   "some-client-id",
+				TemplateID: "some-template-id",
+			}))
+
+			Expect(clientTemplatesRepo.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(clientTemplatesRepo.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+		})
+
+		It("returns no template when the client has never set one", func() {
+			clientTemplatesRepo.GetCall.Returns.Error = models.NewRecordNotFoundError("not found")
+
+			clientTemplate, err := collection.Get(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clientTemplate).To(Equal(collections.ClientTemplate{ClientID: "some-client-id"}))
+		})
+
+		It("returns a persistence error when the repository fails", func() {
+			clientTemplatesRepo.GetCall.Returns.Error = errors.New("BOOM!")
+
+			_, err := collection.Get(conn, "some-client-id")
+			Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("BOOM!")}))
+		})
+	})
+
+	Describe("Set", func() {
+		BeforeEach(func() {
+			templatesRepo.GetCall.Returns.Template = models.Template{
+				ID:       "some-template-id",
+				ClientID: "some-client-id",
+			}
+
+			clientTemplatesRepo.UpsertCall.Returns.ClientTemplate = models.ClientTemplate{
+				ClientID:   "some-client-id",
+				TemplateID: "some-template-id",
+			}
+		})
+
+		It("sets the default template of the client", func() {
+			clientTemplate, err := collection.Set(conn, collections.ClientTemplate{
+				ClientID:   "some-client-id",
+				TemplateID: "some-template-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clientTemplate).To(Equal(collections.ClientTemplate{
+				ClientID:   "some-client-id",
+				TemplateID: "some-template-id",
+			}))
+
+			Expect(templatesRepo.GetCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(clientTemplatesRepo.UpsertCall.Receives.Connection).To(Equal(conn))
+			Expect(clientTemplatesRepo.UpsertCall.Receives.ClientTemplate).To(Equal(models.ClientTemplate{
+				ClientID:   "some-client-id",
+				TemplateID: "some-template-id",
+			}))
+		})
+
+		It("clears the default template without looking one up", func() {
+			_, err := collection.Set(conn, collections.ClientTemplate{ClientID: "some-client-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(templatesRepo.GetCall.Receives.TemplateID).To(BeEmpty())
+			Expect(clientTemplatesRepo.UpsertCall.Receives.ClientTemplate).To(Equal(models.ClientTemplate{
+				ClientID: "some-client-id",
+			}))
+		})
+
+		Context("failure cases", func() {
+			It("returns a not found error when the template does not exist", func() {
+				templatesRepo.GetCall.Returns.Error = models.NewRecordNotFoundError("Template with id %q could not be found", "missing-template-id")
+
+				_, err := collection.Set(conn, collections.ClientTemplate{
+					ClientID:   "some-client-id",
+					TemplateID: "missing-template-id",
+				})
+				Expect(err).To(BeAssignableToTypeOf(collections.NotFoundError{}))
+			})
+
+			It("returns a not found error when the template belongs to another client", func() {
+				templatesRepo.GetCall.Returns.Template.ClientID = "other-client-id"
+
+				_, err := collection.Set(conn, collections.ClientTemplate{
+					ClientID:   "some-client-id",
+					TemplateID: "some-template-id",
+				})
+				Expect(err).To(MatchError(collections.NotFoundError{Err: errors.New(`Template with id "some-template-id" could not be found`)}))
+			})
+
+			It("returns a persistence error when the repository fails", func() {
+				clientTemplatesRepo.UpsertCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := collection.Set(conn, collections.ClientTemplate{
+					ClientID:   "some-client-id",
+					TemplateID: "some-template-id",
+				})
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("BOOM!")}))
+			})
+		})
+	})
+})
@@ -39,3 +39,19 @@ type PermissionsError struct {
 func (e PermissionsError) Error() string {
 	return e.Err.Error()
 }
+
+type InUseError struct {
+	Err error
+}
+
+func (e InUseError) Error() string {
+	return e.Err.Error()
+}
+
+type ValidationError struct {
+	Err error
+}
+
+func (e ValidationError) Error() string {
+	return e.Err.Error()
+}
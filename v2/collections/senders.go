@@ -7,9 +7,12 @@ import (
 )
 
 type Sender struct {
-	ID       string
-	Name     string
-	ClientID string
+	ID          string
+	Name        string
+	ClientID    string
+	FromAddress string
+	FromName    string
+	Transport   string
 }
 
 type sendersRepository interface {
@@ -21,15 +24,27 @@ type sendersRepository interface {
 	Delete(conn models.ConnectionInterface, sender models.Sender) error
 }
 
+type transportsGetter interface {
+	Get(conn models.ConnectionInterface, name string) (models.Transport, error)
+}
+
+type transportGrantsGetter interface {
+	Get(conn models.ConnectionInterface, transportName, clientID string) (models.TransportGrant, error)
+}
+
 type SendersCollection struct {
-	senders       sendersRepository
-	campaignTypes campaignTypesRepository
+	senders         sendersRepository
+	campaignTypes   campaignTypesRepository
+	transports      transportsGetter
+	transportGrants transportGrantsGetter
 }
 
-func NewSendersCollection(senders sendersRepository, campaignTypes campaignTypesRepository) SendersCollection {
+func NewSendersCollection(senders sendersRepository, campaignTypes campaignTypesRepository, transports transportsGetter, transportGrants transportGrantsGetter) SendersCollection {
 	return SendersCollection{
-		senders:       senders,
-		campaignTypes: campaignTypes,
+		senders:         senders,
+		campaignTypes:   campaignTypes,
+		transports:      transports,
+		transportGrants: transportGrants,
 	}
 }
 
@@ -39,10 +54,37 @@ func (sc SendersCollection) Set(conn ConnectionInterface, sender Sender) (Sender
 		err   error
 	)
 
+	if sender.Transport != "" {
+		_, err = sc.transports.Get(conn, sender.Transport)
+		if err != nil {
+			switch err.(type) {
+			case models.RecordNotFoundError:
+				return Sender{}, NotFoundError{err}
+			default:
+				return Sender{}, PersistenceError{err}
+			}
+		}
+
+		// A transport that was not granted to the client is reported as
+		// missing so that clients cannot discover each other's relays.
+		_, err = sc.transportGrants.Get(conn, sender.Transport, sender.ClientID)
+		if err != nil {
+			switch err.(type) {
+			case models.RecordNotFoundError:
+				return Sender{}, NotFoundError{fmt.Errorf("Transport %q could not be found", sender.Transport)}
+			default:
+				return Sender{}, PersistenceError{err}
+			}
+		}
+	}
+
 	if sender.ID == "" {
 		model, err = sc.senders.Insert(conn, models.Sender{
-			Name:     sender.Name,
-			ClientID: sender.ClientID,
+			Name:        sender.Name,
+			ClientID:    sender.ClientID,
+			FromAddress: sender.FromAddress,
+			FromName:    sender.FromName,
+			Transport:   sender.Transport,
 		})
 		if err != nil {
 			switch err.(type) {
@@ -57,9 +99,12 @@ func (sc SendersCollection) Set(conn ConnectionInterface, sender Sender) (Sender
 		}
 	} else {
 		model, err = sc.senders.Update(conn, models.Sender{
-			ID:       sender.ID,
-			Name:     sender.Name,
-			ClientID: sender.ClientID,
+			ID:          sender.ID,
+			Name:        sender.Name,
+			ClientID:    sender.ClientID,
+			FromAddress: sender.FromAddress,
+			FromName:    sender.FromName,
+			Transport:   sender.Transport,
 		})
 		if err != nil {
 			switch err.(type) {
@@ -73,9 +118,12 @@ func (sc SendersCollection) Set(conn ConnectionInterface, sender Sender) (Sender
 	}
 
 	return Sender{
-		ID:       model.ID,
-		Name:     model.Name,
-		ClientID: model.ClientID,
+		ID:          model.ID,
+		Name:        model.Name,
+		ClientID:    model.ClientID,
+		FromAddress: model.FromAddress,
+		FromName:    model.FromName,
+		Transport:   model.Transport,
 	}, nil
 }
 
@@ -89,9 +137,12 @@ func (sc SendersCollection) List(conn ConnectionInterface, clientID string) ([]S
 
 	for _, model := range models {
 		sender := Sender{
-			ID:       model.ID,
-			Name:     model.Name,
-			ClientID: model.ClientID,
+			ID:          model.ID,
+			Name:        model.Name,
+			ClientID:    model.ClientID,
+			FromAddress: model.FromAddress,
+			FromName:    model.FromName,
+			Transport:   model.Transport,
 		}
 
 		senderList = append(senderList, sender)
@@ -116,9 +167,12 @@ func (sc SendersCollection) Get(conn ConnectionInterface, senderID, clientID str
 	}
 
 	return Sender{
-		ID:       model.ID,
-		Name:     model.Name,
-		ClientID: model.ClientID,
+		ID:          model.ID,
+		Name:        model.Name,
+		ClientID:    model.ClientID,
+		FromAddress: model.FromAddress,
+		FromName:    model.FromName,
+		Transport:   model.Transport,
 	}, nil
 }
 
@@ -16,14 +16,18 @@ var _ = Describe("SendersCollection", func() {
 		sendersCollection       collections.SendersCollection
 		sendersRepository       *mocks.SendersRepository
 		campaignTypesRepository *mocks.CampaignTypesRepository
+		transportsRepository    *mocks.TransportsRepository
+		transportGrants         *mocks.TransportGrantsRepository
 		conn                    *mocks.Connection
 	)
 
 	BeforeEach(func() {
 		sendersRepository = mocks.NewSendersRepository()
 		campaignTypesRepository = mocks.NewCampaignTypesRepository()
+		transportsRepository = mocks.NewTransportsRepository()
+		transportGrants = mocks.NewTransportGrantsRepository()
 
-		sendersCollection = collections.NewSendersCollection(sendersRepository, campaignTypesRepository)
+		sendersCollection = collections.NewSendersCollection(sendersRepository, campaignTypesRepository, transportsRepository, transportGrants)
 		conn = mocks.NewConnection()
 	})
 
@@ -114,7 +118,73 @@ var _ = Describe("SendersCollection", func() {
 			}))
 		})
 
+		It("binds the sender to a transport", func() {
+			sendersRepository.InsertCall.Returns.Sender = models.Sender{
+				ID:        "some-sender-id",
+				Name:      "some-sender",
+				ClientID:  "some-client-id",
+				Transport: "corporate-relay",
+			}
+
+			sender, err := sendersCollection.Set(conn, collections.Sender{
+				Name:      "some-sender",
+				ClientID:  "some-client-id",
+				Transport: "corporate-relay",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sender.Transport).To(Equal("corporate-relay"))
+
+			Expect(transportsRepository.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(transportsRepository.GetCall.Receives.Name).To(Equal("corporate-relay"))
+			Expect(transportGrants.GetCall.Receives.Connection).To(Equal(conn))
+			Expect(transportGrants.GetCall.Receives.TransportName).To(Equal("corporate-relay"))
+			Expect(transportGrants.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+			Expect(sendersRepository.InsertCall.Receives.Sender.Transport).To(Equal("corporate-relay"))
+		})
+
 		Context("failure cases", func() {
+			Context("when the transport does not exist", func() {
+				It("returns a not found error", func() {
+					transportsRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("Transport \"missing-relay\" could not be found")}
+
+					_, err := sendersCollection.Set(conn, collections.Sender{
+						Name:      "some-sender",
+						ClientID:  "some-client-id",
+						Transport: "missing-relay",
+					})
+					Expect(err).To(MatchError(collections.NotFoundError{
+						Err: models.RecordNotFoundError{Err: errors.New("Transport \"missing-relay\" could not be found")},
+					}))
+				})
+			})
+
+			Context("when the transport has not been granted to the client", func() {
+				It("returns a not found error", func() {
+					transportGrants.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("Client \"some-client-id\" has not been granted transport \"corporate-relay\"")}
+
+					_, err := sendersCollection.Set(conn, collections.Sender{
+						Name:      "some-sender",
+						ClientID:  "some-client-id",
+						Transport: "corporate-relay",
+					})
+					Expect(err).To(MatchError(collections.NotFoundError{Err: errors.New("Transport \"corporate-relay\" could not be found")}))
+					Expect(sendersRepository.InsertCall.Receives.Sender).To(Equal(models.Sender{}))
+				})
+			})
+
+			Context("when the grant cannot be checked", func() {
+				It("returns a persistence error", func() {
+					transportGrants.GetCall.Returns.Error = errors.New("BOOM!")
+
+					_, err := sendersCollection.Set(conn, collections.Sender{
+						Name:      "some-sender",
+						ClientID:  "some-client-id",
+						Transport: "corporate-relay",
+					})
+					Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("BOOM!")}))
+				})
+			})
+
 			Context("when inserting", func() {
 				It("handles unexpected database errors", func() {
 					sendersRepository.InsertCall.Returns.Sender = models.Sender{}
@@ -0,0 +1,186 @@
+package collections
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+)
+
+type TemplatePartial struct {
+	Name     string
+	ClientID string
+	Text     string
+	HTML     string
+}
+
+type templatePartialsRepository interface {
+	Upsert(conn models.ConnectionInterface, partial models.TemplatePartial) (models.TemplatePartial, error)
+	Get(conn models.ConnectionInterface, clientID, name string) (models.TemplatePartial, error)
+	List(conn models.ConnectionInterface, clientID string) ([]models.TemplatePartial, error)
+	Delete(conn models.ConnectionInterface, partial models.TemplatePartial) error
+}
+
+type clientTemplatesLister interface {
+	List(conn models.ConnectionInterface, clientID string) ([]models.Template, error)
+}
+
+// TemplatePartialsCollection stores the named partials a client's templates
+// and layouts include.
+type TemplatePartialsCollection struct {
+	partials  templatePartialsRepository
+	templates clientTemplatesLister
+}
+
+func NewTemplatePartialsCollection(partials templatePartialsRepository, templates clientTemplatesLister) TemplatePartialsCollection {
+	return TemplatePartialsCollection{
+		partials:  partials,
+		templates: templates,
+	}
+}
+
+func (c TemplatePartialsCollection) Set(conn ConnectionInterface, partial TemplatePartial) (TemplatePartial, error) {
+	existing, err := c.partials.List(conn, partial.ClientID)
+	if err != nil {
+		return TemplatePartial{}, PersistenceError{err}
+	}
+
+	partials := []common.Partial{{Name: partial.Name, Text: partial.Text, HTML: partial.HTML}}
+	for _, model := range existing {
+		if model.Name != partial.Name {
+			partials = append(partials, common.Partial{Name: model.Name, Text: model.Text, HTML: model.HTML})
+		}
+	}
+
+	if cycle := common.PartialCycle(partials); cycle != nil {
+		return TemplatePartial{}, ValidationError{fmt.Errorf("Partial %q creates a circular reference: %s", partial.Name, strings.Join(cycle, " -> "))}
+	}
+
+	model, err := c.partials.Upsert(conn, models.TemplatePartial{
+		ClientID: partial.ClientID,
+		Name:     partial.Name,
+		Text:     partial.Text,
+		HTML:     partial.HTML,
+	})
+	if err != nil {
+		return TemplatePartial{}, PersistenceError{err}
+	}
+
+	return newTemplatePartial(model), nil
+}
+
+func (c TemplatePartialsCollection) Get(conn ConnectionInterface, clientID, name string) (TemplatePartial, error) {
+	model, err := c.partials.Get(conn, clientID, name)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return TemplatePartial{}, NotFoundError{err}
+		default:
+			return TemplatePartial{}, PersistenceError{err}
+		}
+	}
+
+	return newTemplatePartial(model), nil
+}
+
+func (c TemplatePartialsCollection) List(conn ConnectionInterface, clientID string) ([]TemplatePartial, error) {
+	partials := []TemplatePartial{}
+
+	models, err := c.partials.List(conn, clientID)
+	if err != nil {
+		return partials, PersistenceError{err}
+	}
+
+	for _, model := range models {
+		partials = append(partials, newTemplatePartial(model))
+	}
+
+	return partials, nil
+}
+
+func (c TemplatePartialsCollection) Delete(conn ConnectionInterface, clientID, name string) error {
+	model, err := c.partials.Get(conn, clientID, name)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return NotFoundError{err}
+		default:
+			return PersistenceError{err}
+		}
+	}
+
+	templates, err := c.templates.List(conn, clientID)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	var users []string
+	for _, template := range templates {
+		var localizations common.Localizations
+		if template.Localizations != "" {
+			err = json.Unmarshal([]byte(template.Localizations), &localizations)
+			if err != nil {
+				return PersistenceError{err}
+			}
+		}
+
+		if includes(name, template.Subject, template.Text, template.HTML) {
+			users = append(users, fmt.Sprintf("template %q", template.Name))
+			continue
+		}
+
+		for _, localization := range localizations {
+			if includes(name, localization.Subject, localization.Text, localization.HTML) {
+				users = append(users, fmt.Sprintf("template %q", template.Name))
+				break
+			}
+		}
+	}
+
+	partials, err := c.partials.List(conn, clientID)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	for _, partial := range partials {
+		if partial.Name != name && includes(name, partial.Text, partial.HTML) {
+			users = append(users, fmt.Sprintf("partial %q", partial.Name))
+		}
+	}
+
+	if len(users) > 0 {
+		sort.Strings(users)
+		return InUseError{fmt.Errorf("Partial %q is included by %s and cannot be deleted", name, strings.Join(users, ", "))}
+	}
+
+	err = c.partials.Delete(conn, model)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	return nil
+}
+
+func includes(name string, sources ...string) bool {
+	for _, source := range sources {
+		for _, reference := range common.TemplateReferences(source) {
+			if reference == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func newTemplatePartial(model models.TemplatePartial) TemplatePartial {
+	return TemplatePartial{
+		Name:     model.Name,
+		ClientID: model.ClientID,
+		Text:     model.Text,
+		HTML:     model.HTML,
+	}
+}
@@ -0,0 +1,206 @@
+package collections_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplatePartialsCollection", func() {
+	var (
+		partialsCollection  collections.TemplatePartialsCollection
+		partialsRepository  *mocks.TemplatePartialsRepository
+		templatesRepository *mocks.TemplatesRepository
+		conn                *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		partialsRepository = mocks.NewTemplatePartialsRepository()
+		templatesRepository = mocks.NewTemplatesRepository()
+		conn = mocks.NewConnection()
+
+		partialsCollection = collections.NewTemplatePartialsCollection(partialsRepository, templatesRepository)
+	})
+
+	Describe("Set", func() {
+		It("persists the partial", func() {
+			partialsRepository.UpsertCall.Returns.Partial = models.TemplatePartial{
+				ID:       "some-partial-id",
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Text:     "Sent to {{.To}}",
+				HTML:     "<p>Sent to {{.To}}</p>",
+			}
+
+			partial, err := partialsCollection.Set(conn, collections.TemplatePartial{
+				Name:     "footer",
+				ClientID: "some-client-id",
+				Text:     "Sent to {{.To}}",
+				HTML:     "<p>Sent to {{.To}}</p>",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial).To(Equal(collections.TemplatePartial{
+				Name:     "footer",
+				ClientID: "some-client-id",
+				Text:     "Sent to {{.To}}",
+				HTML:     "<p>Sent to {{.To}}</p>",
+			}))
+
+			Expect(partialsRepository.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+			Expect(partialsRepository.UpsertCall.Receives.Connection).To(Equal(conn))
+			Expect(partialsRepository.UpsertCall.Receives.Partial).To(Equal(models.TemplatePartial{
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Text:     "Sent to {{.To}}",
+				HTML:     "<p>Sent to {{.To}}</p>",
+			}))
+		})
+
+		It("allows a partial to replace its own earlier content", func() {
+			partialsRepository.ListCall.Returns.Partials = []models.TemplatePartial{
+				{Name: "footer", Text: `{{template "legal" .}}`},
+				{Name: "legal", Text: `{{template "footer" .}}`},
+			}
+
+			_, err := partialsCollection.Set(conn, collections.TemplatePartial{
+				Name:     "legal",
+				ClientID: "some-client-id",
+				Text:     "All rights reserved",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the partial would include itself", func() {
+			It("returns a validation error naming the chain of partials", func() {
+				partialsRepository.ListCall.Returns.Partials = []models.TemplatePartial{
+					{Name: "footer", Text: `{{template "legal" .}}`},
+					{Name: "legal", HTML: "All rights reserved"},
+				}
+
+				_, err := partialsCollection.Set(conn, collections.TemplatePartial{
+					Name:     "legal",
+					ClientID: "some-client-id",
+					HTML:     `{{template "footer" .}}`,
+				})
+				Expect(err).To(MatchError(collections.ValidationError{Err: errors.New(`Partial "legal" creates a circular reference: footer -> legal -> footer`)}))
+				Expect(partialsRepository.UpsertCall.Receives.Partial).To(Equal(models.TemplatePartial{}))
+			})
+		})
+
+		Context("when the repository errors", func() {
+			It("returns a persistence error", func() {
+				partialsRepository.UpsertCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := partialsCollection.Set(conn, collections.TemplatePartial{Name: "footer"})
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("BOOM!")}))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the client's partial", func() {
+			partialsRepository.GetCall.Returns.Partial = models.TemplatePartial{
+				ID:       "some-partial-id",
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Text:     "Sent to {{.To}}",
+			}
+
+			partial, err := partialsCollection.Get(conn, "some-client-id", "footer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial).To(Equal(collections.TemplatePartial{
+				Name:     "footer",
+				ClientID: "some-client-id",
+				Text:     "Sent to {{.To}}",
+			}))
+
+			Expect(partialsRepository.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+			Expect(partialsRepository.GetCall.Receives.Name).To(Equal("footer"))
+		})
+
+		Context("when the partial does not exist", func() {
+			It("returns a not found error", func() {
+				partialsRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("not found")}
+
+				_, err := partialsCollection.Get(conn, "some-client-id", "missing")
+				Expect(err).To(MatchError(collections.NotFoundError{Err: models.RecordNotFoundError{Err: errors.New("not found")}}))
+			})
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the client's partials", func() {
+			partialsRepository.ListCall.Returns.Partials = []models.TemplatePartial{
+				{ClientID: "some-client-id", Name: "footer"},
+				{ClientID: "some-client-id", Name: "header"},
+			}
+
+			partials, err := partialsCollection.List(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(Equal([]collections.TemplatePartial{
+				{ClientID: "some-client-id", Name: "footer"},
+				{ClientID: "some-client-id", Name: "header"},
+			}))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the partial", func() {
+			partialsRepository.GetCall.Returns.Partial = models.TemplatePartial{ID: "some-partial-id", Name: "footer"}
+
+			err := partialsCollection.Delete(conn, "some-client-id", "footer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partialsRepository.DeleteCall.Receives.Partial).To(Equal(models.TemplatePartial{ID: "some-partial-id", Name: "footer"}))
+
+			Expect(templatesRepository.ListCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepository.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+			Expect(partialsRepository.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+		})
+
+		Context("when templates or other partials still include the partial", func() {
+			BeforeEach(func() {
+				partialsRepository.GetCall.Returns.Partial = models.TemplatePartial{ID: "some-partial-id", Name: "footer"}
+				templatesRepository.ListCall.Returns.Templates = []models.Template{
+					{Name: "welcome", HTML: `<p>hi</p>{{template "footer" .}}`},
+					{Name: "localized", HTML: "<p>hi</p>", Localizations: `{"fr": {"text": "{{template \"footer\" .}}"}}`},
+					{Name: "unrelated", HTML: `<p>hi</p>{{template "header" .}}`},
+				}
+				partialsRepository.ListCall.Returns.Partials = []models.TemplatePartial{
+					{Name: "footer", HTML: "<p>bye</p>"},
+					{Name: "signature", HTML: `<p>{{template "footer" .}}</p>`},
+				}
+			})
+
+			It("refuses to delete it and lists what includes it", func() {
+				err := partialsCollection.Delete(conn, "some-client-id", "footer")
+				Expect(err).To(MatchError(collections.InUseError{Err: errors.New(`Partial "footer" is included by partial "signature", template "localized", template "welcome" and cannot be deleted`)}))
+				Expect(partialsRepository.DeleteCall.Receives.Partial).To(Equal(models.TemplatePartial{}))
+			})
+		})
+
+		Context("when the templates cannot be listed", func() {
+			It("returns a persistence error", func() {
+				partialsRepository.GetCall.Returns.Partial = models.TemplatePartial{ID: "some-partial-id", Name: "footer"}
+				templatesRepository.ListCall.Returns.Error = errors.New("some error")
+
+				err := partialsCollection.Delete(conn, "some-client-id", "footer")
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("some error")}))
+				Expect(partialsRepository.DeleteCall.Receives.Partial).To(Equal(models.TemplatePartial{}))
+			})
+		})
+
+		Context("when the partial does not exist", func() {
+			It("returns a not found error", func() {
+				partialsRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("not found")}
+
+				err := partialsCollection.Delete(conn, "some-client-id", "missing")
+				Expect(err).To(MatchError(collections.NotFoundError{Err: models.RecordNotFoundError{Err: errors.New("not found")}}))
+			})
+		})
+	})
+})
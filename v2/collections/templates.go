@@ -2,48 +2,144 @@ package collections
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/util"
 	"github.com/cloudfoundry-incubator/notifications/v2/models"
 )
 
 type Template struct {
-	ID       string
-	Name     string
-	HTML     string
-	Text     string
-	Subject  string
-	Metadata string
-	ClientID string
+	ID            string
+	Name          string
+	HTML          string
+	Text          string
+	Subject       string
+	Metadata      string
+	Localizations string
+	LayoutID      string
+	ClientID      string
+	Version       int
+
+	DisableAutoText    bool
+	DisableCSSInlining bool
+}
+
+type TemplateVersion struct {
+	TemplateID    string
+	Version       int
+	Name          string
+	HTML          string
+	Text          string
+	Subject       string
+	Metadata      string
+	Localizations string
+	LayoutID      string
+	CreatedAt     time.Time
+
+	DisableAutoText    bool
+	DisableCSSInlining bool
+}
+
+// TemplateDiff holds a line diff for each field that differs between two
+// versions of a template. Unchanged fields are left empty.
+type TemplateDiff struct {
+	TemplateID    string
+	From          int
+	To            int
+	Name          string
+	HTML          string
+	Text          string
+	Subject       string
+	Metadata      string
+	Localizations string
+	LayoutID      string
+
+	DisableAutoText    string
+	DisableCSSInlining string
+}
+
+// TemplateAssociations are the campaign types that use a template, the
+// campaigns still sending with it and the templates using it as their layout.
+type TemplateAssociations struct {
+	CampaignTypes []CampaignType
+	Campaigns     []Campaign
+	Templates     []Template
 }
 
 type templatesRepository interface {
 	Insert(conn models.ConnectionInterface, template models.Template) (createdTemplate models.Template, err error)
 	Update(conn models.ConnectionInterface, template models.Template) (updatedTemplate models.Template, err error)
 	Get(conn models.ConnectionInterface, templateID string) (retrievedTemplate models.Template, err error)
+	GetForUpdate(conn models.ConnectionInterface, templateID string) (retrievedTemplate models.Template, err error)
 	Delete(conn models.ConnectionInterface, templateID string) error
 	List(conn models.ConnectionInterface, clientID string) (templateList []models.Template, err error)
+	ListByLayoutID(conn models.ConnectionInterface, layoutID string) (templateList []models.Template, err error)
+}
+
+type templateVersionsRepository interface {
+	Insert(conn models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error)
+	Get(conn models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error)
+	List(conn models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error)
+	DeleteAll(conn models.ConnectionInterface, templateID string) error
+}
+
+type templateCampaignTypesRepository interface {
+	ListByTemplateID(conn models.ConnectionInterface, templateID string) ([]models.CampaignType, error)
+	Update(conn models.ConnectionInterface, campaignType models.CampaignType) (models.CampaignType, error)
+}
+
+type templateCampaignsRepository interface {
+	ListSendingCampaignsByTemplateID(conn models.ConnectionInterface, templateID string) ([]models.Campaign, error)
 }
 
+// TemplatesCollection stores every change to a template as an immutable
+// version alongside the current copy of the template.
 type TemplatesCollection struct {
-	repo templatesRepository
+	repo            templatesRepository
+	versions        templateVersionsRepository
+	campaignTypes   templateCampaignTypesRepository
+	campaigns       templateCampaignsRepository
+	clientTemplates clientTemplatesRepository
 }
 
-func NewTemplatesCollection(repo templatesRepository) TemplatesCollection {
+func NewTemplatesCollection(repo templatesRepository, versions templateVersionsRepository, campaignTypes templateCampaignTypesRepository, campaigns templateCampaignsRepository, clientTemplates clientTemplatesRepository) TemplatesCollection {
 	return TemplatesCollection{
-		repo: repo,
+		repo:            repo,
+		versions:        versions,
+		campaignTypes:   campaignTypes,
+		campaigns:       campaigns,
+		clientTemplates: clientTemplates,
 	}
 }
 
 func (c TemplatesCollection) Set(conn ConnectionInterface, template Template) (Template, error) {
+	_, err := common.ParseDataSchema(template.Metadata)
+	if err != nil {
+		return Template{}, ValidationError{err}
+	}
+
+	err = c.checkLayout(conn, template)
+	if err != nil {
+		return Template{}, err
+	}
+
 	if template.ID == "" || template.ID == models.DefaultTemplate.ID {
 		model, err := c.repo.Insert(conn, models.Template{
-			ID:       template.ID,
-			Name:     template.Name,
-			HTML:     template.HTML,
-			Text:     template.Text,
-			Subject:  template.Subject,
-			Metadata: template.Metadata,
-			ClientID: template.ClientID,
+			ID:            template.ID,
+			Name:          template.Name,
+			HTML:          template.HTML,
+			Text:          template.Text,
+			Subject:       template.Subject,
+			Metadata:      template.Metadata,
+			Localizations: template.Localizations,
+			LayoutID:      template.LayoutID,
+			ClientID:      template.ClientID,
+			Version:       1,
+
+			DisableAutoText:    template.DisableAutoText,
+			DisableCSSInlining: template.DisableCSSInlining,
 		})
 		if err != nil {
 			switch err.(type) {
@@ -54,15 +150,12 @@ func (c TemplatesCollection) Set(conn ConnectionInterface, template Template) (T
 			}
 		}
 
-		return Template{
-			ID:       model.ID,
-			Name:     model.Name,
-			HTML:     model.HTML,
-			Text:     model.Text,
-			Subject:  model.Subject,
-			Metadata: model.Metadata,
-			ClientID: model.ClientID,
-		}, nil
+		err = c.recordVersion(conn, model)
+		if err != nil {
+			return Template{}, err
+		}
+
+		return newTemplate(model), nil
 	}
 
 	return c.updateExistingRecord(conn, template)
@@ -83,19 +176,52 @@ func (c TemplatesCollection) Get(conn ConnectionInterface, templateID, clientID
 		return Template{}, NotFoundError{fmt.Errorf("Template with id %q could not be found", templateID)}
 	}
 
-	return Template{
-		ID:       template.ID,
-		Name:     template.Name,
-		HTML:     template.HTML,
-		Text:     template.Text,
-		Subject:  template.Subject,
-		Metadata: template.Metadata,
-		ClientID: template.ClientID,
-	}, nil
+	return newTemplate(template), nil
 }
 
-func (c TemplatesCollection) Delete(conn ConnectionInterface, templateID string) error {
-	err := c.repo.Delete(conn, templateID)
+// Delete removes a template and all of its versions. Campaign types that use
+// the template are reassigned to the replacement template, without which the
+// template cannot be deleted. A client default template is replaced too, or
+// cleared when there is no replacement. Campaigns still sending with the
+// template always prevent its deletion, since their messages were queued
+// with it.
+func (c TemplatesCollection) Delete(conn ConnectionInterface, templateID, clientID, replacementID string) error {
+	campaignTypes, campaigns, templates, err := c.associations(conn, templateID)
+	if err != nil {
+		return err
+	}
+
+	if len(campaigns) > 0 {
+		return InUseError{fmt.Errorf("Template %q cannot be deleted while campaigns are sending with it", templateID)}
+	}
+
+	if replacementID != "" {
+		err = c.checkReplacement(conn, templateID, clientID, replacementID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(templates) > 0 {
+		err = c.reassignLayout(conn, templates, templateID, replacementID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(campaignTypes) > 0 {
+		err = c.reassign(conn, campaignTypes, templateID, replacementID)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.replaceClientTemplate(conn, templateID, clientID, replacementID)
+	if err != nil {
+		return err
+	}
+
+	err = c.repo.Delete(conn, templateID)
 	if err != nil {
 		switch err.(type) {
 		case models.RecordNotFoundError:
@@ -105,9 +231,108 @@ func (c TemplatesCollection) Delete(conn ConnectionInterface, templateID string)
 		}
 	}
 
+	err = c.versions.DeleteAll(conn, templateID)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
 	return nil
 }
 
+// ListAssociations returns the campaign types and campaigns that would stop
+// the template owned by the client from being deleted.
+func (c TemplatesCollection) ListAssociations(conn ConnectionInterface, templateID, clientID string) (TemplateAssociations, error) {
+	associations := TemplateAssociations{
+		CampaignTypes: []CampaignType{},
+		Campaigns:     []Campaign{},
+		Templates:     []Template{},
+	}
+
+	template, err := c.Get(conn, templateID, clientID)
+	if err != nil {
+		return associations, err
+	}
+
+	// The default template is shared by every client, so what uses it is
+	// not for any one client to see.
+	if template.ClientID != clientID {
+		return associations, NotFoundError{fmt.Errorf("Template with id %q could not be found", templateID)}
+	}
+
+	campaignTypes, campaigns, templates, err := c.associations(conn, templateID)
+	if err != nil {
+		return associations, err
+	}
+
+	for _, model := range campaignTypes {
+		campaignType, err := newCampaignType(model)
+		if err != nil {
+			return associations, err
+		}
+
+		associations.CampaignTypes = append(associations.CampaignTypes, campaignType)
+	}
+
+	for _, campaign := range campaigns {
+		associations.Campaigns = append(associations.Campaigns, Campaign{
+			ID:              campaign.ID,
+			CampaignTypeID:  campaign.CampaignTypeID,
+			Subject:         campaign.Subject,
+			TemplateID:      campaign.TemplateID,
+			TemplateVersion: campaign.TemplateVersion,
+			SenderID:        campaign.SenderID,
+			StartTime:       campaign.StartTime,
+		})
+	}
+
+	for _, template := range templates {
+		associations.Templates = append(associations.Templates, newTemplate(template))
+	}
+
+	return associations, nil
+}
+
+// UsedOnlyByCritical reports whether every campaign type using the template
+// is critical. A template that nothing uses yet, that wraps other templates
+// or that is the default template of the client may still end up in mail
+// recipients can unsubscribe from, so it is not.
+func (c TemplatesCollection) UsedOnlyByCritical(conn ConnectionInterface, templateID, clientID string) (bool, error) {
+	campaignTypes, err := c.campaignTypes.ListByTemplateID(conn, templateID)
+	if err != nil {
+		return false, PersistenceError{err}
+	}
+
+	if len(campaignTypes) == 0 {
+		return false, nil
+	}
+
+	for _, campaignType := range campaignTypes {
+		if !campaignType.Critical {
+			return false, nil
+		}
+	}
+
+	templates, err := c.repo.ListByLayoutID(conn, templateID)
+	if err != nil {
+		return false, PersistenceError{err}
+	}
+
+	if len(templates) > 0 {
+		return false, nil
+	}
+
+	clientTemplate, err := c.clientTemplates.Get(conn, clientID)
+	if err != nil {
+		if _, ok := err.(models.RecordNotFoundError); ok {
+			return true, nil
+		}
+
+		return false, PersistenceError{err}
+	}
+
+	return clientTemplate.TemplateID != templateID, nil
+}
+
 func (c TemplatesCollection) List(conn ConnectionInterface, clientID string) ([]Template, error) {
 	var templateList []Template
 
@@ -117,41 +342,362 @@ func (c TemplatesCollection) List(conn ConnectionInterface, clientID string) ([]
 	}
 
 	for _, template := range templates {
-		templateList = append(templateList, Template{
-			ID:       template.ID,
-			Name:     template.Name,
-			HTML:     template.HTML,
-			Text:     template.Text,
-			Subject:  template.Subject,
-			Metadata: template.Metadata,
-			ClientID: template.ClientID,
-		})
+		templateList = append(templateList, newTemplate(template))
 	}
 
 	return templateList, nil
 }
 
+// GetVersion returns a single stored version of a template owned by the
+// client.
+func (c TemplatesCollection) GetVersion(conn ConnectionInterface, templateID, clientID string, version int) (TemplateVersion, error) {
+	_, err := c.Get(conn, templateID, clientID)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	model, err := c.versions.Get(conn, templateID, version)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return TemplateVersion{}, NotFoundError{err}
+		default:
+			return TemplateVersion{}, PersistenceError{err}
+		}
+	}
+
+	return newTemplateVersion(model), nil
+}
+
+// ListVersions returns the stored versions of a template, newest first.
+func (c TemplatesCollection) ListVersions(conn ConnectionInterface, templateID, clientID string) ([]TemplateVersion, error) {
+	versions := []TemplateVersion{}
+
+	_, err := c.Get(conn, templateID, clientID)
+	if err != nil {
+		return versions, err
+	}
+
+	versionModels, err := c.versions.List(conn, templateID)
+	if err != nil {
+		return versions, PersistenceError{err}
+	}
+
+	for _, model := range versionModels {
+		versions = append(versions, newTemplateVersion(model))
+	}
+
+	return versions, nil
+}
+
+func (c TemplatesCollection) DiffVersions(conn ConnectionInterface, templateID, clientID string, from, to int) (TemplateDiff, error) {
+	fromVersion, err := c.GetVersion(conn, templateID, clientID, from)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+
+	toVersion, err := c.GetVersion(conn, templateID, clientID, to)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+
+	return TemplateDiff{
+		TemplateID:    templateID,
+		From:          from,
+		To:            to,
+		Name:          util.DiffLines(fromVersion.Name, toVersion.Name),
+		HTML:          util.DiffLines(fromVersion.HTML, toVersion.HTML),
+		Text:          util.DiffLines(fromVersion.Text, toVersion.Text),
+		Subject:       util.DiffLines(fromVersion.Subject, toVersion.Subject),
+		Metadata:      util.DiffLines(fromVersion.Metadata, toVersion.Metadata),
+		Localizations: util.DiffLines(fromVersion.Localizations, toVersion.Localizations),
+		LayoutID:      util.DiffLines(fromVersion.LayoutID, toVersion.LayoutID),
+
+		DisableAutoText:    util.DiffLines(strconv.FormatBool(fromVersion.DisableAutoText), strconv.FormatBool(toVersion.DisableAutoText)),
+		DisableCSSInlining: util.DiffLines(strconv.FormatBool(fromVersion.DisableCSSInlining), strconv.FormatBool(toVersion.DisableCSSInlining)),
+	}, nil
+}
+
+// Rollback restores the content of an earlier version. The restored content
+// is stored as a new version so that history is never rewritten.
+func (c TemplatesCollection) Rollback(conn ConnectionInterface, templateID, clientID string, version int) (Template, error) {
+	template, err := c.Get(conn, templateID, clientID)
+	if err != nil {
+		return Template{}, err
+	}
+
+	previous, err := c.GetVersion(conn, templateID, clientID, version)
+	if err != nil {
+		return Template{}, err
+	}
+
+	template.Name = previous.Name
+	template.HTML = previous.HTML
+	template.Text = previous.Text
+	template.Subject = previous.Subject
+	template.Metadata = previous.Metadata
+	template.Localizations = previous.Localizations
+	template.LayoutID = previous.LayoutID
+	template.DisableAutoText = previous.DisableAutoText
+	template.DisableCSSInlining = previous.DisableCSSInlining
+
+	return c.Set(conn, template)
+}
+
+// Layouts returns the chain of layouts wrapping the template, starting with
+// the innermost. Layouts must belong to the same client as the template.
+func (c TemplatesCollection) Layouts(conn ConnectionInterface, template Template) ([]Template, error) {
+	var layouts []Template
+	seen := map[string]bool{template.ID: true}
+
+	for layoutID := template.LayoutID; layoutID != ""; {
+		if seen[layoutID] {
+			return nil, ValidationError{fmt.Errorf("Layout %q creates a circular reference", template.LayoutID)}
+		}
+		seen[layoutID] = true
+
+		layout, err := c.repo.Get(conn, layoutID)
+		if err != nil {
+			switch err.(type) {
+			case models.RecordNotFoundError:
+				return nil, NotFoundError{fmt.Errorf("Layout %q could not be found", layoutID)}
+			default:
+				return nil, PersistenceError{err}
+			}
+		}
+
+		if layout.ClientID != template.ClientID {
+			return nil, NotFoundError{fmt.Errorf("Layout %q could not be found", layoutID)}
+		}
+
+		layouts = append(layouts, newTemplate(layout))
+		layoutID = layout.LayoutID
+	}
+
+	return layouts, nil
+}
+
+func (c TemplatesCollection) associations(conn ConnectionInterface, templateID string) ([]models.CampaignType, []models.Campaign, []models.Template, error) {
+	campaignTypes, err := c.campaignTypes.ListByTemplateID(conn, templateID)
+	if err != nil {
+		return nil, nil, nil, PersistenceError{err}
+	}
+
+	campaigns, err := c.campaigns.ListSendingCampaignsByTemplateID(conn, templateID)
+	if err != nil {
+		return nil, nil, nil, PersistenceError{err}
+	}
+
+	templates, err := c.repo.ListByLayoutID(conn, templateID)
+	if err != nil {
+		return nil, nil, nil, PersistenceError{err}
+	}
+
+	return campaignTypes, campaigns, templates, nil
+}
+
+func (c TemplatesCollection) checkReplacement(conn ConnectionInterface, templateID, clientID, replacementID string) error {
+	if replacementID == templateID {
+		return ValidationError{fmt.Errorf("Template %q cannot replace itself", templateID)}
+	}
+
+	_, err := c.Get(conn, replacementID, clientID)
+	if err != nil {
+		if _, ok := err.(NotFoundError); ok {
+			return ValidationError{fmt.Errorf("Replacement template %q could not be found", replacementID)}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (c TemplatesCollection) reassign(conn ConnectionInterface, campaignTypes []models.CampaignType, templateID, replacementID string) error {
+	if replacementID == "" {
+		return InUseError{fmt.Errorf("Template %q is used by campaign types and cannot be deleted without a replacement template", templateID)}
+	}
+
+	for _, campaignType := range campaignTypes {
+		campaignType.TemplateID = replacementID
+
+		_, err := c.campaignTypes.Update(conn, campaignType)
+		if err != nil {
+			return PersistenceError{err}
+		}
+	}
+
+	return nil
+}
+
+// reassignLayout wraps the templates that use the deleted template as their
+// layout in the replacement instead. Every change is checked before any is
+// made, so that a replacement that would wrap itself is rejected up front.
+// Each change is stored as a new version of the template.
+func (c TemplatesCollection) reassignLayout(conn ConnectionInterface, templates []models.Template, templateID, replacementID string) error {
+	if replacementID == "" {
+		return InUseError{fmt.Errorf("Template %q is used as a layout by other templates and cannot be deleted without a replacement template", templateID)}
+	}
+
+	for _, model := range templates {
+		template := newTemplate(model)
+		template.LayoutID = replacementID
+
+		err := c.checkLayout(conn, template)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, model := range templates {
+		template := newTemplate(model)
+		template.LayoutID = replacementID
+
+		_, err := c.updateExistingRecord(conn, template)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c TemplatesCollection) replaceClientTemplate(conn ConnectionInterface, templateID, clientID, replacementID string) error {
+	clientTemplate, err := c.clientTemplates.Get(conn, clientID)
+	if err != nil {
+		if _, ok := err.(models.RecordNotFoundError); ok {
+			return nil
+		}
+
+		return PersistenceError{err}
+	}
+
+	if clientTemplate.TemplateID != templateID {
+		return nil
+	}
+
+	clientTemplate.TemplateID = replacementID
+	_, err = c.clientTemplates.Upsert(conn, clientTemplate)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	return nil
+}
+
+func (c TemplatesCollection) checkLayout(conn ConnectionInterface, template Template) error {
+	layouts, err := c.Layouts(conn, template)
+	if err != nil {
+		if notFound, ok := err.(NotFoundError); ok {
+			return ValidationError{notFound.Err}
+		}
+
+		return err
+	}
+
+	for _, layout := range layouts {
+		if !common.IncludesLayoutContent(layout.Text) && !common.IncludesLayoutContent(layout.HTML) {
+			return ValidationError{fmt.Errorf("Layout %q does not include {{template \"content\" .}}", layout.ID)}
+		}
+	}
+
+	return nil
+}
+
+// updateExistingRecord locks the template row while it bumps the version and
+// records it, so callers should pass a transaction.
 func (c TemplatesCollection) updateExistingRecord(conn ConnectionInterface, template Template) (Template, error) {
+	existing, err := c.repo.GetForUpdate(conn, template.ID)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return Template{}, NotFoundError{err}
+		default:
+			return Template{}, PersistenceError{err}
+		}
+	}
+
 	model, err := c.repo.Update(conn, models.Template{
-		ID:       template.ID,
-		Name:     template.Name,
-		HTML:     template.HTML,
-		Text:     template.Text,
-		Subject:  template.Subject,
-		Metadata: template.Metadata,
-		ClientID: template.ClientID,
+		ID:            template.ID,
+		Name:          template.Name,
+		HTML:          template.HTML,
+		Text:          template.Text,
+		Subject:       template.Subject,
+		Metadata:      template.Metadata,
+		Localizations: template.Localizations,
+		LayoutID:      template.LayoutID,
+		ClientID:      template.ClientID,
+		Version:       existing.Version + 1,
+
+		DisableAutoText:    template.DisableAutoText,
+		DisableCSSInlining: template.DisableCSSInlining,
 	})
 	if err != nil {
 		return Template{}, PersistenceError{err}
 	}
 
+	err = c.recordVersion(conn, model)
+	if err != nil {
+		return Template{}, err
+	}
+
+	return newTemplate(model), nil
+}
+
+func (c TemplatesCollection) recordVersion(conn ConnectionInterface, template models.Template) error {
+	_, err := c.versions.Insert(conn, models.TemplateVersion{
+		TemplateID:    template.ID,
+		Version:       template.Version,
+		Name:          template.Name,
+		HTML:          template.HTML,
+		Text:          template.Text,
+		Subject:       template.Subject,
+		Metadata:      template.Metadata,
+		Localizations: template.Localizations,
+		LayoutID:      template.LayoutID,
+
+		DisableAutoText:    template.DisableAutoText,
+		DisableCSSInlining: template.DisableCSSInlining,
+	})
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	return nil
+}
+
+func newTemplate(model models.Template) Template {
 	return Template{
-		ID:       model.ID,
-		Name:     model.Name,
-		HTML:     model.HTML,
-		Text:     model.Text,
-		Subject:  model.Subject,
-		Metadata: model.Metadata,
-		ClientID: model.ClientID,
-	}, nil
+		ID:            model.ID,
+		Name:          model.Name,
+		HTML:          model.HTML,
+		Text:          model.Text,
+		Subject:       model.Subject,
+		Metadata:      model.Metadata,
+		Localizations: model.Localizations,
+		LayoutID:      model.LayoutID,
+		ClientID:      model.ClientID,
+		Version:       model.Version,
+
+		DisableAutoText:    model.DisableAutoText,
+		DisableCSSInlining: model.DisableCSSInlining,
+	}
+}
+
+func newTemplateVersion(model models.TemplateVersion) TemplateVersion {
+	return TemplateVersion{
+		TemplateID:    model.TemplateID,
+		Version:       model.Version,
+		Name:          model.Name,
+		HTML:          model.HTML,
+		Text:          model.Text,
+		Subject:       model.Subject,
+		Metadata:      model.Metadata,
+		Localizations: model.Localizations,
+		LayoutID:      model.LayoutID,
+		CreatedAt:     model.CreatedAt,
+
+		DisableAutoText:    model.DisableAutoText,
+		DisableCSSInlining: model.DisableCSSInlining,
+	}
 }
@@ -2,6 +2,7 @@ package collections_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
@@ -15,13 +16,23 @@ var _ = Describe("TemplatesCollection", func() {
 	var (
 		templatesCollection collections.TemplatesCollection
 		templatesRepository *mocks.TemplatesRepository
+		versionsRepository  *mocks.TemplateVersionsRepository
+		campaignTypesRepo   *mocks.CampaignTypesRepository
+		campaignsRepo       *mocks.CampaignsRepository
+		clientTemplatesRepo *mocks.ClientTemplatesRepository
 		conn                *mocks.Connection
 	)
 
 	BeforeEach(func() {
 		templatesRepository = mocks.NewTemplatesRepository()
+		versionsRepository = mocks.NewTemplateVersionsRepository()
 
-		templatesCollection = collections.NewTemplatesCollection(templatesRepository)
+		campaignTypesRepo = mocks.NewCampaignTypesRepository()
+		campaignsRepo = mocks.NewCampaignsRepository()
+		clientTemplatesRepo = mocks.NewClientTemplatesRepository()
+		clientTemplatesRepo.GetCall.Returns.Error = models.NewRecordNotFoundError("not found")
+
+		templatesCollection = collections.NewTemplatesCollection(templatesRepository, versionsRepository, campaignTypesRepo, campaignsRepo, clientTemplatesRepo)
 		conn = mocks.NewConnection()
 	})
 
@@ -59,6 +70,29 @@ var _ = Describe("TemplatesCollection", func() {
 					HTML:     "<h1>My Cool Template</h1>",
 					Subject:  "{{.Subject}}",
 					ClientID: "some-client-id",
+					Version:  1,
+				}))
+			})
+
+			It("records the template as its first version", func() {
+				templatesRepository.InsertCall.Returns.Template.Version = 1
+
+				template, err := templatesCollection.Set(conn, collections.Template{
+					Name:     "some-template",
+					HTML:     "<h1>My Cool Template</h1>",
+					Subject:  "{{.Subject}}",
+					ClientID: "some-client-id",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(template.Version).To(Equal(1))
+
+				Expect(versionsRepository.InsertCall.Receives.Connection).To(Equal(conn))
+				Expect(versionsRepository.InsertCall.Receives.TemplateVersion).To(Equal(models.TemplateVersion{
+					TemplateID: "some-template-id",
+					Version:    1,
+					Name:       "some-template",
+					HTML:       "<h1>My Cool Template</h1>",
+					Subject:    "{{.Subject}}",
 				}))
 			})
 		})
@@ -98,6 +132,36 @@ var _ = Describe("TemplatesCollection", func() {
 					HTML:     "<h1>My Cool Template</h1>",
 					Subject:  "{{.Subject}}",
 					ClientID: "some-client-id",
+					Version:  1,
+				}))
+			})
+
+			It("stores the update as the next version of the template", func() {
+				templatesRepository.GetForUpdateCall.Returns.Template = models.Template{
+					ID:      "existing-id",
+					Version: 3,
+				}
+				templatesRepository.UpdateCall.Returns.Template.Version = 4
+
+				template, err := templatesCollection.Set(conn, collections.Template{
+					ID:       "existing-id",
+					Name:     "new-template",
+					HTML:     "<h1>My Cool Template</h1>",
+					Subject:  "{{.Subject}}",
+					ClientID: "some-client-id",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(template.Version).To(Equal(4))
+
+				Expect(templatesRepository.GetForUpdateCall.Receives.Connection).To(Equal(conn))
+				Expect(templatesRepository.GetForUpdateCall.Receives.TemplateID).To(Equal("existing-id"))
+				Expect(templatesRepository.UpdateCall.Receives.Template.Version).To(Equal(4))
+				Expect(versionsRepository.InsertCall.Receives.TemplateVersion).To(Equal(models.TemplateVersion{
+					TemplateID: "existing-id",
+					Version:    4,
+					Name:       "new-template",
+					HTML:       "<h1>My Cool Template</h1>",
+					Subject:    "{{.Subject}}",
 				}))
 			})
 
@@ -121,6 +185,7 @@ var _ = Describe("TemplatesCollection", func() {
 						HTML:     "new default html",
 						Subject:  "New Default Subject",
 						ClientID: "",
+						Version:  1,
 					}))
 				})
 
@@ -144,6 +209,7 @@ var _ = Describe("TemplatesCollection", func() {
 						HTML:     "new default html",
 						Subject:  "New Default Subject",
 						ClientID: "",
+						Version:  1,
 					}))
 				})
 			})
@@ -175,6 +241,121 @@ var _ = Describe("TemplatesCollection", func() {
 					})
 					Expect(err).To(MatchError(collections.PersistenceError{repoError}))
 				})
+
+				It("returns a not found error when the template being updated does not exist", func() {
+					templatesRepository.GetForUpdateCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("not found")}
+
+					_, err := templatesCollection.Set(conn, collections.Template{
+						ID:   "missing-id",
+						Name: "new-template",
+					})
+					Expect(err).To(MatchError(collections.NotFoundError{Err: models.RecordNotFoundError{Err: errors.New("not found")}}))
+					Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{}))
+				})
+
+				It("returns a ValidationError when the metadata declares an unknown data type", func() {
+					_, err := templatesCollection.Set(conn, collections.Template{
+						Name:     "some-template",
+						ClientID: "some-client-id",
+						Metadata: `{"variables": {"app_name": {"type": "text"}}}`,
+					})
+					Expect(err).To(BeAssignableToTypeOf(collections.ValidationError{}))
+					Expect(err).To(MatchError(`metadata variable "app_name" has an unknown type "text", expected one of string, number, boolean, object, array`))
+					Expect(templatesRepository.InsertCall.Receives.Template).To(Equal(models.Template{}))
+				})
+
+				It("returns a PersistenceError when the version cannot be recorded", func() {
+					versionsRepository.InsertCall.Returns.Error = errors.New("version failure")
+
+					_, err := templatesCollection.Set(conn, collections.Template{
+						Name:     "some-template",
+						ClientID: "some-client-id",
+					})
+					Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("version failure")}))
+				})
+			})
+		})
+
+		Context("when the template declares a layout", func() {
+			It("stores the layout after checking the chain of layouts", func() {
+				templatesRepository.GetCall.Returns.Templates = []models.Template{
+					{ID: "inner-layout-id", ClientID: "some-client-id", HTML: `<div>{{template "content" .}}</div>`, LayoutID: "outer-layout-id"},
+					{ID: "outer-layout-id", ClientID: "some-client-id", Text: `{{template "content" .}}`},
+				}
+				templatesRepository.InsertCall.Returns.Template = models.Template{
+					ID:       "some-template-id",
+					Name:     "some-template",
+					ClientID: "some-client-id",
+					LayoutID: "inner-layout-id",
+				}
+
+				template, err := templatesCollection.Set(conn, collections.Template{
+					Name:     "some-template",
+					ClientID: "some-client-id",
+					LayoutID: "inner-layout-id",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(template.LayoutID).To(Equal("inner-layout-id"))
+
+				Expect(templatesRepository.GetCall.Receives.TemplateIDs).To(Equal([]string{"inner-layout-id", "outer-layout-id"}))
+				Expect(templatesRepository.InsertCall.Receives.Template.LayoutID).To(Equal("inner-layout-id"))
+			})
+
+			It("returns a ValidationError when the layout does not exist", func() {
+				templatesRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("not found")}
+
+				_, err := templatesCollection.Set(conn, collections.Template{
+					Name:     "some-template",
+					ClientID: "some-client-id",
+					LayoutID: "missing-layout-id",
+				})
+				Expect(err).To(MatchError(collections.ValidationError{Err: errors.New(`Layout "missing-layout-id" could not be found`)}))
+				Expect(templatesRepository.InsertCall.Receives.Template).To(Equal(models.Template{}))
+			})
+
+			It("returns a ValidationError when the layout belongs to another client", func() {
+				templatesRepository.GetCall.Returns.Template = models.Template{
+					ID:       "other-layout-id",
+					ClientID: "other-client-id",
+					Text:     `{{template "content" .}}`,
+				}
+
+				_, err := templatesCollection.Set(conn, collections.Template{
+					Name:     "some-template",
+					ClientID: "some-client-id",
+					LayoutID: "other-layout-id",
+				})
+				Expect(err).To(MatchError(collections.ValidationError{Err: errors.New(`Layout "other-layout-id" could not be found`)}))
+			})
+
+			It("returns a ValidationError when the layout does not include the content it wraps", func() {
+				templatesRepository.GetCall.Returns.Template = models.Template{
+					ID:       "some-layout-id",
+					ClientID: "some-client-id",
+					HTML:     "<div>no content</div>",
+				}
+
+				_, err := templatesCollection.Set(conn, collections.Template{
+					Name:     "some-template",
+					ClientID: "some-client-id",
+					LayoutID: "some-layout-id",
+				})
+				Expect(err).To(MatchError(collections.ValidationError{Err: errors.New(`Layout "some-layout-id" does not include {{template "content" .}}`)}))
+			})
+
+			It("returns a ValidationError when the layouts lead back to the template", func() {
+				templatesRepository.GetCall.Returns.Templates = []models.Template{
+					{ID: "some-layout-id", ClientID: "some-client-id", Text: `{{template "content" .}}`, LayoutID: "some-template-id"},
+				}
+
+				_, err := templatesCollection.Set(conn, collections.Template{
+					ID:       "some-template-id",
+					Name:     "some-template",
+					ClientID: "some-client-id",
+					LayoutID: "some-layout-id",
+				})
+				Expect(err).To(MatchError(collections.ValidationError{Err: errors.New(`Layout "some-layout-id" creates a circular reference`)}))
+				Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{}))
 			})
 		})
 	})
@@ -212,12 +393,13 @@ var _ = Describe("TemplatesCollection", func() {
 				template, err := templatesCollection.Get(conn, "default", "some-client-id")
 				Expect(err).NotTo(HaveOccurred())
 				Expect(template).To(Equal(collections.Template{
-					ID:       models.DefaultTemplate.ID,
-					Name:     models.DefaultTemplate.Name,
-					Text:     models.DefaultTemplate.Text,
-					HTML:     models.DefaultTemplate.HTML,
-					Subject:  models.DefaultTemplate.Subject,
-					Metadata: models.DefaultTemplate.Metadata,
+					ID:            models.DefaultTemplate.ID,
+					Name:          models.DefaultTemplate.Name,
+					Text:          models.DefaultTemplate.Text,
+					HTML:          models.DefaultTemplate.HTML,
+					Subject:       models.DefaultTemplate.Subject,
+					Metadata:      models.DefaultTemplate.Metadata,
+					Localizations: models.DefaultTemplate.Localizations,
 				}))
 			})
 		})
@@ -250,27 +432,514 @@ var _ = Describe("TemplatesCollection", func() {
 		})
 	})
 
+	Describe("Layouts", func() {
+		It("returns the layouts wrapping the template, innermost first", func() {
+			templatesRepository.GetCall.Returns.Templates = []models.Template{
+				{ID: "inner-layout-id", ClientID: "some-client-id", HTML: `<div>{{template "content" .}}</div>`, LayoutID: "outer-layout-id"},
+				{ID: "outer-layout-id", ClientID: "some-client-id", Text: `{{template "content" .}}`},
+			}
+
+			layouts, err := templatesCollection.Layouts(conn, collections.Template{
+				ID:       "some-template-id",
+				ClientID: "some-client-id",
+				LayoutID: "inner-layout-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(layouts).To(Equal([]collections.Template{
+				{ID: "inner-layout-id", ClientID: "some-client-id", HTML: `<div>{{template "content" .}}</div>`, LayoutID: "outer-layout-id"},
+				{ID: "outer-layout-id", ClientID: "some-client-id", Text: `{{template "content" .}}`},
+			}))
+		})
+
+		It("returns no layouts when the template does not declare one", func() {
+			layouts, err := templatesCollection.Layouts(conn, collections.Template{ID: "some-template-id"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(layouts).To(BeEmpty())
+			Expect(templatesRepository.GetCall.CallCount).To(Equal(0))
+		})
+
+		It("returns a NotFoundError when a layout has been deleted", func() {
+			templatesRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("not found")}
+
+			_, err := templatesCollection.Layouts(conn, collections.Template{
+				ClientID: "some-client-id",
+				LayoutID: "missing-layout-id",
+			})
+			Expect(err).To(MatchError(collections.NotFoundError{Err: errors.New(`Layout "missing-layout-id" could not be found`)}))
+		})
+
+		It("returns a PersistenceError when the repository fails", func() {
+			templatesRepository.GetCall.Returns.Error = errors.New("database is down")
+
+			_, err := templatesCollection.Layouts(conn, collections.Template{
+				ClientID: "some-client-id",
+				LayoutID: "some-layout-id",
+			})
+			Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("database is down")}))
+		})
+	})
+
 	Describe("Delete", func() {
 		It("deletes a template from the collection", func() {
-			err := templatesCollection.Delete(conn, "some-template-id")
+			err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "")
 			Expect(err).NotTo(HaveOccurred())
 
+			Expect(campaignTypesRepo.ListByTemplateIDCall.Receives.Connection).To(Equal(conn))
+			Expect(campaignTypesRepo.ListByTemplateIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+
+			Expect(campaignsRepo.ListSendingCampaignsByTemplateIDCall.Receives.Connection).To(Equal(conn))
+			Expect(campaignsRepo.ListSendingCampaignsByTemplateIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+
 			Expect(templatesRepository.DeleteCall.Receives.Connection).To(Equal(conn))
 			Expect(templatesRepository.DeleteCall.Receives.TemplateID).To(Equal("some-template-id"))
+
+			Expect(versionsRepository.DeleteAllCall.Receives.Connection).To(Equal(conn))
+			Expect(versionsRepository.DeleteAllCall.Receives.TemplateID).To(Equal("some-template-id"))
+		})
+
+		Context("when campaign types use the template", func() {
+			BeforeEach(func() {
+				campaignTypesRepo.ListByTemplateIDCall.Returns.CampaignTypeList = []models.CampaignType{
+					{ID: "some-campaign-type-id", Name: "some-campaign-type", TemplateID: "some-template-id"},
+				}
+
+				templatesRepository.GetCall.Returns.Template = models.Template{
+					ID:       "other-template-id",
+					ClientID: "some-client-id",
+				}
+			})
+
+			It("reassigns them to the replacement template", func() {
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "other-template-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(templatesRepository.GetCall.Receives.TemplateID).To(Equal("other-template-id"))
+
+				Expect(campaignTypesRepo.UpdateCall.CallCount).To(Equal(1))
+				Expect(campaignTypesRepo.UpdateCall.Receives.Connection).To(Equal(conn))
+				Expect(campaignTypesRepo.UpdateCall.Receives.CampaignType).To(Equal(models.CampaignType{
+					ID:         "some-campaign-type-id",
+					Name:       "some-campaign-type",
+					TemplateID: "other-template-id",
+				}))
+
+				Expect(templatesRepository.DeleteCall.Receives.TemplateID).To(Equal("some-template-id"))
+			})
+
+			It("returns an in use error when no replacement is given", func() {
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "")
+				Expect(err).To(MatchError(collections.InUseError{errors.New(`Template "some-template-id" is used by campaign types and cannot be deleted without a replacement template`)}))
+
+				Expect(campaignTypesRepo.UpdateCall.CallCount).To(Equal(0))
+				Expect(templatesRepository.DeleteCall.Receives.TemplateID).To(BeEmpty())
+			})
+
+			It("returns a validation error when the template would replace itself", func() {
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "some-template-id")
+				Expect(err).To(MatchError(collections.ValidationError{errors.New(`Template "some-template-id" cannot replace itself`)}))
+			})
+
+			It("returns a validation error when the replacement cannot be found", func() {
+				templatesRepository.GetCall.Returns.Error = models.NewRecordNotFoundError("")
+
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "missing-template-id")
+				Expect(err).To(MatchError(collections.ValidationError{errors.New(`Replacement template "missing-template-id" could not be found`)}))
+			})
+
+			It("returns a validation error when the replacement belongs to another client", func() {
+				templatesRepository.GetCall.Returns.Template.ClientID = "other-client-id"
+
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "other-template-id")
+				Expect(err).To(MatchError(collections.ValidationError{errors.New(`Replacement template "other-template-id" could not be found`)}))
+			})
+
+			It("returns a persistence error when a campaign type cannot be updated", func() {
+				campaignTypesRepo.UpdateCall.Returns.Error = errors.New("failed to update")
+
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "other-template-id")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to update")}))
+			})
+		})
+
+		Context("when templates use the template as their layout", func() {
+			BeforeEach(func() {
+				templatesRepository.ListByLayoutIDCall.Returns.Templates = []models.Template{
+					{
+						ID:       "some-wrapped-template-id",
+						Name:     "some-wrapped-template",
+						HTML:     "<p>wrapped</p>",
+						LayoutID: "some-template-id",
+						ClientID: "some-client-id",
+						Version:  2,
+					},
+				}
+
+				templatesRepository.GetCall.Returns.Template = models.Template{
+					ID:       "other-template-id",
+					HTML:     `<div>{{template "content" .}}</div>`,
+					ClientID: "some-client-id",
+				}
+				templatesRepository.GetForUpdateCall.Returns.Template = models.Template{
+					ID:      "some-wrapped-template-id",
+					Version: 2,
+				}
+				templatesRepository.UpdateCall.Returns.Template = models.Template{
+					ID:       "some-wrapped-template-id",
+					Name:     "some-wrapped-template",
+					HTML:     "<p>wrapped</p>",
+					LayoutID: "other-template-id",
+					ClientID: "some-client-id",
+					Version:  3,
+				}
+			})
+
+			It("wraps them in the replacement template and records a new version", func() {
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "other-template-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(templatesRepository.ListByLayoutIDCall.Receives.Connection).To(Equal(conn))
+				Expect(templatesRepository.ListByLayoutIDCall.Receives.LayoutID).To(Equal("some-template-id"))
+
+				Expect(templatesRepository.GetForUpdateCall.Receives.TemplateID).To(Equal("some-wrapped-template-id"))
+				Expect(templatesRepository.UpdateCall.Receives.Connection).To(Equal(conn))
+				Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{
+					ID:       "some-wrapped-template-id",
+					Name:     "some-wrapped-template",
+					HTML:     "<p>wrapped</p>",
+					LayoutID: "other-template-id",
+					ClientID: "some-client-id",
+					Version:  3,
+				}))
+
+				Expect(versionsRepository.InsertCall.Receives.TemplateVersion.TemplateID).To(Equal("some-wrapped-template-id"))
+				Expect(versionsRepository.InsertCall.Receives.TemplateVersion.LayoutID).To(Equal("other-template-id"))
+				Expect(versionsRepository.InsertCall.Receives.TemplateVersion.Version).To(Equal(3))
+
+				Expect(templatesRepository.DeleteCall.Receives.TemplateID).To(Equal("some-template-id"))
+			})
+
+			It("returns an in use error when no replacement is given", func() {
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "")
+				Expect(err).To(MatchError(collections.InUseError{Err: errors.New(`Template "some-template-id" is used as a layout by other templates and cannot be deleted without a replacement template`)}))
+
+				Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{}))
+				Expect(templatesRepository.DeleteCall.Receives.TemplateID).To(BeEmpty())
+			})
+
+			It("returns a validation error when the replacement is not a layout", func() {
+				templatesRepository.GetCall.Returns.Template.HTML = "<p>no content</p>"
+
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "other-template-id")
+				Expect(err).To(MatchError(collections.ValidationError{Err: errors.New(`Layout "other-template-id" does not include {{template "content" .}}`)}))
+
+				Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{}))
+				Expect(templatesRepository.DeleteCall.Receives.TemplateID).To(BeEmpty())
+			})
+
+			It("returns a validation error when the replacement would wrap itself", func() {
+				templatesRepository.ListByLayoutIDCall.Returns.Templates[0].ID = "other-template-id"
+
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "other-template-id")
+				Expect(err).To(MatchError(collections.ValidationError{Err: errors.New(`Layout "other-template-id" creates a circular reference`)}))
+
+				Expect(templatesRepository.DeleteCall.Receives.TemplateID).To(BeEmpty())
+			})
+
+			It("returns a persistence error when the templates cannot be listed", func() {
+				templatesRepository.ListByLayoutIDCall.Returns.Error = errors.New("failed to list")
+
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "other-template-id")
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("failed to list")}))
+			})
+
+			It("returns a persistence error when a template cannot be updated", func() {
+				templatesRepository.UpdateCall.Returns.Error = errors.New("failed to update")
+
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "other-template-id")
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("failed to update")}))
+				Expect(templatesRepository.DeleteCall.Receives.TemplateID).To(BeEmpty())
+			})
+		})
+
+		Context("when the template is the default template of the client", func() {
+			BeforeEach(func() {
+				clientTemplatesRepo.GetCall.Returns.Error = nil
+				clientTemplatesRepo.GetCall.Returns.ClientTemplate = models.ClientTemplate{
+					ClientID:   "some-client-id",
+					TemplateID: "some-template-id",
+				}
+
+				templatesRepository.GetCall.Returns.Template = models.Template{
+					ID:       "other-template-id",
+					ClientID: "some-client-id",
+				}
+			})
+
+			It("replaces it with the replacement template", func() {
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "other-template-id")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(clientTemplatesRepo.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+				Expect(clientTemplatesRepo.UpsertCall.Receives.Connection).To(Equal(conn))
+				Expect(clientTemplatesRepo.UpsertCall.Receives.ClientTemplate).To(Equal(models.ClientTemplate{
+					ClientID:   "some-client-id",
+					TemplateID: "other-template-id",
+				}))
+			})
+
+			It("clears it when there is no replacement", func() {
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(clientTemplatesRepo.UpsertCall.Receives.ClientTemplate).To(Equal(models.ClientTemplate{
+					ClientID: "some-client-id",
+				}))
+			})
+
+			It("leaves a different default template alone", func() {
+				clientTemplatesRepo.GetCall.Returns.ClientTemplate.TemplateID = "another-template-id"
+
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(clientTemplatesRepo.UpsertCall.Receives.ClientTemplate).To(Equal(models.ClientTemplate{}))
+			})
+
+			It("returns a persistence error when it cannot be replaced", func() {
+				clientTemplatesRepo.UpsertCall.Returns.Error = errors.New("failed to upsert")
+
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "")
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("failed to upsert")}))
+				Expect(templatesRepository.DeleteCall.Receives.TemplateID).To(BeEmpty())
+			})
+		})
+
+		Context("when campaigns are sending with the template", func() {
+			It("returns an in use error, even with a replacement", func() {
+				campaignsRepo.ListSendingCampaignsByTemplateIDCall.Returns.Campaigns = []models.Campaign{
+					{ID: "some-campaign-id", TemplateID: "some-template-id"},
+				}
+
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "other-template-id")
+				Expect(err).To(MatchError(collections.InUseError{errors.New(`Template "some-template-id" cannot be deleted while campaigns are sending with it`)}))
+
+				Expect(campaignTypesRepo.UpdateCall.CallCount).To(Equal(0))
+				Expect(templatesRepository.DeleteCall.Receives.TemplateID).To(BeEmpty())
+			})
 		})
 
 		Context("failure cases", func() {
 			It("returns a not found error if the template does not exist", func() {
 				templatesRepository.DeleteCall.Returns.Error = models.NewRecordNotFoundError("")
-				err := templatesCollection.Delete(conn, "missing-template-id")
+				err := templatesCollection.Delete(conn, "missing-template-id", "some-client-id", "")
 				Expect(err).To(BeAssignableToTypeOf(collections.NotFoundError{}))
 			})
 
 			It("returns a persistence error if one occurs", func() {
 				templatesRepository.DeleteCall.Returns.Error = errors.New("failed to delete")
-				err := templatesCollection.Delete(conn, "some-template-id")
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "")
 				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to delete")}))
 			})
+
+			It("returns a persistence error if the versions cannot be deleted", func() {
+				versionsRepository.DeleteAllCall.Returns.Error = errors.New("failed to delete versions")
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "")
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("failed to delete versions")}))
+			})
+
+			It("returns a persistence error if the campaign types cannot be listed", func() {
+				campaignTypesRepo.ListByTemplateIDCall.Returns.Error = errors.New("failed to list")
+				err := templatesCollection.Delete(conn, "some-template-id", "some-client-id", "")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to list")}))
+			})
+		})
+	})
+
+	Describe("ListAssociations", func() {
+		var startTime time.Time
+
+		BeforeEach(func() {
+			startTime = time.Now().Truncate(time.Second)
+
+			templatesRepository.GetCall.Returns.Template = models.Template{
+				ID:       "some-template-id",
+				ClientID: "some-client-id",
+			}
+
+			campaignTypesRepo.ListByTemplateIDCall.Returns.CampaignTypeList = []models.CampaignType{
+				{
+					ID:         "some-campaign-type-id",
+					Name:       "some-campaign-type",
+					TemplateID: "some-template-id",
+					SenderID:   "some-sender-id",
+					Headers:    `{"X-Some-Header":"some-value"}`,
+				},
+			}
+
+			campaignsRepo.ListSendingCampaignsByTemplateIDCall.Returns.Campaigns = []models.Campaign{
+				{
+					ID:              "some-campaign-id",
+					CampaignTypeID:  "some-campaign-type-id",
+					Subject:         "some-subject",
+					TemplateID:      "some-template-id",
+					TemplateVersion: 2,
+					SenderID:        "some-sender-id",
+					StartTime:       startTime,
+				},
+			}
+
+			templatesRepository.ListByLayoutIDCall.Returns.Templates = []models.Template{
+				{
+					ID:       "some-wrapped-template-id",
+					Name:     "some-wrapped-template",
+					LayoutID: "some-template-id",
+					ClientID: "some-client-id",
+				},
+			}
+		})
+
+		It("returns the campaign types, sending campaigns and templates using the template", func() {
+			associations, err := templatesCollection.ListAssociations(conn, "some-template-id", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(associations).To(Equal(collections.TemplateAssociations{
+				CampaignTypes: []collections.CampaignType{
+					{
+						ID:         "some-campaign-type-id",
+						Name:       "some-campaign-type",
+						TemplateID: "some-template-id",
+						SenderID:   "some-sender-id",
+						Headers:    map[string]string{"X-Some-Header": "some-value"},
+					},
+				},
+				Campaigns: []collections.Campaign{
+					{
+						ID:              "some-campaign-id",
+						CampaignTypeID:  "some-campaign-type-id",
+						Subject:         "some-subject",
+						TemplateID:      "some-template-id",
+						TemplateVersion: 2,
+						SenderID:        "some-sender-id",
+						StartTime:       startTime,
+					},
+				},
+				Templates: []collections.Template{
+					{
+						ID:       "some-wrapped-template-id",
+						Name:     "some-wrapped-template",
+						LayoutID: "some-template-id",
+						ClientID: "some-client-id",
+					},
+				},
+			}))
+
+			Expect(campaignTypesRepo.ListByTemplateIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(campaignsRepo.ListSendingCampaignsByTemplateIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(templatesRepository.ListByLayoutIDCall.Receives.LayoutID).To(Equal("some-template-id"))
+		})
+
+		Context("failure cases", func() {
+			It("returns a not found error if the template belongs to another client", func() {
+				templatesRepository.GetCall.Returns.Template.ClientID = "other-client-id"
+
+				_, err := templatesCollection.ListAssociations(conn, "some-template-id", "some-client-id")
+				Expect(err).To(BeAssignableToTypeOf(collections.NotFoundError{}))
+			})
+
+			It("returns a not found error for the default template", func() {
+				templatesRepository.GetCall.Returns.Template = models.DefaultTemplate
+
+				_, err := templatesCollection.ListAssociations(conn, "default", "some-client-id")
+				Expect(err).To(BeAssignableToTypeOf(collections.NotFoundError{}))
+			})
+
+			It("returns a persistence error if the campaigns cannot be listed", func() {
+				campaignsRepo.ListSendingCampaignsByTemplateIDCall.Returns.Error = errors.New("failed to list")
+
+				_, err := templatesCollection.ListAssociations(conn, "some-template-id", "some-client-id")
+				Expect(err).To(MatchError(collections.PersistenceError{errors.New("failed to list")}))
+			})
+
+			It("returns a persistence error if the templates using it as a layout cannot be listed", func() {
+				templatesRepository.ListByLayoutIDCall.Returns.Error = errors.New("failed to list")
+
+				_, err := templatesCollection.ListAssociations(conn, "some-template-id", "some-client-id")
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("failed to list")}))
+			})
+		})
+	})
+
+	Describe("UsedOnlyByCritical", func() {
+		BeforeEach(func() {
+			campaignTypesRepo.ListByTemplateIDCall.Returns.CampaignTypeList = []models.CampaignType{
+				{ID: "some-campaign-type-id", TemplateID: "some-template-id", Critical: true},
+				{ID: "other-campaign-type-id", TemplateID: "some-template-id", Critical: true},
+			}
+		})
+
+		It("is true when every campaign type using the template is critical", func() {
+			critical, err := templatesCollection.UsedOnlyByCritical(conn, "some-template-id", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(critical).To(BeTrue())
+
+			Expect(campaignTypesRepo.ListByTemplateIDCall.Receives.Connection).To(Equal(conn))
+			Expect(campaignTypesRepo.ListByTemplateIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(templatesRepository.ListByLayoutIDCall.Receives.LayoutID).To(Equal("some-template-id"))
+			Expect(clientTemplatesRepo.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+		})
+
+		It("is false when a campaign type using the template is not critical", func() {
+			campaignTypesRepo.ListByTemplateIDCall.Returns.CampaignTypeList[1].Critical = false
+
+			critical, err := templatesCollection.UsedOnlyByCritical(conn, "some-template-id", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(critical).To(BeFalse())
+		})
+
+		It("is false when no campaign type uses the template", func() {
+			campaignTypesRepo.ListByTemplateIDCall.Returns.CampaignTypeList = []models.CampaignType{}
+
+			critical, err := templatesCollection.UsedOnlyByCritical(conn, "some-template-id", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(critical).To(BeFalse())
+		})
+
+		It("is false when other templates use the template as their layout", func() {
+			templatesRepository.ListByLayoutIDCall.Returns.Templates = []models.Template{
+				{ID: "some-wrapped-template-id", LayoutID: "some-template-id"},
+			}
+
+			critical, err := templatesCollection.UsedOnlyByCritical(conn, "some-template-id", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(critical).To(BeFalse())
+		})
+
+		It("is false when the template is the default template of the client", func() {
+			clientTemplatesRepo.GetCall.Returns.Error = nil
+			clientTemplatesRepo.GetCall.Returns.ClientTemplate = models.ClientTemplate{
+				ClientID:   "some-client-id",
+				TemplateID: "some-template-id",
+			}
+
+			critical, err := templatesCollection.UsedOnlyByCritical(conn, "some-template-id", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(critical).To(BeFalse())
+		})
+
+		Context("failure cases", func() {
+			It("returns a persistence error if the campaign types cannot be listed", func() {
+				campaignTypesRepo.ListByTemplateIDCall.Returns.Error = errors.New("failed to list")
+
+				_, err := templatesCollection.UsedOnlyByCritical(conn, "some-template-id", "some-client-id")
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("failed to list")}))
+			})
+
+			It("returns a persistence error if the client template cannot be retrieved", func() {
+				clientTemplatesRepo.GetCall.Returns.Error = errors.New("failed to get")
+
+				_, err := templatesCollection.UsedOnlyByCritical(conn, "some-template-id", "some-client-id")
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("failed to get")}))
+			})
 		})
 	})
 
@@ -302,4 +971,172 @@ var _ = Describe("TemplatesCollection", func() {
 			})
 		})
 	})
+
+	Describe("versions", func() {
+		var createdAt time.Time
+
+		BeforeEach(func() {
+			createdAt = time.Date(2015, time.March, 4, 5, 6, 7, 0, time.UTC)
+
+			templatesRepository.GetCall.Returns.Template = models.Template{
+				ID:       "some-template-id",
+				Name:     "some-template",
+				HTML:     "<p>current</p>",
+				Subject:  "{{.Subject}}",
+				ClientID: "some-client-id",
+				Version:  3,
+			}
+			templatesRepository.GetForUpdateCall.Returns.Template = templatesRepository.GetCall.Returns.Template
+			templatesRepository.UpdateCall.Returns.Template = models.Template{
+				ID:       "some-template-id",
+				Name:     "some-template",
+				HTML:     "<p>first</p>",
+				Subject:  "First {{.Subject}}",
+				ClientID: "some-client-id",
+				Version:  4,
+			}
+
+			versionsRepository.GetCall.Returns.TemplateVersions = map[int]models.TemplateVersion{
+				1: {
+					TemplateID: "some-template-id",
+					Version:    1,
+					Name:       "some-template",
+					HTML:       "<p>first</p>",
+					Subject:    "First {{.Subject}}",
+					Metadata:   "{}",
+					CreatedAt:  createdAt,
+				},
+				3: {
+					TemplateID: "some-template-id",
+					Version:    3,
+					Name:       "some-template",
+					HTML:       "<p>current</p>",
+					Subject:    "{{.Subject}}",
+					Metadata:   "{}",
+					CreatedAt:  createdAt,
+				},
+			}
+			versionsRepository.ListCall.Returns.TemplateVersions = []models.TemplateVersion{
+				versionsRepository.GetCall.Returns.TemplateVersions[3],
+				versionsRepository.GetCall.Returns.TemplateVersions[1],
+			}
+		})
+
+		Describe("GetVersion", func() {
+			It("returns the requested version of the template", func() {
+				version, err := templatesCollection.GetVersion(conn, "some-template-id", "some-client-id", 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version).To(Equal(collections.TemplateVersion{
+					TemplateID: "some-template-id",
+					Version:    1,
+					Name:       "some-template",
+					HTML:       "<p>first</p>",
+					Subject:    "First {{.Subject}}",
+					Metadata:   "{}",
+					CreatedAt:  createdAt,
+				}))
+
+				Expect(versionsRepository.GetCall.Receives.Connection).To(Equal(conn))
+				Expect(versionsRepository.GetCall.Receives.TemplateID).To(Equal("some-template-id"))
+				Expect(versionsRepository.GetCall.Receives.Version).To(Equal(1))
+			})
+
+			Context("failure cases", func() {
+				It("returns a not found error when the template belongs to another client", func() {
+					_, err := templatesCollection.GetVersion(conn, "some-template-id", "other-client-id", 1)
+					Expect(err).To(BeAssignableToTypeOf(collections.NotFoundError{}))
+				})
+
+				It("returns a not found error when the version does not exist", func() {
+					versionsRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("no such version")}
+
+					_, err := templatesCollection.GetVersion(conn, "some-template-id", "some-client-id", 9)
+					Expect(err).To(MatchError(collections.NotFoundError{Err: models.RecordNotFoundError{Err: errors.New("no such version")}}))
+				})
+
+				It("returns a persistence error when the version cannot be retrieved", func() {
+					versionsRepository.GetCall.Returns.Error = errors.New("database failure")
+
+					_, err := templatesCollection.GetVersion(conn, "some-template-id", "some-client-id", 1)
+					Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("database failure")}))
+				})
+			})
+		})
+
+		Describe("ListVersions", func() {
+			It("returns the versions of the template, newest first", func() {
+				versions, err := templatesCollection.ListVersions(conn, "some-template-id", "some-client-id")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versions).To(HaveLen(2))
+				Expect(versions[0].Version).To(Equal(3))
+				Expect(versions[1].Version).To(Equal(1))
+
+				Expect(versionsRepository.ListCall.Receives.Connection).To(Equal(conn))
+				Expect(versionsRepository.ListCall.Receives.TemplateID).To(Equal("some-template-id"))
+			})
+
+			Context("failure cases", func() {
+				It("returns a not found error when the template belongs to another client", func() {
+					_, err := templatesCollection.ListVersions(conn, "some-template-id", "other-client-id")
+					Expect(err).To(BeAssignableToTypeOf(collections.NotFoundError{}))
+				})
+
+				It("returns a persistence error when the versions cannot be listed", func() {
+					versionsRepository.ListCall.Returns.Error = errors.New("database failure")
+
+					_, err := templatesCollection.ListVersions(conn, "some-template-id", "some-client-id")
+					Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("database failure")}))
+				})
+			})
+		})
+
+		Describe("DiffVersions", func() {
+			It("diffs the fields that changed between two versions", func() {
+				diff, err := templatesCollection.DiffVersions(conn, "some-template-id", "some-client-id", 1, 3)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(diff).To(Equal(collections.TemplateDiff{
+					TemplateID: "some-template-id",
+					From:       1,
+					To:         3,
+					HTML:       "-<p>first</p>\n+<p>current</p>",
+					Subject:    "-First {{.Subject}}\n+{{.Subject}}",
+				}))
+			})
+
+			It("returns a not found error when either version does not exist", func() {
+				versionsRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("no such version")}
+
+				_, err := templatesCollection.DiffVersions(conn, "some-template-id", "some-client-id", 1, 9)
+				Expect(err).To(BeAssignableToTypeOf(collections.NotFoundError{}))
+			})
+		})
+
+		Describe("Rollback", func() {
+			It("stores the content of the earlier version as a new version", func() {
+				template, err := templatesCollection.Rollback(conn, "some-template-id", "some-client-id", 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(template.Version).To(Equal(4))
+				Expect(template.HTML).To(Equal("<p>first</p>"))
+
+				Expect(templatesRepository.UpdateCall.Receives.Template).To(Equal(models.Template{
+					ID:       "some-template-id",
+					Name:     "some-template",
+					HTML:     "<p>first</p>",
+					Subject:  "First {{.Subject}}",
+					Metadata: "{}",
+					ClientID: "some-client-id",
+					Version:  4,
+				}))
+				Expect(versionsRepository.InsertCall.Receives.TemplateVersion.Version).To(Equal(4))
+			})
+
+			It("returns a not found error when the version does not exist", func() {
+				versionsRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("no such version")}
+
+				_, err := templatesCollection.Rollback(conn, "some-template-id", "some-client-id", 9)
+				Expect(err).To(BeAssignableToTypeOf(collections.NotFoundError{}))
+				Expect(versionsRepository.InsertCall.CallCount).To(Equal(0))
+			})
+		})
+	})
 })
@@ -0,0 +1,285 @@
+package collections
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+	"github.com/pivotal-golang/conceal"
+)
+
+type Transport struct {
+	Name          string
+	Host          string
+	Port          string
+	User          string
+	Pass          string
+	CRAMMD5Secret string
+	XOAUTH2Token  string
+	AuthMechanism string
+	TLSMode       string
+}
+
+type transportsRepository interface {
+	Upsert(conn models.ConnectionInterface, transport models.Transport) (models.Transport, error)
+	Get(conn models.ConnectionInterface, name string) (models.Transport, error)
+	List(conn models.ConnectionInterface) ([]models.Transport, error)
+	Delete(conn models.ConnectionInterface, transport models.Transport) error
+	CountReferences(conn models.ConnectionInterface, name string) (int64, error)
+	CountClientReferences(conn models.ConnectionInterface, name, clientID string) (int64, error)
+}
+
+type transportGrantsRepository interface {
+	Upsert(conn models.ConnectionInterface, grant models.TransportGrant) (models.TransportGrant, error)
+	Get(conn models.ConnectionInterface, transportName, clientID string) (models.TransportGrant, error)
+	List(conn models.ConnectionInterface, transportName string) ([]models.TransportGrant, error)
+	Delete(conn models.ConnectionInterface, grant models.TransportGrant) error
+	DeleteByTransportName(conn models.ConnectionInterface, transportName string) error
+}
+
+// TransportsCollection stores named SMTP relay configurations. Credentials
+// are encrypted with the cloak before they are persisted. A client may only
+// bind its senders to the transports it has been granted.
+type TransportsCollection struct {
+	transports transportsRepository
+	grants     transportGrantsRepository
+	cloak      conceal.CloakInterface
+}
+
+func NewTransportsCollection(transports transportsRepository, grants transportGrantsRepository, cloak conceal.CloakInterface) TransportsCollection {
+	return TransportsCollection{
+		transports: transports,
+		grants:     grants,
+		cloak:      cloak,
+	}
+}
+
+func (c TransportsCollection) Set(conn ConnectionInterface, transport Transport) (Transport, error) {
+	model := models.Transport{
+		Name:          transport.Name,
+		Host:          transport.Host,
+		Port:          transport.Port,
+		User:          transport.User,
+		AuthMechanism: transport.AuthMechanism,
+		TLSMode:       transport.TLSMode,
+	}
+
+	var err error
+	model.Pass, err = c.veil(transport.Pass)
+	if err != nil {
+		return Transport{}, UnknownError{err}
+	}
+
+	model.CRAMMD5Secret, err = c.veil(transport.CRAMMD5Secret)
+	if err != nil {
+		return Transport{}, UnknownError{err}
+	}
+
+	model.XOAUTH2Token, err = c.veil(transport.XOAUTH2Token)
+	if err != nil {
+		return Transport{}, UnknownError{err}
+	}
+
+	_, err = c.transports.Upsert(conn, model)
+	if err != nil {
+		return Transport{}, PersistenceError{err}
+	}
+
+	return transport, nil
+}
+
+func (c TransportsCollection) Get(conn ConnectionInterface, name string) (Transport, error) {
+	model, err := c.transports.Get(conn, name)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return Transport{}, NotFoundError{err}
+		default:
+			return Transport{}, PersistenceError{err}
+		}
+	}
+
+	return c.unveil(model)
+}
+
+func (c TransportsCollection) List(conn ConnectionInterface) ([]Transport, error) {
+	transports := []Transport{}
+
+	models, err := c.transports.List(conn)
+	if err != nil {
+		return transports, PersistenceError{err}
+	}
+
+	for _, model := range models {
+		transport, err := c.unveil(model)
+		if err != nil {
+			return []Transport{}, err
+		}
+
+		transports = append(transports, transport)
+	}
+
+	return transports, nil
+}
+
+func (c TransportsCollection) Delete(conn ConnectionInterface, name string) error {
+	model, err := c.transports.Get(conn, name)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return NotFoundError{err}
+		default:
+			return UnknownError{err}
+		}
+	}
+
+	count, err := c.transports.CountReferences(conn, name)
+	if err != nil {
+		return UnknownError{err}
+	}
+
+	if count > 0 {
+		return InUseError{fmt.Errorf("Transport %q is bound to senders or clients and cannot be deleted", name)}
+	}
+
+	err = c.grants.DeleteByTransportName(conn, name)
+	if err != nil {
+		return UnknownError{err}
+	}
+
+	err = c.transports.Delete(conn, model)
+	if err != nil {
+		return UnknownError{err}
+	}
+
+	return nil
+}
+
+func (c TransportsCollection) Grant(conn ConnectionInterface, name, clientID string) error {
+	_, err := c.transports.Get(conn, name)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return NotFoundError{err}
+		default:
+			return PersistenceError{err}
+		}
+	}
+
+	_, err = c.grants.Upsert(conn, models.TransportGrant{
+		TransportName: name,
+		ClientID:      clientID,
+	})
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	return nil
+}
+
+func (c TransportsCollection) Revoke(conn ConnectionInterface, name, clientID string) error {
+	grant, err := c.grants.Get(conn, name, clientID)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return NotFoundError{err}
+		default:
+			return PersistenceError{err}
+		}
+	}
+
+	count, err := c.transports.CountClientReferences(conn, name, clientID)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	if count > 0 {
+		return InUseError{fmt.Errorf("Transport %q is bound to senders of client %q and cannot be revoked", name, clientID)}
+	}
+
+	err = c.grants.Delete(conn, grant)
+	if err != nil {
+		return PersistenceError{err}
+	}
+
+	return nil
+}
+
+func (c TransportsCollection) ListGrants(conn ConnectionInterface, name string) ([]string, error) {
+	clientIDs := []string{}
+
+	_, err := c.transports.Get(conn, name)
+	if err != nil {
+		switch err.(type) {
+		case models.RecordNotFoundError:
+			return clientIDs, NotFoundError{err}
+		default:
+			return clientIDs, PersistenceError{err}
+		}
+	}
+
+	grants, err := c.grants.List(conn, name)
+	if err != nil {
+		return clientIDs, PersistenceError{err}
+	}
+
+	for _, grant := range grants {
+		clientIDs = append(clientIDs, grant.ClientID)
+	}
+
+	return clientIDs, nil
+}
+
+func (c TransportsCollection) veil(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	ciphertext, err := c.cloak.Veil([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return string(ciphertext), nil
+}
+
+func (c TransportsCollection) unveil(model models.Transport) (Transport, error) {
+	var err error
+	transport := Transport{
+		Name:          model.Name,
+		Host:          model.Host,
+		Port:          model.Port,
+		User:          model.User,
+		AuthMechanism: model.AuthMechanism,
+		TLSMode:       model.TLSMode,
+	}
+
+	transport.Pass, err = c.unveilString(model.Pass)
+	if err != nil {
+		return Transport{}, UnknownError{err}
+	}
+
+	transport.CRAMMD5Secret, err = c.unveilString(model.CRAMMD5Secret)
+	if err != nil {
+		return Transport{}, UnknownError{err}
+	}
+
+	transport.XOAUTH2Token, err = c.unveilString(model.XOAUTH2Token)
+	if err != nil {
+		return Transport{}, UnknownError{err}
+	}
+
+	return transport, nil
+}
+
+func (c TransportsCollection) unveilString(ciphertext string) (string, error) {
+	if ciphertext == "" {
+		return "", nil
+	}
+
+	plaintext, err := c.cloak.Unveil([]byte(ciphertext))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
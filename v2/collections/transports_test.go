@@ -0,0 +1,268 @@
+package collections_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransportsCollection", func() {
+	var (
+		transportsCollection collections.TransportsCollection
+		transportsRepository *mocks.TransportsRepository
+		transportGrants      *mocks.TransportGrantsRepository
+		cloak                *mocks.Cloak
+		conn                 *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		transportsRepository = mocks.NewTransportsRepository()
+		transportGrants = mocks.NewTransportGrantsRepository()
+		cloak = mocks.NewCloak()
+		conn = mocks.NewConnection()
+
+		transportsCollection = collections.NewTransportsCollection(transportsRepository, transportGrants, cloak)
+	})
+
+	Describe("Set", func() {
+		It("encrypts the credentials before persisting the transport", func() {
+			cloak.VeilCall.Returns.CipherText = []byte("encrypted-pass")
+
+			transport, err := transportsCollection.Set(conn, collections.Transport{
+				Name:          "corporate-relay",
+				Host:          "smtp.example.com",
+				Port:          "587",
+				User:          "some-user",
+				Pass:          "some-pass",
+				AuthMechanism: "plain",
+				TLSMode:       "starttls",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transport.Pass).To(Equal("some-pass"))
+
+			Expect(cloak.VeilCall.Receives.PlainText).To(Equal([]byte("some-pass")))
+			Expect(transportsRepository.UpsertCall.Receives.Connection).To(Equal(conn))
+			Expect(transportsRepository.UpsertCall.Receives.Transport).To(Equal(models.Transport{
+				Name:          "corporate-relay",
+				Host:          "smtp.example.com",
+				Port:          "587",
+				User:          "some-user",
+				Pass:          "encrypted-pass",
+				AuthMechanism: "plain",
+				TLSMode:       "starttls",
+			}))
+		})
+
+		Context("when encryption fails", func() {
+			It("returns an unknown error", func() {
+				cloak.VeilCall.Returns.Error = errors.New("cipher failure")
+
+				_, err := transportsCollection.Set(conn, collections.Transport{
+					Name: "corporate-relay",
+					Pass: "some-pass",
+				})
+				Expect(err).To(MatchError(collections.UnknownError{Err: errors.New("cipher failure")}))
+			})
+		})
+
+		Context("when the repository errors", func() {
+			It("returns a persistence error", func() {
+				transportsRepository.UpsertCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := transportsCollection.Set(conn, collections.Transport{Name: "corporate-relay"})
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("BOOM!")}))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		It("decrypts the credentials", func() {
+			transportsRepository.GetCall.Returns.Transport = models.Transport{
+				Name: "corporate-relay",
+				Host: "smtp.example.com",
+				Port: "587",
+				User: "some-user",
+				Pass: "encrypted-pass",
+			}
+			cloak.UnveilCall.Returns.PlainText = []byte("some-pass")
+
+			transport, err := transportsCollection.Get(conn, "corporate-relay")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transport).To(Equal(collections.Transport{
+				Name: "corporate-relay",
+				Host: "smtp.example.com",
+				Port: "587",
+				User: "some-user",
+				Pass: "some-pass",
+			}))
+
+			Expect(transportsRepository.GetCall.Receives.Name).To(Equal("corporate-relay"))
+			Expect(cloak.UnveilCall.Receives.CipherText).To(Equal([]byte("encrypted-pass")))
+		})
+
+		Context("when the transport does not exist", func() {
+			It("returns a not found error", func() {
+				transportsRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("not found")}
+
+				_, err := transportsCollection.Get(conn, "missing-relay")
+				Expect(err).To(MatchError(collections.NotFoundError{Err: models.RecordNotFoundError{Err: errors.New("not found")}}))
+			})
+		})
+	})
+
+	Describe("List", func() {
+		It("returns all transports", func() {
+			transportsRepository.ListCall.Returns.Transports = []models.Transport{
+				{Name: "relay-a", Host: "a.example.com"},
+				{Name: "relay-b", Host: "b.example.com"},
+			}
+
+			transports, err := transportsCollection.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transports).To(Equal([]collections.Transport{
+				{Name: "relay-a", Host: "a.example.com"},
+				{Name: "relay-b", Host: "b.example.com"},
+			}))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the transport", func() {
+			transportsRepository.GetCall.Returns.Transport = models.Transport{Name: "corporate-relay"}
+
+			err := transportsCollection.Delete(conn, "corporate-relay")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transportsRepository.CountReferencesCall.Receives.Name).To(Equal("corporate-relay"))
+			Expect(transportGrants.DeleteByTransportNameCall.Receives.TransportName).To(Equal("corporate-relay"))
+			Expect(transportsRepository.DeleteCall.Receives.Transport).To(Equal(models.Transport{Name: "corporate-relay"}))
+		})
+
+		Context("when the transport does not exist", func() {
+			It("returns a not found error", func() {
+				transportsRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("not found")}
+
+				err := transportsCollection.Delete(conn, "missing-relay")
+				Expect(err).To(MatchError(collections.NotFoundError{Err: models.RecordNotFoundError{Err: errors.New("not found")}}))
+			})
+		})
+
+		Context("when senders or clients are bound to the transport", func() {
+			It("returns an in use error without deleting it", func() {
+				transportsRepository.GetCall.Returns.Transport = models.Transport{Name: "corporate-relay"}
+				transportsRepository.CountReferencesCall.Returns.Count = 2
+
+				err := transportsCollection.Delete(conn, "corporate-relay")
+				Expect(err).To(MatchError(collections.InUseError{Err: errors.New(`Transport "corporate-relay" is bound to senders or clients and cannot be deleted`)}))
+				Expect(transportGrants.DeleteByTransportNameCall.Receives.TransportName).To(BeEmpty())
+				Expect(transportsRepository.DeleteCall.Receives.Transport).To(Equal(models.Transport{}))
+			})
+		})
+
+		Context("when the references cannot be counted", func() {
+			It("returns an unknown error", func() {
+				transportsRepository.CountReferencesCall.Returns.Error = errors.New("BOOM!")
+
+				err := transportsCollection.Delete(conn, "corporate-relay")
+				Expect(err).To(MatchError(collections.UnknownError{Err: errors.New("BOOM!")}))
+			})
+		})
+	})
+
+	Describe("Grant", func() {
+		It("grants the transport to the client", func() {
+			err := transportsCollection.Grant(conn, "corporate-relay", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transportsRepository.GetCall.Receives.Name).To(Equal("corporate-relay"))
+			Expect(transportGrants.UpsertCall.Receives.Connection).To(Equal(conn))
+			Expect(transportGrants.UpsertCall.Receives.Grant).To(Equal(models.TransportGrant{
+				TransportName: "corporate-relay",
+				ClientID:      "some-client-id",
+			}))
+		})
+
+		Context("when the transport does not exist", func() {
+			It("returns a not found error", func() {
+				transportsRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("not found")}
+
+				err := transportsCollection.Grant(conn, "missing-relay", "some-client-id")
+				Expect(err).To(MatchError(collections.NotFoundError{Err: models.RecordNotFoundError{Err: errors.New("not found")}}))
+			})
+		})
+
+		Context("when the grant cannot be saved", func() {
+			It("returns a persistence error", func() {
+				transportGrants.UpsertCall.Returns.Error = errors.New("BOOM!")
+
+				err := transportsCollection.Grant(conn, "corporate-relay", "some-client-id")
+				Expect(err).To(MatchError(collections.PersistenceError{Err: errors.New("BOOM!")}))
+			})
+		})
+	})
+
+	Describe("Revoke", func() {
+		It("revokes the grant", func() {
+			transportGrants.GetCall.Returns.Grant = models.TransportGrant{
+				TransportName: "corporate-relay",
+				ClientID:      "some-client-id",
+			}
+
+			err := transportsCollection.Revoke(conn, "corporate-relay", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transportsRepository.CountClientReferencesCall.Receives.Name).To(Equal("corporate-relay"))
+			Expect(transportsRepository.CountClientReferencesCall.Receives.ClientID).To(Equal("some-client-id"))
+			Expect(transportGrants.DeleteCall.Receives.Grant).To(Equal(models.TransportGrant{
+				TransportName: "corporate-relay",
+				ClientID:      "some-client-id",
+			}))
+		})
+
+		Context("when the grant does not exist", func() {
+			It("returns a not found error", func() {
+				transportGrants.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("not found")}
+
+				err := transportsCollection.Revoke(conn, "corporate-relay", "some-client-id")
+				Expect(err).To(MatchError(collections.NotFoundError{Err: models.RecordNotFoundError{Err: errors.New("not found")}}))
+			})
+		})
+
+		Context("when senders of the client are bound to the transport", func() {
+			It("returns an in use error without revoking the grant", func() {
+				transportsRepository.CountClientReferencesCall.Returns.Count = 1
+
+				err := transportsCollection.Revoke(conn, "corporate-relay", "some-client-id")
+				Expect(err).To(MatchError(collections.InUseError{Err: errors.New(`Transport "corporate-relay" is bound to senders of client "some-client-id" and cannot be revoked`)}))
+				Expect(transportGrants.DeleteCall.Receives.Grant).To(Equal(models.TransportGrant{}))
+			})
+		})
+	})
+
+	Describe("ListGrants", func() {
+		It("returns the clients granted the transport", func() {
+			transportGrants.ListCall.Returns.Grants = []models.TransportGrant{
+				{TransportName: "corporate-relay", ClientID: "client-a"},
+				{TransportName: "corporate-relay", ClientID: "client-b"},
+			}
+
+			clientIDs, err := transportsCollection.ListGrants(conn, "corporate-relay")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clientIDs).To(Equal([]string{"client-a", "client-b"}))
+			Expect(transportGrants.ListCall.Receives.TransportName).To(Equal("corporate-relay"))
+		})
+
+		Context("when the transport does not exist", func() {
+			It("returns a not found error", func() {
+				transportsRepository.GetCall.Returns.Error = models.RecordNotFoundError{Err: errors.New("not found")}
+
+				_, err := transportsCollection.ListGrants(conn, "missing-relay")
+				Expect(err).To(MatchError(collections.NotFoundError{Err: models.RecordNotFoundError{Err: errors.New("not found")}}))
+			})
+		})
+	})
+})
@@ -18,6 +18,7 @@ type Unsubscriber struct {
 
 type unsubscribersSetterDeleter interface {
 	Insert(connection models.ConnectionInterface, unsubscriber models.Unsubscriber) (models.Unsubscriber, error)
+	Get(connection models.ConnectionInterface, userGUID, campaignTypeID string) (models.Unsubscriber, error)
 	Delete(connection models.ConnectionInterface, unsubscriber models.Unsubscriber) error
 }
 
@@ -64,7 +65,14 @@ func (c UnsubscribersCollection) Set(connection ConnectionInterface, unsubscribe
 		UserGUID:       unsubscriber.UserGUID,
 	})
 	if err != nil {
-		return Unsubscriber{}, err
+		if _, ok := err.(models.DuplicateRecordError); !ok {
+			return Unsubscriber{}, err
+		}
+
+		unsub, err = c.unsubscribersRepository.Get(connection, unsubscriber.UserGUID, unsubscriber.CampaignTypeID)
+		if err != nil {
+			return Unsubscriber{}, err
+		}
 	}
 
 	unsubscriber.ID = unsub.ID
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type Attachment struct {
+	ID          string    `db:"id"`
+	CampaignID  string    `db:"campaign_id"`
+	Filename    string    `db:"filename"`
+	ContentType string    `db:"content_type"`
+	ContentID   string    `db:"content_id"`
+	Content     []byte    `db:"content"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type AttachmentsRepository struct {
+	guidGenerator guidGeneratorFunc
+	clock         clock
+}
+
+func NewAttachmentsRepository(guidGenerator guidGeneratorFunc, clock clock) AttachmentsRepository {
+	return AttachmentsRepository{
+		guidGenerator: guidGenerator,
+		clock:         clock,
+	}
+}
+
+func (r AttachmentsRepository) Insert(conn ConnectionInterface, attachment Attachment) (Attachment, error) {
+	var err error
+	attachment.ID, err = r.guidGenerator()
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	attachment.CreatedAt = r.clock.Now()
+
+	err = conn.Insert(&attachment)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+func (r AttachmentsRepository) Get(conn ConnectionInterface, attachmentID string) (Attachment, error) {
+	attachment := Attachment{}
+
+	err := conn.SelectOne(&attachment, "SELECT * FROM `attachments` WHERE `id` = ?", attachmentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return attachment, RecordNotFoundError{fmt.Errorf("Attachment with id %q could not be found", attachmentID)}
+		}
+
+		return attachment, err
+	}
+
+	return attachment, nil
+}
@@ -0,0 +1,99 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AttachmentsRepository", func() {
+	var (
+		repo          models.AttachmentsRepository
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+		clock         *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid"}
+		clock = &mocks.Clock{}
+		clock.NowCall.Returns.Time = time.Now().UTC().Truncate(time.Second)
+
+		repo = models.NewAttachmentsRepository(guidGenerator.Generate, clock)
+		conn = database.Connection()
+	})
+
+	Describe("Insert", func() {
+		It("returns the inserted record", func() {
+			attachment, err := repo.Insert(conn, models.Attachment{
+				CampaignID:  "some-campaign-id",
+				Filename:    "invoice.pdf",
+				ContentType: "application/pdf",
+				Content:     []byte("some-content"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(attachment.ID).To(Equal("first-random-guid"))
+			Expect(attachment.CreatedAt).To(Equal(clock.NowCall.Returns.Time))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error when the guid generator fails", func() {
+				guidGenerator.GenerateCall.Returns.Error = errors.New("some-guid-error")
+
+				_, err := repo.Insert(conn, models.Attachment{})
+				Expect(err).To(MatchError(errors.New("some-guid-error")))
+			})
+
+			It("returns an error when the database blows up", func() {
+				connection := mocks.NewConnection()
+				connection.InsertCall.Returns.Error = errors.New("some-db-error")
+
+				_, err := repo.Insert(connection, models.Attachment{})
+				Expect(err).To(MatchError(errors.New("some-db-error")))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		It("fetches the attachment given an id", func() {
+			attachment, err := repo.Insert(conn, models.Attachment{
+				CampaignID:  "some-campaign-id",
+				Filename:    "logo.png",
+				ContentType: "image/png",
+				ContentID:   "logo",
+				Content:     []byte("some-content"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			retrievedAttachment, err := repo.Get(conn, attachment.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retrievedAttachment).To(Equal(attachment))
+		})
+
+		Context("failure cases", func() {
+			It("returns a RecordNotFoundError when the attachment does not exist", func() {
+				_, err := repo.Get(conn, "missing-attachment-id")
+				Expect(err).To(MatchError(models.RecordNotFoundError{errors.New(`Attachment with id "missing-attachment-id" could not be found`)}))
+			})
+
+			It("returns an error when the database blows up", func() {
+				connection := mocks.NewConnection()
+				connection.SelectOneCall.Returns.Error = errors.New("some-db-error")
+
+				_, err := repo.Get(connection, "some-attachment-id")
+				Expect(err).To(MatchError(errors.New("some-db-error")))
+			})
+		})
+	})
+})
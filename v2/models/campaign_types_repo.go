@@ -11,8 +11,12 @@ type CampaignType struct {
 	Name        string `db:"name"`
 	Description string `db:"description"`
 	Critical    bool   `db:"critical"`
+	Threading   bool   `db:"threading"`
 	TemplateID  string `db:"template_id"`
 	SenderID    string `db:"sender_id"`
+	Headers     string `db:"headers"`
+	FromAddress string `db:"from_address"`
+	FromName    string `db:"from_name"`
 }
 
 type CampaignTypesRepository struct {
@@ -64,6 +68,12 @@ func (r CampaignTypesRepository) List(connection ConnectionInterface, senderID s
 	return campaignTypeList, err
 }
 
+func (r CampaignTypesRepository) ListByTemplateID(connection ConnectionInterface, templateID string) ([]CampaignType, error) {
+	campaignTypeList := []CampaignType{}
+	_, err := connection.Select(&campaignTypeList, "SELECT * FROM `campaign_types` WHERE `template_id` = ?", templateID)
+	return campaignTypeList, err
+}
+
 func (r CampaignTypesRepository) Get(connection ConnectionInterface, campaignTypeID string) (CampaignType, error) {
 	campaignType := CampaignType{}
 	err := connection.SelectOne(&campaignType, "SELECT * FROM `campaign_types` WHERE `id` = ?", campaignTypeID)
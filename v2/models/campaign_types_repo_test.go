@@ -153,6 +153,37 @@ var _ = Describe("CampaignTypesRepo", func() {
 		})
 	})
 
+	Describe("ListByTemplateID", func() {
+		It("fetches the records that use the template", func() {
+			campaignType, err := repo.Insert(conn, models.CampaignType{
+				Name:       "campaign-type-one",
+				TemplateID: "some-template-id",
+				SenderID:   "some-sender-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(conn, models.CampaignType{
+				Name:       "campaign-type-two",
+				TemplateID: "other-template-id",
+				SenderID:   "some-sender-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			campaignTypes, err := repo.ListByTemplateID(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(campaignTypes).To(Equal([]models.CampaignType{campaignType}))
+		})
+
+		Context("failure cases", func() {
+			It("returns errors", func() {
+				conn := mocks.NewConnection()
+				conn.SelectCall.Returns.Error = errors.New("BOOM!")
+				_, err := repo.ListByTemplateID(conn, "some-template-id")
+				Expect(err).To(MatchError("BOOM!"))
+			})
+		})
+	})
+
 	Describe("Get", func() {
 		It("fetches a record from the database", func() {
 			campaignType, err := repo.Insert(conn, models.CampaignType{
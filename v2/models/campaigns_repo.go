@@ -9,22 +9,24 @@ import (
 )
 
 type Campaign struct {
-	ID             string         `db:"id"`
-	SendTo         string         `db:"send_to"`
-	CampaignTypeID string         `db:"campaign_type_id"`
-	Text           string         `db:"text"`
-	HTML           string         `db:"html"`
-	Subject        string         `db:"subject"`
-	TemplateID     string         `db:"template_id"`
-	ReplyTo        string         `db:"reply_to"`
-	SenderID       string         `db:"sender_id"`
-	Status         string         `db:"status"`
-	TotalMessages  int            `db:"total_messages"`
-	SentMessages   int            `db:"sent_messages"`
-	RetryMessages  int            `db:"retry_messages"`
-	FailedMessages int            `db:"failed_messages"`
-	StartTime      time.Time      `db:"start_time"`
-	CompletedTime  mysql.NullTime `db:"completed_time"`
+	ID              string         `db:"id"`
+	SendTo          string         `db:"send_to"`
+	CampaignTypeID  string         `db:"campaign_type_id"`
+	Text            string         `db:"text"`
+	HTML            string         `db:"html"`
+	Subject         string         `db:"subject"`
+	TemplateID      string         `db:"template_id"`
+	TemplateVersion int            `db:"template_version"`
+	TemplateSource  string         `db:"template_source"`
+	ReplyTo         string         `db:"reply_to"`
+	SenderID        string         `db:"sender_id"`
+	Status          string         `db:"status"`
+	TotalMessages   int            `db:"total_messages"`
+	SentMessages    int            `db:"sent_messages"`
+	RetryMessages   int            `db:"retry_messages"`
+	FailedMessages  int            `db:"failed_messages"`
+	StartTime       time.Time      `db:"start_time"`
+	CompletedTime   mysql.NullTime `db:"completed_time"`
 }
 
 type CampaignsRepository struct {
@@ -80,3 +82,11 @@ func (r CampaignsRepository) ListSendingCampaigns(conn ConnectionInterface) ([]C
 
 	return campaignList, err
 }
+
+func (r CampaignsRepository) ListSendingCampaignsByTemplateID(conn ConnectionInterface, templateID string) ([]Campaign, error) {
+	campaignList := []Campaign{}
+
+	_, err := conn.Select(&campaignList, "SELECT * FROM `campaigns` WHERE `status` != \"completed\" AND `template_id` = ?", templateID)
+
+	return campaignList, err
+}
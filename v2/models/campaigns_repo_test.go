@@ -156,4 +156,51 @@ var _ = Describe("CampaignsRepository", func() {
 			})
 		})
 	})
+
+	Describe("ListSendingCampaignsByTemplateID", func() {
+		var campaign models.Campaign
+
+		BeforeEach(func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid", "third-random-guid"}
+
+			var err error
+			campaign, err = repo.Insert(connection, models.Campaign{
+				TemplateID: "some-template-id",
+				Status:     "sending",
+				StartTime:  time.Now().UTC().Truncate(time.Second),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(connection, models.Campaign{
+				TemplateID: "some-template-id",
+				Status:     "completed",
+				StartTime:  time.Now().UTC().Truncate(time.Second),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(connection, models.Campaign{
+				TemplateID: "other-template-id",
+				Status:     "sending",
+				StartTime:  time.Now().UTC().Truncate(time.Second),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("only returns campaigns in a sending state that use the template", func() {
+			sendingCampaigns, err := repo.ListSendingCampaignsByTemplateID(connection, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sendingCampaigns).To(HaveLen(1))
+			Expect(sendingCampaigns[0].ID).To(Equal(campaign.ID))
+		})
+
+		Context("failure cases", func() {
+			It("returns an unknown error the database takes a dump", func() {
+				fakeConnection := mocks.NewConnection()
+				fakeConnection.SelectCall.Returns.Error = errors.New("something bad happened")
+
+				_, err := repo.ListSendingCampaignsByTemplateID(fakeConnection, "some-template-id")
+				Expect(err).To(MatchError(errors.New("something bad happened")))
+			})
+		})
+	})
 })
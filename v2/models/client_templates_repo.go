@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type ClientTemplate struct {
+	ClientID   string    `db:"client_id"`
+	TemplateID string    `db:"template_id"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+type ClientTemplatesRepository struct {
+	clock clock
+}
+
+func NewClientTemplatesRepository(clock clock) ClientTemplatesRepository {
+	return ClientTemplatesRepository{
+		clock: clock,
+	}
+}
+
+func (r ClientTemplatesRepository) Upsert(conn ConnectionInterface, clientTemplate ClientTemplate) (ClientTemplate, error) {
+	existing, err := r.Get(conn, clientTemplate.ClientID)
+	if err != nil {
+		if _, ok := err.(RecordNotFoundError); !ok {
+			return ClientTemplate{}, err
+		}
+
+		clientTemplate.CreatedAt = r.clock.Now().Truncate(time.Second).UTC()
+		clientTemplate.UpdatedAt = clientTemplate.CreatedAt
+
+		err = conn.Insert(&clientTemplate)
+		if err != nil {
+			return ClientTemplate{}, err
+		}
+
+		return clientTemplate, nil
+	}
+
+	clientTemplate.CreatedAt = existing.CreatedAt
+	clientTemplate.UpdatedAt = r.clock.Now().Truncate(time.Second).UTC()
+
+	_, err = conn.Update(&clientTemplate)
+	if err != nil {
+		return ClientTemplate{}, err
+	}
+
+	return clientTemplate, nil
+}
+
+func (r ClientTemplatesRepository) Get(conn ConnectionInterface, clientID string) (ClientTemplate, error) {
+	clientTemplate := ClientTemplate{}
+	err := conn.SelectOne(&clientTemplate, "SELECT * FROM `v2_client_templates` WHERE `client_id` = ?", clientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = RecordNotFoundError{fmt.Errorf("Client %q has no default template", clientID)}
+		}
+		return clientTemplate, err
+	}
+
+	return clientTemplate, nil
+}
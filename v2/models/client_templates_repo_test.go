@@ -0,0 +1,84 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ClientTemplatesRepository", func() {
+	var (
+		repo  models.ClientTemplatesRepository
+		conn  db.ConnectionInterface
+		clock *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+
+		clock = &mocks.Clock{}
+		clock.NowCall.Returns.Time = time.Now().UTC().Truncate(time.Second)
+
+		repo = models.NewClientTemplatesRepository(clock)
+		conn = database.Connection()
+	})
+
+	Describe("Upsert", func() {
+		It("inserts the default template for a client", func() {
+			clientTemplate, err := repo.Upsert(conn, models.ClientTemplate{
+				ClientID:   "some-client-id",
+				TemplateID: "some-template-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clientTemplate.CreatedAt).To(Equal(clock.NowCall.Returns.Time))
+
+			clientTemplate, err = repo.Get(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clientTemplate).To(Equal(models.ClientTemplate{
+				ClientID:   "some-client-id",
+				TemplateID: "some-template-id",
+				CreatedAt:  clock.NowCall.Returns.Time,
+				UpdatedAt:  clock.NowCall.Returns.Time,
+			}))
+		})
+
+		It("updates the default template, keeping its creation time", func() {
+			createdAt := clock.NowCall.Returns.Time
+			_, err := repo.Upsert(conn, models.ClientTemplate{
+				ClientID:   "some-client-id",
+				TemplateID: "some-template-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			clock.NowCall.Returns.Time = createdAt.Add(time.Hour)
+			_, err = repo.Upsert(conn, models.ClientTemplate{
+				ClientID:   "some-client-id",
+				TemplateID: "other-template-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			clientTemplate, err := repo.Get(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(clientTemplate.TemplateID).To(Equal("other-template-id"))
+			Expect(clientTemplate.CreatedAt).To(Equal(createdAt))
+			Expect(clientTemplate.UpdatedAt).To(Equal(createdAt.Add(time.Hour)))
+		})
+	})
+
+	Describe("Get", func() {
+		Context("when the client has no default template", func() {
+			It("returns a record not found error", func() {
+				_, err := repo.Get(conn, "missing-client-id")
+				Expect(err).To(MatchError(models.RecordNotFoundError{Err: errors.New("Client \"missing-client-id\" has no default template")}))
+			})
+		})
+	})
+})
@@ -34,4 +34,12 @@ func Setup(database *db.DB) {
 	database.TableMap().AddTableWithName(Campaign{}, "campaigns").SetKeys(false, "ID")
 	database.TableMap().AddTableWithName(Message{}, "messages").SetKeys(false, "ID")
 	database.TableMap().AddTableWithName(Unsubscriber{}, "unsubscribers").SetKeys(false, "ID").SetUniqueTogether("campaign_type_id", "user_guid")
+	database.TableMap().AddTableWithName(Attachment{}, "attachments").SetKeys(false, "ID")
+	database.TableMap().AddTableWithName(Suppression{}, "suppressions").SetKeys(false, "Address")
+	database.TableMap().AddTableWithName(UserLocale{}, "user_locales").SetKeys(false, "UserID")
+	database.TableMap().AddTableWithName(Transport{}, "transports").SetKeys(false, "Name")
+	database.TableMap().AddTableWithName(TransportGrant{}, "transport_grants").SetKeys(false, "TransportName", "ClientID")
+	database.TableMap().AddTableWithName(TemplateVersion{}, "v2_template_versions").SetKeys(false, "ID").SetUniqueTogether("template_id", "version")
+	database.TableMap().AddTableWithName(TemplatePartial{}, "v2_template_partials").SetKeys(false, "ID").SetUniqueTogether("client_id", "name")
+	database.TableMap().AddTableWithName(ClientTemplate{}, "v2_client_templates").SetKeys(false, "ClientID")
 }
@@ -13,9 +13,12 @@ type SendersRepository struct {
 type guidGeneratorFunc func() (string, error)
 
 type Sender struct {
-	ID       string `db:"id"`
-	Name     string `db:"name"`
-	ClientID string `db:"client_id"`
+	ID          string `db:"id"`
+	Name        string `db:"name"`
+	ClientID    string `db:"client_id"`
+	FromAddress string `db:"from_address"`
+	FromName    string `db:"from_name"`
+	Transport   string `db:"transport"`
 }
 
 func NewSendersRepository(guidGenerator guidGeneratorFunc) SendersRepository {
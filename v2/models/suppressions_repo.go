@@ -0,0 +1,38 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Suppression struct {
+	Address    string    `db:"address"`
+	Reason     string    `db:"reason"`
+	Status     string    `db:"status"`
+	Diagnostic string    `db:"diagnostic"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+type SuppressionsRepository struct{}
+
+func NewSuppressionsRepository() SuppressionsRepository {
+	return SuppressionsRepository{}
+}
+
+func (r SuppressionsRepository) Get(conn ConnectionInterface, address string) (Suppression, error) {
+	suppression := Suppression{}
+
+	err := conn.SelectOne(&suppression, "SELECT * FROM `suppressions` WHERE `address` = ?", strings.ToLower(address))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return suppression, RecordNotFoundError{fmt.Errorf("Suppression for %q could not be found", address)}
+		}
+
+		return suppression, err
+	}
+
+	return suppression, nil
+}
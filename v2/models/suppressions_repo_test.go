@@ -0,0 +1,59 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SuppressionsRepository", func() {
+	var (
+		repo models.SuppressionsRepository
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+
+		repo = models.NewSuppressionsRepository()
+		conn = database.Connection()
+	})
+
+	Describe("Get", func() {
+		It("finds the suppression regardless of address case", func() {
+			createdAt := time.Now().Truncate(time.Second).UTC()
+			err := conn.Insert(&models.Suppression{
+				Address:   "some-user@example.com",
+				Reason:    "bounce",
+				Status:    "5.1.1",
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			suppression, err := repo.Get(conn, "Some-User@Example.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(suppression).To(Equal(models.Suppression{
+				Address:   "some-user@example.com",
+				Reason:    "bounce",
+				Status:    "5.1.1",
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			}))
+		})
+
+		Context("when the address is not suppressed", func() {
+			It("returns a record not found error", func() {
+				_, err := repo.Get(conn, "missing@example.com")
+				Expect(err).To(MatchError(models.RecordNotFoundError{Err: errors.New("Suppression for \"missing@example.com\" could not be found")}))
+			})
+		})
+	})
+})
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type TemplatePartial struct {
+	ID        string    `db:"id"`
+	ClientID  string    `db:"client_id"`
+	Name      string    `db:"name"`
+	Text      string    `db:"text"`
+	HTML      string    `db:"html"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type TemplatePartialsRepository struct {
+	generateGUID guidGeneratorFunc
+	clock        clock
+}
+
+func NewTemplatePartialsRepository(guidGenerator guidGeneratorFunc, clock clock) TemplatePartialsRepository {
+	return TemplatePartialsRepository{
+		generateGUID: guidGenerator,
+		clock:        clock,
+	}
+}
+
+func (r TemplatePartialsRepository) Upsert(conn ConnectionInterface, partial TemplatePartial) (TemplatePartial, error) {
+	existing, err := r.Get(conn, partial.ClientID, partial.Name)
+	if err != nil {
+		if _, ok := err.(RecordNotFoundError); !ok {
+			return TemplatePartial{}, err
+		}
+
+		partial.ID, err = r.generateGUID()
+		if err != nil {
+			return TemplatePartial{}, err
+		}
+
+		partial.CreatedAt = r.clock.Now().Truncate(time.Second).UTC()
+		partial.UpdatedAt = partial.CreatedAt
+
+		err = conn.Insert(&partial)
+		if err != nil {
+			return TemplatePartial{}, err
+		}
+
+		return partial, nil
+	}
+
+	partial.ID = existing.ID
+	partial.CreatedAt = existing.CreatedAt
+	partial.UpdatedAt = r.clock.Now().Truncate(time.Second).UTC()
+
+	_, err = conn.Update(&partial)
+	if err != nil {
+		return TemplatePartial{}, err
+	}
+
+	return partial, nil
+}
+
+func (r TemplatePartialsRepository) Get(conn ConnectionInterface, clientID, name string) (TemplatePartial, error) {
+	partial := TemplatePartial{}
+	err := conn.SelectOne(&partial, "SELECT * FROM `v2_template_partials` WHERE `client_id` = ? AND `name` = ?", clientID, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = RecordNotFoundError{fmt.Errorf("Partial %q could not be found", name)}
+		}
+		return partial, err
+	}
+
+	return partial, nil
+}
+
+func (r TemplatePartialsRepository) List(conn ConnectionInterface, clientID string) ([]TemplatePartial, error) {
+	partials := []TemplatePartial{}
+	_, err := conn.Select(&partials, "SELECT * FROM `v2_template_partials` WHERE `client_id` = ? ORDER BY `name`", clientID)
+	return partials, err
+}
+
+func (r TemplatePartialsRepository) Delete(conn ConnectionInterface, partial TemplatePartial) error {
+	_, err := conn.Delete(&partial)
+	return err
+}
@@ -0,0 +1,130 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplatePartialsRepository", func() {
+	var (
+		repo          models.TemplatePartialsRepository
+		conn          db.ConnectionInterface
+		clock         *mocks.Clock
+		guidGenerator *mocks.IDGenerator
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+
+		clock = &mocks.Clock{}
+		clock.NowCall.Returns.Time = time.Now().UTC().Truncate(time.Second)
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-partial-guid", "second-partial-guid"}
+
+		repo = models.NewTemplatePartialsRepository(guidGenerator.Generate, clock)
+		conn = database.Connection()
+	})
+
+	Describe("Upsert", func() {
+		It("inserts a new partial", func() {
+			partial, err := repo.Upsert(conn, models.TemplatePartial{
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Text:     "Sent to {{.To}}",
+				HTML:     "<p>Sent to {{.To}}</p>",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial.ID).To(Equal("first-partial-guid"))
+
+			partial, err = repo.Get(conn, "some-client-id", "footer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial).To(Equal(models.TemplatePartial{
+				ID:        "first-partial-guid",
+				ClientID:  "some-client-id",
+				Name:      "footer",
+				Text:      "Sent to {{.To}}",
+				HTML:      "<p>Sent to {{.To}}</p>",
+				CreatedAt: clock.NowCall.Returns.Time,
+				UpdatedAt: clock.NowCall.Returns.Time,
+			}))
+		})
+
+		It("updates an existing partial, keeping its id and creation time", func() {
+			createdAt := clock.NowCall.Returns.Time
+			_, err := repo.Upsert(conn, models.TemplatePartial{
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Text:     "old footer",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			clock.NowCall.Returns.Time = createdAt.Add(time.Hour)
+			_, err = repo.Upsert(conn, models.TemplatePartial{
+				ClientID: "some-client-id",
+				Name:     "footer",
+				Text:     "new footer",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			partial, err := repo.Get(conn, "some-client-id", "footer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial.ID).To(Equal("first-partial-guid"))
+			Expect(partial.Text).To(Equal("new footer"))
+			Expect(partial.CreatedAt).To(Equal(createdAt))
+			Expect(partial.UpdatedAt).To(Equal(createdAt.Add(time.Hour)))
+		})
+	})
+
+	Describe("Get", func() {
+		It("does not return partials owned by another client", func() {
+			_, err := repo.Upsert(conn, models.TemplatePartial{ClientID: "other-client-id", Name: "footer"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Get(conn, "some-client-id", "footer")
+			Expect(err).To(MatchError(models.RecordNotFoundError{Err: errors.New("Partial \"footer\" could not be found")}))
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the client's partials ordered by name", func() {
+			_, err := repo.Upsert(conn, models.TemplatePartial{ClientID: "some-client-id", Name: "header"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Upsert(conn, models.TemplatePartial{ClientID: "some-client-id", Name: "footer"})
+			Expect(err).NotTo(HaveOccurred())
+
+			partials, err := repo.List(conn, "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(HaveLen(2))
+			Expect(partials[0].Name).To(Equal("footer"))
+			Expect(partials[1].Name).To(Equal("header"))
+
+			partials, err = repo.List(conn, "other-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(BeEmpty())
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the partial", func() {
+			partial, err := repo.Upsert(conn, models.TemplatePartial{ClientID: "some-client-id", Name: "footer"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Delete(conn, partial)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Get(conn, "some-client-id", "footer")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError{}))
+		})
+	})
+})
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type TemplateVersion struct {
+	ID            string    `db:"id"`
+	TemplateID    string    `db:"template_id"`
+	Version       int       `db:"version"`
+	Name          string    `db:"name"`
+	HTML          string    `db:"html"`
+	Text          string    `db:"text"`
+	Subject       string    `db:"subject"`
+	Metadata      string    `db:"metadata"`
+	Localizations string    `db:"localizations"`
+	LayoutID      string    `db:"layout_id"`
+	CreatedAt     time.Time `db:"created_at"`
+
+	DisableAutoText    bool `db:"disable_auto_text"`
+	DisableCSSInlining bool `db:"disable_css_inlining"`
+}
+
+type TemplateVersionsRepository struct {
+	generateGUID guidGeneratorFunc
+	clock        clock
+}
+
+func NewTemplateVersionsRepository(guidGenerator guidGeneratorFunc, clock clock) TemplateVersionsRepository {
+	return TemplateVersionsRepository{
+		generateGUID: guidGenerator,
+		clock:        clock,
+	}
+}
+
+func (r TemplateVersionsRepository) Insert(conn ConnectionInterface, version TemplateVersion) (TemplateVersion, error) {
+	var err error
+	version.ID, err = r.generateGUID()
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	version.CreatedAt = r.clock.Now().Truncate(time.Second).UTC()
+
+	err = conn.Insert(&version)
+	if err != nil {
+		return TemplateVersion{}, err
+	}
+
+	return version, nil
+}
+
+func (r TemplateVersionsRepository) Get(conn ConnectionInterface, templateID string, version int) (TemplateVersion, error) {
+	templateVersion := TemplateVersion{}
+	err := conn.SelectOne(&templateVersion, "SELECT * FROM `v2_template_versions` WHERE `template_id` = ? AND `version` = ?", templateID, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = RecordNotFoundError{fmt.Errorf("Version %d of template %q could not be found", version, templateID)}
+		}
+
+		return TemplateVersion{}, err
+	}
+
+	return templateVersion, nil
+}
+
+func (r TemplateVersionsRepository) List(conn ConnectionInterface, templateID string) ([]TemplateVersion, error) {
+	versions := []TemplateVersion{}
+
+	_, err := conn.Select(&versions, "SELECT * FROM `v2_template_versions` WHERE `template_id` = ? ORDER BY `version` DESC", templateID)
+	if err != nil {
+		return []TemplateVersion{}, err
+	}
+
+	return versions, nil
+}
+
+func (r TemplateVersionsRepository) DeleteAll(conn ConnectionInterface, templateID string) error {
+	_, err := conn.Exec("DELETE FROM `v2_template_versions` WHERE `template_id` = ?", templateID)
+	return err
+}
@@ -0,0 +1,113 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionsRepository", func() {
+	var (
+		repo          models.TemplateVersionsRepository
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+		clock         *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"first-version-guid", "second-version-guid", "third-version-guid"}
+
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Date(2015, time.March, 4, 5, 6, 7, 0, time.UTC)
+
+		repo = models.NewTemplateVersionsRepository(guidGenerator.Generate, clock)
+	})
+
+	Describe("Insert", func() {
+		It("inserts a version of a template", func() {
+			version, err := repo.Insert(conn, models.TemplateVersion{
+				TemplateID: "some-template-id",
+				Version:    1,
+				Name:       "some-name",
+				HTML:       "<p>{{.HTML}}</p>",
+				Text:       "{{.Text}}",
+				Subject:    "{{.Subject}}",
+				Metadata:   "{}",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.ID).To(Equal("first-version-guid"))
+			Expect(version.CreatedAt).To(Equal(time.Date(2015, time.March, 4, 5, 6, 7, 0, time.UTC)))
+
+			found, err := repo.Get(conn, "some-template-id", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(Equal(version))
+		})
+
+		It("does not allow the same version of a template to be stored twice", func() {
+			_, err := repo.Insert(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: 1})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns a record not found error when the version does not exist", func() {
+			_, err := repo.Get(conn, "some-template-id", 3)
+			Expect(err).To(MatchError(models.RecordNotFoundError{Err: errors.New(`Version 3 of template "some-template-id" could not be found`)}))
+		})
+	})
+
+	Describe("List", func() {
+		It("lists the versions of a template, newest first", func() {
+			_, err := repo.Insert(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: 2})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(conn, models.TemplateVersion{TemplateID: "other-template-id", Version: 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := repo.List(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(2))
+			Expect(versions[0].Version).To(Equal(2))
+			Expect(versions[1].Version).To(Equal(1))
+		})
+	})
+
+	Describe("DeleteAll", func() {
+		It("deletes every version of a template", func() {
+			_, err := repo.Insert(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(conn, models.TemplateVersion{TemplateID: "other-template-id", Version: 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.DeleteAll(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := repo.List(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(BeEmpty())
+
+			versions, err = repo.List(conn, "other-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(HaveLen(1))
+		})
+	})
+})
@@ -6,22 +6,29 @@ import (
 )
 
 var DefaultTemplate = Template{
-	ID:       "default",
-	Name:     "The Default Template",
-	Subject:  "{{.Subject}}",
-	Text:     "{{.Text}}",
-	HTML:     "{{.HTML}}",
-	Metadata: "{}",
+	ID:            "default",
+	Name:          "The Default Template",
+	Subject:       "{{.Subject}}",
+	Text:          "{{.Text}}",
+	HTML:          "{{.HTML}}",
+	Metadata:      "{}",
+	Localizations: "{}",
 }
 
 type Template struct {
-	ID       string `db:"id"`
-	Name     string `db:"name"`
-	HTML     string `db:"html"`
-	Text     string `db:"text"`
-	Subject  string `db:"subject"`
-	Metadata string `db:"metadata"`
-	ClientID string `db:"client_id"`
+	ID            string `db:"id"`
+	Name          string `db:"name"`
+	HTML          string `db:"html"`
+	Text          string `db:"text"`
+	Subject       string `db:"subject"`
+	Metadata      string `db:"metadata"`
+	Localizations string `db:"localizations"`
+	LayoutID      string `db:"layout_id"`
+	ClientID      string `db:"client_id"`
+	Version       int    `db:"version"`
+
+	DisableAutoText    bool `db:"disable_auto_text"`
+	DisableCSSInlining bool `db:"disable_css_inlining"`
 }
 
 type TemplatesRepository struct {
@@ -52,8 +59,18 @@ func (r TemplatesRepository) Insert(conn ConnectionInterface, template Template)
 }
 
 func (r TemplatesRepository) Get(conn ConnectionInterface, templateID string) (Template, error) {
+	return r.get(conn, "SELECT * FROM `v2_templates` WHERE `id` = ?", templateID)
+}
+
+// GetForUpdate reads the template and locks its row until the surrounding
+// transaction ends, so that concurrent updates cannot claim the same version.
+func (r TemplatesRepository) GetForUpdate(conn ConnectionInterface, templateID string) (Template, error) {
+	return r.get(conn, "SELECT * FROM `v2_templates` WHERE `id` = ? FOR UPDATE", templateID)
+}
+
+func (r TemplatesRepository) get(conn ConnectionInterface, query, templateID string) (Template, error) {
 	template := Template{}
-	err := conn.SelectOne(&template, "SELECT * FROM `v2_templates` WHERE `id` = ?", templateID)
+	err := conn.SelectOne(&template, query, templateID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			if templateID == DefaultTemplate.ID {
@@ -111,3 +128,11 @@ func (r TemplatesRepository) List(conn ConnectionInterface, clientID string) ([]
 
 	return templates, err
 }
+
+func (r TemplatesRepository) ListByLayoutID(conn ConnectionInterface, layoutID string) ([]Template, error) {
+	templates := []Template{}
+
+	_, err := conn.Select(&templates, "SELECT * FROM `v2_templates` WHERE `layout_id` = ? ORDER BY `name`", layoutID)
+
+	return templates, err
+}
@@ -118,6 +118,43 @@ var _ = Describe("TemplatesRepo", func() {
 		})
 	})
 
+	Describe("ListByLayoutID", func() {
+		It("returns the templates using the layout", func() {
+			layout, err := repo.Insert(conn, models.Template{
+				Name:     "some-layout",
+				ClientID: "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(conn, models.Template{
+				Name:     "some-wrapped-template",
+				LayoutID: layout.ID,
+				ClientID: "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Insert(conn, models.Template{
+				Name:     "some-unwrapped-template",
+				ClientID: "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			templates, err := repo.ListByLayoutID(conn, layout.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(templates).To(HaveLen(1))
+			Expect(templates[0].Name).To(Equal("some-wrapped-template"))
+		})
+
+		Context("failure cases", func() {
+			It("returns an error if one occurs", func() {
+				connection := mocks.NewConnection()
+				connection.SelectCall.Returns.Error = errors.New("an error")
+				_, err := repo.ListByLayoutID(connection, "some-layout-id")
+				Expect(err).To(MatchError(errors.New("an error")))
+			})
+		})
+	})
+
 	Describe("Get", func() {
 		It("fetches the template given a template_id", func() {
 			createdTemplate, err := repo.Insert(conn, models.Template{
@@ -145,6 +182,32 @@ var _ = Describe("TemplatesRepo", func() {
 		})
 	})
 
+	Describe("GetForUpdate", func() {
+		It("fetches the template within a transaction", func() {
+			createdTemplate, err := repo.Insert(conn, models.Template{
+				Name:     "some-template",
+				ClientID: "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			transaction := conn.Transaction()
+			Expect(transaction.Begin()).To(Succeed())
+
+			template, err := repo.GetForUpdate(transaction, createdTemplate.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(template).To(Equal(createdTemplate))
+
+			Expect(transaction.Commit()).To(Succeed())
+		})
+
+		Context("failure cases", func() {
+			It("returns not found error if it happens", func() {
+				_, err := repo.GetForUpdate(conn, "missing-template-id")
+				Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError{}))
+			})
+		})
+	})
+
 	Describe("Delete", func() {
 		It("deletes the template given a template_id", func() {
 			template, err := repo.Insert(conn, models.Template{
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type TransportGrant struct {
+	TransportName string    `db:"transport_name"`
+	ClientID      string    `db:"client_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+type TransportGrantsRepository struct {
+	clock clock
+}
+
+func NewTransportGrantsRepository(clock clock) TransportGrantsRepository {
+	return TransportGrantsRepository{
+		clock: clock,
+	}
+}
+
+func (r TransportGrantsRepository) Upsert(conn ConnectionInterface, grant TransportGrant) (TransportGrant, error) {
+	existing, err := r.Get(conn, grant.TransportName, grant.ClientID)
+	if err == nil {
+		return existing, nil
+	}
+
+	if _, ok := err.(RecordNotFoundError); !ok {
+		return TransportGrant{}, err
+	}
+
+	grant.CreatedAt = r.clock.Now().Truncate(time.Second).UTC()
+
+	err = conn.Insert(&grant)
+	if err != nil {
+		return TransportGrant{}, err
+	}
+
+	return grant, nil
+}
+
+func (r TransportGrantsRepository) Get(conn ConnectionInterface, transportName, clientID string) (TransportGrant, error) {
+	grant := TransportGrant{}
+	err := conn.SelectOne(&grant, "SELECT * FROM `transport_grants` WHERE `transport_name` = ? AND `client_id` = ?", transportName, clientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = RecordNotFoundError{fmt.Errorf("Client %q has not been granted transport %q", clientID, transportName)}
+		}
+		return grant, err
+	}
+
+	return grant, nil
+}
+
+func (r TransportGrantsRepository) List(conn ConnectionInterface, transportName string) ([]TransportGrant, error) {
+	grants := []TransportGrant{}
+	_, err := conn.Select(&grants, "SELECT * FROM `transport_grants` WHERE `transport_name` = ? ORDER BY `client_id`", transportName)
+	return grants, err
+}
+
+func (r TransportGrantsRepository) Delete(conn ConnectionInterface, grant TransportGrant) error {
+	_, err := conn.Delete(&grant)
+	return err
+}
+
+func (r TransportGrantsRepository) DeleteByTransportName(conn ConnectionInterface, transportName string) error {
+	_, err := conn.Exec("DELETE FROM `transport_grants` WHERE `transport_name` = ?", transportName)
+	return err
+}
@@ -0,0 +1,137 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransportGrantsRepository", func() {
+	var (
+		repo  models.TransportGrantsRepository
+		conn  db.ConnectionInterface
+		clock *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+
+		clock = &mocks.Clock{}
+		clock.NowCall.Returns.Time = time.Now().UTC().Truncate(time.Second)
+
+		repo = models.NewTransportGrantsRepository(clock)
+		conn = database.Connection()
+	})
+
+	Describe("Upsert", func() {
+		It("grants the transport to the client", func() {
+			grant, err := repo.Upsert(conn, models.TransportGrant{
+				TransportName: "corporate-relay",
+				ClientID:      "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			grant, err = repo.Get(conn, "corporate-relay", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(grant).To(Equal(models.TransportGrant{
+				TransportName: "corporate-relay",
+				ClientID:      "some-client-id",
+				CreatedAt:     clock.NowCall.Returns.Time,
+			}))
+		})
+
+		It("keeps an existing grant", func() {
+			createdAt := clock.NowCall.Returns.Time
+			_, err := repo.Upsert(conn, models.TransportGrant{
+				TransportName: "corporate-relay",
+				ClientID:      "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			clock.NowCall.Returns.Time = createdAt.Add(time.Hour)
+
+			grant, err := repo.Upsert(conn, models.TransportGrant{
+				TransportName: "corporate-relay",
+				ClientID:      "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(grant.CreatedAt).To(Equal(createdAt))
+		})
+	})
+
+	Describe("Get", func() {
+		Context("when the client has not been granted the transport", func() {
+			It("returns a record not found error", func() {
+				_, err := repo.Get(conn, "corporate-relay", "some-client-id")
+				Expect(err).To(MatchError(models.RecordNotFoundError{Err: errors.New(`Client "some-client-id" has not been granted transport "corporate-relay"`)}))
+			})
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the grants for the transport ordered by client", func() {
+			for _, grant := range []models.TransportGrant{
+				{TransportName: "corporate-relay", ClientID: "client-b"},
+				{TransportName: "corporate-relay", ClientID: "client-a"},
+				{TransportName: "other-relay", ClientID: "client-c"},
+			} {
+				_, err := repo.Upsert(conn, grant)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			grants, err := repo.List(conn, "corporate-relay")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(grants).To(HaveLen(2))
+			Expect(grants[0].ClientID).To(Equal("client-a"))
+			Expect(grants[1].ClientID).To(Equal("client-b"))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("revokes the grant", func() {
+			grant, err := repo.Upsert(conn, models.TransportGrant{
+				TransportName: "corporate-relay",
+				ClientID:      "some-client-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Delete(conn, grant)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Get(conn, "corporate-relay", "some-client-id")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError{}))
+		})
+	})
+
+	Describe("DeleteByTransportName", func() {
+		It("revokes every grant for the transport", func() {
+			for _, grant := range []models.TransportGrant{
+				{TransportName: "corporate-relay", ClientID: "client-a"},
+				{TransportName: "corporate-relay", ClientID: "client-b"},
+				{TransportName: "other-relay", ClientID: "client-a"},
+			} {
+				_, err := repo.Upsert(conn, grant)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			err := repo.DeleteByTransportName(conn, "corporate-relay")
+			Expect(err).NotTo(HaveOccurred())
+
+			grants, err := repo.List(conn, "corporate-relay")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(grants).To(BeEmpty())
+
+			grants, err = repo.List(conn, "other-relay")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(grants).To(HaveLen(1))
+		})
+	})
+})
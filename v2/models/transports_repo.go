@@ -0,0 +1,100 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type Transport struct {
+	Name          string    `db:"name"`
+	Host          string    `db:"host"`
+	Port          string    `db:"port"`
+	User          string    `db:"user"`
+	Pass          string    `db:"pass"`
+	CRAMMD5Secret string    `db:"crammd5_secret"`
+	XOAUTH2Token  string    `db:"xoauth2_token"`
+	AuthMechanism string    `db:"auth_mechanism"`
+	TLSMode       string    `db:"tls_mode"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+type TransportsRepository struct {
+	clock clock
+}
+
+func NewTransportsRepository(clock clock) TransportsRepository {
+	return TransportsRepository{
+		clock: clock,
+	}
+}
+
+func (r TransportsRepository) Upsert(conn ConnectionInterface, transport Transport) (Transport, error) {
+	existing, err := r.Get(conn, transport.Name)
+	if err != nil {
+		if _, ok := err.(RecordNotFoundError); !ok {
+			return Transport{}, err
+		}
+
+		transport.CreatedAt = r.clock.Now().Truncate(time.Second).UTC()
+		transport.UpdatedAt = transport.CreatedAt
+
+		err = conn.Insert(&transport)
+		if err != nil {
+			return Transport{}, err
+		}
+
+		return transport, nil
+	}
+
+	transport.CreatedAt = existing.CreatedAt
+	transport.UpdatedAt = r.clock.Now().Truncate(time.Second).UTC()
+
+	_, err = conn.Update(&transport)
+	if err != nil {
+		return Transport{}, err
+	}
+
+	return transport, nil
+}
+
+func (r TransportsRepository) Get(conn ConnectionInterface, name string) (Transport, error) {
+	transport := Transport{}
+	err := conn.SelectOne(&transport, "SELECT * FROM `transports` WHERE `name` = ?", name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			err = RecordNotFoundError{fmt.Errorf("Transport %q could not be found", name)}
+		}
+		return transport, err
+	}
+
+	return transport, nil
+}
+
+func (r TransportsRepository) List(conn ConnectionInterface) ([]Transport, error) {
+	transports := []Transport{}
+	_, err := conn.Select(&transports, "SELECT * FROM `transports` ORDER BY `name`")
+	return transports, err
+}
+
+func (r TransportsRepository) Delete(conn ConnectionInterface, transport Transport) error {
+	_, err := conn.Delete(&transport)
+	return err
+}
+
+// CountReferences returns how many v2 senders and v1 clients are bound to the
+// named transport.
+func (r TransportsRepository) CountReferences(conn ConnectionInterface, name string) (int64, error) {
+	var count int64
+	err := conn.SelectOne(&count, "SELECT (SELECT COUNT(*) FROM `senders` WHERE `transport` = ?) + (SELECT COUNT(*) FROM `clients` WHERE `transport` = ?)", name, name)
+	return count, err
+}
+
+// CountClientReferences returns how many senders owned by the client are bound
+// to the named transport.
+func (r TransportsRepository) CountClientReferences(conn ConnectionInterface, name, clientID string) (int64, error) {
+	var count int64
+	err := conn.SelectOne(&count, "SELECT COUNT(*) FROM `senders` WHERE `transport` = ? AND `client_id` = ?", name, clientID)
+	return count, err
+}
@@ -0,0 +1,161 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TransportsRepository", func() {
+	var (
+		repo  models.TransportsRepository
+		conn  db.ConnectionInterface
+		clock *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+
+		clock = &mocks.Clock{}
+		clock.NowCall.Returns.Time = time.Now().UTC().Truncate(time.Second)
+
+		repo = models.NewTransportsRepository(clock)
+		conn = database.Connection()
+	})
+
+	Describe("Upsert", func() {
+		It("inserts a new transport", func() {
+			transport, err := repo.Upsert(conn, models.Transport{
+				Name:          "corporate-relay",
+				Host:          "smtp.example.com",
+				Port:          "587",
+				User:          "some-user",
+				Pass:          "encrypted-pass",
+				AuthMechanism: "plain",
+				TLSMode:       "starttls",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transport.CreatedAt).To(Equal(clock.NowCall.Returns.Time))
+
+			transport, err = repo.Get(conn, "corporate-relay")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transport).To(Equal(models.Transport{
+				Name:          "corporate-relay",
+				Host:          "smtp.example.com",
+				Port:          "587",
+				User:          "some-user",
+				Pass:          "encrypted-pass",
+				AuthMechanism: "plain",
+				TLSMode:       "starttls",
+				CreatedAt:     clock.NowCall.Returns.Time,
+				UpdatedAt:     clock.NowCall.Returns.Time,
+			}))
+		})
+
+		It("updates an existing transport, keeping its creation time", func() {
+			createdAt := clock.NowCall.Returns.Time
+			_, err := repo.Upsert(conn, models.Transport{
+				Name: "corporate-relay",
+				Host: "smtp.example.com",
+				Port: "587",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			clock.NowCall.Returns.Time = createdAt.Add(time.Hour)
+			_, err = repo.Upsert(conn, models.Transport{
+				Name: "corporate-relay",
+				Host: "relay.example.com",
+				Port: "465",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			transport, err := repo.Get(conn, "corporate-relay")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transport.Host).To(Equal("relay.example.com"))
+			Expect(transport.Port).To(Equal("465"))
+			Expect(transport.CreatedAt).To(Equal(createdAt))
+			Expect(transport.UpdatedAt).To(Equal(createdAt.Add(time.Hour)))
+		})
+	})
+
+	Describe("Get", func() {
+		Context("when the transport does not exist", func() {
+			It("returns a record not found error", func() {
+				_, err := repo.Get(conn, "missing-relay")
+				Expect(err).To(MatchError(models.RecordNotFoundError{Err: errors.New("Transport \"missing-relay\" could not be found")}))
+			})
+		})
+	})
+
+	Describe("List", func() {
+		It("returns the transports ordered by name", func() {
+			_, err := repo.Upsert(conn, models.Transport{Name: "relay-b"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Upsert(conn, models.Transport{Name: "relay-a"})
+			Expect(err).NotTo(HaveOccurred())
+
+			transports, err := repo.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transports).To(HaveLen(2))
+			Expect(transports[0].Name).To(Equal("relay-a"))
+			Expect(transports[1].Name).To(Equal("relay-b"))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("deletes the transport", func() {
+			transport, err := repo.Upsert(conn, models.Transport{Name: "corporate-relay"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Delete(conn, transport)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Get(conn, "corporate-relay")
+			Expect(err).To(BeAssignableToTypeOf(models.RecordNotFoundError{}))
+		})
+	})
+
+	Describe("CountReferences", func() {
+		It("counts the senders and clients bound to the transport", func() {
+			_, err := conn.Exec("INSERT INTO `senders` (`id`, `name`, `client_id`, `transport`) VALUES ('sender-1', 'sender', 'some-client-id', 'corporate-relay')")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = conn.Exec("INSERT INTO `senders` (`id`, `name`, `client_id`, `transport`) VALUES ('sender-2', 'sender', 'other-client-id', 'other-relay')")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = conn.Exec("INSERT INTO `clients` (`id`, `transport`) VALUES ('some-client-id', 'corporate-relay')")
+			Expect(err).NotTo(HaveOccurred())
+
+			count, err := repo.CountReferences(conn, "corporate-relay")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(2)))
+
+			count, err = repo.CountReferences(conn, "unused-relay")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(0)))
+		})
+	})
+
+	Describe("CountClientReferences", func() {
+		It("counts only the senders of the given client", func() {
+			_, err := conn.Exec("INSERT INTO `senders` (`id`, `name`, `client_id`, `transport`) VALUES ('sender-1', 'sender', 'some-client-id', 'corporate-relay')")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = conn.Exec("INSERT INTO `senders` (`id`, `name`, `client_id`, `transport`) VALUES ('sender-2', 'sender', 'other-client-id', 'corporate-relay')")
+			Expect(err).NotTo(HaveOccurred())
+
+			count, err := repo.CountClientReferences(conn, "corporate-relay", "some-client-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(int64(1)))
+		})
+	})
+})
@@ -67,6 +67,19 @@ var _ = Describe("UnsubscribersRepo", func() {
 					Expect(err).To(MatchError(errors.New("some other error")))
 				})
 			})
+
+			Context("when the user is already unsubscribed", func() {
+				It("returns a duplicate record error", func() {
+					connection := mocks.NewConnection()
+					connection.InsertCall.Returns.Error = errors.New("Error 1062: Duplicate entry 'some-user-guid-some-campaign-type-id' for key 'user_guid_campaign_type_id'")
+
+					_, err := repo.Insert(connection, models.Unsubscriber{
+						CampaignTypeID: "some-campaign-type-id",
+						UserGUID:       "some-user-guid",
+					})
+					Expect(err).To(MatchError(models.DuplicateRecordError{Err: errors.New("User some-user-guid is already unsubscribed from campaign_type some-campaign-type-id")}))
+				})
+			})
 		})
 	})
 
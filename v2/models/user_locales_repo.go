@@ -0,0 +1,34 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type UserLocale struct {
+	UserID    string    `db:"user_id"`
+	Locale    string    `db:"locale"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type UserLocalesRepository struct{}
+
+func NewUserLocalesRepository() UserLocalesRepository {
+	return UserLocalesRepository{}
+}
+
+// Get returns the locale the user prefers to receive notifications in, or an
+// empty string when they have not chosen one.
+func (r UserLocalesRepository) Get(connection ConnectionInterface, userGUID string) (string, error) {
+	userLocale := UserLocale{}
+	err := connection.SelectOne(&userLocale, "SELECT * FROM `user_locales` WHERE `user_id` = ?", userGUID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	return userLocale.Locale, nil
+}
@@ -0,0 +1,48 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v2/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UserLocalesRepository", func() {
+	var (
+		repo models.UserLocalesRepository
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+
+		repo = models.NewUserLocalesRepository()
+		conn = database.Connection()
+	})
+
+	Describe("Get", func() {
+		It("returns the locale the user prefers", func() {
+			err := conn.Insert(&models.UserLocale{
+				UserID:    "some-user-guid",
+				Locale:    "pt-BR",
+				UpdatedAt: time.Now().UTC(),
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			locale, err := repo.Get(conn, "some-user-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(locale).To(Equal("pt-BR"))
+		})
+
+		It("returns an empty locale when the user has no preference", func() {
+			locale, err := repo.Get(conn, "some-user-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(locale).To(BeEmpty())
+		})
+	})
+})
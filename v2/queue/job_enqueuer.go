@@ -16,6 +16,7 @@ type User struct {
 	GUID        string
 	Email       string
 	Endorsement string
+	Data        map[string]interface{}
 }
 
 type Response struct {
@@ -37,6 +38,20 @@ type Options struct {
 	Role              string
 	Endorsement       string
 	TemplateID        string
+	TemplateVersion   int
+	AttachmentIDs     []string
+	ThreadKey         string
+	Headers           map[string]string
+	From              string
+	Transport         string
+	Localizations     map[string]Localization
+	Data              map[string]interface{}
+}
+
+type Localization struct {
+	Subject string
+	Text    string
+	HTML    HTML
 }
 
 type HTML struct {
@@ -101,6 +116,7 @@ func (enqueuer JobEnqueuer) Enqueue(conn ConnectionInterface, users []User, opti
 		}
 
 		options.Endorsement = user.Endorsement
+		options.Data = user.Data
 
 		job := gobble.NewJob(Delivery{
 			JobType:         "v2",
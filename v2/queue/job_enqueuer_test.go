@@ -77,7 +77,7 @@ var _ = Describe("JobEnqueuer", func() {
 		It("enqueues jobs with the deliveries", func() {
 			users := []queue.User{
 				{GUID: "user-1", Endorsement: "endores 1"},
-				{GUID: "user-2", Endorsement: "endores 2"},
+				{GUID: "user-2", Endorsement: "endores 2", Data: map[string]interface{}{"name": "Two"}},
 				{GUID: "user-3", Endorsement: "endores 3"},
 				{GUID: "user-4", Endorsement: "endores 4"},
 			}
@@ -111,7 +111,7 @@ var _ = Describe("JobEnqueuer", func() {
 				},
 				{
 					JobType:         "v2",
-					Options:         queue.Options{Endorsement: "endores 2"},
+					Options:         queue.Options{Endorsement: "endores 2", Data: map[string]interface{}{"name": "Two"}},
 					UserGUID:        "user-2",
 					Space:           space,
 					Organization:    org,
@@ -0,0 +1,309 @@
+package bundles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+)
+
+const (
+	bundleVersion = 1
+	bundleAPI     = "v2"
+)
+
+var partialName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+type bundleDocument struct {
+	Version       int                    `json:"version"`
+	API           string                 `json:"api"`
+	Templates     []templateDocument     `json:"templates"`
+	Partials      []partialDocument      `json:"partials"`
+	Senders       []senderDocument       `json:"senders"`
+	CampaignTypes []campaignTypeDocument `json:"campaign_types"`
+}
+
+type templateDocument struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Subject       string          `json:"subject"`
+	Text          string          `json:"text"`
+	HTML          string          `json:"html"`
+	Metadata      json.RawMessage `json:"metadata,omitempty"`
+	Localizations json.RawMessage `json:"localizations,omitempty"`
+	LayoutID      string          `json:"layout_id,omitempty"`
+
+	DisableAutoText    bool `json:"disable_auto_text,omitempty"`
+	DisableCSSInlining bool `json:"disable_css_inlining,omitempty"`
+}
+
+type partialDocument struct {
+	Name string `json:"name"`
+	Text string `json:"text"`
+	HTML string `json:"html"`
+}
+
+type senderDocument struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	FromAddress string `json:"from_address,omitempty"`
+	FromName    string `json:"from_name,omitempty"`
+	Transport   string `json:"transport,omitempty"`
+}
+
+type campaignTypeDocument struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Critical    bool              `json:"critical"`
+	Threading   bool              `json:"threading,omitempty"`
+	TemplateID  string            `json:"template_id,omitempty"`
+	SenderID    string            `json:"sender_id"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	FromAddress string            `json:"from_address,omitempty"`
+	FromName    string            `json:"from_name,omitempty"`
+}
+
+func newBundleDocument(bundle collections.Bundle) bundleDocument {
+	document := bundleDocument{
+		Version:       bundleVersion,
+		API:           bundleAPI,
+		Templates:     []templateDocument{},
+		Partials:      []partialDocument{},
+		Senders:       []senderDocument{},
+		CampaignTypes: []campaignTypeDocument{},
+	}
+
+	for _, template := range bundle.Templates {
+		document.Templates = append(document.Templates, templateDocument{
+			ID:            template.ID,
+			Name:          template.Name,
+			Subject:       template.Subject,
+			Text:          template.Text,
+			HTML:          template.HTML,
+			Metadata:      rawJSON(template.Metadata),
+			Localizations: rawJSON(template.Localizations),
+			LayoutID:      template.LayoutID,
+
+			DisableAutoText:    template.DisableAutoText,
+			DisableCSSInlining: template.DisableCSSInlining,
+		})
+	}
+
+	for _, partial := range bundle.Partials {
+		document.Partials = append(document.Partials, partialDocument{
+			Name: partial.Name,
+			Text: partial.Text,
+			HTML: partial.HTML,
+		})
+	}
+
+	for _, sender := range bundle.Senders {
+		document.Senders = append(document.Senders, senderDocument{
+			ID:          sender.ID,
+			Name:        sender.Name,
+			FromAddress: sender.FromAddress,
+			FromName:    sender.FromName,
+			Transport:   sender.Transport,
+		})
+	}
+
+	for _, campaignType := range bundle.CampaignTypes {
+		document.CampaignTypes = append(document.CampaignTypes, campaignTypeDocument{
+			ID:          campaignType.ID,
+			Name:        campaignType.Name,
+			Description: campaignType.Description,
+			Critical:    campaignType.Critical,
+			Threading:   campaignType.Threading,
+			TemplateID:  campaignType.TemplateID,
+			SenderID:    campaignType.SenderID,
+			Headers:     campaignType.Headers,
+			FromAddress: campaignType.FromAddress,
+			FromName:    campaignType.FromName,
+		})
+	}
+
+	return document
+}
+
+// validate applies the checks the API makes when each record is created on
+// its own.
+func (d bundleDocument) validate(senderDomains []string) error {
+	if d.Version != bundleVersion {
+		return fmt.Errorf("unsupported bundle version %d", d.Version)
+	}
+
+	if d.API != bundleAPI {
+		return fmt.Errorf("bundle must be exported from the %s API", bundleAPI)
+	}
+
+	for _, partial := range d.Partials {
+		if !partialName.MatchString(partial.Name) || partial.Name == common.LayoutContent {
+			return fmt.Errorf("partial %q: name may only contain letters, digits, \"-\" and \"_\"", partial.Name)
+		}
+
+		if partial.Text == "" && partial.HTML == "" {
+			return fmt.Errorf("partial %q: missing either partial text or html", partial.Name)
+		}
+
+		err := common.ValidatePartial(common.Partial{Name: partial.Name, Text: partial.Text, HTML: partial.HTML})
+		if err != nil {
+			return fmt.Errorf("partial %q: %s", partial.Name, err)
+		}
+	}
+
+	for _, template := range d.Templates {
+		if template.Name == "" {
+			return errors.New("template \"name\" field cannot be empty")
+		}
+
+		if template.HTML == "" && template.Text == "" {
+			return fmt.Errorf("template %q: missing either template text or html", template.Name)
+		}
+
+		var localizations common.Localizations
+		if len(template.Localizations) > 0 {
+			err := json.Unmarshal(template.Localizations, &localizations)
+			if err != nil {
+				return fmt.Errorf("template %q: localizations must map each locale to a subject, text and html", template.Name)
+			}
+		}
+
+		_, err := common.ValidateLocalizedTemplates(common.Templates{
+			Subject: template.subject(),
+			Text:    template.Text,
+			HTML:    template.HTML,
+		}, localizations, false)
+		if err != nil {
+			return fmt.Errorf("template %q: %s", template.Name, err)
+		}
+	}
+
+	for _, sender := range d.Senders {
+		if sender.Name == "" {
+			return errors.New("missing sender name")
+		}
+
+		err := mail.ValidateFrom(sender.FromName, sender.FromAddress, senderDomains)
+		if err != nil {
+			return fmt.Errorf("sender %q: %s", sender.Name, err)
+		}
+	}
+
+	for _, campaignType := range d.CampaignTypes {
+		if campaignType.Name == "" {
+			return errors.New("missing campaign type name")
+		}
+
+		if campaignType.Description == "" {
+			return fmt.Errorf("campaign type %q: missing campaign type description", campaignType.Name)
+		}
+
+		err := mail.ValidateHeaders(campaignType.Headers)
+		if err != nil {
+			return fmt.Errorf("campaign type %q: %s", campaignType.Name, err)
+		}
+
+		err = mail.ValidateFrom(campaignType.FromName, campaignType.FromAddress, senderDomains)
+		if err != nil {
+			return fmt.Errorf("campaign type %q: %s", campaignType.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (d bundleDocument) hasCriticalCampaignTypes() bool {
+	for _, campaignType := range d.CampaignTypes {
+		if campaignType.Critical {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d bundleDocument) bundle() collections.Bundle {
+	var bundle collections.Bundle
+
+	for _, template := range d.Templates {
+		metadata := "{}"
+		if len(template.Metadata) > 0 {
+			metadata = string(template.Metadata)
+		}
+
+		localizations := "{}"
+		if len(template.Localizations) > 0 {
+			localizations = string(template.Localizations)
+		}
+
+		bundle.Templates = append(bundle.Templates, collections.Template{
+			ID:            template.ID,
+			Name:          template.Name,
+			Subject:       template.subject(),
+			Text:          template.Text,
+			HTML:          template.HTML,
+			Metadata:      metadata,
+			Localizations: localizations,
+			LayoutID:      template.LayoutID,
+
+			DisableAutoText:    template.DisableAutoText,
+			DisableCSSInlining: template.DisableCSSInlining,
+		})
+	}
+
+	for _, partial := range d.Partials {
+		bundle.Partials = append(bundle.Partials, collections.TemplatePartial{
+			Name: partial.Name,
+			Text: partial.Text,
+			HTML: partial.HTML,
+		})
+	}
+
+	for _, sender := range d.Senders {
+		bundle.Senders = append(bundle.Senders, collections.Sender{
+			ID:          sender.ID,
+			Name:        sender.Name,
+			FromAddress: sender.FromAddress,
+			FromName:    sender.FromName,
+			Transport:   sender.Transport,
+		})
+	}
+
+	for _, campaignType := range d.CampaignTypes {
+		bundle.CampaignTypes = append(bundle.CampaignTypes, collections.CampaignType{
+			ID:          campaignType.ID,
+			Name:        campaignType.Name,
+			Description: campaignType.Description,
+			Critical:    campaignType.Critical,
+			Threading:   campaignType.Threading,
+			TemplateID:  campaignType.TemplateID,
+			SenderID:    campaignType.SenderID,
+			Headers:     campaignType.Headers,
+			FromAddress: campaignType.FromAddress,
+			FromName:    campaignType.FromName,
+		})
+	}
+
+	return bundle
+}
+
+func (t templateDocument) subject() string {
+	if t.Subject == "" {
+		return "{{.Subject}}"
+	}
+
+	return t.Subject
+}
+
+func rawJSON(value string) json.RawMessage {
+	if value == "" {
+		return nil
+	}
+
+	return json.RawMessage(value)
+}
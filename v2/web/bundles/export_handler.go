@@ -0,0 +1,64 @@
+package bundles
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/util"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionExporter interface {
+	Export(conn collections.ConnectionInterface, clientID string) (collections.Bundle, error)
+}
+
+type ExportHandler struct {
+	bundles collectionExporter
+}
+
+func NewExportHandler(bundles collectionExporter) ExportHandler {
+	return ExportHandler{
+		bundles: bundles,
+	}
+}
+
+func (h ExportHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	format := req.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "tar" {
+		w.WriteHeader(422)
+		w.Write([]byte(`{ "errors": [ "\"format\" must be json or tar" ] }`))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	bundle, err := h.bundles.Export(database.Connection(), context.Get("client_id").(string))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	document, err := json.Marshal(newBundleDocument(bundle))
+	if err != nil {
+		panic(err)
+	}
+
+	if format == "tar" {
+		archive := bytes.NewBuffer([]byte{})
+		err = util.WriteJSONTar(archive, document)
+		if err != nil {
+			panic(err)
+		}
+
+		w.Header().Set("Content-Type", "application/x-tar")
+		w.Header().Set("Content-Disposition", `attachment; filename="notifications-bundle.tar"`)
+		w.Write(archive.Bytes())
+		return
+	}
+
+	w.Write(document)
+}
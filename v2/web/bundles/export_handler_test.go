@@ -0,0 +1,133 @@
+package bundles_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/util"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/bundles"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExportHandler", func() {
+	var (
+		handler           bundles.ExportHandler
+		bundlesCollection *mocks.BundlesCollection
+		context           stack.Context
+		writer            *httptest.ResponseRecorder
+		conn              *mocks.Connection
+		database          *mocks.Database
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("client_id", "some-client-id")
+		context.Set("database", database)
+
+		bundlesCollection = mocks.NewBundlesCollection()
+		bundlesCollection.ExportCall.Returns.Bundle = collections.Bundle{
+			Templates: []collections.Template{{
+				ID:            "some-template-id",
+				Name:          "some-template",
+				Subject:       "{{.Subject}}",
+				Text:          "some text",
+				HTML:          "<p>some html</p>",
+				Metadata:      `{"variables": {}}`,
+				Localizations: "",
+				ClientID:      "some-client-id",
+				Version:       3,
+			}},
+			Partials: []collections.TemplatePartial{{Name: "footer", ClientID: "some-client-id", Text: "bye"}},
+			Senders:  []collections.Sender{{ID: "some-sender-id", Name: "some-sender", ClientID: "some-client-id", Transport: "billing"}},
+			CampaignTypes: []collections.CampaignType{{
+				ID:          "some-campaign-type-id",
+				Name:        "some-campaign-type",
+				Description: "some description",
+				TemplateID:  "some-template-id",
+				SenderID:    "some-sender-id",
+			}},
+		}
+
+		writer = httptest.NewRecorder()
+		handler = bundles.NewExportHandler(bundlesCollection)
+	})
+
+	It("exports the client's records as a versioned JSON bundle", func() {
+		request, err := http.NewRequest("GET", "/export", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(bundlesCollection.ExportCall.Receives.Connection).To(Equal(conn))
+		Expect(bundlesCollection.ExportCall.Receives.ClientID).To(Equal("some-client-id"))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"version": 1,
+			"api": "v2",
+			"templates": [{
+				"id": "some-template-id",
+				"name": "some-template",
+				"subject": "{{.Subject}}",
+				"text": "some text",
+				"html": "<p>some html</p>",
+				"metadata": {"variables": {}}
+			}],
+			"partials": [{"name": "footer", "text": "bye", "html": ""}],
+			"senders": [{"id": "some-sender-id", "name": "some-sender", "transport": "billing"}],
+			"campaign_types": [{
+				"id": "some-campaign-type-id",
+				"name": "some-campaign-type",
+				"description": "some description",
+				"critical": false,
+				"template_id": "some-template-id",
+				"sender_id": "some-sender-id"
+			}]
+		}`))
+	})
+
+	It("exports a tar bundle with a file for each kind of record", func() {
+		request, err := http.NewRequest("GET", "/export?format=tar", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Header().Get("Content-Type")).To(Equal("application/x-tar"))
+
+		document, err := util.ReadJSONTar(writer.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(document)).To(ContainSubstring(`"api":"v2"`))
+		Expect(string(document)).To(ContainSubstring(`"id":"some-template-id"`))
+	})
+
+	It("returns a 422 when the format is not supported", func() {
+		request, err := http.NewRequest("GET", "/export?format=zip", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(422))
+		Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["\"format\" must be json or tar"]}`))
+	})
+
+	It("returns a 500 when the bundle cannot be exported", func() {
+		bundlesCollection.ExportCall.Returns.Error = errors.New("database is gone")
+		request, err := http.NewRequest("GET", "/export", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+		Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["database is gone"]}`))
+	})
+})
@@ -0,0 +1,151 @@
+package bundles
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/util"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionImporter interface {
+	Import(conn collections.ConnectionInterface, bundle collections.Bundle, clientID string, options collections.ImportOptions) ([]collections.ImportedRecord, error)
+}
+
+type ImportHandler struct {
+	bundles       collectionImporter
+	senderDomains []string
+}
+
+func NewImportHandler(bundles collectionImporter, senderDomains []string) ImportHandler {
+	return ImportHandler{
+		bundles:       bundles,
+		senderDomains: senderDomains,
+	}
+}
+
+type importResponse struct {
+	DryRun  bool                   `json:"dry_run"`
+	Records []importRecordResponse `json:"records"`
+}
+
+type importRecordResponse struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	SourceID string `json:"source_id,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Action   string `json:"action"`
+}
+
+func (h ImportHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	query := req.URL.Query()
+	options := collections.ImportOptions{
+		Conflict: query.Get("conflict"),
+		IDMap:    map[string]string{},
+	}
+
+	if dryRun := query.Get("dry_run"); dryRun != "" {
+		var err error
+		options.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			w.WriteHeader(422)
+			w.Write([]byte(`{ "errors": [ "\"dry_run\" must be true or false" ] }`))
+			return
+		}
+	}
+
+	for _, mapping := range query["id_map"] {
+		ids := strings.SplitN(mapping, ":", 2)
+		if len(ids) != 2 || ids[0] == "" || ids[1] == "" {
+			w.WriteHeader(422)
+			w.Write([]byte(`{ "errors": [ "\"id_map\" entries must take the form <bundle-id>:<id>" ] }`))
+			return
+		}
+
+		options.IDMap[ids[0]] = ids[1]
+	}
+
+	var body io.Reader = req.Body
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType == "application/x-tar" {
+		document, err := util.ReadJSONTar(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{ "errors": [ %q ] }`, fmt.Sprintf("invalid tar bundle: %s", err))
+			return
+		}
+
+		body = bytes.NewReader(document)
+	}
+
+	var document bundleDocument
+	err := json.NewDecoder(body).Decode(&document)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "errors": [ "invalid json body" ] }`))
+		return
+	}
+
+	err = document.validate(h.senderDomains)
+	if err != nil {
+		w.WriteHeader(422)
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	if document.hasCriticalCampaignTypes() && !hasCriticalScope(context) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{ "errors": [ "You do not have permission to create critical campaign types" ] }`))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	records, err := h.bundles.Import(database.Connection(), document.bundle(), context.Get("client_id").(string), options)
+	if err != nil {
+		switch err.(type) {
+		case collections.ValidationError:
+			w.WriteHeader(422)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	response := importResponse{
+		DryRun:  options.DryRun,
+		Records: []importRecordResponse{},
+	}
+
+	for _, record := range records {
+		response.Records = append(response.Records, importRecordResponse{
+			Type:     record.Type,
+			Name:     record.Name,
+			SourceID: record.SourceID,
+			ID:       record.ID,
+			Action:   record.Action,
+		})
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+func hasCriticalScope(context stack.Context) bool {
+	token := context.Get("token").(*jwt.Token)
+	for _, scope := range token.Claims["scope"].([]interface{}) {
+		if scope.(string) == "critical_notifications.write" {
+			return true
+		}
+	}
+
+	return false
+}
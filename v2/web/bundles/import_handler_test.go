@@ -0,0 +1,222 @@
+package bundles_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/util"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/bundles"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImportHandler", func() {
+	var (
+		handler           bundles.ImportHandler
+		bundlesCollection *mocks.BundlesCollection
+		context           stack.Context
+		writer            *httptest.ResponseRecorder
+		conn              *mocks.Connection
+		database          *mocks.Database
+		document          string
+	)
+
+	setScopes := func(scopes ...string) {
+		rawToken := helpers.BuildToken(map[string]interface{}{
+			"alg": "RS256",
+		}, map[string]interface{}{
+			"client_id": "some-client-id",
+			"exp":       int64(3404281214),
+			"scope":     scopes,
+		})
+		token, err := jwt.Parse(rawToken, func(*jwt.Token) (interface{}, error) {
+			return []byte(helpers.UAAPublicKey), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		context.Set("token", token)
+	}
+
+	importBundle := func(path, contentType, body string) {
+		request, err := http.NewRequest("POST", path, bytes.NewBufferString(body))
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Content-Type", contentType)
+
+		handler.ServeHTTP(writer, request, context)
+	}
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("client_id", "some-client-id")
+		context.Set("database", database)
+		setScopes("notifications.write")
+
+		document = `{
+			"version": 1,
+			"api": "v2",
+			"templates": [{"id": "source-template-id", "name": "welcome", "text": "Hello {{.Text}}"}],
+			"partials": [{"name": "footer", "html": "<p>bye</p>"}],
+			"senders": [{"id": "source-sender-id", "name": "billing", "from_address": "billing@example.com"}],
+			"campaign_types": [{"id": "source-campaign-type-id", "name": "invoices", "description": "monthly invoices", "sender_id": "source-sender-id", "template_id": "source-template-id"}]
+		}`
+
+		bundlesCollection = mocks.NewBundlesCollection()
+		bundlesCollection.ImportCall.Returns.Records = []collections.ImportedRecord{
+			{Type: "partial", Name: "footer", Action: "created"},
+			{Type: "template", Name: "welcome", SourceID: "source-template-id", ID: "new-template-id", Action: "created"},
+		}
+
+		writer = httptest.NewRecorder()
+		handler = bundles.NewImportHandler(bundlesCollection, []string{"example.com"})
+	})
+
+	It("imports the bundle and lists what happened to each record", func() {
+		importBundle("/import?conflict=rename&id_map=source-sender-id:existing-sender-id", "application/json", document)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"dry_run": false,
+			"records": [
+				{"type": "partial", "name": "footer", "action": "created"},
+				{"type": "template", "name": "welcome", "source_id": "source-template-id", "id": "new-template-id", "action": "created"}
+			]
+		}`))
+
+		Expect(bundlesCollection.ImportCall.Receives.Connection).To(Equal(conn))
+		Expect(bundlesCollection.ImportCall.Receives.ClientID).To(Equal("some-client-id"))
+		Expect(bundlesCollection.ImportCall.Receives.Options).To(Equal(collections.ImportOptions{
+			Conflict: "rename",
+			IDMap:    map[string]string{"source-sender-id": "existing-sender-id"},
+		}))
+		Expect(bundlesCollection.ImportCall.Receives.Bundle).To(Equal(collections.Bundle{
+			Templates: []collections.Template{{
+				ID:            "source-template-id",
+				Name:          "welcome",
+				Subject:       "{{.Subject}}",
+				Text:          "Hello {{.Text}}",
+				Metadata:      "{}",
+				Localizations: "{}",
+			}},
+			Partials: []collections.TemplatePartial{{Name: "footer", HTML: "<p>bye</p>"}},
+			Senders:  []collections.Sender{{ID: "source-sender-id", Name: "billing", FromAddress: "billing@example.com"}},
+			CampaignTypes: []collections.CampaignType{{
+				ID:          "source-campaign-type-id",
+				Name:        "invoices",
+				Description: "monthly invoices",
+				SenderID:    "source-sender-id",
+				TemplateID:  "source-template-id",
+			}},
+		}))
+	})
+
+	It("passes a dry run along", func() {
+		importBundle("/import?dry_run=true", "application/json", document)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(bundlesCollection.ImportCall.Receives.Options.DryRun).To(BeTrue())
+		Expect(writer.Body.String()).To(ContainSubstring(`"dry_run":true`))
+	})
+
+	It("imports a tar bundle", func() {
+		archive := bytes.NewBuffer([]byte{})
+		Expect(util.WriteJSONTar(archive, []byte(document))).To(Succeed())
+
+		importBundle("/import", "application/x-tar", archive.String())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(bundlesCollection.ImportCall.Receives.Bundle.Templates).To(HaveLen(1))
+		Expect(bundlesCollection.ImportCall.Receives.Bundle.CampaignTypes).To(HaveLen(1))
+	})
+
+	It("requires critical_notifications.write to import critical campaign types", func() {
+		importBundle("/import", "application/json", `{
+			"version": 1,
+			"api": "v2",
+			"campaign_types": [{"name": "alerts", "description": "outages", "critical": true, "sender_id": "some-sender-id"}]
+		}`)
+
+		Expect(writer.Code).To(Equal(http.StatusForbidden))
+		Expect(bundlesCollection.ImportCall.WasCalled).To(BeFalse())
+
+		setScopes("notifications.write", "critical_notifications.write")
+		writer = httptest.NewRecorder()
+		importBundle("/import", "application/json", `{
+			"version": 1,
+			"api": "v2",
+			"campaign_types": [{"name": "alerts", "description": "outages", "critical": true, "sender_id": "some-sender-id"}]
+		}`)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+	})
+
+	Context("when the bundle is invalid", func() {
+		examples := []struct {
+			description string
+			path        string
+			body        string
+			message     string
+		}{
+			{"an unsupported version", "/import", `{"version": 2, "api": "v2"}`, `"unsupported bundle version 2"`},
+			{"a bundle from another API", "/import", `{"version": 1, "api": "v1"}`, `"bundle must be exported from the v2 API"`},
+			{"an invalid dry run", "/import?dry_run=maybe", `{"version": 1, "api": "v2"}`, `"\"dry_run\" must be true or false"`},
+			{"an invalid ID map", "/import?id_map=some-id", `{"version": 1, "api": "v2"}`, `"\"id_map\" entries must take the form \u003cbundle-id\u003e:\u003cid\u003e"`},
+			{"a template without content", "/import", `{"version": 1, "api": "v2", "templates": [{"name": "empty"}]}`, `"template \"empty\": missing either template text or html"`},
+			{"a template that does not compile", "/import", `{"version": 1, "api": "v2", "templates": [{"name": "broken", "text": "{{.Bad}"}]}`, `"template \"broken\": template: text:1: bad character U+007D '}'"`},
+			{"a sender outside the allowed domains", "/import", `{"version": 1, "api": "v2", "senders": [{"name": "billing", "from_address": "billing@elsewhere.com"}]}`, `"sender \"billing\": from_address \"billing@elsewhere.com\" is not in an allowed sending domain"`},
+			{"an invalid partial name", "/import", `{"version": 1, "api": "v2", "partials": [{"name": "has space", "text": "hi"}]}`, `"partial \"has space\": name may only contain letters, digits, \"-\" and \"_\""`},
+		}
+
+		for _, example := range examples {
+			example := example
+
+			It("returns a 422 for "+example.description, func() {
+				importBundle(example.path, "application/json", example.body)
+
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": [` + example.message + `]}`))
+				Expect(bundlesCollection.ImportCall.WasCalled).To(BeFalse())
+			})
+		}
+	})
+
+	It("returns a 400 when the body is not JSON", func() {
+		importBundle("/import", "application/json", "not json")
+
+		Expect(writer.Code).To(Equal(http.StatusBadRequest))
+		Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["invalid json body"]}`))
+	})
+
+	It("returns a 400 when the tar bundle cannot be read", func() {
+		importBundle("/import", "application/x-tar", "not a tar")
+
+		Expect(writer.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("returns a 422 when the collection rejects the bundle", func() {
+		bundlesCollection.ImportCall.Returns.Error = collections.ValidationError{Err: errors.New(`sender "billing": Transport "smtp" could not be found`)}
+
+		importBundle("/import", "application/json", document)
+
+		Expect(writer.Code).To(Equal(422))
+		Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["sender \"billing\": Transport \"smtp\" could not be found"]}`))
+	})
+
+	It("returns a 500 when the bundle cannot be stored", func() {
+		bundlesCollection.ImportCall.Returns.Error = collections.PersistenceError{Err: errors.New("database is gone")}
+
+		importBundle("/import", "application/json", document)
+
+		Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+	})
+})
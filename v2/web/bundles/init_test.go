@@ -0,0 +1,13 @@
+package bundles_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestWebV2BundlesSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "v2/web/bundles")
+}
@@ -0,0 +1,23 @@
+package bundles
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type Routes struct {
+	RequestLogging    stack.Middleware
+	Authenticator     stack.Middleware
+	DatabaseAllocator stack.Middleware
+	BundlesCollection collections.BundlesCollection
+	SenderDomains     []string
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/export", NewExportHandler(r.BundlesCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/import", NewImportHandler(r.BundlesCollection, r.SenderDomains), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+}
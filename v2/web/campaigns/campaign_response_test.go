@@ -101,4 +101,61 @@ var _ = Describe("CampaignResponse", func() {
 			}
 		}`))
 	})
+
+	It("includes the template version the campaign is rendered with", func() {
+		campaign := collections.Campaign{
+			ID:              "some-campaign-id",
+			CampaignTypeID:  "some-campaign-type-id",
+			TemplateID:      "some-template-id",
+			TemplateVersion: 3,
+		}
+
+		output, err := json.Marshal(campaigns.NewCampaignResponse(campaign))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchJSON(`{
+			"id":   "some-campaign-id",
+			"send_to": null,
+			"campaign_type_id": "some-campaign-type-id",
+			"text": "",
+			"html": "",
+			"subject": "",
+			"template_id": "some-template-id",
+			"template_version": 3,
+			"reply_to": "",
+			"_links": {
+				"self": {
+					"href": "/campaigns/some-campaign-id"
+				},
+				"template": {
+					"href": "/templates/some-template-id"
+				},
+				"template_version": {
+					"href": "/templates/some-template-id/versions/3"
+				},
+				"campaign_type": {
+					"href": "/campaign_types/some-campaign-type-id"
+				},
+				"status": {
+					"href": "/campaigns/some-campaign-id/status"
+				}
+			}
+		}`))
+	})
+
+	It("includes where the template the campaign is rendered with came from", func() {
+		campaign := collections.Campaign{
+			ID:             "some-campaign-id",
+			CampaignTypeID: "some-campaign-type-id",
+			TemplateID:     "some-template-id",
+			TemplateSource: "client",
+		}
+
+		output, err := json.Marshal(campaigns.NewCampaignResponse(campaign))
+		Expect(err).NotTo(HaveOccurred())
+
+		var response map[string]interface{}
+		err = json.Unmarshal(output, &response)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response["template_source"]).To(Equal("client"))
+	})
 })
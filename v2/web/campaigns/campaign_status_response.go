@@ -8,13 +8,16 @@ import (
 )
 
 type CampaignStatusResponseLinks struct {
-	Self     Link `json:"self"`
-	Campaign Link `json:"campaign"`
+	Self            Link  `json:"self"`
+	Campaign        Link  `json:"campaign"`
+	TemplateVersion *Link `json:"template_version,omitempty"`
 }
 
 type CampaignStatusResponse struct {
 	CampaignID            string                      `json:"id"`
 	Status                string                      `json:"status"`
+	TemplateID            string                      `json:"template_id,omitempty"`
+	TemplateVersion       int                         `json:"template_version,omitempty"`
 	TotalMessages         int                         `json:"total_messages"`
 	SentMessages          int                         `json:"sent_messages"`
 	RetryMessages         int                         `json:"retry_messages"`
@@ -30,6 +33,8 @@ func NewCampaignStatusResponse(status collections.CampaignStatus) CampaignStatus
 	return CampaignStatusResponse{
 		CampaignID:            status.CampaignID,
 		Status:                status.Status,
+		TemplateID:            status.TemplateID,
+		TemplateVersion:       status.TemplateVersion,
 		TotalMessages:         status.TotalMessages,
 		SentMessages:          status.SentMessages,
 		RetryMessages:         status.RetryMessages,
@@ -39,8 +44,9 @@ func NewCampaignStatusResponse(status collections.CampaignStatus) CampaignStatus
 		StartTime:             status.StartTime,
 		CompletedTime:         status.CompletedTime,
 		Links: CampaignStatusResponseLinks{
-			Self:     Link{fmt.Sprintf("/campaigns/%s/status", status.CampaignID)},
-			Campaign: Link{fmt.Sprintf("/campaigns/%s", status.CampaignID)},
+			Self:            Link{fmt.Sprintf("/campaigns/%s/status", status.CampaignID)},
+			Campaign:        Link{fmt.Sprintf("/campaigns/%s", status.CampaignID)},
+			TemplateVersion: templateVersionLink(status.TemplateID, status.TemplateVersion),
 		},
 	}
 }
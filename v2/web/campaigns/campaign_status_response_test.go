@@ -92,4 +92,42 @@ var _ = Describe("CampaignStatusResponse", func() {
 			}
 		}`))
 	})
+
+	It("includes the template version the campaign was rendered with", func() {
+		campaignStatus := collections.CampaignStatus{
+			CampaignID:      "some-campaign-id",
+			Status:          "sending",
+			TemplateID:      "some-template-id",
+			TemplateVersion: 2,
+			StartTime:       startTime,
+		}
+
+		output, err := json.Marshal(campaigns.NewCampaignStatusResponse(campaignStatus))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchJSON(`{
+			"id": "some-campaign-id",
+			"status": "sending",
+			"template_id": "some-template-id",
+			"template_version": 2,
+			"total_messages": 0,
+			"sent_messages": 0,
+			"retry_messages": 0,
+			"failed_messages": 0,
+			"queued_messages": 0,
+			"undeliverable_messages": 0,
+			"start_time": "2009-12-11T10:21:45Z",
+			"completed_time": null,
+			"_links": {
+				"self": {
+					"href": "/campaigns/some-campaign-id/status"
+				},
+				"campaign": {
+					"href": "/campaigns/some-campaign-id"
+				},
+				"template_version": {
+					"href": "/templates/some-template-id/versions/2"
+				}
+			}
+		}`))
+	})
 })
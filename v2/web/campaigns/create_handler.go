@@ -3,16 +3,27 @@ package campaigns
 import (
 	"encoding/json"
 	"fmt"
+	"mime"
 	"net/http"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/markdown"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 )
 
+const (
+	maxAttachmentSize   = 3 << 20
+	maxAttachmentsTotal = 10 << 20
+	maxDataSize         = 16 << 10
+	maxRecipientData    = 1 << 20
+)
+
 type collectionCreator interface {
 	Create(conn collections.ConnectionInterface, campaign collections.Campaign, clientID string, hasCriticalScope bool) (collections.Campaign, error)
 }
@@ -34,13 +45,34 @@ func NewCreateHandler(collection collectionCreator, clock clock) CreateHandler {
 }
 
 type createRequest struct {
-	SendTo         map[string][]string `json:"send_to"`
-	CampaignTypeID string              `json:"campaign_type_id"`
-	Text           string              `json:"text"`
-	HTML           string              `json:"html"`
-	Subject        string              `json:"subject"`
-	TemplateID     string              `json:"template_id"`
-	ReplyTo        string              `json:"reply_to"`
+	SendTo         map[string][]string               `json:"send_to"`
+	CampaignTypeID string                            `json:"campaign_type_id"`
+	Text           string                            `json:"text"`
+	HTML           string                            `json:"html"`
+	Markdown       string                            `json:"markdown"`
+	Subject        string                            `json:"subject"`
+	TemplateID     string                            `json:"template_id"`
+	ReplyTo        string                            `json:"reply_to"`
+	Attachments    []attachmentRequest               `json:"attachments"`
+	ThreadKey      string                            `json:"thread_key"`
+	Headers        map[string]string                 `json:"headers"`
+	Localizations  map[string]localizationRequest    `json:"localizations"`
+	Data           map[string]interface{}            `json:"data"`
+	RecipientData  map[string]map[string]interface{} `json:"recipient_data"`
+}
+
+type localizationRequest struct {
+	Subject  string `json:"subject"`
+	Text     string `json:"text"`
+	HTML     string `json:"html"`
+	Markdown string `json:"markdown"`
+}
+
+type attachmentRequest struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	ContentID   string `json:"content_id"`
+	Content     []byte `json:"content"`
 }
 
 func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
@@ -56,6 +88,8 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
+	request.renderMarkdown()
+
 	if !isValid(request, w, req) {
 		return
 	}
@@ -68,6 +102,38 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		}
 	}
 
+	var attachments []collections.Attachment
+	for _, attachment := range request.Attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(attachment.Filename))
+		}
+
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		attachments = append(attachments, collections.Attachment{
+			Filename:    attachment.Filename,
+			ContentType: contentType,
+			ContentID:   attachment.ContentID,
+			Content:     attachment.Content,
+		})
+	}
+
+	var localizations map[string]collections.CampaignLocalization
+	for locale, localization := range request.Localizations {
+		if localizations == nil {
+			localizations = map[string]collections.CampaignLocalization{}
+		}
+
+		localizations[locale] = collections.CampaignLocalization{
+			Subject: localization.Subject,
+			Text:    localization.Text,
+			HTML:    localization.HTML,
+		}
+	}
+
 	database := context.Get("database").(DatabaseInterface)
 
 	campaign, err := h.collection.Create(database.Connection(), collections.Campaign{
@@ -80,6 +146,12 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		ReplyTo:        request.ReplyTo,
 		SenderID:       senderID,
 		StartTime:      h.clock.Now(),
+		Attachments:    attachments,
+		ThreadKey:      request.ThreadKey,
+		Headers:        request.Headers,
+		Localizations:  localizations,
+		Data:           request.Data,
+		RecipientData:  request.RecipientData,
 	}, context.Get("client_id").(string), hasCriticalScope)
 	if err != nil {
 		switch err.(type) {
@@ -87,6 +159,8 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 			w.WriteHeader(http.StatusNotFound)
 		case collections.PermissionsError:
 			w.WriteHeader(http.StatusForbidden)
+		case collections.ValidationError:
+			w.WriteHeader(422)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -99,6 +173,32 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	json.NewEncoder(w).Encode(NewCampaignResponse(campaign))
 }
 
+func (request *createRequest) renderMarkdown() {
+	request.Text, request.HTML = renderMarkdown(request.Markdown, request.Text, request.HTML)
+
+	for locale, localization := range request.Localizations {
+		localization.Text, localization.HTML = renderMarkdown(localization.Markdown, localization.Text, localization.HTML)
+		request.Localizations[locale] = localization
+	}
+}
+
+func renderMarkdown(source, text, html string) (string, string) {
+	if source == "" {
+		return text, html
+	}
+
+	renderedHTML, renderedText := markdown.Render(source)
+	if text == "" {
+		text = renderedText
+	}
+
+	if html == "" {
+		html = renderedHTML
+	}
+
+	return text, html
+}
+
 func isValid(request createRequest, w http.ResponseWriter, req *http.Request) bool {
 	for audienceKey, _ := range request.SendTo {
 		if !contains([]string{"users", "spaces", "orgs", "emails"}, audienceKey) {
@@ -117,16 +217,83 @@ func isValid(request createRequest, w http.ResponseWriter, req *http.Request) bo
 	}
 
 	if request.Text == "" && request.HTML == "" {
-		return invalidResponse(w, "missing either campaign text or html")
+		return invalidResponse(w, "missing either campaign text, html or markdown")
 	}
 
 	if request.Subject == "" {
 		return invalidResponse(w, "missing subject")
 	}
 
+	var attachmentsTotal int
+	for _, attachment := range request.Attachments {
+		if attachment.Filename == "" {
+			return invalidResponse(w, "missing attachment filename")
+		}
+
+		if len(attachment.Content) == 0 {
+			return invalidResponse(w, fmt.Sprintf("missing content for attachment %q", attachment.Filename))
+		}
+
+		if len(attachment.Content) > maxAttachmentSize {
+			return invalidResponse(w, fmt.Sprintf("attachment %q exceeds the maximum size of %d bytes", attachment.Filename, maxAttachmentSize))
+		}
+
+		attachmentsTotal += len(attachment.Content)
+	}
+
+	if attachmentsTotal > maxAttachmentsTotal {
+		return invalidResponse(w, fmt.Sprintf("attachments exceed the maximum total size of %d bytes", maxAttachmentsTotal))
+	}
+
+	for locale := range request.Localizations {
+		if strings.TrimSpace(locale) == "" {
+			return invalidResponse(w, "localizations must be keyed by a locale")
+		}
+	}
+
+	err := mail.ValidateHeaders(request.Headers)
+	if err != nil {
+		return invalidResponse(w, err.Error())
+	}
+
+	if encodedSize(request.Data) > maxDataSize {
+		return invalidResponse(w, fmt.Sprintf("data exceeds the maximum size of %d bytes", maxDataSize))
+	}
+
+	var recipientDataTotal int
+	for recipient, data := range request.RecipientData {
+		if strings.TrimSpace(recipient) == "" {
+			return invalidResponse(w, "recipient_data must be keyed by a user GUID or email address")
+		}
+
+		size := encodedSize(data)
+		if size > maxDataSize {
+			return invalidResponse(w, fmt.Sprintf("recipient_data for %q exceeds the maximum size of %d bytes", recipient, maxDataSize))
+		}
+
+		recipientDataTotal += size
+	}
+
+	if recipientDataTotal > maxRecipientData {
+		return invalidResponse(w, fmt.Sprintf("recipient_data exceeds the maximum total size of %d bytes", maxRecipientData))
+	}
+
 	return true
 }
 
+func encodedSize(data map[string]interface{}) int {
+	if data == nil {
+		return 0
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+
+	return len(encoded)
+}
+
 func contains(elements []string, element string) bool {
 	for _, elem := range elements {
 		if element == elem {
@@ -2,10 +2,12 @@ package campaigns_test
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
@@ -306,6 +308,166 @@ var _ = Describe("CreateHandler", func() {
 		}))
 	})
 
+	It("sends a campaign with attachments", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"send_to": map[string][]string{
+				"users": {"user-123"},
+			},
+			"campaign_type_id": "some-campaign-type-id",
+			"text":             "come see our new stuff",
+			"subject":          "Cool New Stuff",
+			"attachments": []map[string]interface{}{
+				{
+					"filename":     "invoice.pdf",
+					"content_type": "application/pdf",
+					"content":      base64.StdEncoding.EncodeToString([]byte("some-pdf-content")),
+				},
+				{
+					"filename":   "logo.png",
+					"content_id": "logo",
+					"content":    base64.StdEncoding.EncodeToString([]byte("some-png-content")),
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusAccepted))
+		Expect(campaignsCollection.CreateCall.Receives.Campaign.Attachments).To(Equal([]collections.Attachment{
+			{
+				Filename:    "invoice.pdf",
+				ContentType: "application/pdf",
+				Content:     []byte("some-pdf-content"),
+			},
+			{
+				Filename:    "logo.png",
+				ContentType: "image/png",
+				ContentID:   "logo",
+				Content:     []byte("some-png-content"),
+			},
+		}))
+	})
+
+	It("sends a campaign with a thread key", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"send_to": map[string][]string{
+				"users": {"user-123"},
+			},
+			"campaign_type_id": "some-campaign-type-id",
+			"text":             "come see our new stuff",
+			"subject":          "Cool New Stuff",
+			"thread_key":       "some-thread-key",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusAccepted))
+		Expect(campaignsCollection.CreateCall.Receives.Campaign.ThreadKey).To(Equal("some-thread-key"))
+	})
+
+	It("sends a campaign with custom headers", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"send_to": map[string][]string{
+				"users": {"user-123"},
+			},
+			"campaign_type_id": "some-campaign-type-id",
+			"text":             "come see our new stuff",
+			"subject":          "Cool New Stuff",
+			"headers": map[string]string{
+				"X-Tracking-ID": "some-tracking-id",
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusAccepted))
+		Expect(campaignsCollection.CreateCall.Receives.Campaign.Headers).To(Equal(map[string]string{
+			"X-Tracking-ID": "some-tracking-id",
+		}))
+	})
+
+	It("sends a campaign with template data for all recipients and for each one", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"send_to": map[string][]string{
+				"users": {"user-123", "user-456"},
+			},
+			"campaign_type_id": "some-campaign-type-id",
+			"text":             "{{.Data.product}} is {{.Data.discount}}% off",
+			"subject":          "Cool New Stuff",
+			"data": map[string]interface{}{
+				"product":  "widget",
+				"discount": 10,
+			},
+			"recipient_data": map[string]interface{}{
+				"user-456": map[string]interface{}{"discount": 20},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusAccepted))
+		Expect(campaignsCollection.CreateCall.Receives.Campaign.Data).To(Equal(map[string]interface{}{
+			"product":  "widget",
+			"discount": 10.0,
+		}))
+		Expect(campaignsCollection.CreateCall.Receives.Campaign.RecipientData).To(Equal(map[string]map[string]interface{}{
+			"user-456": {"discount": 20.0},
+		}))
+	})
+
+	It("sends a campaign written in markdown", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"send_to": map[string][]string{
+				"users": {"user-123"},
+			},
+			"campaign_type_id": "some-campaign-type-id",
+			"markdown":         "come see our **new** stuff",
+			"subject":          "Cool New Stuff",
+			"localizations": map[string]interface{}{
+				"fr": map[string]string{
+					"subject":  "Nouveautés",
+					"markdown": "venez voir nos *nouveautés*",
+					"text":     "venez voir nos nouveautés",
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusAccepted))
+
+		campaign := campaignsCollection.CreateCall.Receives.Campaign
+		Expect(campaign.Text).To(Equal("come see our new stuff\n"))
+		Expect(campaign.HTML).To(Equal("<p>come see our <strong>new</strong> stuff</p>\n"))
+		Expect(campaign.Localizations).To(Equal(map[string]collections.CampaignLocalization{
+			"fr": {
+				Subject: "Nouveautés",
+				Text:    "venez voir nos nouveautés",
+				HTML:    "<p>venez voir nos <em>nouveautés</em></p>\n",
+			},
+		}))
+	})
+
 	Context("when validating user-input", func() {
 		Context("when the campaign_type_id is missing", func() {
 			BeforeEach(func() {
@@ -354,7 +516,7 @@ var _ = Describe("CreateHandler", func() {
 			It("returns a 422 and states that the request is missing either text or html", func() {
 				handler.ServeHTTP(writer, request, context)
 				Expect(writer.Code).To(Equal(422))
-				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["missing either campaign text or html"]}`))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["missing either campaign text, html or markdown"]}`))
 			})
 		})
 
@@ -428,6 +590,197 @@ var _ = Describe("CreateHandler", func() {
 				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["\"malformed-email\" is not a valid email address"]}`))
 			})
 		})
+
+		Context("when an attachment is missing a filename", func() {
+			BeforeEach(func() {
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"send_to": map[string][]string{
+						"users": {"user-123"},
+					},
+					"campaign_type_id": "some-campaign-type-id",
+					"text":             "come see our new stuff",
+					"subject":          "Cool New Stuff",
+					"attachments": []map[string]interface{}{
+						{"content": base64.StdEncoding.EncodeToString([]byte("some-content"))},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a 422 and states the attachment is missing a filename", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["missing attachment filename"]}`))
+			})
+		})
+
+		Context("when an attachment is missing content", func() {
+			BeforeEach(func() {
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"send_to": map[string][]string{
+						"users": {"user-123"},
+					},
+					"campaign_type_id": "some-campaign-type-id",
+					"text":             "come see our new stuff",
+					"subject":          "Cool New Stuff",
+					"attachments": []map[string]interface{}{
+						{"filename": "invoice.pdf"},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a 422 and states the attachment is missing content", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["missing content for attachment \"invoice.pdf\""]}`))
+			})
+		})
+
+		Context("when an attachment is too large", func() {
+			BeforeEach(func() {
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"send_to": map[string][]string{
+						"users": {"user-123"},
+					},
+					"campaign_type_id": "some-campaign-type-id",
+					"text":             "come see our new stuff",
+					"subject":          "Cool New Stuff",
+					"attachments": []map[string]interface{}{
+						{
+							"filename": "huge.pdf",
+							"content":  base64.StdEncoding.EncodeToString(make([]byte, 3<<20+1)),
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a 422 and states the attachment is too large", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["attachment \"huge.pdf\" exceeds the maximum size of 3145728 bytes"]}`))
+			})
+		})
+
+		Context("when a header cannot be overridden", func() {
+			BeforeEach(func() {
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"send_to": map[string][]string{
+						"users": {"user-123"},
+					},
+					"campaign_type_id": "some-campaign-type-id",
+					"text":             "come see our new stuff",
+					"subject":          "Cool New Stuff",
+					"headers": map[string]string{
+						"From": "someone-else@example.com",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a 422 and states the header is not allowed", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["header \"From\" cannot be overridden"]}`))
+			})
+		})
+
+		Context("when the attachments are too large in total", func() {
+			BeforeEach(func() {
+				content := base64.StdEncoding.EncodeToString(make([]byte, 3<<20))
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"send_to": map[string][]string{
+						"users": {"user-123"},
+					},
+					"campaign_type_id": "some-campaign-type-id",
+					"text":             "come see our new stuff",
+					"subject":          "Cool New Stuff",
+					"attachments": []map[string]interface{}{
+						{"filename": "first.pdf", "content": content},
+						{"filename": "second.pdf", "content": content},
+						{"filename": "third.pdf", "content": content},
+						{"filename": "fourth.pdf", "content": content},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a 422 and states the attachments are too large", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["attachments exceed the maximum total size of 10485760 bytes"]}`))
+			})
+		})
+
+		Context("when the data is too large", func() {
+			BeforeEach(func() {
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"send_to": map[string][]string{
+						"users": {"user-123"},
+					},
+					"campaign_type_id": "some-campaign-type-id",
+					"text":             "come see our new stuff",
+					"subject":          "Cool New Stuff",
+					"data": map[string]interface{}{
+						"notes": strings.Repeat("x", 16<<10),
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a 422 and states the data is too large", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["data exceeds the maximum size of 16384 bytes"]}`))
+			})
+		})
+
+		Context("when the data for a recipient is too large", func() {
+			BeforeEach(func() {
+				requestBody, err := json.Marshal(map[string]interface{}{
+					"send_to": map[string][]string{
+						"users": {"user-123"},
+					},
+					"campaign_type_id": "some-campaign-type-id",
+					"text":             "come see our new stuff",
+					"subject":          "Cool New Stuff",
+					"recipient_data": map[string]interface{}{
+						"user-123": map[string]interface{}{
+							"notes": strings.Repeat("x", 16<<10),
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns a 422 and names the recipient", func() {
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["recipient_data for \"user-123\" exceeds the maximum size of 16384 bytes"]}`))
+			})
+		})
 	})
 
 	Context("when the token does not have the critical scope", func() {
@@ -532,6 +885,15 @@ var _ = Describe("CreateHandler", func() {
 			})
 		})
 
+		Context("when the collection returns a validation error", func() {
+			It("returns a 422 and the corresponding error", func() {
+				campaignsCollection.CreateCall.Returns.Error = collections.ValidationError{Err: errors.New("data.app_name is required")}
+				handler.ServeHTTP(writer, request, context)
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["data.app_name is required"]}`))
+			})
+		})
+
 		Context("when the request JSON is not well-formed", func() {
 			It("returns a 400 and states that the request is invalid", func() {
 				request, err := http.NewRequest("POST", "/senders/some-sender-id/campaigns", bytes.NewBufferString("%%%"))
@@ -19,7 +19,11 @@ type CampaignTypeResponse struct {
 	Name        string                    `json:"name"`
 	Description string                    `json:"description"`
 	Critical    bool                      `json:"critical"`
+	Threading   bool                      `json:"threading"`
 	TemplateID  string                    `json:"template_id"`
+	Headers     map[string]string         `json:"headers,omitempty"`
+	FromAddress string                    `json:"from_address,omitempty"`
+	FromName    string                    `json:"from_name,omitempty"`
 	Links       CampaignTypeResponseLinks `json:"_links"`
 }
 
@@ -29,7 +33,11 @@ func NewCampaignTypeResponse(campaignType collections.CampaignType) CampaignType
 		Name:        campaignType.Name,
 		Description: campaignType.Description,
 		Critical:    campaignType.Critical,
+		Threading:   campaignType.Threading,
 		TemplateID:  campaignType.TemplateID,
+		Headers:     campaignType.Headers,
+		FromAddress: campaignType.FromAddress,
+		FromName:    campaignType.FromName,
 		Links: CampaignTypeResponseLinks{
 			Self: Link{Href: fmt.Sprintf("/campaign_types/%s", campaignType.ID)},
 		},
@@ -39,6 +39,7 @@ var _ = Describe("CampaignTypeResponse", func() {
 			Name:        "some-campaign-type",
 			Description: "cool campaign type",
 			Critical:    true,
+			Threading:   true,
 			TemplateID:  "some-template-id",
 		}
 
@@ -49,6 +50,7 @@ var _ = Describe("CampaignTypeResponse", func() {
 			"name": "some-campaign-type",
 			"description": "cool campaign type",
 			"critical": true,
+			"threading": true,
 			"template_id": "some-template-id",
 			"_links": {
 				"self": {
@@ -57,4 +59,33 @@ var _ = Describe("CampaignTypeResponse", func() {
 			}
 		}`))
 	})
+
+	It("includes the default headers when there are any", func() {
+		campaignType := collections.CampaignType{
+			ID:          "some-campaign-type-id",
+			Name:        "some-campaign-type",
+			Description: "cool campaign type",
+			TemplateID:  "some-template-id",
+			Headers:     map[string]string{"Importance": "high"},
+		}
+
+		output, err := json.Marshal(campaigntypes.NewCampaignTypeResponse(campaignType))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchJSON(`{
+			"id":   "some-campaign-type-id",
+			"name": "some-campaign-type",
+			"description": "cool campaign type",
+			"critical": false,
+			"threading": false,
+			"template_id": "some-template-id",
+			"headers": {
+				"Importance": "high"
+			},
+			"_links": {
+				"self": {
+					"href": "/campaign_types/some-campaign-type-id"
+				}
+			}
+		}`))
+	})
 })
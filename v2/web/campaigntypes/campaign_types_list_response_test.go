@@ -73,6 +73,7 @@ var _ = Describe("CampaignTypesListResponse", func() {
 					"name": "some-campaign-type",
 					"description": "first campaign type",
 					"critical": false,
+					"threading": false,
 					"template_id": "",
 					"_links": {
 						"self": {
@@ -85,6 +86,7 @@ var _ = Describe("CampaignTypesListResponse", func() {
 					"name": "another-campaign-type",
 					"description": "second campaign type",
 					"critical": true,
+					"threading": false,
 					"template_id": "template-id",
 					"_links": {
 						"self": {
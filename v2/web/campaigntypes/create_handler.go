@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
@@ -16,12 +17,14 @@ type collectionSetter interface {
 }
 
 type CreateHandler struct {
-	collection collectionSetter
+	collection    collectionSetter
+	senderDomains []string
 }
 
-func NewCreateHandler(collection collectionSetter) CreateHandler {
+func NewCreateHandler(collection collectionSetter, senderDomains []string) CreateHandler {
 	return CreateHandler{
-		collection: collection,
+		collection:    collection,
+		senderDomains: senderDomains,
 	}
 }
 
@@ -30,10 +33,14 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	senderID := splitURL[len(splitURL)-2]
 
 	var createRequest struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-		Critical    bool   `json:"critical"`
-		TemplateID  string `json:"template_id"`
+		Name        string            `json:"name"`
+		Description string            `json:"description"`
+		Critical    bool              `json:"critical"`
+		Threading   bool              `json:"threading"`
+		TemplateID  string            `json:"template_id"`
+		Headers     map[string]string `json:"headers"`
+		FromAddress string            `json:"from_address"`
+		FromName    string            `json:"from_name"`
 	}
 
 	err := json.NewDecoder(req.Body).Decode(&createRequest)
@@ -55,6 +62,20 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
+	err = mail.ValidateHeaders(createRequest.Headers)
+	if err != nil {
+		w.WriteHeader(422)
+		fmt.Fprintf(w, `{"errors": [%q]}`, err)
+		return
+	}
+
+	err = mail.ValidateFrom(createRequest.FromName, createRequest.FromAddress, h.senderDomains)
+	if err != nil {
+		w.WriteHeader(422)
+		fmt.Fprintf(w, `{"errors": [%q]}`, err)
+		return
+	}
+
 	if createRequest.Critical == true {
 		hasCriticalWrite := false
 		token := context.Get("token").(*jwt.Token)
@@ -77,8 +98,12 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		Name:        createRequest.Name,
 		Description: createRequest.Description,
 		Critical:    createRequest.Critical,
+		Threading:   createRequest.Threading,
 		TemplateID:  createRequest.TemplateID,
 		SenderID:    senderID,
+		Headers:     createRequest.Headers,
+		FromAddress: createRequest.FromAddress,
+		FromName:    createRequest.FromName,
 	}, context.Get("client_id").(string))
 	if err != nil {
 		switch err.(type) {
@@ -78,7 +78,7 @@ var _ = Describe("CreateHandler", func() {
 		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaign_types", bytes.NewBuffer(requestBody))
 		Expect(err).NotTo(HaveOccurred())
 
-		handler = campaigntypes.NewCreateHandler(campaignTypesCollection)
+		handler = campaigntypes.NewCreateHandler(campaignTypesCollection, []string{"example.com"})
 	})
 
 	It("creates a campaign type", func() {
@@ -99,6 +99,7 @@ var _ = Describe("CreateHandler", func() {
 			"name": "some-campaign-type",
 			"description": "some-campaign-type-description",
 			"critical": false,
+			"threading": false,
 			"template_id": "some-template-id",
 			"_links": {
 				"self": {
@@ -108,6 +109,63 @@ var _ = Describe("CreateHandler", func() {
 		}`))
 	})
 
+	It("creates a campaign type with default headers", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"name":        "some-campaign-type",
+			"description": "some-campaign-type-description",
+			"headers": map[string]string{
+				"Auto-Submitted": "auto-generated",
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaign_types", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(campaignTypesCollection.SetCall.Receives.CampaignType.Headers).To(Equal(map[string]string{
+			"Auto-Submitted": "auto-generated",
+		}))
+	})
+
+	It("creates a campaign type that opts into threading", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"name":        "some-campaign-type",
+			"description": "some-campaign-type-description",
+			"threading":   true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaign_types", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(campaignTypesCollection.SetCall.Receives.CampaignType.Threading).To(BeTrue())
+	})
+
+	It("creates a campaign type with a from address and name", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"name":         "some-campaign-type",
+			"description":  "some-campaign-type-description",
+			"from_address": "billing@example.com",
+			"from_name":    "Billing",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders/some-sender-id/campaign_types", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(campaignTypesCollection.SetCall.Receives.CampaignType.FromAddress).To(Equal("billing@example.com"))
+		Expect(campaignTypesCollection.SetCall.Receives.CampaignType.FromName).To(Equal("Billing"))
+	})
+
 	It("requires critical_notifications.write to create a critical campaign type", func() {
 		tokenClaims["scope"] = []string{"notifications.write", "critical_notifications.write"}
 		rawToken := helpers.BuildToken(tokenHeader, tokenClaims)
@@ -153,6 +211,7 @@ var _ = Describe("CreateHandler", func() {
 			"name": "some-campaign-type",
 			"description": "some-campaign-type-description",
 			"critical": true,
+			"threading": false,
 			"template_id": "some-template-id",
 			"_links": {
 				"self": {
@@ -219,6 +278,45 @@ var _ = Describe("CreateHandler", func() {
 			}`))
 		})
 
+		It("returns a 422 when a header cannot be overridden", func() {
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"name":        "some-campaign-type",
+				"description": "some-campaign-type-description",
+				"headers": map[string]string{
+					"DKIM-Signature": "some-signature",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = http.NewRequest("POST", "/senders/some-sender-id/campaign_types", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["header \"DKIM-Signature\" cannot be overridden"]
+			}`))
+		})
+
+		It("returns a 422 when the from address is not in an allowed sending domain", func() {
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"name":         "some-campaign-type",
+				"description":  "some-campaign-type-description",
+				"from_address": "billing@elsewhere.com",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = http.NewRequest("POST", "/senders/some-sender-id/campaign_types", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["from_address \"billing@elsewhere.com\" is not in an allowed sending domain"]
+			}`))
+			Expect(campaignTypesCollection.SetCall.WasCalled).To(BeFalse())
+		})
+
 		It("returns a 422 when description is omitted", func() {
 			requestBody, err := json.Marshal(map[string]interface{}{
 				"name":        "some name",
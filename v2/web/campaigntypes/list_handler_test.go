@@ -79,6 +79,7 @@ var _ = Describe("ListHandler", func() {
 					"name": "first-campaign-type",
 					"description": "first-campaign-type-description",
 					"critical": false,
+					"threading": false,
 					"template_id": "",
 					"_links": {
 						"self": {
@@ -91,6 +92,7 @@ var _ = Describe("ListHandler", func() {
 					"name": "second-campaign-type",
 					"description": "second-campaign-type-description",
 					"critical": true,
+					"threading": false,
 					"template_id": "",
 					"_links": {
 						"self": {
@@ -14,12 +14,13 @@ type Routes struct {
 	Authenticator           stack.Middleware
 	DatabaseAllocator       stack.Middleware
 	CampaignTypesCollection collections.CampaignTypesCollection
+	SenderDomains           []string
 }
 
 func (r Routes) Register(m muxer) {
-	m.Handle("POST", "/senders/{sender_id}/campaign_types", NewCreateHandler(r.CampaignTypesCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/senders/{sender_id}/campaign_types", NewCreateHandler(r.CampaignTypesCollection, r.SenderDomains), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/senders/{sender_id}/campaign_types", NewListHandler(r.CampaignTypesCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/campaign_types/{campaign_type_id:.*}", NewShowHandler(r.CampaignTypesCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
-	m.Handle("PUT", "/campaign_types/{campaign_type_id}", NewUpdateHandler(r.CampaignTypesCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/campaign_types/{campaign_type_id}", NewUpdateHandler(r.CampaignTypesCollection, r.SenderDomains), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
 	m.Handle("DELETE", "/campaign_types/{campaign_type_id}", NewDeleteHandler(r.CampaignTypesCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
 }
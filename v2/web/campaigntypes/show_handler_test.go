@@ -67,6 +67,7 @@ var _ = Describe("ShowHandler", func() {
 			"name": "first-campaign-type",
 			"description": "first-campaign-type-description",
 			"critical": true,
+			"threading": false,
 			"template_id": "template-id",
 			"_links": {
 				"self": {
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
@@ -17,20 +18,26 @@ type collectionUpdater interface {
 }
 
 type UpdateHandler struct {
-	collection collectionUpdater
+	collection    collectionUpdater
+	senderDomains []string
 }
 
-func NewUpdateHandler(collection collectionUpdater) UpdateHandler {
+func NewUpdateHandler(collection collectionUpdater, senderDomains []string) UpdateHandler {
 	return UpdateHandler{
-		collection: collection,
+		collection:    collection,
+		senderDomains: senderDomains,
 	}
 }
 
 type UpdateRequest struct {
-	Name        *string `json:"name"`
-	Description *string `json:"description"`
-	Critical    *bool   `json:"critical"`
-	TemplateID  *string `json:"template_id"`
+	Name        *string           `json:"name"`
+	Description *string           `json:"description"`
+	Critical    *bool             `json:"critical"`
+	Threading   *bool             `json:"threading"`
+	TemplateID  *string           `json:"template_id"`
+	Headers     map[string]string `json:"headers"`
+	FromAddress *string           `json:"from_address"`
+	FromName    *string           `json:"from_name"`
 }
 
 func (u UpdateRequest) isValid() (bool, string) {
@@ -47,6 +54,12 @@ func (u UpdateRequest) isValid() (bool, string) {
 		validationErrors = append(validationErrors, "description cannot be blank")
 	}
 
+	err := mail.ValidateHeaders(u.Headers)
+	if err != nil {
+		validFlag = false
+		validationErrors = append(validationErrors, err.Error())
+	}
+
 	return validFlag, strings.Join(validationErrors, ", ")
 }
 
@@ -62,10 +75,26 @@ func (u UpdateRequest) includesCritical() bool {
 	return u.Critical != nil
 }
 
+func (u UpdateRequest) includesThreading() bool {
+	return u.Threading != nil
+}
+
 func (u UpdateRequest) includesTemplateID() bool {
 	return u.TemplateID != nil
 }
 
+func (u UpdateRequest) includesHeaders() bool {
+	return u.Headers != nil
+}
+
+func (u UpdateRequest) includesFromAddress() bool {
+	return u.FromAddress != nil
+}
+
+func (u UpdateRequest) includesFromName() bool {
+	return u.FromName != nil
+}
+
 func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
 	splitURL := strings.Split(req.URL.Path, "/")
 	campaignTypeID := splitURL[len(splitURL)-1]
@@ -111,10 +140,33 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		campaignType.Critical = *updateRequest.Critical
 	}
 
+	if updateRequest.includesThreading() {
+		campaignType.Threading = *updateRequest.Threading
+	}
+
 	if updateRequest.includesTemplateID() {
 		campaignType.TemplateID = *updateRequest.TemplateID
 	}
 
+	if updateRequest.includesHeaders() {
+		campaignType.Headers = updateRequest.Headers
+	}
+
+	if updateRequest.includesFromAddress() {
+		campaignType.FromAddress = *updateRequest.FromAddress
+	}
+
+	if updateRequest.includesFromName() {
+		campaignType.FromName = *updateRequest.FromName
+	}
+
+	err = mail.ValidateFrom(campaignType.FromName, campaignType.FromAddress, h.senderDomains)
+	if err != nil {
+		w.WriteHeader(422)
+		fmt.Fprintf(w, `{"errors": [%q]}`, err)
+		return
+	}
+
 	if campaignType.Critical == true {
 		hasCriticalWrite := false
 		token := context.Get("token").(*jwt.Token)
@@ -82,7 +82,7 @@ var _ = Describe("UpdateHandler", func() {
 		request, err = http.NewRequest("PUT", "/campaign_types/some-campaign-type-id", bytes.NewBuffer(requestBody))
 		Expect(err).NotTo(HaveOccurred())
 
-		handler = campaigntypes.NewUpdateHandler(campaignTypesCollection)
+		handler = campaigntypes.NewUpdateHandler(campaignTypesCollection, []string{"example.com"})
 	})
 
 	It("updates an existing campaign type", func() {
@@ -123,6 +123,7 @@ var _ = Describe("UpdateHandler", func() {
 			"name": "update-campaign-type",
 			"description": "update-campaign-type-description",
 			"critical": true,
+			"threading": false,
 			"template_id": "some-template-id",
 			"_links": {
 				"self": {
@@ -132,6 +133,22 @@ var _ = Describe("UpdateHandler", func() {
 		}`))
 	})
 
+	It("updates whether the campaign type opts into threading", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"threading": true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("PUT", "/campaign_types/some-campaign-type-id", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(campaignTypesCollection.SetCall.Receives.CampaignType.Threading).To(BeTrue())
+		Expect(campaignTypesCollection.SetCall.Receives.CampaignType.Name).To(Equal("my old name"))
+	})
+
 	It("works when only the name field is updated", func() {
 		requestBody, err := json.Marshal(map[string]interface{}{
 			"name": "my new name",
@@ -167,6 +184,7 @@ var _ = Describe("UpdateHandler", func() {
 			"name": "my new name",
 			"description": "old description",
 			"critical": true,
+			"threading": false,
 			"template_id": "",
 			"_links": {
 				"self": {
@@ -209,6 +227,7 @@ var _ = Describe("UpdateHandler", func() {
 			"name": "my old name",
 			"description": "old description",
 			"critical": true,
+			"threading": false,
 			"template_id": "",
 			"_links": {
 				"self": {
@@ -218,6 +237,42 @@ var _ = Describe("UpdateHandler", func() {
 		}`))
 	})
 
+	It("updates the default headers", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"headers": map[string]string{
+				"Importance": "high",
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err := http.NewRequest("PUT", "/campaign_types/some-campaign-type-id", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(campaignTypesCollection.SetCall.Receives.CampaignType.Headers).To(Equal(map[string]string{
+			"Importance": "high",
+		}))
+	})
+
+	It("updates the from address and name", func() {
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"from_address": "billing@example.com",
+			"from_name":    "Billing",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err := http.NewRequest("PUT", "/campaign_types/some-campaign-type-id", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(campaignTypesCollection.SetCall.Receives.CampaignType.FromAddress).To(Equal("billing@example.com"))
+		Expect(campaignTypesCollection.SetCall.Receives.CampaignType.FromName).To(Equal("Billing"))
+	})
+
 	It("allows an update of critical from true to false even if the client does not have the critical_notifications.write scope", func() {
 		campaignTypesCollection.SetCall.Returns.CampaignType = collections.CampaignType{
 			ID:          "some-campaign-type-id",
@@ -252,6 +307,7 @@ var _ = Describe("UpdateHandler", func() {
 				"name": "update-campaign-type",
 				"description": "update-campaign-type-description",
 				"critical": false,
+				"threading": false,
 				"template_id": "some-template-id",
 				"_links": {
 					"self": {
@@ -290,6 +346,41 @@ var _ = Describe("UpdateHandler", func() {
 			}`))
 		})
 
+		It("returns a 422 if a header cannot be overridden", func() {
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"headers": map[string]string{
+					"To": "someone-else@example.com",
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = http.NewRequest("PUT", "/campaign_types/some-campaign-type-id", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["header \"To\" cannot be overridden"]
+			}`))
+		})
+
+		It("returns a 422 if the from address is not in an allowed sending domain", func() {
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"from_address": "billing@elsewhere.com",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = http.NewRequest("PUT", "/campaign_types/some-campaign-type-id", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["from_address \"billing@elsewhere.com\" is not in an allowed sending domain"]
+			}`))
+			Expect(campaignTypesCollection.SetCall.WasCalled).To(BeFalse())
+		})
+
 		It("returns a 422 if the description field is updated to an empty string", func() {
 			requestBody, err := json.Marshal(map[string]interface{}{
 				"description": "",
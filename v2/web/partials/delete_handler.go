@@ -0,0 +1,47 @@
+package partials
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionDeleter interface {
+	Delete(conn collections.ConnectionInterface, clientID, name string) error
+}
+
+type DeleteHandler struct {
+	partials collectionDeleter
+}
+
+func NewDeleteHandler(partials collectionDeleter) DeleteHandler {
+	return DeleteHandler{
+		partials: partials,
+	}
+}
+
+func (h DeleteHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	name := splitURL[len(splitURL)-1]
+
+	database := context.Get("database").(DatabaseInterface)
+	err := h.partials.Delete(database.Connection(), context.Get("client_id").(string), name)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		case collections.InUseError:
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
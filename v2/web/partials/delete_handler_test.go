@@ -0,0 +1,81 @@
+package partials_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteHandler", func() {
+	var (
+		handler            partials.DeleteHandler
+		partialsCollection *mocks.TemplatePartialsCollection
+		context            stack.Context
+		writer             *httptest.ResponseRecorder
+		request            *http.Request
+		conn               *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("client_id", "some-client-id")
+
+		partialsCollection = mocks.NewTemplatePartialsCollection()
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/partials/footer", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = partials.NewDeleteHandler(partialsCollection)
+	})
+
+	It("deletes the client's partial", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(partialsCollection.DeleteCall.Receives.Connection).To(Equal(conn))
+		Expect(partialsCollection.DeleteCall.Receives.ClientID).To(Equal("some-client-id"))
+		Expect(partialsCollection.DeleteCall.Receives.Name).To(Equal("footer"))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 404 when the partial does not exist", func() {
+			partialsCollection.DeleteCall.Returns.Error = collections.NotFoundError{Err: errors.New("Partial \"footer\" could not be found")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Partial \"footer\" could not be found" ] }`))
+		})
+
+		It("returns a 409 when templates still include the partial", func() {
+			partialsCollection.DeleteCall.Returns.Error = collections.InUseError{Err: errors.New("Partial \"footer\" is included by template \"welcome\" and cannot be deleted")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusConflict))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Partial \"footer\" is included by template \"welcome\" and cannot be deleted" ] }`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			partialsCollection.DeleteCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "BOOM!" ] }`))
+		})
+	})
+})
@@ -0,0 +1,46 @@
+package partials
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionGetter interface {
+	Get(conn collections.ConnectionInterface, clientID, name string) (collections.TemplatePartial, error)
+}
+
+type GetHandler struct {
+	partials collectionGetter
+}
+
+func NewGetHandler(partials collectionGetter) GetHandler {
+	return GetHandler{
+		partials: partials,
+	}
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	name := splitURL[len(splitURL)-1]
+
+	database := context.Get("database").(DatabaseInterface)
+	partial, err := h.partials.Get(database.Connection(), context.Get("client_id").(string), name)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewPartialResponse(partial))
+}
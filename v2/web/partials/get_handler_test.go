@@ -0,0 +1,90 @@
+package partials_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler            partials.GetHandler
+		partialsCollection *mocks.TemplatePartialsCollection
+		context            stack.Context
+		writer             *httptest.ResponseRecorder
+		request            *http.Request
+		conn               *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("client_id", "some-client-id")
+
+		partialsCollection = mocks.NewTemplatePartialsCollection()
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/partials/footer", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = partials.NewGetHandler(partialsCollection)
+	})
+
+	It("returns the client's partial", func() {
+		partialsCollection.GetCall.Returns.Partial = collections.TemplatePartial{
+			Name:     "footer",
+			ClientID: "some-client-id",
+			Text:     "Sent to {{.To}}",
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"name": "footer",
+			"text": "Sent to {{.To}}",
+			"html": "",
+			"_links": {
+				"self": {
+					"href": "/partials/footer"
+				}
+			}
+		}`))
+
+		Expect(partialsCollection.GetCall.Receives.Connection).To(Equal(conn))
+		Expect(partialsCollection.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+		Expect(partialsCollection.GetCall.Receives.Name).To(Equal("footer"))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 404 when the partial does not exist", func() {
+			partialsCollection.GetCall.Returns.Error = collections.NotFoundError{Err: errors.New("Partial \"footer\" could not be found")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Partial \"footer\" could not be found" ] }`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			partialsCollection.GetCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "BOOM!" ] }`))
+		})
+	})
+})
@@ -0,0 +1,99 @@
+package partials_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler            partials.ListHandler
+		partialsCollection *mocks.TemplatePartialsCollection
+		context            stack.Context
+		writer             *httptest.ResponseRecorder
+		request            *http.Request
+	)
+
+	BeforeEach(func() {
+		database := mocks.NewDatabase()
+
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("client_id", "some-client-id")
+
+		partialsCollection = mocks.NewTemplatePartialsCollection()
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/partials", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = partials.NewListHandler(partialsCollection)
+	})
+
+	It("returns the client's partials", func() {
+		partialsCollection.ListCall.Returns.Partials = []collections.TemplatePartial{
+			{
+				Name:     "footer",
+				ClientID: "some-client-id",
+				HTML:     "<p>footer</p>",
+			},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"partials": [
+				{
+					"name": "footer",
+					"text": "",
+					"html": "<p>footer</p>",
+					"_links": {
+						"self": {
+							"href": "/partials/footer"
+						}
+					}
+				}
+			],
+			"_links": {
+				"self": {
+					"href": "/partials"
+				}
+			}
+		}`))
+		Expect(partialsCollection.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+	})
+
+	It("returns an empty list when there are no partials", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"partials": [],
+			"_links": {
+				"self": {
+					"href": "/partials"
+				}
+			}
+		}`))
+	})
+
+	It("returns a 500 when the collection errors", func() {
+		partialsCollection.ListCall.Returns.Error = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+		Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+		Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "BOOM!" ] }`))
+	})
+})
@@ -0,0 +1,57 @@
+package partials
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+)
+
+type Link struct {
+	Href string `json:"href"`
+}
+
+type PartialResponseLinks struct {
+	Self Link `json:"self"`
+}
+
+type PartialResponse struct {
+	Name  string               `json:"name"`
+	Text  string               `json:"text"`
+	HTML  string               `json:"html"`
+	Links PartialResponseLinks `json:"_links"`
+}
+
+func NewPartialResponse(partial collections.TemplatePartial) PartialResponse {
+	return PartialResponse{
+		Name: partial.Name,
+		Text: partial.Text,
+		HTML: partial.HTML,
+		Links: PartialResponseLinks{
+			Self: Link{fmt.Sprintf("/partials/%s", partial.Name)},
+		},
+	}
+}
+
+type PartialsListResponse struct {
+	Partials []PartialResponse         `json:"partials"`
+	Links    PartialsListResponseLinks `json:"_links"`
+}
+
+type PartialsListResponseLinks struct {
+	Self Link `json:"self"`
+}
+
+func NewPartialsListResponse(partialList []collections.TemplatePartial) PartialsListResponse {
+	partialResponseList := []PartialResponse{}
+
+	for _, partial := range partialList {
+		partialResponseList = append(partialResponseList, NewPartialResponse(partial))
+	}
+
+	return PartialsListResponse{
+		Partials: partialResponseList,
+		Links: PartialsListResponseLinks{
+			Self: Link{"/partials"},
+		},
+	}
+}
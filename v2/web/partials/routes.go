@@ -0,0 +1,24 @@
+package partials
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type Routes struct {
+	RequestLogging             stack.Middleware
+	Authenticator              stack.Middleware
+	DatabaseAllocator          stack.Middleware
+	TemplatePartialsCollection collections.TemplatePartialsCollection
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("PUT", "/partials/{name}", NewSetHandler(r.TemplatePartialsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/partials", NewListHandler(r.TemplatePartialsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/partials/{name}", NewGetHandler(r.TemplatePartialsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/partials/{name}", NewDeleteHandler(r.TemplatePartialsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+}
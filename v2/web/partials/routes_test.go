@@ -0,0 +1,112 @@
+package partials_test
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
+	"github.com/cloudfoundry-incubator/notifications/web"
+	"github.com/pivotal-golang/lager"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Routes", func() {
+	var (
+		logging     middleware.RequestLogging
+		auth        middleware.Authenticator
+		dbAllocator middleware.DatabaseAllocator
+		muxer       web.Muxer
+	)
+
+	BeforeEach(func() {
+		logging = middleware.NewRequestLogging(lager.NewLogger("log-prefix"), mocks.NewClock())
+		auth = middleware.NewAuthenticator(&mocks.TokenValidator{}, "notifications.write")
+		dbAllocator = middleware.NewDatabaseAllocator(&sql.DB{}, false)
+
+		muxer = web.NewMuxer()
+		partials.Routes{
+			RequestLogging:             logging,
+			Authenticator:              auth,
+			DatabaseAllocator:          dbAllocator,
+			TemplatePartialsCollection: collections.TemplatePartialsCollection{},
+		}.Register(muxer)
+	})
+
+	It("routes PUT /partials/{name}", func() {
+		request, err := http.NewRequest("PUT", "/partials/footer", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(partials.SetHandler{}))
+		Expect(s.Middleware).To(HaveLen(3))
+
+		requestLogging := s.Middleware[0].(middleware.RequestLogging)
+		Expect(requestLogging).To(Equal(logging))
+
+		authenticator := s.Middleware[1].(middleware.Authenticator)
+		Expect(authenticator).To(Equal(auth))
+
+		databaseAllocator := s.Middleware[2].(middleware.DatabaseAllocator)
+		Expect(databaseAllocator).To(Equal(dbAllocator))
+	})
+
+	It("routes GET /partials", func() {
+		request, err := http.NewRequest("GET", "/partials", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(partials.ListHandler{}))
+		Expect(s.Middleware).To(HaveLen(3))
+
+		requestLogging := s.Middleware[0].(middleware.RequestLogging)
+		Expect(requestLogging).To(Equal(logging))
+
+		authenticator := s.Middleware[1].(middleware.Authenticator)
+		Expect(authenticator).To(Equal(auth))
+
+		databaseAllocator := s.Middleware[2].(middleware.DatabaseAllocator)
+		Expect(databaseAllocator).To(Equal(dbAllocator))
+	})
+
+	It("routes GET /partials/{name}", func() {
+		request, err := http.NewRequest("GET", "/partials/footer", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(partials.GetHandler{}))
+		Expect(s.Middleware).To(HaveLen(3))
+
+		requestLogging := s.Middleware[0].(middleware.RequestLogging)
+		Expect(requestLogging).To(Equal(logging))
+
+		authenticator := s.Middleware[1].(middleware.Authenticator)
+		Expect(authenticator).To(Equal(auth))
+
+		databaseAllocator := s.Middleware[2].(middleware.DatabaseAllocator)
+		Expect(databaseAllocator).To(Equal(dbAllocator))
+	})
+
+	It("routes DELETE /partials/{name}", func() {
+		request, err := http.NewRequest("DELETE", "/partials/footer", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(partials.DeleteHandler{}))
+		Expect(s.Middleware).To(HaveLen(3))
+
+		requestLogging := s.Middleware[0].(middleware.RequestLogging)
+		Expect(requestLogging).To(Equal(logging))
+
+		authenticator := s.Middleware[1].(middleware.Authenticator)
+		Expect(authenticator).To(Equal(auth))
+
+		databaseAllocator := s.Middleware[2].(middleware.DatabaseAllocator)
+		Expect(databaseAllocator).To(Equal(dbAllocator))
+	})
+})
@@ -0,0 +1,98 @@
+package partials
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+var partialName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+type collectionSetter interface {
+	Set(conn collections.ConnectionInterface, partial collections.TemplatePartial) (collections.TemplatePartial, error)
+}
+
+type SetHandler struct {
+	partials collectionSetter
+}
+
+func NewSetHandler(partials collectionSetter) SetHandler {
+	return SetHandler{
+		partials: partials,
+	}
+}
+
+func (h SetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	name := splitURL[len(splitURL)-1]
+
+	var setRequest struct {
+		Text string `json:"text"`
+		HTML string `json:"html"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&setRequest)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "errors": [ "invalid json body" ] }`))
+		return
+	}
+
+	if !partialName.MatchString(name) {
+		w.WriteHeader(422)
+		w.Write([]byte(`{ "errors": [ "partial name may only contain letters, digits, \"-\" and \"_\"" ] }`))
+		return
+	}
+
+	if name == common.LayoutContent {
+		w.WriteHeader(422)
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, fmt.Sprintf("partial name %q is reserved for the content of a layout", name))
+		return
+	}
+
+	if setRequest.Text == "" && setRequest.HTML == "" {
+		w.WriteHeader(422)
+		w.Write([]byte(`{ "errors": [ "missing either partial text or html" ] }`))
+		return
+	}
+
+	err = common.ValidatePartial(common.Partial{
+		Name: name,
+		Text: setRequest.Text,
+		HTML: setRequest.HTML,
+	})
+	if err != nil {
+		w.WriteHeader(422)
+		json.NewEncoder(w).Encode(map[string][]string{
+			"errors": err.(common.TemplateValidationError).Messages(),
+		})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	partial, err := h.partials.Set(database.Connection(), collections.TemplatePartial{
+		Name:     name,
+		ClientID: context.Get("client_id").(string),
+		Text:     setRequest.Text,
+		HTML:     setRequest.HTML,
+	})
+	if err != nil {
+		switch err.(type) {
+		case collections.ValidationError:
+			w.WriteHeader(422)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewPartialResponse(partial))
+}
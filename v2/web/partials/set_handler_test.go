@@ -0,0 +1,150 @@
+package partials_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SetHandler", func() {
+	var (
+		handler            partials.SetHandler
+		partialsCollection *mocks.TemplatePartialsCollection
+		context            stack.Context
+		writer             *httptest.ResponseRecorder
+		request            *http.Request
+		conn               *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("client_id", "some-client-id")
+
+		partialsCollection = mocks.NewTemplatePartialsCollection()
+		partialsCollection.SetCall.Returns.Partial = collections.TemplatePartial{
+			Name:     "footer",
+			ClientID: "some-client-id",
+			Text:     "Sent to {{.To}}",
+			HTML:     "<p>Sent to {{.To}}</p>",
+		}
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("PUT", "/partials/footer", bytes.NewBufferString(`{
+			"text": "Sent to {{.To}}",
+			"html": "<p>Sent to {{.To}}</p>"
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = partials.NewSetHandler(partialsCollection)
+	})
+
+	It("sets the partial for the client", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(partialsCollection.SetCall.Receives.Connection).To(Equal(conn))
+		Expect(partialsCollection.SetCall.Receives.Partial).To(Equal(collections.TemplatePartial{
+			Name:     "footer",
+			ClientID: "some-client-id",
+			Text:     "Sent to {{.To}}",
+			HTML:     "<p>Sent to {{.To}}</p>",
+		}))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"name": "footer",
+			"text": "Sent to {{.To}}",
+			"html": "<p>Sent to {{.To}}</p>",
+			"_links": {
+				"self": {
+					"href": "/partials/footer"
+				}
+			}
+		}`))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 400 when the JSON cannot be unmarshalled", func() {
+			var err error
+			request, err = http.NewRequest("PUT", "/partials/footer", strings.NewReader("%%%"))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusBadRequest))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "invalid json body" ] }`))
+		})
+
+		It("returns a 422 when the name contains unsupported characters", func() {
+			var err error
+			request, err = http.NewRequest("PUT", "/partials/foot%22er", strings.NewReader(`{"text": "footer"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "partial name may only contain letters, digits, \"-\" and \"_\"" ] }`))
+		})
+
+		It("returns a 422 when the name is reserved for layout content", func() {
+			var err error
+			request, err = http.NewRequest("PUT", "/partials/content", strings.NewReader(`{"text": "footer"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "partial name \"content\" is reserved for the content of a layout" ] }`))
+		})
+
+		It("returns a 422 when the partial has no text or html", func() {
+			var err error
+			request, err = http.NewRequest("PUT", "/partials/footer", strings.NewReader(`{}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "missing either partial text or html" ] }`))
+		})
+
+		It("returns a 422 when the partial does not compile", func() {
+			var err error
+			request, err = http.NewRequest("PUT", "/partials/footer", strings.NewReader(`{"text": "{{.Nope}}"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(ContainSubstring("can't evaluate field Nope"))
+			Expect(partialsCollection.SetCall.Receives.Partial).To(Equal(collections.TemplatePartial{}))
+		})
+
+		It("returns a 422 when the partial creates a circular reference", func() {
+			partialsCollection.SetCall.Returns.Error = collections.ValidationError{Err: errors.New(`Partial "footer" creates a circular reference: footer -> footer`)}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Partial \"footer\" creates a circular reference: footer -> footer" ] }`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			partialsCollection.SetCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "BOOM!" ] }`))
+		})
+	})
+})
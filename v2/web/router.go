@@ -8,21 +8,27 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/metrics"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/sanitize"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/util"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/cloudfoundry-incubator/notifications/v2/models"
 	"github.com/cloudfoundry-incubator/notifications/v2/queue"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/bundles"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/campaigns"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/campaigntypes"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/info"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/partials"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/root"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/senders"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/transports"
 	"github.com/cloudfoundry-incubator/notifications/v2/web/unsubscribers"
 	"github.com/gorilla/mux"
 	"github.com/pivotal-cf-experimental/warrant"
+	"github.com/pivotal-golang/conceal"
 	"github.com/pivotal-golang/lager"
 	"github.com/ryanmoran/stack"
 )
@@ -53,6 +59,13 @@ type Config struct {
 	UAAClientID       string
 	UAAClientSecret   string
 	CCHost            string
+	EncryptionKey     []byte
+	SenderDomains     []string
+	Sender            string
+	Domain            string
+
+	HTMLPolicy         sanitize.Policy
+	HTMLClientPolicies map[string]sanitize.Policy
 }
 
 func NewRouter(mx muxer, config Config) http.Handler {
@@ -84,13 +97,29 @@ func NewRouter(mx muxer, config Config) http.Handler {
 	campaignsRepository := models.NewCampaignsRepository(guidGenerator.Generate, clock)
 	messagesRepository := models.NewMessagesRepository(clock, guidGenerator.Generate)
 	unsubscribersRepository := models.NewUnsubscribersRepository(guidGenerator.Generate)
-
-	sendersCollection := collections.NewSendersCollection(sendersRepository, campaignTypesRepository)
-	templatesCollection := collections.NewTemplatesCollection(templatesRepository)
+	attachmentsRepository := models.NewAttachmentsRepository(guidGenerator.Generate, clock)
+	transportsRepository := models.NewTransportsRepository(clock)
+	transportGrantsRepository := models.NewTransportGrantsRepository(clock)
+	templateVersionsRepository := models.NewTemplateVersionsRepository(guidGenerator.Generate, clock)
+	templatePartialsRepository := models.NewTemplatePartialsRepository(guidGenerator.Generate, clock)
+	clientTemplatesRepository := models.NewClientTemplatesRepository(clock)
+
+	sendersCollection := collections.NewSendersCollection(sendersRepository, campaignTypesRepository, transportsRepository, transportGrantsRepository)
+	templatesCollection := collections.NewTemplatesCollection(templatesRepository, templateVersionsRepository, campaignTypesRepository, campaignsRepository, clientTemplatesRepository)
+	templatePartialsCollection := collections.NewTemplatePartialsCollection(templatePartialsRepository, templatesRepository)
+	clientTemplatesCollection := collections.NewClientTemplatesCollection(clientTemplatesRepository, templatesRepository)
 	campaignTypesCollection := collections.NewCampaignTypesCollection(campaignTypesRepository, sendersRepository, templatesRepository)
-	campaignsCollection := collections.NewCampaignsCollection(campaignEnqueuer, campaignsRepository, campaignTypesRepository, templatesRepository, sendersRepository)
+	campaignsCollection := collections.NewCampaignsCollection(campaignEnqueuer, campaignsRepository, campaignTypesRepository, clientTemplatesRepository, templatesRepository, sendersRepository, attachmentsRepository)
 	campaignStatusesCollection := collections.NewCampaignStatusesCollection(campaignsRepository, sendersRepository, messagesRepository)
+	bundlesCollection := collections.NewBundlesCollection(templatesCollection, templatePartialsCollection, sendersCollection, campaignTypesCollection)
+	cloak, err := conceal.NewCloak(config.EncryptionKey)
+	if err != nil {
+		panic(err)
+	}
+
 	unsubscribersCollection := collections.NewUnsubscribersCollection(unsubscribersRepository, campaignTypesRepository, userFinder)
+	transportsCollection := collections.NewTransportsCollection(transportsRepository, transportGrantsRepository, cloak)
+	previewer := common.NewPreviewer(cloak, sanitize.NewSanitizer(config.HTMLPolicy, config.HTMLClientPolicies), config.Sender, config.Domain)
 
 	root.Routes{
 		RequestLogging: requestLogging,
@@ -106,6 +135,7 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		Authenticator:     notificationsWriteAuthenticator,
 		DatabaseAllocator: databaseAllocator,
 		SendersCollection: sendersCollection,
+		SenderDomains:     config.SenderDomains,
 	}.Register(mx)
 
 	campaigntypes.Routes{
@@ -113,14 +143,40 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		Authenticator:           notificationsWriteAuthenticator,
 		DatabaseAllocator:       databaseAllocator,
 		CampaignTypesCollection: campaignTypesCollection,
+		SenderDomains:           config.SenderDomains,
+	}.Register(mx)
+
+	transports.Routes{
+		RequestLogging:       requestLogging,
+		Authenticator:        notificationsAdminAuthenticator,
+		DatabaseAllocator:    databaseAllocator,
+		TransportsCollection: transportsCollection,
 	}.Register(mx)
 
 	templates.Routes{
-		RequestLogging:      requestLogging,
-		WriteAuthenticator:  notificationsWriteAuthenticator,
-		AdminAuthenticator:  notificationsAdminAuthenticator,
-		DatabaseAllocator:   databaseAllocator,
-		TemplatesCollection: templatesCollection,
+		RequestLogging:             requestLogging,
+		WriteAuthenticator:         notificationsWriteAuthenticator,
+		AdminAuthenticator:         notificationsAdminAuthenticator,
+		DatabaseAllocator:          databaseAllocator,
+		TemplatesCollection:        templatesCollection,
+		TemplatePartialsCollection: templatePartialsCollection,
+		ClientTemplatesCollection:  clientTemplatesCollection,
+		Previewer:                  previewer,
+	}.Register(mx)
+
+	partials.Routes{
+		RequestLogging:             requestLogging,
+		Authenticator:              notificationsWriteAuthenticator,
+		DatabaseAllocator:          databaseAllocator,
+		TemplatePartialsCollection: templatePartialsCollection,
+	}.Register(mx)
+
+	bundles.Routes{
+		RequestLogging:    requestLogging,
+		Authenticator:     notificationsWriteAuthenticator,
+		DatabaseAllocator: databaseAllocator,
+		BundlesCollection: bundlesCollection,
+		SenderDomains:     config.SenderDomains,
 	}.Register(mx)
 
 	campaigns.Routes{
@@ -137,6 +193,7 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		Authenticator:           unsubscribesAuthenticator,
 		DatabaseAllocator:       databaseAllocator,
 		UnsubscribersCollection: unsubscribersCollection,
+		Cloak:                   cloak,
 	}.Register(mx)
 
 	return mx
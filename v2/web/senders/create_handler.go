@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/ryanmoran/stack"
 )
@@ -14,18 +15,23 @@ type collectionSetter interface {
 }
 
 type CreateHandler struct {
-	senders collectionSetter
+	senders       collectionSetter
+	senderDomains []string
 }
 
-func NewCreateHandler(senders collectionSetter) CreateHandler {
+func NewCreateHandler(senders collectionSetter, senderDomains []string) CreateHandler {
 	return CreateHandler{
-		senders: senders,
+		senders:       senders,
+		senderDomains: senderDomains,
 	}
 }
 
 func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
 	var createRequest struct {
-		Name string `json:"name"`
+		Name        string `json:"name"`
+		FromAddress string `json:"from_address"`
+		FromName    string `json:"from_name"`
+		Transport   string `json:"transport"`
 	}
 
 	err := json.NewDecoder(req.Body).Decode(&createRequest)
@@ -43,6 +49,13 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
+	err = mail.ValidateFrom(createRequest.FromName, createRequest.FromAddress, h.senderDomains)
+	if err != nil {
+		w.WriteHeader(422)
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
 	clientID := context.Get("client_id")
 	if clientID == "" {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -51,12 +64,20 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	}
 
 	sender, err := h.senders.Set(database.Connection(), collections.Sender{
-		Name:     createRequest.Name,
-		ClientID: context.Get("client_id").(string),
+		Name:        createRequest.Name,
+		ClientID:    context.Get("client_id").(string),
+		FromAddress: createRequest.FromAddress,
+		FromName:    createRequest.FromName,
+		Transport:   createRequest.Transport,
 	})
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprintf(w, `{ "errors": [ "%s" ] }`, err)
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
 		return
 	}
 
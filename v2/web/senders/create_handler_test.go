@@ -53,7 +53,7 @@ var _ = Describe("CreateHandler", func() {
 		request, err = http.NewRequest("POST", "/senders", bytes.NewBuffer(requestBody))
 		Expect(err).NotTo(HaveOccurred())
 
-		handler = senders.NewCreateHandler(sendersCollection)
+		handler = senders.NewCreateHandler(sendersCollection, []string{"example.com"})
 	})
 
 	It("creates a sender", func() {
@@ -83,7 +83,74 @@ var _ = Describe("CreateHandler", func() {
 		}`))
 	})
 
+	It("creates a sender with a from address and name", func() {
+		sendersCollection.SetCall.Returns.Sender = collections.Sender{
+			ID:          "some-sender-id",
+			Name:        "some-sender",
+			FromAddress: "billing@example.com",
+			FromName:    "Billing",
+		}
+
+		requestBody, err := json.Marshal(map[string]string{
+			"name":         "some-sender",
+			"from_address": "billing@example.com",
+			"from_name":    "Billing",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/senders", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(sendersCollection.SetCall.Receives.Sender).To(Equal(collections.Sender{
+			Name:        "some-sender",
+			ClientID:    "some-client-id",
+			FromAddress: "billing@example.com",
+			FromName:    "Billing",
+		}))
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-sender-id",
+			"name": "some-sender",
+			"from_address": "billing@example.com",
+			"from_name": "Billing",
+			"_links": {
+				"self": {
+					"href": "/senders/some-sender-id"
+				},
+				"campaign_types": {
+					"href": "/senders/some-sender-id/campaign_types"
+				},
+				"campaigns": {
+					"href": "/senders/some-sender-id/campaigns"
+				}
+			}
+		}`))
+	})
+
 	Context("failure cases", func() {
+		It("returns a 422 when the from address is not in an allowed sending domain", func() {
+			requestBody, err := json.Marshal(map[string]string{
+				"name":         "some-sender",
+				"from_address": "billing@elsewhere.com",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = http.NewRequest("POST", "/senders", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": [
+					"from_address \"billing@elsewhere.com\" is not in an allowed sending domain"
+				]
+			}`))
+			Expect(sendersCollection.SetCall.Receives.Sender).To(Equal(collections.Sender{}))
+		})
+
 		It("returns a 400 when the JSON cannot be unmarshalled", func() {
 			var err error
 			request, err = http.NewRequest("POST", "/senders", strings.NewReader("%%%"))
@@ -124,6 +191,28 @@ var _ = Describe("CreateHandler", func() {
 			}`))
 		})
 
+		It("returns a 404 when the transport does not exist", func() {
+			sendersCollection.SetCall.Returns.Error = collections.NotFoundError{Err: errors.New("Transport \"missing-relay\" could not be found")}
+
+			requestBody, err := json.Marshal(map[string]string{
+				"name":      "some-sender",
+				"transport": "missing-relay",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = http.NewRequest("POST", "/senders", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(sendersCollection.SetCall.Receives.Sender.Transport).To(Equal("missing-relay"))
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": [
+					"Transport \"missing-relay\" could not be found"
+				]
+			}`))
+		})
+
 		It("returns a 500 when the collection indicates a system error", func() {
 			sendersCollection.SetCall.Returns.Error = errors.New("BOOM!")
 
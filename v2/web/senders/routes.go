@@ -14,11 +14,12 @@ type Routes struct {
 	Authenticator     stack.Middleware
 	DatabaseAllocator stack.Middleware
 	SendersCollection collections.SendersCollection
+	SenderDomains     []string
 }
 
 func (r Routes) Register(m muxer) {
-	m.Handle("POST", "/senders", NewCreateHandler(r.SendersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
-	m.Handle("PUT", "/senders/{sender_id:[^/]*}", NewUpdateHandler(r.SendersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/senders", NewCreateHandler(r.SendersCollection, r.SenderDomains), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/senders/{sender_id:[^/]*}", NewUpdateHandler(r.SendersCollection, r.SenderDomains), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/senders", NewListHandler(r.SendersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/senders/{sender_id:[^/]*}", NewGetHandler(r.SendersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
 	m.Handle("DELETE", "/senders/{sender_id:[^/]*}", NewDeleteHandler(r.SendersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/ryanmoran/stack"
 )
@@ -16,12 +17,14 @@ type collectionSetGetter interface {
 }
 
 type UpdateHandler struct {
-	senders collectionSetGetter
+	senders       collectionSetGetter
+	senderDomains []string
 }
 
-func NewUpdateHandler(senders collectionSetGetter) UpdateHandler {
+func NewUpdateHandler(senders collectionSetGetter, senderDomains []string) UpdateHandler {
 	return UpdateHandler{
-		senders: senders,
+		senders:       senders,
+		senderDomains: senderDomains,
 	}
 }
 
@@ -30,7 +33,10 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	senderID := splitURL[len(splitURL)-1]
 
 	var updateRequest struct {
-		Name string `json:"name"`
+		Name        string  `json:"name"`
+		FromAddress *string `json:"from_address"`
+		FromName    *string `json:"from_name"`
+		Transport   *string `json:"transport"`
 	}
 
 	err := json.NewDecoder(req.Body).Decode(&updateRequest)
@@ -43,7 +49,7 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	database := context.Get("database").(DatabaseInterface)
 	clientID := context.Get("client_id").(string)
 
-	_, err = h.senders.Get(database.Connection(), senderID, clientID)
+	existingSender, err := h.senders.Get(database.Connection(), senderID, clientID)
 	if err != nil {
 		switch err.(type) {
 		case collections.NotFoundError:
@@ -55,15 +61,39 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
+	if updateRequest.FromAddress != nil {
+		existingSender.FromAddress = *updateRequest.FromAddress
+	}
+
+	if updateRequest.FromName != nil {
+		existingSender.FromName = *updateRequest.FromName
+	}
+
+	if updateRequest.Transport != nil {
+		existingSender.Transport = *updateRequest.Transport
+	}
+
+	err = mail.ValidateFrom(existingSender.FromName, existingSender.FromAddress, h.senderDomains)
+	if err != nil {
+		w.WriteHeader(422)
+		w.Write([]byte(fmt.Sprintf(`{ "errors": [ %q ]}`, err)))
+		return
+	}
+
 	sender, err := h.senders.Set(database.Connection(), collections.Sender{
-		ID:       senderID,
-		Name:     updateRequest.Name,
-		ClientID: clientID,
+		ID:          senderID,
+		Name:        updateRequest.Name,
+		ClientID:    clientID,
+		FromAddress: existingSender.FromAddress,
+		FromName:    existingSender.FromName,
+		Transport:   existingSender.Transport,
 	})
 	if err != nil {
 		switch err.(type) {
 		case collections.DuplicateRecordError:
 			w.WriteHeader(422)
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -43,7 +43,7 @@ var _ = Describe("UpdateHandler", func() {
 
 		writer = httptest.NewRecorder()
 
-		handler = senders.NewUpdateHandler(sendersCollection)
+		handler = senders.NewUpdateHandler(sendersCollection, []string{"example.com"})
 	})
 
 	It("updates a sender", func() {
@@ -82,7 +82,72 @@ var _ = Describe("UpdateHandler", func() {
 		}`))
 	})
 
+	It("updates the from address and name, keeping values that are not supplied", func() {
+		sendersCollection.GetCall.Returns.Sender = collections.Sender{
+			ID:          "some-sender-id",
+			Name:        "some-sender",
+			ClientID:    "some-client-id",
+			FromAddress: "billing@example.com",
+			FromName:    "Billing",
+		}
+
+		requestBody, err := json.Marshal(map[string]string{
+			"name":      "changed-sender",
+			"from_name": "Accounts",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err := http.NewRequest("PUT", "/senders/some-sender-id", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(sendersCollection.SetCall.Receives.Sender).To(Equal(collections.Sender{
+			ID:          "some-sender-id",
+			Name:        "changed-sender",
+			ClientID:    "some-client-id",
+			FromAddress: "billing@example.com",
+			FromName:    "Accounts",
+		}))
+	})
+
+	It("binds the sender to a transport", func() {
+		requestBody, err := json.Marshal(map[string]string{
+			"name":      "changed-sender",
+			"transport": "corporate-relay",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err := http.NewRequest("PUT", "/senders/some-sender-id", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(sendersCollection.SetCall.Receives.Sender.Transport).To(Equal("corporate-relay"))
+	})
+
 	Context("failure cases", func() {
+		Context("when the from address is not in an allowed sending domain", func() {
+			It("returns a 422 with an error message", func() {
+				requestBody, err := json.Marshal(map[string]string{
+					"name":         "changed-sender",
+					"from_address": "billing@elsewhere.com",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err := http.NewRequest("PUT", "/senders/some-sender-id", bytes.NewBuffer(requestBody))
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["from_address \"billing@elsewhere.com\" is not in an allowed sending domain"]}`))
+				Expect(sendersCollection.SetCall.Receives.Sender).To(Equal(collections.Sender{}))
+			})
+		})
+
 		Context("when the sender cannot be got", func() {
 			It("returns a 404 and a not found error", func() {
 				sendersCollection.GetCall.Returns.Error = collections.NotFoundError{errors.New("Sender \"some-missing-sender-id\" does not exist.")}
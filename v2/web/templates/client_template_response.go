@@ -0,0 +1,32 @@
+package templates
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+)
+
+type ClientTemplateResponseLinks struct {
+	Self     Link  `json:"self"`
+	Template *Link `json:"template,omitempty"`
+}
+
+type ClientTemplateResponse struct {
+	TemplateID *string                     `json:"template_id"`
+	Links      ClientTemplateResponseLinks `json:"_links"`
+}
+
+func NewClientTemplateResponse(clientTemplate collections.ClientTemplate) ClientTemplateResponse {
+	response := ClientTemplateResponse{
+		Links: ClientTemplateResponseLinks{
+			Self: Link{"/templates/client_default"},
+		},
+	}
+
+	if clientTemplate.TemplateID != "" {
+		response.TemplateID = &clientTemplate.TemplateID
+		response.Links.Template = &Link{fmt.Sprintf("/templates/%s", clientTemplate.TemplateID)}
+	}
+
+	return response
+}
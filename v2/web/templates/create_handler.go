@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/ryanmoran/stack"
 )
@@ -25,12 +26,17 @@ func NewCreateHandler(templates collectionSetter) CreateHandler {
 
 func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
 	var createRequest struct {
-		Name     string           `json:"name"`
-		HTML     string           `json:"html"`
-		Text     string           `json:"text"`
-		Subject  string           `json:"subject"`
-		Metadata *json.RawMessage `json:"metadata"`
-		ClientID string           `json:"client_id"`
+		Name          string           `json:"name"`
+		HTML          string           `json:"html"`
+		Text          string           `json:"text"`
+		Subject       string           `json:"subject"`
+		Metadata      *json.RawMessage `json:"metadata"`
+		Localizations *json.RawMessage `json:"localizations"`
+		LayoutID      string           `json:"layout_id"`
+		ClientID      string           `json:"client_id"`
+
+		DisableAutoText    bool `json:"disable_auto_text"`
+		DisableCSSInlining bool `json:"disable_css_inlining"`
 	}
 
 	err := json.NewDecoder(req.Body).Decode(&createRequest)
@@ -63,6 +69,31 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		createRequest.Subject = "{{.Subject}}"
 	}
 
+	if createRequest.Localizations == nil {
+		localizations := json.RawMessage("{}")
+		createRequest.Localizations = &localizations
+	}
+
+	localizations, err := decodeLocalizations(string(*createRequest.Localizations))
+	if err != nil {
+		w.WriteHeader(422)
+		w.Write([]byte(`{ "errors": [ "localizations must map each locale to a subject, text and html" ] }`))
+		return
+	}
+
+	warnings, err := common.ValidateLocalizedTemplates(common.Templates{
+		Subject: createRequest.Subject,
+		Text:    createRequest.Text,
+		HTML:    createRequest.HTML,
+	}, localizations, false)
+	if err != nil {
+		w.WriteHeader(422)
+		json.NewEncoder(w).Encode(map[string][]string{
+			"errors": err.(common.TemplateValidationError).Messages(),
+		})
+		return
+	}
+
 	if createRequest.Metadata == nil {
 		metadata := json.RawMessage("{}")
 		createRequest.Metadata = &metadata
@@ -70,18 +101,31 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 
 	database := context.Get("database").(DatabaseInterface)
 
-	template, err := h.templates.Set(database.Connection(), collections.Template{
-		Name:     createRequest.Name,
-		HTML:     createRequest.HTML,
-		Text:     createRequest.Text,
-		Subject:  createRequest.Subject,
-		Metadata: string(*createRequest.Metadata),
-		ClientID: clientID,
+	// A template and its first version are stored together.
+	transaction := database.Connection().Transaction()
+	transaction.Begin()
+
+	template, err := h.templates.Set(transaction, collections.Template{
+		Name:          createRequest.Name,
+		HTML:          createRequest.HTML,
+		Text:          createRequest.Text,
+		Subject:       createRequest.Subject,
+		Metadata:      string(*createRequest.Metadata),
+		Localizations: string(*createRequest.Localizations),
+		LayoutID:      createRequest.LayoutID,
+		ClientID:      clientID,
+
+		DisableAutoText:    createRequest.DisableAutoText,
+		DisableCSSInlining: createRequest.DisableCSSInlining,
 	})
 	if err != nil {
+		transaction.Rollback()
+
 		switch err.(type) {
 		case collections.DuplicateRecordError:
 			w.WriteHeader(http.StatusConflict)
+		case collections.ValidationError:
+			w.WriteHeader(422)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -90,7 +134,17 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
+	err = transaction.Commit()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"errors": [ %q ]}`, err)
+		return
+	}
+
 	w.WriteHeader(http.StatusCreated)
 
-	json.NewEncoder(w).Encode(NewTemplateResponse(template))
+	response := NewTemplateResponse(template)
+	response.Warnings = warnings
+
+	json.NewEncoder(w).Encode(response)
 }
@@ -25,13 +25,19 @@ var _ = Describe("CreateHandler", func() {
 		writer              *httptest.ResponseRecorder
 		request             *http.Request
 		database            *mocks.Database
+		transaction         *mocks.Transaction
 	)
 
 	BeforeEach(func() {
 		context = stack.NewContext()
 		context.Set("client_id", "some-client-id")
 
+		transaction = mocks.NewTransaction()
+		conn := mocks.NewConnection()
+		conn.TransactionCall.Returns.Transaction = transaction
+
 		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
 		context.Set("database", database)
 
 		writer = httptest.NewRecorder()
@@ -74,12 +80,19 @@ var _ = Describe("CreateHandler", func() {
 			"metadata": {
 				"template": "metadata"
 			},
+			"warnings": [ "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe" ],
 			"_links": {
 				"self": {
 					"href": "/templates/some-template-id"
 				}
 			}
 		}`))
+
+		Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(transaction))
+
+		Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+		Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
 	})
 
 	It("creates a template with only name and text", func() {
@@ -112,6 +125,7 @@ var _ = Describe("CreateHandler", func() {
 			"html": "",
 			"subject": "{{.Subject}}",
 			"metadata": {},
+			"warnings": [ "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe" ],
 			"_links": {
 				"self": {
 					"href": "/templates/some-template-id"
@@ -150,6 +164,7 @@ var _ = Describe("CreateHandler", func() {
 			"html": "template html",
 			"subject": "{{.Subject}}",
 			"metadata": {},
+			"warnings": [ "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe" ],
 			"_links": {
 				"self": {
 					"href": "/templates/some-template-id"
@@ -188,6 +203,7 @@ var _ = Describe("CreateHandler", func() {
 			"html": "template html",
 			"subject": "{{.Subject}}",
 			"metadata": {},
+			"warnings": [ "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe" ],
 			"_links": {
 				"self": {
 					"href": "/templates/some-template-id"
@@ -196,6 +212,102 @@ var _ = Describe("CreateHandler", func() {
 		}`))
 	})
 
+	It("creates a template wrapped in a layout", func() {
+		templatesCollection.SetCall.Returns.Template = collections.Template{
+			ID:       "some-template-id",
+			Name:     "an interesting template",
+			Text:     "template text",
+			Subject:  "{{.Subject}}",
+			Metadata: "{}",
+			LayoutID: "some-layout-id",
+		}
+
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"name":      "an interesting template",
+			"text":      "template text",
+			"layout_id": "some-layout-id",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/templates", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(templatesCollection.SetCall.Receives.Template.LayoutID).To(Equal("some-layout-id"))
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-template-id",
+			"name": "an interesting template",
+			"text": "template text",
+			"html": "",
+			"subject": "{{.Subject}}",
+			"metadata": {},
+			"layout_id": "some-layout-id",
+			"warnings": [ "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe" ],
+			"_links": {
+				"self": {
+					"href": "/templates/some-template-id"
+				}
+			}
+		}`))
+	})
+
+	It("creates a template that disables automatic text and css inlining", func() {
+		templatesCollection.SetCall.Returns.Template = collections.Template{
+			ID:                 "some-template-id",
+			Name:               "an interesting template",
+			HTML:               "<p>template html</p>",
+			Subject:            "{{.Subject}}",
+			Metadata:           "{}",
+			DisableAutoText:    true,
+			DisableCSSInlining: true,
+		}
+
+		requestBody, err := json.Marshal(map[string]interface{}{
+			"name":                 "an interesting template",
+			"html":                 "<p>template html</p>",
+			"disable_auto_text":    true,
+			"disable_css_inlining": true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("POST", "/templates", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(templatesCollection.SetCall.Receives.Template.DisableAutoText).To(BeTrue())
+		Expect(templatesCollection.SetCall.Receives.Template.DisableCSSInlining).To(BeTrue())
+
+		var response map[string]interface{}
+		err = json.Unmarshal(writer.Body.Bytes(), &response)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response["disable_auto_text"]).To(BeTrue())
+		Expect(response["disable_css_inlining"]).To(BeTrue())
+	})
+
+	It("does not warn when the template includes an unsubscribe link", func() {
+		var err error
+		request, err = http.NewRequest("POST", "/templates", strings.NewReader(`{
+			"name": "a cool template",
+			"html": "<a href=\"{{.ListUnsubscribeURL}}\">unsubscribe</a>"
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		templatesCollection.SetCall.Returns.Template = collections.Template{
+			ID:       "some-template-id",
+			Metadata: "{}",
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusCreated))
+		Expect(writer.Body.String()).NotTo(ContainSubstring("warnings"))
+	})
+
 	Context("failure cases", func() {
 		It("returns a 400 when the JSON cannot be unmarshalled", func() {
 			var err error
@@ -293,6 +405,31 @@ var _ = Describe("CreateHandler", func() {
 			}`))
 		})
 
+		It("returns a 422 listing every template part that does not compile", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates", strings.NewReader(`{
+				"name": "a cool template",
+				"subject": "{{.Subject",
+				"text": "hello\n{{.Txet}}"
+			}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+
+			var response struct {
+				Errors []string
+			}
+			err = json.Unmarshal(writer.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(response.Errors).To(HaveLen(2))
+			Expect(response.Errors[0]).To(HavePrefix("template: subject:1:"))
+			Expect(response.Errors[1]).To(HavePrefix("template: text:2:"))
+			Expect(response.Errors[1]).To(ContainSubstring("can't evaluate field Txet"))
+			Expect(templatesCollection.SetCall.Receives.Template).To(Equal(collections.Template{}))
+		})
+
 		It("returns a 401 when the request does not include a client id", func() {
 			context.Set("client_id", "")
 
@@ -309,11 +446,20 @@ var _ = Describe("CreateHandler", func() {
 			Expect(writer.Code).To(Equal(http.StatusConflict))
 		})
 
+		It("returns a 422 when the layout cannot be used", func() {
+			templatesCollection.SetCall.Returns.Error = collections.ValidationError{Err: errors.New(`Layout "some-layout-id" could not be found`)}
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": ["Layout \"some-layout-id\" could not be found"] }`))
+		})
+
 		It("returns a 500 when the collection indicates a system error", func() {
 			templatesCollection.SetCall.Returns.Error = errors.New("The database is bad")
 			handler.ServeHTTP(writer, request, context)
 			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
 			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": ["The database is bad"] }`))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
 		})
 	})
 })
@@ -11,7 +11,7 @@ import (
 
 type collectionsDeleter interface {
 	Get(conn collections.ConnectionInterface, templateID, clientID string) (collections.Template, error)
-	Delete(conn collections.ConnectionInterface, templateID string) error
+	Delete(conn collections.ConnectionInterface, templateID, clientID, replacementID string) error
 }
 
 type DeleteHandler struct {
@@ -27,6 +27,7 @@ func NewDeleteHandler(deleter collectionsDeleter) DeleteHandler {
 func (h DeleteHandler) ServeHTTP(w http.ResponseWriter, request *http.Request, context stack.Context) {
 	splitURL := strings.Split(request.URL.Path, "/")
 	templateID := splitURL[len(splitURL)-1]
+	replacementID := request.URL.Query().Get("replacement_template_id")
 
 	database := context.Get("database").(collections.DatabaseInterface)
 	clientID := context.Get("client_id").(string)
@@ -44,11 +45,22 @@ func (h DeleteHandler) ServeHTTP(w http.ResponseWriter, request *http.Request, c
 		return
 	}
 
-	err = h.deleter.Delete(database.Connection(), templateID)
+	// Campaign types are reassigned and the template deleted together, so
+	// that no campaign type is left pointing at a missing template.
+	transaction := database.Connection().Transaction()
+	transaction.Begin()
+
+	err = h.deleter.Delete(transaction, templateID, clientID, replacementID)
 	if err != nil {
+		transaction.Rollback()
+
 		switch err.(type) {
 		case collections.NotFoundError:
 			w.WriteHeader(http.StatusNotFound)
+		case collections.InUseError:
+			w.WriteHeader(http.StatusConflict)
+		case collections.ValidationError:
+			w.WriteHeader(422)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -57,5 +69,12 @@ func (h DeleteHandler) ServeHTTP(w http.ResponseWriter, request *http.Request, c
 		return
 	}
 
+	err = transaction.Commit()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"errors": [%q]}`, err)
+		return
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -16,13 +16,14 @@ import (
 
 var _ = Describe("DeleteHandler", func() {
 	var (
-		handler    templates.DeleteHandler
-		writer     *httptest.ResponseRecorder
-		request    *http.Request
-		conn       *mocks.Connection
-		database   *mocks.Database
-		collection *mocks.TemplatesCollection
-		context    stack.Context
+		handler     templates.DeleteHandler
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		conn        *mocks.Connection
+		transaction *mocks.Transaction
+		database    *mocks.Database
+		collection  *mocks.TemplatesCollection
+		context     stack.Context
 	)
 
 	BeforeEach(func() {
@@ -30,7 +31,9 @@ var _ = Describe("DeleteHandler", func() {
 
 		context = stack.NewContext()
 
+		transaction = mocks.NewTransaction()
 		conn = mocks.NewConnection()
+		conn.TransactionCall.Returns.Transaction = transaction
 		database = mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 		context.Set("database", database)
@@ -57,8 +60,26 @@ var _ = Describe("DeleteHandler", func() {
 		Expect(collection.GetCall.Receives.TemplateID).To(Equal("some-template-id"))
 		Expect(collection.GetCall.Receives.ClientID).To(Equal("some-client-id"))
 
-		Expect(collection.DeleteCall.Receives.Connection).To(Equal(database.Connection()))
+		Expect(collection.DeleteCall.Receives.Connection).To(Equal(transaction))
 		Expect(collection.DeleteCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.DeleteCall.Receives.ClientID).To(Equal("some-client-id"))
+		Expect(collection.DeleteCall.Receives.ReplacementID).To(BeEmpty())
+
+		Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+		Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+	})
+
+	It("passes along the template that replaces it", func() {
+		var err error
+		request, err = http.NewRequest("DELETE", "/templates/some-template-id?replacement_template_id=other-template-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(collection.DeleteCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.DeleteCall.Receives.ReplacementID).To(Equal("other-template-id"))
 	})
 
 	Context("failure cases", func() {
@@ -82,6 +103,29 @@ var _ = Describe("DeleteHandler", func() {
 			}`))
 		})
 
+		It("returns a 409 and rolls back when the template is in use", func() {
+			collection.DeleteCall.Returns.Error = collections.InUseError{Err: errors.New(`Template "some-template-id" is used by campaign types and cannot be deleted without a replacement template`)}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusConflict))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["Template \"some-template-id\" is used by campaign types and cannot be deleted without a replacement template"]
+			}`))
+
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("returns a 422 when the replacement template is invalid", func() {
+			collection.DeleteCall.Returns.Error = collections.ValidationError{Err: errors.New(`Replacement template "missing-template-id" could not be found`)}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["Replacement template \"missing-template-id\" could not be found"]
+			}`))
+		})
+
 		It("returns a 500 when the get collection call results in an unknown error", func() {
 			collection.GetCall.Returns.Error = errors.New("something bad happened")
 
@@ -0,0 +1,64 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type versionDiffer interface {
+	DiffVersions(conn collections.ConnectionInterface, templateID, clientID string, from, to int) (collections.TemplateDiff, error)
+}
+
+type DiffHandler struct {
+	collection versionDiffer
+}
+
+func NewDiffHandler(collection versionDiffer) DiffHandler {
+	return DiffHandler{
+		collection: collection,
+	}
+}
+
+func (h DiffHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	templateID := splitURL[len(splitURL)-2]
+
+	query := req.URL.Query()
+
+	from, err := strconv.Atoi(query.Get("from"))
+	if err != nil || from < 1 {
+		w.WriteHeader(422)
+		w.Write([]byte(`{ "errors": [ "\"from\" must be a template version number" ] }`))
+		return
+	}
+
+	to, err := strconv.Atoi(query.Get("to"))
+	if err != nil || to < 1 {
+		w.WriteHeader(422)
+		w.Write([]byte(`{ "errors": [ "\"to\" must be a template version number" ] }`))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	clientID := context.Get("client_id").(string)
+
+	diff, err := h.collection.DiffVersions(database.Connection(), templateID, clientID, from, to)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewTemplateDiffResponse(diff))
+}
@@ -0,0 +1,121 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffHandler", func() {
+	var (
+		handler    templates.DiffHandler
+		context    stack.Context
+		conn       *mocks.Connection
+		writer     *httptest.ResponseRecorder
+		request    *http.Request
+		collection *mocks.TemplatesCollection
+	)
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/templates/some-template-id/diff?from=1&to=3", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		collection = mocks.NewTemplatesCollection()
+
+		handler = templates.NewDiffHandler(collection)
+	})
+
+	It("diffs two versions of a template", func() {
+		collection.DiffVersionsCall.Returns.TemplateDiff = collections.TemplateDiff{
+			TemplateID: "some-template-id",
+			From:       1,
+			To:         3,
+			Text:       "-old text\n+new text",
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"template_id": "some-template-id",
+			"from": 1,
+			"to": 3,
+			"diff": {
+				"text": "-old text\n+new text"
+			},
+			"_links": {
+				"self": { "href": "/templates/some-template-id/diff?from=1&to=3" },
+				"from": { "href": "/templates/some-template-id/versions/1" },
+				"to": { "href": "/templates/some-template-id/versions/3" }
+			}
+		}`))
+
+		Expect(collection.DiffVersionsCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.DiffVersionsCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.DiffVersionsCall.Receives.ClientID).To(Equal("some-client-id"))
+		Expect(collection.DiffVersionsCall.Receives.From).To(Equal(1))
+		Expect(collection.DiffVersionsCall.Receives.To).To(Equal(3))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 422 when the from version is missing", func() {
+			var err error
+			request, err = http.NewRequest("GET", "/templates/some-template-id/diff?to=3", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "\"from\" must be a template version number" ] }`))
+		})
+
+		It("returns a 422 when the to version is not a number", func() {
+			var err error
+			request, err = http.NewRequest("GET", "/templates/some-template-id/diff?from=1&to=banana", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "\"to\" must be a template version number" ] }`))
+		})
+
+		It("returns a 404 when a version cannot be found", func() {
+			collection.DiffVersionsCall.Returns.Error = collections.NotFoundError{Err: errors.New("version not found")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "version not found" ] }`))
+		})
+
+		It("returns a 500 when the collection fails", func() {
+			collection.DiffVersionsCall.Returns.Error = errors.New("database is down")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "database is down" ] }`))
+		})
+	})
+})
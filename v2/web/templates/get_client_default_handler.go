@@ -0,0 +1,38 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type clientTemplateGetter interface {
+	Get(conn collections.ConnectionInterface, clientID string) (collections.ClientTemplate, error)
+}
+
+type GetClientDefaultHandler struct {
+	collection clientTemplateGetter
+}
+
+func NewGetClientDefaultHandler(collection clientTemplateGetter) GetClientDefaultHandler {
+	return GetClientDefaultHandler{
+		collection: collection,
+	}
+}
+
+func (h GetClientDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	database := context.Get("database").(DatabaseInterface)
+	clientID := context.Get("client_id").(string)
+
+	clientTemplate, err := h.collection.Get(database.Connection(), clientID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewClientTemplateResponse(clientTemplate))
+}
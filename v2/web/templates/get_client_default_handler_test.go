@@ -0,0 +1,91 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetClientDefaultHandler", func() {
+	var (
+		handler    templates.GetClientDefaultHandler
+		context    stack.Context
+		conn       *mocks.Connection
+		writer     *httptest.ResponseRecorder
+		request    *http.Request
+		collection *mocks.ClientTemplatesCollection
+	)
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/templates/client_default", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		collection = mocks.NewClientTemplatesCollection()
+
+		handler = templates.NewGetClientDefaultHandler(collection)
+	})
+
+	It("returns the default template of the client", func() {
+		collection.GetCall.Returns.ClientTemplate = collections.ClientTemplate{
+			ClientID:   "some-client-id",
+			TemplateID: "some-template-id",
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"template_id": "some-template-id",
+			"_links": {
+				"self": { "href": "/templates/client_default" },
+				"template": { "href": "/templates/some-template-id" }
+			}
+		}`))
+
+		Expect(collection.GetCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+	})
+
+	It("returns a null template when the client has not set one", func() {
+		collection.GetCall.Returns.ClientTemplate = collections.ClientTemplate{ClientID: "some-client-id"}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"template_id": null,
+			"_links": {
+				"self": { "href": "/templates/client_default" }
+			}
+		}`))
+	})
+
+	It("returns a 500 when the collection fails", func() {
+		collection.GetCall.Returns.Error = errors.New("database is down")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+		Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "database is down" ] }`))
+	})
+})
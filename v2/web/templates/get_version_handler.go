@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type versionGetter interface {
+	GetVersion(conn collections.ConnectionInterface, templateID, clientID string, version int) (collections.TemplateVersion, error)
+}
+
+type GetVersionHandler struct {
+	collection versionGetter
+}
+
+func NewGetVersionHandler(collection versionGetter) GetVersionHandler {
+	return GetVersionHandler{
+		collection: collection,
+	}
+}
+
+func (h GetVersionHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	templateID := splitURL[len(splitURL)-3]
+
+	version, err := strconv.Atoi(splitURL[len(splitURL)-1])
+	if err != nil || version < 1 {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, fmt.Sprintf("Version %q of template %q could not be found", splitURL[len(splitURL)-1], templateID))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	clientID := context.Get("client_id").(string)
+
+	templateVersion, err := h.collection.GetVersion(database.Connection(), templateID, clientID, version)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewTemplateVersionResponse(templateVersion))
+}
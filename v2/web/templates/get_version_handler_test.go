@@ -0,0 +1,118 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetVersionHandler", func() {
+	var (
+		handler    templates.GetVersionHandler
+		context    stack.Context
+		conn       *mocks.Connection
+		writer     *httptest.ResponseRecorder
+		request    *http.Request
+		collection *mocks.TemplatesCollection
+	)
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/templates/some-template-id/versions/2", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		collection = mocks.NewTemplatesCollection()
+
+		handler = templates.NewGetVersionHandler(collection)
+	})
+
+	It("gets a version of a template", func() {
+		collection.GetVersionCall.Returns.TemplateVersion = collections.TemplateVersion{
+			TemplateID: "some-template-id",
+			Version:    2,
+			Name:       "an interesting template",
+			Text:       "template text",
+			HTML:       "template html",
+			Subject:    "template subject",
+			Metadata:   `{ "template": "metadata" }`,
+			CreatedAt:  time.Date(2015, time.July, 1, 0, 0, 0, 0, time.UTC),
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"template_id": "some-template-id",
+			"version": 2,
+			"name": "an interesting template",
+			"text": "template text",
+			"html": "template html",
+			"subject": "template subject",
+			"metadata": {
+				"template": "metadata"
+			},
+			"created_at": "2015-07-01T00:00:00Z",
+			"_links": {
+				"self": { "href": "/templates/some-template-id/versions/2" },
+				"template": { "href": "/templates/some-template-id" }
+			}
+		}`))
+
+		Expect(collection.GetVersionCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.GetVersionCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.GetVersionCall.Receives.ClientID).To(Equal("some-client-id"))
+		Expect(collection.GetVersionCall.Receives.Version).To(Equal(2))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 404 when the version is not a number", func() {
+			var err error
+			request, err = http.NewRequest("GET", "/templates/some-template-id/versions/latest", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Version \"latest\" of template \"some-template-id\" could not be found" ] }`))
+			Expect(collection.GetVersionCall.Receives.TemplateID).To(BeEmpty())
+		})
+
+		It("returns a 404 when the version cannot be found", func() {
+			collection.GetVersionCall.Returns.Error = collections.NotFoundError{Err: errors.New("version not found")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "version not found" ] }`))
+		})
+
+		It("returns a 500 when the collection fails", func() {
+			collection.GetVersionCall.Returns.Error = errors.New("database is down")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "database is down" ] }`))
+		})
+	})
+})
@@ -0,0 +1,47 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type associationsLister interface {
+	ListAssociations(conn collections.ConnectionInterface, templateID, clientID string) (collections.TemplateAssociations, error)
+}
+
+type ListAssociationsHandler struct {
+	collection associationsLister
+}
+
+func NewListAssociationsHandler(collection associationsLister) ListAssociationsHandler {
+	return ListAssociationsHandler{
+		collection: collection,
+	}
+}
+
+func (h ListAssociationsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	templateID := splitURL[len(splitURL)-2]
+
+	database := context.Get("database").(DatabaseInterface)
+	clientID := context.Get("client_id").(string)
+
+	associations, err := h.collection.ListAssociations(database.Connection(), templateID, clientID)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewTemplateAssociationsResponse(templateID, associations))
+}
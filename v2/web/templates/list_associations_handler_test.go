@@ -0,0 +1,149 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListAssociationsHandler", func() {
+	var (
+		handler    templates.ListAssociationsHandler
+		context    stack.Context
+		conn       *mocks.Connection
+		writer     *httptest.ResponseRecorder
+		request    *http.Request
+		collection *mocks.TemplatesCollection
+	)
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/templates/some-template-id/associations", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		collection = mocks.NewTemplatesCollection()
+
+		handler = templates.NewListAssociationsHandler(collection)
+	})
+
+	It("lists the campaign types, campaigns and templates that use the template", func() {
+		collection.ListAssociationsCall.Returns.Associations = collections.TemplateAssociations{
+			CampaignTypes: []collections.CampaignType{
+				{ID: "some-campaign-type-id", Name: "some-campaign-type", SenderID: "some-sender-id", TemplateID: "some-template-id"},
+			},
+			Campaigns: []collections.Campaign{
+				{
+					ID:              "some-campaign-id",
+					CampaignTypeID:  "some-campaign-type-id",
+					SenderID:        "some-sender-id",
+					TemplateID:      "some-template-id",
+					TemplateVersion: 3,
+					StartTime:       time.Date(2015, time.July, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			Templates: []collections.Template{
+				{ID: "some-wrapped-template-id", Name: "some-wrapped-template", LayoutID: "some-template-id"},
+			},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"campaign_types": [
+				{
+					"id": "some-campaign-type-id",
+					"name": "some-campaign-type",
+					"sender_id": "some-sender-id",
+					"_links": {
+						"self": { "href": "/campaign_types/some-campaign-type-id" }
+					}
+				}
+			],
+			"campaigns": [
+				{
+					"id": "some-campaign-id",
+					"campaign_type_id": "some-campaign-type-id",
+					"sender_id": "some-sender-id",
+					"template_version": 3,
+					"start_time": "2015-07-02T00:00:00Z",
+					"_links": {
+						"self": { "href": "/campaigns/some-campaign-id" }
+					}
+				}
+			],
+			"templates": [
+				{
+					"id": "some-wrapped-template-id",
+					"name": "some-wrapped-template",
+					"_links": {
+						"self": { "href": "/templates/some-wrapped-template-id" }
+					}
+				}
+			],
+			"_links": {
+				"self": { "href": "/templates/some-template-id/associations" },
+				"template": { "href": "/templates/some-template-id" }
+			}
+		}`))
+
+		Expect(collection.ListAssociationsCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.ListAssociationsCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.ListAssociationsCall.Receives.ClientID).To(Equal("some-client-id"))
+	})
+
+	It("returns empty lists when nothing uses the template", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"campaign_types": [],
+			"campaigns": [],
+			"templates": [],
+			"_links": {
+				"self": { "href": "/templates/some-template-id/associations" },
+				"template": { "href": "/templates/some-template-id" }
+			}
+		}`))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 404 when the template cannot be found", func() {
+			collection.ListAssociationsCall.Returns.Error = collections.NotFoundError{Err: errors.New("template not found")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "template not found" ] }`))
+		})
+
+		It("returns a 500 when the collection fails", func() {
+			collection.ListAssociationsCall.Returns.Error = errors.New("database is down")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "database is down" ] }`))
+		})
+	})
+})
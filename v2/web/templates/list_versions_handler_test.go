@@ -0,0 +1,135 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListVersionsHandler", func() {
+	var (
+		handler    templates.ListVersionsHandler
+		context    stack.Context
+		conn       *mocks.Connection
+		writer     *httptest.ResponseRecorder
+		request    *http.Request
+		collection *mocks.TemplatesCollection
+	)
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/templates/some-template-id/versions", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		collection = mocks.NewTemplatesCollection()
+
+		handler = templates.NewListVersionsHandler(collection)
+	})
+
+	It("lists the versions of a template", func() {
+		collection.ListVersionsCall.Returns.TemplateVersions = []collections.TemplateVersion{
+			{
+				TemplateID: "some-template-id",
+				Version:    2,
+				Name:       "new name",
+				Text:       "new text",
+				Subject:    "new subject",
+				Metadata:   "{}",
+				CreatedAt:  time.Date(2015, time.July, 2, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				TemplateID: "some-template-id",
+				Version:    1,
+				Name:       "old name",
+				Text:       "old text",
+				Subject:    "old subject",
+				Metadata:   "{}",
+				CreatedAt:  time.Date(2015, time.July, 1, 0, 0, 0, 0, time.UTC),
+			},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"versions": [
+				{
+					"template_id": "some-template-id",
+					"version": 2,
+					"name": "new name",
+					"text": "new text",
+					"html": "",
+					"subject": "new subject",
+					"metadata": {},
+					"created_at": "2015-07-02T00:00:00Z",
+					"_links": {
+						"self": { "href": "/templates/some-template-id/versions/2" },
+						"template": { "href": "/templates/some-template-id" }
+					}
+				},
+				{
+					"template_id": "some-template-id",
+					"version": 1,
+					"name": "old name",
+					"text": "old text",
+					"html": "",
+					"subject": "old subject",
+					"metadata": {},
+					"created_at": "2015-07-01T00:00:00Z",
+					"_links": {
+						"self": { "href": "/templates/some-template-id/versions/1" },
+						"template": { "href": "/templates/some-template-id" }
+					}
+				}
+			],
+			"_links": {
+				"self": { "href": "/templates/some-template-id/versions" },
+				"template": { "href": "/templates/some-template-id" }
+			}
+		}`))
+
+		Expect(collection.ListVersionsCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.ListVersionsCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.ListVersionsCall.Receives.ClientID).To(Equal("some-client-id"))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 404 when the template cannot be found", func() {
+			collection.ListVersionsCall.Returns.Error = collections.NotFoundError{Err: errors.New("template not found")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "template not found" ] }`))
+		})
+
+		It("returns a 500 when the collection fails", func() {
+			collection.ListVersionsCall.Returns.Error = errors.New("database is down")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "database is down" ] }`))
+		})
+	})
+})
@@ -0,0 +1,207 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type templatePreviewer interface {
+	Preview(delivery common.Delivery, templates common.Templates) (common.Preview, error)
+}
+
+type previewCollection interface {
+	Get(conn collections.ConnectionInterface, templateID, clientID string) (collections.Template, error)
+	Layouts(conn collections.ConnectionInterface, template collections.Template) ([]collections.Template, error)
+}
+
+type partialsLister interface {
+	List(conn collections.ConnectionInterface, clientID string) ([]collections.TemplatePartial, error)
+}
+
+type PreviewResponse struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+	MIME    string `json:"mime"`
+}
+
+type PreviewHandler struct {
+	collection previewCollection
+	partials   partialsLister
+	previewer  templatePreviewer
+}
+
+func NewPreviewHandler(collection previewCollection, partials partialsLister, previewer templatePreviewer) PreviewHandler {
+	return PreviewHandler{
+		collection: collection,
+		partials:   partials,
+		previewer:  previewer,
+	}
+}
+
+func (h PreviewHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	templateID := splitURL[len(splitURL)-2]
+
+	var previewRequest struct {
+		Subject      string                 `json:"subject"`
+		Text         string                 `json:"text"`
+		HTML         string                 `json:"html"`
+		Endorsement  string                 `json:"endorsement"`
+		Data         map[string]interface{} `json:"data"`
+		Organization struct {
+			GUID string `json:"guid"`
+			Name string `json:"name"`
+		} `json:"organization"`
+		Space struct {
+			GUID string `json:"guid"`
+			Name string `json:"name"`
+		} `json:"space"`
+		Template *struct {
+			Subject  string `json:"subject"`
+			Text     string `json:"text"`
+			HTML     string `json:"html"`
+			LayoutID string `json:"layout_id"`
+
+			DisableAutoText    bool `json:"disable_auto_text"`
+			DisableCSSInlining bool `json:"disable_css_inlining"`
+		} `json:"template"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&previewRequest)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "errors": [ "malformed JSON request" ] }`))
+		return
+	}
+
+	clientID := context.Get("client_id").(string)
+	database := context.Get("database").(DatabaseInterface)
+	conn := database.Connection()
+
+	var template collections.Template
+	if templateID == "templates" {
+		if previewRequest.Template == nil {
+			w.WriteHeader(422)
+			w.Write([]byte(`{ "errors": [ "missing template to preview" ] }`))
+			return
+		}
+
+		template = collections.Template{
+			Subject:  previewRequest.Template.Subject,
+			Text:     previewRequest.Template.Text,
+			HTML:     previewRequest.Template.HTML,
+			LayoutID: previewRequest.Template.LayoutID,
+			ClientID: clientID,
+
+			DisableAutoText:    previewRequest.Template.DisableAutoText,
+			DisableCSSInlining: previewRequest.Template.DisableCSSInlining,
+		}
+	} else {
+		template, err = h.collection.Get(conn, templateID, clientID)
+		if err != nil {
+			switch err.(type) {
+			case collections.NotFoundError:
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+			return
+		}
+	}
+
+	if template.Subject == "" {
+		template.Subject = "{{.Subject}}"
+	}
+
+	templates, err := h.compose(conn, clientID, template)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError, collections.ValidationError:
+			w.WriteHeader(422)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	preview, err := h.previewer.Preview(common.Delivery{
+		ClientID:     clientID,
+		Organization: cf.CloudControllerOrganization{GUID: previewRequest.Organization.GUID, Name: previewRequest.Organization.Name},
+		Space:        cf.CloudControllerSpace{GUID: previewRequest.Space.GUID, Name: previewRequest.Space.Name},
+		Options: common.Options{
+			Subject:     previewRequest.Subject,
+			Text:        previewRequest.Text,
+			HTML:        common.HTML{BodyContent: previewRequest.HTML},
+			Endorsement: previewRequest.Endorsement,
+			Data:        previewRequest.Data,
+		},
+	}, templates)
+	if err != nil {
+		switch err.(type) {
+		case common.TemplateCompileError:
+			w.WriteHeader(422)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(PreviewResponse{
+		Subject: preview.Subject,
+		Text:    preview.Text,
+		HTML:    preview.HTML,
+		MIME:    preview.MIME,
+	})
+}
+
+// compose wraps the template in its layouts and defines the client's partials,
+// the same way the delivery loader does.
+func (h PreviewHandler) compose(conn collections.ConnectionInterface, clientID string, template collections.Template) (common.Templates, error) {
+	layouts, err := h.collection.Layouts(conn, template)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	var layoutTemplates []common.Templates
+	for _, layout := range layouts {
+		layoutTemplates = append(layoutTemplates, common.Templates{
+			Text: layout.Text,
+			HTML: layout.HTML,
+		})
+	}
+
+	partials, err := h.partials.List(conn, clientID)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	var commonPartials []common.Partial
+	for _, partial := range partials {
+		commonPartials = append(commonPartials, common.Partial{
+			Name: partial.Name,
+			Text: partial.Text,
+			HTML: partial.HTML,
+		})
+	}
+
+	return common.ComposeTemplates(common.Templates{
+		Name:    template.Name,
+		Subject: template.Subject,
+		Text:    template.Text,
+		HTML:    template.HTML,
+
+		DisableAutoText:    template.DisableAutoText,
+		DisableCSSInlining: template.DisableCSSInlining,
+	}, layoutTemplates, commonPartials), nil
+}
@@ -0,0 +1,243 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreviewHandler", func() {
+	var (
+		handler    templates.PreviewHandler
+		context    stack.Context
+		conn       *mocks.Connection
+		writer     *httptest.ResponseRecorder
+		request    *http.Request
+		collection *mocks.TemplatesCollection
+		partials   *mocks.TemplatePartialsCollection
+		previewer  *mocks.TemplatePreviewer
+	)
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer([]byte(`{
+			"subject": "some subject",
+			"text": "some text",
+			"html": "<p>some html</p>",
+			"endorsement": "some endorsement",
+			"organization": { "guid": "some-org-guid", "name": "some-org" },
+			"space": { "guid": "some-space-guid", "name": "some-space" }
+		}`)))
+		Expect(err).NotTo(HaveOccurred())
+
+		collection = mocks.NewTemplatesCollection()
+		collection.GetCall.Returns.Template = collections.Template{
+			ID:      "some-template-id",
+			Name:    "some-name",
+			Subject: "Hi {{.Subject}}",
+			Text:    "{{.Text}}",
+			HTML:    "{{.HTML}}",
+		}
+
+		previewer = mocks.NewTemplatePreviewer()
+		previewer.PreviewCall.Returns.Preview = common.Preview{
+			Subject: "Hi some subject",
+			Text:    "some text",
+			HTML:    "<html><p>some html</p></html>",
+			MIME:    "Subject: Hi some subject",
+		}
+
+		partials = mocks.NewTemplatePartialsCollection()
+
+		handler = templates.NewPreviewHandler(collection, partials, previewer)
+	})
+
+	It("renders a preview of a stored template", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"subject": "Hi some subject",
+			"text": "some text",
+			"html": "<html><p>some html</p></html>",
+			"mime": "Subject: Hi some subject"
+		}`))
+
+		Expect(collection.GetCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.GetCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.GetCall.Receives.ClientID).To(Equal("some-client-id"))
+
+		Expect(previewer.PreviewCall.Receives.Templates).To(Equal(common.Templates{
+			Name:    "some-name",
+			Subject: "Hi {{.Subject}}",
+			Text:    "{{.Text}}",
+			HTML:    "{{.HTML}}",
+		}))
+		Expect(previewer.PreviewCall.Receives.Delivery).To(Equal(common.Delivery{
+			ClientID:     "some-client-id",
+			Organization: cf.CloudControllerOrganization{GUID: "some-org-guid", Name: "some-org"},
+			Space:        cf.CloudControllerSpace{GUID: "some-space-guid", Name: "some-space"},
+			Options: common.Options{
+				Subject:     "some subject",
+				Text:        "some text",
+				HTML:        common.HTML{BodyContent: "<p>some html</p>"},
+				Endorsement: "some endorsement",
+			},
+		}))
+	})
+
+	It("renders a preview of an inline template", func() {
+		var err error
+		request, err = http.NewRequest("POST", "/templates/preview", bytes.NewBuffer([]byte(`{
+			"text": "some text",
+			"template": { "text": "inline {{.Text}}" }
+		}`)))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(collection.GetCall.Receives.TemplateID).To(BeEmpty())
+		Expect(previewer.PreviewCall.Receives.Templates).To(Equal(common.Templates{
+			Subject: "{{.Subject}}",
+			Text:    "inline {{.Text}}",
+		}))
+	})
+
+	It("wraps the template in its layouts and includes the client's partials", func() {
+		collection.GetCall.Returns.Template.LayoutID = "some-layout-id"
+		collection.LayoutsCall.Returns.Layouts = []collections.Template{
+			{ID: "some-layout-id", HTML: `<div>{{template "content" .}}</div>`},
+		}
+		partials.ListCall.Returns.Partials = []collections.TemplatePartial{
+			{Name: "footer", Text: "the footer", HTML: "<p>the footer</p>"},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(collection.LayoutsCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.LayoutsCall.Receives.Template.LayoutID).To(Equal("some-layout-id"))
+		Expect(partials.ListCall.Receives.ClientID).To(Equal("some-client-id"))
+		Expect(previewer.PreviewCall.Receives.Templates).To(Equal(common.Templates{
+			Name:    "some-name",
+			Subject: `Hi {{.Subject}}{{define "footer"}}the footer{{end}}`,
+			Text:    `{{.Text}}{{define "footer"}}the footer{{end}}`,
+			HTML:    `<div>{{template "content" .}}</div>{{define "content"}}{{.HTML}}{{end}}{{define "footer"}}<p>the footer</p>{{end}}`,
+		}))
+	})
+
+	It("wraps an inline template in the layout it names", func() {
+		var err error
+		request, err = http.NewRequest("POST", "/templates/preview", bytes.NewBuffer([]byte(`{
+			"text": "some text",
+			"template": { "text": "inline {{.Text}}", "layout_id": "some-layout-id" }
+		}`)))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(collection.LayoutsCall.Receives.Template).To(Equal(collections.Template{
+			Subject:  "{{.Subject}}",
+			Text:     "inline {{.Text}}",
+			LayoutID: "some-layout-id",
+			ClientID: "some-client-id",
+		}))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 400 when the request body is not valid JSON", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates/some-template-id/preview", bytes.NewBuffer([]byte(`%%%`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusBadRequest))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "malformed JSON request" ] }`))
+		})
+
+		It("returns a 422 when an inline preview has no template", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates/preview", bytes.NewBuffer([]byte(`{ "text": "some text" }`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "missing template to preview" ] }`))
+		})
+
+		It("returns a 404 when the template cannot be found", func() {
+			collection.GetCall.Returns.Error = collections.NotFoundError{Err: errors.New("template not found")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "template not found" ] }`))
+		})
+
+		It("returns a 422 when the template does not compile", func() {
+			previewer.PreviewCall.Returns.Error = common.TemplateCompileError{
+				Part: "text",
+				Line: 2,
+				Err:  errors.New(`template: text:2: unexpected "}" in operand`),
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "template: text:2: unexpected \"}\" in operand" ] }`))
+		})
+
+		It("returns a 422 when the layout cannot be found", func() {
+			collection.LayoutsCall.Returns.Error = collections.NotFoundError{Err: errors.New(`Layout "some-layout-id" could not be found`)}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Layout \"some-layout-id\" could not be found" ] }`))
+		})
+
+		It("returns a 500 when the partials cannot be loaded", func() {
+			partials.ListCall.Returns.Error = errors.New("database is down")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "database is down" ] }`))
+		})
+
+		It("returns a 500 when the collection fails", func() {
+			collection.GetCall.Returns.Error = errors.New("database is down")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "database is down" ] }`))
+		})
+	})
+})
@@ -0,0 +1,83 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type templateRollbacker interface {
+	Rollback(conn collections.ConnectionInterface, templateID, clientID string, version int) (collections.Template, error)
+}
+
+type RollbackHandler struct {
+	collection templateRollbacker
+}
+
+func NewRollbackHandler(collection templateRollbacker) RollbackHandler {
+	return RollbackHandler{
+		collection: collection,
+	}
+}
+
+func (h RollbackHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	templateID := splitURL[len(splitURL)-2]
+
+	var rollbackRequest struct {
+		Version int `json:"version"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&rollbackRequest)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "errors": [ "malformed JSON request" ] }`))
+		return
+	}
+
+	if rollbackRequest.Version < 1 {
+		w.WriteHeader(422)
+		w.Write([]byte(`{ "errors": [ "missing template version to roll back to" ] }`))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	var clientID string
+	if templateID != "default" {
+		clientID = context.Get("client_id").(string)
+	}
+
+	// A rollback is stored as a new version, under the same lock as an update.
+	transaction := database.Connection().Transaction()
+	transaction.Begin()
+
+	template, err := h.collection.Rollback(transaction, templateID, clientID, rollbackRequest.Version)
+	if err != nil {
+		transaction.Rollback()
+
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		case collections.ValidationError:
+			w.WriteHeader(422)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewTemplateResponse(template))
+}
@@ -0,0 +1,159 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RollbackHandler", func() {
+	var (
+		handler     templates.RollbackHandler
+		context     stack.Context
+		conn        *mocks.Connection
+		transaction *mocks.Transaction
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		collection  *mocks.TemplatesCollection
+	)
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("POST", "/templates/some-template-id/rollback", bytes.NewBuffer([]byte(`{ "version": 2 }`)))
+		Expect(err).NotTo(HaveOccurred())
+
+		collection = mocks.NewTemplatesCollection()
+
+		handler = templates.NewRollbackHandler(collection)
+	})
+
+	It("rolls a template back to an earlier version", func() {
+		collection.RollbackCall.Returns.Template = collections.Template{
+			ID:       "some-template-id",
+			Name:     "old name",
+			Text:     "old text",
+			Subject:  "old subject",
+			Metadata: "{}",
+			ClientID: "some-client-id",
+			Version:  5,
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": "some-template-id",
+			"name": "old name",
+			"text": "old text",
+			"html": "",
+			"subject": "old subject",
+			"metadata": {},
+			"version": 5,
+			"_links": {
+				"self": { "href": "/templates/some-template-id" }
+			}
+		}`))
+
+		Expect(collection.RollbackCall.Receives.Connection).To(Equal(transaction))
+		Expect(collection.RollbackCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(collection.RollbackCall.Receives.ClientID).To(Equal("some-client-id"))
+		Expect(collection.RollbackCall.Receives.Version).To(Equal(2))
+
+		Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+		Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+	})
+
+	It("rolls back the default template without a client", func() {
+		var err error
+		request, err = http.NewRequest("POST", "/templates/default/rollback", bytes.NewBuffer([]byte(`{ "version": 1 }`)))
+		Expect(err).NotTo(HaveOccurred())
+
+		collection.RollbackCall.Returns.Template = collections.Template{
+			ID:       "default",
+			Metadata: "{}",
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(collection.RollbackCall.Receives.TemplateID).To(Equal("default"))
+		Expect(collection.RollbackCall.Receives.ClientID).To(BeEmpty())
+	})
+
+	Context("failure cases", func() {
+		It("returns a 400 when the request body is not valid JSON", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates/some-template-id/rollback", bytes.NewBuffer([]byte(`%%%`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusBadRequest))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "malformed JSON request" ] }`))
+		})
+
+		It("returns a 422 when the version is missing", func() {
+			var err error
+			request, err = http.NewRequest("POST", "/templates/some-template-id/rollback", bytes.NewBuffer([]byte(`{}`)))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "missing template version to roll back to" ] }`))
+		})
+
+		It("returns a 404 when the version cannot be found", func() {
+			collection.RollbackCall.Returns.Error = collections.NotFoundError{Err: errors.New("version not found")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "version not found" ] }`))
+		})
+
+		It("returns a 422 when the restored layout can no longer be used", func() {
+			collection.RollbackCall.Returns.Error = collections.ValidationError{Err: errors.New(`Layout "some-layout-id" could not be found`)}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Layout \"some-layout-id\" could not be found" ] }`))
+		})
+
+		It("returns a 500 when the collection fails", func() {
+			collection.RollbackCall.Returns.Error = errors.New("database is down")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "database is down" ] }`))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package templates
 
 import (
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/ryanmoran/stack"
 )
@@ -10,18 +11,31 @@ type muxer interface {
 }
 
 type Routes struct {
-	RequestLogging      stack.Middleware
-	WriteAuthenticator  stack.Middleware
-	AdminAuthenticator  stack.Middleware
-	DatabaseAllocator   stack.Middleware
-	TemplatesCollection collections.TemplatesCollection
+	RequestLogging             stack.Middleware
+	WriteAuthenticator         stack.Middleware
+	AdminAuthenticator         stack.Middleware
+	DatabaseAllocator          stack.Middleware
+	TemplatesCollection        collections.TemplatesCollection
+	TemplatePartialsCollection collections.TemplatePartialsCollection
+	ClientTemplatesCollection  collections.ClientTemplatesCollection
+	Previewer                  common.Previewer
 }
 
 func (r Routes) Register(m muxer) {
 	m.Handle("GET", "/templates", NewListHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("POST", "/templates", NewCreateHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/client_default", NewGetClientDefaultHandler(r.ClientTemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/templates/client_default", NewUpdateClientDefaultHandler(r.ClientTemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/templates/{template_id}", NewGetHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("DELETE", "/templates/{template_id}", NewDeleteHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("PUT", "/templates/default", NewUpdateDefaultHandler(r.TemplatesCollection), r.RequestLogging, r.AdminAuthenticator, r.DatabaseAllocator)
 	m.Handle("PUT", "/templates/{template_id}", NewUpdateHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/versions", NewListVersionsHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/versions/{version}", NewGetVersionHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/associations", NewListAssociationsHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/diff", NewDiffHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/default/rollback", NewRollbackHandler(r.TemplatesCollection), r.RequestLogging, r.AdminAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/{template_id}/rollback", NewRollbackHandler(r.TemplatesCollection), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/preview", NewPreviewHandler(r.TemplatesCollection, r.TemplatePartialsCollection, r.Previewer), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/{template_id}/preview", NewPreviewHandler(r.TemplatesCollection, r.TemplatePartialsCollection, r.Previewer), r.RequestLogging, r.WriteAuthenticator, r.DatabaseAllocator)
 }
@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+)
+
+type TemplateCampaignTypeAssociation struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	SenderID string `json:"sender_id"`
+	Links    struct {
+		Self Link `json:"self"`
+	} `json:"_links"`
+}
+
+type TemplateCampaignAssociation struct {
+	ID              string    `json:"id"`
+	CampaignTypeID  string    `json:"campaign_type_id"`
+	SenderID        string    `json:"sender_id"`
+	TemplateVersion int       `json:"template_version,omitempty"`
+	StartTime       time.Time `json:"start_time"`
+	Links           struct {
+		Self Link `json:"self"`
+	} `json:"_links"`
+}
+
+type TemplateLayoutAssociation struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Links struct {
+		Self Link `json:"self"`
+	} `json:"_links"`
+}
+
+type TemplateAssociationsResponseLinks struct {
+	Self     Link `json:"self"`
+	Template Link `json:"template"`
+}
+
+type TemplateAssociationsResponse struct {
+	CampaignTypes []TemplateCampaignTypeAssociation `json:"campaign_types"`
+	Campaigns     []TemplateCampaignAssociation     `json:"campaigns"`
+	Templates     []TemplateLayoutAssociation       `json:"templates"`
+	Links         TemplateAssociationsResponseLinks `json:"_links"`
+}
+
+func NewTemplateAssociationsResponse(templateID string, associations collections.TemplateAssociations) TemplateAssociationsResponse {
+	response := TemplateAssociationsResponse{
+		CampaignTypes: []TemplateCampaignTypeAssociation{},
+		Campaigns:     []TemplateCampaignAssociation{},
+		Templates:     []TemplateLayoutAssociation{},
+		Links: TemplateAssociationsResponseLinks{
+			Self:     Link{fmt.Sprintf("/templates/%s/associations", templateID)},
+			Template: Link{fmt.Sprintf("/templates/%s", templateID)},
+		},
+	}
+
+	for _, campaignType := range associations.CampaignTypes {
+		association := TemplateCampaignTypeAssociation{
+			ID:       campaignType.ID,
+			Name:     campaignType.Name,
+			SenderID: campaignType.SenderID,
+		}
+		association.Links.Self = Link{fmt.Sprintf("/campaign_types/%s", campaignType.ID)}
+
+		response.CampaignTypes = append(response.CampaignTypes, association)
+	}
+
+	for _, campaign := range associations.Campaigns {
+		association := TemplateCampaignAssociation{
+			ID:              campaign.ID,
+			CampaignTypeID:  campaign.CampaignTypeID,
+			SenderID:        campaign.SenderID,
+			TemplateVersion: campaign.TemplateVersion,
+			StartTime:       campaign.StartTime,
+		}
+		association.Links.Self = Link{fmt.Sprintf("/campaigns/%s", campaign.ID)}
+
+		response.Campaigns = append(response.Campaigns, association)
+	}
+
+	for _, template := range associations.Templates {
+		association := TemplateLayoutAssociation{
+			ID:   template.ID,
+			Name: template.Name,
+		}
+		association.Links.Self = Link{fmt.Sprintf("/templates/%s", template.ID)}
+
+		response.Templates = append(response.Templates, association)
+	}
+
+	return response
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 )
 
@@ -16,24 +17,48 @@ type TemplateResponseLinks struct {
 }
 
 type TemplateResponse struct {
-	ID       string                `json:"id"`
-	Name     string                `json:"name"`
-	Text     string                `json:"text"`
-	HTML     string                `json:"html"`
-	Subject  string                `json:"subject"`
-	Metadata *json.RawMessage      `json:"metadata"`
-	Links    TemplateResponseLinks `json:"_links"`
+	ID            string                `json:"id"`
+	Name          string                `json:"name"`
+	Text          string                `json:"text"`
+	HTML          string                `json:"html"`
+	Subject       string                `json:"subject"`
+	Metadata      *json.RawMessage      `json:"metadata"`
+	Localizations common.Localizations  `json:"localizations,omitempty"`
+	LayoutID      string                `json:"layout_id,omitempty"`
+	Version       int                   `json:"version,omitempty"`
+	Warnings      []string              `json:"warnings,omitempty"`
+	Links         TemplateResponseLinks `json:"_links"`
+
+	DisableAutoText    bool `json:"disable_auto_text,omitempty"`
+	DisableCSSInlining bool `json:"disable_css_inlining,omitempty"`
 }
 
 func NewTemplateResponse(template collections.Template) TemplateResponse {
 	metadata := json.RawMessage(template.Metadata)
+	localizations, _ := decodeLocalizations(template.Localizations)
 	return TemplateResponse{
-		ID:       template.ID,
-		Name:     template.Name,
-		Text:     template.Text,
-		HTML:     template.HTML,
-		Subject:  template.Subject,
-		Metadata: &metadata,
-		Links:    TemplateResponseLinks{Link{fmt.Sprintf("/templates/%s", template.ID)}},
+		ID:            template.ID,
+		Name:          template.Name,
+		Text:          template.Text,
+		HTML:          template.HTML,
+		Subject:       template.Subject,
+		Metadata:      &metadata,
+		Localizations: localizations,
+		LayoutID:      template.LayoutID,
+		Version:       template.Version,
+		Links:         TemplateResponseLinks{Link{fmt.Sprintf("/templates/%s", template.ID)}},
+
+		DisableAutoText:    template.DisableAutoText,
+		DisableCSSInlining: template.DisableCSSInlining,
+	}
+}
+
+func decodeLocalizations(localizations string) (common.Localizations, error) {
+	var decoded common.Localizations
+	if localizations == "" {
+		return decoded, nil
 	}
+
+	err := json.Unmarshal([]byte(localizations), &decoded)
+	return decoded, err
 }
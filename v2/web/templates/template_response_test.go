@@ -64,4 +64,50 @@ var _ = Describe("TemplateResponse", func() {
 			}
 		}`))
 	})
+
+	It("includes the current version when the template has one", func() {
+		output, err := json.Marshal(templates.NewTemplateResponse(collections.Template{
+			ID:       "some-template-id",
+			Metadata: "{}",
+			Version:  4,
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchJSON(`{
+			"id": "some-template-id",
+			"name": "",
+			"text": "",
+			"html": "",
+			"subject": "",
+			"metadata": {},
+			"version": 4,
+			"_links": {
+				"self": {
+					"href": "/templates/some-template-id"
+				}
+			}
+		}`))
+	})
+
+	It("includes the layout when the template has one", func() {
+		output, err := json.Marshal(templates.NewTemplateResponse(collections.Template{
+			ID:       "some-template-id",
+			Metadata: "{}",
+			LayoutID: "some-layout-id",
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchJSON(`{
+			"id": "some-template-id",
+			"name": "",
+			"text": "",
+			"html": "",
+			"subject": "",
+			"metadata": {},
+			"layout_id": "some-layout-id",
+			"_links": {
+				"self": {
+					"href": "/templates/some-template-id"
+				}
+			}
+		}`))
+	})
 })
@@ -0,0 +1,134 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+)
+
+type TemplateVersionResponseLinks struct {
+	Self     Link `json:"self"`
+	Template Link `json:"template"`
+}
+
+type TemplateVersionResponse struct {
+	TemplateID    string                       `json:"template_id"`
+	Version       int                          `json:"version"`
+	Name          string                       `json:"name"`
+	Text          string                       `json:"text"`
+	HTML          string                       `json:"html"`
+	Subject       string                       `json:"subject"`
+	Metadata      *json.RawMessage             `json:"metadata"`
+	Localizations common.Localizations         `json:"localizations,omitempty"`
+	LayoutID      string                       `json:"layout_id,omitempty"`
+	CreatedAt     time.Time                    `json:"created_at"`
+	Links         TemplateVersionResponseLinks `json:"_links"`
+
+	DisableAutoText    bool `json:"disable_auto_text,omitempty"`
+	DisableCSSInlining bool `json:"disable_css_inlining,omitempty"`
+}
+
+func NewTemplateVersionResponse(version collections.TemplateVersion) TemplateVersionResponse {
+	metadata := json.RawMessage(version.Metadata)
+	localizations, _ := decodeLocalizations(version.Localizations)
+	return TemplateVersionResponse{
+		TemplateID:    version.TemplateID,
+		Version:       version.Version,
+		Name:          version.Name,
+		Text:          version.Text,
+		HTML:          version.HTML,
+		Subject:       version.Subject,
+		Metadata:      &metadata,
+		Localizations: localizations,
+		LayoutID:      version.LayoutID,
+		CreatedAt:     version.CreatedAt,
+		Links: TemplateVersionResponseLinks{
+			Self:     Link{fmt.Sprintf("/templates/%s/versions/%d", version.TemplateID, version.Version)},
+			Template: Link{fmt.Sprintf("/templates/%s", version.TemplateID)},
+		},
+
+		DisableAutoText:    version.DisableAutoText,
+		DisableCSSInlining: version.DisableCSSInlining,
+	}
+}
+
+type TemplateVersionsListResponseLinks struct {
+	Self     Link `json:"self"`
+	Template Link `json:"template"`
+}
+
+type TemplateVersionsListResponse struct {
+	Versions []TemplateVersionResponse         `json:"versions"`
+	Links    TemplateVersionsListResponseLinks `json:"_links"`
+}
+
+func NewTemplateVersionsListResponse(templateID string, versionList []collections.TemplateVersion) TemplateVersionsListResponse {
+	versions := []TemplateVersionResponse{}
+
+	for _, v := range versionList {
+		versions = append(versions, NewTemplateVersionResponse(v))
+	}
+
+	return TemplateVersionsListResponse{
+		Versions: versions,
+		Links: TemplateVersionsListResponseLinks{
+			Self:     Link{fmt.Sprintf("/templates/%s/versions", templateID)},
+			Template: Link{fmt.Sprintf("/templates/%s", templateID)},
+		},
+	}
+}
+
+type TemplateDiffFields struct {
+	Name          string `json:"name,omitempty"`
+	HTML          string `json:"html,omitempty"`
+	Text          string `json:"text,omitempty"`
+	Subject       string `json:"subject,omitempty"`
+	Metadata      string `json:"metadata,omitempty"`
+	Localizations string `json:"localizations,omitempty"`
+	LayoutID      string `json:"layout_id,omitempty"`
+
+	DisableAutoText    string `json:"disable_auto_text,omitempty"`
+	DisableCSSInlining string `json:"disable_css_inlining,omitempty"`
+}
+
+type TemplateDiffResponseLinks struct {
+	Self Link `json:"self"`
+	From Link `json:"from"`
+	To   Link `json:"to"`
+}
+
+type TemplateDiffResponse struct {
+	TemplateID string                    `json:"template_id"`
+	From       int                       `json:"from"`
+	To         int                       `json:"to"`
+	Diff       TemplateDiffFields        `json:"diff"`
+	Links      TemplateDiffResponseLinks `json:"_links"`
+}
+
+func NewTemplateDiffResponse(diff collections.TemplateDiff) TemplateDiffResponse {
+	return TemplateDiffResponse{
+		TemplateID: diff.TemplateID,
+		From:       diff.From,
+		To:         diff.To,
+		Diff: TemplateDiffFields{
+			Name:          diff.Name,
+			HTML:          diff.HTML,
+			Text:          diff.Text,
+			Subject:       diff.Subject,
+			Metadata:      diff.Metadata,
+			Localizations: diff.Localizations,
+			LayoutID:      diff.LayoutID,
+
+			DisableAutoText:    diff.DisableAutoText,
+			DisableCSSInlining: diff.DisableCSSInlining,
+		},
+		Links: TemplateDiffResponseLinks{
+			Self: Link{fmt.Sprintf("/templates/%s/diff?from=%d&to=%d", diff.TemplateID, diff.From, diff.To)},
+			From: Link{fmt.Sprintf("/templates/%s/versions/%d", diff.TemplateID, diff.From)},
+			To:   Link{fmt.Sprintf("/templates/%s/versions/%d", diff.TemplateID, diff.To)},
+		},
+	}
+}
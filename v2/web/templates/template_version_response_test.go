@@ -0,0 +1,93 @@
+package templates_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionResponse", func() {
+	It("can marshal a template version into JSON", func() {
+		output, err := json.Marshal(templates.NewTemplateVersionResponse(collections.TemplateVersion{
+			TemplateID: "some-template-id",
+			Version:    2,
+			Name:       "some-template",
+			Text:       "template-text",
+			HTML:       "template-html",
+			Subject:    "template-subject",
+			Metadata:   `{ "template": "metadata" }`,
+			CreatedAt:  time.Date(2015, time.July, 1, 12, 0, 0, 0, time.UTC),
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchJSON(`{
+			"template_id": "some-template-id",
+			"version": 2,
+			"name": "some-template",
+			"text": "template-text",
+			"html": "template-html",
+			"subject": "template-subject",
+			"metadata": {
+				"template": "metadata"
+			},
+			"created_at": "2015-07-01T12:00:00Z",
+			"_links": {
+				"self": {
+					"href": "/templates/some-template-id/versions/2"
+				},
+				"template": {
+					"href": "/templates/some-template-id"
+				}
+			}
+		}`))
+	})
+
+	It("can marshal a list of template versions into JSON", func() {
+		output, err := json.Marshal(templates.NewTemplateVersionsListResponse("some-template-id", []collections.TemplateVersion{}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchJSON(`{
+			"versions": [],
+			"_links": {
+				"self": {
+					"href": "/templates/some-template-id/versions"
+				},
+				"template": {
+					"href": "/templates/some-template-id"
+				}
+			}
+		}`))
+	})
+
+	It("can marshal a template diff into JSON, leaving out unchanged fields", func() {
+		output, err := json.Marshal(templates.NewTemplateDiffResponse(collections.TemplateDiff{
+			TemplateID: "some-template-id",
+			From:       1,
+			To:         3,
+			Subject:    "-old subject\n+new subject",
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(output).To(MatchJSON(`{
+			"template_id": "some-template-id",
+			"from": 1,
+			"to": 3,
+			"diff": {
+				"subject": "-old subject\n+new subject"
+			},
+			"_links": {
+				"self": {
+					"href": "/templates/some-template-id/diff?from=1&to=3"
+				},
+				"from": {
+					"href": "/templates/some-template-id/versions/1"
+				},
+				"to": {
+					"href": "/templates/some-template-id/versions/3"
+				}
+			}
+		}`))
+	})
+})
@@ -0,0 +1,61 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type clientTemplateSetter interface {
+	Set(conn collections.ConnectionInterface, clientTemplate collections.ClientTemplate) (collections.ClientTemplate, error)
+}
+
+type UpdateClientDefaultHandler struct {
+	collection clientTemplateSetter
+}
+
+func NewUpdateClientDefaultHandler(collection clientTemplateSetter) UpdateClientDefaultHandler {
+	return UpdateClientDefaultHandler{
+		collection: collection,
+	}
+}
+
+// ServeHTTP sets the template the client's campaigns use when neither the
+// campaign nor its campaign type names one. A null template_id clears it.
+func (h UpdateClientDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	var updateRequest struct {
+		TemplateID *string `json:"template_id"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&updateRequest)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "errors": [ "malformed JSON request" ] }`))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	clientID := context.Get("client_id").(string)
+
+	clientTemplate := collections.ClientTemplate{ClientID: clientID}
+	if updateRequest.TemplateID != nil {
+		clientTemplate.TemplateID = *updateRequest.TemplateID
+	}
+
+	clientTemplate, err = h.collection.Set(database.Connection(), clientTemplate)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewClientTemplateResponse(clientTemplate))
+}
@@ -0,0 +1,119 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpdateClientDefaultHandler", func() {
+	var (
+		handler    templates.UpdateClientDefaultHandler
+		context    stack.Context
+		conn       *mocks.Connection
+		writer     *httptest.ResponseRecorder
+		collection *mocks.ClientTemplatesCollection
+	)
+
+	newRequest := func(body string) *http.Request {
+		request, err := http.NewRequest("PUT", "/templates/client_default", bytes.NewBufferString(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		return request
+	}
+
+	BeforeEach(func() {
+		context = stack.NewContext()
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context.Set("database", database)
+
+		context.Set("client_id", "some-client-id")
+
+		writer = httptest.NewRecorder()
+
+		collection = mocks.NewClientTemplatesCollection()
+
+		handler = templates.NewUpdateClientDefaultHandler(collection)
+	})
+
+	It("sets the default template of the client", func() {
+		collection.SetCall.Returns.ClientTemplate = collections.ClientTemplate{
+			ClientID:   "some-client-id",
+			TemplateID: "some-template-id",
+		}
+
+		handler.ServeHTTP(writer, newRequest(`{"template_id": "some-template-id"}`), context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"template_id": "some-template-id",
+			"_links": {
+				"self": { "href": "/templates/client_default" },
+				"template": { "href": "/templates/some-template-id" }
+			}
+		}`))
+
+		Expect(collection.SetCall.Receives.Connection).To(Equal(conn))
+		Expect(collection.SetCall.Receives.ClientTemplate).To(Equal(collections.ClientTemplate{
+			ClientID:   "some-client-id",
+			TemplateID: "some-template-id",
+		}))
+	})
+
+	It("clears the default template of the client when the template is null", func() {
+		collection.SetCall.Returns.ClientTemplate = collections.ClientTemplate{ClientID: "some-client-id"}
+
+		handler.ServeHTTP(writer, newRequest(`{"template_id": null}`), context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"template_id": null,
+			"_links": {
+				"self": { "href": "/templates/client_default" }
+			}
+		}`))
+
+		Expect(collection.SetCall.Receives.ClientTemplate).To(Equal(collections.ClientTemplate{
+			ClientID: "some-client-id",
+		}))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 400 when the JSON is malformed", func() {
+			handler.ServeHTTP(writer, newRequest(`{"template_id": `), context)
+
+			Expect(writer.Code).To(Equal(http.StatusBadRequest))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "malformed JSON request" ] }`))
+		})
+
+		It("returns a 404 when the template cannot be found", func() {
+			collection.SetCall.Returns.Error = collections.NotFoundError{Err: errors.New(`Template with id "missing-template-id" could not be found`)}
+
+			handler.ServeHTTP(writer, newRequest(`{"template_id": "missing-template-id"}`), context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Template with id \"missing-template-id\" could not be found" ] }`))
+		})
+
+		It("returns a 500 when the collection fails", func() {
+			collection.SetCall.Returns.Error = errors.New("database is down")
+
+			handler.ServeHTTP(writer, newRequest(`{"template_id": "some-template-id"}`), context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "database is down" ] }`))
+		})
+	})
+})
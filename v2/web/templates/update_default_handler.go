@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/ryanmoran/stack"
 )
 
@@ -20,11 +22,16 @@ func NewUpdateDefaultHandler(collection collectionSetGetter) UpdateDefaultHandle
 
 func (h UpdateDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
 	var updateRequest struct {
-		Name     *string          `json:"name"`
-		HTML     *string          `json:"html"`
-		Text     *string          `json:"text"`
-		Subject  *string          `json:"subject"`
-		Metadata *json.RawMessage `json:"metadata"`
+		Name          *string          `json:"name"`
+		HTML          *string          `json:"html"`
+		Text          *string          `json:"text"`
+		Subject       *string          `json:"subject"`
+		Metadata      *json.RawMessage `json:"metadata"`
+		Localizations *json.RawMessage `json:"localizations"`
+		LayoutID      *string          `json:"layout_id"`
+
+		DisableAutoText    *bool `json:"disable_auto_text"`
+		DisableCSSInlining *bool `json:"disable_css_inlining"`
 	}
 
 	err := json.NewDecoder(req.Body).Decode(&updateRequest)
@@ -63,6 +70,22 @@ func (h UpdateDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request
 		template.Metadata = string(*updateRequest.Metadata)
 	}
 
+	if updateRequest.Localizations != nil {
+		template.Localizations = string(*updateRequest.Localizations)
+	}
+
+	if updateRequest.LayoutID != nil {
+		template.LayoutID = *updateRequest.LayoutID
+	}
+
+	if updateRequest.DisableAutoText != nil {
+		template.DisableAutoText = *updateRequest.DisableAutoText
+	}
+
+	if updateRequest.DisableCSSInlining != nil {
+		template.DisableCSSInlining = *updateRequest.DisableCSSInlining
+	}
+
 	if template.Name == "" {
 		w.WriteHeader(422)
 		w.Write([]byte(`{ "errors": [ "Template \"name\" field cannot be empty" ] }`))
@@ -79,12 +102,52 @@ func (h UpdateDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request
 		template.Subject = "{{.Subject}}"
 	}
 
-	template, err = h.templatesCollection.Set(database.Connection(), template)
+	localizations, err := decodeLocalizations(template.Localizations)
+	if err != nil {
+		w.WriteHeader(422)
+		w.Write([]byte(`{ "errors": [ "localizations must map each locale to a subject, text and html" ] }`))
+		return
+	}
+
+	warnings, err := common.ValidateLocalizedTemplates(common.Templates{
+		Subject: template.Subject,
+		Text:    template.Text,
+		HTML:    template.HTML,
+	}, localizations, false)
+	if err != nil {
+		w.WriteHeader(422)
+		json.NewEncoder(w).Encode(map[string][]string{
+			"errors": err.(common.TemplateValidationError).Messages(),
+		})
+		return
+	}
+
+	transaction := database.Connection().Transaction()
+	transaction.Begin()
+
+	template, err = h.templatesCollection.Set(transaction, template)
+	if err != nil {
+		transaction.Rollback()
+
+		switch err.(type) {
+		case collections.ValidationError:
+			w.WriteHeader(422)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprintf(w, `{ "errors": [%q] }`, err)
+		return
+	}
+
+	err = transaction.Commit()
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, `{ "errors": [%q] }`, err)
 		return
 	}
 
-	json.NewEncoder(w).Encode(NewTemplateResponse(template))
+	response := NewTemplateResponse(template)
+	response.Warnings = warnings
+
+	json.NewEncoder(w).Encode(response)
 }
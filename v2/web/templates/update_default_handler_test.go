@@ -24,6 +24,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 		request             *http.Request
 		templatesCollection *mocks.TemplatesCollection
 		conn                *mocks.Connection
+		transaction         *mocks.Transaction
 	)
 
 	BeforeEach(func() {
@@ -31,6 +32,9 @@ var _ = Describe("UpdateDefaultHandler", func() {
 		database := mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
+
 		templatesCollection = mocks.NewTemplatesCollection()
 		templatesCollection.GetCall.Returns.Template = collections.Template{
 			ID:       "default",
@@ -82,6 +86,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 			"text":     "new text",
 			"subject":  "new subject",
 			"metadata": {"template":"new"},
+			"warnings": [ "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe" ],
 			"_links": {
 				"self": {
 					"href": "/templates/default"
@@ -89,7 +94,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 			}
 		}`))
 
-		Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(conn))
+		Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(transaction))
 		Expect(templatesCollection.SetCall.Receives.Template).To(Equal(collections.Template{
 			ID:       "default",
 			Name:     "new template name",
@@ -99,6 +104,10 @@ var _ = Describe("UpdateDefaultHandler", func() {
 			Metadata: `{"template":"new"}`,
 			ClientID: "",
 		}))
+
+		Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+		Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
 	})
 
 	Context("when omitting fields", func() {
@@ -130,6 +139,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 				"text":     "default text",
 				"subject":  "default subject",
 				"metadata": {"template":"default"},
+				"warnings": [ "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe" ],
 				"_links": {
 					"self": {
 						"href": "/templates/default"
@@ -137,7 +147,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 				}
 			}`))
 
-			Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesCollection.SetCall.Receives.Template).To(Equal(collections.Template{
 				ID:       "default",
 				Name:     "a default template",
@@ -180,6 +190,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 				"text":     "default text",
 				"subject":  "{{.Subject}}",
 				"metadata": {"template":"default"},
+				"warnings": [ "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe" ],
 				"_links": {
 					"self": {
 						"href": "/templates/default"
@@ -187,7 +198,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 				}
 			}`))
 
-			Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesCollection.SetCall.Receives.Template).To(Equal(collections.Template{
 				ID:       "default",
 				Name:     "a default template",
@@ -267,6 +278,19 @@ var _ = Describe("UpdateDefaultHandler", func() {
 			})
 		})
 
+		Context("when the layout cannot be used", func() {
+			It("returns a 422 error with an error message", func() {
+				templatesCollection.SetCall.Returns.Error = collections.ValidationError{Err: errors.New(`Layout "some-layout-id" could not be found`)}
+
+				handler.ServeHTTP(writer, request, context)
+
+				Expect(writer.Code).To(Equal(422))
+				Expect(writer.Body.String()).To(MatchJSON(`{
+					"errors": ["Layout \"some-layout-id\" could not be found"]
+				}`))
+			})
+		})
+
 		Context("when the templates repo set call returns an error", func() {
 			It("returns a 500 error with an error message", func() {
 				templatesCollection.SetCall.Returns.Error = errors.New("failed to set")
@@ -277,6 +301,8 @@ var _ = Describe("UpdateDefaultHandler", func() {
 				Expect(writer.Body.String()).To(MatchJSON(`{
 					"errors": ["failed to set"]
 				}`))
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
 			})
 		})
 	})
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
 	"github.com/ryanmoran/stack"
 )
@@ -13,6 +14,7 @@ import (
 type collectionSetGetter interface {
 	Set(conn collections.ConnectionInterface, template collections.Template) (createdTemplate collections.Template, err error)
 	Get(conn collections.ConnectionInterface, templateID, clientID string) (template collections.Template, err error)
+	UsedOnlyByCritical(conn collections.ConnectionInterface, templateID, clientID string) (critical bool, err error)
 }
 
 type UpdateHandler struct {
@@ -30,11 +32,16 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	templateID := splitURL[len(splitURL)-1]
 
 	var updateRequest struct {
-		Name     *string          `json:"name"`
-		HTML     *string          `json:"html"`
-		Text     *string          `json:"text"`
-		Subject  *string          `json:"subject"`
-		Metadata *json.RawMessage `json:"metadata"`
+		Name          *string          `json:"name"`
+		HTML          *string          `json:"html"`
+		Text          *string          `json:"text"`
+		Subject       *string          `json:"subject"`
+		Metadata      *json.RawMessage `json:"metadata"`
+		Localizations *json.RawMessage `json:"localizations"`
+		LayoutID      *string          `json:"layout_id"`
+
+		DisableAutoText    *bool `json:"disable_auto_text"`
+		DisableCSSInlining *bool `json:"disable_css_inlining"`
 	}
 
 	err := json.NewDecoder(req.Body).Decode(&updateRequest)
@@ -80,6 +87,22 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		template.Metadata = string(*updateRequest.Metadata)
 	}
 
+	if updateRequest.Localizations != nil {
+		template.Localizations = string(*updateRequest.Localizations)
+	}
+
+	if updateRequest.LayoutID != nil {
+		template.LayoutID = *updateRequest.LayoutID
+	}
+
+	if updateRequest.DisableAutoText != nil {
+		template.DisableAutoText = *updateRequest.DisableAutoText
+	}
+
+	if updateRequest.DisableCSSInlining != nil {
+		template.DisableCSSInlining = *updateRequest.DisableCSSInlining
+	}
+
 	if template.Name == "" {
 		w.WriteHeader(422)
 		w.Write([]byte(`{ "errors": [ "Template \"name\" field cannot be empty" ] }`))
@@ -96,11 +119,47 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
-	template, err = h.templates.Set(database.Connection(), template)
+	localizations, err := decodeLocalizations(template.Localizations)
+	if err != nil {
+		w.WriteHeader(422)
+		w.Write([]byte(`{ "errors": [ "localizations must map each locale to a subject, text and html" ] }`))
+		return
+	}
+
+	critical, err := h.templates.UsedOnlyByCritical(database.Connection(), templateID, clientID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	warnings, err := common.ValidateLocalizedTemplates(common.Templates{
+		Subject: template.Subject,
+		Text:    template.Text,
+		HTML:    template.HTML,
+	}, localizations, critical)
+	if err != nil {
+		w.WriteHeader(422)
+		json.NewEncoder(w).Encode(map[string][]string{
+			"errors": err.(common.TemplateValidationError).Messages(),
+		})
+		return
+	}
+
+	// The template is locked, updated and versioned in one transaction, so
+	// that concurrent updates cannot record the same version.
+	transaction := database.Connection().Transaction()
+	transaction.Begin()
+
+	template, err = h.templates.Set(transaction, template)
 	if err != nil {
+		transaction.Rollback()
+
 		switch err.(type) {
 		case collections.NotFoundError:
 			w.WriteHeader(http.StatusNotFound)
+		case collections.ValidationError:
+			w.WriteHeader(422)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
@@ -108,5 +167,15 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
-	json.NewEncoder(w).Encode(NewTemplateResponse(template))
+	err = transaction.Commit()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(fmt.Sprintf(`{ "errors": [ %q ]}`, err)))
+		return
+	}
+
+	response := NewTemplateResponse(template)
+	response.Warnings = warnings
+
+	json.NewEncoder(w).Encode(response)
 }
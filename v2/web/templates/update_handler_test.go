@@ -24,6 +24,7 @@ var _ = Describe("UpdateHandler", func() {
 		request             *http.Request
 		templatesCollection *mocks.TemplatesCollection
 		conn                *mocks.Connection
+		transaction         *mocks.Transaction
 	)
 
 	BeforeEach(func() {
@@ -31,6 +32,9 @@ var _ = Describe("UpdateHandler", func() {
 		database := mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
+
 		context = stack.NewContext()
 		context.Set("database", database)
 		context.Set("client_id", "some-client-id")
@@ -83,6 +87,7 @@ var _ = Describe("UpdateHandler", func() {
 			"metadata": {
 				"template": "metadata"
 			},
+			"warnings": [ "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe" ],
 			"_links": {
 				"self": {
 					"href": "/templates/some-template-id"
@@ -90,7 +95,7 @@ var _ = Describe("UpdateHandler", func() {
 			}
 		}`))
 
-		Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(conn))
+		Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(transaction))
 		Expect(templatesCollection.SetCall.Receives.Template).To(Equal(collections.Template{
 			ID:       "some-template-id",
 			Name:     "an interesting template",
@@ -100,6 +105,25 @@ var _ = Describe("UpdateHandler", func() {
 			Metadata: `{"template":"metadata"}`,
 			ClientID: "some-client-id",
 		}))
+
+		Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+		Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+	})
+
+	Context("when only critical campaign types use the template", func() {
+		It("does not warn about the missing unsubscribe link", func() {
+			templatesCollection.UsedOnlyByCriticalCall.Returns.Critical = true
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.String()).NotTo(ContainSubstring("warnings"))
+
+			Expect(templatesCollection.UsedOnlyByCriticalCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesCollection.UsedOnlyByCriticalCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(templatesCollection.UsedOnlyByCriticalCall.Receives.ClientID).To(Equal("some-client-id"))
+		})
 	})
 
 	Context("when omitting fields", func() {
@@ -133,6 +157,7 @@ var _ = Describe("UpdateHandler", func() {
 				"metadata": {
 					"template": "metadata"
 				},
+				"warnings": [ "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe" ],
 				"_links": {
 					"self": {
 						"href": "/templates/some-template-id"
@@ -140,7 +165,7 @@ var _ = Describe("UpdateHandler", func() {
 				}
 			}`))
 
-			Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesCollection.SetCall.Receives.Template).To(Equal(collections.Template{
 				ID:       "some-template-id",
 				Name:     "an interesting template",
@@ -190,6 +215,7 @@ var _ = Describe("UpdateHandler", func() {
 				"metadata": {
 					"template": "metadata"
 				},
+				"warnings": [ "template does not include an unsubscribe link ({{.UnsubscribeID}} or {{.ListUnsubscribeURL}}), recipients of non-critical notifications will not be able to unsubscribe" ],
 				"_links": {
 					"self": {
 						"href": "/templates/some-template-id"
@@ -197,7 +223,7 @@ var _ = Describe("UpdateHandler", func() {
 				}
 			}`))
 
-			Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesCollection.SetCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesCollection.SetCall.Receives.Template).To(Equal(collections.Template{
 				ID:       "some-template-id",
 				Name:     "an interesting template",
@@ -210,6 +236,71 @@ var _ = Describe("UpdateHandler", func() {
 		})
 	})
 
+	Context("when changing the layout", func() {
+		It("sets the layout on the template", func() {
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"layout_id": "some-layout-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = http.NewRequest("PUT", "/templates/some-template-id", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(templatesCollection.SetCall.Receives.Template.LayoutID).To(Equal("some-layout-id"))
+		})
+
+		It("removes the layout when the layout id is empty", func() {
+			templatesCollection.GetCall.Returns.Template.LayoutID = "some-layout-id"
+
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"layout_id": "",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = http.NewRequest("PUT", "/templates/some-template-id", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(templatesCollection.SetCall.Receives.Template.LayoutID).To(BeEmpty())
+		})
+
+		It("returns a 422 when the layout cannot be used", func() {
+			templatesCollection.SetCall.Returns.Error = collections.ValidationError{Err: errors.New(`Layout "some-layout-id" creates a circular reference`)}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["Layout \"some-layout-id\" creates a circular reference"]
+			}`))
+		})
+	})
+
+	Context("when changing the html processing settings", func() {
+		It("only changes the settings that are given", func() {
+			templatesCollection.GetCall.Returns.Template.DisableCSSInlining = true
+
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"disable_auto_text": true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = http.NewRequest("PUT", "/templates/some-template-id", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(templatesCollection.SetCall.Receives.Template.DisableAutoText).To(BeTrue())
+			Expect(templatesCollection.SetCall.Receives.Template.DisableCSSInlining).To(BeTrue())
+		})
+	})
+
 	Context("when the template does not exist", func() {
 		It("returns a 404 and and error message", func() {
 			templatesCollection.GetCall.Returns.Error = collections.NotFoundError{errors.New("not found")}
@@ -246,6 +337,24 @@ var _ = Describe("UpdateHandler", func() {
 		})
 	})
 
+	Context("when the updated template does not compile", func() {
+		It("returns a 422 with an error message", func() {
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"html": "<p>{{.HMTL}}</p>",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request, err = http.NewRequest("PUT", "/templates/some-template-id", bytes.NewBuffer(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(ContainSubstring(`template: html:1:`))
+			Expect(writer.Body.String()).To(ContainSubstring(`can't evaluate field HMTL`))
+		})
+	})
+
 	Context("when the html and text field would be empty", func() {
 		It("returns a 422 with an error message", func() {
 			templatesCollection.GetCall.Returns.Template = collections.Template{
@@ -299,6 +408,18 @@ var _ = Describe("UpdateHandler", func() {
 			}`))
 		})
 
+		It("returns a 500 with an error message if the template usage cannot be checked", func() {
+			templatesCollection.UsedOnlyByCriticalCall.Returns.Error = errors.New("failed to list campaign types")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["failed to list campaign types"]
+			}`))
+			Expect(templatesCollection.SetCall.Receives.Template).To(Equal(collections.Template{}))
+		})
+
 		It("returns a 500 with an error message if setting the template fails", func() {
 			templatesCollection.SetCall.Returns.Error = errors.New("failed to talk to the db")
 
@@ -308,6 +429,19 @@ var _ = Describe("UpdateHandler", func() {
 			Expect(writer.Body.String()).To(MatchJSON(`{
 				"errors": ["failed to talk to the db"]
 			}`))
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("returns a 500 with an error message if the transaction cannot be committed", func() {
+			transaction.CommitCall.Returns.Error = errors.New("commit failed")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": ["commit failed"]
+			}`))
 		})
 	})
 })
@@ -0,0 +1,79 @@
+package transports_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/transports"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteHandler", func() {
+	var (
+		handler              transports.DeleteHandler
+		transportsCollection *mocks.TransportsCollection
+		context              stack.Context
+		writer               *httptest.ResponseRecorder
+		request              *http.Request
+		conn                 *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		transportsCollection = mocks.NewTransportsCollection()
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/transports/corporate-relay", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = transports.NewDeleteHandler(transportsCollection)
+	})
+
+	It("deletes the transport", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(transportsCollection.DeleteCall.Receives.Connection).To(Equal(conn))
+		Expect(transportsCollection.DeleteCall.Receives.Name).To(Equal("corporate-relay"))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 404 when the transport does not exist", func() {
+			transportsCollection.DeleteCall.Returns.Error = collections.NotFoundError{Err: errors.New("Transport \"corporate-relay\" could not be found")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Transport \"corporate-relay\" could not be found" ] }`))
+		})
+
+		It("returns a 409 when senders or clients are bound to the transport", func() {
+			transportsCollection.DeleteCall.Returns.Error = collections.InUseError{Err: errors.New("Transport \"corporate-relay\" is bound to senders or clients and cannot be deleted")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusConflict))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Transport \"corporate-relay\" is bound to senders or clients and cannot be deleted" ] }`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			transportsCollection.DeleteCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "BOOM!" ] }`))
+		})
+	})
+})
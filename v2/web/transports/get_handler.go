@@ -0,0 +1,46 @@
+package transports
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionGetter interface {
+	Get(conn collections.ConnectionInterface, name string) (collections.Transport, error)
+}
+
+type GetHandler struct {
+	transports collectionGetter
+}
+
+func NewGetHandler(transports collectionGetter) GetHandler {
+	return GetHandler{
+		transports: transports,
+	}
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	name := splitURL[len(splitURL)-1]
+
+	database := context.Get("database").(DatabaseInterface)
+	transport, err := h.transports.Get(database.Connection(), name)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewTransportResponse(transport))
+}
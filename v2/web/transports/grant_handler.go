@@ -0,0 +1,46 @@
+package transports
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionGranter interface {
+	Grant(conn collections.ConnectionInterface, name, clientID string) error
+}
+
+type GrantHandler struct {
+	transports collectionGranter
+}
+
+func NewGrantHandler(transports collectionGranter) GrantHandler {
+	return GrantHandler{
+		transports: transports,
+	}
+}
+
+func (h GrantHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	name := splitURL[len(splitURL)-3]
+	clientID := splitURL[len(splitURL)-1]
+
+	database := context.Get("database").(DatabaseInterface)
+	err := h.transports.Grant(database.Connection(), name, clientID)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
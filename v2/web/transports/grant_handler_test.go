@@ -0,0 +1,72 @@
+package transports_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/transports"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GrantHandler", func() {
+	var (
+		handler              transports.GrantHandler
+		transportsCollection *mocks.TransportsCollection
+		context              stack.Context
+		writer               *httptest.ResponseRecorder
+		request              *http.Request
+		conn                 *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		transportsCollection = mocks.NewTransportsCollection()
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("PUT", "/transports/corporate-relay/clients/some-client-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = transports.NewGrantHandler(transportsCollection)
+	})
+
+	It("grants the transport to the client", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(transportsCollection.GrantCall.Receives.Connection).To(Equal(conn))
+		Expect(transportsCollection.GrantCall.Receives.Name).To(Equal("corporate-relay"))
+		Expect(transportsCollection.GrantCall.Receives.ClientID).To(Equal("some-client-id"))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 404 when the transport does not exist", func() {
+			transportsCollection.GrantCall.Returns.Error = collections.NotFoundError{Err: errors.New("Transport \"corporate-relay\" could not be found")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Transport \"corporate-relay\" could not be found" ] }`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			transportsCollection.GrantCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "BOOM!" ] }`))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package transports_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestWebV2TransportsSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "v2/web/transports")
+}
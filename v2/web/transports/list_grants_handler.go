@@ -0,0 +1,46 @@
+package transports
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionGrantsLister interface {
+	ListGrants(conn collections.ConnectionInterface, name string) ([]string, error)
+}
+
+type ListGrantsHandler struct {
+	transports collectionGrantsLister
+}
+
+func NewListGrantsHandler(transports collectionGrantsLister) ListGrantsHandler {
+	return ListGrantsHandler{
+		transports: transports,
+	}
+}
+
+func (h ListGrantsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	name := splitURL[len(splitURL)-2]
+
+	database := context.Get("database").(DatabaseInterface)
+	clientIDs, err := h.transports.ListGrants(database.Connection(), name)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewTransportGrantsResponse(name, clientIDs))
+}
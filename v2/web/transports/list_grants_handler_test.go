@@ -0,0 +1,84 @@
+package transports_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/transports"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListGrantsHandler", func() {
+	var (
+		handler              transports.ListGrantsHandler
+		transportsCollection *mocks.TransportsCollection
+		context              stack.Context
+		writer               *httptest.ResponseRecorder
+		request              *http.Request
+		conn                 *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		transportsCollection = mocks.NewTransportsCollection()
+		transportsCollection.ListGrantsCall.Returns.ClientIDs = []string{"client-a", "client-b"}
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/transports/corporate-relay/clients", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = transports.NewListGrantsHandler(transportsCollection)
+	})
+
+	It("returns the clients granted the transport", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(transportsCollection.ListGrantsCall.Receives.Connection).To(Equal(conn))
+		Expect(transportsCollection.ListGrantsCall.Receives.Name).To(Equal("corporate-relay"))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"client_ids": ["client-a", "client-b"],
+			"_links": {
+				"self": {
+					"href": "/transports/corporate-relay/clients"
+				},
+				"transport": {
+					"href": "/transports/corporate-relay"
+				}
+			}
+		}`))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 404 when the transport does not exist", func() {
+			transportsCollection.ListGrantsCall.Returns.Error = collections.NotFoundError{Err: errors.New("Transport \"corporate-relay\" could not be found")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Transport \"corporate-relay\" could not be found" ] }`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			transportsCollection.ListGrantsCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "BOOM!" ] }`))
+		})
+	})
+})
@@ -0,0 +1,37 @@
+package transports
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionLister interface {
+	List(conn collections.ConnectionInterface) ([]collections.Transport, error)
+}
+
+type ListHandler struct {
+	transports collectionLister
+}
+
+func NewListHandler(transports collectionLister) ListHandler {
+	return ListHandler{
+		transports: transports,
+	}
+}
+
+func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	database := context.Get("database").(DatabaseInterface)
+
+	transportList, err := h.transports.List(database.Connection())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewTransportsListResponse(transportList))
+}
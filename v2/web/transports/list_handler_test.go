@@ -0,0 +1,102 @@
+package transports_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/transports"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler              transports.ListHandler
+		transportsCollection *mocks.TransportsCollection
+		context              stack.Context
+		writer               *httptest.ResponseRecorder
+		request              *http.Request
+	)
+
+	BeforeEach(func() {
+		database := mocks.NewDatabase()
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		transportsCollection = mocks.NewTransportsCollection()
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/transports", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = transports.NewListHandler(transportsCollection)
+	})
+
+	It("returns the list of transports", func() {
+		transportsCollection.ListCall.Returns.TransportList = []collections.Transport{
+			{
+				Name:          "corporate-relay",
+				Host:          "smtp.example.com",
+				Port:          "587",
+				AuthMechanism: "none",
+				TLSMode:       "starttls",
+			},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"transports": [
+				{
+					"name": "corporate-relay",
+					"host": "smtp.example.com",
+					"port": "587",
+					"user": "",
+					"auth_mechanism": "none",
+					"tls_mode": "starttls",
+					"_links": {
+						"self": {
+							"href": "/transports/corporate-relay"
+						}
+					}
+				}
+			],
+			"_links": {
+				"self": {
+					"href": "/transports"
+				}
+			}
+		}`))
+	})
+
+	It("returns an empty list when there are no transports", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"transports": [],
+			"_links": {
+				"self": {
+					"href": "/transports"
+				}
+			}
+		}`))
+	})
+
+	It("returns a 500 when the collection errors", func() {
+		transportsCollection.ListCall.Returns.Error = errors.New("BOOM!")
+
+		handler.ServeHTTP(writer, request, context)
+		Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+		Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "BOOM!" ] }`))
+	})
+})
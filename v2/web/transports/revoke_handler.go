@@ -0,0 +1,48 @@
+package transports
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type collectionRevoker interface {
+	Revoke(conn collections.ConnectionInterface, name, clientID string) error
+}
+
+type RevokeHandler struct {
+	transports collectionRevoker
+}
+
+func NewRevokeHandler(transports collectionRevoker) RevokeHandler {
+	return RevokeHandler{
+		transports: transports,
+	}
+}
+
+func (h RevokeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	name := splitURL[len(splitURL)-3]
+	clientID := splitURL[len(splitURL)-1]
+
+	database := context.Get("database").(DatabaseInterface)
+	err := h.transports.Revoke(database.Connection(), name, clientID)
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		case collections.InUseError:
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
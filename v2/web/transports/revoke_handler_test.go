@@ -0,0 +1,80 @@
+package transports_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/transports"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RevokeHandler", func() {
+	var (
+		handler              transports.RevokeHandler
+		transportsCollection *mocks.TransportsCollection
+		context              stack.Context
+		writer               *httptest.ResponseRecorder
+		request              *http.Request
+		conn                 *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		transportsCollection = mocks.NewTransportsCollection()
+
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/transports/corporate-relay/clients/some-client-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = transports.NewRevokeHandler(transportsCollection)
+	})
+
+	It("revokes the grant", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(transportsCollection.RevokeCall.Receives.Connection).To(Equal(conn))
+		Expect(transportsCollection.RevokeCall.Receives.Name).To(Equal("corporate-relay"))
+		Expect(transportsCollection.RevokeCall.Receives.ClientID).To(Equal("some-client-id"))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 404 when the grant does not exist", func() {
+			transportsCollection.RevokeCall.Returns.Error = collections.NotFoundError{Err: errors.New("Client \"some-client-id\" has not been granted transport \"corporate-relay\"")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Client \"some-client-id\" has not been granted transport \"corporate-relay\"" ] }`))
+		})
+
+		It("returns a 409 when senders of the client are bound to the transport", func() {
+			transportsCollection.RevokeCall.Returns.Error = collections.InUseError{Err: errors.New("Transport \"corporate-relay\" is bound to senders of client \"some-client-id\" and cannot be revoked")}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusConflict))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "Transport \"corporate-relay\" is bound to senders of client \"some-client-id\" and cannot be revoked" ] }`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			transportsCollection.RevokeCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "BOOM!" ] }`))
+		})
+	})
+})
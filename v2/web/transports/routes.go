@@ -0,0 +1,27 @@
+package transports
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type Routes struct {
+	RequestLogging       stack.Middleware
+	Authenticator        stack.Middleware
+	DatabaseAllocator    stack.Middleware
+	TransportsCollection collections.TransportsCollection
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("PUT", "/transports/{name}", NewSetHandler(r.TransportsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/transports", NewListHandler(r.TransportsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/transports/{name}", NewGetHandler(r.TransportsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/transports/{name}", NewDeleteHandler(r.TransportsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/transports/{name}/clients", NewListGrantsHandler(r.TransportsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/transports/{name}/clients/{client_id}", NewGrantHandler(r.TransportsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/transports/{name}/clients/{client_id}", NewRevokeHandler(r.TransportsCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+}
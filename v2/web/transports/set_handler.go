@@ -0,0 +1,114 @@
+package transports
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/ryanmoran/stack"
+)
+
+var (
+	authMechanisms = []string{"none", "plain", "cram-md5", "login", "xoauth2"}
+	tlsModes       = []string{"none", "starttls", "implicit"}
+)
+
+type collectionSetter interface {
+	Set(conn collections.ConnectionInterface, transport collections.Transport) (collections.Transport, error)
+}
+
+type SetHandler struct {
+	transports collectionSetter
+}
+
+func NewSetHandler(transports collectionSetter) SetHandler {
+	return SetHandler{
+		transports: transports,
+	}
+}
+
+func (h SetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	name := splitURL[len(splitURL)-1]
+
+	var setRequest struct {
+		Host          string `json:"host"`
+		Port          string `json:"port"`
+		User          string `json:"user"`
+		Pass          string `json:"pass"`
+		CRAMMD5Secret string `json:"crammd5_secret"`
+		XOAUTH2Token  string `json:"xoauth2_token"`
+		AuthMechanism string `json:"auth_mechanism"`
+		TLSMode       string `json:"tls_mode"`
+	}
+
+	err := json.NewDecoder(req.Body).Decode(&setRequest)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{ "errors": [ "invalid json body" ] }`))
+		return
+	}
+
+	if setRequest.AuthMechanism == "" {
+		setRequest.AuthMechanism = "none"
+	}
+
+	if setRequest.TLSMode == "" {
+		setRequest.TLSMode = "starttls"
+	}
+
+	var validationErrors []string
+	if setRequest.Host == "" {
+		validationErrors = append(validationErrors, "missing transport host")
+	}
+
+	if setRequest.Port == "" {
+		validationErrors = append(validationErrors, "missing transport port")
+	}
+
+	if !contains(authMechanisms, setRequest.AuthMechanism) {
+		validationErrors = append(validationErrors, fmt.Sprintf("auth_mechanism must be one of %s", strings.Join(authMechanisms, ", ")))
+	}
+
+	if !contains(tlsModes, setRequest.TLSMode) {
+		validationErrors = append(validationErrors, fmt.Sprintf("tls_mode must be one of %s", strings.Join(tlsModes, ", ")))
+	}
+
+	if len(validationErrors) > 0 {
+		w.WriteHeader(422)
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, strings.Join(validationErrors, ", "))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	transport, err := h.transports.Set(database.Connection(), collections.Transport{
+		Name:          name,
+		Host:          setRequest.Host,
+		Port:          setRequest.Port,
+		User:          setRequest.User,
+		Pass:          setRequest.Pass,
+		CRAMMD5Secret: setRequest.CRAMMD5Secret,
+		XOAUTH2Token:  setRequest.XOAUTH2Token,
+		AuthMechanism: setRequest.AuthMechanism,
+		TLSMode:       setRequest.TLSMode,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{ "errors": [ %q ] }`, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(NewTransportResponse(transport))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
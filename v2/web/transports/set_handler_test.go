@@ -0,0 +1,129 @@
+package transports_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/transports"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SetHandler", func() {
+	var (
+		handler              transports.SetHandler
+		transportsCollection *mocks.TransportsCollection
+		context              stack.Context
+		writer               *httptest.ResponseRecorder
+		request              *http.Request
+		conn                 *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		transportsCollection = mocks.NewTransportsCollection()
+		transportsCollection.SetCall.Returns.Transport = collections.Transport{
+			Name:          "corporate-relay",
+			Host:          "smtp.example.com",
+			Port:          "587",
+			User:          "some-user",
+			Pass:          "some-pass",
+			AuthMechanism: "plain",
+			TLSMode:       "starttls",
+		}
+
+		writer = httptest.NewRecorder()
+
+		requestBody, err := json.Marshal(map[string]string{
+			"host":           "smtp.example.com",
+			"port":           "587",
+			"user":           "some-user",
+			"pass":           "some-pass",
+			"auth_mechanism": "plain",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err = http.NewRequest("PUT", "/transports/corporate-relay", bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = transports.NewSetHandler(transportsCollection)
+	})
+
+	It("sets the transport without returning its credentials", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(transportsCollection.SetCall.Receives.Connection).To(Equal(conn))
+		Expect(transportsCollection.SetCall.Receives.Transport).To(Equal(collections.Transport{
+			Name:          "corporate-relay",
+			Host:          "smtp.example.com",
+			Port:          "587",
+			User:          "some-user",
+			Pass:          "some-pass",
+			AuthMechanism: "plain",
+			TLSMode:       "starttls",
+		}))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"name": "corporate-relay",
+			"host": "smtp.example.com",
+			"port": "587",
+			"user": "some-user",
+			"auth_mechanism": "plain",
+			"tls_mode": "starttls",
+			"_links": {
+				"self": {
+					"href": "/transports/corporate-relay"
+				}
+			}
+		}`))
+	})
+
+	Context("failure cases", func() {
+		It("returns a 400 when the JSON cannot be unmarshalled", func() {
+			var err error
+			request, err = http.NewRequest("PUT", "/transports/corporate-relay", strings.NewReader("%%%"))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusBadRequest))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "invalid json body" ] }`))
+		})
+
+		It("returns a 422 when the request is invalid", func() {
+			var err error
+			request, err = http.NewRequest("PUT", "/transports/corporate-relay", strings.NewReader(`{"tls_mode": "sometimes"}`))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(422))
+			Expect(writer.Body.String()).To(MatchJSON(`{
+				"errors": [
+					"missing transport host, missing transport port, tls_mode must be one of none, starttls, implicit"
+				]
+			}`))
+		})
+
+		It("returns a 500 when the collection errors", func() {
+			transportsCollection.SetCall.Returns.Error = errors.New("BOOM!")
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{ "errors": [ "BOOM!" ] }`))
+		})
+	})
+})
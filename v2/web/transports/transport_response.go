@@ -0,0 +1,84 @@
+package transports
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+)
+
+type Link struct {
+	Href string `json:"href"`
+}
+
+type TransportResponseLinks struct {
+	Self Link `json:"self"`
+}
+
+// TransportResponse never includes the transport credentials.
+type TransportResponse struct {
+	Name          string                 `json:"name"`
+	Host          string                 `json:"host"`
+	Port          string                 `json:"port"`
+	User          string                 `json:"user"`
+	AuthMechanism string                 `json:"auth_mechanism"`
+	TLSMode       string                 `json:"tls_mode"`
+	Links         TransportResponseLinks `json:"_links"`
+}
+
+func NewTransportResponse(transport collections.Transport) TransportResponse {
+	return TransportResponse{
+		Name:          transport.Name,
+		Host:          transport.Host,
+		Port:          transport.Port,
+		User:          transport.User,
+		AuthMechanism: transport.AuthMechanism,
+		TLSMode:       transport.TLSMode,
+		Links: TransportResponseLinks{
+			Self: Link{fmt.Sprintf("/transports/%s", transport.Name)},
+		},
+	}
+}
+
+type TransportsListResponse struct {
+	Transports []TransportResponse         `json:"transports"`
+	Links      TransportsListResponseLinks `json:"_links"`
+}
+
+type TransportsListResponseLinks struct {
+	Self Link `json:"self"`
+}
+
+func NewTransportsListResponse(transportList []collections.Transport) TransportsListResponse {
+	transportResponseList := []TransportResponse{}
+
+	for _, transport := range transportList {
+		transportResponseList = append(transportResponseList, NewTransportResponse(transport))
+	}
+
+	return TransportsListResponse{
+		Transports: transportResponseList,
+		Links: TransportsListResponseLinks{
+			Self: Link{"/transports"},
+		},
+	}
+}
+
+type TransportGrantsResponse struct {
+	ClientIDs []string                     `json:"client_ids"`
+	Links     TransportGrantsResponseLinks `json:"_links"`
+}
+
+type TransportGrantsResponseLinks struct {
+	Self      Link `json:"self"`
+	Transport Link `json:"transport"`
+}
+
+func NewTransportGrantsResponse(name string, clientIDs []string) TransportGrantsResponse {
+	return TransportGrantsResponse{
+		ClientIDs: clientIDs,
+		Links: TransportGrantsResponseLinks{
+			Self:      Link{fmt.Sprintf("/transports/%s/clients", name)},
+			Transport: Link{fmt.Sprintf("/transports/%s", name)},
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package unsubscribers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/pivotal-golang/conceal"
+	"github.com/ryanmoran/stack"
+)
+
+type ListUnsubscribeHandler struct {
+	collection unsubscribersGetSetter
+	cloak      conceal.CloakInterface
+}
+
+func NewListUnsubscribeHandler(collection unsubscribersGetSetter, cloak conceal.CloakInterface) ListUnsubscribeHandler {
+	return ListUnsubscribeHandler{
+		collection: collection,
+		cloak:      cloak,
+	}
+}
+
+func (h ListUnsubscribeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	splitURL := strings.Split(req.URL.Path, "/")
+	token := splitURL[len(splitURL)-1]
+
+	plainText, err := h.cloak.Unveil([]byte(token))
+	parts := strings.Split(string(plainText), "|")
+	if err != nil || len(parts) != 3 || parts[0] == "" {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors": ["The unsubscribe token is invalid"]}`))
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	_, err = h.collection.Set(database.Connection(), collections.Unsubscriber{
+		CampaignTypeID: parts[2],
+		UserGUID:       parts[0],
+	})
+	if err != nil {
+		switch err.(type) {
+		case collections.NotFoundError:
+			w.WriteHeader(http.StatusNotFound)
+		case collections.PermissionsError:
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		w.Write([]byte(fmt.Sprintf(`{"errors": [%q]}`, err)))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
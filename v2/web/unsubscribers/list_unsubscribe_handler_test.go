@@ -0,0 +1,109 @@
+package unsubscribers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/cloudfoundry-incubator/notifications/v2/web/unsubscribers"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListUnsubscribeHandler", func() {
+	var (
+		handler                 unsubscribers.ListUnsubscribeHandler
+		writer                  *httptest.ResponseRecorder
+		request                 *http.Request
+		context                 stack.Context
+		unsubscribersCollection *mocks.UnsubscribersCollection
+		cloak                   *mocks.Cloak
+		connection              *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		unsubscribersCollection = mocks.NewUnsubscribersCollection()
+		cloak = mocks.NewCloak()
+		cloak.UnveilCall.Returns.PlainText = []byte("some-user-guid|some-client-id|some-campaign-type-id")
+		handler = unsubscribers.NewListUnsubscribeHandler(unsubscribersCollection, cloak)
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("POST", "/list_unsubscribe/some-token=", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("unsubscribes the user from the campaign type encoded in the token", func() {
+		handler.ServeHTTP(writer, request, context)
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(writer.Body.String()).To(BeEmpty())
+
+		Expect(cloak.UnveilCall.Receives.CipherText).To(Equal([]byte("some-token=")))
+		Expect(unsubscribersCollection.SetCall.Receives.Connection).To(Equal(connection))
+		Expect(unsubscribersCollection.SetCall.Receives.Unsubscriber).To(Equal(collections.Unsubscriber{
+			CampaignTypeID: "some-campaign-type-id",
+			UserGUID:       "some-user-guid",
+		}))
+	})
+
+	Context("when an error occurs", func() {
+		It("returns a 404 when the token cannot be decrypted", func() {
+			cloak.UnveilCall.Returns.Error = errors.New("bad cipher")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["The unsubscribe token is invalid"]}`))
+			Expect(unsubscribersCollection.SetCall.Receives.Unsubscriber).To(Equal(collections.Unsubscriber{}))
+		})
+
+		It("returns a 404 when the token does not identify a user", func() {
+			cloak.UnveilCall.Returns.PlainText = []byte("|some-client-id|some-campaign-type-id")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["The unsubscribe token is invalid"]}`))
+		})
+
+		It("returns a 404 when the campaign type cannot be found", func() {
+			unsubscribersCollection.SetCall.Returns.Error = collections.NotFoundError{Err: errors.New("some-error")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusNotFound))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["some-error"]}`))
+		})
+
+		It("returns a 403 when the campaign type is critical", func() {
+			unsubscribersCollection.SetCall.Returns.Error = collections.PermissionsError{Err: errors.New("some-error")}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusForbidden))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["some-error"]}`))
+		})
+
+		It("returns a 500 when an unknown error occurs", func() {
+			unsubscribersCollection.SetCall.Returns.Error = errors.New("some-error")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusInternalServerError))
+			Expect(writer.Body.String()).To(MatchJSON(`{"errors": ["some-error"]}`))
+		})
+	})
+})
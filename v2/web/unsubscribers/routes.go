@@ -2,6 +2,7 @@ package unsubscribers
 
 import (
 	"github.com/cloudfoundry-incubator/notifications/v2/collections"
+	"github.com/pivotal-golang/conceal"
 	"github.com/ryanmoran/stack"
 )
 
@@ -14,9 +15,11 @@ type Routes struct {
 	Authenticator           stack.Middleware
 	DatabaseAllocator       stack.Middleware
 	UnsubscribersCollection collections.UnsubscribersCollection
+	Cloak                   conceal.CloakInterface
 }
 
 func (r Routes) Register(m muxer) {
 	m.Handle("PUT", "/campaign_types/{campaign_type_id}/unsubscribers/{user_guid}", NewUpdateHandler(r.UnsubscribersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
 	m.Handle("DELETE", "/campaign_types/{campaign_type_id}/unsubscribers/{user_guid}", NewDeleteHandler(r.UnsubscribersCollection), r.RequestLogging, r.Authenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/list_unsubscribe/{token}", NewListUnsubscribeHandler(r.UnsubscribersCollection, r.Cloak), r.RequestLogging, r.DatabaseAllocator)
 }
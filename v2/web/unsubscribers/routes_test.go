@@ -34,6 +34,7 @@ var _ = Describe("Routes", func() {
 			Authenticator:           auth,
 			DatabaseAllocator:       dbAllocator,
 			UnsubscribersCollection: collections.UnsubscribersCollection{},
+			Cloak:                   mocks.NewCloak(),
 		}.Register(muxer)
 	})
 
@@ -72,4 +73,19 @@ var _ = Describe("Routes", func() {
 		databaseAllocator := s.Middleware[2].(middleware.DatabaseAllocator)
 		Expect(databaseAllocator).To(Equal(dbAllocator))
 	})
+
+	It("routes POST /list_unsubscribe/{token}", func() {
+		request, err := http.NewRequest("POST", "/list_unsubscribe/some-token", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(unsubscribers.ListUnsubscribeHandler{}))
+		Expect(s.Middleware).To(HaveLen(2))
+
+		requestLogging := s.Middleware[0].(middleware.RequestLogging)
+		Expect(requestLogging).To(Equal(logging))
+
+		databaseAllocator := s.Middleware[1].(middleware.DatabaseAllocator)
+		Expect(databaseAllocator).To(Equal(dbAllocator))
+	})
 })
@@ -24,6 +24,12 @@ func NewRouter(mother MotherInterface, config Config) http.Handler {
 		CCHost:            config.CCHost,
 		CORSOrigin:        config.CORSOrigin,
 		SQLDB:             config.SQLDB,
+		EncryptionKey:     config.EncryptionKey,
+		Sender:            config.Sender,
+		Domain:            config.Domain,
+
+		HTMLPolicy:         config.HTMLPolicy,
+		HTMLClientPolicies: config.HTMLClientPolicies,
 	})
 
 	v2 := v2web.NewRouter(NewMuxer(), v2web.Config{
@@ -37,6 +43,13 @@ func NewRouter(mother MotherInterface, config Config) http.Handler {
 		UAAClientID:       config.UAAClientID,
 		UAAClientSecret:   config.UAAClientSecret,
 		CCHost:            config.CCHost,
+		EncryptionKey:     config.EncryptionKey,
+		SenderDomains:     config.SenderDomains,
+		Sender:            config.Sender,
+		Domain:            config.Domain,
+
+		HTMLPolicy:         config.HTMLPolicy,
+		HTMLClientPolicies: config.HTMLClientPolicies,
 	})
 
 	return VersionRouter{
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/cloudfoundry-incubator/notifications/sanitize"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/pivotal-golang/lager"
 )
@@ -24,6 +25,13 @@ type Config struct {
 	UAAClientSecret   string
 	DefaultUAAScopes  []string
 	CCHost            string
+	EncryptionKey     []byte
+	SenderDomains     []string
+	Sender            string
+	Domain            string
+
+	HTMLPolicy         sanitize.Policy
+	HTMLClientPolicies map[string]sanitize.Policy
 }
 
 type Server struct{}
@@ -9,6 +9,12 @@ type VersionRouter map[int]http.Handler
 
 func (vr VersionRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	versionHeader := req.Header.Get("X-NOTIFICATIONS-VERSION")
+	if versionHeader == "" {
+		// Links followed from emails, such as one-click unsubscribes, cannot
+		// set headers, so they carry the version in the query string instead.
+		versionHeader = req.URL.Query().Get("version")
+	}
+
 	if versionHeader == "" {
 		versionHeader = "1"
 	}
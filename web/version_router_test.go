@@ -52,6 +52,16 @@ var _ = Describe("VersionRouter", func() {
 		Expect(v3Called).To(BeFalse())
 	})
 
+	It("falls back to the version query parameter when the header is absent", func() {
+		request, err := http.NewRequest("POST", "/list_unsubscribe/some-token?version=3", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		router.ServeHTTP(writer, request)
+
+		Expect(v3Called).To(BeTrue())
+		Expect(v1Called).To(BeFalse())
+	})
+
 	It("returns a 404 if the version number is not an integer", func() {
 		request.Header.Set("X-NOTIFICATIONS-VERSION", "banana")
 		router.ServeHTTP(writer, request)